@@ -30,7 +30,9 @@ type API struct {
 	Wrapper  service.WrapperService
 	Facade   facade.Facade
 	*service.AppCombinedService
-	log *log.Logger
+	// conf names the generated function configs in the same way as the facade recognizes them
+	conf config.Facade
+	log  *log.Logger
 }
 
 // NewAPI NewAPI
@@ -136,6 +138,7 @@ func NewAPI(config *config.CloudConfig) (*API, error) {
 		Wrapper:            wrapper,
 		AppCombinedService: acs,
 		Facade:             appFacade,
+		conf:               config.Facade,
 		log:                log.L().With(log.Any("api", "admin")),
 	}, nil
 }
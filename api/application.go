@@ -14,7 +14,9 @@ import (
 	"github.com/jinzhu/copier"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 const (
@@ -30,7 +32,7 @@ const (
 // GetApplication get a application
 func (api *API) GetApplication(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
-	app, err := api.Facade.GetApp(ns, n, "")
+	app, err := api.Facade.GetApp(c.RequestContext(), ns, n, "")
 	if err != nil {
 		return nil, err
 	}
@@ -96,12 +98,12 @@ func (api *API) CreateApplication(c *common.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	app, err = api.Facade.CreateApp(ns, baseApp, app, configs)
+	res, err := api.Facade.CreateApp(c.RequestContext(), ns, baseApp, app, configs)
 	if err != nil {
 		return nil, err
 	}
 
-	return api.ToApplicationView(app)
+	return api.ToApplicationView(res.App)
 }
 
 // UpdateApplication update the application
@@ -149,9 +151,16 @@ func (api *API) UpdateApplication(c *common.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	app, err = api.Facade.UpdateApp(ns, oldApp, app, configs)
-
-	return api.ToApplicationView(app)
+	ctx := c.RequestContext()
+	// the update removing the app from too many nodes is confirmed by force
+	if c.Query("force") == "true" {
+		ctx = facade.WithForce(ctx)
+	}
+	res, err := api.Facade.UpdateApp(ctx, ns, oldApp, app, configs)
+	if err != nil {
+		return nil, err
+	}
+	return api.ToApplicationView(res.App)
 }
 
 // DeleteApplication delete the application
@@ -171,7 +180,7 @@ func (api *API) DeleteApplication(c *common.Context) (interface{}, error) {
 		return nil, common.Error(common.ErrAppReferencedByNode, common.Field("name", name))
 	}
 
-	err = api.Facade.DeleteApp(ns, name, app)
+	err = api.Facade.DeleteApp(c.RequestContext(), ns, name, app)
 	return nil, err
 }
 
@@ -317,7 +326,11 @@ func (api *API) UpdateNodeAndAppIndex(namespace string, app *specV1.Application)
 	if err != nil {
 		return err
 	}
-	return api.Index.RefreshNodesIndexByApp(nil, namespace, app.Name, nodes)
+	if err = api.Index.RefreshNodesIndexByApp(nil, namespace, app.Name, nodes); err != nil {
+		return err
+	}
+	service.RecordNodesIndexRefresh(namespace, app.Name, len(nodes))
+	return nil
 }
 
 func (api *API) ToApplicationView(app *specV1.Application) (*models.ApplicationView, error) {
@@ -342,12 +355,12 @@ func (api *API) ToApplicationView(app *specV1.Application) (*models.ApplicationV
 	}
 	for index := range appView.Services {
 		service := &appView.Services[index]
-		generatedConfigName, err := getGenConfigNameOfFunctionService(app, service.Name)
+		generatedConfigName, err := getGenConfigNameOfFunctionService(api.functionConfigPrefix(), app, service.Name)
 		if err != nil {
 			return nil, err
 		}
 
-		generatedProgramConfigName, err := getGenProgramNameOfFunctionService(app, service.Name)
+		generatedProgramConfigName, err := getGenProgramNameOfFunctionService(api.functionProgramConfigPrefix(), app, service.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -396,7 +409,7 @@ func (api *API) ToApplication(appView *models.ApplicationView, oldApp *specV1.Ap
 	var configs []specV1.Configuration
 	for index := range app.Services {
 		service := &app.Services[index]
-		config, err := generateConfigOfFunctionService(service, app)
+		config, err := generateConfigOfFunctionService(api.functionConfigPrefix(), service, app)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -654,7 +667,24 @@ func (api *API) isAppCanDelete(namesapce, name string) (bool, error) {
 	return true, nil
 }
 
-func getGenConfigNameOfFunctionService(app *specV1.Application, serviceName string) (string, error) {
+// functionConfigPrefix returns the configured prefix of the generated function configs, the default one if not configured
+func (api *API) functionConfigPrefix() string {
+	if api.conf.FunctionConfigPrefix == "" {
+		return FunctionConfigPrefix
+	}
+	return api.conf.FunctionConfigPrefix
+}
+
+// functionProgramConfigPrefix returns the configured prefix of the generated function program configs,
+// the default one if not configured
+func (api *API) functionProgramConfigPrefix() string {
+	if api.conf.FunctionProgramConfigPrefix == "" {
+		return FunctionProgramConfigPrefix
+	}
+	return api.conf.FunctionProgramConfigPrefix
+}
+
+func getGenConfigNameOfFunctionService(prefix string, app *specV1.Application, serviceName string) (string, error) {
 	volumeMountName := getNameOfFunctionConfigVolumeMount(serviceName)
 	for _, v := range app.Volumes {
 		if v.Name == volumeMountName {
@@ -664,10 +694,10 @@ func getGenConfigNameOfFunctionService(app *specV1.Application, serviceName stri
 			return v.VolumeSource.Config.Name, nil
 		}
 	}
-	return strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", FunctionConfigPrefix, app.Name, serviceName, common.RandString(9))), nil
+	return strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", prefix, app.Name, serviceName, common.RandString(9))), nil
 }
 
-func getGenProgramNameOfFunctionService(app *specV1.Application, serviceName string) (string, error) {
+func getGenProgramNameOfFunctionService(prefix string, app *specV1.Application, serviceName string) (string, error) {
 	volumeMountName := getNameOfFunctionProgramVmMount(serviceName)
 	for _, v := range app.Volumes {
 		if v.Name != volumeMountName {
@@ -678,10 +708,10 @@ func getGenProgramNameOfFunctionService(app *specV1.Application, serviceName str
 		}
 		return v.VolumeSource.Config.Name, nil
 	}
-	return strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", FunctionProgramConfigPrefix, app.Name, serviceName, common.RandString(9))), nil
+	return strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", prefix, app.Name, serviceName, common.RandString(9))), nil
 }
 
-func generateConfigOfFunctionService(service *specV1.Service, app *specV1.Application) (*specV1.Configuration, error) {
+func generateConfigOfFunctionService(prefix string, service *specV1.Service, app *specV1.Application) (*specV1.Configuration, error) {
 	serviceFunctions := models.ServiceFunction{
 		Functions: service.Functions,
 	}
@@ -691,7 +721,7 @@ func generateConfigOfFunctionService(service *specV1.Service, app *specV1.Applic
 		return nil, err
 	}
 
-	generatedConfigName, err := getGenConfigNameOfFunctionService(app, service.Name)
+	generatedConfigName, err := getGenConfigNameOfFunctionService(prefix, app, service.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -710,7 +740,7 @@ func generateConfigOfFunctionService(service *specV1.Service, app *specV1.Applic
 }
 
 func generateProgramOfFunctionService(api *API, service *specV1.Service, app *specV1.Application) (*specV1.Configuration, error) {
-	generatedConfigName, err := getGenProgramNameOfFunctionService(app, service.Name)
+	generatedConfigName, err := getGenProgramNameOfFunctionService(api.functionProgramConfigPrefix(), app, service.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -17,6 +17,8 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
 	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
@@ -248,7 +250,7 @@ func TestGetInvisibleApplication(t *testing.T) {
 			},
 		},
 	}
-	fApp.EXPECT().GetApp(mApp.Namespace, "cba", "").Return(mApp, nil).Times(1)
+	fApp.EXPECT().GetApp(gomock.Any(), mApp.Namespace, "cba", "").Return(mApp, nil).Times(1)
 	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/cba", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -271,7 +273,7 @@ func TestGetContainerApplication(t *testing.T) {
 	api.Facade = fApp
 
 	mApp := getMockContainerApp()
-	fApp.EXPECT().GetApp(mApp.Namespace, "cba", "").Return(nil, errors.New("err")).Times(1)
+	fApp.EXPECT().GetApp(gomock.Any(), mApp.Namespace, "cba", "").Return(nil, errors.New("err")).Times(1)
 	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/cba", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -284,7 +286,7 @@ func TestGetContainerApplication(t *testing.T) {
 			specV1.SecretLabel: specV1.SecretRegistry,
 		},
 	}
-	fApp.EXPECT().GetApp(mApp.Namespace, mApp.Name, "").Return(mApp, nil).Times(1)
+	fApp.EXPECT().GetApp(gomock.Any(), mApp.Namespace, mApp.Name, "").Return(mApp, nil).Times(1)
 	sSecret.EXPECT().Get(mApp.Namespace, secret.Name, "").Return(secret, nil).Times(1)
 
 	// 200
@@ -315,7 +317,7 @@ func TestGetFunctionApplication(t *testing.T) {
 
 	mApp := getMockFunctionApp()
 
-	fApp.EXPECT().GetApp(mApp.Namespace, "cba", "").Return(nil, errors.New("err")).Times(1)
+	fApp.EXPECT().GetApp(gomock.Any(), mApp.Namespace, "cba", "").Return(nil, errors.New("err")).Times(1)
 	req, _ := http.NewRequest(http.MethodGet, "/v1/apps/cba", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -337,7 +339,7 @@ func TestGetFunctionApplication(t *testing.T) {
 			"service.yml": string(data),
 		},
 	}
-	fApp.EXPECT().GetApp(mApp.Namespace, mApp.Name, "").Return(mApp, nil).Times(1)
+	fApp.EXPECT().GetApp(gomock.Any(), mApp.Namespace, mApp.Name, "").Return(mApp, nil).Times(1)
 	sConfig.EXPECT().Get(mApp.Namespace, "baetyl-function-app-service-xxxxxxxxx", "").Return(config, nil).Times(1)
 	sConfig.EXPECT().Get(mApp.Namespace, "baetyl-function-program-config-x3-xs3-uwredcfxb", "").Return(config, nil).Times(1)
 
@@ -589,7 +591,7 @@ func TestCreateContainerApplication(t *testing.T) {
 	sSecret.EXPECT().Get(appView.Namespace, "registry01", "").Return(secret, nil).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "eden2", "").Return(eden2, nil).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error")).Times(1)
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView)
 	req, _ = http.NewRequest(http.MethodPost, "/v1/apps?base=eden2", bytes.NewReader(body))
@@ -602,7 +604,7 @@ func TestCreateContainerApplication(t *testing.T) {
 	sSecret.EXPECT().Get(appView.Namespace, "registry01", "").Return(secret, nil).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "eden2", "").Return(eden2, nil).Return(eden2, nil).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(mApp, nil).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: mApp}, nil).Times(1)
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView)
 	req, _ = http.NewRequest(http.MethodPost, "/v1/apps?base=eden2", bytes.NewReader(body))
@@ -798,7 +800,7 @@ func TestCreateApplicationHasCertificates(t *testing.T) {
 	sSecret.EXPECT().Get(appView.Namespace, "registry01", "").Return(secret, nil).Times(1)
 	sSecret.EXPECT().Get(appView.Namespace, "certificate01", "").Return(secret, nil).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, nil, app, gomock.Any()).Return(app1, nil)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, nil, app, gomock.Any()).Return(&facade.AppResult{App: app1}, nil)
 	sSecret.EXPECT().Get(appView.Namespace, "secret01", "").Return(secret, nil).Times(1)
 	sSecret.EXPECT().Get(appView.Namespace, "registry01", "").Return(secretRegistry, nil).Times(1)
 	sSecret.EXPECT().Get(appView.Namespace, "certificate01", "").Return(secretCertificate, nil).Times(1)
@@ -866,14 +868,14 @@ func TestUpdateContainerApplication(t *testing.T) {
 	mApp3.Services[0].Type = "deployment"
 
 	sApp.EXPECT().Get(gomock.Any(), "abc", gomock.Any()).Return(mApp, nil).AnyTimes()
-	fApp.EXPECT().UpdateApp(mApp.Namespace, gomock.Any(), mApp2, gomock.Any()).Return(mApp3, nil)
+	fApp.EXPECT().UpdateApp(gomock.Any(), mApp.Namespace, gomock.Any(), mApp2, gomock.Any()).Return(&facade.AppResult{App: mApp3}, nil)
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mApp2)
 	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc", bytes.NewReader(body))
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	fApp.EXPECT().UpdateApp(mApp.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+	fApp.EXPECT().UpdateApp(gomock.Any(), mApp.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mApp2)
 	req, _ = http.NewRequest(http.MethodPut, "/v1/apps/abc", bytes.NewReader(body))
@@ -1027,7 +1029,7 @@ func TestUpdateNativeNotFunctionApplication(t *testing.T) {
 	sConfig.EXPECT().Get(appView.Namespace, "test-program", "").Return(programConfig, nil).Times(2)
 	sConfig.EXPECT().Get(appView.Namespace, "agent-conf", "").Return(agentConfig, nil).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(app1, nil)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: app1}, nil)
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(appView)
 	req, _ := http.NewRequest(http.MethodPost, "/v1/apps", bytes.NewReader(body))
@@ -1169,7 +1171,7 @@ func TestUpdateNativeNotFunctionApplication(t *testing.T) {
 	sConfig.EXPECT().Get(appView.Namespace, "test-program2", "").Return(programConfig2, nil).Times(2)
 	sConfig.EXPECT().Get(appView.Namespace, "agent-conf", "").Return(agentConfig, nil).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(app12, nil)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: app12}, nil)
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView12)
 	req, _ = http.NewRequest(http.MethodPost, "/v1/apps", bytes.NewReader(body))
@@ -1290,7 +1292,7 @@ func TestUpdateNativeNotFunctionApplication(t *testing.T) {
 	sConfig.EXPECT().Get(appView.Namespace, "test-program2", "").Return(programConfig2, nil).Times(2)
 
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(app1, nil).Times(1)
-	fApp.EXPECT().UpdateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(app2, nil)
+	fApp.EXPECT().UpdateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: app2}, nil)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView2)
@@ -1624,7 +1626,7 @@ func TestCreateFunctionApplication(t *testing.T) {
 		"python36": "image",
 	}
 	sFunc.EXPECT().ListRuntimes().Return(funcs, nil).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err")).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err")).Times(1)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView)
@@ -1638,7 +1640,7 @@ func TestCreateFunctionApplication(t *testing.T) {
 	sTempalte.EXPECT().UnmarshalTemplate("baetyl-python36-program.yml", gomock.Any(), config2).Return(nil).Times(1)
 	sFunc.EXPECT().ListRuntimes().Return(funcs, nil).Times(1)
 	// one more for program config
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(eden2, nil).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: eden2}, nil).Times(1)
 	sConfig.EXPECT().Get(appView.Namespace, gomock.Any(), "").Return(config, nil).AnyTimes()
 
 	w = httptest.NewRecorder()
@@ -2088,7 +2090,7 @@ func TestUpdateFunctionApplication(t *testing.T) {
 		"python36": "image",
 	}
 	sFunc.EXPECT().ListRuntimes().Return(funcs, nil).Times(2)
-	fApp.EXPECT().UpdateApp(namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(newApp, nil)
+	fApp.EXPECT().UpdateApp(gomock.Any(), namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: newApp}, nil)
 	sConfig.EXPECT().Get(namespace, "baetyl-function-config-app-service-2", "").Return(config2, nil).Times(1)
 	sConfig.EXPECT().Get(namespace, "baetyl-function-config-app-service-3", "").Return(config2, nil).Times(1)
 	sConfig.EXPECT().Get(namespace, "baetyl-function-program-config-app-service-bbbb", "").Return(config2, nil).Times(1)
@@ -2128,7 +2130,7 @@ func TestDeleteApplication(t *testing.T) {
 
 	// 500
 	sApp.EXPECT().Get(gomock.Any(), "abc", gomock.Any()).Return(app, nil).Times(1)
-	fApp.EXPECT().DeleteApp(app.Namespace, app.Name, gomock.Any()).Return(fmt.Errorf("error")).Times(1)
+	fApp.EXPECT().DeleteApp(gomock.Any(), app.Namespace, app.Name, gomock.Any()).Return(fmt.Errorf("error")).Times(1)
 	req, _ := http.NewRequest(http.MethodDelete, "/v1/apps/abc", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -2136,7 +2138,7 @@ func TestDeleteApplication(t *testing.T) {
 
 	// 200
 	sApp.EXPECT().Get(gomock.Any(), "abc", gomock.Any()).Return(app, nil).Times(1)
-	fApp.EXPECT().DeleteApp(app.Namespace, app.Name, gomock.Any()).Return(nil).Times(1)
+	fApp.EXPECT().DeleteApp(gomock.Any(), app.Namespace, app.Name, gomock.Any()).Return(nil).Times(1)
 	req, _ = http.NewRequest(http.MethodDelete, "/v1/apps/abc", nil)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -2374,7 +2376,7 @@ func TestCreateKubeFunctionApplication(t *testing.T) {
 		"python36": "image",
 	}
 	sFunc.EXPECT().ListRuntimes().Return(funcs, nil).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err")).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err")).Times(1)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView)
@@ -2388,7 +2390,7 @@ func TestCreateKubeFunctionApplication(t *testing.T) {
 	sTemplate.EXPECT().UnmarshalTemplate("baetyl-python36-program.yml", gomock.Any(), config2).Return(nil).Times(1)
 	sFunc.EXPECT().ListRuntimes().Return(funcs, nil).Times(1)
 	// one more for program config
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(eden2, nil).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: eden2}, nil).Times(1)
 	sConfig.EXPECT().Get(appView.Namespace, gomock.Any(), "").Return(config, nil).AnyTimes()
 
 	w = httptest.NewRecorder()
@@ -2505,7 +2507,7 @@ func TestCreateKubeNotFunctionApplication(t *testing.T) {
 	sSecret.EXPECT().Get(appView.Namespace, "registry01", "").Return(secret, nil).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "eden2", "").Return(eden2, nil).Return(eden2, nil).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(eden2, nil).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: eden2}, nil).Times(1)
 	sSecret.EXPECT().Get(appView.Namespace, "secret01", "").Return(secret, nil).Times(1)
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(appView)
@@ -2721,7 +2723,7 @@ func TestCreateNativeFunctionApplication(t *testing.T) {
 		"python36": "image",
 	}
 	sFunc.EXPECT().ListRuntimes().Return(funcs, nil).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err")).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("err")).Times(1)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(appView)
@@ -2735,7 +2737,7 @@ func TestCreateNativeFunctionApplication(t *testing.T) {
 	sTempalte.EXPECT().UnmarshalTemplate("baetyl-python36-program.yml", gomock.Any(), config2).Return(nil).Times(1)
 	sFunc.EXPECT().ListRuntimes().Return(funcs, nil).Times(1)
 	// one more for program config
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(eden2, nil).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: eden2}, nil).Times(1)
 	sConfig.EXPECT().Get(appView.Namespace, gomock.Any(), "").Return(config, nil).AnyTimes()
 
 	w = httptest.NewRecorder()
@@ -2866,7 +2868,7 @@ func TestCreateNativeNotFunctionApplication(t *testing.T) {
 	sSecret.EXPECT().Get(appView.Namespace, "registry01", "").Return(secret, nil).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
 	sApp.EXPECT().Get(appView.Namespace, "eden2", "").Return(eden2, nil).Return(eden2, nil).Times(1)
-	fApp.EXPECT().CreateApp(appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(eden2, nil).Times(1)
+	fApp.EXPECT().CreateApp(gomock.Any(), appView.Namespace, gomock.Any(), gomock.Any(), gomock.Any()).Return(&facade.AppResult{App: eden2}, nil).Times(1)
 	sSecret.EXPECT().Get(appView.Namespace, "secret01", "").Return(secret, nil).Times(1)
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(appView)
@@ -2994,3 +2996,23 @@ func TestAPI_GetNodeSysAppRegistries(t *testing.T) {
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestGenConfigNameOfFunctionServiceWithPrefix(t *testing.T) {
+	api := &API{}
+	app := &specV1.Application{Name: "app"}
+	name, err := getGenConfigNameOfFunctionService(api.functionConfigPrefix(), app, "svc")
+	assert.NoError(t, err)
+	assert.Len(t, name, len("baetyl-function-config-app-svc-")+9)
+	name, err = getGenProgramNameOfFunctionService(api.functionProgramConfigPrefix(), app, "svc")
+	assert.NoError(t, err)
+	assert.Len(t, name, len("baetyl-function-program-config-app-svc-")+9)
+
+	api.conf = config.Facade{FunctionConfigPrefix: "fn", FunctionProgramConfigPrefix: "fnp"}
+	name, err = getGenConfigNameOfFunctionService(api.functionConfigPrefix(), app, "svc")
+	assert.NoError(t, err)
+	assert.Len(t, name, len("fn-app-svc-")+9)
+	assert.Equal(t, "fn-app-svc-", name[:len("fn-app-svc-")])
+	name, err = getGenProgramNameOfFunctionService(api.functionProgramConfigPrefix(), app, "svc")
+	assert.NoError(t, err)
+	assert.Equal(t, "fnp-app-svc-", name[:len("fnp-app-svc-")])
+}
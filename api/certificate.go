@@ -61,7 +61,7 @@ func (api *API) CreateCertificate(c *common.Context) (interface{}, error) {
 	if err = cfg.ParseCertInfo(); err != nil {
 		return nil, err
 	}
-	res, err := api.Facade.CreateSecret(ns, cfg.ToSecret())
+	res, err := api.Facade.CreateSecret(c.RequestContext(), ns, cfg.ToSecret())
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +106,7 @@ func (api *API) UpdateCertificate(c *common.Context) (interface{}, error) {
 // DeleteCertificate delete the Certificate
 func (api *API) DeleteCertificate(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
-	return api.deleteSecret(ns, n, "certificate")
+	return api.deleteSecret(c, ns, n, "certificate")
 }
 
 // GetAppByCertificate list app
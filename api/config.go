@@ -81,7 +81,7 @@ func (api *API) CreateConfig(c *common.Context) (interface{}, error) {
 			common.Field("error", "this name is already in use"))
 	}
 
-	config, err = api.Facade.CreateConfig(ns, config)
+	config, err = api.Facade.CreateConfig(c.RequestContext(), ns, config)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +115,7 @@ func (api *API) UpdateConfig(c *common.Context) (interface{}, error) {
 	config.UpdateTimestamp = time.Now()
 	config.CreationTimestamp = res.CreationTimestamp
 
-	res, err = api.Facade.UpdateConfig(ns, config)
+	res, err = api.Facade.UpdateConfig(c.RequestContext(), ns, config)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +148,7 @@ func (api *API) DeleteConfig(c *common.Context) (interface{}, error) {
 	}
 
 	//TODO: should remove file(bos/aws) of a function Config
-	return nil, api.Facade.DeleteConfig(ns, n)
+	return nil, api.Facade.DeleteConfig(c.RequestContext(), ns, n)
 }
 
 func (api *API) GetAppByConfig(c *common.Context) (interface{}, error) {
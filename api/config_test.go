@@ -294,7 +294,7 @@ func TestCreateConfig(t *testing.T) {
 		System:            false,
 	}
 	sConfig.EXPECT().Get(mConf.Namespace, mConf.Name, gomock.Any()).Return(nil, nil).Times(1)
-	fConfig.EXPECT().CreateConfig(mConf.Namespace, gomock.Any()).Return(res, nil).Times(1)
+	fConfig.EXPECT().CreateConfig(gomock.Any(), mConf.Namespace, gomock.Any()).Return(res, nil).Times(1)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mConf)
@@ -380,7 +380,7 @@ func TestCreateConfig(t *testing.T) {
 		System:            false,
 	}
 	sConfig.EXPECT().Get(mConf.Namespace, mConf.Name, gomock.Any()).Return(nil, nil).Times(1)
-	fConfig.EXPECT().CreateConfig(mConf.Namespace, gomock.Any()).Return(res, nil).Times(1)
+	fConfig.EXPECT().CreateConfig(gomock.Any(), mConf.Namespace, gomock.Any()).Return(res, nil).Times(1)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mConf)
@@ -432,7 +432,7 @@ func TestCreateConfig(t *testing.T) {
 		System:            false,
 	}
 	sConfig.EXPECT().Get(mConf.Namespace, mConf.Name, gomock.Any()).Return(nil, nil).Times(1)
-	fConfig.EXPECT().CreateConfig(mConf.Namespace, gomock.Any()).Return(res, nil).Times(1)
+	fConfig.EXPECT().CreateConfig(gomock.Any(), mConf.Namespace, gomock.Any()).Return(res, nil).Times(1)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mConf)
@@ -469,7 +469,7 @@ func TestCreateConfig(t *testing.T) {
 	}
 
 	sConfig.EXPECT().Get(mConf.Namespace, mConf.Name, gomock.Any()).Return(nil, nil).Times(1)
-	fConfig.EXPECT().CreateConfig(mConf.Namespace, gomock.Any()).Return(nil, errors.New("err")).Times(1)
+	fConfig.EXPECT().CreateConfig(gomock.Any(), mConf.Namespace, gomock.Any()).Return(nil, errors.New("err")).Times(1)
 
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mConf)
@@ -577,7 +577,7 @@ func TestUpdateConfig(t *testing.T) {
 
 	res2 := &specV1.Configuration{}
 	sConfig.EXPECT().Get(ns, name, gomock.Any()).Return(res2, nil).Times(1)
-	fConfig.EXPECT().UpdateConfig(ns, gomock.Any()).Return(nil, errors.New("err")).Times(1)
+	fConfig.EXPECT().UpdateConfig(gomock.Any(), ns, gomock.Any()).Return(nil, errors.New("err")).Times(1)
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mConf2)
 	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/abc", bytes.NewReader(body))
@@ -596,7 +596,7 @@ func TestUpdateConfig(t *testing.T) {
 		Description: "diff",
 	}
 	sConfig.EXPECT().Get(ns, name, gomock.Any()).Return(res3, nil).Times(1)
-	fConfig.EXPECT().UpdateConfig(ns, gomock.Any()).Return(res, nil).Times(1)
+	fConfig.EXPECT().UpdateConfig(gomock.Any(), ns, gomock.Any()).Return(res, nil).Times(1)
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mConf2)
 	req, _ = http.NewRequest(http.MethodPut, "/v1/configs/"+name, bytes.NewReader(body))
@@ -733,7 +733,7 @@ func TestDeleteConfig(t *testing.T) {
 	// 200
 	sConfig.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(mConf, nil)
 	sIndex.EXPECT().ListAppIndexByConfig(gomock.Any(), gomock.Any()).Return(nil, nil)
-	fConfig.EXPECT().DeleteConfig(mConf.Namespace, mConf.Name).Return(nil)
+	fConfig.EXPECT().DeleteConfig(gomock.Any(), mConf.Namespace, mConf.Name).Return(nil)
 	req, _ = http.NewRequest(http.MethodDelete, "/v1/configs/abc", nil)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
@@ -267,7 +267,7 @@ func (api *API) UpdateNode(c *common.Context) (interface{}, error) {
 		}
 	}
 
-	node, err = api.Node.Update(c.GetNamespace(), node)
+	node, err = api.Facade.UpdateNode(c.RequestContext(), c.GetNamespace(), node)
 	if err != nil {
 		return nil, err
 	}
@@ -366,6 +366,8 @@ func (api *API) deleteAllSysAppsOfNode(node *v1.Node) (interface{}, error) {
 				log.Any("type", common.Index),
 				log.Any(common.KeyContextNamespace, node.Namespace),
 				log.Any("app", v))
+		} else {
+			service.RecordNodesIndexRefresh(node.Namespace, v, 0)
 		}
 	}
 	return nil, nil
@@ -628,7 +630,7 @@ func (api *API) UpdateCoreApp(c *common.Context) (interface{}, error) {
 		return nil, err
 	}
 
-	_, err = api.Node.Update(ns, node)
+	_, err = api.Facade.UpdateNode(c.RequestContext(), ns, node)
 	if err != nil {
 		return nil, err
 	}
@@ -853,6 +855,8 @@ func (api *API) deleteDeletedSysApps(node *v1.Node, obsoleteAppAlias []string) e
 				log.Any("type", common.Index),
 				log.Any(common.KeyContextNamespace, node.Namespace),
 				log.Any("app", v))
+		} else {
+			service.RecordNodesIndexRefresh(node.Namespace, v, 0)
 		}
 	}
 
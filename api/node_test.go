@@ -18,6 +18,7 @@ import (
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	mf "github.com/baetyl/baetyl-cloud/v2/mock/facade"
 	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
@@ -752,6 +753,8 @@ func TestUpdateNodeAddSysApp(t *testing.T) {
 	sIndex := ms.NewMockIndexService(mockCtl)
 	sApp := ms.NewMockApplicationService(mockCtl)
 	sConfig := ms.NewMockConfigService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade = fApp
 	sModule := ms.NewMockModuleService(mockCtl)
 	sSysApp := ms.NewMockSystemAppService(mockCtl)
 	api.Node = sNode
@@ -781,7 +784,7 @@ func TestUpdateNodeAddSysApp(t *testing.T) {
 	}
 
 	sNode.EXPECT().Get(nil, gomock.Any(), gomock.Any()).Return(mNode, nil).Times(1)
-	sNode.EXPECT().Update(mNode.Namespace, mNode).Return(mNode, nil)
+	fApp.EXPECT().UpdateNode(gomock.Any(), mNode.Namespace, mNode).Return(mNode, nil)
 	// equal case
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(mNode)
@@ -837,7 +840,7 @@ func TestUpdateNodeAddSysApp(t *testing.T) {
 		},
 		SysApps: []string{"a"},
 	}
-	sNode.EXPECT().Update(mNode.Namespace, mNode3).Return(mNode3, nil)
+	fApp.EXPECT().UpdateNode(gomock.Any(), mNode.Namespace, mNode3).Return(mNode3, nil)
 	// equal
 	w = httptest.NewRecorder()
 	body, _ = json.Marshal(mNode3)
@@ -886,7 +889,7 @@ func TestUpdateNodeAddSysApp(t *testing.T) {
 		},
 		SysApps: []string{"a"},
 	}
-	sNode.EXPECT().Update(mNode.Namespace, mNode6).Return(mNode6, nil)
+	fApp.EXPECT().UpdateNode(gomock.Any(), mNode.Namespace, mNode6).Return(mNode6, nil)
 
 	mNode5 := &specV1.Node{
 		Namespace: "default",
@@ -919,6 +922,8 @@ func TestUpdateNodeDeleteSysApp(t *testing.T) {
 	sIndex := ms.NewMockIndexService(mockCtl)
 	sApp := ms.NewMockApplicationService(mockCtl)
 	sConfig := ms.NewMockConfigService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade = fApp
 	api.Node = sNode
 	api.Init = sInit
 	api.Prop = sProp
@@ -1005,7 +1010,7 @@ func TestUpdateNodeDeleteSysApp(t *testing.T) {
 			specV1.KeyAccelerator:      "",
 		},
 	}
-	sNode.EXPECT().Update(mNode7.Namespace, mNode9).Return(mNode9, nil)
+	fApp.EXPECT().UpdateNode(gomock.Any(), mNode7.Namespace, mNode9).Return(mNode9, nil)
 
 	mNode8 := &specV1.Node{
 		Namespace: "default",
@@ -1037,6 +1042,8 @@ func TestUpdateNodeAccelerator(t *testing.T) {
 	sIndex := ms.NewMockIndexService(mockCtl)
 	sApp := ms.NewMockApplicationService(mockCtl)
 	sConfig := ms.NewMockConfigService(mockCtl)
+	fApp := mf.NewMockFacade(mockCtl)
+	api.Facade = fApp
 	sModule := ms.NewMockModuleService(mockCtl)
 	sSysApp := ms.NewMockSystemAppService(mockCtl)
 	api.Node = sNode
@@ -1152,7 +1159,7 @@ func TestUpdateNodeAccelerator(t *testing.T) {
 	sConfig.EXPECT().Update(nil, initConf.Namespace, initConf).Return(initConf, nil)
 	sApp.EXPECT().Update(nil, gomock.Any(), initApp).Return(initApp, nil)
 
-	sNode.EXPECT().Update(newNode.Namespace, newNode).Return(newNode, nil)
+	fApp.EXPECT().UpdateNode(gomock.Any(), newNode.Namespace, newNode).Return(newNode, nil)
 	// equal case
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(newNode)
@@ -1837,8 +1844,10 @@ func TestAPI_UpdateCoreApp(t *testing.T) {
 	mockConfig := ms.NewMockConfigService(mockCtl)
 	mockInit := ms.NewMockInitService(mockCtl)
 	mockModule := ms.NewMockModuleService(mockCtl)
+	mockFacade := mf.NewMockFacade(mockCtl)
 	api.Init = mockInit
 	api.Node = mockNode
+	api.Facade = mockFacade
 	api.Index = mockIndex
 	api.App = mockApp
 	api.Prop = mockProp
@@ -1947,7 +1956,7 @@ func TestAPI_UpdateCoreApp(t *testing.T) {
 
 	mockApp.EXPECT().Update(nil, ns, coreApp).Return(coreApp, nil).Times(1)
 	mockNode.EXPECT().UpdateNodeAppVersion(nil, ns, coreApp).Return(appList, nil).Times(1)
-	mockNode.EXPECT().Update(ns, node).Return(node, nil).Times(1)
+	mockFacade.EXPECT().UpdateNode(gomock.Any(), ns, node).Return(node, nil).Times(1)
 
 	coreConfig = models.NodeCoreConfigs{
 		Version:   "v2.0.0",
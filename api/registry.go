@@ -63,7 +63,7 @@ func (api *API) CreateRegistry(c *common.Context) (interface{}, error) {
 	if err = api.validateRegistryModel(cfg); err != nil {
 		return nil, err
 	}
-	secret, err := api.Facade.CreateSecret(ns, cfg.ToSecret())
+	secret, err := api.Facade.CreateSecret(c.RequestContext(), ns, cfg.ToSecret())
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +118,7 @@ func (api *API) RefreshRegistryPassword(c *common.Context) (interface{}, error)
 		return nil, err
 	}
 
-	secret, err = api.Facade.UpdateSecret(ns, sd.ToSecret())
+	secret, err = api.Facade.UpdateSecret(c.RequestContext(), ns, sd.ToSecret())
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +128,7 @@ func (api *API) RefreshRegistryPassword(c *common.Context) (interface{}, error)
 // DeleteRegistry delete the Registry
 func (api *API) DeleteRegistry(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
-	return api.deleteSecret(ns, n, "registry")
+	return api.deleteSecret(c, ns, n, "registry")
 }
 
 // GetAppByRegistry list app
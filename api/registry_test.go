@@ -134,7 +134,7 @@ func TestCreateRegistry(t *testing.T) {
 		},
 	}
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
-	fSecret.EXPECT().CreateSecret(mConf.Namespace, gomock.Any()).Return(mConf2, nil)
+	fSecret.EXPECT().CreateSecret(gomock.Any(), mConf.Namespace, gomock.Any()).Return(mConf2, nil)
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(mConf)
 	req, _ := http.NewRequest(http.MethodPost, "/v1/registries", bytes.NewReader(body))
@@ -142,7 +142,7 @@ func TestCreateRegistry(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
-	fSecret.EXPECT().CreateSecret(mConf.Namespace, gomock.Any()).Return(nil, errors.New("create failed"))
+	fSecret.EXPECT().CreateSecret(gomock.Any(), mConf.Namespace, gomock.Any()).Return(nil, errors.New("create failed"))
 	w3 := httptest.NewRecorder()
 	body3, _ := json.Marshal(mConf)
 	req3, _ := http.NewRequest(http.MethodPost, "/v1/registries", bytes.NewReader(body3))
@@ -264,7 +264,7 @@ func TestRefreshRegistryPassword(t *testing.T) {
 		Password:  "haha",
 	}
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(mConfSecret, nil)
-	fSecret.EXPECT().UpdateSecret(mConf2.Namespace, gomock.Any()).Return(mConfSecret, nil)
+	fSecret.EXPECT().UpdateSecret(gomock.Any(), mConf2.Namespace, gomock.Any()).Return(mConfSecret, nil)
 	w3 := httptest.NewRecorder()
 	body3, _ := json.Marshal(mConf2)
 	req3, _ := http.NewRequest(http.MethodPost, "/v1/registries/cba/refresh", bytes.NewReader(body3))
@@ -299,7 +299,7 @@ func TestDeleteRegistry(t *testing.T) {
 		},
 	}
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(mConfSecret, nil)
-	fSecret.EXPECT().DeleteSecret(mConfSecret.Namespace, mConfSecret.Name).Return(nil).AnyTimes()
+	fSecret.EXPECT().DeleteSecret(gomock.Any(), mConfSecret.Namespace, mConfSecret.Name).Return(nil).AnyTimes()
 	sIndex.EXPECT().ListAppIndexBySecret(gomock.Any(), gomock.Any()).Return(nil, nil)
 	// 200
 	req, _ := http.NewRequest(http.MethodDelete, "/v1/registries/abc", nil)
@@ -57,7 +57,7 @@ func (api *API) CreateSecret(c *common.Context) (interface{}, error) {
 	if sd != nil {
 		return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", "this name is already in use"))
 	}
-	res, err := api.Facade.CreateSecret(ns, cfg.ToSecret())
+	res, err := api.Facade.CreateSecret(c.RequestContext(), ns, cfg.ToSecret())
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +83,7 @@ func (api *API) UpdateSecret(c *common.Context) (interface{}, error) {
 
 	cfg.Version = sd.Version
 	cfg.UpdateTimestamp = time.Now()
-	secret, err := api.Facade.UpdateSecret(ns, cfg.ToSecret())
+	secret, err := api.Facade.UpdateSecret(c.RequestContext(), ns, cfg.ToSecret())
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +93,7 @@ func (api *API) UpdateSecret(c *common.Context) (interface{}, error) {
 // DeleteSecret delete the secret
 func (api *API) DeleteSecret(c *common.Context) (interface{}, error) {
 	ns, n := c.GetNamespace(), c.GetNameFromParam()
-	return api.deleteSecret(ns, n, "secret")
+	return api.deleteSecret(c, ns, n, "secret")
 }
 
 // GetAppBySecret list app
@@ -141,7 +141,7 @@ func (api *API) ToSecretViewList(s *models.SecretList) *models.SecretViewList {
 	return models.FromSecretListToView(s, false)
 }
 
-func (api *API) deleteSecret(namespace, secret, secretType string) (interface{}, error) {
+func (api *API) deleteSecret(c *common.Context, namespace, secret, secretType string) (interface{}, error) {
 	_, err := api.Secret.Get(namespace, secret, "")
 	if err != nil {
 		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
@@ -159,7 +159,7 @@ func (api *API) deleteSecret(namespace, secret, secretType string) (interface{},
 	if len(appNames) > 0 {
 		return nil, common.Error(common.ErrResourceHasBeenUsed, common.Field("type", secretType), common.Field("name", secret))
 	}
-	return nil, api.Facade.DeleteSecret(namespace, secret)
+	return nil, api.Facade.DeleteSecret(c.RequestContext(), namespace, secret)
 }
 
 func (api *API) listAppBySecret(namespace, secret string) (*models.ApplicationList, error) {
@@ -127,7 +127,7 @@ func TestCreateSecret(t *testing.T) {
 		},
 	}
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
-	fSecret.EXPECT().CreateSecret(mConf.Namespace, gomock.Any()).Return(mConf2, nil)
+	fSecret.EXPECT().CreateSecret(gomock.Any(), mConf.Namespace, gomock.Any()).Return(mConf2, nil)
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(mConf)
 	req, _ := http.NewRequest(http.MethodPost, "/v1/secrets", bytes.NewReader(body))
@@ -135,7 +135,7 @@ func TestCreateSecret(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
-	fSecret.EXPECT().CreateSecret(mConf.Namespace, gomock.Any()).Return(nil, errors.New("create failed"))
+	fSecret.EXPECT().CreateSecret(gomock.Any(), mConf.Namespace, gomock.Any()).Return(nil, errors.New("create failed"))
 	w3 := httptest.NewRecorder()
 	body3, _ := json.Marshal(mConf)
 	req3, _ := http.NewRequest(http.MethodPost, "/v1/secrets", bytes.NewReader(body3))
@@ -210,14 +210,14 @@ func TestUpdateSecret(t *testing.T) {
 		},
 	}
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(mConfSecret2, nil).AnyTimes()
-	fSecret.EXPECT().UpdateSecret(mConfSecret2.Namespace, gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid))
+	fSecret.EXPECT().UpdateSecret(gomock.Any(), mConfSecret2.Namespace, gomock.Any()).Return(nil, common.Error(common.ErrRequestParamInvalid))
 	w3 := httptest.NewRecorder()
 	body3, _ := json.Marshal(mConf)
 	req3, _ := http.NewRequest(http.MethodPut, "/v1/secrets/cba", bytes.NewReader(body3))
 	router.ServeHTTP(w3, req3)
 	assert.Equal(t, http.StatusBadRequest, w3.Code)
 
-	fSecret.EXPECT().UpdateSecret(mConfSecret2.Namespace, gomock.Any()).Return(mConfSecret2, nil)
+	fSecret.EXPECT().UpdateSecret(gomock.Any(), mConfSecret2.Namespace, gomock.Any()).Return(mConfSecret2, nil)
 	w4 := httptest.NewRecorder()
 	body4, _ := json.Marshal(mConf)
 	req4, _ := http.NewRequest(http.MethodPut, "/v1/secrets/abc", bytes.NewReader(body4))
@@ -252,7 +252,7 @@ func TestDeleteSecret(t *testing.T) {
 		},
 	}
 	sSecret.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(mConfSecret, nil).AnyTimes()
-	fSecret.EXPECT().DeleteSecret(mConfSecret.Namespace, mConfSecret.Name).Return(nil).AnyTimes()
+	fSecret.EXPECT().DeleteSecret(gomock.Any(), mConfSecret.Namespace, mConfSecret.Name).Return(nil).AnyTimes()
 	sIndex.EXPECT().ListAppIndexBySecret(gomock.Any(), gomock.Any()).Return(nil, nil)
 	// 200
 	req, _ := http.NewRequest(http.MethodDelete, "/v1/secrets/abc", nil)
@@ -126,6 +126,56 @@ const (
 	LabelCluster     = "baetyl-cluster"
 	LabelNodeMode    = "baetyl-node-mode"
 	LabelAppMode     = "baetyl-app-mode"
+	// LabelCronTimezone IANA timezone in which the cron time of the app is interpreted
+	LabelCronTimezone = "baetyl-cron-timezone"
+	// LabelCronTimes the comma separated RFC3339 times on which the cron of the app fires besides its cron time,
+	// they're interpreted in the timezone of the cron as well
+	LabelCronTimes = "baetyl-cron-times"
+	// LabelCronMisfirePolicy the policy applied to the cron times of the app missed by the scheduler, skip if empty
+	LabelCronMisfirePolicy = "baetyl-cron-misfire-policy"
+	// LabelCronOverlapPolicy the policy applied to the run of the cron of the app while the previous run is applying
+	LabelCronOverlapPolicy = "baetyl-cron-overlap-policy"
+	// LabelCronNoNodesPolicy the policy applied to the run of the cron of the app whose selector matches no nodes, proceed if empty
+	LabelCronNoNodesPolicy = "baetyl-cron-no-nodes-policy"
+	// LabelCronSelectorResolver the resolver which resolves the selector of the cron of the app as its query
+	// to the nodes each time the cron fires, the selector is the label selector if empty
+	LabelCronSelectorResolver = "baetyl-cron-selector-resolver"
+	// LabelCronPaused marks the app whose cron is paused, it is only set on the app read and never stored
+	LabelCronPaused = "baetyl-cron-paused"
+	// LabelCronNextRuns the comma separated RFC3339 upcoming times of the cron of the app in its timezone,
+	// it is only set on the app read and never stored
+	LabelCronNextRuns = "baetyl-cron-next-runs"
+	// LabelAppFrozen marks the app frozen by FreezeApp, it is only set on the app read and never stored
+	LabelAppFrozen = "baetyl-app-frozen"
+	// LabelSkipNodeIndex marks the app whose node bindings are managed externally if it's "true", the app is persisted
+	// and versioned, but never deployed to the nodes matched by its selector nor removed from the nodes
+	LabelSkipNodeIndex = "baetyl-skip-node-index"
+	// LabelActivateAt the RFC3339 time the version of the app is scheduled to be deployed to the nodes at,
+	// it is only set on the app read and never stored
+	LabelActivateAt = "baetyl-activate-at"
+	// LabelCanaryPercent the percent of the nodes matched by the selector which the current version of the app
+	// is rolled out to, the other nodes keep the version they desire until the app is promoted
+	LabelCanaryPercent = "baetyl-canary-percent"
+	// LabelRolloutMaxNodes the max nodes the current version of the app is updated on at once, the other matched
+	// nodes keep the version they desire until the rollout is advanced as the updated nodes report it running
+	LabelRolloutMaxNodes = "baetyl-rollout-max-nodes"
+	// LabelAnnotationPrefix the prefix of the labels which carry the annotations of the app, e.g. the team,
+	// cost-center and ticket of the app, they're stored as labels so that the apps can be selected by them
+	LabelAnnotationPrefix = "annotation." + BaetylCloudGroup + "/"
+	// LabelConfigChecksum the checksum of the data of the generated function config computed when it's written,
+	// which is compared with the data read to detect the corruption of the store
+	LabelConfigChecksum = "baetyl-config-checksum"
+	// LabelConfigOptional marks the generated function config optional, it's written within a savepoint and
+	// skipped if failed instead of aborting the mutation of the app
+	LabelConfigOptional = "baetyl-config-optional"
+	// LabelConfigBlobs marks the config whose large values are stored in the content-addressed blob store,
+	// the values referencing the blobs are replaced with the blobs when the config is read
+	LabelConfigBlobs = "baetyl-config-blobs"
+	// LabelConfigEncrypted marks the config whose values are encrypted at rest, the encrypted values carry the ids
+	// of the keys encrypting them and are decrypted when the config is read
+	LabelConfigEncrypted = "baetyl-config-encrypted"
+	// LabelAppTemplate marks the config which stores an app template instantiated by InstantiateTemplate
+	LabelAppTemplate = "baetyl-app-template"
 )
 
 const (
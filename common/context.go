@@ -6,13 +6,11 @@ import (
 	"net/http"
 	"runtime/debug"
 
-	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
 	"github.com/baetyl/baetyl-go/v2/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	uuid "github.com/satori/go.uuid"
-	"gopkg.in/go-playground/validator.v9"
 )
 
 // Context context
@@ -51,6 +49,50 @@ func NewContextEmpty() *Context {
 	return &Context{&gin.Context{}}
 }
 
+// HeaderIdempotencyKey the header of the request carrying the idempotency key
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+type userContextKey struct{}
+type idempotencyKeyContextKey struct{}
+
+// RequestContext returns the context of the http request, which is canceled if the client goes away,
+// the user and the idempotency key of the request are carried by the context if exist
+func (c *Context) RequestContext() context.Context {
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = c.Request.Context()
+		if key := c.Request.Header.Get(HeaderIdempotencyKey); key != "" {
+			ctx = WithIdempotencyKey(ctx, key)
+		}
+	}
+	if user, ok := c.Get("user"); ok {
+		ctx = WithUser(ctx, user.(User))
+	}
+	return ctx
+}
+
+// WithUser returns a copy of the context carrying the user
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext gets the user carried by the context if exists
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userContextKey{}).(User)
+	return user, ok
+}
+
+// WithIdempotencyKey returns a copy of the context carrying the idempotency key
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext gets the idempotency key carried by the context, empty if not exists
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
 // SetNamespace sets namespace into context
 func (c *Context) SetNamespace(ns string) {
 	c.Set("namespace", ns)
@@ -125,13 +167,7 @@ func (c *Context) LoadBody(obj interface{}) error {
 	if err != nil {
 		return err
 	}
-	err = validate.Struct(obj)
-	if err != nil {
-		if es, ok := err.(validator.ValidationErrors); ok {
-			for _, v := range es {
-				return Error(Code(v.Tag()), Field(v.Tag(), v.Field()), Field("error", err.Error()))
-			}
-		}
+	if err = ValidateStruct(obj); err != nil {
 		return err
 	}
 	return utils.SetDefaults(obj)
@@ -142,13 +178,7 @@ func (c *Context) LoadBodyMulti(obj interface{}) error {
 	if err != nil {
 		return err
 	}
-	err = validate.Struct(obj)
-	if err != nil {
-		if es, ok := err.(validator.ValidationErrors); ok {
-			for _, v := range es {
-				return Error(Code(v.Tag()), Field(v.Tag(), v.Field()), Field("error", err.Error()))
-			}
-		}
+	if err = ValidateStruct(obj); err != nil {
 		return err
 	}
 	return utils.SetDefaults(obj)
@@ -170,22 +200,14 @@ func PackageResponse(res interface{}) (int, interface{}) {
 
 // PopulateFailedResponse PopulateFailedResponse
 func PopulateFailedResponse(cc *Context, err error, abort bool) {
-	var code string
-	var status int
-	switch e := err.(type) {
-	case errors.Coder:
-		code = e.Code()
-		status = getHTTPStatus(Code(e.Code()))
-	default:
-		code = ErrUnknown
-		status = http.StatusInternalServerError
-	}
+	code := CodeOf(err)
+	status := getHTTPStatus(code)
 
 	log.L().Error("process failed.", log.Any(cc.GetTrace()), log.Code(err))
 
 	k, v := cc.GetTrace()
 	body := gin.H{
-		"code":    code,
+		"code":    string(code),
 		"message": err.Error(),
 		k:         v,
 	}
@@ -324,3 +324,21 @@ func TestWrapperMis(t *testing.T) {
 	router.ServeHTTP(w5, req)
 	assert.Equal(t, http.StatusOK, w5.Code)
 }
+
+func TestContext_RequestContext(t *testing.T) {
+	c := NewContextEmpty()
+	ctx := c.RequestContext()
+	assert.NotNil(t, ctx)
+	_, ok := UserFromContext(ctx)
+	assert.False(t, ok)
+
+	c.SetUser(User{ID: "id", Name: "name"})
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	user, ok := UserFromContext(c.RequestContext())
+	assert.True(t, ok)
+	assert.Equal(t, User{ID: "id", Name: "name"}, user)
+	assert.Equal(t, "", IdempotencyKeyFromContext(c.RequestContext()))
+
+	c.Request.Header.Set(HeaderIdempotencyKey, "key")
+	assert.Equal(t, "key", IdempotencyKeyFromContext(c.RequestContext()))
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"bytes"
+	goerrors "errors"
 	"strings"
 	"text/template"
 
@@ -35,6 +36,16 @@ func Error(c Code, fs ...*F) error {
 	return errors.CodeError(string(c), m)
 }
 
+// CodeOf returns the code of the error, or of the first error it wraps which has a code, e.g. the coded error
+// wrapped by fmt.Errorf with %w. The errors without any code are internal errors, which are coded ErrUnknown.
+func CodeOf(err error) Code {
+	var coder errors.Coder
+	if goerrors.As(err, &coder) {
+		return Code(coder.Code())
+	}
+	return ErrUnknown
+}
+
 // Field field
 type F struct {
 	k string
@@ -1,8 +1,12 @@
 package common
 
 import (
+	goerrors "errors"
+	"fmt"
+	"net/http"
 	"testing"
 
+	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -99,3 +103,30 @@ func TestError(t *testing.T) {
 		})
 	}
 }
+
+func TestCodeOf(t *testing.T) {
+	assert.Equal(t, Code(ErrResourceNotFound), CodeOf(Error(ErrResourceNotFound)))
+	assert.Equal(t, Code(ErrResourceNotFound), CodeOf(errors.Trace(Error(ErrResourceNotFound))))
+	assert.Equal(t, Code(ErrResourceNotFound), CodeOf(fmt.Errorf("wrapped: %w", Error(ErrResourceNotFound))))
+	assert.Equal(t, Code(ErrUnknown), CodeOf(errors.Trace(goerrors.New("raw"))))
+	assert.Equal(t, Code(ErrUnknown), CodeOf(fmt.Errorf("wrapped: %v", Error(ErrResourceNotFound))))
+}
+
+func TestGetHTTPStatus(t *testing.T) {
+	tests := map[Code]int{
+		ErrResourceConflict:        http.StatusBadRequest,
+		ErrAppNameConflict:         http.StatusBadRequest,
+		ErrResourceVersionConflict: http.StatusConflict,
+		ErrResourceNotFound:        http.StatusNotFound,
+		ErrConfigHistoryMissing:    http.StatusNotFound,
+		ErrQuotaExceeded:           http.StatusForbidden,
+		ErrRequestParamInvalid:     http.StatusBadRequest,
+		ErrConfigInUsed:            http.StatusBadRequest,
+		ErrForbidden:               http.StatusForbidden,
+		ErrUnknown:                 http.StatusInternalServerError,
+		ErrConfigCipher:            http.StatusInternalServerError,
+	}
+	for code, status := range tests {
+		assert.Equal(t, status, getHTTPStatus(code), code)
+	}
+}
@@ -28,6 +28,7 @@ const (
 	ErrResourceNotFound        = "ErrResourceNotFound"
 	ErrResourceAccessForbidden = "ErrResourceAccessForbidden"
 	ErrResourceConflict        = "ErrResourceConflict"
+	ErrResourceVersionConflict = "ErrResourceVersionConflict"
 	ErrResourceDeleteForbidden = "ErrResourceDeleteForbidden"
 	ErrResourceHasBeenUsed     = "ErrResourceHasBeenUsed"
 	ErrNodeNotReady            = "ErrNodeNotReady"
@@ -41,12 +42,34 @@ const (
 	ErrAppNameConflict         = "ErrAppNameConflict"
 	ErrVolumeNotFoundWhenMount = "ErrVolumeNotFoundWhenMount"
 	ErrAppReferencedByNode     = "ErrAppReferencedByNode"
+	ErrIdempotencyKeyConflict  = "ErrIdempotencyKeyConflict"
+	ErrQuotaExceeded           = "ErrQuotaExceeded"
+	ErrInvalidAppName          = "ErrInvalidAppName"
+	ErrLargeImpact             = "ErrLargeImpact"
+	ErrLocked                  = "ErrLocked"
+	ErrShuttingDown            = "ErrShuttingDown"
+	ErrRateLimited             = "ErrRateLimited"
+	ErrMissingConfigRef        = "ErrMissingConfigRef"
+	ErrMissingSecretRef        = "ErrMissingSecretRef"
+	ErrForbidden               = "ErrForbidden"
+	ErrAppValidation           = "ErrAppValidation"
+	ErrAppFrozen               = "ErrAppFrozen"
+	ErrInvalidBaseApp          = "ErrInvalidBaseApp"
+	ErrConfigHistoryMissing    = "ErrConfigHistoryMissing"
+	ErrCronNoNodes             = "ErrCronNoNodes"
+	// * cron
+	ErrInvalidCron         = "ErrInvalidCron"
+	ErrInvalidCronTimezone = "ErrInvalidCronTimezone"
+	ErrCronRunning         = "ErrCronRunning"
+	ErrNotLeader           = "ErrNotLeader"
 	// * node
 	ErrNodeNumMaxLimit       = "ErrNodeNumMaxLimit"
 	ErrNodeNumQueryException = "ErrNodeNumQueryException"
 
 	// * config
-	ErrConfigInUsed = "ErrConfigInUsed"
+	ErrConfigInUsed   = "ErrConfigInUsed"
+	ErrConfigTooLarge = "ErrConfigTooLarge"
+	ErrConfigCipher   = "ErrConfigCipher"
 	// * register
 	ErrRegisterQuotaNumOut     = "ErrRegisterQuotaNumOut"
 	ErrRegisterDeleteRecord    = "ErrRegisterDeleteRecord"
@@ -112,6 +135,7 @@ var templates = map[Code]string{
 	ErrResourceAccessForbidden: `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} can not be accessed{{if .namespace}} in namespace({{.namespace}}){{end}}.`,
 	ErrResourceConflict:        `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} already exist.`,
 	ErrResourceHasBeenUsed:     `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} has been used.`,
+	ErrResourceVersionConflict: `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} has been modified{{if .version}}, the current version is ({{.version}}){{end}}, please get it and retry.`,
 	ErrResourceDeleteForbidden: `The {{if .type}}({{.type}}) {{end}}resource{{if .name}} ({{.name}}){{end}} can not be deleted{{if .namespace}} in namespace({{.namespace}}){{end}}`,
 	// * volumes
 	ErrVolumeType: "The volume{{if .name}} ({{.name}}){{end}} type should be{{if .type}} ({{.type}}){{end}}.",
@@ -122,11 +146,33 @@ var templates = map[Code]string{
 	ErrVolumeNotFoundWhenMount: "The mount volume name{{if .name}}({{.name}}){{end}} can't find in the Volumes[].",
 	ErrNodeNotReady:            "The node {{if .name}}({{.name}} ){{end}}is not ready, please retry later.",
 	ErrAppReferencedByNode:     "The {{if .name}}({{.name}}){{end}} app is still referenced by a node.",
+	ErrIdempotencyKeyConflict:  "The idempotency key{{if .key}} ({{.key}}){{end}} has been used by the app{{if .name}} ({{.name}}){{end}}.",
+	ErrInvalidAppName:          "The name of the app{{if .name}} ({{.name}}){{end}} is invalid.{{if .rule}} ({{.rule}}){{end}}",
+	ErrLargeImpact:             "The update of the app{{if .name}} ({{.name}}){{end}} removes it from {{.removed}} of the {{.total}} nodes, which exceeds the limit, please confirm it with force.",
+	ErrLocked:                  "The app{{if .name}} ({{.name}}){{end}} is being changed by another request, please retry later.",
+	ErrShuttingDown:            "The server is shutting down, please retry later.",
+	ErrMissingConfigRef:        "The configs{{if .configs}} ({{.configs}}){{end}} referenced by the volumes of the app{{if .name}} ({{.name}}){{end}} are not found.",
+	ErrMissingSecretRef:        "The secrets{{if .secrets}} ({{.secrets}}){{end}} referenced by the volumes of the app{{if .name}} ({{.name}}){{end}} are not found.",
+	ErrAppValidation:           "The app{{if .name}} ({{.name}}){{end}} is invalid.{{if .errors}} ({{.errors}}){{end}}",
+	ErrAppFrozen:               "The app{{if .name}} ({{.name}}){{end}} is frozen, it can't be changed until it's unfrozen.",
+	ErrInvalidBaseApp:          "The app can't be created with the base app{{if .name}} ({{.name}}){{end}}.{{if .error}} ({{.error}}){{end}}",
+	ErrConfigHistoryMissing:    "The config ({{.name}}) of the version ({{.version}}) isn't kept, the app can't be rolled back with its configs.",
+	ErrCronNoNodes:             "The run of the cron of the app ({{.name}}) is skipped, its selector ({{.selector}}) matches no nodes.",
+	ErrForbidden:               "The user{{if .user}} ({{.user}}){{end}} is forbidden to access the namespace{{if .namespace}} ({{.namespace}}){{end}}.",
+	ErrRateLimited:             "The requests of the apps{{if .namespace}} in the namespace ({{.namespace}}){{end}} are too frequent, please retry later.",
+	ErrQuotaExceeded:           "The number of {{if .type}}{{.type}}s{{else}}resources{{end}} in the namespace{{if .namespace}} ({{.namespace}}){{end}} reaches the quota{{if .limit}} ({{.limit}}){{end}}.",
+	// * cron
+	ErrInvalidCron:         "The cron of the app{{if .name}} ({{.name}}){{end}} is invalid.{{if .error}} ({{.error}}){{end}}",
+	ErrInvalidCronTimezone: "The timezone{{if .timezone}} ({{.timezone}}){{end}} of the cron is unknown.",
+	ErrCronRunning:         "The previous run of the cron of the app{{if .name}} ({{.name}}){{end}} is still applying, the run is skipped.",
+	ErrNotLeader:           "The lease ({{.name}}) is held by another replica{{if .error}}, {{.error}}{{end}}.",
 	// * node
 	ErrNodeNumMaxLimit:       "The number of nodes reaches the maximum limit",
 	ErrNodeNumQueryException: "The number of nodes is null",
 	// * config
-	ErrConfigInUsed: "The config name {{if .name}}({{.name}}){{end}} in used.",
+	ErrConfigInUsed:   "The config name {{if .name}}({{.name}}){{end}} in used.{{if .apps}} (referenced by apps: {{.apps}}){{end}}",
+	ErrConfigTooLarge: "The size of the config{{if .name}} ({{.name}}){{end}} is {{.size}} bytes, which exceeds the limit ({{.limit}} bytes).",
+	ErrConfigCipher:   "The value{{if .key}} ({{.key}}){{end}} of the config{{if .name}} ({{.name}}){{end}} fails to be encrypted or decrypted.{{if .error}} ({{.error}}){{end}}",
 	// * register
 	ErrRegisterQuotaNumOut:     "Number reached the upper limit {{if .num}}({{.num}}){{end}}",
 	ErrRegisterDeleteRecord:    "Batch {{if .name}}({{.name}}){{end}} delete failed, record not null.",
@@ -180,15 +226,24 @@ var templates = map[Code]string{
 	ErrUpdateSubLabels: "Failed to update sub node labels. {{if .error}} ({{.error}}){{end}}",
 }
 
+// getHTTPStatus maps the codes to the HTTP status by their categories, the codes of the invalid input
+// (e.g. ErrRequestParamInvalid) are mapped to 400 by default, and the internal errors (ErrUnknown) to 500.
+// The codes existing before, e.g. ErrResourceConflict and ErrConfigInUsed, keep their status for the clients.
 func getHTTPStatus(c Code) int {
 	switch c {
-	case ErrResourceNotFound, ErrRequestMethodNotFound:
+	case ErrResourceNotFound, ErrRequestMethodNotFound, ErrConfigHistoryMissing:
 		return http.StatusNotFound
 	case ErrRequestAccessDenied:
 		return http.StatusUnauthorized
-	case ErrResourceHasBeenUsed:
+	case ErrResourceHasBeenUsed, ErrQuotaExceeded, ErrForbidden:
 		return http.StatusForbidden
-	case ErrUnknown:
+	case ErrResourceVersionConflict, ErrIdempotencyKeyConflict, ErrCronRunning, ErrLocked, ErrAppFrozen, ErrNotLeader:
+		return http.StatusConflict
+	case ErrShuttingDown:
+		return http.StatusServiceUnavailable
+	case ErrRateLimited:
+		return http.StatusTooManyRequests
+	case ErrUnknown, ErrConfigCipher:
 		return http.StatusInternalServerError
 	default:
 		return http.StatusBadRequest
@@ -117,6 +117,21 @@ func validMaxLengthFunc() validator.Func {
 	}
 }
 
+// ValidateStruct validates the fields of the object by their validate tags, the error of the first
+// invalid field is returned with the code of its tag
+func ValidateStruct(obj interface{}) error {
+	err := validate.Struct(obj)
+	if err != nil {
+		if es, ok := err.(validator.ValidationErrors); ok {
+			for _, v := range es {
+				return Error(Code(v.Tag()), Field(v.Tag(), v.Field()), Field("error", err.Error()))
+			}
+		}
+		return err
+	}
+	return nil
+}
+
 func ValidNonBaetyl(name string) bool {
 	return !strings.Contains(name, "baetyl")
 }
@@ -15,6 +15,7 @@ type CloudConfig struct {
 	LogInfo     log.Config `yaml:"logger" json:"logger"`
 	Task        Task       `yaml:"task" json:"task"`
 	Lock        Lock       `yaml:"lock" json:"lock"`
+	Facade      Facade     `yaml:"facade" json:"facade"`
 	CronJobs    []CronJob  `yaml:"cronJobs" json:"cronJobs" default:"[]"`
 	Cache       struct {
 		ExpirationDuration time.Duration `yaml:"expirationDuration" json:"expirationDuration" default:"10m"`
@@ -23,31 +24,53 @@ type CloudConfig struct {
 		Path string `yaml:"path" json:"path" default:"/etc/baetyl/templates"`
 	} `yaml:"template" json:"template"`
 	Plugin struct {
-		Pubsub     string   `yaml:"pubsub" json:"pubsub" default:"defaultpubsub"`
-		PKI        string   `yaml:"pki" json:"pki" default:"defaultpki"`
-		Auth       string   `yaml:"auth" json:"auth" default:"defaultauth"`
-		License    string   `yaml:"license" json:"license" default:"defaultlicense"`
-		Resource   string   `yaml:"resource" json:"resource" default:"kube"`
-		Shadow     string   `yaml:"shadow" json:"shadow" default:"database"`
-		Index      string   `yaml:"index" json:"index" default:"database"`
-		Batch      string   `yaml:"batch" json:"batch" default:"databaseext"`
-		Record     string   `yaml:"record" json:"record" default:"databaseext"`
-		Callback   string   `yaml:"callback" json:"callback" default:"databaseext"`
-		AppHistory string   `yaml:"appHistory" json:"appHistory" default:"database"`
-		Objects    []string `yaml:"objects" json:"objects" default:"[]"`
-		Functions  []string `yaml:"functions" json:"functions" default:"[]"`
-		Property   string   `yaml:"property" json:"property" default:"database"`
-		Module     string   `yaml:"module" json:"module" default:"database"`
-		SyncLinks  []string `yaml:"synclinks" json:"synclinks" default:"[\"httplink\"]"`
-		Locker     string   `yaml:"locker" json:"locker" default:"defaultlocker"`
-		Task       string   `yaml:"task" json:"task" default:"defaulttask"`
-		Sign       string   `yaml:"sign" json:"sign" default:"defaultsign"`
-		DM         string   `yaml:"dm" json:"dm" default:"databaseext"`
-		Tx         string   `yaml:"tx" json:"tx" default:"defaulttx"`
-		Cron       string   `yaml:"cron" json:"cron" default:"database"`
-		Csrf       string   `yaml:"csrf" json:"csrf" default:"defaultcsrf"`
-		JWT        string   `yaml:"jwt" json:"jwt" default:"defaultjwt"`
+		Pubsub      string   `yaml:"pubsub" json:"pubsub" default:"defaultpubsub"`
+		PKI         string   `yaml:"pki" json:"pki" default:"defaultpki"`
+		Auth        string   `yaml:"auth" json:"auth" default:"defaultauth"`
+		License     string   `yaml:"license" json:"license" default:"defaultlicense"`
+		Resource    string   `yaml:"resource" json:"resource" default:"kube"`
+		Shadow      string   `yaml:"shadow" json:"shadow" default:"database"`
+		Index       string   `yaml:"index" json:"index" default:"database"`
+		Batch       string   `yaml:"batch" json:"batch" default:"databaseext"`
+		Record      string   `yaml:"record" json:"record" default:"databaseext"`
+		Callback    string   `yaml:"callback" json:"callback" default:"databaseext"`
+		AppHistory  string   `yaml:"appHistory" json:"appHistory" default:"database"`
+		Objects     []string `yaml:"objects" json:"objects" default:"[]"`
+		Functions   []string `yaml:"functions" json:"functions" default:"[]"`
+		Property    string   `yaml:"property" json:"property" default:"database"`
+		Module      string   `yaml:"module" json:"module" default:"database"`
+		SyncLinks   []string `yaml:"synclinks" json:"synclinks" default:"[\"httplink\"]"`
+		Locker      string   `yaml:"locker" json:"locker" default:"database"`
+		Task        string   `yaml:"task" json:"task" default:"defaulttask"`
+		Sign        string   `yaml:"sign" json:"sign" default:"defaultsign"`
+		DM          string   `yaml:"dm" json:"dm" default:"databaseext"`
+		Tx          string   `yaml:"tx" json:"tx" default:"defaulttx"`
+		Cron        string   `yaml:"cron" json:"cron" default:"database"`
+		Csrf        string   `yaml:"csrf" json:"csrf" default:"defaultcsrf"`
+		JWT         string   `yaml:"jwt" json:"jwt" default:"defaultjwt"`
+		Event       string   `yaml:"event" json:"event" default:"defaultevent"`
+		Audit       string   `yaml:"audit" json:"audit" default:"database"`
+		Recycle     string   `yaml:"recycle" json:"recycle" default:"database"`
+		Idempotency string   `yaml:"idempotency" json:"idempotency" default:"database"`
+		Outbox      string   `yaml:"outbox" json:"outbox" default:"database"`
+		RateLimiter string   `yaml:"rateLimiter" json:"rateLimiter" default:"defaultratelimiter"`
+		Activation  string   `yaml:"activation" json:"activation" default:"database"`
+		Freeze      string   `yaml:"freeze" json:"freeze" default:"database"`
+		// ConfigBlob the content-addressed store of the large values of the generated function configs,
+		// the configs referencing the blobs are read with the blobs inlined. It's disabled if empty
+		ConfigBlob string `yaml:"configBlob" json:"configBlob"`
+		// KeyProvider provides the keys of ConfigEncryption, which is disabled if empty
+		KeyProvider string `yaml:"keyProvider" json:"keyProvider"`
 	} `yaml:"plugin" json:"plugin"`
+	// ConfigEncryption encrypts the values of the configs whose keys match the patterns at rest
+	ConfigEncryption ConfigEncryption `yaml:"configEncryption" json:"configEncryption"`
+}
+
+// ConfigEncryption encrypts the values of the configs whose keys match the Keys patterns, e.g. "*.password", by the
+// current key of the KeyProvider plugin when the configs are written, and decrypts them when the configs are read.
+// The values are re-encrypted by the current key once the configs are written after the key is rotated
+type ConfigEncryption struct {
+	Keys []string `yaml:"keys" json:"keys"`
 }
 
 type CronJob struct {
@@ -82,3 +105,166 @@ type Task struct {
 type Lock struct {
 	ExpireTime int64 `yaml:"expireTime" json:"expireTime" default:"5" unit:"second"`
 }
+
+// Facade facade config
+type Facade struct {
+	TxRetry TxRetry `yaml:"txRetry" json:"txRetry"`
+	// TxIsolation overrides the isolation levels of the transactions of the facade methods by the method names,
+	// the levels are "read-committed", "repeatable-read", "serializable" and "default" of the storage
+	TxIsolation map[string]string `yaml:"txIsolation" json:"txIsolation"`
+	// StrictConfigClean aborts the app update or deletion if the generated function configs fail to be deleted
+	StrictConfigClean bool             `yaml:"strictConfigClean" json:"strictConfigClean"`
+	ConfigReclaim     ConfigReclaim    `yaml:"configReclaim" json:"configReclaim"`
+	SoftDelete        SoftDelete       `yaml:"softDelete" json:"softDelete"`
+	Idempotency       Idempotency      `yaml:"idempotency" json:"idempotency"`
+	AppQuota          AppQuota         `yaml:"appQuota" json:"appQuota"`
+	AppCache          AppCache         `yaml:"appCache" json:"appCache"`
+	ImpactGuard       ImpactGuard      `yaml:"impactGuard" json:"impactGuard"`
+	Outbox            Outbox           `yaml:"outbox" json:"outbox"`
+	RateLimit         RateLimit        `yaml:"rateLimit" json:"rateLimit"`
+	VersionRetention  VersionRetention `yaml:"versionRetention" json:"versionRetention"`
+	Activation        Activation       `yaml:"activation" json:"activation"`
+	Rollout           Rollout          `yaml:"rollout" json:"rollout"`
+	CronLease         CronLease        `yaml:"cronLease" json:"cronLease"`
+	CronScheduler     CronScheduler    `yaml:"cronScheduler" json:"cronScheduler"`
+	// AppFreeze enables FreezeApp, the apps frozen can't be updated or deleted until they're unfrozen
+	AppFreeze bool `yaml:"appFreeze" json:"appFreeze"`
+	// CronRuns records the runs of the cron apps applied by TriggerCronApp in the cron store, which are listed by ListCronRuns
+	CronRuns bool `yaml:"cronRuns" json:"cronRuns"`
+	// RollbackConfigs restores the generated function configs to their content at the version RollbackApp rolls back to,
+	// the config store must keep the versions of the configs, which are read by the versions referenced by the volumes
+	RollbackConfigs bool `yaml:"rollbackConfigs" json:"rollbackConfigs"`
+	// IndexAppLabels indexes the labels of the apps along with their nodes, so that the nodes are listed by the app
+	// labels by ListAppNodesByLabel. The label index table is required only if it's enabled.
+	IndexAppLabels bool `yaml:"indexAppLabels" json:"indexAppLabels"`
+	// FunctionConfigPrefix and FunctionProgramConfigPrefix name the configs generated for the function apps,
+	// the deployments sharing a store should use different prefixes to tell their configs apart
+	FunctionConfigPrefix        string `yaml:"functionConfigPrefix" json:"functionConfigPrefix" default:"baetyl-function-config"`
+	FunctionProgramConfigPrefix string `yaml:"functionProgramConfigPrefix" json:"functionProgramConfigPrefix" default:"baetyl-function-program-config"`
+	// ConfigSizeLimit the maximum effective size in bytes of a config, which is the size of the ConfigMap synced to
+	// the edge with the binary values encoded in base64, 0 means unlimited
+	ConfigSizeLimit int `yaml:"configSizeLimit" json:"configSizeLimit" default:"1048576"`
+	// ConfigBlobThreshold the values of the generated function configs larger than it in bytes are stored once in the
+	// ConfigBlob store by their hashes and referenced by the configs, 0 means the values are always stored inline.
+	// It takes effect only if the ConfigBlob plugin is set. The blobs are referenced by the latest versions of the
+	// configs only, the versions whose blobs are released are missing to RollbackConfigs
+	ConfigBlobThreshold int `yaml:"configBlobThreshold" json:"configBlobThreshold"`
+	// IndexPageSize the number of apps or nodes loaded per page when verifying or repairing the node-app indexes
+	IndexPageSize int `yaml:"indexPageSize" json:"indexPageSize" default:"100"`
+	// ReservedAppNamePrefixes the prefixes reserved for the system apps, the apps named with them can't be created
+	ReservedAppNamePrefixes []string `yaml:"reservedAppNamePrefixes" json:"reservedAppNamePrefixes" default:"[\"baetyl-\"]"`
+}
+
+// VersionRetention prunes the versions of the apps kept in the history after the updates, the versions neither
+// among the latest KeepLast nor newer than MaxAge are pruned, the policy of 0 is ignored and nothing is pruned
+// if both are 0. The current version and the pinned versions are always kept
+type VersionRetention struct {
+	Enabled  bool          `yaml:"enabled" json:"enabled"`
+	KeepLast int           `yaml:"keepLast" json:"keepLast" default:"10"`
+	MaxAge   time.Duration `yaml:"maxAge" json:"maxAge"`
+}
+
+// Activation activates the versions of the apps scheduled to be activated later once they're due,
+// the due activations are checked every Interval and at most BatchSize of them are activated per round
+type Activation struct {
+	Enabled   bool          `yaml:"enabled" json:"enabled"`
+	Interval  time.Duration `yaml:"interval" json:"interval" default:"1m"`
+	BatchSize int           `yaml:"batchSize" json:"batchSize" default:"100"`
+}
+
+// Rollout advances the staggered rollouts of the apps of all the namespaces every Interval, as AdvanceRollout
+// does for each of them, so that the next nodes are updated once the updating ones report the version running
+type Rollout struct {
+	Enabled  bool          `yaml:"enabled" json:"enabled"`
+	Interval time.Duration `yaml:"interval" json:"interval" default:"1m"`
+}
+
+// CronLease the lease the replica acquires to lead the cron scheduler, which is renewed every RenewInterval
+// and lost if not renewed within TTL, so that only one of the replicas fires the crons at once
+type CronLease struct {
+	TTL           time.Duration `yaml:"ttl" json:"ttl" default:"30s"`
+	RenewInterval time.Duration `yaml:"renewInterval" json:"renewInterval" default:"10s"`
+}
+
+// CronScheduler fires the expired crons every Interval by FireDueCrons, the cron times passed longer than
+// MisfireThreshold when fired are missed and handled by the misfire policies of the crons
+type CronScheduler struct {
+	Enabled          bool          `yaml:"enabled" json:"enabled"`
+	Interval         time.Duration `yaml:"interval" json:"interval" default:"10s"`
+	MisfireThreshold time.Duration `yaml:"misfireThreshold" json:"misfireThreshold" default:"1m"`
+}
+
+// RateLimit limits the writes of the apps by the token buckets of the namespaces, or of the apps if PerApp,
+// the rate and the burst of the namespace override the default ones. The reads are limited too if Reads
+type RateLimit struct {
+	Enabled    bool                     `yaml:"enabled" json:"enabled"`
+	Rate       float64                  `yaml:"rate" json:"rate" default:"10"`
+	Burst      int                      `yaml:"burst" json:"burst" default:"20"`
+	PerApp     bool                     `yaml:"perApp" json:"perApp"`
+	Reads      bool                     `yaml:"reads" json:"reads"`
+	Namespaces map[string]RateLimitRule `yaml:"namespaces" json:"namespaces"`
+}
+
+// RateLimitRule the tokens refilled per second and the capacity of the token bucket
+type RateLimitRule struct {
+	Rate  float64 `yaml:"rate" json:"rate"`
+	Burst int     `yaml:"burst" json:"burst"`
+}
+
+// Outbox writes the app events to the outbox within the transactions of the changes, and relays them to the
+// event sink afterwards, so that no committed change is missed by the sink. The events are delivered at least once.
+// The operations on the crons of the apps are written to the outbox too and relayed to the cron store if Cron,
+// so that the crons converge to the apps committed instead of being compensated on rollback
+type Outbox struct {
+	Enabled       bool          `yaml:"enabled" json:"enabled"`
+	Cron          bool          `yaml:"cron" json:"cron"`
+	RelayInterval time.Duration `yaml:"relayInterval" json:"relayInterval" default:"5s"`
+	BatchSize     int           `yaml:"batchSize" json:"batchSize" default:"100"`
+}
+
+// ImpactGuard rejects the selector change of an app which removes it from more nodes than the limits unless forced,
+// the number and the percent of the nodes matched by the old selector are checked if positive, 0 means unlimited
+type ImpactGuard struct {
+	MaxRemovedNodes   int `yaml:"maxRemovedNodes" json:"maxRemovedNodes"`
+	MaxRemovedPercent int `yaml:"maxRemovedPercent" json:"maxRemovedPercent"`
+}
+
+// AppCache the in-process LRU cache of the apps read by GetApp, the cache is invalidated by the mutations
+// of this process only, so it should be disabled if the apps are changed by the other replicas
+type AppCache struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	Size    int  `yaml:"size" json:"size" default:"1024"`
+}
+
+// AppQuota limits the number of apps of a namespace, the limit of the namespace overrides the default one,
+// 0 means unlimited
+type AppQuota struct {
+	Limit      int            `yaml:"limit" json:"limit"`
+	Namespaces map[string]int `yaml:"namespaces" json:"namespaces"`
+}
+
+// Idempotency policy of the idempotency keys of app creation
+type Idempotency struct {
+	TTL        time.Duration `yaml:"ttl" json:"ttl" default:"24h"`
+	GCInterval time.Duration `yaml:"gcInterval" json:"gcInterval" default:"1h"`
+}
+
+// SoftDelete policy of keeping the deleted apps in the recycle bin, the apps are restorable
+// until purged after the retention
+type SoftDelete struct {
+	Enabled       bool          `yaml:"enabled" json:"enabled"`
+	Retention     time.Duration `yaml:"retention" json:"retention" default:"72h"`
+	PurgeInterval time.Duration `yaml:"purgeInterval" json:"purgeInterval" default:"10m"`
+}
+
+// ConfigReclaim policy of reclaiming the orphaned function configs
+type ConfigReclaim struct {
+	GracePeriod time.Duration `yaml:"gracePeriod" json:"gracePeriod" default:"10m"`
+	BatchSize   int           `yaml:"batchSize" json:"batchSize" default:"50"`
+}
+
+// TxRetry retry policy of the transactions failed by deadlock
+type TxRetry struct {
+	Max       int           `yaml:"max" json:"max" default:"3"`
+	BaseDelay time.Duration `yaml:"baseDelay" json:"baseDelay" default:"50ms"`
+}
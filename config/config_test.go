@@ -50,15 +50,49 @@ func TestDefaultValue(t *testing.T) {
 	expect.Plugin.Module = "database"
 	expect.Plugin.SyncLinks = []string{"httplink"}
 	expect.Plugin.Pubsub = "defaultpubsub"
-	expect.Plugin.Locker = "defaultlocker"
+	expect.Plugin.Locker = "database"
 	expect.Plugin.Task = "defaulttask"
 	expect.Lock.ExpireTime = 5
+	expect.Facade.TxRetry.Max = 3
+	expect.Facade.TxRetry.BaseDelay = time.Millisecond * 50
+	expect.Facade.ConfigReclaim.GracePeriod = time.Minute * 10
+	expect.Facade.ConfigReclaim.BatchSize = 50
+	expect.Facade.SoftDelete.Retention = time.Hour * 72
+	expect.Facade.SoftDelete.PurgeInterval = time.Minute * 10
+	expect.Facade.Idempotency.TTL = time.Hour * 24
+	expect.Facade.Idempotency.GCInterval = time.Hour
+	expect.Facade.Outbox.RelayInterval = time.Second * 5
+	expect.Facade.Outbox.BatchSize = 100
+	expect.Facade.RateLimit.Rate = 10
+	expect.Facade.RateLimit.Burst = 20
+	expect.Facade.VersionRetention.KeepLast = 10
+	expect.Facade.Activation.Interval = time.Minute
+	expect.Facade.Activation.BatchSize = 100
+	expect.Facade.Rollout.Interval = time.Minute
+	expect.Facade.CronScheduler.Interval = time.Second * 10
+	expect.Facade.CronScheduler.MisfireThreshold = time.Minute
+	expect.Facade.CronLease.TTL = time.Second * 30
+	expect.Facade.CronLease.RenewInterval = time.Second * 10
+	expect.Facade.IndexPageSize = 100
+	expect.Facade.AppCache.Size = 1024
+	expect.Facade.ConfigSizeLimit = 1048576
+	expect.Facade.ReservedAppNamePrefixes = []string{"baetyl-"}
+	expect.Facade.FunctionConfigPrefix = "baetyl-function-config"
+	expect.Facade.FunctionProgramConfigPrefix = "baetyl-function-program-config"
 	expect.Plugin.DM = "databaseext"
 	expect.Plugin.Tx = "defaulttx"
 	expect.Plugin.Sign = "defaultsign"
 	expect.Plugin.Cron = "database"
 	expect.Plugin.Csrf = "defaultcsrf"
 	expect.Plugin.JWT = "defaultjwt"
+	expect.Plugin.Event = "defaultevent"
+	expect.Plugin.Audit = "database"
+	expect.Plugin.Recycle = "database"
+	expect.Plugin.Idempotency = "database"
+	expect.Plugin.Outbox = "database"
+	expect.Plugin.RateLimiter = "defaultratelimiter"
+	expect.Plugin.Activation = "database"
+	expect.Plugin.Freeze = "database"
 
 	expect.Template.Path = "/etc/baetyl/templates"
 
@@ -0,0 +1,210 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type activateAtKey struct{}
+
+// WithActivateAt returns a copy of ctx with which the app created or updated is stored and versioned but not
+// deployed to the nodes, which keep the version they desire until the app is activated at the time by
+// ActivateDueApps. Unlike the cron, the activation fires once and the app behaves normally afterwards.
+func WithActivateAt(ctx context.Context, at time.Time) context.Context {
+	return context.WithValue(ctx, activateAtKey{}, at)
+}
+
+func activateAtFromContext(ctx context.Context) (time.Time, bool) {
+	at, ok := ctx.Value(activateAtKey{}).(time.Time)
+	return at, ok
+}
+
+// checkActivateAt checks the activation of the app scheduled by ctx, which must be enabled and in the future
+func (a *facade) checkActivateAt(ctx context.Context, app *specV1.Application) error {
+	at, ok := activateAtFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if a.activation == nil {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the scheduled activation of the apps is disabled"))
+	}
+	if !at.After(time.Now()) {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the activation time (%s) should be after now", at.Format(time.RFC3339))))
+	}
+	if app.CronStatus == specV1.CronWait {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the app (%s) waiting for cron can't be scheduled to activate", app.Name)))
+	}
+	return nil
+}
+
+// scheduleAppActivation schedules the activation of the version of the app written within the transaction,
+// the activation scheduled before is replaced
+func (a *facade) scheduleAppActivation(tx interface{}, ns string, app *specV1.Application, at time.Time) error {
+	return a.activation.CreateAppActivation(tx, &models.AppActivation{
+		Namespace:  ns,
+		Name:       app.Name,
+		Version:    app.Version,
+		ActivateAt: at.UTC(),
+	})
+}
+
+// dropAppActivation deletes the activation of the app scheduled before, since the app is deployed or deleted
+func (a *facade) dropAppActivation(tx interface{}, ns, name string) error {
+	if a.activation == nil {
+		return nil
+	}
+	return a.activation.DeleteAppActivation(tx, ns, name)
+}
+
+// labelAppActivation labels the version of the app read with the time it's scheduled to be activated at
+func (a *facade) labelAppActivation(ns string, app *specV1.Application) error {
+	delete(app.Labels, common.LabelActivateAt)
+	if a.activation == nil {
+		return nil
+	}
+	activation, err := a.activation.GetAppActivation(ns, app.Name)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	if activation.Version != app.Version {
+		return nil
+	}
+	if app.Labels == nil {
+		app.Labels = map[string]string{}
+	}
+	app.Labels[common.LabelActivateAt] = activation.ActivateAt.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// CancelAppActivation cancels the activation of the app scheduled before, the version stored is kept but not
+// deployed, so the nodes keep the version they desire until the app is updated again
+func (a *facade) CancelAppActivation(ctx context.Context, ns, name string) (err error) {
+	defer observeCall(ns, "CancelAppActivation", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	if a.activation == nil {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the scheduled activation of the apps is disabled"))
+	}
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	activation, err := a.activation.GetAppActivation(ns, name)
+	if err != nil {
+		return err
+	}
+	err = a.runTx(ctx, ns, "CancelAppActivation", func(tx interface{}, undo *compensations) error {
+		undo.onCommit(func() { a.invalidateCachedApp(ns, name) })
+		return a.activation.DeleteAppActivation(tx, ns, name)
+	})
+	if err != nil {
+		return err
+	}
+	a.log.Info("app activation canceled",
+		log.Any(common.KeyContextNamespace, ns),
+		log.Any("name", name),
+		log.Any("version", activation.Version),
+		log.Any("activateAt", activation.ActivateAt))
+	return nil
+}
+
+// ActivateDueApps activates the versions of the apps whose activations are due in the order of their times,
+// the apps are deployed to the nodes matched by their selectors and removed from the nodes no longer matched.
+// The activations are checked in batches until no more is due, the first failure stops the round and the
+// activation failed is retried next round.
+func (a *facade) ActivateDueApps(ctx context.Context) error {
+	if a.activation == nil {
+		return nil
+	}
+	batch := a.conf.Activation.BatchSize
+	for {
+		if err := ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		activations, err := a.activation.ListDueAppActivations(time.Now(), batch)
+		if err != nil {
+			return err
+		}
+		for i := range activations {
+			if err = a.activateApp(ctx, &activations[i]); err != nil {
+				a.log.Warn("failed to activate app",
+					log.Any(common.KeyContextNamespace, activations[i].Namespace),
+					log.Any("name", activations[i].Name),
+					log.Any("version", activations[i].Version),
+					log.Error(err))
+				return err
+			}
+		}
+		if len(activations) < batch {
+			return nil
+		}
+	}
+}
+
+// activateApp deploys the version of the app scheduled to activate and deletes its activation,
+// the activation of the app missing or updated since then is deleted only
+func (a *facade) activateApp(ctx context.Context, activation *models.AppActivation) error {
+	ns, name := activation.Namespace, activation.Name
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var app *specV1.Application
+	var nodes []string
+	err = a.runTx(ctx, ns, "ActivateApp", func(tx interface{}, undo *compensations) error {
+		app = nil
+		cur, err := a.app.Get(ns, name, "")
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		if cur != nil && cur.Version == activation.Version {
+			indexed, err := a.index.ListNodesByApp(ns, name)
+			if err != nil {
+				return err
+			}
+			if nodes, err = a.reindexApp(tx, ns, cur, indexed, undo); err != nil {
+				return err
+			}
+			if err = a.writeAppOutbox(tx, models.AppActivated, ns, cur, nodes); err != nil {
+				return err
+			}
+			app = cur
+			undo.onCommit(func() { a.publishAppEvent(ctx, models.AppActivated, ns, cur, nodes) })
+		}
+		return a.activation.DeleteAppActivation(tx, ns, name)
+	})
+	if err != nil {
+		return err
+	}
+	if app == nil {
+		a.log.Warn("the activation of the app missing or updated is dropped",
+			log.Any(common.KeyContextNamespace, ns), log.Any("name", name), log.Any("version", activation.Version))
+		return nil
+	}
+	a.log.Info("app activated",
+		log.Any(common.KeyContextNamespace, ns),
+		log.Any("name", name),
+		log.Any("version", app.Version),
+		log.Any("activateAt", activation.ActivateAt),
+		log.Any("nodes", nodes))
+	return nil
+}
@@ -0,0 +1,174 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestScheduleAppActivation(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:       mAppFacade.sNode,
+		app:        mAppFacade.sApp,
+		config:     mAppFacade.sConfig,
+		secret:     mAppFacade.sSecret,
+		index:      mAppFacade.sIndex,
+		cron:       mAppFacade.sCron,
+		activation: mAppFacade.sActivate,
+		txFactory:  mAppFacade.txFactory,
+		log:        log.L(),
+	}
+	ns := "baetyl-cloud"
+	at := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	ctx := WithActivateAt(context.Background(), at)
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	// the app created is stored without being deployed to the nodes
+	app := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b"}
+	created := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Version: "1"}
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(created, nil)
+	mAppFacade.sActivate.EXPECT().CreateAppActivation(nil, &models.AppActivation{Namespace: ns, Name: "abc", Version: "1", ActivateAt: at}).Return(nil)
+	res, err := appFacade.CreateApp(ctx, ns, nil, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, created, res.App)
+	assert.Empty(t, res.Nodes)
+
+	// the pending activation is labeled on the version read
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Namespace: ns, Name: "abc", Version: "1"}, nil)
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "abc").Return(&models.AppActivation{Namespace: ns, Name: "abc", Version: "1", ActivateAt: at}, nil)
+	read, err := appFacade.GetApp(context.Background(), ns, "abc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, at.Format(time.RFC3339), read.Labels[common.LabelActivateAt])
+
+	// the nodes keep the version they desire until the version updated is activated
+	app = &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d", Labels: map[string]string{common.LabelActivateAt: "x"}}
+	updated := &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d", Version: "2"}
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(created, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d", Labels: map[string]string{}, Version: created.Version}).Return(updated, nil)
+	mAppFacade.sActivate.EXPECT().CreateAppActivation(nil, &models.AppActivation{Namespace: ns, Name: "abc", Version: "2", ActivateAt: at}).Return(nil)
+	res, err = appFacade.UpdateApp(ctx, ns, created, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, res.App)
+
+	// the update deployed immediately drops the pending activation
+	app = &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d"}
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(updated, nil)
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "abc").Return(&models.AppActivation{Namespace: ns, Name: "abc", Version: "2", ActivateAt: at}, nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(updated, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(updated, nil)
+	mAppFacade.sActivate.EXPECT().DeleteAppActivation(nil, ns, "abc").Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, updated).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	res, err = appFacade.UpdateApp(context.Background(), ns, updated, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, res.Nodes)
+
+	// the invalid activations
+	_, err = appFacade.CreateApp(WithActivateAt(context.Background(), time.Now().Add(-time.Minute)), ns, nil, &specV1.Application{Namespace: ns, Name: "abc"}, nil)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+	assert.Contains(t, err.Error(), "should be after now")
+	cronApp := &specV1.Application{Namespace: ns, Name: "abc", CronStatus: specV1.CronWait, CronTime: time.Now().Add(time.Hour)}
+	err = appFacade.checkActivateAt(ctx, cronApp)
+	assert.Contains(t, err.Error(), "waiting for cron")
+	appFacade.activation = nil
+	err = appFacade.checkActivateAt(ctx, app)
+	assert.Contains(t, err.Error(), "disabled")
+	assert.NoError(t, appFacade.checkActivateAt(context.Background(), app))
+	assert.NoError(t, appFacade.ActivateDueApps(context.Background()))
+}
+
+func TestCancelAppActivation(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		activation: mAppFacade.sActivate,
+		txFactory:  mAppFacade.txFactory,
+		log:        log.L(),
+	}
+	ns := "baetyl-cloud"
+	activation := &models.AppActivation{Namespace: ns, Name: "abc", Version: "1", ActivateAt: time.Now()}
+
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "abc").Return(activation, nil)
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.sActivate.EXPECT().DeleteAppActivation(nil, ns, "abc").Return(nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil)
+	assert.NoError(t, appFacade.CancelAppActivation(context.Background(), ns, "abc"))
+
+	// nothing is scheduled
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "abc").Return(nil, common.Error(common.ErrResourceNotFound))
+	err := appFacade.CancelAppActivation(context.Background(), ns, "abc")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	appFacade.activation = nil
+	err = appFacade.CancelAppActivation(context.Background(), ns, "abc")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
+
+func TestActivateDueApps(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:       mAppFacade.sNode,
+		app:        mAppFacade.sApp,
+		index:      mAppFacade.sIndex,
+		activation: mAppFacade.sActivate,
+		txFactory:  mAppFacade.txFactory,
+		watchers:   newAppWatchers(),
+		conf:       config.Facade{Activation: config.Activation{BatchSize: 2}},
+		log:        log.L(),
+	}
+	ns := "baetyl-cloud"
+	now := time.Now()
+	due := []models.AppActivation{
+		{Namespace: ns, Name: "abc", Version: "2", ActivateAt: now.Add(-time.Minute)},
+		{Namespace: ns, Name: "stale", Version: "1", ActivateAt: now.Add(-time.Minute)},
+	}
+	app := &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d", Version: "2"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	events, err := appFacade.WatchApps(context.Background(), ns)
+	assert.NoError(t, err)
+
+	// the version due is deployed to the nodes matched and removed from the nodes no longer matched,
+	// the activation of the app updated since then is dropped
+	gomock.InOrder(
+		mAppFacade.sActivate.EXPECT().ListDueAppActivations(gomock.Any(), 2).Return(due, nil),
+		mAppFacade.sActivate.EXPECT().ListDueAppActivations(gomock.Any(), 2).Return(nil, nil),
+	)
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(app, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "abc").Return([]string{"n0", "n1"}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "c=d").Return([]string{"n1", "n2"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n0"}, app, gomock.Any()).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1", "n2"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1", "n2"}).Return(nil)
+	mAppFacade.sActivate.EXPECT().DeleteAppActivation(nil, ns, "abc").Return(nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "stale", "").Return(&specV1.Application{Namespace: ns, Name: "stale", Version: "3"}, nil)
+	mAppFacade.sActivate.EXPECT().DeleteAppActivation(nil, ns, "stale").Return(nil)
+	assert.NoError(t, appFacade.ActivateDueApps(context.Background()))
+	event := <-events
+	assert.Equal(t, models.AppActivated, event.Action)
+	assert.Equal(t, "abc", event.Name)
+	assert.Equal(t, []string{"n1", "n2"}, event.Nodes)
+
+	// the failure stops the round
+	mAppFacade.sActivate.EXPECT().ListDueAppActivations(gomock.Any(), 2).Return(due[:1], nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, unknownErr)
+	err = appFacade.ActivateDueApps(context.Background())
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+}
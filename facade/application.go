@@ -1,228 +1,1805 @@
 package facade
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
+// the default prefixes of the generated function configs, which are overridden by the facade config
 const (
 	FunctionConfigPrefix        = "baetyl-function-config"
 	FunctionProgramConfigPrefix = "baetyl-function-program-config"
 )
 
-func (a *facade) GetApp(ns, name, version string) (*specV1.Application, error) {
-	app, err := a.app.Get(ns, name, version)
+// AppPreview the result of a dry run deployment of an application
+type AppPreview struct {
+	Nodes          []string `json:"nodes"`
+	CreatedConfigs []string `json:"createdConfigs"`
+	UpdatedConfigs []string `json:"updatedConfigs"`
+}
+
+// AppResult the app created or updated with the nodes whose desire is changed by the mutation, which are the nodes
+// the app is deployed to, and for the update also the nodes it's removed from, the same as the nodes of its event
+type AppResult struct {
+	App   *specV1.Application
+	Nodes []string
+}
+
+// AppCreateRequest one application of a batch creation
+type AppCreateRequest struct {
+	BaseApp *specV1.Application
+	App     *specV1.Application
+	Configs []specV1.Configuration
+}
+
+// GetApp gets the app, the app waiting for cron carries the selector of its cron.
+// The cron is read within a read-only transaction if ctx is returned by WithReadOnlyTx, otherwise
+// the app is served by the app cache if enabled.
+// The app is returned without selector if its cron is not found, which is expected while the cron
+// is being triggered or deleted, but the failure of reading the cron is returned.
+func (a *facade) GetApp(ctx context.Context, ns, name, version string) (app *specV1.Application, err error) {
+	defer observeCall(ns, "GetApp", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	ctx, span := startAppSpan(ctx, "GetApp", ns, name)
+	defer func() { endSpan(span, app, err) }()
+	if err = ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = a.limitAppReads(ctx, ns); err != nil {
+		return nil, err
+	}
+	// the consistent snapshot asked by the read-only transaction is never served by the cache
+	cached := !readOnlyTxFromContext(ctx)
+	key := appCacheKey{ns: ns, name: name, version: version}
+	var gen uint64
+	if cached {
+		var ok bool
+		if app, gen, ok = a.getCachedApp(key); ok {
+			return app, nil
+		}
+	}
+	err = a.runReadTx(ctx, ns, "GetApp", func(tx interface{}) error {
+		res, err := a.app.Get(ns, name, version)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if res != nil && res.CronStatus == specV1.CronWait {
+			cronApp, err := a.cron.GetCron(tx, name, ns)
+			if err == nil {
+				res.Selector = cronApp.Selector
+				res.Labels = withCronPausedLabel(res.Labels, cronApp.Paused)
+				res.Labels = withCronNextRunsLabel(res.Labels, cronApp)
+			} else if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+				a.log.Warn("the cron of the app waiting for cron is not found",
+					log.Any("namespace", ns), log.Any("name", name), log.Any("version", res.Version))
+			} else {
+				a.log.Error("failed to get the cron of the app",
+					log.Any("namespace", ns), log.Any("name", name), log.Error(err))
+				return errors.Trace(err)
+			}
+		}
+		if res != nil {
+			if err = a.labelAppActivation(ns, res); err != nil {
+				a.log.Error("failed to get the activation of the app",
+					log.Any("namespace", ns), log.Any("name", name), log.Error(err))
+				return err
+			}
+			if err = a.labelAppFrozen(tx, ns, res); err != nil {
+				a.log.Error("failed to get the freeze of the app",
+					log.Any("namespace", ns), log.Any("name", name), log.Error(err))
+				return err
+			}
+		}
+		app = res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached {
+		a.cacheApp(key, app, gen)
+	}
+	return app, nil
+}
+
+// GetApps gets the versions of the apps referred to in the order of refs, the current versions are read by a single
+// query and the crons of the apps waiting for cron are read by another, the apps not in the current versions
+// are read one by one. ErrResourceNotFound listing all the apps missing is returned if any is not found.
+func (a *facade) GetApps(ctx context.Context, ns string, refs []models.AppRef) (apps []*specV1.Application, err error) {
+	defer observeCall(ns, "GetApps", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = a.limitAppReads(ctx, ns); err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return []*specV1.Application{}, nil
+	}
+	names := make([]string, 0, len(refs))
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		if !seen[ref.Name] {
+			seen[ref.Name] = true
+			names = append(names, ref.Name)
+		}
+	}
+	err = a.runReadTx(ctx, ns, "GetApps", func(tx interface{}) error {
+		current, err := a.app.GetBatch(ns, names)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		byName := map[string]*specV1.Application{}
+		for _, app := range current {
+			byName[app.Name] = app
+		}
+		res := make([]*specV1.Application, 0, len(refs))
+		var missing []string
+		for _, ref := range refs {
+			app, ok := byName[ref.Name]
+			if ok && ref.Version != "" && ref.Version != app.Version {
+				if app, err = a.app.Get(ns, ref.Name, ref.Version); err != nil && !isNotFound(err) {
+					return errors.Trace(err)
+				}
+				ok = err == nil && app != nil
+			}
+			if !ok {
+				missing = append(missing, appRefString(ref))
+				continue
+			}
+			res = append(res, app)
+		}
+		if len(missing) > 0 {
+			return common.Error(common.ErrResourceNotFound,
+				common.Field("type", "app"),
+				common.Field("name", strings.Join(missing, ",")),
+				common.Field("namespace", ns))
+		}
+		if err = a.fillCronSelectors(tx, ns, res); err != nil {
+			return err
+		}
+		apps = res
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// fillCronSelectors sets the selectors of the apps waiting for cron to the ones of their crons read in a batch,
+// the app is kept without selector if its cron is not found as GetApp does
+func (a *facade) fillCronSelectors(tx interface{}, ns string, apps []*specV1.Application) error {
+	var names []string
+	for _, app := range apps {
+		if app.CronStatus == specV1.CronWait {
+			names = append(names, app.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	crons, err := a.cron.ListCrons(tx, ns, names)
+	if err != nil {
+		a.log.Error("failed to list the crons of the apps",
+			log.Any("namespace", ns), log.Any("names", names), log.Error(err))
+		return errors.Trace(err)
+	}
+	byName := map[string]models.Cron{}
+	for _, c := range crons {
+		byName[c.Name] = c
+	}
+	for _, app := range apps {
+		if app.CronStatus != specV1.CronWait {
+			continue
+		}
+		cronApp, ok := byName[app.Name]
+		if !ok {
+			a.log.Warn("the cron of the app waiting for cron is not found",
+				log.Any("namespace", ns), log.Any("name", app.Name), log.Any("version", app.Version))
+			continue
+		}
+		app.Selector = cronApp.Selector
+		app.Labels = withCronPausedLabel(app.Labels, cronApp.Paused)
+	}
+	return nil
+}
+
+func appRefString(ref models.AppRef) string {
+	if ref.Version == "" {
+		return ref.Name
+	}
+	return ref.Name + "@" + ref.Version
+}
+
+// ListApps lists the apps of the namespace filtered by the label selector, the annotation selector and the name
+// substring. The apps are paged by the cursor (limit and continue) of opt only, since the store doesn't filter
+// by the name substring: the name filter applies to the page listed, which may hold fewer apps than the limit
+// even if more follow. The total counts all the apps matched by the selectors and the name, see countApps. The
+// apps waiting for cron carry the selector of their cron, the crons are read within a read-only transaction if
+// ctx is returned by WithReadOnlyTx.
+func (a *facade) ListApps(ctx context.Context, ns string, opt *models.ListOptions) (res *models.ApplicationList, err error) {
+	defer observeCall(ns, "ListApps", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err = a.limitAppReads(ctx, ns); err != nil {
+		return nil, err
+	}
+	if opt == nil {
+		opt = &models.ListOptions{}
+	}
+	selector := opt.LabelSelector
+	if opt.AnnotationSelector != "" {
+		as, err := models.AnnotationSelectorToLabelSelector(opt.AnnotationSelector)
+		if err != nil {
+			return nil, common.Error(common.ErrRequestParamInvalid, common.Field("error", err.Error()))
+		}
+		if strings.TrimSpace(selector) != "" {
+			selector += ","
+		}
+		selector += as
+	}
+	list, err := a.app.List(ns, &models.ListOptions{
+		LabelSelector: selector,
+		Limit:         opt.Limit,
+		Continue:      opt.Continue,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	name := strings.Trim(opt.Name, "%")
+	items := make([]models.AppItem, 0, len(list.Items))
+	for _, item := range list.Items {
+		if name != "" && !strings.Contains(item.Name, name) {
+			continue
+		}
+		item.Annotations = models.AppAnnotations(item.Labels)
+		items = append(items, item)
+	}
+	total := len(items)
+	if opt.Limit > 0 {
+		if total, err = a.countApps(ns, selector, name); err != nil {
+			return nil, err
+		}
+	}
+	res = &models.ApplicationList{Total: total, ListOptions: opt}
+	opt.Continue = ""
+	if list.ListOptions != nil {
+		opt.Continue = list.ListOptions.Continue
+	}
+
+	err = a.runReadTx(ctx, ns, "ListApps", func(tx interface{}) error {
+		for i := range items {
+			if items[i].CronStatus != specV1.CronWait {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return errors.Trace(err)
+			}
+			cronApp, err := a.cron.GetCron(tx, items[i].Name, ns)
+			if err == nil {
+				items[i].Selector = cronApp.Selector
+				items[i].CronTime = cronApp.CronTime
+				items[i].CronTimes = cronApp.CronTimes
+				items[i].Labels = withCronPausedLabel(items[i].Labels, cronApp.Paused)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Items = items
+	return res, nil
+}
+
+// countApps counts the apps of the namespace matched by the label selector and the name substring, by listing
+// them without the cursor, as the store has no count query.
+func (a *facade) countApps(ns, selector, name string) (int, error) {
+	list, err := a.app.List(ns, &models.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	count := 0
+	for _, item := range list.Items {
+		if name == "" || strings.Contains(item.Name, name) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (a *facade) CreateApp(ctx context.Context, ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (result *AppResult, err error) {
+	defer observeCall(ns, "CreateApp", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	done, err := a.drain.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if err = a.limitAppWrites(ctx, ns, app.Name); err != nil {
+		return nil, err
+	}
+	ctx, span := startAppSpan(ctx, "CreateApp", ns, app.Name)
+	var res *specV1.Application
+	defer func() { endSpan(span, res, err) }()
+	// all the problems of the app are reported together
+	if err = a.validateApp(ns, app, configs); err != nil {
+		return nil, err
+	}
+	// the app created with the same idempotency key is returned instead of creating a second one
+	key := common.IdempotencyKeyFromContext(ctx)
+	if res, err = a.getIdempotentApp(ctx, ns, key, app.Name); err != nil || res != nil {
+		return a.existingAppResult(ns, res, err)
+	}
+
+	unlockApp, err := a.lockApp(ctx, ns, app.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockApp()
+	unlock, err := a.lockAppQuota(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var nodes []string
+	origin := *app
+	err = a.runTx(ctx, ns, "CreateApp", func(tx interface{}, undo *compensations) error {
+		// restore the app modified by the failed attempt
+		*app = origin
+		if err := a.checkAppQuota(ns, 1); err != nil {
+			return err
+		}
+		if err := a.createAppIdempotency(tx, ns, key, app.Name); err != nil {
+			return err
+		}
+		var err error
+		res, nodes, err = a.createApp(ctx, tx, ns, baseApp, app, configs, undo)
+		return err
+	})
+	if err != nil {
+		// the concurrent creation with the same key wins
+		if existing, e := a.getIdempotentApp(ctx, ns, key, origin.Name); e == nil && existing != nil {
+			res = existing
+			return a.existingAppResult(ns, existing, nil)
+		}
+		return nil, err
+	}
+	a.publishAppEvent(ctx, models.AppCreated, ns, res, nodes)
+	return &AppResult{App: res, Nodes: nodesOrEmpty(nodes)}, nil
+}
+
+// existingAppResult returns the result of the app created before, the nodes of which are read from the index
+func (a *facade) existingAppResult(ns string, app *specV1.Application, err error) (*AppResult, error) {
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := a.index.ListNodesByApp(ns, app.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &AppResult{App: app, Nodes: nodesOrEmpty(nodes)}, nil
+}
+
+func nodesOrEmpty(nodes []string) []string {
+	if nodes == nil {
+		return []string{}
+	}
+	return nodes
+}
+
+// CreateApps creates a batch of applications in a single transaction,
+// all of them are committed or rolled back together
+func (a *facade) CreateApps(ctx context.Context, ns string, reqs []*AppCreateRequest) (apps []*specV1.Application, err error) {
+	defer observeCall(ns, "CreateApps", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	done, err := a.drain.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if err = validAppCreateRequests(reqs); err != nil {
+		return nil, err
+	}
+	// all the problems of the apps are reported together
+	if err = a.validateApps(ns, reqs); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		names = append(names, req.App.Name)
+	}
+	if err = a.limitAppWrites(ctx, ns, names...); err != nil {
+		return nil, err
+	}
+
+	origins := make([]specV1.Application, len(reqs))
+	for i, req := range reqs {
+		origins[i] = *req.App
+	}
+	unlockApps, err := a.lockApps(ctx, ns, names)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockApps()
+	unlock, err := a.lockAppQuota(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	err = a.runTx(ctx, ns, "CreateApps", func(tx interface{}, undo *compensations) error {
+		if err := a.checkAppQuota(ns, len(reqs)); err != nil {
+			return err
+		}
+		apps = make([]*specV1.Application, 0, len(reqs))
+		for i, req := range reqs {
+			*req.App = origins[i]
+			if err := a.checkAppRefs(ns, req.App, req.Configs); err != nil {
+				return wrapAppError(origins[i].Name, err)
+			}
+			app, nodes, err := a.createApp(ctx, tx, ns, req.BaseApp, req.App, req.Configs, undo)
+			if err != nil {
+				return wrapAppError(origins[i].Name, err)
+			}
+			apps = append(apps, app)
+			undo.onCommit(func() {
+				a.publishAppEvent(ctx, models.AppCreated, ns, app, nodes)
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// createApp creates the app within the transaction, the writes out of the transaction
+// are registered to undo so that they can be compensated on rollback
+func (a *facade) createApp(ctx context.Context, tx interface{}, ns string, baseApp, app *specV1.Application, configs []specV1.Configuration, undo *compensations) (*specV1.Application, []string, error) {
+	delete(app.Labels, common.LabelCronPaused)
+	delete(app.Labels, common.LabelCronNextRuns)
+	delete(app.Labels, common.LabelActivateAt)
+	delete(app.Labels, common.LabelAppFrozen)
+	err := a.checkActivateAt(ctx, app)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = a.preCreateApp(ctx, tx, ns, app); err != nil {
+		return nil, nil, err
+	}
+	var cronApp *models.Cron
+	if app.CronStatus == specV1.CronWait {
+		if cronApp, err = newAppCron(app); err != nil {
+			return nil, nil, err
+		}
+		if _, err = a.selectorResolver(cronApp); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = traceStep(ctx, "UpsertConfigs", func() error {
+		return a.updateGenConfigsOfFunctionApp(tx, ns, configs)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cronApp != nil {
+		if err = a.putAppCron(ctx, tx, cronApp, false, undo); err != nil {
+			return nil, nil, err
+		}
+		app.Selector = ""
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if baseApp, err = a.resolveBaseApp(ns, baseApp); err != nil {
+		return nil, nil, err
+	}
+	app, err = a.app.CreateWithBase(tx, ns, app, baseApp)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = a.createAppAudit(ctx, tx, models.AppCreated, ns, app.Name, "", app.Version); err != nil {
+		return nil, nil, err
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var nodes []string
+	if at, ok := activateAtFromContext(ctx); ok {
+		// the app is deployed once it's activated
+		err = a.scheduleAppActivation(tx, ns, app, at)
+	} else {
+		err = traceStep(ctx, "RefreshIndex", func() (err error) {
+			nodes, err = a.updateNodeAndAppIndex(tx, ns, app, undo)
+			return
+		})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = a.writeAppOutbox(tx, models.AppCreated, ns, app, nodes); err != nil {
+		return nil, nil, err
+	}
+	return app, nodes, nil
+}
+
+func (a *facade) UpdateApp(ctx context.Context, ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (result *AppResult, err error) {
+	defer observeCall(ns, "UpdateApp", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	done, err := a.drain.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if err = a.limitAppWrites(ctx, ns, app.Name); err != nil {
+		return nil, err
+	}
+	ctx, span := startAppSpan(ctx, "UpdateApp", ns, app.Name)
+	var res *specV1.Application
+	defer func() { endSpan(span, res, err) }()
+	unlock, err := a.lockApp(ctx, ns, app.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	stored, err := a.appUnchanged(ctx, ns, oldApp, app, configs)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		if err = ctx.Err(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		res = stored
+		return &AppResult{App: stored, Nodes: []string{}}, nil
+	}
+	// the cron time of an app already waiting may pass before the cron fires
+	if err = validAppCron(app, oldApp == nil || oldApp.CronStatus != specV1.CronWait); err != nil {
+		return nil, err
+	}
+	var nodes []string
+	origin := *app
+	err = a.runTx(ctx, ns, "UpdateApp", func(tx interface{}, undo *compensations) error {
+		*app = origin
+		var err error
+		res, nodes, err = a.updateApp(ctx, tx, ns, oldApp, app, configs, undo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.publishAppEvent(ctx, models.AppUpdated, ns, res, nodes)
+	if a.history != nil {
+		a.pruneAppVersionsInBackground(ns, res.Name)
+	}
+	return &AppResult{App: res, Nodes: nodesOrEmpty(nodes)}, nil
+}
+
+// resolveBaseApp reads the base app given by its namespace, name and version from the store, so that the app is
+// merged with the base app stored instead of the one passed in. ErrInvalidBaseApp is returned if it's not found.
+func (a *facade) resolveBaseApp(ns string, baseApp *specV1.Application) (*specV1.Application, error) {
+	if baseApp == nil {
+		return nil, nil
+	}
+	if baseApp.Name == "" {
+		return nil, common.Error(common.ErrInvalidBaseApp, common.Field("error", "the name of the base app is empty"))
+	}
+	baseNs := baseApp.Namespace
+	if baseNs == "" {
+		baseNs = ns
+	}
+	res, err := a.app.Get(baseNs, baseApp.Name, baseApp.Version)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, common.Error(common.ErrInvalidBaseApp,
+				common.Field("name", baseApp.Name),
+				common.Field("error", "the base app is not found"))
+		}
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
+
+// appUnchanged checks the app to be updated is the same as the one stored, e.g. the spec applied again by
+// the reconcile loop, so that the update is a no-op and the app stored is returned, nil is returned otherwise.
+// The apps are compared without the version, the creation time and the labels only set on the app read.
+// The update of the function app with configs is never a no-op, neither is the update scheduled to activate
+// or dropping the pending activation of the app. The no-op is rejected as the update is if the app is frozen
+// or changed since the version the update is based on.
+func (a *facade) appUnchanged(ctx context.Context, ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+	if oldApp == nil || len(configs) > 0 {
+		return nil, nil
+	}
+	if _, ok := activateAtFromContext(ctx); ok {
+		return nil, nil
+	}
+	news, err := normalizedApp(app)
+	if err != nil {
+		return nil, nil
+	}
+	// the app changed from the old app of the caller is updated without reading the app stored
+	if olds, err := normalizedApp(oldApp); err != nil || !bytes.Equal(olds, news) {
+		return nil, nil
+	}
+	stored, err := a.app.Get(ns, app.Name, "")
+	if err != nil {
+		// the app missing is reported by the update
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if olds, err := normalizedApp(stored); err != nil || !bytes.Equal(olds, news) {
+		return nil, nil
+	}
+	if err = a.checkAppFrozen(nil, ns, app.Name); err != nil {
+		return nil, err
+	}
+	version := app.Version
+	if version == "" {
+		version = oldApp.Version
+	}
+	if err = appVersionConflict(stored, version); err != nil {
+		return nil, err
+	}
+	if a.activation == nil {
+		return stored, nil
+	}
+	if _, err = a.activation.GetAppActivation(ns, app.Name); err != nil {
+		if isNotFound(err) {
+			return stored, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return nil, nil
+}
+
+func normalizedApp(app *specV1.Application) ([]byte, error) {
+	res, err := copyApp(app)
+	if err != nil {
+		return nil, err
+	}
+	res.Namespace = ""
+	res.Version = ""
+	res.CreationTimestamp = time.Time{}
+	delete(res.Labels, common.LabelCronPaused)
+	delete(res.Labels, common.LabelCronNextRuns)
+	delete(res.Labels, common.LabelActivateAt)
+	delete(res.Labels, common.LabelAppFrozen)
+	if len(res.Labels) == 0 {
+		res.Labels = nil
+	}
+	return json.Marshal(res)
+}
+
+func (a *facade) updateApp(ctx context.Context, tx interface{}, ns string, oldApp, app *specV1.Application, configs []specV1.Configuration, undo *compensations) (*specV1.Application, []string, error) {
+	err := a.checkAppFrozen(tx, ns, app.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the app carrying no version is based on the old app read by the caller, and carries its version to the store
+	// so that the store rejects the write as well if the app is changed since then
+	if app.Version == "" {
+		app.Version = oldApp.Version
+	}
+	if err = a.checkAppVersion(tx, ns, app.Name, app.Version); err != nil {
+		return nil, nil, err
+	}
+
+	delete(app.Labels, common.LabelCronPaused)
+	delete(app.Labels, common.LabelCronNextRuns)
+	delete(app.Labels, common.LabelActivateAt)
+	delete(app.Labels, common.LabelAppFrozen)
+	if err = a.checkActivateAt(ctx, app); err != nil {
+		return nil, nil, err
+	}
+	if err = a.preUpdateApp(ctx, tx, ns, oldApp, app); err != nil {
+		return nil, nil, err
+	}
+	if err = a.checkUpdateImpact(ctx, tx, ns, oldApp, app); err != nil {
+		return nil, nil, err
+	}
+	var cronApp *models.Cron
+	if app.CronStatus == specV1.CronWait {
+		if cronApp, err = newAppCron(app); err != nil {
+			return nil, nil, err
+		}
+		if _, err = a.selectorResolver(cronApp); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err = a.checkAppRefs(ns, app, configs); err != nil {
+		return nil, nil, err
+	}
+	err = traceStep(ctx, "UpsertConfigs", func() error {
+		return a.updateGenConfigsOfFunctionApp(tx, ns, configs)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cronApp != nil {
+		if err = a.putAppCron(ctx, tx, cronApp, oldApp.CronStatus == specV1.CronWait, nil); err != nil {
+			return nil, nil, err
+		}
+		app.Selector = ""
+	}
+	if oldApp.CronStatus == specV1.CronWait && app.CronStatus != specV1.CronWait {
+		if err = a.dropAppCron(ctx, tx, ns, app.Name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	app, err = a.app.Update(tx, ns, app)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = a.createAppAudit(ctx, tx, models.AppUpdated, ns, app.Name, oldApp.Version, app.Version); err != nil {
+		return nil, nil, err
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	var nodes, removed []string
+	at, scheduled := activateAtFromContext(ctx)
+	if scheduled {
+		// the nodes keep the version they desire until the app is activated
+		err = a.scheduleAppActivation(tx, ns, app, at)
+	} else if err = a.dropAppActivation(tx, ns, app.Name); err == nil {
+		err = traceStep(ctx, "RefreshIndex", func() (err error) {
+			if oldApp != nil && oldApp.Selector != app.Selector {
+				removed, nodes, err = a.moveNodeAndAppIndex(tx, ns, oldApp, app, undo)
+				return
+			}
+			nodes, err = a.updateNodeAndAppIndex(tx, ns, app, undo)
+			return
+		})
+		if oldApp != nil {
+			olds := removed
+			if oldApp.Selector == app.Selector {
+				olds = nodes
+			}
+			a.undoNodeAndAppIndex(ns, oldApp, app, olds, nodes, undo)
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the generated function configs of the old version are kept for the nodes until the app is activated,
+	// they're reclaimed by ReclaimFunctionConfigs afterwards
+	if !scheduled {
+		if err = a.cleanGenConfigsOfFunctionApp(tx, configs, oldApp, app); err != nil && a.conf.StrictConfigClean {
+			return nil, nil, err
+		}
+	}
+	nodes = mergeNodes(nodes, removed)
+	if err = a.writeAppOutbox(tx, models.AppUpdated, ns, app, nodes); err != nil {
+		return nil, nil, err
+	}
+	return app, nodes, nil
+}
+
+// PreviewApp computes the nodes matched by the app and the generated configs to be written without
+// persisting anything, the transaction is only used for reading and always rolled back
+func (a *facade) PreviewApp(ctx context.Context, ns string, app *specV1.Application, configs []specV1.Configuration) (*AppPreview, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	tx, errTx := a.txFactory.BeginTx(ctx, a.txOptions("PreviewApp"))
+	if errTx != nil {
+		return nil, errTx
+	}
+	defer a.txFactory.Rollback(tx)
+
+	preview := &AppPreview{
+		Nodes:          []string{},
+		CreatedConfigs: []string{},
+		UpdatedConfigs: []string{},
+	}
+	for _, cfg := range configs {
+		_, err := a.config.Get(ns, cfg.Name, "")
+		if err == nil {
+			preview.UpdatedConfigs = append(preview.UpdatedConfigs, cfg.Name)
+			continue
+		}
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			preview.CreatedConfigs = append(preview.CreatedConfigs, cfg.Name)
+			continue
+		}
+		return nil, err
+	}
+
+	nodes, err := a.node.MatchNodes(tx, ns, app.Selector)
 	if err != nil {
+		return nil, err
+	}
+	preview.Nodes = append(preview.Nodes, nodes...)
+	return preview, nil
+}
+
+// RollbackApp re-applies the spec of the target version as a new version of the app. With the history of the
+// versions enabled, the target must be kept in the history and defaults to the latest pinned version if empty.
+// With RollbackConfigs enabled, the generated function configs are restored to their content at the target version
+func (a *facade) RollbackApp(ctx context.Context, ns, name, targetVersion string) (*specV1.Application, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	cur, err := a.app.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	// the versions pruned can't be rolled back to, and the latest pinned one is the default target
+	if a.history != nil {
+		if targetVersion == "" {
+			if targetVersion, err = a.latestPinnedAppVersion(ctx, ns, name); err != nil {
+				return nil, err
+			}
+		} else if _, err = a.findAppVersion(ns, name, targetVersion); err != nil {
+			return nil, err
+		}
+	}
+	app, err := a.app.Get(ns, name, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.CronStatus == specV1.CronWait {
+		// the selector of a cron app is kept by the cron entry instead of the app
+		cronApp, err := a.cron.GetCron(nil, name, ns)
+		if err == nil {
+			app.Selector = cronApp.Selector
+		} else if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			// the selector of the current cron app is lost along with its cron entry, so it can't be restored
+			if cur.CronStatus == specV1.CronWait {
+				return nil, common.Error(common.ErrResourceNotFound,
+					common.Field("type", "cron"),
+					common.Field("name", name),
+					common.Field("namespace", ns))
+			}
+			// the cron entry is restored with the selector kept by the target version, or else the one in use
+			cur.CronStatus = specV1.CronNotSet
+			if app.Selector == "" {
+				app.Selector = cur.Selector
+			}
+		} else {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	var configs []specV1.Configuration
+	if a.conf.RollbackConfigs {
+		if configs, err = a.historicalGenConfigs(ns, app); err != nil {
+			return nil, err
+		}
+	}
+
+	// based on the current version, so a new version is generated instead of reusing the old one
+	app.Version = cur.Version
+	app.CreationTimestamp = cur.CreationTimestamp
+	res, err := a.UpdateApp(ctx, ns, cur, app, configs)
+	if err != nil {
+		return nil, err
+	}
+	return res.App, nil
+}
+
+// historicalGenConfigs reads the generated function configs of the versions referenced by the volumes of the app,
+// which are upserted along with the app rolled back. ErrConfigHistoryMissing is returned if any of them isn't kept
+// by the config store, instead of deploying the app with the configs of another version.
+func (a *facade) historicalGenConfigs(ns string, app *specV1.Application) ([]specV1.Configuration, error) {
+	var configs []specV1.Configuration
+	seen := map[string]bool{}
+	for _, v := range app.Volumes {
+		if v.Config == nil || !a.isFunctionConfig(v.Config.Name) || seen[v.Config.Name] {
+			continue
+		}
+		seen[v.Config.Name] = true
+		missing := common.Error(common.ErrConfigHistoryMissing,
+			common.Field("name", v.Config.Name),
+			common.Field("version", v.Config.Version))
+		if v.Config.Version == "" {
+			return nil, missing
+		}
+		cfg, err := a.config.Get(ns, v.Config.Name, v.Config.Version)
+		if isNotFound(err) {
+			return nil, missing
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// the store without the history returns the latest version instead
+		if cfg.Version != v.Config.Version {
+			return nil, missing
+		}
+		configs = append(configs, *cfg)
+	}
+	return configs, nil
+}
+
+// DeleteApp deletes the app read by the caller, ErrResourceVersionConflict is returned if the app
+// is updated since then, so the node indexes of the newer version the caller never saw are kept
+func (a *facade) DeleteApp(ctx context.Context, ns, name string, app *specV1.Application) (err error) {
+	defer observeCall(ns, "DeleteApp", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	done, err := a.drain.enter()
+	if err != nil {
+		return err
+	}
+	defer done()
+	if err = a.limitAppWrites(ctx, ns, name); err != nil {
+		return err
+	}
+	ctx, span := startAppSpan(ctx, "DeleteApp", ns, name)
+	defer func() { endSpan(span, app, err) }()
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	var nodes []string
+	err = a.runTx(ctx, ns, "DeleteApp", func(tx interface{}, undo *compensations) error {
+		err := a.checkAppVersion(tx, ns, name, app.Version)
+		if err != nil {
+			return err
+		}
+		if err = a.preDeleteApp(ctx, tx, ns, app); err != nil {
+			return err
+		}
+		if a.conf.SoftDelete.Enabled {
+			nodes, err = a.softDeleteApp(ctx, tx, ns, name, app, undo)
+		} else {
+			nodes, err = a.deleteApp(ctx, tx, ns, name, app, undo)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	a.publishAppEvent(ctx, models.AppDeleted, ns, app, nodes)
+	// the secrets of the app soft deleted are kept for the restoration until it's purged
+	if !a.conf.SoftDelete.Enabled {
+		a.cleanGenSecretsOfApp(ns, app)
+	}
+	return nil
+}
+
+// DeleteApps deletes a batch of apps in a single transaction, all of them are deleted or rolled back together.
+// The generated function configs shared by the apps of the batch are cleaned after all of them are deleted.
+func (a *facade) DeleteApps(ctx context.Context, ns string, names []string) (err error) {
+	defer observeCall(ns, "DeleteApps", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	done, err := a.drain.enter()
+	if err != nil {
+		return err
+	}
+	defer done()
+	if err = a.limitAppWrites(ctx, ns, names...); err != nil {
+		return err
+	}
+	if err = validAppNames(names); err != nil {
+		return err
+	}
+	unlock, err := a.lockApps(ctx, ns, names)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = a.runTx(ctx, ns, "DeleteApps", func(tx interface{}, undo *compensations) error {
+		apps := make([]*specV1.Application, 0, len(names))
+		for _, name := range names {
+			app, err := a.app.Get(ns, name, "")
+			if err != nil {
+				return wrapAppError(name, err)
+			}
+			apps = append(apps, app)
+		}
+		shared := sharedGenConfigs(apps, a.isFunctionConfig)
+		for cfg, sharedBy := range shared {
+			a.log.Info("the config is shared by the apps to delete",
+				log.Any(common.KeyContextNamespace, ns),
+				log.Any("config", cfg),
+				log.Any("apps", sharedBy))
+		}
+		for _, app := range apps {
+			if err := a.preDeleteApp(ctx, tx, ns, app); err != nil {
+				return wrapAppError(app.Name, err)
+			}
+			var nodes []string
+			var err error
+			if a.conf.SoftDelete.Enabled {
+				nodes, err = a.softDeleteApp(ctx, tx, ns, app.Name, app, undo)
+			} else {
+				nodes, err = a.deleteApp(ctx, tx, ns, app.Name, app, undo)
+			}
+			if err != nil {
+				return wrapAppError(app.Name, err)
+			}
+			deleted := app
+			undo.onCommit(func() {
+				a.publishAppEvent(ctx, models.AppDeleted, ns, deleted, nodes)
+				if !a.conf.SoftDelete.Enabled {
+					a.cleanGenSecretsOfApp(ns, deleted)
+				}
+			})
+		}
+		if a.conf.SoftDelete.Enabled {
+			return nil
+		}
+		// the shared configs are kept by deleteApp since the other apps of the batch still reference them
+		if err := a.cleanSharedGenConfigs(tx, ns, names, shared); err != nil && a.conf.StrictConfigClean {
+			return err
+		}
+		return nil
+	})
+	return err
+}
+
+// sharedGenConfigs returns the generated function configs referenced by more than one of the apps,
+// with the names of the apps referencing them
+func sharedGenConfigs(apps []*specV1.Application, isFunctionConfig func(string) bool) map[string][]string {
+	refs := map[string][]string{}
+	for _, app := range apps {
+		seen := map[string]bool{}
+		for _, v := range app.Volumes {
+			if v.VolumeSource.Config == nil {
+				continue
+			}
+			name := v.VolumeSource.Config.Name
+			if seen[name] || !isFunctionConfig(name) {
+				continue
+			}
+			seen[name] = true
+			refs[name] = append(refs[name], app.Name)
+		}
+	}
+	shared := map[string][]string{}
+	for name, apps := range refs {
+		if len(apps) > 1 {
+			shared[name] = apps
+		}
+	}
+	return shared
+}
+
+// cleanSharedGenConfigs deletes the configs shared by the deleted apps unless referenced by the apps out of the batch
+func (a *facade) cleanSharedGenConfigs(tx interface{}, ns string, deleted []string, shared map[string][]string) error {
+	excluded := map[string]bool{}
+	for _, name := range deleted {
+		excluded[name] = true
+	}
+	names := make([]string, 0, len(shared))
+	for name := range shared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var cleanErr *CleanConfigsError
+	for _, name := range names {
+		// the index of the deleted apps may not be refreshed out of the transaction yet, so they're excluded
+		_, apps, err := a.config.IsReferenced(ns, name)
+		if err == nil {
+			kept := false
+			for _, app := range apps {
+				if !excluded[app] {
+					kept = true
+					break
+				}
+			}
+			if kept {
+				continue
+			}
+			err = a.deleteGenConfig(tx, ns, name)
+		}
+		if err != nil {
+			common.LogDirtyData(err,
+				log.Any("type", common.Config),
+				log.Any(common.KeyContextNamespace, ns),
+				log.Any("name", name))
+			configCleanFailures.Add(1)
+			if cleanErr == nil {
+				cleanErr = &CleanConfigsError{Namespace: ns, Errors: map[string]error{}}
+			}
+			cleanErr.Errors[name] = err
+		}
+	}
+	if cleanErr != nil {
+		return cleanErr
+	}
+	return nil
+}
+
+// deleteApp deletes the app and removes it from the nodes, the cron is deleted last
+// since it's written out of the transaction and can't be rolled back
+func (a *facade) deleteApp(ctx context.Context, tx interface{}, ns, name string, app *specV1.Application, undo *compensations) ([]string, error) {
+	err := a.checkAppFrozen(tx, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.app.Delete(tx, ns, name, ""); err != nil {
+		return nil, err
+	}
+	if err = a.createAppAudit(ctx, tx, models.AppDeleted, ns, name, app.Version, ""); err != nil {
+		return nil, err
+	}
+	if err = a.dropAppActivation(tx, ns, name); err != nil {
+		return nil, err
+	}
+
+	if err = ctx.Err(); err != nil {
 		return nil, errors.Trace(err)
 	}
+	//delete the app from node
+	var nodes []string
+	err = traceStep(ctx, "RefreshIndex", func() (err error) {
+		nodes, err = a.deleteNodeAndAppIndex(tx, ns, app, undo)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if app != nil && app.CronStatus == specV1.CronWait {
-		cronApp, err := a.cron.GetCron(name, ns)
-		if err == nil {
-			app.Selector = cronApp.Selector
-		}
+	if err = a.cleanGenConfigsOfFunctionApp(tx, nil, app, nil); err != nil && a.conf.StrictConfigClean {
+		return nil, err
 	}
-	return app, nil
+	if err = a.writeAppOutbox(tx, models.AppDeleted, ns, app, nodes); err != nil {
+		return nil, err
+	}
+
+	if err = a.deleteCronOfApp(ctx, tx, ns, app); err != nil {
+		return nil, err
+	}
+	return nodes, nil
 }
 
-func (a *facade) CreateApp(ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
-	tx, errTx := a.txFactory.BeginTx()
-	if errTx != nil {
-		return nil, errTx
+// deleteCronOfApp deletes the cron of the app waiting for cron
+func (a *facade) deleteCronOfApp(ctx context.Context, tx interface{}, ns string, app *specV1.Application) error {
+	if app.CronStatus != specV1.CronWait {
+		return nil
 	}
-	var err error
-	defer func() {
-		if p := recover(); p != nil {
-			a.txFactory.Rollback(tx)
-			panic(p)
-		} else if err != nil {
-			a.txFactory.Rollback(tx)
-		} else {
-			a.txFactory.Commit(tx)
+	return a.dropAppCron(ctx, tx, ns, app.Name)
+}
+
+// validAppName checks the name of the app to create is a DNS-1123 label, which is neither reserved
+// for the system apps nor for the generated function configs
+func (a *facade) validAppName(name string) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return common.Error(common.ErrInvalidAppName,
+			common.Field("name", name),
+			common.Field("rule", strings.Join(errs, "; ")))
+	}
+	prefix, programPrefix := a.functionConfigPrefixes()
+	for _, p := range append([]string{prefix, programPrefix}, a.conf.ReservedAppNamePrefixes...) {
+		if p != "" && strings.HasPrefix(name, p) {
+			return common.Error(common.ErrInvalidAppName,
+				common.Field("name", name),
+				common.Field("rule", fmt.Sprintf("the prefix (%s) is reserved", p)))
 		}
-	}()
-	err = a.updateGenConfigsOfFunctionApp(tx, ns, configs)
-	if err != nil {
-		return nil, err
 	}
+	return nil
+}
 
-	if app.CronStatus == specV1.CronWait {
-		err = a.cron.CreateCron(&models.Cron{
-			Name:      app.Name,
-			Namespace: app.Namespace,
-			Selector:  app.Selector,
-			CronTime:  app.CronTime,
-		})
-		if err != nil {
-			return nil, errors.Trace(err)
+// checkUpdateImpact compares the nodes matched by the old selector and the new one, ErrLargeImpact is returned
+// if the app is removed from more nodes than the impact guard allows, unless ctx is returned by WithForce.
+// The apps waiting for cron are skipped since they're removed from the nodes until the cron fires by design.
+func (a *facade) checkUpdateImpact(ctx context.Context, tx interface{}, ns string, oldApp, app *specV1.Application) error {
+	guard := a.conf.ImpactGuard
+	if guard.MaxRemovedNodes <= 0 && guard.MaxRemovedPercent <= 0 {
+		return nil
+	}
+	if oldApp == nil || oldApp.Selector == app.Selector || forceFromContext(ctx) ||
+		oldApp.CronStatus == specV1.CronWait || app.CronStatus == specV1.CronWait {
+		return nil
+	}
+	olds, err := a.node.MatchNodes(tx, ns, oldApp.Selector)
+	if err != nil || len(olds) == 0 {
+		return err
+	}
+	news, err := a.node.MatchNodes(tx, ns, app.Selector)
+	if err != nil {
+		return err
+	}
+	matched := map[string]bool{}
+	for _, n := range news {
+		matched[n] = true
+	}
+	removed := 0
+	for _, n := range olds {
+		if !matched[n] {
+			removed++
 		}
-		app.Selector = ""
 	}
+	if (guard.MaxRemovedNodes > 0 && removed > guard.MaxRemovedNodes) ||
+		(guard.MaxRemovedPercent > 0 && removed*100 > guard.MaxRemovedPercent*len(olds)) {
+		return common.Error(common.ErrLargeImpact,
+			common.Field("name", app.Name),
+			common.Field("removed", removed),
+			common.Field("total", len(olds)))
+	}
+	return nil
+}
 
-	app, err = a.app.CreateWithBase(tx, ns, app, baseApp)
+// checkAppVersion compares the version read by the caller with the current one read in the transaction, so that
+// only the first of the concurrent writes based on the same version passes
+func (a *facade) checkAppVersion(tx interface{}, ns, name, version string) error {
+	cur, err := a.app.GetForUpdate(tx, ns, name)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	return appVersionConflict(cur, version)
+}
 
-	err = a.UpdateNodeAndAppIndex(tx, ns, app)
-	if err != nil {
-		return nil, err
+// appVersionConflict returns ErrResourceVersionConflict if the current app isn't of the version given
+func appVersionConflict(cur *specV1.Application, version string) error {
+	if cur.Version != version {
+		return common.Error(common.ErrResourceVersionConflict,
+			common.Field("type", common.APP),
+			common.Field("name", cur.Name),
+			common.Field("version", cur.Version))
 	}
-	return app, nil
+	return nil
 }
 
-func (a *facade) UpdateApp(ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
-	var err error
-	tx, errTx := a.txFactory.BeginTx()
-	if errTx != nil {
-		return nil, errTx
+// undoNodeAndAppIndex registers the compensation restoring the node desires and the node indexes of the app
+// to the old version, since they may be written out of the transaction, e.g. the transaction of the default
+// factory is nil and the kube shadows ignore it. The old version is restored to the nodes matched by the old
+// selector (olds), and the app is removed from the nodes newly matched by the new selector (news).
+func (a *facade) undoNodeAndAppIndex(ns string, oldApp, app *specV1.Application, olds, news []string, undo *compensations) {
+	matched := map[string]bool{}
+	for _, n := range olds {
+		matched[n] = true
 	}
-	defer func() {
-		if p := recover(); p != nil {
-			a.txFactory.Rollback(tx)
-			panic(p)
-		} else if err != nil {
-			a.txFactory.Rollback(tx)
-		} else {
-			a.txFactory.Commit(tx)
+	var added []string
+	for _, n := range news {
+		if !matched[n] {
+			added = append(added, n)
+		}
+	}
+	if len(olds) == 0 && len(added) == 0 {
+		return
+	}
+	restore := *oldApp
+	undo.add(func() error {
+		if err := a.node.UpdateDesire(nil, ns, olds, &restore, service.RefreshNodeDesireByApp); err != nil {
+			return err
+		}
+		if err := a.node.UpdateDesire(nil, ns, added, &restore, service.DeleteNodeDesireByApp); err != nil {
+			return err
+		}
+		return a.refreshNodesIndexByApp(nil, ns, &restore, olds, nil)
+	})
+}
+
+// moveNodeAndAppIndex moves the app whose selector changes from the nodes matched by the old selector to the
+// nodes matched by the new one. The app is only removed from the old nodes it's no longer deployed to, so the nodes
+// matched by both selectors are written once instead of being removed and added again. The old nodes and the
+// nodes deployed to are returned
+func (a *facade) moveNodeAndAppIndex(tx interface{}, ns string, oldApp, app *specV1.Application, undo *compensations) ([]string, []string, error) {
+	if skipNodeIndex(oldApp) || skipNodeIndex(app) {
+		removed, err := a.deleteNodeAndAppIndex(tx, ns, oldApp, undo)
+		if err != nil {
+			return nil, nil, err
 		}
-	}()
+		nodes, err := a.updateNodeAndAppIndex(tx, ns, app, undo)
+		return removed, nodes, err
+	}
+	olds, err := a.node.MatchNodes(tx, ns, oldApp.Selector)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the nodes written are returned on failure too, so that they're compensated
+	nodes, err := a.updateNodeAndAppIndex(tx, ns, app, undo)
+	if err != nil {
+		return olds, nodes, err
+	}
+	if stale := subtract(olds, nodes); len(stale) > 0 {
+		err = a.node.UpdateDesire(tx, ns, stale, oldApp, service.DeleteNodeDesireByApp)
+	}
+	return olds, nodes, err
+}
+
+func (a *facade) DeleteNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application) error {
+	_, err := a.deleteNodeAndAppIndex(tx, namespace, app, nil)
+	return err
+}
 
-	err = a.updateGenConfigsOfFunctionApp(tx, ns, configs)
+// deleteNodeAndAppIndex removes the app from the nodes and returns the nodes
+func (a *facade) deleteNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application, undo *compensations) ([]string, error) {
+	if skipNodeIndex(app) {
+		return nil, nil
+	}
+	nodes, err := a.node.DeleteNodeAppVersion(tx, namespace, app)
 	if err != nil {
 		return nil, err
 	}
 
-	if app.CronStatus == specV1.CronWait {
-		err = a.cron.UpdateCron(&models.Cron{
-			Name:      app.Name,
-			Namespace: app.Namespace,
-			Selector:  app.Selector,
-			CronTime:  app.CronTime,
-		})
+	return nodes, a.refreshNodesIndexByApp(tx, namespace, app, make([]string, 0), undo)
+}
+
+// updateGenConfigsOfFunctionApp upserts the generated function configs within the transaction, no more config
+// is upserted after a failure, which is returned to roll back.
+// The configs are labeled with their checksums, by which the unchanged ones are skipped by Upsert.
+func (a *facade) updateGenConfigsOfFunctionApp(tx interface{}, namespace string, configs []specV1.Configuration) error {
+	// all the configs are checked before any of them is written
+	for i := range configs {
+		if err := a.checkConfigSize(&configs[i]); err != nil {
+			return err
+		}
+	}
+	configs = a.withConfigChecksums(configs)
+	configs, optionals := splitOptionalConfigs(configs)
+	for i := range configs {
+		cfg, err := a.externalizeConfigBlobs(tx, namespace, &configs[i])
 		if err != nil {
-			return nil, errors.Trace(err)
+			return err
 		}
-		app.Selector = ""
+		configs[i] = *cfg
+	}
+	if err := a.upsertGenConfigs(tx, namespace, configs); err != nil {
+		return err
 	}
-	if oldApp.CronStatus == specV1.CronWait && app.CronStatus == specV1.CronNotSet {
-		err = a.cron.DeleteCron(app.Name, ns)
+	// the optional configs are written one by one since each one is isolated by the savepoint
+	for i := range optionals {
+		cfg := &optionals[i]
+		skipped, err := a.runOptional(tx, optionalConfigSavepoint, func() error {
+			cfg, err := a.externalizeConfigBlobs(tx, namespace, cfg)
+			if err != nil {
+				return err
+			}
+			_, err = a.config.Upsert(tx, namespace, cfg)
+			return err
+		})
 		if err != nil {
-			return nil, errors.Trace(err)
+			return err
+		}
+		if skipped != nil {
+			a.log.Warn("the optional config is skipped",
+				log.Any(common.KeyContextNamespace, namespace),
+				log.Any("name", cfg.Name),
+				log.Error(skipped))
 		}
 	}
+	return nil
+}
 
-	app, err = a.app.Update(tx, ns, app)
-	if err != nil {
-		return nil, err
+const optionalConfigSavepoint = "baetyl_optional_config"
+
+// splitOptionalConfigs splits the configs labeled optional from the required ones
+func splitOptionalConfigs(configs []specV1.Configuration) (required, optionals []specV1.Configuration) {
+	for _, cfg := range configs {
+		if cfg.Labels[common.LabelConfigOptional] == "true" {
+			optionals = append(optionals, cfg)
+		} else {
+			required = append(required, cfg)
+		}
 	}
+	return
+}
 
-	if oldApp != nil && oldApp.Selector != app.Selector {
-		// delete old nodes
-		if err = a.DeleteNodeAndAppIndex(tx, ns, oldApp); err != nil {
-			return nil, err
+// upsertGenConfigs upserts the generated configs one by one, since the writes of a transaction share its connection
+func (a *facade) upsertGenConfigs(tx interface{}, namespace string, configs []specV1.Configuration) error {
+	for i := range configs {
+		if _, err := a.config.Upsert(tx, namespace, &configs[i]); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	// update nodes
-	if err = a.UpdateNodeAndAppIndex(tx, ns, app); err != nil {
+func (a *facade) UpdateNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application) error {
+	_, err := a.updateNodeAndAppIndex(tx, namespace, app, nil)
+	return err
+}
+
+// updateNodeAndAppIndex deploys the app to the nodes matched by its selector and returns the nodes
+func (a *facade) updateNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application, undo *compensations) ([]string, error) {
+	if skipNodeIndex(app) {
+		return nil, nil
+	}
+	selector, err := normalizeSelector(app.Selector)
+	if err != nil {
 		return nil, err
 	}
-
-	a.cleanGenConfigsOfFunctionApp(tx, configs, oldApp)
-	return app, nil
+	if selector != app.Selector {
+		cp := *app
+		cp.Selector = selector
+		app = &cp
+	}
+	percent, err := canaryPercent(app)
+	if err != nil {
+		return nil, err
+	}
+	if percent > 0 {
+		return a.updateCanaryNodeAndAppIndex(tx, namespace, app, percent, undo)
+	}
+	maxNodes, err := rolloutMaxNodes(app)
+	if err != nil {
+		return nil, err
+	}
+	if maxNodes > 0 {
+		return a.updateStaggeredNodeAndAppIndex(tx, namespace, app, maxNodes, undo)
+	}
+	nodes, err := a.node.UpdateNodeAppVersion(tx, namespace, app)
+	if err != nil {
+		return nil, err
+	}
+	return nodes, a.refreshNodesIndexByApp(tx, namespace, app, nodes, undo)
 }
 
-func (a *facade) DeleteApp(ns, name string, app *specV1.Application) error {
+// refreshNodesIndexByApp refreshes the nodes indexed by the app, along with the labels of the app if IndexAppLabels is enabled.
+// The refresh is recorded once the transaction of undo is committed, or right away without undo
+func (a *facade) refreshNodesIndexByApp(tx interface{}, namespace string, app *specV1.Application, nodes []string, undo *compensations) error {
 	var err error
-	tx, errTx := a.txFactory.BeginTx()
-	if errTx != nil {
-		return errTx
+	if a.conf.IndexAppLabels {
+		err = a.index.RefreshNodesIndexByAppWithLabels(tx, namespace, app.Name, app.Labels, nodes)
+	} else {
+		err = a.index.RefreshNodesIndexByApp(tx, namespace, app.Name, nodes)
 	}
-	defer func() {
-		if p := recover(); p != nil {
-			a.txFactory.Rollback(tx)
-			panic(p)
-		} else if err != nil {
-			a.txFactory.Rollback(tx)
-		} else {
-			a.txFactory.Commit(tx)
-		}
-	}()
-
-	if app.CronStatus == specV1.CronWait {
-		err = a.cron.DeleteCron(name, ns)
-		if err != nil {
-			return errors.Trace(err)
-		}
+	if err != nil {
+		return err
+	}
+	name, n := app.Name, len(nodes)
+	if undo == nil {
+		service.RecordNodesIndexRefresh(namespace, name, n)
+	} else {
+		undo.onCommit(func() { service.RecordNodesIndexRefresh(namespace, name, n) })
 	}
+	return nil
+}
 
-	if err = a.app.Delete(tx, ns, name, ""); err != nil {
+// skipNodeIndex returns whether the node bindings of the app are managed externally
+func skipNodeIndex(app *specV1.Application) bool {
+	return app != nil && app.Labels[common.LabelSkipNodeIndex] == "true"
+}
+
+// checkAppRefs checks the configs and the secrets referenced by the volumes of the app exist
+func (a *facade) checkAppRefs(ns string, app *specV1.Application, configs []specV1.Configuration) error {
+	if err := a.checkConfigRefs(ns, app, configs); err != nil {
 		return err
 	}
+	return a.checkSecretRefs(ns, app)
+}
 
-	//delete the app from node
-	if err = a.DeleteNodeAndAppIndex(tx, ns, app); err != nil {
-		return err
+// checkConfigRefs checks the configs referenced by the volumes of the app are either in configs to be upserted
+// or in the store, ErrMissingConfigRef listing the dangling references is returned otherwise
+func (a *facade) checkConfigRefs(ns string, app *specV1.Application, configs []specV1.Configuration) error {
+	upserted := map[string]bool{}
+	for _, cfg := range configs {
+		upserted[cfg.Name] = true
+	}
+	checked := map[string]bool{}
+	var missing []string
+	for _, v := range app.Volumes {
+		if v.VolumeSource.Config == nil {
+			continue
+		}
+		name := v.VolumeSource.Config.Name
+		if upserted[name] || checked[name] {
+			continue
+		}
+		checked[name] = true
+		_, err := a.config.Get(ns, name, "")
+		if isNotFound(err) {
+			missing = append(missing, name)
+		} else if err != nil {
+			return errors.Trace(err)
+		}
 	}
+	if len(missing) > 0 {
+		return common.Error(common.ErrMissingConfigRef,
+			common.Field("name", app.Name),
+			common.Field("configs", strings.Join(missing, ",")))
+	}
+	return nil
+}
 
-	a.cleanGenConfigsOfFunctionApp(tx, nil, app)
+// checkSecretRefs checks the secrets referenced by the volumes of the app are in the store,
+// ErrMissingSecretRef listing the dangling references is returned otherwise
+func (a *facade) checkSecretRefs(ns string, app *specV1.Application) error {
+	checked := map[string]bool{}
+	var missing []string
+	for _, v := range app.Volumes {
+		if v.VolumeSource.Secret == nil || checked[v.VolumeSource.Secret.Name] {
+			continue
+		}
+		name := v.VolumeSource.Secret.Name
+		checked[name] = true
+		_, err := a.secret.Get(ns, name, "")
+		if isNotFound(err) {
+			missing = append(missing, name)
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if len(missing) > 0 {
+		return common.Error(common.ErrMissingSecretRef,
+			common.Field("name", app.Name),
+			common.Field("secrets", strings.Join(missing, ",")))
+	}
 	return nil
 }
 
-func (a *facade) DeleteNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application) error {
-	_, err := a.node.DeleteNodeAppVersion(tx, namespace, app)
-	if err != nil {
-		return err
+// cleanGenSecretsOfApp deletes the secrets generated for the app, which are labeled with the name of the app,
+// after the deletion of the app is committed since the secrets aren't deleted within the transaction.
+// The failures are logged as dirty data only.
+func (a *facade) cleanGenSecretsOfApp(ns string, app *specV1.Application) {
+	for _, name := range a.listGenSecretsOfApp(ns, app) {
+		if err := a.secret.Delete(ns, name); err != nil && !isNotFound(err) {
+			common.LogDirtyData(err,
+				log.Any("type", common.Secret),
+				log.Any(common.KeyContextNamespace, ns),
+				log.Any("name", name))
+		}
 	}
+}
 
-	return a.index.RefreshNodesIndexByApp(tx, namespace, app.Name, make([]string, 0))
+// listGenSecretsOfApp lists the secrets mounted by the app which are generated for it
+func (a *facade) listGenSecretsOfApp(ns string, app *specV1.Application) []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, v := range app.Volumes {
+		if v.VolumeSource.Secret == nil || seen[v.VolumeSource.Secret.Name] {
+			continue
+		}
+		name := v.VolumeSource.Secret.Name
+		seen[name] = true
+		secret, err := a.secret.Get(ns, name, "")
+		if err != nil {
+			if !isNotFound(err) {
+				a.log.Warn("failed to get the secret mounted by the app",
+					log.Any(common.KeyContextNamespace, ns), log.Any("app", app.Name), log.Any("name", name), log.Error(err))
+			}
+			continue
+		}
+		if secret.Labels[common.LabelAppName] == app.Name {
+			res = append(res, name)
+		}
+	}
+	return res
 }
 
-func (a *facade) updateGenConfigsOfFunctionApp(tx interface{}, namespace string, configs []specV1.Configuration) error {
-	for _, cfg := range configs {
-		_, err := a.config.Upsert(tx, namespace, &cfg)
+// cleanGenConfigsOfFunctionApp deletes the generated function configs of oldApp
+// which are neither regenerated in configs nor referenced by app (if not nil) or any other app,
+// all the configs are tried and the failures are returned as a *CleanConfigsError
+func (a *facade) cleanGenConfigsOfFunctionApp(tx interface{}, configs []specV1.Configuration, oldApp, app *specV1.Application) error {
+	var cleanErr *CleanConfigsError
+	for _, c := range a.listGenConfigsToClean(configs, oldApp, app) {
+		name, err := c.name, c.err
+		if err == nil && len(c.sharedBy) > 0 {
+			continue
+		}
+		if err == nil {
+			err = a.deleteGenConfig(tx, oldApp.Namespace, name)
+		}
 		if err != nil {
-			return err
+			common.LogDirtyData(err,
+				log.Any("type", common.Config),
+				log.Any(common.KeyContextNamespace, oldApp.Namespace),
+				log.Any("name", name))
+			configCleanFailures.Add(1)
+			if cleanErr == nil {
+				cleanErr = &CleanConfigsError{Namespace: oldApp.Namespace, Errors: map[string]error{}}
+			}
+			cleanErr.Errors[name] = err
 		}
 	}
+	if cleanErr != nil {
+		return cleanErr
+	}
 	return nil
 }
 
-func (a *facade) UpdateNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application) error {
-	nodes, err := a.node.UpdateNodeAppVersion(tx, namespace, app)
-	if err != nil {
-		return err
+// genConfigToClean a generated function config of the app to be cleaned, which is kept if shared by other apps
+type genConfigToClean struct {
+	name     string
+	sharedBy []string
+	err      error
+}
+
+// listGenConfigsToClean lists the generated function configs of oldApp which are neither regenerated in configs
+// nor referenced by app (if not nil), with the other apps referencing them or the failure of looking them up
+func (a *facade) listGenConfigsToClean(configs []specV1.Configuration, oldApp, app *specV1.Application) []genConfigToClean {
+	var res []genConfigToClean
+	for _, name := range a.dryRunCleanGenConfigsOfFunctionApp(configs, oldApp, app) {
+		// the index of oldApp may not be refreshed out of the transaction yet, so it's excluded
+		apps, err := a.listOtherAppsReferencingConfig(oldApp.Namespace, name, oldApp.Name)
+		res = append(res, genConfigToClean{name: name, sharedBy: apps, err: err})
 	}
-	return a.index.RefreshNodesIndexByApp(tx, namespace, app.Name, nodes)
+	return res
 }
 
-func (a *facade) cleanGenConfigsOfFunctionApp(tx interface{}, configs []specV1.Configuration, oldApp *specV1.Application) {
+// dryRunCleanGenConfigsOfFunctionApp lists the configs cleanGenConfigsOfFunctionApp would delete without deleting
+// them, which are the generated function configs of oldApp neither regenerated in configs nor referenced by app
+// (if not nil). It's computed from the prefixes and the references only, the configs still shared by the other
+// apps, which are kept by the cleanup, aren't excluded.
+func (a *facade) dryRunCleanGenConfigsOfFunctionApp(configs []specV1.Configuration, oldApp, app *specV1.Application) []string {
 	m := map[string]bool{}
 	for _, cfg := range configs {
 		m[cfg.Name] = true
 	}
+	if app != nil {
+		for _, v := range app.Volumes {
+			if v.VolumeSource.Config != nil {
+				m[v.VolumeSource.Config.Name] = true
+			}
+		}
+	}
 
+	var res []string
 	for _, v := range oldApp.Volumes {
 		if v.VolumeSource.Config == nil {
 			continue
 		}
-		if _, ok := m[v.VolumeSource.Config.Name]; !ok && (strings.HasPrefix(v.VolumeSource.Config.Name, FunctionConfigPrefix) ||
-			strings.HasPrefix(v.VolumeSource.Config.Name, FunctionProgramConfigPrefix)) {
-			err := a.config.Delete(tx, oldApp.Namespace, v.VolumeSource.Config.Name)
-			if err != nil {
-				common.LogDirtyData(err,
-					log.Any("type", common.Config),
-					log.Any(common.KeyContextNamespace, oldApp.Namespace),
-					log.Any("name", v.VolumeSource.Config.Name))
-				continue
-			}
+		name := v.VolumeSource.Config.Name
+		if _, ok := m[name]; ok || !a.isFunctionConfig(name) {
+			continue
+		}
+		res = append(res, name)
+	}
+	return res
+}
+
+// listOtherAppsReferencingConfig lists the apps referencing the config except the app
+func (a *facade) listOtherAppsReferencingConfig(ns, config, app string) ([]string, error) {
+	_, apps, err := a.config.IsReferenced(ns, config)
+	if err != nil {
+		return nil, err
+	}
+	var others []string
+	for _, name := range apps {
+		if name != app {
+			others = append(others, name)
+		}
+	}
+	return others, nil
+}
+
+// CleanConfigsError aggregates the failures of deleting the generated function configs
+type CleanConfigsError struct {
+	Namespace string
+	Errors    map[string]error
+}
+
+// Code the code shared by the failures of the configs
+func (e *CleanConfigsError) Code() string {
+	return sharedCode(e.Errors)
+}
+
+func (e *CleanConfigsError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", name, e.Errors[name].Error()))
+	}
+	return fmt.Sprintf("failed to clean configs of namespace (%s): %s", e.Namespace, strings.Join(msgs, "; "))
+}
+
+func validAppCreateRequests(reqs []*AppCreateRequest) error {
+	names := map[string]bool{}
+	for i, req := range reqs {
+		if req == nil || req.App == nil {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the application of request (%d) is empty", i)))
+		}
+		if req.App.Name == "" {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the name of application in request (%d) is empty", i)))
+		}
+		if names[req.App.Name] {
+			return common.Error(common.ErrAppNameConflict,
+				common.Field("where", "batch"),
+				common.Field("name", req.App.Name))
+		}
+		names[req.App.Name] = true
+	}
+	return nil
+}
+
+func validAppNames(names []string) error {
+	seen := map[string]bool{}
+	for i, name := range names {
+		if name == "" {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the name of application (%d) is empty", i)))
+		}
+		if seen[name] {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the application (%s) is duplicated", name)))
 		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// wrapAppError prefixes the error message with the application name, keeping its code.
+// The error without any code is coded ErrUnknown, since its category is lost along with the error.
+func wrapAppError(name string, err error) error {
+	msg := fmt.Sprintf("app (%s): %s", name, err.Error())
+	return errors.CodeError(string(common.CodeOf(err)), msg)
+}
+
+// ResolveSelector returns the nodes of the namespace currently matched by the selector without any write,
+// the nodes are matched in the same way as the app is deployed
+func (a *facade) ResolveSelector(ctx context.Context, ns, selector string) ([]string, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	selector, err := normalizeSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := a.node.MatchNodes(nil, ns, selector)
+	if err != nil {
+		return nil, err
+	}
+	if nodes == nil {
+		nodes = []string{}
+	}
+	return nodes, nil
+}
+
+// normalizeSelector parses the selector string as the serialized form of the structured label selector
+// and formats it back, so the string selectors and the structured ones target the nodes in the same way
+func normalizeSelector(selector string) (string, error) {
+	ls, err := models.ParseLabelSelector(selector)
+	if err != nil {
+		return "", common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("invalid selector (%s): %s", selector, err.Error())))
+	}
+	res, err := models.FormatLabelSelector(ls)
+	if err != nil {
+		return "", common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("invalid selector (%s): %s", selector, err.Error())))
 	}
+	return res, nil
 }
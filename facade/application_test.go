@@ -1,15 +1,23 @@
 package facade
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
 )
 
 func TestCreateApplication(t *testing.T) {
@@ -23,7 +31,7 @@ func TestCreateApplication(t *testing.T) {
 		cron:      mAppFacade.sCron,
 		txFactory: mAppFacade.txFactory,
 	}
-	mAppFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
 
 	config := &specV1.Configuration{}
@@ -34,28 +42,89 @@ func TestCreateApplication(t *testing.T) {
 	ns := "baetyl-cloud"
 
 	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, unknownErr)
-	_, err := appFacade.CreateApp(ns, app, app, configs)
+	_, err := appFacade.CreateApp(context.Background(), ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(app, nil).AnyTimes()
 	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err = appFacade.CreateApp(ns, app, app, configs)
+	_, err = appFacade.CreateApp(context.Background(), ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), gomock.Any()).Return(app, nil).AnyTimes()
 	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err = appFacade.CreateApp(ns, app, app, configs)
+	_, err = appFacade.CreateApp(context.Background(), ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	app.CronStatus = specV1.CronWait
+	app.CronTime = time.Now().Add(time.Hour)
 	mAppFacade.sCron.EXPECT().CreateCron(gomock.Any()).Return(nil)
 	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
 	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil)
 	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), gomock.Any()).Return(nil)
-	_, err = appFacade.CreateApp(ns, app, app, configs)
+	_, err = appFacade.CreateApp(context.Background(), ns, app, app, configs)
 	assert.NoError(t, err)
 }
 
+func TestCreateApplicationInvalidBase(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:       mAppFacade.sApp,
+		txFactory: mAppFacade.txFactory,
+	}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Name: "abc"}
+
+	_, err := appFacade.CreateApp(context.Background(), ns, &specV1.Application{}, app, nil)
+	assert.Equal(t, common.ErrInvalidBaseApp, err.(errors.Coder).Code())
+
+	// the base app is read from its own namespace
+	mAppFacade.sApp.EXPECT().Get("other", "base", "12").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	_, err = appFacade.CreateApp(context.Background(), ns, &specV1.Application{Namespace: "other", Name: "base", Version: "12"}, app, nil)
+	assert.Equal(t, common.ErrInvalidBaseApp, err.(errors.Coder).Code())
+	assert.Contains(t, err.Error(), "not found")
+
+	mAppFacade.sApp.EXPECT().Get(ns, "base", "").Return(nil, unknownErr).Times(1)
+	_, err = appFacade.CreateApp(context.Background(), ns, &specV1.Application{Name: "base"}, app, nil)
+	assert.Error(t, err)
+
+	// the app is merged with the base app stored instead of the one passed in
+	stored := &specV1.Application{Namespace: ns, Name: "base", Services: []specV1.Service{{Name: "s1"}}}
+	mAppFacade.sApp.EXPECT().Get(ns, "base", "").Return(stored, nil).Times(1)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, stored).Return(nil, unknownErr).Times(1)
+	_, err = appFacade.CreateApp(context.Background(), ns, &specV1.Application{Name: "base"}, app, nil)
+	assert.Error(t, err)
+}
+
+func TestCreateApplicationInvalidName(t *testing.T) {
+	appFacade := &facade{
+		conf: config.Facade{
+			FunctionConfigPrefix:    "fn-config",
+			ReservedAppNamePrefixes: []string{"baetyl-"},
+		},
+	}
+	ns := "baetyl-cloud"
+	for _, name := range []string{"", "App", "a_b", "-abc", strings.Repeat("a", 64), "baetyl-core", "fn-config-abc", "baetyl-function-program-config-abc"} {
+		_, err := appFacade.CreateApp(context.Background(), ns, nil, &specV1.Application{Name: name}, nil)
+		assert.Equal(t, common.ErrAppValidation, err.(errors.Coder).Code(), name)
+		verr := err.(*ValidationError)
+		assert.Len(t, verr.Errors, 1, name)
+		assert.Equal(t, "name", verr.Errors[0].Field, name)
+		assert.Equal(t, common.ErrInvalidAppName, verr.Errors[0].Code, name)
+	}
+	_, err := appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{{App: &specV1.Application{Name: "baetyl-core"}}})
+	assert.Equal(t, common.ErrAppValidation, err.(errors.Coder).Code())
+	verr := err.(*ValidationError)
+	assert.Len(t, verr.Errors, 1)
+	assert.Equal(t, "apps[baetyl-core].name", verr.Errors[0].Field)
+	assert.Equal(t, common.ErrInvalidAppName, verr.Errors[0].Code)
+	assert.Contains(t, err.Error(), "the prefix (baetyl-) is reserved")
+}
+
 func TestDeleteApplication(t *testing.T) {
 	mAppFacade, mCtl := InitMockEnvironment(t)
 	defer mCtl.Finish()
@@ -126,26 +195,129 @@ func TestDeleteApplication(t *testing.T) {
 			},
 		},
 	}
-	mAppFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(&specV1.Application{Namespace: ns, Name: "abc"}, nil).AnyTimes()
 	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
 	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
 
 	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(unknownErr).Times(1)
-	err := appFacade.DeleteApp(ns, app.Name, app)
+	err := appFacade.DeleteApp(context.Background(), ns, app.Name, app)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(nil).AnyTimes()
 	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, unknownErr).Times(1)
-	err = appFacade.DeleteApp(ns, app.Name, app)
+	err = appFacade.DeleteApp(context.Background(), ns, app.Name, app)
 	assert.Error(t, err, unknownErr)
 
 	app.CronStatus = specV1.CronWait
+	app.CronTime = time.Now().Add(time.Hour)
 	mAppFacade.sCron.EXPECT().DeleteCron(app.Name, ns).Return(nil)
 	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil).Times(1)
 	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, app.Name, gomock.Any()).Return(nil).AnyTimes()
+	// the index of the app itself is not refreshed yet
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, gomock.Any()).Return(true, []string{app.Name}, nil).Times(2)
 	mAppFacade.sConfig.EXPECT().Delete(nil, ns, gomock.Any()).Return(unknownErr)
-	err = appFacade.DeleteApp(ns, app.Name, app)
+	failures := testutil.ToFloat64(configCleanFailures)
+	err = appFacade.DeleteApp(context.Background(), ns, app.Name, app)
+	assert.NoError(t, err)
+	assert.Equal(t, failures+1, testutil.ToFloat64(configCleanFailures))
+
+	// strict, the cron is kept since the deletion fails
+	appFacade.conf.StrictConfigClean = true
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil).Times(1)
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, "baetyl-function-config-app-service-xxxxxxxxx").Return(unknownErr)
+	err = appFacade.DeleteApp(context.Background(), ns, app.Name, app)
+	assert.Error(t, err)
+	cleanErr, ok := err.(*CleanConfigsError)
+	assert.True(t, ok)
+	assert.Equal(t, ns, cleanErr.Namespace)
+	assert.Equal(t, unknownErr, cleanErr.Errors["baetyl-function-config-app-service-xxxxxxxxx"])
+	assert.Equal(t, failures+2, testutil.ToFloat64(configCleanFailures))
+
+	// the config shared with another app is kept
+	mAppFacade.sCron.EXPECT().DeleteCron(app.Name, ns).Return(nil)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil).Times(1)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "baetyl-function-config-app-service-xxxxxxxxx").Return(true, []string{app.Name, "other"}, nil)
+	err = appFacade.DeleteApp(context.Background(), ns, app.Name, app)
 	assert.NoError(t, err)
+
+	// the config is kept if the references are unknown
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil).Times(1)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "baetyl-function-config-app-service-xxxxxxxxx").Return(false, nil, unknownErr)
+	err = appFacade.DeleteApp(context.Background(), ns, app.Name, app)
+	cleanErr, ok = err.(*CleanConfigsError)
+	assert.True(t, ok)
+	assert.Equal(t, unknownErr, cleanErr.Errors["baetyl-function-config-app-service-xxxxxxxxx"])
+}
+
+func TestDeleteCronApplicationFailed(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		recycle:   mAppFacade.sRecycle,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{
+		Namespace:  ns,
+		Name:       "abc",
+		CronStatus: specV1.CronWait,
+		CronTime:   time.Now().Add(time.Hour),
+	}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(&specV1.Application{Namespace: ns, Name: "abc"}, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	// the cron survives the failed deletion of the app, DeleteCron is never called
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(unknownErr)
+	err := appFacade.DeleteApp(context.Background(), ns, app.Name, app)
+	assert.Equal(t, unknownErr, err)
+
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(nil)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, unknownErr)
+	err = appFacade.DeleteApp(context.Background(), ns, app.Name, app)
+	assert.Equal(t, unknownErr, err)
+
+	// soft delete
+	appFacade.conf.SoftDelete.Enabled = true
+	mAppFacade.sCron.EXPECT().GetCron(nil, app.Name, ns).Return(&models.Cron{Selector: "a=b"}, nil)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(unknownErr)
+	err = appFacade.DeleteApp(context.Background(), ns, app.Name, app)
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestDeleteApplicationVersionConflict(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Name: "abc", Namespace: ns, Version: "1"}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(2)
+
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, app.Name).Return(nil, unknownErr).Times(1)
+	err := appFacade.DeleteApp(context.Background(), ns, app.Name, app)
+	assert.Equal(t, unknownErr, err)
+
+	// the app is updated since read by the caller, nothing is deleted
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, app.Name).Return(&specV1.Application{Name: "abc", Version: "2"}, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	err = appFacade.DeleteApp(context.Background(), ns, app.Name, app)
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(errors.Coder).Code())
 }
 
 func TestUpdateApplication(t *testing.T) {
@@ -212,32 +384,35 @@ func TestUpdateApplication(t *testing.T) {
 	configs := []specV1.Configuration{*config}
 	ns := "baetyl-cloud"
 
-	mAppFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(&specV1.Application{Namespace: ns, Name: "abc"}, nil).AnyTimes()
 	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
 	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
 
+	mAppFacade.sConfig.EXPECT().Get(ns, "func1", "").Return(&specV1.Configuration{Namespace: ns, Name: "func1"}, nil).AnyTimes()
 	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err := appFacade.UpdateApp(ns, app, app, configs)
+	_, err := appFacade.UpdateApp(context.Background(), ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, nil).AnyTimes()
 	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(nil, unknownErr).Times(1)
-	_, err = appFacade.UpdateApp(ns, app, app, configs)
+	_, err = appFacade.UpdateApp(context.Background(), ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(app, nil).AnyTimes()
 	oldApp := &specV1.Application{
 		Selector: "test",
 	}
-	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, oldApp).Return(nil, unknownErr).Times(1)
-	_, err = appFacade.UpdateApp(ns, oldApp, app, configs)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "test").Return(nil, unknownErr).Times(1)
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err = appFacade.UpdateApp(ns, app, app, configs)
+	_, err = appFacade.UpdateApp(context.Background(), ns, app, app, configs)
 	assert.Error(t, err, unknownErr)
 
 	app.CronStatus = specV1.CronWait
+	app.CronTime = time.Now().Add(time.Hour)
 	appNew := &specV1.Application{
 		Namespace:  "baetyl-cloud",
 		Name:       "abc",
@@ -246,13 +421,14 @@ func TestUpdateApplication(t *testing.T) {
 	}
 	mAppFacade.sCron.EXPECT().UpdateCron(gomock.Any()).Return(nil).AnyTimes()
 	mAppFacade.sCron.EXPECT().DeleteCron(app.Name, ns).Return(unknownErr).Times(1)
-	_, err = appFacade.UpdateApp(ns, app, appNew, configs)
+	_, err = appFacade.UpdateApp(context.Background(), ns, app, appNew, configs)
 	assert.Error(t, err, unknownErr)
 
 	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).AnyTimes()
 	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, app.Name, gomock.Any()).Return(nil).AnyTimes()
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, gomock.Any()).Return(false, nil, nil).AnyTimes()
 	mAppFacade.sConfig.EXPECT().Delete(nil, ns, gomock.Any()).Return(nil).AnyTimes()
-	_, err = appFacade.UpdateApp(ns, app, app, configs)
+	_, err = appFacade.UpdateApp(context.Background(), ns, app, app, configs)
 	assert.NoError(t, err)
 }
 
@@ -260,19 +436,21 @@ func TestGetApplication(t *testing.T) {
 	mAppFacade, mCtl := InitMockEnvironment(t)
 	defer mCtl.Finish()
 	appFacade := &facade{
-		app:  mAppFacade.sApp,
-		cron: mAppFacade.sCron,
+		app:       mAppFacade.sApp,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
 	}
 	name, ns := "baetyl", "cloud"
 	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(nil, unknownErr).Times(1)
-	_, err := appFacade.GetApp(ns, name, "")
+	_, err := appFacade.GetApp(context.Background(), ns, name, "")
 	assert.Error(t, err, unknownErr)
 
 	app := &specV1.Application{
 		Namespace:  ns,
 		Name:       name,
 		CronStatus: specV1.CronWait,
-		CronTime:   time.Now(),
+		CronTime:   time.Now().Add(time.Hour),
 	}
 	cronApp := &models.Cron{
 		Namespace: ns,
@@ -281,7 +459,1261 @@ func TestGetApplication(t *testing.T) {
 		CronTime:  time.Now(),
 	}
 	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil).Times(1)
-	mAppFacade.sCron.EXPECT().GetCron(name, ns).Return(cronApp, nil).Times(1)
-	_, err = appFacade.GetApp(ns, name, "")
+	mAppFacade.sCron.EXPECT().GetCron(nil, name, ns).Return(cronApp, nil).Times(1)
+	_, err = appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+
+	// the app is returned without selector if the cron is not found
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, CronStatus: specV1.CronWait}, nil).Times(1)
+	mAppFacade.sCron.EXPECT().GetCron(nil, name, ns).Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	res, err := appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", res.Selector)
+
+	// the failure of the cron store is returned
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, CronStatus: specV1.CronWait}, nil).Times(1)
+	mAppFacade.sCron.EXPECT().GetCron(nil, name, ns).Return(nil, unknownErr).Times(1)
+	_, err = appFacade.GetApp(context.Background(), ns, name, "")
+	assert.Equal(t, unknownErr, errors.Cause(err))
+
+	// the cron is read within the read-only transaction
+	tx := "tx"
+	ctx := WithReadOnlyTx(context.Background())
+	mAppFacade.txFactory.EXPECT().BeginReadOnlyTx(gomock.Any()).Return(tx, nil).Times(2)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil).Times(1)
+	mAppFacade.sCron.EXPECT().GetCron(tx, name, ns).Return(cronApp, nil).Times(1)
+	mAppFacade.txFactory.EXPECT().Commit(tx).Times(1)
+	_, err = appFacade.GetApp(ctx, ns, name, "")
+	assert.NoError(t, err)
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(nil, unknownErr).Times(1)
+	mAppFacade.txFactory.EXPECT().Rollback(tx).Times(1)
+	_, err = appFacade.GetApp(ctx, ns, name, "")
+	assert.Error(t, err)
+
+	mAppFacade.txFactory.EXPECT().BeginReadOnlyTx(gomock.Any()).Return(nil, unknownErr).Times(1)
+	_, err = appFacade.GetApp(ctx, ns, name, "")
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestCreateApplications(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app1 := &specV1.Application{Name: "app1", Namespace: ns}
+	app2 := &specV1.Application{Name: "app2", Namespace: ns}
+	configs := []specV1.Configuration{{Name: "cfg"}}
+
+	_, err := appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{{App: app1}, {App: nil}})
+	assert.Error(t, err)
+	_, err = appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{{App: app1}, {App: app1}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "app1")
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	// the second app fails, the whole batch is rolled back
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(1)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app1, nil).Return(app1, nil).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app1).Return([]string{"node1"}, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, app1.Name, []string{"node1"}).Return(nil).Times(1)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app2, nil).Return(nil, common.Error(common.ErrAppNameConflict)).Times(1)
+	_, err = appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{{App: app1, Configs: configs}, {App: app2, Configs: configs}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "app2")
+	assert.Equal(t, common.ErrAppNameConflict, err.(errors.Coder).Code())
+
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().Times(1)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).DoAndReturn(
+		func(_ interface{}, _ string, app, _ *specV1.Application) (*specV1.Application, error) {
+			return app, nil
+		}).Times(2)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	apps, err := appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{{App: app1}, {App: app2}})
+	assert.NoError(t, err)
+	assert.Len(t, apps, 2)
+}
+
+func TestDeleteApplications(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	shared, own := "baetyl-function-config-shared", "baetyl-function-config-own"
+	volume := func(cfg string) specV1.Volume {
+		return specV1.Volume{Name: cfg, VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: cfg}}}
+	}
+	app1 := &specV1.Application{Name: "app1", Namespace: ns, Volumes: []specV1.Volume{volume(shared), volume(own)}}
+	app2 := &specV1.Application{Name: "app2", Namespace: ns, Volumes: []specV1.Volume{volume(shared), volume("cfg")}}
+
+	err := appFacade.DeleteApps(context.Background(), ns, []string{"app1", ""})
+	assert.Error(t, err)
+	err = appFacade.DeleteApps(context.Background(), ns, []string{"app1", "app1"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "app1")
+
+	assert.Equal(t, map[string][]string{shared: {"app1", "app2"}}, sharedGenConfigs([]*specV1.Application{app1, app2}, appFacade.isFunctionConfig))
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().Get(ns, "app1", "").Return(app1, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().Get(ns, "app2", "").Return(app2, nil).AnyTimes()
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, own).Return(true, []string{"app1"}, nil).AnyTimes()
+
+	// the second app fails, the whole batch is rolled back
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(1)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "app1", "").Return(nil)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, shared).Return(true, []string{"app1", "app2"}, nil)
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, own).Return(nil)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "app2", "").Return(unknownErr)
+	err = appFacade.DeleteApps(context.Background(), ns, []string{"app1", "app2"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "app2")
+
+	// the shared config is deleted after both apps are deleted
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().Times(2)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, gomock.Any(), "").Return(nil).Times(2)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, shared).Return(true, []string{"app1", "app2"}, nil).Times(3)
+	gomock.InOrder(
+		mAppFacade.sConfig.EXPECT().Delete(nil, ns, own).Return(nil),
+		mAppFacade.sConfig.EXPECT().Delete(nil, ns, shared).Return(nil),
+	)
+	err = appFacade.DeleteApps(context.Background(), ns, []string{"app1", "app2"})
+	assert.NoError(t, err)
+
+	// the shared config is kept if referenced by the app out of the batch
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, gomock.Any(), "").Return(nil).Times(2)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, shared).Return(true, []string{"app1", "app2", "app3"}, nil).Times(3)
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, own).Return(nil)
+	err = appFacade.DeleteApps(context.Background(), ns, []string{"app1", "app2"})
+	assert.NoError(t, err)
+}
+
+func TestUpdateApplicationVersionConflict(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	oldApp := &specV1.Application{Name: "abc", Namespace: ns, Version: "1"}
+	app := &specV1.Application{Name: "abc", Namespace: ns, Version: "1", Description: "changed"}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(2)
+
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, app.Name).Return(nil, unknownErr).Times(1)
+	_, err := appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.Equal(t, unknownErr, err)
+
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, app.Name).Return(&specV1.Application{Name: "abc", Version: "2"}, nil).Times(1)
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(errors.Coder).Code())
+
+	// the app carrying no version is checked against the version of the old app read by the caller
+	noVersion := &specV1.Application{Name: "abc", Namespace: ns, Description: "changed"}
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, app.Name).Return(&specV1.Application{Name: "abc", Version: "2"}, nil)
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, noVersion, nil)
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(errors.Coder).Code())
+
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().Times(1)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, app.Name).Return(&specV1.Application{Name: "abc", Version: "1"}, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(&specV1.Application{Name: "abc", Namespace: ns, Version: "2"}, nil).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, app.Name, gomock.Any()).Return(nil).Times(1)
+	res, err := appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", res.App.Version)
+	assert.Equal(t, []string{}, res.Nodes)
+}
+
+func TestUpdateApplicationUnchanged(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:       mAppFacade.sNode,
+		app:        mAppFacade.sApp,
+		index:      mAppFacade.sIndex,
+		activation: mAppFacade.sActivate,
+		freeze:     mAppFacade.sFreeze,
+		locker:     mAppFacade.sLocker,
+		txFactory:  mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	oldApp := &specV1.Application{
+		Name:              "abc",
+		Namespace:         ns,
+		Version:           "1",
+		CreationTimestamp: time.Now(),
+		Selector:          "a=b",
+		Labels:            map[string]string{common.LabelAppFrozen: "true", "x": "y"},
+		Services:          []specV1.Service{{Name: "s", Image: "i"}},
+	}
+	stored := &specV1.Application{
+		Name:              "abc",
+		Namespace:         ns,
+		Version:           "1",
+		CreationTimestamp: oldApp.CreationTimestamp,
+		Selector:          "a=b",
+		Labels:            map[string]string{"x": "y"},
+		Services:          []specV1.Service{{Name: "s", Image: "i"}},
+	}
+	// the same spec without the version, the timestamp and the labels set on read
+	app := &specV1.Application{
+		Name:     "abc",
+		Selector: "a=b",
+		Labels:   map[string]string{"x": "y"},
+		Services: []specV1.Service{{Name: "s", Image: "i"}},
+	}
+	notFound := common.Error(common.ErrResourceNotFound)
+	lock := appLockPrefix + ns + "/abc"
+
+	// no transaction is began, the app stored is compared and returned under the lock of the app
+	gomock.InOrder(
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v1", nil),
+		mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(stored, nil),
+		mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(nil, notFound),
+		mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "abc").Return(nil, notFound),
+		mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v1"),
+	)
+	res, err := appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, stored, res.App)
+	assert.Equal(t, []string{}, res.Nodes)
+
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v1", nil).AnyTimes()
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v1").AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(stored, nil)
+	mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(nil, notFound)
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "abc").Return(nil, notFound)
+	_, err = appFacade.UpdateApp(ctx, ns, oldApp, app, nil)
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(stored, nil)
+	mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(nil, notFound)
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "abc").Return(nil, unknownErr)
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.Equal(t, unknownErr, errors.Cause(err))
+
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, unknownErr)
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.Equal(t, unknownErr, errors.Cause(err))
+
+	// the no-op is rejected if the app is frozen
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(stored, nil)
+	mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(&models.AppFreeze{Namespace: ns, Name: "abc", FrozenBy: "admin"}, nil)
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.Equal(t, common.ErrAppFrozen, err.(errors.Coder).Code())
+
+	// the no-op is rejected if the app stored is changed since the version the update is based on
+	changedStored := *stored
+	changedStored.Version = "2"
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(&changedStored, nil).Times(2)
+	mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(nil, notFound).Times(2)
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(errors.Coder).Code())
+	withVersion := *app
+	withVersion.Version = "1"
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, &withVersion, nil)
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(errors.Coder).Code())
+
+	// the update dropping the pending activation, scheduled to activate or with the configs isn't a no-op
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(stored, nil)
+	mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(nil, notFound)
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "abc").Return(&models.AppActivation{Namespace: ns, Name: "abc", Version: "1"}, nil)
+	unchanged, err := appFacade.appUnchanged(context.Background(), ns, oldApp, app, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, unchanged)
+	unchanged, err = appFacade.appUnchanged(WithActivateAt(context.Background(), time.Now().Add(time.Hour)), ns, oldApp, app, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, unchanged)
+	unchanged, err = appFacade.appUnchanged(context.Background(), ns, oldApp, app, []specV1.Configuration{{Name: "cfg"}})
+	assert.NoError(t, err)
+	assert.Nil(t, unchanged)
+
+	// the app stored is changed though the old app of the caller is the same, or the app is missing
+	changedStored = *stored
+	changedStored.Selector = "a=c"
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(&changedStored, nil)
+	unchanged, err = appFacade.appUnchanged(context.Background(), ns, oldApp, app, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, unchanged)
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, notFound)
+	unchanged, err = appFacade.appUnchanged(context.Background(), ns, oldApp, app, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, unchanged)
+
+	// changed from the old app of the caller
+	changed := *app
+	changed.Labels = map[string]string{"x": "z"}
+	unchanged, err = appFacade.appUnchanged(context.Background(), ns, oldApp, &changed, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, unchanged)
+	changed = *app
+	changed.Services = []specV1.Service{{Name: "s", Image: "j"}}
+	unchanged, err = appFacade.appUnchanged(context.Background(), ns, oldApp, &changed, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, unchanged)
+}
+
+func TestUpdateApplicationLargeImpact(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{ImpactGuard: config.ImpactGuard{MaxRemovedNodes: 2, MaxRemovedPercent: 50}},
+	}
+	ns := "baetyl-cloud"
+	oldApp := &specV1.Application{Name: "abc", Namespace: ns, Selector: "a=a"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(&specV1.Application{Namespace: ns, Name: "abc"}, nil).AnyTimes()
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=a").Return([]string{"n1", "n2", "n3", "n4"}, nil).AnyTimes()
+
+	// 3 of the 4 nodes are removed
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(2)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1", "n5"}, nil)
+	_, err := appFacade.UpdateApp(context.Background(), ns, oldApp, &specV1.Application{Name: "abc", Selector: "a=b"}, nil)
+	assert.Equal(t, common.ErrLargeImpact, err.(errors.Coder).Code())
+
+	// 2 of the 4 nodes are removed, the percent is checked
+	appFacade.conf.ImpactGuard.MaxRemovedPercent = 25
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1", "n2"}, nil)
+	_, err = appFacade.UpdateApp(context.Background(), ns, oldApp, &specV1.Application{Name: "abc", Selector: "a=b"}, nil)
+	assert.Equal(t, common.ErrLargeImpact, err.(errors.Coder).Code())
+
+	// the update is confirmed by force
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(&specV1.Application{Name: "abc", Selector: "a=b", Version: "2"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n2", "n3", "n4"}, oldApp, gomock.Any()).Return(nil)
+	_, err = appFacade.UpdateApp(WithForce(context.Background()), ns, oldApp, &specV1.Application{Name: "abc", Selector: "a=b"}, nil)
+	assert.NoError(t, err)
+}
+
+func TestUpdateApplicationIndexCompensation(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	oldApp := &specV1.Application{Name: "abc", Namespace: ns, Version: "1", Selector: "a=a"}
+	app := &specV1.Application{Name: "abc", Namespace: ns, Version: "1", Selector: "a=b"}
+
+	// the node desires written out of the transaction, which is nil as the default transaction factory begins
+	versions := map[string]map[string]string{"n1": {"abc": "1"}, "n2": {"abc": "1"}, "n3": {}}
+	updateDesire := func(nodes []string, app *specV1.Application, f func(*models.Shadow, *specV1.Application)) {
+		for _, n := range nodes {
+			shadow := &models.Shadow{Desire: specV1.Desire{}}
+			var infos []specV1.AppInfo
+			for name, version := range versions[n] {
+				infos = append(infos, specV1.AppInfo{Name: name, Version: version})
+			}
+			shadow.Desire.SetAppInfos(false, infos)
+			f(shadow, app)
+			versions[n] = map[string]string{}
+			for _, info := range shadow.Desire.AppInfos(false) {
+				versions[n][info.Name] = info.Version
+			}
+		}
+	}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(oldApp, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(&specV1.Application{Name: "abc", Namespace: ns, Version: "2", Selector: "a=b"}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=a").Return([]string{"n1", "n2"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) ([]string, error) {
+			updateDesire([]string{"n2", "n3"}, app, service.RefreshNodeDesireByApp)
+			return []string{"n2", "n3"}, nil
+		})
+	// the index refresh fails after the desires are written
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n2", "n3"}).Return(unknownErr)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, nodes []string, app *specV1.Application, f func(*models.Shadow, *specV1.Application)) error {
+			updateDesire(nodes, app, f)
+			return nil
+		}).Times(2)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1", "n2"}).Return(nil)
+
+	_, err := appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, map[string]map[string]string{"n1": {"abc": "1"}, "n2": {"abc": "1"}, "n3": {}}, versions)
+}
+
+func TestUpdateApplicationSelectorChange(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	oldApp := &specV1.Application{Name: "abc", Namespace: ns, Version: "1", Selector: "a=a"}
+	app := &specV1.Application{Name: "abc", Namespace: ns, Version: "1", Selector: "a=b"}
+	updated := &specV1.Application{Name: "abc", Namespace: ns, Version: "2", Selector: "a=b"}
+
+	// the node desires and the times they're written
+	versions := map[string]map[string]string{"n1": {"abc": "1"}, "n2": {"abc": "1"}, "n3": {}}
+	writes := map[string]int{}
+	updateDesire := func(nodes []string, app *specV1.Application, f func(*models.Shadow, *specV1.Application)) {
+		for _, n := range nodes {
+			shadow := &models.Shadow{Desire: specV1.Desire{}}
+			var infos []specV1.AppInfo
+			for name, version := range versions[n] {
+				infos = append(infos, specV1.AppInfo{Name: name, Version: version})
+			}
+			shadow.Desire.SetAppInfos(false, infos)
+			f(shadow, app)
+			versions[n] = map[string]string{}
+			for _, info := range shadow.Desire.AppInfos(false) {
+				versions[n][info.Name] = info.Version
+			}
+			writes[n]++
+		}
+	}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(oldApp, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(updated, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=a").Return([]string{"n1", "n2"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, updated).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) ([]string, error) {
+			updateDesire([]string{"n2", "n3"}, app, service.RefreshNodeDesireByApp)
+			return []string{"n2", "n3"}, nil
+		})
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n2", "n3"}).Return(nil)
+	// only the node no longer matched is removed
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, oldApp, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, nodes []string, app *specV1.Application, f func(*models.Shadow, *specV1.Application)) error {
+			updateDesire(nodes, app, f)
+			return nil
+		})
+
+	res, err := appFacade.UpdateApp(context.Background(), ns, oldApp, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2", "n3"}, res.Nodes)
+	assert.Equal(t, map[string]map[string]string{"n1": {}, "n2": {"abc": "2"}, "n3": {"abc": "2"}}, versions)
+	// the node matched by both selectors is written once
+	assert.Equal(t, map[string]int{"n1": 1, "n2": 1, "n3": 1}, writes)
+}
+
+func TestRollbackApplication(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns, name := "baetyl-cloud", "abc"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(nil, unknownErr).Times(1)
+	_, err := appFacade.RollbackApp(context.Background(), ns, name, "1")
+	assert.Equal(t, unknownErr, err)
+
+	cur := func() *specV1.Application {
+		return &specV1.Application{Name: name, Namespace: ns, Version: "3", Selector: "a=b"}
+	}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(cur(), nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(nil, unknownErr).Times(1)
+	_, err = appFacade.RollbackApp(context.Background(), ns, name, "1")
+	assert.Equal(t, unknownErr, err)
+
+	// plain rollback re-applies the target spec based on the current version
+	target := &specV1.Application{Name: name, Namespace: ns, Version: "1", Selector: "a=b", Description: "v1"}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(cur(), nil).Times(1)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, name).Return(cur(), nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(target, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, "3", app.Version)
+			assert.Equal(t, "v1", app.Description)
+			app.Version = "4"
+			return app, nil
+		}).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, gomock.Any()).Return(nil).Times(1)
+	res, err := appFacade.RollbackApp(context.Background(), ns, name, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", res.Version)
+
+	// rollback to a cron version restores the cron entry
+	cronTarget := &specV1.Application{Name: name, Namespace: ns, Version: "2", CronStatus: specV1.CronWait, CronTime: time.Now().Add(time.Hour)}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(cur(), nil).Times(1)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, name).Return(cur(), nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "2").Return(cronTarget, nil).Times(1)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	mAppFacade.sCron.EXPECT().CreateCron(gomock.Any()).DoAndReturn(func(c *models.Cron) error {
+		assert.Equal(t, "a=b", c.Selector)
+		return nil
+	}).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, "", app.Selector)
+			return app, nil
+		}).Times(1)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nil, nil).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, gomock.Any()).Return(nil).Times(1)
+	_, err = appFacade.RollbackApp(context.Background(), ns, name, "2")
+	assert.NoError(t, err)
+
+	// the selector of a cron app can't be restored without its cron entry
+	cronCur := &specV1.Application{Name: name, Namespace: ns, Version: "3", CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(cronCur, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "2").Return(cronTarget, nil).Times(1)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	_, err = appFacade.RollbackApp(context.Background(), ns, name, "2")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+}
+
+func TestRollbackApplicationConfigs(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{RollbackConfigs: true},
+	}
+	ns, name, cfgName := "baetyl-cloud", "abc", "baetyl-function-config-abc"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	withConfig := func(version, cfgVersion string) *specV1.Application {
+		return &specV1.Application{Name: name, Namespace: ns, Version: version, Selector: "a=b",
+			Volumes: []specV1.Volume{{
+				Name:         "v1",
+				VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: cfgName, Version: cfgVersion}},
+			}}}
+	}
+
+	// the store without the history returns the latest version of the config
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(withConfig("3", "7"), nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(withConfig("1", "5"), nil).Times(1)
+	mAppFacade.sConfig.EXPECT().Get(ns, cfgName, "5").Return(&specV1.Configuration{Name: cfgName, Version: "7"}, nil).Times(1)
+	_, err := appFacade.RollbackApp(context.Background(), ns, name, "1")
+	assert.Equal(t, common.ErrConfigHistoryMissing, err.(errors.Coder).Code())
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(withConfig("3", "7"), nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(withConfig("1", "5"), nil).Times(1)
+	mAppFacade.sConfig.EXPECT().Get(ns, cfgName, "5").Return(nil, common.Error(common.ErrResourceNotFound)).Times(1)
+	_, err = appFacade.RollbackApp(context.Background(), ns, name, "1")
+	assert.Equal(t, common.ErrConfigHistoryMissing, err.(errors.Coder).Code())
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(withConfig("3", "7"), nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(withConfig("1", "5"), nil).Times(1)
+	mAppFacade.sConfig.EXPECT().Get(ns, cfgName, "5").Return(nil, unknownErr).Times(1)
+	_, err = appFacade.RollbackApp(context.Background(), ns, name, "1")
+	assert.Error(t, err)
+
+	// the config is restored to its content at the target version along with the app
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(withConfig("3", "7"), nil).Times(1)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, name).Return(withConfig("3", "7"), nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(withConfig("1", "5"), nil).Times(1)
+	mAppFacade.sConfig.EXPECT().Get(ns, cfgName, "5").Return(&specV1.Configuration{
+		Name: cfgName, Namespace: ns, Version: "5", Data: map[string]string{"k": "v5"}}, nil).Times(1)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+			assert.Equal(t, cfgName, cfg.Name)
+			assert.Equal(t, "v5", cfg.Data["k"])
+			return cfg, nil
+		}).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, "3", app.Version)
+			app.Version = "4"
+			return app, nil
+		}).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, gomock.Any()).Return(nil).Times(1)
+	res, err := appFacade.RollbackApp(context.Background(), ns, name, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", res.Version)
+}
+
+func TestPreviewApplication(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		config:    mAppFacade.sConfig,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Name: "abc", Namespace: ns, Selector: "a=b"}
+	configs := []specV1.Configuration{{Name: "c1"}, {Name: "c2"}}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, unknownErr).Times(1)
+	_, err := appFacade.PreviewApp(context.Background(), ns, app, configs)
+	assert.Equal(t, unknownErr, err)
+
+	// always rolled back, never committed
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(3)
+
+	mAppFacade.sConfig.EXPECT().Get(ns, "c1", "").Return(nil, unknownErr).Times(1)
+	_, err = appFacade.PreviewApp(context.Background(), ns, app, configs)
+	assert.Equal(t, unknownErr, err)
+
+	mAppFacade.sConfig.EXPECT().Get(ns, "c1", "").Return(&specV1.Configuration{Name: "c1"}, nil).Times(2)
+	mAppFacade.sConfig.EXPECT().Get(ns, "c2", "").Return(nil, common.Error(common.ErrResourceNotFound)).Times(2)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nil, unknownErr).Times(1)
+	_, err = appFacade.PreviewApp(context.Background(), ns, app, configs)
+	assert.Equal(t, unknownErr, err)
+
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1", "n2"}, nil).Times(1)
+	preview, err := appFacade.PreviewApp(context.Background(), ns, app, configs)
+	assert.NoError(t, err)
+	assert.Equal(t, &AppPreview{
+		Nodes:          []string{"n1", "n2"},
+		CreatedConfigs: []string{"c2"},
+		UpdatedConfigs: []string{"c1"},
+	}, preview)
+}
+
+func TestCreateApplicationCronCompensation(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{
+		Name:       "abc",
+		Namespace:  ns,
+		Selector:   "a=b",
+		CronStatus: specV1.CronWait,
+		CronTime:   time.Now().Add(time.Hour),
+	}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	// the cron created before the failure is deleted on rollback
+	mAppFacade.sCron.EXPECT().CreateCron(gomock.Any()).Return(nil).Times(1)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(nil, unknownErr).Times(1)
+	mAppFacade.sCron.EXPECT().DeleteCron(app.Name, ns).Return(nil).Times(1)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.Equal(t, unknownErr, err)
+
+	// the cron is not deleted if it failed to be created
+	app.Selector = "a=b"
+	mAppFacade.sCron.EXPECT().CreateCron(gomock.Any()).Return(unknownErr).Times(1)
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.Error(t, err)
+
+	// a compensation failure does not hide the original error
+	app.Selector = "a=b"
+	mAppFacade.sCron.EXPECT().CreateCron(gomock.Any()).Return(nil).Times(1)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(app, nil).Times(1)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return(nil, unknownErr).Times(1)
+	mAppFacade.sCron.EXPECT().DeleteCron(app.Name, ns).Return(errors.New("delete failed")).Times(1)
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestApplicationContextCanceled(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Name: "abc", Namespace: ns}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := appFacade.GetApp(ctx, ns, app.Name, "")
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+
+	// the transaction is rolled back and no store is written after the context is canceled
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(3)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(&specV1.Application{Namespace: ns, Name: "abc"}, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(3)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
+	_, err = appFacade.CreateApp(ctx, ns, nil, app, []specV1.Configuration{{Name: "cfg"}})
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+
+	_, err = appFacade.UpdateApp(ctx, ns, app, &specV1.Application{Name: "abc", Namespace: ns, Selector: "a=b"}, nil)
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, app.Name, "").Return(nil).Times(1)
+	err = appFacade.DeleteApp(ctx, ns, app.Name, app)
+	assert.Equal(t, context.Canceled, errors.Cause(err))
+}
+
+func TestListApplications(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:  mAppFacade.sApp,
+		cron: mAppFacade.sCron,
+	}
+	ns := "baetyl-cloud"
+	list := &models.ApplicationList{
+		Items: []models.AppItem{
+			{Name: "app-a", Selector: "a=a"},
+			{Name: "app-b", CronStatus: specV1.CronWait},
+			{Name: "app-c", Selector: "c=c"},
+			{Name: "other"},
+		},
+		ListOptions: &models.ListOptions{Continue: "next"},
+	}
+	cronTime := time.Now()
+	// the name filter applies to the page listed by the cursor
+	opt := &models.ListOptions{LabelSelector: "x=y", Limit: 4, Filter: models.Filter{Name: "app"}}
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{LabelSelector: "x=y", Limit: 4}).Return(list, nil)
+	// the total counts the matched apps of all the pages
+	all := &models.ApplicationList{Items: append(append([]models.AppItem{}, list.Items...), models.AppItem{Name: "app-d"}, models.AppItem{Name: "other-e"})}
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{LabelSelector: "x=y"}).Return(all, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), "app-b", ns).Return(&models.Cron{Selector: "b=b", CronTime: cronTime}, nil)
+	res, err := appFacade.ListApps(context.Background(), ns, opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, res.Total)
+	assert.Equal(t, "next", res.Continue)
+	assert.Len(t, res.Items, 3)
+	assert.Equal(t, "app-a", res.Items[0].Name)
+	assert.Equal(t, "b=b", res.Items[1].Selector)
+	assert.Equal(t, cronTime, res.Items[1].CronTime)
+
+	// the next page is listed by the cursor, the last page has no cursor
+	opt = &models.ListOptions{Limit: 4, Continue: "next"}
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{Limit: 4, Continue: "next"}).Return(&models.ApplicationList{
+		Items: []models.AppItem{{Name: "app-d"}}, ListOptions: &models.ListOptions{}}, nil)
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{}).Return(all, nil)
+	res, err = appFacade.ListApps(context.Background(), ns, opt)
+	assert.NoError(t, err)
+	assert.Empty(t, res.Continue)
+	assert.Len(t, res.Items, 1)
+	assert.Equal(t, 6, res.Total)
+
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{Limit: 4}).Return(list, nil)
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{}).Return(nil, unknownErr)
+	_, err = appFacade.ListApps(context.Background(), ns, &models.ListOptions{Limit: 4})
+	assert.Error(t, err)
+
+	// the apps are selected by the annotations carried by the labels
+	annotated := &models.ApplicationList{
+		Items: []models.AppItem{
+			{Name: "app-a", Labels: map[string]string{"x": "y", common.LabelAnnotationPrefix + "team": "infra"}},
+		},
+	}
+	opt = &models.ListOptions{LabelSelector: "x=y", AnnotationSelector: "team=infra"}
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{LabelSelector: "x=y," + common.LabelAnnotationPrefix + "team=infra"}).Return(annotated, nil)
+	res, err = appFacade.ListApps(context.Background(), ns, opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, map[string]string{"team": "infra"}, res.Items[0].Annotations)
+
+	_, err = appFacade.ListApps(context.Background(), ns, &models.ListOptions{AnnotationSelector: "team in ("})
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(nil, unknownErr)
+	_, err = appFacade.ListApps(context.Background(), ns, nil)
+	assert.Error(t, err)
+}
+
+func TestResolveSelector(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node: mAppFacade.sNode,
+	}
+	ns := "baetyl-cloud"
+
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1", "n2"}, nil)
+	nodes, err := appFacade.ResolveSelector(context.Background(), ns, "a=b")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2"}, nodes)
+
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "").Return(nil, nil)
+	nodes, err = appFacade.ResolveSelector(context.Background(), ns, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, nodes)
+
+	// the label selector is matched in its normalized form
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b,env in (prod,staging),x notin (y)").Return([]string{"n1"}, nil)
+	nodes, err = appFacade.ResolveSelector(context.Background(), ns, "env in (staging,prod), x!=y, a=b")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, nodes)
+
+	_, err = appFacade.ResolveSelector(context.Background(), ns, "a in (")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+	_, err = appFacade.ResolveSelector(context.Background(), ns, "a>1")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=c").Return(nil, unknownErr)
+	_, err = appFacade.ResolveSelector(context.Background(), ns, "a=c")
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestUpdateNodeAndAppIndexWithLabelSelector(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:  mAppFacade.sNode,
+		index: mAppFacade.sIndex,
+	}
+	ns := "baetyl-cloud"
+
+	app := &specV1.Application{Name: "app", Selector: "env in (staging,prod),!deprecated"}
+	expected := &specV1.Application{Name: "app", Selector: "!deprecated,env in (prod,staging)"}
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, expected).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "app", []string{"n1"}).Return(nil)
+	assert.NoError(t, appFacade.UpdateNodeAndAppIndex(nil, ns, app))
+	assert.Equal(t, "env in (staging,prod),!deprecated", app.Selector)
+
+	app.Selector = "env in ("
+	err := appFacade.UpdateNodeAndAppIndex(nil, ns, app)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
+
+func TestUpdateGenConfigsOfFunctionApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		config: mAppFacade.sConfig,
+	}
+	ns := "baetyl-cloud"
+	configs := make([]specV1.Configuration, 3)
+	for i := range configs {
+		configs[i].Name = fmt.Sprintf("baetyl-function-config-%d", i)
+	}
+
+	// the configs are upserted one by one
+	var upserted []string
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).DoAndReturn(func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		upserted = append(upserted, cfg.Name)
+		return cfg, nil
+	}).Times(len(configs))
+	err := appFacade.updateGenConfigsOfFunctionApp(nil, ns, configs)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"baetyl-function-config-0", "baetyl-function-config-1", "baetyl-function-config-2"}, upserted)
+
+	// no more config is upserted after a failure
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, unknownErr)
+	err = appFacade.updateGenConfigsOfFunctionApp(nil, ns, configs)
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestGenConfigBlobsOfFunctionApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		config: mAppFacade.sConfig,
+		blob:   mAppFacade.sBlob,
+		conf:   config.Facade{ConfigBlobThreshold: 8},
+		log:    log.L(),
+	}
+	ns := "baetyl-cloud"
+	name := "baetyl-function-program-config-abc"
+	cfg := specV1.Configuration{Name: name, Data: map[string]string{"a.bin": "0123456789"}}
+	ref := map[string]string{"a.bin": models.ConfigBlobRef(models.ConfigBlobHash("0123456789"))}
+
+	// the checksum is computed from the data before the large values are externalized
+	mAppFacade.sBlob.EXPECT().Externalize(nil, ns, gomock.Any(), 8).DoAndReturn(
+		func(_ interface{}, _ string, c *specV1.Configuration, _ int) (*specV1.Configuration, error) {
+			assert.Equal(t, models.ConfigChecksum(&cfg), c.Labels[common.LabelConfigChecksum])
+			res := *c
+			res.Data = ref
+			return &res, nil
+		})
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, c *specV1.Configuration) (*specV1.Configuration, error) {
+			assert.Equal(t, ref, c.Data)
+			return c, nil
+		})
+	assert.NoError(t, appFacade.updateGenConfigsOfFunctionApp(nil, ns, []specV1.Configuration{cfg}))
+
+	mAppFacade.sBlob.EXPECT().Externalize(nil, ns, gomock.Any(), 8).Return(nil, unknownErr)
+	assert.Equal(t, unknownErr, appFacade.updateGenConfigsOfFunctionApp(nil, ns, []specV1.Configuration{cfg}))
+
+	// the blobs are released with the config
+	oldApp := &specV1.Application{Name: "abc", Namespace: ns, Volumes: []specV1.Volume{
+		{Name: name, VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: name}}},
+	}}
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, name).Return(true, []string{"abc"}, nil).Times(2)
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, name).Return(nil).Times(2)
+	mAppFacade.sBlob.EXPECT().Release(nil, ns, name).Return(nil)
+	assert.NoError(t, appFacade.cleanGenConfigsOfFunctionApp(nil, nil, oldApp, nil))
+
+	mAppFacade.sBlob.EXPECT().Release(nil, ns, name).Return(unknownErr)
+	err := appFacade.cleanGenConfigsOfFunctionApp(nil, nil, oldApp, nil)
+	assert.Equal(t, unknownErr, err.(*CleanConfigsError).Errors[name])
+}
+
+func TestCreateAppOptionalConfig(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Name: "abc", Namespace: ns}
+	configs := []specV1.Configuration{
+		{Name: "required", Data: map[string]string{"a": "b"}},
+		{Name: "optional", Labels: map[string]string{common.LabelConfigOptional: "true"}, Data: map[string]string{"c": "d"}},
+	}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	// the failed optional config is rolled back to the savepoint and the creation proceeds
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	gomock.InOrder(
+		mAppFacade.sConfig.EXPECT().Upsert(nil, ns, configNameMatcher("required")).Return(nil, nil),
+		mAppFacade.txFactory.EXPECT().Savepoint(nil, optionalConfigSavepoint).Return(nil),
+		mAppFacade.sConfig.EXPECT().Upsert(nil, ns, configNameMatcher("optional")).Return(nil, unknownErr),
+		mAppFacade.txFactory.EXPECT().RollbackToSavepoint(nil, optionalConfigSavepoint).Return(nil),
+		mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(app, nil),
+	)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, configs)
+	assert.NoError(t, err)
+
+	// the failure of the savepoint aborts the creation
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, configNameMatcher("required")).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Savepoint(nil, optionalConfigSavepoint).Return(unknownErr)
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, app, configs)
+	assert.Equal(t, unknownErr, err)
+}
+
+type configNameMatcher string
+
+func (m configNameMatcher) Matches(x interface{}) bool {
+	cfg, ok := x.(*specV1.Configuration)
+	return ok && cfg.Name == string(m)
+}
+
+func (m configNameMatcher) String() string {
+	return "is the config " + string(m)
+}
+
+func TestDryRunCleanGenConfigsOfFunctionApp(t *testing.T) {
+	a := &facade{conf: config.Facade{FunctionConfigPrefix: "fn", FunctionProgramConfigPrefix: "fnp"}}
+	configVolume := func(name string) specV1.Volume {
+		return specV1.Volume{Name: name, VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: name}}}
+	}
+	oldApp := &specV1.Application{
+		Namespace: "baetyl-cloud",
+		Name:      "abc",
+		Volumes: []specV1.Volume{
+			configVolume("fn-abc-a"),
+			configVolume("fnp-abc-b"),
+			configVolume("fn-abc-c"),
+			configVolume("fn-abc-d"),
+			configVolume("user-config"),
+			{Name: "secret", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "fn-abc-e"}}},
+		},
+	}
+
+	// all the function configs are orphaned by the deletion, the configs of the users are untouched
+	assert.Equal(t, []string{"fn-abc-a", "fnp-abc-b", "fn-abc-c", "fn-abc-d"}, a.dryRunCleanGenConfigsOfFunctionApp(nil, oldApp, nil))
+
+	// the configs regenerated or still referenced by the new app are kept
+	app := &specV1.Application{Namespace: "baetyl-cloud", Name: "abc", Volumes: []specV1.Volume{configVolume("fn-abc-d")}}
+	configs := []specV1.Configuration{{Name: "fn-abc-a"}}
+	assert.Equal(t, []string{"fnp-abc-b", "fn-abc-c"}, a.dryRunCleanGenConfigsOfFunctionApp(configs, oldApp, app))
+
+	// nothing is orphaned
+	assert.Empty(t, a.dryRunCleanGenConfigsOfFunctionApp(nil, &specV1.Application{Name: "abc"}, nil))
+}
+
+func TestGetApps(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:       mAppFacade.sApp,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ctx := context.Background()
+	ns := "baetyl-cloud"
+	current := func() []*specV1.Application {
+		return []*specV1.Application{
+			{Namespace: ns, Name: "a", Version: "2"},
+			{Namespace: ns, Name: "b", Version: "1", CronStatus: specV1.CronWait},
+			{Namespace: ns, Name: "c", Version: "1", CronStatus: specV1.CronWait},
+		}
+	}
+
+	// the current versions and the crons are read by batch, the other versions one by one
+	mAppFacade.sApp.EXPECT().GetBatch(ns, []string{"a", "b", "c"}).Return(current(), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "a", "1").Return(&specV1.Application{Namespace: ns, Name: "a", Version: "1"}, nil)
+	mAppFacade.sCron.EXPECT().ListCrons(nil, ns, []string{"b", "c"}).Return([]models.Cron{{Name: "b", Namespace: ns, Selector: "x=y", Paused: true}}, nil)
+	apps, err := appFacade.GetApps(ctx, ns, []models.AppRef{{Name: "a", Version: "1"}, {Name: "b"}, {Name: "c", Version: "1"}})
+	assert.NoError(t, err)
+	assert.Len(t, apps, 3)
+	assert.Equal(t, "1", apps[0].Version)
+	assert.Equal(t, "x=y", apps[1].Selector)
+	assert.Equal(t, "true", apps[1].Labels[common.LabelCronPaused])
+	assert.Equal(t, "", apps[2].Selector)
+
+	// the apps missing are reported
+	mAppFacade.sApp.EXPECT().GetBatch(ns, []string{"a", "d"}).Return(current()[:1], nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "a", "0").Return(nil, common.Error(common.ErrResourceNotFound))
+	_, err = appFacade.GetApps(ctx, ns, []models.AppRef{{Name: "a", Version: "0"}, {Name: "d"}})
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+	assert.Contains(t, err.Error(), "a@0,d")
+
+	// the failures of the stores
+	mAppFacade.sApp.EXPECT().GetBatch(ns, []string{"a"}).Return(nil, unknownErr)
+	_, err = appFacade.GetApps(ctx, ns, []models.AppRef{{Name: "a"}})
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+	mAppFacade.sApp.EXPECT().GetBatch(ns, []string{"b"}).Return(current()[1:2], nil)
+	mAppFacade.sCron.EXPECT().ListCrons(nil, ns, []string{"b"}).Return(nil, unknownErr)
+	_, err = appFacade.GetApps(ctx, ns, []models.AppRef{{Name: "b"}})
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+
+	apps, err = appFacade.GetApps(ctx, ns, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, apps)
+}
+
+func TestCheckConfigRefs(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		secret:    mAppFacade.sSecret,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	configVolume := func(name string) specV1.Volume {
+		return specV1.Volume{Name: name, VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: name}}}
+	}
+	app := &specV1.Application{
+		Namespace: ns,
+		Name:      "abc",
+		Volumes: []specV1.Volume{
+			configVolume("upserted"),
+			configVolume("stored"),
+			configVolume("missing1"),
+			configVolume("missing1"),
+			configVolume("missing2"),
+			{Name: "secret", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "missing3"}}},
+		},
+	}
+	configs := []specV1.Configuration{{Namespace: ns, Name: "upserted"}}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "config"))
+
+	// the dangling references are listed with the dangling secrets, nothing is written
+	mAppFacade.sConfig.EXPECT().Get(ns, "stored", "").Return(&specV1.Configuration{Namespace: ns, Name: "stored"}, nil)
+	mAppFacade.sConfig.EXPECT().Get(ns, "missing1", "").Return(nil, notFound)
+	mAppFacade.sConfig.EXPECT().Get(ns, "missing2", "").Return(nil, notFound)
+	mAppFacade.sSecret.EXPECT().Get(ns, "missing3", "").Return(nil, notFound)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, configs)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrAppValidation, err.(errors.Coder).Code())
+	verr := err.(*ValidationError)
+	assert.Len(t, verr.Errors, 2)
+	assert.Equal(t, common.ErrMissingConfigRef, verr.Errors[0].Code)
+	assert.Contains(t, verr.Errors[0].Message, "missing1,missing2")
+	assert.Equal(t, common.ErrMissingSecretRef, verr.Errors[1].Code)
+	assert.Contains(t, err.Error(), "missing3")
+
+	// the failure of the config store
+	mAppFacade.sConfig.EXPECT().Get(ns, "stored", "").Return(nil, unknownErr)
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, app, configs)
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+
+	// the failure of the config store
+	mAppFacade.sConfig.EXPECT().Get(ns, "stored", "").Return(nil, unknownErr)
+	err = appFacade.checkConfigRefs(ns, app, configs)
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+
+	// all the references resolve
+	mAppFacade.sConfig.EXPECT().Get(ns, "stored", "").Return(&specV1.Configuration{Namespace: ns, Name: "stored"}, nil)
+	assert.NoError(t, appFacade.checkConfigRefs(ns, &specV1.Application{Name: "abc", Volumes: app.Volumes[:2]}, configs))
+}
+
+func TestCheckSecretRefs(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		secret: mAppFacade.sSecret,
+		log:    log.L(),
+	}
+	ns := "baetyl-cloud"
+	secretVolume := func(name string) specV1.Volume {
+		return specV1.Volume{Name: name, VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: name}}}
+	}
+	app := &specV1.Application{
+		Namespace: ns,
+		Name:      "abc",
+		Volumes: []specV1.Volume{
+			secretVolume("stored"),
+			secretVolume("missing1"),
+			secretVolume("missing1"),
+			secretVolume("missing2"),
+			{Name: "config", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "missing3"}}},
+		},
+	}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "secret"))
+
+	// the dangling references are listed
+	mAppFacade.sSecret.EXPECT().Get(ns, "stored", "").Return(&specV1.Secret{Namespace: ns, Name: "stored"}, nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "missing1", "").Return(nil, notFound)
+	mAppFacade.sSecret.EXPECT().Get(ns, "missing2", "").Return(nil, notFound)
+	err := appFacade.checkSecretRefs(ns, app)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrMissingSecretRef, err.(errors.Coder).Code())
+	assert.Contains(t, err.Error(), "missing1,missing2")
+
+	// the failure of the secret store
+	mAppFacade.sSecret.EXPECT().Get(ns, "stored", "").Return(nil, unknownErr)
+	err = appFacade.checkSecretRefs(ns, app)
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+
+	// all the references resolve
+	mAppFacade.sSecret.EXPECT().Get(ns, "stored", "").Return(&specV1.Secret{Namespace: ns, Name: "stored"}, nil)
+	assert.NoError(t, appFacade.checkSecretRefs(ns, &specV1.Application{Name: "abc", Volumes: app.Volumes[:1]}))
+}
+
+func TestCleanGenSecretsOfApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		secret: mAppFacade.sSecret,
+		log:    log.L(),
+	}
+	ns := "baetyl-cloud"
+	secretVolume := func(name string) specV1.Volume {
+		return specV1.Volume{Name: name, VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: name}}}
+	}
+	app := &specV1.Application{
+		Namespace: ns,
+		Name:      "abc",
+		Volumes:   []specV1.Volume{secretVolume("gen1"), secretVolume("gen2"), secretVolume("shared"), secretVolume("gone")},
+	}
+	generated := map[string]string{common.LabelAppName: "abc"}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "secret"))
+
+	// only the secrets labeled with the app are deleted, the failures don't stop the cleanup
+	mAppFacade.sSecret.EXPECT().Get(ns, "gen1", "").Return(&specV1.Secret{Name: "gen1", Labels: generated}, nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "gen2", "").Return(&specV1.Secret{Name: "gen2", Labels: generated}, nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "shared", "").Return(&specV1.Secret{Name: "shared"}, nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "gone", "").Return(nil, notFound)
+	mAppFacade.sSecret.EXPECT().Delete(ns, "gen1").Return(unknownErr)
+	mAppFacade.sSecret.EXPECT().Delete(ns, "gen2").Return(nil)
+	appFacade.cleanGenSecretsOfApp(ns, app)
+}
+
+func TestSkipNodeIndex(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		secret:    mAppFacade.sSecret,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	labels := map[string]string{common.LabelSkipNodeIndex: "true"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// the app is persisted and versioned without touching the nodes and the indexes
+	app := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Labels: labels}
+	created := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Labels: labels, Version: "1"}
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(created, nil)
+	res, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, created, res.App)
+	assert.Empty(t, res.Nodes)
+
+	app = &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d", Labels: labels}
+	updated := &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d", Labels: labels, Version: "2"}
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(created, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(updated, nil)
+	res, err = appFacade.UpdateApp(context.Background(), ns, created, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, res.App)
+
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(updated, nil)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "abc", "").Return(nil)
+	assert.NoError(t, appFacade.DeleteApp(context.Background(), ns, "abc", updated))
+
+	// the app isn't verified against the nodes matched by its selector
+	issues, err := appFacade.verifyNodesOfApp(ns, models.AppItem{Name: "abc", Selector: "c=d", Labels: labels})
 	assert.NoError(t, err)
+	assert.Empty(t, issues)
 }
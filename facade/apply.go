@@ -0,0 +1,43 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+// ApplyApp reconciles the app to the desired spec, the app is created by CreateApp if it doesn't exist,
+// otherwise it's updated by UpdateApp. The version of desired, if set, must be the current version of the app
+// or ErrResourceVersionConflict is returned, the current version is applied over if it isn't set.
+// Whether the app is created is returned with the app applied.
+func (a *facade) ApplyApp(ctx context.Context, ns string, desired *specV1.Application, configs []specV1.Configuration) (res *specV1.Application, created bool, err error) {
+	defer observeCall(ns, "ApplyApp", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, false, err
+	}
+	cur, err := a.getAppIfExists(ns, desired.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	var result *AppResult
+	if cur == nil {
+		result, err = a.CreateApp(ctx, ns, nil, desired, configs)
+		if err != nil {
+			return nil, false, err
+		}
+		return result.App, true, nil
+	}
+
+	app := *desired
+	app.Namespace = ns
+	if app.Version == "" {
+		app.Version = cur.Version
+	}
+	app.CreationTimestamp = cur.CreationTimestamp
+	result, err = a.UpdateApp(ctx, ns, cur, &app, configs)
+	if err != nil {
+		return nil, false, err
+	}
+	return result.App, false, nil
+}
@@ -0,0 +1,73 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestApplyApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		secret:    mAppFacade.sSecret,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "app"))
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	// the app missing is created
+	desired := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b"}
+	created := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Version: "1"}
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, notFound)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, desired, nil).Return(created, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, created).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	res, ok, err := appFacade.ApplyApp(context.Background(), ns, desired, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, created, res)
+
+	// the app existing is updated over its current version
+	desired = &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Description: "updated"}
+	updated := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Description: "updated", Version: "2"}
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(created, nil).Times(1)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(created, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Description: "updated", Version: "1"}).Return(updated, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, updated).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	res, ok, err = appFacade.ApplyApp(context.Background(), ns, desired, nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, updated, res)
+	assert.Equal(t, "", desired.Version)
+
+	// the version applied is stale
+	desired = &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Version: "1"}
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(updated, nil).Times(1)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(updated, nil).Times(1)
+	_, _, err = appFacade.ApplyApp(context.Background(), ns, desired, nil)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(errors.Coder).Code())
+
+	// the failure of the app store
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, unknownErr)
+	_, _, err = appFacade.ApplyApp(context.Background(), ns, desired, nil)
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+}
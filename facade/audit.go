@@ -0,0 +1,46 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ListAppAudit lists the audits of the app from the latest
+func (a *facade) ListAppAudit(ctx context.Context, ns, name string) ([]models.AppAudit, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return a.audit.ListAppAudit(ns, name)
+}
+
+// createAppAudit writes the audit of the app mutation within the transaction,
+// the actor is the user carried by the context
+func (a *facade) createAppAudit(ctx context.Context, tx interface{}, action models.AppAction, ns, name, oldVersion, newVersion string) error {
+	if a.audit == nil {
+		return nil
+	}
+	var actor string
+	if user, ok := common.UserFromContext(ctx); ok {
+		actor = user.Name
+		if actor == "" {
+			actor = user.ID
+		}
+	}
+	return a.audit.CreateAppAudit(tx, &models.AppAudit{
+		Namespace:  ns,
+		Name:       name,
+		Action:     action,
+		Actor:      actor,
+		OldVersion: oldVersion,
+		NewVersion: newVersion,
+		Timestamp:  time.Now().UTC(),
+	})
+}
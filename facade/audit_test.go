@@ -0,0 +1,77 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAppAudit(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		audit:     mAppFacade.sAudit,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	ctx := common.WithUser(context.Background(), common.User{ID: "uid", Name: "user"})
+	app := &specV1.Application{Namespace: ns, Name: "abc"}
+	created := &specV1.Application{Namespace: ns, Name: "abc", Version: "1"}
+
+	// the audit is rolled back with the app
+	gomock.InOrder(
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(created, nil),
+		mAppFacade.sAudit.EXPECT().CreateAppAudit(nil, gomock.Any()).Return(nil),
+		mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, created).Return(nil, unknownErr),
+		mAppFacade.txFactory.EXPECT().Rollback(nil).Return(),
+	)
+	_, err := appFacade.CreateApp(ctx, ns, nil, app, nil)
+	assert.Equal(t, unknownErr, err)
+
+	// the failure of audit fails the mutation
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(created, nil)
+	mAppFacade.sAudit.EXPECT().CreateAppAudit(nil, gomock.Any()).Return(unknownErr)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	_, err = appFacade.CreateApp(ctx, ns, nil, app, nil)
+	assert.Equal(t, unknownErr, err)
+
+	// update
+	app.Description = "changed"
+	updated := &specV1.Application{Namespace: ns, Name: "abc", Version: "2"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(created, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, app).Return(updated, nil)
+	mAppFacade.sAudit.EXPECT().CreateAppAudit(nil, gomock.Any()).DoAndReturn(func(_ interface{}, audit *models.AppAudit) error {
+		assert.Equal(t, ns, audit.Namespace)
+		assert.Equal(t, "abc", audit.Name)
+		assert.Equal(t, models.AppUpdated, audit.Action)
+		assert.Equal(t, "user", audit.Actor)
+		assert.Equal(t, "1", audit.OldVersion)
+		assert.Equal(t, "2", audit.NewVersion)
+		assert.False(t, audit.Timestamp.IsZero())
+		return nil
+	})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, updated).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	_, err = appFacade.UpdateApp(ctx, ns, created, app, nil)
+	assert.NoError(t, err)
+
+	mAppFacade.sAudit.EXPECT().ListAppAudit(ns, "abc").Return([]models.AppAudit{{Name: "abc"}}, nil)
+	res, err := appFacade.ListAppAudit(ctx, ns, "abc")
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+}
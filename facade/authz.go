@@ -0,0 +1,90 @@
+package facade
+
+import (
+	"context"
+	"sync"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// Authorizer decides whether the caller can access the namespace, e.g. whether the tenant of the caller owns it.
+// The user is carried by ctx, ok is false if the caller is anonymous, e.g. the background jobs.
+// ErrForbidden should be returned if the access is denied.
+type Authorizer interface {
+	Authorize(ctx context.Context, user common.User, ok bool, ns string) error
+}
+
+// PrivilegeAuthorizer decides whether the caller can take the privileged action on the namespace beyond accessing it,
+// e.g. freezing the apps. The privileged actions are denied if the authorizer set doesn't implement it.
+type PrivilegeAuthorizer interface {
+	AuthorizePrivilege(ctx context.Context, user common.User, ok bool, ns, action string) error
+}
+
+// the privileged actions checked by PrivilegeAuthorizer
+const (
+	// PrivilegeFreezeApp freezes or unfreezes the apps
+	PrivilegeFreezeApp = "freezeApp"
+)
+
+// AuthorizerFunc adapts the func to the Authorizer
+type AuthorizerFunc func(ctx context.Context, user common.User, ok bool, ns string) error
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, user common.User, ok bool, ns string) error {
+	return f(ctx, user, ok, ns)
+}
+
+// authorizer holds the authorizer set to the facade, all the accesses are allowed if it's not set
+type authorizer struct {
+	mu    sync.RWMutex
+	authz Authorizer
+}
+
+func (z *authorizer) set(authz Authorizer) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+	z.authz = authz
+}
+
+func (z *authorizer) get() Authorizer {
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+	return z.authz
+}
+
+// SetAuthorizer sets the authorizer consulted by the facade methods before accessing the namespaces,
+// the authorizer set before is replaced, nil allows all the accesses
+func (a *facade) SetAuthorizer(authz Authorizer) {
+	a.authz.set(authz)
+}
+
+// authorize checks the access of the caller carried by ctx to the namespaces
+func (a *facade) authorize(ctx context.Context, namespaces ...string) error {
+	authz := a.authz.get()
+	if authz == nil {
+		return nil
+	}
+	user, ok := common.UserFromContext(ctx)
+	for _, ns := range namespaces {
+		if err := authz.Authorize(ctx, user, ok, ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorizePrivilege checks the caller carried by ctx can access the namespace and take the privileged action on it
+func (a *facade) authorizePrivilege(ctx context.Context, ns, action string) error {
+	authz := a.authz.get()
+	if authz == nil {
+		return nil
+	}
+	user, ok := common.UserFromContext(ctx)
+	if err := authz.Authorize(ctx, user, ok, ns); err != nil {
+		return err
+	}
+	privilege, is := authz.(PrivilegeAuthorizer)
+	if !is {
+		return common.Error(common.ErrForbidden, common.Field("user", user.Name), common.Field("namespace", ns))
+	}
+	return privilege.AuthorizePrivilege(ctx, user, ok, ns, action)
+}
@@ -0,0 +1,61 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestAuthorize(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app: mAppFacade.sApp,
+		log: log.L(),
+	}
+	// the tenant owns the namespaces prefixed with its name
+	appFacade.SetAuthorizer(AuthorizerFunc(func(_ context.Context, user common.User, ok bool, ns string) error {
+		if !ok || ns != user.ID+"-ns" {
+			return common.Error(common.ErrForbidden, common.Field("user", user.Name), common.Field("namespace", ns))
+		}
+		return nil
+	}))
+	ctx := common.WithUser(context.Background(), common.User{ID: "t1", Name: "alice"})
+	app := &specV1.Application{Namespace: "t1-ns", Name: "abc"}
+
+	// the namespace of the tenant
+	mAppFacade.sApp.EXPECT().Get("t1-ns", "abc", "").Return(app, nil)
+	res, err := appFacade.GetApp(ctx, "t1-ns", "abc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, app, res)
+
+	// the namespace of another tenant, nothing is accessed
+	_, err = appFacade.GetApp(ctx, "t2-ns", "abc", "")
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrForbidden, err.(errors.Coder).Code())
+	assert.Contains(t, err.Error(), "alice")
+	err = appFacade.DeleteApp(ctx, "t2-ns", "abc", nil)
+	assert.Equal(t, common.ErrForbidden, err.(errors.Coder).Code())
+	_, err = appFacade.ListApps(ctx, "t2-ns", nil)
+	assert.Equal(t, common.ErrForbidden, err.(errors.Coder).Code())
+
+	// both the source and the destination are checked by the clone
+	_, err = appFacade.CloneApp(ctx, "t1-ns", "abc", "", "t2-ns", "abc", CloneConflictFail)
+	assert.Equal(t, common.ErrForbidden, err.(errors.Coder).Code())
+
+	// the anonymous caller
+	_, err = appFacade.GetApp(context.Background(), "t1-ns", "abc", "")
+	assert.Equal(t, common.ErrForbidden, err.(errors.Coder).Code())
+
+	// all the accesses are allowed without the authorizer
+	appFacade.SetAuthorizer(nil)
+	mAppFacade.sApp.EXPECT().Get("t2-ns", "abc", "").Return(app, nil)
+	_, err = appFacade.GetApp(context.Background(), "t2-ns", "abc", "")
+	assert.NoError(t, err)
+}
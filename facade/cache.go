@@ -0,0 +1,129 @@
+package facade
+
+import (
+	"container/list"
+	"sync"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+type appCacheKey struct {
+	ns, name, version string
+}
+
+type appCacheEntry struct {
+	key appCacheKey
+	app *specV1.Application
+}
+
+// appCache the LRU cache of the apps read by GetApp, the apps are invalidated after the mutations are committed.
+// The generation is bumped by every invalidation, the app read across an invalidation is not cached since it
+// may be read before the commit.
+type appCache struct {
+	mu    sync.Mutex
+	size  int
+	gen   uint64
+	ll    *list.List
+	items map[appCacheKey]*list.Element
+}
+
+func newAppCache(size int) *appCache {
+	return &appCache{
+		size:  size,
+		ll:    list.New(),
+		items: map[appCacheKey]*list.Element{},
+	}
+}
+
+func (c *appCache) generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen
+}
+
+func (c *appCache) get(key appCacheKey) (*specV1.Application, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+	return e.Value.(*appCacheEntry).app, true
+}
+
+// add caches the app read since the generation, the least recently used app is evicted if the cache is full
+func (c *appCache) add(key appCacheKey, app *specV1.Application, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	if e, ok := c.items[key]; ok {
+		e.Value.(*appCacheEntry).app = app
+		c.ll.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&appCacheEntry{key: key, app: app})
+	for c.ll.Len() > c.size {
+		e := c.ll.Back()
+		c.ll.Remove(e)
+		delete(c.items, e.Value.(*appCacheEntry).key)
+	}
+}
+
+// invalidate removes all the versions of the app
+func (c *appCache) invalidate(ns, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	for key, e := range c.items {
+		if key.ns == ns && key.name == name {
+			c.ll.Remove(e)
+			delete(c.items, key)
+		}
+	}
+}
+
+// getCachedApp returns a copy of the cached app, so that the cached one is never modified by the caller.
+// The generation is returned on miss to cache the app read afterwards.
+func (a *facade) getCachedApp(key appCacheKey) (*specV1.Application, uint64, bool) {
+	if a.cache == nil {
+		return nil, 0, false
+	}
+	gen := a.cache.generation()
+	app, ok := a.cache.get(key)
+	if !ok {
+		facadeAppCacheRequests.WithLabelValues(cacheMiss).Inc()
+		return nil, gen, false
+	}
+	res, err := copyApp(app)
+	if err != nil {
+		return nil, gen, false
+	}
+	facadeAppCacheRequests.WithLabelValues(cacheHit).Inc()
+	return res, gen, true
+}
+
+// cacheApp caches a copy of the app read since the generation, the app waiting for cron is never cached
+// since its selector and paused label are kept by the cron, which is changed without the app, neither is
+// the app waiting for activation, whose activation is deleted without the app once activated
+func (a *facade) cacheApp(key appCacheKey, app *specV1.Application, gen uint64) {
+	if a.cache == nil || app == nil || app.CronStatus == specV1.CronWait || app.Labels[common.LabelActivateAt] != "" {
+		return
+	}
+	res, err := copyApp(app)
+	if err != nil {
+		return
+	}
+	a.cache.add(key, res, gen)
+}
+
+func (a *facade) invalidateCachedApp(ns, name string) {
+	if a.cache == nil {
+		return
+	}
+	a.cache.invalidate(ns, name)
+}
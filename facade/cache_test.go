@@ -0,0 +1,82 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAppCache(t *testing.T) {
+	c := newAppCache(2)
+	k1 := appCacheKey{ns: "default", name: "a", version: ""}
+	k2 := appCacheKey{ns: "default", name: "a", version: "1"}
+	k3 := appCacheKey{ns: "default", name: "b", version: ""}
+
+	c.add(k1, &specV1.Application{Name: "a", Version: "2"}, c.generation())
+	c.add(k2, &specV1.Application{Name: "a", Version: "1"}, c.generation())
+	_, ok := c.get(k1)
+	assert.True(t, ok)
+	// the least recently used k2 is evicted
+	c.add(k3, &specV1.Application{Name: "b"}, c.generation())
+	_, ok = c.get(k2)
+	assert.False(t, ok)
+	_, ok = c.get(k3)
+	assert.True(t, ok)
+
+	// the app read across the invalidation is not cached
+	gen := c.generation()
+	c.invalidate("default", "a")
+	_, ok = c.get(k1)
+	assert.False(t, ok)
+	c.add(k1, &specV1.Application{Name: "a", Version: "2"}, gen)
+	_, ok = c.get(k1)
+	assert.False(t, ok)
+	_, ok = c.get(k3)
+	assert.True(t, ok)
+}
+
+func TestGetAppCached(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:   mAppFacade.sApp,
+		cron:  mAppFacade.sCron,
+		cache: newAppCache(10),
+		log:   log.L(),
+	}
+	ns, name := "baetyl-cloud", "abc"
+	hits := testutil.ToFloat64(facadeAppCacheRequests.WithLabelValues(cacheHit))
+	misses := testutil.ToFloat64(facadeAppCacheRequests.WithLabelValues(cacheMiss))
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, Version: "1", Labels: map[string]string{"k": "v"}}, nil)
+	app, err := appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	app.Labels["k"] = "changed"
+	app, err = appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "v", app.Labels["k"])
+	assert.Equal(t, hits+1, testutil.ToFloat64(facadeAppCacheRequests.WithLabelValues(cacheHit)))
+	assert.Equal(t, misses+1, testutil.ToFloat64(facadeAppCacheRequests.WithLabelValues(cacheMiss)))
+
+	// the committed mutation invalidates the app
+	appFacade.publishAppEvent(context.Background(), models.AppUpdated, ns, &specV1.Application{Name: name, Version: "2"}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, Version: "2"}, nil)
+	app, err = appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", app.Version)
+
+	// the app waiting for cron is not cached
+	mAppFacade.sApp.EXPECT().Get(ns, "cron", "").Return(&specV1.Application{Name: "cron", CronStatus: specV1.CronWait}, nil).Times(2)
+	mAppFacade.sCron.EXPECT().GetCron(nil, "cron", ns).Return(&models.Cron{Selector: "a=b"}, nil).Times(2)
+	for i := 0; i < 2; i++ {
+		app, err = appFacade.GetApp(context.Background(), ns, "cron", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "a=b", app.Selector)
+	}
+}
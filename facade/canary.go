@@ -0,0 +1,211 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// CanaryStatus the rollout of the current version of an application
+type CanaryStatus struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   string `json:"version"`
+	// Percent is 0 if the app is fully rolled out
+	Percent int `json:"percent"`
+	// Canary are the matched nodes chosen for the current version
+	Canary []string `json:"canary"`
+	// Versions are the matched nodes grouped by the version of the app they desire
+	Versions map[string][]string `json:"versions"`
+	// Pending are the matched nodes which don't desire the app yet
+	Pending []string `json:"pending"`
+}
+
+// CanaryRollout rolls the current version of the app out to the percent of the nodes matched by its selector,
+// the other nodes keep the version they desire. The nodes are chosen by the hash of their names, so the same
+// nodes stay on canary across calls and a larger percent keeps the nodes already chosen.
+func (a *facade) CanaryRollout(ctx context.Context, ns, name string, percent int) (*specV1.Application, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if percent <= 0 || percent >= 100 {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the canary percent (%d) should be between 1 and 99, promote the app to roll it out to all nodes", percent)))
+	}
+	cur, err := a.getCanaryApp(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	app := *cur
+	app.Labels = map[string]string{}
+	for k, v := range cur.Labels {
+		app.Labels[k] = v
+	}
+	app.Labels[common.LabelCanaryPercent] = strconv.Itoa(percent)
+	res, err := a.UpdateApp(ctx, ns, cur, &app, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.App, nil
+}
+
+// PromoteApp rolls the current version of the app in canary out to all the nodes matched by its selector
+func (a *facade) PromoteApp(ctx context.Context, ns, name string) (*specV1.Application, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	cur, err := a.getCanaryApp(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := cur.Labels[common.LabelCanaryPercent]; !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the app (%s) is not in canary", name)))
+	}
+	app := *cur
+	app.Labels = map[string]string{}
+	for k, v := range cur.Labels {
+		if k != common.LabelCanaryPercent {
+			app.Labels[k] = v
+		}
+	}
+	res, err := a.UpdateApp(ctx, ns, cur, &app, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.App, nil
+}
+
+// GetCanaryStatus returns the nodes matched by the app and the versions of the app they desire
+func (a *facade) GetCanaryStatus(ctx context.Context, ns, name string) (*CanaryStatus, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	app, err := a.app.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	percent, err := canaryPercent(app)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := a.node.MatchNodes(nil, ns, app.Selector)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(nodes)
+
+	status := &CanaryStatus{
+		Name:      name,
+		Namespace: ns,
+		Version:   app.Version,
+		Percent:   percent,
+		Canary:    []string{},
+		Versions:  map[string][]string{},
+		Pending:   []string{},
+	}
+	if percent > 0 {
+		status.Canary = append(status.Canary, selectCanaryNodes(name, nodes, percent)...)
+		sort.Strings(status.Canary)
+	}
+	for _, n := range nodes {
+		desire, err := a.node.GetDesire(ns, n)
+		if err != nil {
+			if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+				return nil, err
+			}
+			desire = nil
+		}
+		version := ""
+		if desire != nil {
+			for _, info := range desire.AppInfos(app.System) {
+				if info.Name == name {
+					version = info.Version
+					break
+				}
+			}
+		}
+		if version == "" {
+			status.Pending = append(status.Pending, n)
+			continue
+		}
+		status.Versions[version] = append(status.Versions[version], n)
+	}
+	return status, nil
+}
+
+func (a *facade) getCanaryApp(ns, name string) (*specV1.Application, error) {
+	app, err := a.app.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	// the cron app is deployed when the cron fires instead
+	if app.CronStatus == specV1.CronWait {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the app (%s) waiting for cron can't be rolled out in canary", name)))
+	}
+	return app, nil
+}
+
+// updateCanaryNodeAndAppIndex deploys the app to the canary nodes only,
+// but indexes all the matched nodes since the others keep running the previous version
+func (a *facade) updateCanaryNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application, percent int, undo *compensations) ([]string, error) {
+	nodes, err := a.node.MatchNodes(tx, namespace, app.Selector)
+	if err != nil {
+		return nil, err
+	}
+	if canary := selectCanaryNodes(app.Name, nodes, percent); len(canary) > 0 {
+		if err = a.node.UpdateDesire(tx, namespace, canary, app, service.RefreshNodeDesireByApp); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, a.refreshNodesIndexByApp(tx, namespace, app, nodes, undo)
+}
+
+// canaryPercent returns the canary percent of the app, 0 is returned if the app is rolled out to all nodes
+func canaryPercent(app *specV1.Application) (int, error) {
+	v, ok := app.Labels[common.LabelCanaryPercent]
+	if !ok {
+		return 0, nil
+	}
+	percent, err := strconv.Atoi(v)
+	if err != nil || percent <= 0 || percent > 100 {
+		return 0, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the canary percent (%s) of the app (%s) should be between 1 and 100", v, app.Name)))
+	}
+	if percent == 100 {
+		return 0, nil
+	}
+	return percent, nil
+}
+
+// selectCanaryNodes chooses the percent (rounded up) of the nodes ordered by the hash of the app and node names
+func selectCanaryNodes(app string, nodes []string, percent int) []string {
+	sorted := make([]string, len(nodes))
+	copy(sorted, nodes)
+	sort.Slice(sorted, func(i, j int) bool {
+		hi, hj := canaryHash(app, sorted[i]), canaryHash(app, sorted[j])
+		if hi != hj {
+			return hi < hj
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted[:(len(sorted)*percent+99)/100]
+}
+
+func canaryHash(app, node string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(app + "/" + node))
+	return h.Sum32()
+}
@@ -0,0 +1,171 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestSelectCanaryNodes(t *testing.T) {
+	var nodes []string
+	for i := 0; i < 50; i++ {
+		nodes = append(nodes, fmt.Sprintf("node-%d", i))
+	}
+	assert.Len(t, selectCanaryNodes("app", nodes, 10), 5)
+	assert.Len(t, selectCanaryNodes("app", nodes, 1), 1)
+	assert.Len(t, selectCanaryNodes("app", nodes[:3], 10), 1)
+	assert.Len(t, selectCanaryNodes("app", nil, 10), 0)
+
+	// the same nodes are chosen regardless of the order, and kept with a larger percent
+	reversed := make([]string, len(nodes))
+	for i, n := range nodes {
+		reversed[len(nodes)-1-i] = n
+	}
+	small := selectCanaryNodes("app", nodes, 20)
+	assert.Equal(t, small, selectCanaryNodes("app", reversed, 20))
+	assert.Subset(t, selectCanaryNodes("app", nodes, 60), small)
+	assert.NotEqual(t, small, selectCanaryNodes("other", nodes, 20))
+}
+
+func TestCanaryPercent(t *testing.T) {
+	app := &specV1.Application{Name: "abc"}
+	percent, err := canaryPercent(app)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, percent)
+
+	app.Labels = map[string]string{common.LabelCanaryPercent: "30"}
+	percent, err = canaryPercent(app)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, percent)
+
+	app.Labels[common.LabelCanaryPercent] = "100"
+	percent, err = canaryPercent(app)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, percent)
+
+	for _, v := range []string{"0", "101", "-1", "x"} {
+		app.Labels[common.LabelCanaryPercent] = v
+		_, err = canaryPercent(app)
+		assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code(), v)
+	}
+}
+
+func TestCanaryRollout(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns, name := "baetyl-cloud", "abc"
+	nodes := []string{"n1", "n2", "n3", "n4"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	_, err := appFacade.CanaryRollout(context.Background(), ns, name, 100)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	cur := &specV1.Application{Name: name, Namespace: ns, Version: "1", Selector: "a=b", Labels: map[string]string{"k": "v"}}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(cur, nil).Times(1)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, name).Return(cur, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, map[string]string{"k": "v", common.LabelCanaryPercent: "50"}, app.Labels)
+			app.Version = "2"
+			return app, nil
+		})
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nodes, nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, selectCanaryNodes(name, nodes, 50), gomock.Any(), gomock.Any()).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, nodes).Return(nil)
+	res, err := appFacade.CanaryRollout(context.Background(), ns, name, 50)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", res.Version)
+	assert.Equal(t, map[string]string{"k": "v"}, cur.Labels)
+
+	// cron app
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, CronStatus: specV1.CronWait}, nil)
+	_, err = appFacade.CanaryRollout(context.Background(), ns, name, 50)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
+
+func TestPromoteApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns, name := "baetyl-cloud", "abc"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, Version: "1"}, nil)
+	_, err := appFacade.PromoteApp(context.Background(), ns, name)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	cur := &specV1.Application{Name: name, Namespace: ns, Version: "2", Selector: "a=b",
+		Labels: map[string]string{"k": "v", common.LabelCanaryPercent: "50"}}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(cur, nil).Times(1)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, name).Return(cur, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, map[string]string{"k": "v"}, app.Labels)
+			app.Version = "3"
+			return app, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return([]string{"n1", "n2"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, []string{"n1", "n2"}).Return(nil)
+	res, err := appFacade.PromoteApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", res.Version)
+}
+
+func TestGetCanaryStatus(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node: mAppFacade.sNode,
+		app:  mAppFacade.sApp,
+	}
+	ns, name := "baetyl-cloud", "abc"
+	nodes := []string{"n3", "n1", "n2"}
+	app := &specV1.Application{Name: name, Version: "2", Selector: "a=b",
+		Labels: map[string]string{common.LabelCanaryPercent: "30"}}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nodes, nil)
+	desire := func(version string) *specV1.Desire {
+		return &specV1.Desire{"apps": []specV1.AppInfo{{Name: name, Version: version}}}
+	}
+	mAppFacade.sNode.EXPECT().GetDesire(ns, "n1").Return(desire("2"), nil)
+	mAppFacade.sNode.EXPECT().GetDesire(ns, "n2").Return(desire("1"), nil)
+	mAppFacade.sNode.EXPECT().GetDesire(ns, "n3").Return(nil, common.Error(common.ErrResourceNotFound))
+
+	status, err := appFacade.GetCanaryStatus(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, &CanaryStatus{
+		Name:      name,
+		Namespace: ns,
+		Version:   "2",
+		Percent:   30,
+		Canary:    selectCanaryNodes(name, []string{"n1", "n2", "n3"}, 30),
+		Versions:  map[string][]string{"2": {"n1"}, "1": {"n2"}},
+		Pending:   []string{"n3"},
+	}, status)
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1"}, nil)
+	mAppFacade.sNode.EXPECT().GetDesire(ns, "n1").Return(nil, unknownErr)
+	_, err = appFacade.GetCanaryStatus(context.Background(), ns, name)
+	assert.Equal(t, unknownErr, err)
+}
@@ -0,0 +1,151 @@
+package facade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// CloneConflictPolicy decides what to do if a config to be cloned already exists in the destination
+type CloneConflictPolicy string
+
+const (
+	// CloneConflictFail fails the clone with ErrResourceConflict
+	CloneConflictFail CloneConflictPolicy = "fail"
+	// CloneConflictReuse references the existing config instead of cloning it
+	CloneConflictReuse CloneConflictPolicy = "reuse"
+)
+
+// CloneApp copies the app of the version into the destination namespace as newName, the configs
+// referenced by the app are cloned as well. The generated function configs are renamed after the
+// new app while the others keep their names, the existing ones are handled by the policy.
+func (a *facade) CloneApp(ctx context.Context, srcNs, name, version, dstNs, newName string, policy CloneConflictPolicy) (*specV1.Application, error) {
+	if err := a.authorize(ctx, srcNs, dstNs); err != nil {
+		return nil, err
+	}
+	src, err := a.GetApp(ctx, srcNs, name, version)
+	if err != nil {
+		return nil, err
+	}
+	app, err := copyApp(src)
+	if err != nil {
+		return nil, err
+	}
+	app.Name = newName
+	app.Namespace = dstNs
+	app.Version = ""
+	app.CreationTimestamp = time.Time{}
+	delete(app.Labels, common.LabelCronPaused)
+	delete(app.Labels, common.LabelCronNextRuns)
+
+	var configs []specV1.Configuration
+	cloned := map[string]string{}
+	for _, v := range app.Volumes {
+		if v.Config == nil {
+			continue
+		}
+		// the config mounted by several volumes is cloned once
+		cfgName, ok := cloned[v.Config.Name]
+		if !ok {
+			cfg, err := a.cloneConfig(ctx, srcNs, dstNs, v.Config.Name, name, newName, policy)
+			if err != nil {
+				return nil, err
+			}
+			cfgName = v.Config.Name
+			if cfg != nil {
+				cfgName = cfg.Name
+				configs = append(configs, *cfg)
+			}
+			cloned[v.Config.Name] = cfgName
+		}
+		v.Config.Name = cfgName
+		v.Config.Version = ""
+	}
+	res, err := a.CreateApp(ctx, dstNs, nil, app, configs)
+	if err != nil {
+		return nil, err
+	}
+	return res.App, nil
+}
+
+// cloneConfig copies the config into the destination namespace, nil if the existing one is reused
+func (a *facade) cloneConfig(ctx context.Context, srcNs, dstNs, cfgName, appName, newAppName string, policy CloneConflictPolicy) (*specV1.Configuration, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	src, err := a.config.Get(srcNs, cfgName, "")
+	if err != nil {
+		return nil, err
+	}
+	name := a.cloneConfigName(cfgName, appName, newAppName)
+	if name == cfgName {
+		_, err = a.config.Get(dstNs, name, "")
+		if err == nil {
+			if policy == CloneConflictReuse {
+				return nil, nil
+			}
+			return nil, common.Error(common.ErrResourceConflict, common.Field("type", common.Config), common.Field("name", name))
+		}
+		if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, err
+		}
+	}
+
+	cfg := &specV1.Configuration{
+		Name:        name,
+		Namespace:   dstNs,
+		Labels:      map[string]string{},
+		Data:        map[string]string{},
+		Description: src.Description,
+		System:      src.System,
+	}
+	for k, v := range src.Labels {
+		cfg.Labels[k] = v
+	}
+	for k, v := range src.Data {
+		cfg.Data[k] = v
+	}
+	return cfg, nil
+}
+
+// cloneConfigName renames the generated function config (prefix-app-service-suffix) after the new app
+// with a new random suffix, so that it's owned by the new app only. Other configs keep their names.
+func (a *facade) cloneConfigName(name, appName, newAppName string) string {
+	prefix, programPrefix := a.functionConfigPrefixes()
+	prefixes := []string{programPrefix, prefix}
+	// the longer one is tried first in case it starts with the other
+	if len(prefix) > len(programPrefix) {
+		prefixes = []string{prefix, programPrefix}
+	}
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(name, prefix+"-") {
+			continue
+		}
+		rest := strings.TrimPrefix(strings.TrimPrefix(name, prefix+"-"), appName+"-")
+		if i := strings.LastIndex(rest, "-"); i >= 0 {
+			rest = rest[:i]
+		}
+		return strings.ToLower(fmt.Sprintf("%s-%s-%s-%s", prefix, newAppName, rest, common.RandString(9)))
+	}
+	return name
+}
+
+// copyApp deep copies the app
+func copyApp(app *specV1.Application) (*specV1.Application, error) {
+	data, err := json.Marshal(app)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res := new(specV1.Application)
+	if err = json.Unmarshal(data, res); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
@@ -0,0 +1,151 @@
+package facade
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestCloneApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		secret:    mAppFacade.sSecret,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	srcNs, dstNs := "staging", "production"
+	funcCfg := "baetyl-function-config-abc-svc-xxxxxxxxx"
+	newApp := func() *specV1.Application {
+		return &specV1.Application{
+			Namespace: srcNs,
+			Name:      "abc",
+			Version:   "v1",
+			Type:      common.FunctionApp,
+			Selector:  "a=b",
+			Labels:    map[string]string{"app": "abc"},
+			Volumes: []specV1.Volume{
+				{Name: "func", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: funcCfg, Version: "1"}}},
+				{Name: "user1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "user", Version: "2"}}},
+				{Name: "user2", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "user", Version: "2"}}},
+				{Name: "secret", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s"}}},
+			},
+		}
+	}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "config"))
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	// the secrets aren't cloned, which are referenced in the destination namespace
+	mAppFacade.sSecret.EXPECT().Get(dstNs, "s", "").Return(&specV1.Secret{Namespace: dstNs, Name: "s"}, nil).AnyTimes()
+
+	// the configs are cloned and the references are rewritten
+	src := newApp()
+	mAppFacade.sApp.EXPECT().Get(srcNs, "abc", "v1").Return(src, nil)
+	mAppFacade.sConfig.EXPECT().Get(srcNs, funcCfg, "").Return(&specV1.Configuration{
+		Namespace: srcNs, Name: funcCfg, Version: "1", Data: map[string]string{"k": "func"},
+	}, nil)
+	mAppFacade.sConfig.EXPECT().Get(srcNs, "user", "").Return(&specV1.Configuration{
+		Namespace: srcNs, Name: "user", Version: "2", Labels: map[string]string{"l": "v"}, Data: map[string]string{"k": "user"},
+	}, nil)
+	mAppFacade.sConfig.EXPECT().Get(dstNs, "user", "").Return(nil, notFound)
+	var upserted []specV1.Configuration
+	mAppFacade.sConfig.EXPECT().Upsert(nil, dstNs, gomock.Any()).DoAndReturn(func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		upserted = append(upserted, *cfg)
+		return cfg, nil
+	}).Times(2)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, dstNs, gomock.Any(), nil).DoAndReturn(
+		func(_ interface{}, _ string, app, _ *specV1.Application) (*specV1.Application, error) {
+			return app, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, dstNs, gomock.Any()).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, dstNs, "abc-prod", gomock.Any()).Return(nil)
+	res, err := appFacade.CloneApp(context.Background(), srcNs, "abc", "v1", dstNs, "abc-prod", CloneConflictFail)
+	assert.NoError(t, err)
+	assert.Equal(t, dstNs, res.Namespace)
+	assert.Equal(t, "abc-prod", res.Name)
+	assert.Equal(t, "", res.Version)
+	assert.Equal(t, "a=b", res.Selector)
+	assert.True(t, strings.HasPrefix(res.Volumes[0].Config.Name, "baetyl-function-config-abc-prod-svc-"))
+	assert.Equal(t, "", res.Volumes[0].Config.Version)
+	assert.Equal(t, "user", res.Volumes[1].Config.Name)
+	assert.Equal(t, "user", res.Volumes[2].Config.Name)
+	assert.Equal(t, "s", res.Volumes[3].Secret.Name)
+	assert.Len(t, upserted, 2)
+	assert.Equal(t, res.Volumes[0].Config.Name, upserted[0].Name)
+	assert.Equal(t, dstNs, upserted[0].Namespace)
+	assert.Equal(t, "", upserted[0].Version)
+	assert.Equal(t, map[string]string{"k": "func"}, upserted[0].Data)
+	assert.Equal(t, "user", upserted[1].Name)
+	assert.Equal(t, map[string]string{"l": "v"}, upserted[1].Labels)
+	// the source is untouched
+	assert.Equal(t, funcCfg, src.Volumes[0].Config.Name)
+	assert.Equal(t, srcNs, src.Namespace)
+
+	// conflict
+	mAppFacade.sApp.EXPECT().Get(srcNs, "abc", "v1").Return(newApp(), nil)
+	mAppFacade.sConfig.EXPECT().Get(srcNs, funcCfg, "").Return(&specV1.Configuration{Namespace: srcNs, Name: funcCfg}, nil)
+	mAppFacade.sConfig.EXPECT().Get(srcNs, "user", "").Return(&specV1.Configuration{Namespace: srcNs, Name: "user"}, nil)
+	mAppFacade.sConfig.EXPECT().Get(dstNs, "user", "").Return(&specV1.Configuration{Namespace: dstNs, Name: "user"}, nil)
+	_, err = appFacade.CloneApp(context.Background(), srcNs, "abc", "v1", dstNs, "abc-prod", CloneConflictFail)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrResourceConflict, e.Code())
+
+	// reuse
+	mAppFacade.sApp.EXPECT().Get(srcNs, "abc", "v1").Return(newApp(), nil)
+	mAppFacade.sConfig.EXPECT().Get(srcNs, funcCfg, "").Return(&specV1.Configuration{Namespace: srcNs, Name: funcCfg}, nil)
+	mAppFacade.sConfig.EXPECT().Get(srcNs, "user", "").Return(&specV1.Configuration{Namespace: srcNs, Name: "user"}, nil)
+	// the config reused is looked up again by the check of the references
+	mAppFacade.sConfig.EXPECT().Get(dstNs, "user", "").Return(&specV1.Configuration{Namespace: dstNs, Name: "user"}, nil).Times(2)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, dstNs, gomock.Any()).DoAndReturn(func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.True(t, appFacade.isFunctionConfig(cfg.Name))
+		return cfg, nil
+	})
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, dstNs, gomock.Any(), nil).DoAndReturn(
+		func(_ interface{}, _ string, app, _ *specV1.Application) (*specV1.Application, error) {
+			return app, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, dstNs, gomock.Any()).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, dstNs, "abc-prod", gomock.Any()).Return(nil)
+	res, err = appFacade.CloneApp(context.Background(), srcNs, "abc", "v1", dstNs, "abc-prod", CloneConflictReuse)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", res.Volumes[1].Config.Name)
+
+	// source not found
+	mAppFacade.sApp.EXPECT().Get(srcNs, "none", "").Return(nil, notFound)
+	_, err = appFacade.CloneApp(context.Background(), srcNs, "none", "", dstNs, "abc-prod", CloneConflictFail)
+	assert.Error(t, err)
+}
+
+func TestCloneConfigName(t *testing.T) {
+	a := &facade{}
+	name := a.cloneConfigName("baetyl-function-config-abc-svc-xxxxxxxxx", "abc", "new")
+	assert.True(t, strings.HasPrefix(name, "baetyl-function-config-new-svc-"))
+	assert.Len(t, name, len("baetyl-function-config-new-svc-")+9)
+
+	name = a.cloneConfigName("baetyl-function-program-config-abc-svc-xxxxxxxxx", "abc", "new")
+	assert.True(t, strings.HasPrefix(name, "baetyl-function-program-config-new-svc-"))
+
+	assert.Equal(t, "user", a.cloneConfigName("user", "abc", "new"))
+
+	// the configured prefixes
+	a.conf = config.Facade{FunctionConfigPrefix: "fn", FunctionProgramConfigPrefix: "fn-prog"}
+	name = a.cloneConfigName("fn-prog-abc-svc-xxxxxxxxx", "abc", "new")
+	assert.True(t, strings.HasPrefix(name, "fn-prog-new-svc-"))
+	name = a.cloneConfigName("fn-abc-svc-xxxxxxxxx", "abc", "new")
+	assert.True(t, strings.HasPrefix(name, "fn-new-svc-"))
+	assert.Equal(t, "baetyl-function-config-abc-svc-xxxxxxxxx", a.cloneConfigName("baetyl-function-config-abc-svc-xxxxxxxxx", "abc", "new"))
+}
@@ -1,7 +1,10 @@
 package facade
 
 import (
+	"context"
+	"encoding/base64"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
@@ -10,27 +13,29 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/common"
 )
 
-func (a *facade) CreateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error) {
-	tx, errTx := a.txFactory.BeginTx()
-	if errTx != nil {
-		return nil, errTx
+func (a *facade) CreateConfig(ctx context.Context, ns string, config *specV1.Configuration) (*specV1.Configuration, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
 	}
-	var err error
-	defer func() {
-		if p := recover(); p != nil {
-			a.txFactory.Rollback(tx)
-			panic(p)
-		} else if err != nil {
-			a.txFactory.Rollback(tx)
-		} else {
-			a.txFactory.Commit(tx)
-		}
-	}()
-	config, err = a.config.Create(tx, ns, config)
-	return config, err
+	if err := a.checkConfigSize(config); err != nil {
+		return nil, err
+	}
+	var res *specV1.Configuration
+	err := a.runTx(ctx, ns, "CreateConfig", func(tx interface{}, _ *compensations) error {
+		var err error
+		res, err = a.config.Create(tx, ns, config)
+		return err
+	})
+	return res, err
 }
 
-func (a *facade) UpdateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error) {
+func (a *facade) UpdateConfig(ctx context.Context, ns string, config *specV1.Configuration) (*specV1.Configuration, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := a.checkConfigSize(config); err != nil {
+		return nil, err
+	}
 	var res *specV1.Configuration
 	var err error
 	res, err = a.config.Update(nil, ns, config)
@@ -46,19 +51,38 @@ func (a *facade) UpdateConfig(ns string, config *specV1.Configuration) (*specV1.
 		return nil, err
 	}
 
-	if err = a.updateNodeAndApp(ns, res, appNames); err != nil {
+	if err = a.updateNodeAndApp(ctx, ns, res, appNames); err != nil {
 		log.L().Error("update node and app failed", log.Error(err))
 		return nil, err
 	}
 	return res, err
 }
 
-func (a *facade) DeleteConfig(ns, name string) error {
+// DeleteConfig deletes the config which is referenced by no app
+func (a *facade) DeleteConfig(ctx context.Context, ns, name string) error {
+	if err := a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.Trace(err)
+	}
+	referenced, apps, err := a.config.IsReferenced(ns, name)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return common.Error(common.ErrConfigInUsed,
+			common.Field("name", name),
+			common.Field("apps", strings.Join(apps, ", ")))
+	}
 	return a.config.Delete(nil, ns, name)
 }
 
-func (a *facade) updateNodeAndApp(namespace string, config *specV1.Configuration, appNames []string) error {
+func (a *facade) updateNodeAndApp(ctx context.Context, namespace string, config *specV1.Configuration, appNames []string) error {
 	for _, appName := range appNames {
+		if err := ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
 		app, err := a.app.Get(namespace, appName, "")
 		if err != nil {
 			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
@@ -75,6 +99,9 @@ func (a *facade) updateNodeAndApp(namespace string, config *specV1.Configuration
 		if err != nil {
 			return err
 		}
+		if skipNodeIndex(app) {
+			continue
+		}
 		_, err = a.node.UpdateNodeAppVersion(nil, namespace, app)
 		if err != nil {
 			return err
@@ -96,3 +123,55 @@ func needUpdateApp(config *specV1.Configuration, app *specV1.Application) bool {
 	}
 	return appNeedUpdate
 }
+
+// checkConfigSize checks the effective size of the config against the limit before it's persisted,
+// so that the config exceeding the ConfigMap limit fails in the cloud instead of at sync time on the edge
+func (a *facade) checkConfigSize(config *specV1.Configuration) error {
+	limit := a.conf.ConfigSizeLimit
+	if limit <= 0 || config == nil {
+		return nil
+	}
+	if size := configSize(config); size > limit {
+		return common.Error(common.ErrConfigTooLarge,
+			common.Field("name", config.Name),
+			common.Field("size", size),
+			common.Field("limit", limit))
+	}
+	return nil
+}
+
+// configSize returns the size of the data of the config stored in a ConfigMap,
+// the values which aren't valid UTF-8 are binary data stored in base64
+func configSize(config *specV1.Configuration) int {
+	size := 0
+	for k, v := range config.Data {
+		size += len(k)
+		if utf8.ValidString(v) {
+			size += len(v)
+		} else {
+			size += base64.StdEncoding.EncodedLen(len(v))
+		}
+	}
+	return size
+}
+
+// externalizeConfigBlobs stores the values of the generated function config larger than ConfigBlobThreshold
+// in the blob store and returns the config referencing them, the config is returned as is if the store is disabled
+func (a *facade) externalizeConfigBlobs(tx interface{}, ns string, config *specV1.Configuration) (*specV1.Configuration, error) {
+	if a.blob == nil {
+		return config, nil
+	}
+	return a.blob.Externalize(tx, ns, config, a.conf.ConfigBlobThreshold)
+}
+
+// deleteGenConfig deletes the generated function config and releases the blobs it references,
+// the blobs are deleted with their last references
+func (a *facade) deleteGenConfig(tx interface{}, ns, name string) error {
+	if err := a.config.Delete(tx, ns, name); err != nil {
+		return err
+	}
+	if a.blob == nil {
+		return nil
+	}
+	return a.blob.Release(tx, ns, name)
+}
@@ -1,6 +1,7 @@
 package facade
 
 import (
+	"context"
 	"testing"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
@@ -23,15 +25,49 @@ func TestCreateConfig(t *testing.T) {
 		txFactory: mFacade.txFactory,
 	}
 	ns := "test"
-	mFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	mFacade.txFactory.EXPECT().Rollback(nil).Return().Times(1)
 	mFacade.sConfig.EXPECT().Create(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err := cfgFacade.CreateConfig(ns, nil)
+	_, err := cfgFacade.CreateConfig(context.Background(), ns, nil)
 	assert.Error(t, err, unknownErr)
 
 	mFacade.txFactory.EXPECT().Commit(nil).Return().Times(1)
 	mFacade.sConfig.EXPECT().Create(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
-	_, err = cfgFacade.CreateConfig(ns, nil)
+	_, err = cfgFacade.CreateConfig(context.Background(), ns, nil)
+	assert.NoError(t, err)
+}
+
+func TestConfigSizeLimit(t *testing.T) {
+	mFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	cfgFacade := &facade{
+		config:    mFacade.sConfig,
+		txFactory: mFacade.txFactory,
+		conf:      config.Facade{ConfigSizeLimit: 10},
+	}
+	ns := "test"
+	// the binary value of 6 bytes takes 8 bytes in base64
+	binary := &specV1.Configuration{Name: "bin", Data: map[string]string{"k": "\xff\xfe\xfd\xfc\xfb\xfa"}}
+	assert.Equal(t, 9, configSize(binary))
+	binary.Data["k2"] = "a"
+	_, err := cfgFacade.CreateConfig(context.Background(), ns, binary)
+	assert.Equal(t, common.ErrConfigTooLarge, err.(errors.Coder).Code())
+	assert.Contains(t, err.Error(), "is 12 bytes, which exceeds the limit (10 bytes)")
+	_, err = cfgFacade.UpdateConfig(context.Background(), ns, binary)
+	assert.Equal(t, common.ErrConfigTooLarge, err.(errors.Coder).Code())
+
+	// none of the generated configs is written if any of them is too large
+	mFacade.sConfig.EXPECT().Upsert(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	err = cfgFacade.updateGenConfigsOfFunctionApp(nil, ns, []specV1.Configuration{
+		{Name: "small", Data: map[string]string{"k": "v"}},
+		{Name: "large", Data: map[string]string{"k": "0123456789"}},
+	})
+	assert.Equal(t, common.ErrConfigTooLarge, err.(errors.Coder).Code())
+
+	mFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mFacade.txFactory.EXPECT().Commit(nil).Return()
+	mFacade.sConfig.EXPECT().Create(nil, ns, gomock.Any()).Return(nil, nil)
+	_, err = cfgFacade.CreateConfig(context.Background(), ns, &specV1.Configuration{Name: "small", Data: map[string]string{"k": "012345678"}})
 	assert.NoError(t, err)
 }
 
@@ -88,23 +124,23 @@ func TestUpdateConfig(t *testing.T) {
 	}
 
 	mFacade.sConfig.EXPECT().Update(nil, ns, gomock.Any()).Return(res, unknownErr).Times(1)
-	_, err := cfgFacade.UpdateConfig(ns, res3)
+	_, err := cfgFacade.UpdateConfig(context.Background(), ns, res3)
 	assert.Error(t, err, unknownErr)
 
 	mFacade.sConfig.EXPECT().Update(nil, ns, gomock.Any()).Return(res, nil).AnyTimes()
 	mFacade.sIndex.EXPECT().ListAppIndexByConfig(mConf2.Namespace, "abc").Return(nil, unknownErr).Times(1)
-	_, err = cfgFacade.UpdateConfig(ns, res3)
+	_, err = cfgFacade.UpdateConfig(context.Background(), ns, res3)
 	assert.Error(t, err, unknownErr)
 
 	appNames := make([]string, 0)
 	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(appNames, nil).Times(1)
-	_, err = cfgFacade.UpdateConfig(ns, res3)
+	_, err = cfgFacade.UpdateConfig(context.Background(), ns, res3)
 	assert.NoError(t, err)
 
 	appNames = []string{"app01", "app02"}
 	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(appNames, nil).Times(1)
 	mFacade.sApp.EXPECT().Get(ns, "app01", "").Return(nil, errors.New("err")).Times(1)
-	_, err = cfgFacade.UpdateConfig(ns, res3)
+	_, err = cfgFacade.UpdateConfig(context.Background(), ns, res3)
 	assert.Error(t, err, unknownErr)
 
 	apps := []*specV1.Application{
@@ -136,7 +172,7 @@ func TestUpdateConfig(t *testing.T) {
 	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(appNames, nil).Times(1)
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Get(ns, appNames[1], "").Return(apps[1], nil).Times(1)
-	_, err = cfgFacade.UpdateConfig(ns, res3)
+	_, err = cfgFacade.UpdateConfig(context.Background(), ns, res3)
 	assert.NoError(t, err)
 
 	appNames = []string{"app01"}
@@ -155,14 +191,14 @@ func TestUpdateConfig(t *testing.T) {
 	mFacade.sIndex.EXPECT().ListAppIndexByConfig(ns, name).Return(appNames, nil).AnyTimes()
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err = cfgFacade.UpdateConfig(ns, res3)
+	_, err = cfgFacade.UpdateConfig(context.Background(), ns, res3)
 	assert.Error(t, err, unknownErr)
 
 	apps[0].Volumes[0].Config.Version = "1"
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
 	mFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err = cfgFacade.UpdateConfig(ns, res3)
+	_, err = cfgFacade.UpdateConfig(context.Background(), ns, res3)
 	assert.Error(t, err, unknownErr)
 }
 
@@ -177,7 +213,20 @@ func TestDeleteConfig(t *testing.T) {
 		txFactory: mFacade.txFactory,
 	}
 	ns, n := "test", "test"
+	mFacade.sConfig.EXPECT().IsReferenced(ns, n).Return(false, nil, nil)
 	mFacade.sConfig.EXPECT().Delete(nil, ns, n).Return(nil)
-	err := cfgFacade.DeleteConfig(ns, n)
+	err := cfgFacade.DeleteConfig(context.Background(), ns, n)
 	assert.NoError(t, err)
+
+	// referenced
+	mFacade.sConfig.EXPECT().IsReferenced(ns, n).Return(true, []string{"app1", "app2"}, nil)
+	err = cfgFacade.DeleteConfig(context.Background(), ns, n)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrConfigInUsed, e.Code())
+	assert.Contains(t, err.Error(), "app1, app2")
+
+	mFacade.sConfig.EXPECT().IsReferenced(ns, n).Return(false, nil, unknownErr)
+	err = cfgFacade.DeleteConfig(context.Background(), ns, n)
+	assert.Equal(t, unknownErr, err)
 }
@@ -0,0 +1,641 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// newAppCron builds the cron of the app waiting for cron, the cron times are interpreted
+// in the timezone labeled on the app, and the absolute times are used if no timezone is labeled.
+// The cron fires on any of the cron time and the times labeled on the app, so the earliest is its cron time.
+// The missed times are handled by the misfire policy labeled on the app, which are skipped by default, and
+// the runs matching no nodes by the no-nodes policy labeled on the app, which are applied by default.
+// The selector is resolved by the selector resolver labeled on the app, which is the label selector by default.
+// The times are truncated to seconds, as the cron store keeps them, so that the cron read back is the same.
+func newAppCron(app *specV1.Application) (*models.Cron, error) {
+	tz := app.Labels[common.LabelCronTimezone]
+	walls, err := appCronTimes(app)
+	if err != nil {
+		return nil, err
+	}
+	var times []time.Time
+	for _, wall := range walls {
+		t, err := cronTimeIn(wall, tz)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t.Truncate(time.Second))
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	policy := app.Labels[common.LabelCronMisfirePolicy]
+	if !models.ValidMisfirePolicy(policy) {
+		return nil, common.Error(common.ErrInvalidCron,
+			common.Field("name", app.Name),
+			common.Field("error", fmt.Sprintf("the misfire policy (%s) is unknown", policy)))
+	}
+	overlap := app.Labels[common.LabelCronOverlapPolicy]
+	if !models.ValidOverlapPolicy(overlap) {
+		return nil, common.Error(common.ErrInvalidCron,
+			common.Field("name", app.Name),
+			common.Field("error", fmt.Sprintf("the overlap policy (%s) is unknown", overlap)))
+	}
+	noNodes := app.Labels[common.LabelCronNoNodesPolicy]
+	if !models.ValidNoNodesPolicy(noNodes) {
+		return nil, common.Error(common.ErrInvalidCron,
+			common.Field("name", app.Name),
+			common.Field("error", fmt.Sprintf("the no-nodes policy (%s) is unknown", noNodes)))
+	}
+	cronApp := &models.Cron{
+		Name:          app.Name,
+		Namespace:     app.Namespace,
+		Selector:      app.Selector,
+		CronTime:      times[0],
+		Timezone:      tz,
+		MisfirePolicy: policy,
+		OverlapPolicy: overlap,
+		NoNodesPolicy: noNodes,
+		// the resolver is checked by the facade, since the resolvers are set to the facade
+		SelectorResolver: app.Labels[common.LabelCronSelectorResolver],
+	}
+	if len(times) > 1 {
+		cronApp.CronTimes = times
+	}
+	return cronApp, nil
+}
+
+// appCronTimes returns the cron time of the app followed by the distinct times labeled on the app
+func appCronTimes(app *specV1.Application) ([]time.Time, error) {
+	res := []time.Time{app.CronTime}
+	v, ok := app.Labels[common.LabelCronTimes]
+	if !ok {
+		return res, nil
+	}
+	for _, s := range strings.Split(v, ",") {
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(s))
+		if err != nil || t.IsZero() {
+			return nil, common.Error(common.ErrInvalidCron,
+				common.Field("name", app.Name),
+				common.Field("error", fmt.Sprintf("the cron time (%s) isn't in RFC3339", s)))
+		}
+		dup := false
+		for _, r := range res {
+			if r.Equal(t) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+// cronTimeIn returns the UTC time of the wall clock of t in the timezone
+func cronTimeIn(t time.Time, tz string) (time.Time, error) {
+	if tz == "" {
+		return t.UTC(), nil
+	}
+	// Local depends on the server, which is exactly what the timezone is used to avoid
+	loc, err := time.LoadLocation(tz)
+	if err != nil || tz == "Local" {
+		return time.Time{}, common.Error(common.ErrInvalidCronTimezone, common.Field("timezone", tz))
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc).UTC(), nil
+}
+
+// validAppCron checks the cron of the app waiting for cron before any transaction work begins,
+// the cron is scheduled by the cron time and the times labeled on the app, all of which must be set
+// and, if future is set, after now
+func validAppCron(app *specV1.Application, future bool) error {
+	if app.CronStatus != specV1.CronWait {
+		return nil
+	}
+	cronApp, err := newAppCron(app)
+	if err != nil {
+		return err
+	}
+	if app.CronTime.IsZero() {
+		return common.Error(common.ErrInvalidCron,
+			common.Field("name", app.Name),
+			common.Field("error", "the cron time is empty"))
+	}
+	if future && !cronApp.CronTime.After(time.Now()) {
+		return common.Error(common.ErrInvalidCron,
+			common.Field("name", app.Name),
+			common.Field("error", fmt.Sprintf("the cron time (%s) should be after now", cronApp.CronTime.Format(time.RFC3339))))
+	}
+	return nil
+}
+
+// PauseCronApp pauses the cron of the app, the app keeps waiting for cron but the cron is not fired until resumed
+func (a *facade) PauseCronApp(ctx context.Context, ns, name string) error {
+	if err := a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	return a.setCronAppPaused(ctx, ns, name, true)
+}
+
+// ResumeCronApp resumes the paused cron of the app with the same schedule
+func (a *facade) ResumeCronApp(ctx context.Context, ns, name string) error {
+	if err := a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	return a.setCronAppPaused(ctx, ns, name, false)
+}
+
+func (a *facade) setCronAppPaused(ctx context.Context, ns, name string, paused bool) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Trace(err)
+	}
+	app, err := a.app.Get(ns, name, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if app.CronStatus != specV1.CronWait {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the app (%s) is not waiting for cron", name)))
+	}
+	return errors.Trace(a.cron.SetCronPaused(name, ns, paused))
+}
+
+func withCronPausedLabel(labels map[string]string, paused bool) map[string]string {
+	if !paused {
+		delete(labels, common.LabelCronPaused)
+		return labels
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[common.LabelCronPaused] = "true"
+	return labels
+}
+
+// cronNextRunsShown the number of the upcoming times of the cron previewed on the app read
+const cronNextRunsShown = 5
+
+// withCronNextRunsLabel labels the upcoming times of the cron on the app read so that its schedule
+// can be previewed, the label is removed if the cron has no upcoming times, e.g. it's paused
+func withCronNextRunsLabel(labels map[string]string, cronApp *models.Cron) map[string]string {
+	runs := service.CronNextRuns(cronApp, time.Now(), cronNextRunsShown)
+	if len(runs) == 0 {
+		delete(labels, common.LabelCronNextRuns)
+		return labels
+	}
+	vs := make([]string, 0, len(runs))
+	for _, t := range runs {
+		vs = append(vs, t.Format(time.RFC3339))
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[common.LabelCronNextRuns] = strings.Join(vs, ",")
+	return labels
+}
+
+const cronRunLockPrefix = "baetyl-cron-run-"
+
+// cronSkipWait how long the run of the cron with OverlapSkip waits for the previous run before it's skipped
+var cronSkipWait = time.Second
+
+// lockCronRun locks the run of the cron of the app according to its overlap policy, the lock should be held
+// until the run is applied. The run with OverlapQueue waits until the previous run releases the lock, while
+// the run with OverlapSkip gives up with ErrCronRunning if the lock isn't acquired in cronSkipWait.
+func (a *facade) lockCronRun(ctx context.Context, cronApp *models.Cron) (func(), error) {
+	if cronApp.OverlapPolicy != models.OverlapSkip && cronApp.OverlapPolicy != models.OverlapQueue {
+		return func() {}, nil
+	}
+	name := cronRunLockPrefix + cronApp.Namespace + "/" + cronApp.Name
+	lockCtx := ctx
+	if cronApp.OverlapPolicy == models.OverlapSkip {
+		var cancel context.CancelFunc
+		lockCtx, cancel = context.WithTimeout(ctx, cronSkipWait)
+		defer cancel()
+	}
+	version, err := a.locker.Lock(lockCtx, name, 0)
+	if err != nil {
+		if lockCtx.Err() != nil && ctx.Err() == nil {
+			return nil, common.Error(common.ErrCronRunning, common.Field("name", cronApp.Name))
+		}
+		return nil, errors.Trace(err)
+	}
+	return func() { a.locker.Unlock(ctx, name, version) }, nil
+}
+
+// TriggerCronApp deploys the app waiting for cron to the nodes matched by the selector of its cron
+// immediately, as the cron would do when fired. The cron is left intact even if it is paused,
+// and the nodes the app is deployed to are returned. The run is serialized with the other runs of
+// the app by the overlap policy of the cron. The run of the cron with NoNodesSkip whose selector matches
+// no nodes is skipped with ErrCronNoNodes and recorded as failed, instead of deploying to no nodes.
+func (a *facade) TriggerCronApp(ctx context.Context, ns, name string) (*specV1.Application, []string, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	app, err := a.app.Get(ns, name, "")
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if app.CronStatus != specV1.CronWait {
+		return nil, nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the app (%s) is not waiting for cron", name)))
+	}
+	cronApp, err := a.cron.GetCron(nil, name, ns)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	app, nodes, err := a.runCronApp(ctx, app, cronApp, models.CronRunManual, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	app.Selector = cronApp.Selector
+	a.log.Info("cron app triggered manually",
+		log.Any("audit", "triggerCronApp"),
+		log.Any(common.KeyContextNamespace, ns),
+		log.Any("name", name),
+		log.Any("selector", cronApp.Selector),
+		log.Any("paused", cronApp.Paused),
+		log.Any("nodes", nodes))
+	return app, nodes, nil
+}
+
+// runCronApp deploys the app waiting for cron to the nodes of the selector of its cron in a transaction, serialized
+// with the other runs of the app by the overlap policy of the cron, and records the run by the trigger. The app is
+// updated within the run to keep the nodes it's deployed to once its cron is gone (see firedCronApp), and it's
+// finished by CronFinished if finish. The app updated and the nodes are returned.
+func (a *facade) runCronApp(ctx context.Context, app *specV1.Application, cronApp *models.Cron, trigger string, finish bool) (*specV1.Application, []string, error) {
+	unlock, err := a.lockCronRun(ctx, cronApp)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+	if err = a.checkCronNodes(ctx, app, cronApp); err != nil {
+		a.recordFailedCronRun(app, trigger, err)
+		return nil, nil, err
+	}
+
+	resolved := resolvesCronNodes(app, cronApp)
+	var res *specV1.Application
+	var nodes []string
+	err = a.runTx(ctx, app.Namespace, "RunCronApp", func(tx interface{}, undo *compensations) error {
+		fired, err := a.app.Update(tx, app.Namespace, firedCronApp(app, cronApp, resolved, finish))
+		if err != nil {
+			return err
+		}
+		if nodes, err = a.fireCronApp(ctx, tx, fired, cronApp, resolved, undo); err != nil {
+			return err
+		}
+		// the run is rolled back instead of committed once ctx is done, e.g. the lease of the cron leader is lost
+		if err = ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		res = fired
+		return a.recordCronRun(tx, fired, trigger, nodes, nil)
+	})
+	if err != nil {
+		a.recordFailedCronRun(app, trigger, err)
+		return nil, nil, err
+	}
+	return res, nodes, nil
+}
+
+// firedCronApp returns the copy of the app fired by its cron to be stored. It carries the selector of the cron so
+// that the node indexes refreshed later keep the nodes it's deployed to, while the nodes resolved by any resolver
+// other than the label one are kept by LabelSkipNodeIndex once the app is finished, since the selector isn't a
+// label selector then.
+func firedCronApp(app *specV1.Application, cronApp *models.Cron, resolved, finish bool) *specV1.Application {
+	res := *app
+	if !resolved {
+		res.Selector = cronApp.Selector
+	} else if finish {
+		res.Labels = make(map[string]string, len(app.Labels)+1)
+		for k, v := range app.Labels {
+			res.Labels[k] = v
+		}
+		res.Labels[common.LabelSkipNodeIndex] = "true"
+	}
+	if finish {
+		res.CronStatus = specV1.CronFinished
+	}
+	return &res
+}
+
+// resolvesCronNodes checks the app is deployed to the nodes resolved from the selector of its cron by a resolver
+// other than the label one
+func resolvesCronNodes(app *specV1.Application, cronApp *models.Cron) bool {
+	return !skipNodeIndex(app) && cronApp.SelectorResolver != "" && cronApp.SelectorResolver != SelectorResolverLabel
+}
+
+// FireDueCrons fires the expired crons as the leader of the cron scheduler, nothing is done if another replica
+// leads. Each cron is fired once for each of its times resolved by its misfire policy (see service.CronFirings),
+// and the runs are recorded as scheduled. The apps fired by the last times of their crons are finished by
+// CronFinished, keeping the nodes they're deployed to once their crons are gone. The crons fired, including the ones whose missed times are all skipped,
+// are completed by DeleteExpiredApps, while the crons failed to fire are left to be fired next time.
+// All the crons are tried and the first failure is returned.
+func (a *facade) FireDueCrons(ctx context.Context) error {
+	err := a.LeadCron(ctx, a.fireDueCrons)
+	if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrNotLeader {
+		return nil
+	}
+	return err
+}
+
+func (a *facade) fireDueCrons(ctx context.Context) error {
+	crons, err := a.cron.ListExpiredApps()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	now := time.Now()
+	var fired []uint64
+	var firstErr error
+	for i := range crons {
+		c := &crons[i]
+		if err = ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		if err = a.fireDueCron(ctx, c, now); err != nil {
+			a.log.Warn("failed to fire the cron app",
+				log.Any(common.KeyContextNamespace, c.Namespace),
+				log.Any("name", c.Name),
+				log.Error(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		fired = append(fired, c.Id)
+	}
+	if len(fired) > 0 {
+		if err = a.cron.DeleteExpiredApps(fired); err != nil && firstErr == nil {
+			firstErr = errors.Trace(err)
+		}
+	}
+	return firstErr
+}
+
+// fireDueCron runs the app of the cron once for each of its firings at now, and the app is finished by the last run
+// if the cron has no more times. The cron of the app missing or no longer waiting for cron is completed without any
+// run, so is the run skipped since the selector matches no nodes.
+func (a *facade) fireDueCron(ctx context.Context, cronApp *models.Cron, now time.Time) error {
+	firings := service.CronFirings(cronApp, now, a.conf.CronScheduler.MisfireThreshold)
+	if len(firings) == 0 {
+		return nil
+	}
+	app, err := a.app.Get(cronApp.Namespace, cronApp.Name, "")
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	if app.CronStatus != specV1.CronWait {
+		return nil
+	}
+	finished := true
+	for _, t := range cronApp.Schedules() {
+		if t.After(now) {
+			finished = false
+		}
+	}
+	for i := range firings {
+		fired, _, err := a.runCronApp(ctx, app, cronApp, models.CronRunScheduled, finished && i == len(firings)-1)
+		if err != nil {
+			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrCronNoNodes {
+				continue
+			}
+			return err
+		}
+		app = fired
+	}
+	return nil
+}
+
+// checkCronNodes checks the selector of the cron with NoNodesSkip matches any node before the run is applied,
+// ErrCronNoNodes is returned otherwise
+func (a *facade) checkCronNodes(ctx context.Context, app *specV1.Application, cronApp *models.Cron) error {
+	if cronApp.NoNodesPolicy != models.NoNodesSkip || skipNodeIndex(app) {
+		return nil
+	}
+	nodes, err := a.resolveCronNodes(ctx, cronApp)
+	if err != nil {
+		return err
+	}
+	if len(nodes) > 0 {
+		return nil
+	}
+	a.log.Warn("the run of the cron app is skipped since its selector matches no nodes",
+		log.Any(common.KeyContextNamespace, cronApp.Namespace),
+		log.Any("name", cronApp.Name),
+		log.Any("selector", cronApp.Selector))
+	return common.Error(common.ErrCronNoNodes,
+		common.Field("name", cronApp.Name),
+		common.Field("selector", cronApp.Selector))
+}
+
+// fireCronApp deploys the app to the nodes matched by the label selector of its cron, or to the nodes resolved
+// from the selector by the resolver of the cron at the time it fires
+func (a *facade) fireCronApp(ctx context.Context, tx interface{}, app *specV1.Application, cronApp *models.Cron, resolved bool, undo *compensations) ([]string, error) {
+	if !resolved {
+		return a.updateNodeAndAppIndex(tx, app.Namespace, app, undo)
+	}
+	nodes, err := a.resolveCronNodes(ctx, cronApp)
+	if err != nil {
+		return nil, err
+	}
+	return nodes, a.updateResolvedNodeAndAppIndex(tx, app.Namespace, app, nodes, undo)
+}
+
+// recordCronRun records the run of the cron of the app within the transaction of the run if the runs are recorded,
+// so that the run applied is recorded if and only if it's committed
+func (a *facade) recordCronRun(tx interface{}, app *specV1.Application, trigger string, nodes []string, runErr error) error {
+	if !a.conf.CronRuns {
+		return nil
+	}
+	run := &models.CronRun{
+		Namespace: app.Namespace,
+		Name:      app.Name,
+		Version:   app.Version,
+		Trigger:   trigger,
+		Nodes:     len(nodes),
+		Success:   runErr == nil,
+		RunTime:   time.Now().UTC(),
+	}
+	if runErr != nil {
+		run.Message = runErr.Error()
+	}
+	if err := a.cron.CreateCronRun(tx, run); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// recordFailedCronRun records the run failed after its transaction is rolled back, the failure to record it
+// is only logged so that the error of the run is returned
+func (a *facade) recordFailedCronRun(app *specV1.Application, trigger string, runErr error) {
+	if err := a.recordCronRun(nil, app, trigger, nil, runErr); err != nil {
+		a.log.Error("failed to record the failed run of the cron app",
+			log.Any("namespace", app.Namespace), log.Any("name", app.Name), log.Error(err))
+	}
+}
+
+// ListCronRuns lists the runs of the cron of the app page by page (pageNo and pageSize of opt) from the latest,
+// the runs are kept after the app is deleted so that the scheduled deployments can still be audited
+func (a *facade) ListCronRuns(ctx context.Context, ns, name string, opt *models.ListOptions) (*models.CronRunList, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if !a.conf.CronRuns {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the runs of the cron apps are not recorded"))
+	}
+	if opt == nil {
+		opt = &models.ListOptions{}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	runs, total, err := a.cron.ListCronRuns(ns, name, &opt.Filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if runs == nil {
+		runs = []models.CronRun{}
+	}
+	return &models.CronRunList{
+		Total:       total,
+		ListOptions: opt,
+		Items:       runs,
+	}, nil
+}
+
+// the reasons of the cron issues
+const (
+	// CronMissingApp the cron is of an app which doesn't exist, e.g. the cron is written but the app write fails
+	CronMissingApp = "missingApp"
+	// CronNotWaiting the cron is of an app which doesn't wait for cron, so the cron is never applied to it
+	CronNotWaiting = "notWaiting"
+)
+
+// CronIssue a cron inconsistent with the current version of its app
+type CronIssue struct {
+	App      string `json:"app"`
+	Selector string `json:"selector"`
+	Reason   string `json:"reason"`
+}
+
+// CronReport the result of checking the crons of a namespace against their apps
+type CronReport struct {
+	Namespace string      `json:"namespace"`
+	Crons     int         `json:"crons"`
+	Issues    []CronIssue `json:"issues"`
+}
+
+// VerifyCronApps walks the crons of the namespace page by page, and reports the crons left without an app
+// waiting for them, since the crons are written out of the transaction of the app
+func (a *facade) VerifyCronApps(ctx context.Context, ns string) (*CronReport, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	return a.checkCronApps(ctx, ns, false)
+}
+
+// RepairCronApps reports the crons left as VerifyCronApps, and deletes them once all the pages are checked.
+// Each cron is deleted with its app locked and rechecked in its own transaction, so it is safe to run on a live system.
+func (a *facade) RepairCronApps(ctx context.Context, ns string) (*CronReport, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	return a.checkCronApps(ctx, ns, true)
+}
+
+func (a *facade) checkCronApps(ctx context.Context, ns string, repair bool) (*CronReport, error) {
+	report := &CronReport{Namespace: ns, Issues: []CronIssue{}}
+	filter := &models.Filter{PageNo: 1, PageSize: a.conf.IndexPageSize}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		crons, total, err := a.cron.ListCronsPage(ns, filter)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		report.Crons += len(crons)
+		names := make([]string, 0, len(crons))
+		for _, c := range crons {
+			names = append(names, c.Name)
+		}
+		apps, err := a.app.GetBatch(ns, names)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		found := make(map[string]*specV1.Application, len(apps))
+		for _, app := range apps {
+			found[app.Name] = app
+		}
+		for _, c := range crons {
+			if reason := cronIssueOf(found[c.Name]); reason != "" {
+				report.Issues = append(report.Issues, CronIssue{App: c.Name, Selector: c.Selector, Reason: reason})
+			}
+		}
+		if len(crons) == 0 || filter.PageSize <= 0 || filter.PageNo*filter.PageSize >= total {
+			break
+		}
+		filter.PageNo++
+	}
+
+	// the crons are deleted after the walk so that the pages aren't shifted
+	if repair {
+		for _, issue := range report.Issues {
+			if err := a.repairCronApp(ctx, ns, issue.App); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return report, nil
+}
+
+// cronIssueOf returns the reason the cron of the app is inconsistent with it, empty if consistent
+func cronIssueOf(app *specV1.Application) string {
+	switch {
+	case app == nil:
+		return CronMissingApp
+	case app.CronStatus != specV1.CronWait:
+		return CronNotWaiting
+	default:
+		return ""
+	}
+}
+
+// repairCronApp deletes the cron of the app if it's still inconsistent with the app, which may be rewritten since checked
+func (a *facade) repairCronApp(ctx context.Context, ns, name string) error {
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return a.runTx(ctx, ns, "RepairCronApps", func(tx interface{}, _ *compensations) error {
+		app, err := a.app.Get(ns, name, "")
+		if err != nil && !isNotFound(err) {
+			return errors.Trace(err)
+		}
+		if cronIssueOf(app) == "" {
+			return nil
+		}
+		a.log.Warn("the cron left without its app is deleted",
+			log.Any(common.KeyContextNamespace, ns), log.Any("name", name))
+		return a.dropAppCron(ctx, tx, ns, name)
+	})
+}
@@ -0,0 +1,741 @@
+package facade
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestCronTimeIn(t *testing.T) {
+	wall := time.Date(2021, 10, 1, 8, 0, 0, 0, time.UTC)
+
+	res, err := cronTimeIn(wall, "")
+	assert.NoError(t, err)
+	assert.Equal(t, wall, res)
+
+	res, err = cronTimeIn(wall, "Asia/Shanghai")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC), res)
+
+	_, err = cronTimeIn(wall, "Mars/Olympus")
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrInvalidCronTimezone, e.Code())
+
+	_, err = cronTimeIn(wall, "Local")
+	assert.Error(t, err)
+}
+
+func TestCreateApplicationCronTimezone(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	app := &specV1.Application{
+		Namespace:  ns,
+		Name:       "abc",
+		Selector:   "a=b",
+		CronStatus: specV1.CronWait,
+		CronTime:   time.Date(2099, 10, 1, 8, 0, 0, 0, time.UTC),
+		Labels:     map[string]string{common.LabelCronTimezone: "Unknown/Zone"},
+	}
+	// rejected before any write
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.Error(t, err)
+
+	app.Labels[common.LabelCronTimezone] = "Asia/Shanghai"
+	mAppFacade.sCron.EXPECT().CreateCron(&models.Cron{
+		Namespace: ns,
+		Name:      "abc",
+		Selector:  "a=b",
+		CronTime:  time.Date(2099, 10, 1, 0, 0, 0, 0, time.UTC),
+		Timezone:  "Asia/Shanghai",
+	}).Return(nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.NoError(t, err)
+}
+
+func TestValidAppCron(t *testing.T) {
+	app := &specV1.Application{Name: "abc"}
+	assert.NoError(t, validAppCron(app, true))
+
+	// empty
+	app.CronStatus = specV1.CronWait
+	err := validAppCron(app, true)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrInvalidCron, err.(errors.Coder).Code())
+
+	// out of range
+	app.CronTime = time.Now().Add(-time.Minute)
+	err = validAppCron(app, true)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrInvalidCron, err.(errors.Coder).Code())
+	assert.NoError(t, validAppCron(app, false))
+
+	// valid
+	app.CronTime = time.Now().Add(time.Minute)
+	assert.NoError(t, validAppCron(app, true))
+
+	app.Labels = map[string]string{common.LabelCronTimezone: "Unknown/Zone"}
+	err = validAppCron(app, true)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrInvalidCronTimezone, err.(errors.Coder).Code())
+}
+
+func TestNewAppCronSchedules(t *testing.T) {
+	weekday := time.Date(2099, 10, 2, 8, 0, 0, 0, time.UTC)
+	app := &specV1.Application{Name: "abc", Selector: "a=b", CronTime: weekday}
+
+	// the single cron time is kept as it is
+	res, err := newAppCron(app)
+	assert.NoError(t, err)
+	assert.Equal(t, weekday, res.CronTime)
+	assert.Nil(t, res.CronTimes)
+	assert.Equal(t, []time.Time{weekday}, res.Schedules())
+
+	// the cron time is truncated to seconds as it's stored
+	app.CronTime = weekday.Add(time.Millisecond)
+	res, err = newAppCron(app)
+	assert.NoError(t, err)
+	assert.Equal(t, weekday, res.CronTime)
+	app.CronTime = weekday
+
+	// the cron fires on the earliest time first, the duplicated times are merged
+	app.Labels = map[string]string{
+		common.LabelCronTimezone: "Asia/Shanghai",
+		common.LabelCronTimes:    "2099-10-03T10:00:00Z, 2099-10-01T10:00:00Z,2099-10-02T08:00:00Z",
+	}
+	res, err = newAppCron(app)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2099, 10, 1, 2, 0, 0, 0, time.UTC), res.CronTime)
+	assert.Equal(t, []time.Time{
+		time.Date(2099, 10, 1, 2, 0, 0, 0, time.UTC),
+		time.Date(2099, 10, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2099, 10, 3, 2, 0, 0, 0, time.UTC),
+	}, res.Schedules())
+
+	app.Labels[common.LabelCronTimes] = "2099-10-03 10:00"
+	_, err = newAppCron(app)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrInvalidCron, err.(errors.Coder).Code())
+
+	// the misfire policy is kept by the cron
+	app.Labels[common.LabelCronTimes] = "2099-10-03T10:00:00Z"
+	app.Labels[common.LabelCronMisfirePolicy] = models.MisfireFireOnce
+	res, err = newAppCron(app)
+	assert.NoError(t, err)
+	assert.Equal(t, models.MisfireFireOnce, res.MisfirePolicy)
+	app.Labels[common.LabelCronMisfirePolicy] = "fireTwice"
+	_, err = newAppCron(app)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrInvalidCron, err.(errors.Coder).Code())
+	delete(app.Labels, common.LabelCronMisfirePolicy)
+
+	app.Labels[common.LabelCronOverlapPolicy] = models.OverlapSkip
+	res, err = newAppCron(app)
+	assert.NoError(t, err)
+	assert.Equal(t, models.OverlapSkip, res.OverlapPolicy)
+	app.Labels[common.LabelCronOverlapPolicy] = "wait"
+	_, err = newAppCron(app)
+	assert.Error(t, err)
+	delete(app.Labels, common.LabelCronOverlapPolicy)
+
+	// the selector is kept as the query of the resolver
+	app.Labels[common.LabelCronSelectorResolver] = SelectorResolverApp
+	res, err = newAppCron(app)
+	assert.NoError(t, err)
+	assert.Equal(t, SelectorResolverApp, res.SelectorResolver)
+	assert.Equal(t, "a=b", res.Selector)
+	delete(app.Labels, common.LabelCronSelectorResolver)
+
+	app.Labels[common.LabelCronNoNodesPolicy] = models.NoNodesSkip
+	res, err = newAppCron(app)
+	assert.NoError(t, err)
+	assert.Equal(t, models.NoNodesSkip, res.NoNodesPolicy)
+	app.Labels[common.LabelCronNoNodesPolicy] = "abort"
+	_, err = newAppCron(app)
+	assert.Equal(t, common.ErrInvalidCron, err.(errors.Coder).Code())
+	delete(app.Labels, common.LabelCronNoNodesPolicy)
+
+	// any of the times passed is rejected
+	app.CronStatus = specV1.CronWait
+	app.Labels[common.LabelCronTimes] = time.Now().Add(-time.Minute).Format(time.RFC3339)
+	delete(app.Labels, common.LabelCronTimezone)
+	err = validAppCron(app, true)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrInvalidCron, err.(errors.Coder).Code())
+	assert.NoError(t, validAppCron(app, false))
+}
+
+func TestCreateApplicationInvalidCron(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:       mAppFacade.sApp,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Namespace: ns, Name: "abc", CronStatus: specV1.CronWait}
+
+	// no transaction is began
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.Error(t, err)
+	_, err = appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{{App: app}})
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrAppValidation, err.(errors.Coder).Code())
+	assert.Equal(t, common.ErrInvalidCron, err.(*ValidationError).Errors[0].Code)
+	_, err = appFacade.UpdateApp(context.Background(), ns, &specV1.Application{Name: "abc"}, app, nil)
+	assert.Error(t, err)
+}
+
+func TestPauseResumeCronApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:  mAppFacade.sApp,
+		cron: mAppFacade.sCron,
+	}
+	ns, name := "baetyl-cloud", "abc"
+	app := &specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil).AnyTimes()
+	mAppFacade.sCron.EXPECT().SetCronPaused(name, ns, true).Return(nil)
+	err := appFacade.PauseCronApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+
+	// the paused state is reflected by the label
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "a=b", Paused: true}, nil)
+	res, err := appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "a=b", res.Selector)
+	assert.Equal(t, "true", res.Labels[common.LabelCronPaused])
+
+	mAppFacade.sCron.EXPECT().SetCronPaused(name, ns, false).Return(nil)
+	err = appFacade.ResumeCronApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "a=b"}, nil)
+	res, err = appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	_, ok := res.Labels[common.LabelCronPaused]
+	assert.False(t, ok)
+
+	// not waiting for cron
+	mAppFacade.sApp.EXPECT().Get(ns, "other", "").Return(&specV1.Application{Namespace: ns, Name: "other"}, nil)
+	err = appFacade.PauseCronApp(context.Background(), ns, "other")
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
+
+func TestGetCronAppNextRuns(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:  mAppFacade.sApp,
+		cron: mAppFacade.sCron,
+	}
+	ns, name := "baetyl-cloud", "abc"
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").DoAndReturn(func(_, _, _ string) (*specV1.Application, error) {
+		return &specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}, nil
+	}).AnyTimes()
+
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	assert.NoError(t, err)
+	now := time.Now().Truncate(time.Second)
+	var times []time.Time
+	for i := 7; i >= 1; i-- {
+		times = append(times, now.Add(time.Duration(i)*time.Hour))
+	}
+	times = append(times, now.Add(-time.Hour))
+	cronApp := &models.Cron{Name: name, Namespace: ns, CronTime: now.Add(-time.Hour), CronTimes: times, Timezone: "Asia/Shanghai"}
+
+	// the earliest upcoming times are previewed in the timezone of the cron
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(cronApp, nil)
+	res, err := appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	var expected []string
+	for i := 1; i <= cronNextRunsShown; i++ {
+		expected = append(expected, now.Add(time.Duration(i)*time.Hour).In(loc).Format(time.RFC3339))
+	}
+	assert.Equal(t, strings.Join(expected, ","), res.Labels[common.LabelCronNextRuns])
+
+	// the paused cron has no upcoming times
+	paused := *cronApp
+	paused.Paused = true
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&paused, nil)
+	res, err = appFacade.GetApp(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	_, ok := res.Labels[common.LabelCronNextRuns]
+	assert.False(t, ok)
+}
+
+func TestTriggerCronAppOverlap(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		locker:    mAppFacade.sLocker,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns, name := "baetyl-cloud", "abc"
+	lock := cronRunLockPrefix + ns + "/" + name
+	origin := cronSkipWait
+	cronSkipWait = 10 * time.Millisecond
+	defer func() { cronSkipWait = origin }()
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// the run is queued and holds the lock until applied
+	app := &specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "a=b", OverlapPolicy: models.OverlapQueue}, nil)
+	gomock.InOrder(
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v1", nil),
+		mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(app, nil),
+		mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil),
+		mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, []string{"n1"}).Return(nil),
+		mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v1"),
+	)
+	_, nodes, err := appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, nodes)
+
+	// the run is skipped while the previous run holds the lock
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "a=b", OverlapPolicy: models.OverlapSkip}, nil)
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).DoAndReturn(
+		func(ctx context.Context, _ string, _ int64) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+	_, _, err = appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrCronRunning, err.(errors.Coder).Code())
+
+	// the failure of the locker isn't taken as running
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "a=b", OverlapPolicy: models.OverlapSkip}, nil)
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("", unknownErr)
+	_, _, err = appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.Error(t, err)
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+}
+
+func TestTriggerCronApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns, name := "baetyl-cloud", "abc"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// paused cron is triggered too and left intact
+	app := &specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "a=b", Paused: true}, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1", "n2"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, []string{"n1", "n2"}).Return(nil)
+	res, nodes, err := appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, "a=b", res.Selector)
+	assert.Equal(t, []string{"n1", "n2"}, nodes)
+
+	// deploy failed
+	app = &specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "a=b"}, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return(nil, unknownErr)
+	_, _, err = appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.Equal(t, unknownErr, err)
+
+	// not waiting for cron
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name}, nil)
+	_, _, err = appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.Error(t, err)
+}
+
+func TestTriggerCronAppNoNodes(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{CronRuns: true},
+		log:       log.L(),
+	}
+	ns, name := "baetyl-cloud", "abc"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	cronApp := func(policy string) *models.Cron {
+		return &models.Cron{Name: name, Namespace: ns, Selector: "a=b", NoNodesPolicy: policy}
+	}
+
+	// the run matching no nodes is skipped and recorded as failed
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(cronApp(models.NoNodesSkip), nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nil, nil)
+	mAppFacade.sCron.EXPECT().CreateCronRun(nil, gomock.Any()).DoAndReturn(func(_ interface{}, run *models.CronRun) error {
+		assert.False(t, run.Success)
+		assert.Contains(t, run.Message, "matches no nodes")
+		return nil
+	})
+	_, _, err := appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.Equal(t, common.ErrCronNoNodes, err.(errors.Coder).Code())
+
+	// the run matching nodes is applied
+	app := &specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(cronApp(models.NoNodesSkip), nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1"}, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, []string{"n1"}).Return(nil)
+	mAppFacade.sCron.EXPECT().CreateCronRun(nil, gomock.Any()).Return(nil)
+	_, nodes, err := appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, nodes)
+
+	// the run of the cron proceeding matches no nodes without the check
+	app = &specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(cronApp(""), nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, []string{}).Return(nil)
+	mAppFacade.sCron.EXPECT().CreateCronRun(nil, gomock.Any()).Return(nil)
+	_, nodes, err = appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Empty(t, nodes)
+}
+
+func TestFireDueCrons(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:        mAppFacade.sNode,
+		app:         mAppFacade.sApp,
+		index:       mAppFacade.sIndex,
+		cron:        mAppFacade.sCron,
+		locker:      mAppFacade.sLocker,
+		leaseHolder: "r1",
+		txFactory:   mAppFacade.txFactory,
+		conf:        config.Facade{CronRuns: true},
+		log:         log.L(),
+	}
+	appFacade.conf.CronLease.TTL = 30 * time.Second
+	appFacade.conf.CronLease.RenewInterval = time.Minute
+	appFacade.conf.CronScheduler.MisfireThreshold = time.Minute
+	ns := "baetyl-cloud"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// another replica leads
+	mAppFacade.sLocker.EXPECT().Acquire(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(false, nil)
+	assert.NoError(t, appFacade.FireDueCrons(context.Background()))
+
+	now := time.Now()
+	missed := []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)}
+	crons := []models.Cron{
+		{Id: 1, Name: "skip", Namespace: ns, Selector: "a=b", MisfirePolicy: models.MisfireSkip, CronTimes: missed},
+		{Id: 2, Name: "once", Namespace: ns, Selector: "a=b", MisfirePolicy: models.MisfireFireOnce, CronTimes: missed},
+		{Id: 3, Name: "all", Namespace: ns, Selector: "a=b", MisfirePolicy: models.MisfireFireAll, CronTimes: missed},
+		{Id: 4, Name: "ontime", Namespace: ns, Selector: "a=b", MisfirePolicy: models.MisfireSkip,
+			CronTimes: []time.Time{now.Add(-time.Hour), now.Add(-time.Second), now.Add(time.Hour)}},
+		{Id: 5, Name: "failed", Namespace: ns, Selector: "a=b", MisfirePolicy: models.MisfireFireAll, CronTimes: missed},
+		{Id: 6, Name: "deleted", Namespace: ns, Selector: "a=b", MisfirePolicy: models.MisfireFireAll, CronTimes: missed},
+	}
+	mAppFacade.sLocker.EXPECT().Acquire(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(true, nil)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), CronLeaderLease, "r1")
+	mAppFacade.sCron.EXPECT().ListExpiredApps().Return(crons, nil)
+	for _, name := range []string{"once", "all", "ontime", "failed"} {
+		mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}, nil)
+	}
+	mAppFacade.sApp.EXPECT().Get(ns, "deleted", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	// the apps fired keep the selector of their crons, and are finished by the last runs of their crons
+	statuses := map[string][]specV1.CronStatusCode{}
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			statuses[app.Name] = append(statuses[app.Name], app.CronStatus)
+			return app, nil
+		}).AnyTimes()
+	runs := map[string]int{}
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) ([]string, error) {
+			assert.Equal(t, "a=b", app.Selector)
+			if app.Name == "failed" {
+				return nil, unknownErr
+			}
+			runs[app.Name]++
+			return []string{"n1"}, nil
+		}).AnyTimes()
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), []string{"n1"}).Return(nil).AnyTimes()
+	mAppFacade.sCron.EXPECT().CreateCronRun(nil, gomock.Any()).DoAndReturn(func(_ interface{}, run *models.CronRun) error {
+		assert.Equal(t, models.CronRunScheduled, run.Trigger)
+		assert.Equal(t, run.Name != "failed", run.Success)
+		return nil
+	}).AnyTimes()
+	// the cron failed to fire is left to be fired next time
+	mAppFacade.sCron.EXPECT().DeleteExpiredApps([]uint64{1, 2, 3, 4, 6}).Return(nil)
+	err := appFacade.FireDueCrons(context.Background())
+	assert.Equal(t, unknownErr, errors.Cause(err))
+	assert.Equal(t, map[string]int{"once": 1, "all": 2, "ontime": 1}, runs)
+	assert.Equal(t, []specV1.CronStatusCode{specV1.CronFinished}, statuses["once"])
+	assert.Equal(t, []specV1.CronStatusCode{specV1.CronWait, specV1.CronFinished}, statuses["all"])
+	assert.Equal(t, []specV1.CronStatusCode{specV1.CronWait}, statuses["ontime"])
+}
+
+func TestFireDueCronKeepsNodes(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:        mAppFacade.sNode,
+		app:         mAppFacade.sApp,
+		index:       mAppFacade.sIndex,
+		cron:        mAppFacade.sCron,
+		locker:      mAppFacade.sLocker,
+		leaseHolder: "r1",
+		txFactory:   mAppFacade.txFactory,
+		log:         log.L(),
+	}
+	appFacade.conf.CronLease.TTL = 30 * time.Second
+	appFacade.conf.CronLease.RenewInterval = time.Minute
+	appFacade.conf.CronScheduler.MisfireThreshold = time.Minute
+	ns, name := "baetyl-cloud", "abc"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// the cron fires its last time and is dropped
+	cronApp := models.Cron{Id: 1, Name: name, Namespace: ns, Selector: "a=1", CronTime: time.Now().Add(-time.Second)}
+	mAppFacade.sLocker.EXPECT().Acquire(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(true, nil)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), CronLeaderLease, "r1")
+	mAppFacade.sCron.EXPECT().ListExpiredApps().Return([]models.Cron{cronApp}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name, Version: "1", CronStatus: specV1.CronWait}, nil)
+	var stored *specV1.Application
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			stored = app
+			stored.Version = "2"
+			return stored, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, []string{"n1"}).Return(nil)
+	mAppFacade.sCron.EXPECT().DeleteExpiredApps([]uint64{1}).Return(nil)
+	assert.NoError(t, appFacade.FireDueCrons(context.Background()))
+	assert.Equal(t, "a=1", stored.Selector)
+	assert.Equal(t, specV1.CronFinished, stored.CronStatus)
+
+	// the node relabeled afterwards keeps the app fired
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{
+		ListOptions: &models.ListOptions{},
+		Items: []models.AppItem{
+			{Name: name, Version: stored.Version, Selector: stored.Selector, CronStatus: stored.CronStatus},
+		},
+	}, nil)
+	desire := specV1.Desire{}
+	desire.SetAppInfos(false, []specV1.AppInfo{{Name: name, Version: "2"}})
+	desire.SetAppInfos(true, []specV1.AppInfo{})
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Labels: map[string]string{"a": "1", "b": "2"}, Desire: desire}, nil)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n1").Return([]string{name}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n1", []string{name}).Return(nil)
+	apps, err := appFacade.RefreshNodeIndexesForNode(context.Background(), ns, "n1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{name}, apps)
+}
+
+func TestCronRuns(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{CronRuns: true},
+		log:       log.L(),
+	}
+	ns, name := "baetyl-cloud", "abc"
+	tx := &struct{}{}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(tx, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(tx).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(tx).Return().AnyTimes()
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "a=b"}, nil).AnyTimes()
+
+	// the run applied is recorded within its transaction
+	app := &specV1.Application{Namespace: ns, Name: name, Version: "1", CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Update(tx, ns, gomock.Any()).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(tx, ns, app).Return([]string{"n1", "n2"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(tx, ns, name, []string{"n1", "n2"}).Return(nil)
+	mAppFacade.sCron.EXPECT().CreateCronRun(tx, gomock.Any()).DoAndReturn(func(_ interface{}, run *models.CronRun) error {
+		assert.Equal(t, ns, run.Namespace)
+		assert.Equal(t, name, run.Name)
+		assert.Equal(t, "1", run.Version)
+		assert.Equal(t, models.CronRunManual, run.Trigger)
+		assert.Equal(t, 2, run.Nodes)
+		assert.True(t, run.Success)
+		assert.False(t, run.RunTime.IsZero())
+		return nil
+	})
+	_, nodes, err := appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2"}, nodes)
+
+	// the run failed is recorded after its transaction is rolled back
+	app = &specV1.Application{Namespace: ns, Name: name, Version: "2", CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Update(tx, ns, gomock.Any()).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(tx, ns, app).Return(nil, unknownErr)
+	mAppFacade.sCron.EXPECT().CreateCronRun(nil, gomock.Any()).DoAndReturn(func(_ interface{}, run *models.CronRun) error {
+		assert.False(t, run.Success)
+		assert.Equal(t, 0, run.Nodes)
+		assert.Equal(t, unknownErr.Error(), run.Message)
+		return nil
+	})
+	_, _, err = appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.Equal(t, unknownErr, err)
+
+	// the run isn't applied if it fails to be recorded
+	app = &specV1.Application{Namespace: ns, Name: name, Version: "3", CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Update(tx, ns, gomock.Any()).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(tx, ns, app).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(tx, ns, name, []string{"n1"}).Return(nil)
+	mAppFacade.sCron.EXPECT().CreateCronRun(tx, gomock.Any()).Return(unknownErr)
+	mAppFacade.sCron.EXPECT().CreateCronRun(nil, gomock.Any()).Return(unknownErr)
+	_, _, err = appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.Error(t, err)
+
+	runs := []models.CronRun{{Namespace: ns, Name: name, Version: "2"}, {Namespace: ns, Name: name, Version: "1", Success: true}}
+	opt := &models.ListOptions{Filter: models.Filter{PageNo: 1, PageSize: 2}}
+	mAppFacade.sCron.EXPECT().ListCronRuns(ns, name, &opt.Filter).Return(runs, 3, nil)
+	res, err := appFacade.ListCronRuns(context.Background(), ns, name, opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, runs, res.Items)
+
+	mAppFacade.sCron.EXPECT().ListCronRuns(ns, "none", gomock.Any()).Return(nil, 0, nil)
+	res, err = appFacade.ListCronRuns(context.Background(), ns, "none", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CronRun{}, res.Items)
+
+	// not recorded
+	appFacade.conf.CronRuns = false
+	_, err = appFacade.ListCronRuns(context.Background(), ns, name, nil)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
+
+func TestVerifyRepairCronApps(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{IndexPageSize: 2},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	// the cron is written but the app write fails, and the cron fails to be deleted on the rollback
+	app := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", CronStatus: specV1.CronWait, CronTime: time.Now().Add(time.Hour)}
+	mAppFacade.sCron.EXPECT().CreateCron(gomock.Any()).Return(nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).Return(nil, unknownErr)
+	mAppFacade.sCron.EXPECT().DeleteCron("abc", ns).Return(unknownErr)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.Error(t, err)
+
+	// the cron of abc is left without its app, and the app of ghi no longer waits for cron
+	page1 := []models.Cron{{Namespace: ns, Name: "abc", Selector: "a=b"}, {Namespace: ns, Name: "def", Selector: "c=d"}}
+	page2 := []models.Cron{{Namespace: ns, Name: "ghi", Selector: "e=f"}}
+	def := &specV1.Application{Namespace: ns, Name: "def", CronStatus: specV1.CronWait}
+	ghi := &specV1.Application{Namespace: ns, Name: "ghi", CronStatus: specV1.CronFinished}
+	listCrons := func() {
+		mAppFacade.sCron.EXPECT().ListCronsPage(ns, &models.Filter{PageNo: 1, PageSize: 2}).Return(page1, 3, nil)
+		mAppFacade.sCron.EXPECT().ListCronsPage(ns, &models.Filter{PageNo: 2, PageSize: 2}).Return(page2, 3, nil)
+		mAppFacade.sApp.EXPECT().GetBatch(ns, []string{"abc", "def"}).Return([]*specV1.Application{def}, nil)
+		mAppFacade.sApp.EXPECT().GetBatch(ns, []string{"ghi"}).Return([]*specV1.Application{ghi}, nil)
+	}
+	expected := &CronReport{
+		Namespace: ns,
+		Crons:     3,
+		Issues: []CronIssue{
+			{App: "abc", Selector: "a=b", Reason: CronMissingApp},
+			{App: "ghi", Selector: "e=f", Reason: CronNotWaiting},
+		},
+	}
+
+	listCrons()
+	report, err := appFacade.VerifyCronApps(context.Background(), ns)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, report)
+
+	// the crons are rechecked before deleted, ghi waits for cron again since checked
+	listCrons()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	mAppFacade.sCron.EXPECT().DeleteCron("abc", ns).Return(nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "ghi", "").Return(&specV1.Application{Namespace: ns, Name: "ghi", CronStatus: specV1.CronWait}, nil)
+	report, err = appFacade.RepairCronApps(context.Background(), ns)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, report)
+
+	mAppFacade.sCron.EXPECT().ListCronsPage(ns, gomock.Any()).Return(nil, 0, unknownErr)
+	_, err = appFacade.VerifyCronApps(context.Background(), ns)
+	assert.Error(t, err)
+
+	listCrons()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, unknownErr)
+	_, err = appFacade.RepairCronApps(context.Background(), ns)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package facade
+
+import (
+	"context"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+)
+
+// DeletionPlan the effects of deleting an application
+type DeletionPlan struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   string `json:"version"`
+	// Nodes are the nodes which will lose the app
+	Nodes []string `json:"nodes"`
+	// Configs are the generated function configs which will be deleted
+	Configs []string `json:"configs"`
+	// SharedConfigs are the generated function configs which will be kept since referenced by the other apps
+	SharedConfigs map[string][]string `json:"sharedConfigs"`
+	// SoftDelete is set if the app will be kept in the recycle bin, the configs are deleted when it's purged
+	SoftDelete bool `json:"softDelete"`
+}
+
+// DescribeAppDeletion describes what DeleteApp would do to the app without changing anything,
+// the nodes are listed by the current index and the configs are chosen as DeleteApp cleans them
+func (a *facade) DescribeAppDeletion(ctx context.Context, ns, name string) (*DeletionPlan, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	app, err := a.app.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := a.index.ListNodesByApp(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(nodes)
+
+	plan := &DeletionPlan{
+		Name:          name,
+		Namespace:     ns,
+		Version:       app.Version,
+		Nodes:         nodes,
+		Configs:       []string{},
+		SharedConfigs: map[string][]string{},
+		SoftDelete:    a.conf.SoftDelete.Enabled,
+	}
+	if plan.Nodes == nil {
+		plan.Nodes = []string{}
+	}
+	app.Namespace = ns
+	for _, c := range a.listGenConfigsToClean(nil, app, nil) {
+		if c.err != nil {
+			return nil, c.err
+		}
+		if len(c.sharedBy) > 0 {
+			plan.SharedConfigs[c.name] = c.sharedBy
+		} else {
+			plan.Configs = append(plan.Configs, c.name)
+		}
+	}
+	sort.Strings(plan.Configs)
+	return plan, nil
+}
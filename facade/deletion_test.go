@@ -0,0 +1,59 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestDescribeAppDeletion(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:    mAppFacade.sApp,
+		config: mAppFacade.sConfig,
+		index:  mAppFacade.sIndex,
+		conf:   config.Facade{SoftDelete: config.SoftDelete{Enabled: true}},
+	}
+	ns, name := "baetyl-cloud", "app"
+	app := &specV1.Application{
+		Name:    name,
+		Version: "1",
+		Volumes: []specV1.Volume{
+			{Name: "a", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-config-app-a-xxx"}}},
+			{Name: "b", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-program-config-app-b-xxx"}}},
+			{Name: "c", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg"}}},
+			{Name: "d", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "secret"}}},
+		},
+	}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil).Times(2)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, name).Return([]string{"n2", "n1"}, nil).Times(2)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "baetyl-function-config-app-a-xxx").Return(true, []string{name}, nil)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "baetyl-function-program-config-app-b-xxx").Return(true, []string{name, "other"}, nil).Times(2)
+
+	plan, err := appFacade.DescribeAppDeletion(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, &DeletionPlan{
+		Name:          name,
+		Namespace:     ns,
+		Version:       "1",
+		Nodes:         []string{"n1", "n2"},
+		Configs:       []string{"baetyl-function-config-app-a-xxx"},
+		SharedConfigs: map[string][]string{"baetyl-function-program-config-app-b-xxx": {"other"}},
+		SoftDelete:    true,
+	}, plan)
+
+	// the failure of looking up the references is returned
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "baetyl-function-config-app-a-xxx").Return(false, nil, unknownErr)
+	_, err = appFacade.DescribeAppDeletion(context.Background(), ns, name)
+	assert.Equal(t, unknownErr, err)
+
+	mAppFacade.sApp.EXPECT().Get(ns, "none", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	_, err = appFacade.DescribeAppDeletion(context.Background(), ns, "none")
+	assert.Error(t, err)
+}
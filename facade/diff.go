@@ -0,0 +1,144 @@
+package facade
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// AppDiff the differences between two versions of an application
+type AppDiff struct {
+	Name        string          `json:"name"`
+	Namespace   string          `json:"namespace"`
+	FromVersion string          `json:"fromVersion"`
+	ToVersion   string          `json:"toVersion"`
+	Selector    *SelectorChange `json:"selector,omitempty"`
+	Services    []ServiceChange `json:"services,omitempty"`
+	Volumes     []VolumeChange  `json:"volumes,omitempty"`
+}
+
+type SelectorChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type ServiceChange struct {
+	Name   string     `json:"name"`
+	Change ChangeType `json:"change"`
+}
+
+type VolumeChange struct {
+	Name   string     `json:"name"`
+	Change ChangeType `json:"change"`
+	// FromConfig and ToConfig are the names of referenced configurations
+	FromConfig string `json:"fromConfig,omitempty"`
+	ToConfig   string `json:"toConfig,omitempty"`
+	// ConfigChanged is set if the volume refers to another configuration
+	ConfigChanged bool `json:"configChanged,omitempty"`
+}
+
+// IsEmpty returns true if there is no difference
+func (d *AppDiff) IsEmpty() bool {
+	return d.Selector == nil && len(d.Services) == 0 && len(d.Volumes) == 0
+}
+
+func (a *facade) DiffApp(ctx context.Context, ns, name, fromVersion, toVersion string) (*AppDiff, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	from, err := a.app.Get(ns, name, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := a.app.Get(ns, name, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return diffApp(from, to), nil
+}
+
+func diffApp(from, to *specV1.Application) *AppDiff {
+	diff := &AppDiff{
+		Name:        to.Name,
+		Namespace:   to.Namespace,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+	}
+	if from.Selector != to.Selector {
+		diff.Selector = &SelectorChange{From: from.Selector, To: to.Selector}
+	}
+
+	fromServices := map[string]specV1.Service{}
+	for _, s := range from.Services {
+		fromServices[s.Name] = s
+	}
+	toServices := map[string]specV1.Service{}
+	for _, s := range to.Services {
+		toServices[s.Name] = s
+		old, ok := fromServices[s.Name]
+		if !ok {
+			diff.Services = append(diff.Services, ServiceChange{Name: s.Name, Change: ChangeAdded})
+		} else if !reflect.DeepEqual(old, s) {
+			diff.Services = append(diff.Services, ServiceChange{Name: s.Name, Change: ChangeModified})
+		}
+	}
+	for _, s := range from.Services {
+		if _, ok := toServices[s.Name]; !ok {
+			diff.Services = append(diff.Services, ServiceChange{Name: s.Name, Change: ChangeRemoved})
+		}
+	}
+	sort.Slice(diff.Services, func(i, j int) bool {
+		return diff.Services[i].Name < diff.Services[j].Name
+	})
+
+	fromVolumes := map[string]specV1.Volume{}
+	for _, v := range from.Volumes {
+		fromVolumes[v.Name] = v
+	}
+	toVolumes := map[string]specV1.Volume{}
+	for _, v := range to.Volumes {
+		toVolumes[v.Name] = v
+		old, ok := fromVolumes[v.Name]
+		if !ok {
+			diff.Volumes = append(diff.Volumes, VolumeChange{Name: v.Name, Change: ChangeAdded, ToConfig: configName(v)})
+		} else if !reflect.DeepEqual(old, v) {
+			diff.Volumes = append(diff.Volumes, VolumeChange{
+				Name:          v.Name,
+				Change:        ChangeModified,
+				FromConfig:    configName(old),
+				ToConfig:      configName(v),
+				ConfigChanged: configName(old) != configName(v),
+			})
+		}
+	}
+	for _, v := range from.Volumes {
+		if _, ok := toVolumes[v.Name]; !ok {
+			diff.Volumes = append(diff.Volumes, VolumeChange{Name: v.Name, Change: ChangeRemoved, FromConfig: configName(v)})
+		}
+	}
+	sort.Slice(diff.Volumes, func(i, j int) bool {
+		return diff.Volumes[i].Name < diff.Volumes[j].Name
+	})
+	return diff
+}
+
+func configName(v specV1.Volume) string {
+	if v.Config == nil {
+		return ""
+	}
+	return v.Config.Name
+}
@@ -0,0 +1,73 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app: mAppFacade.sApp,
+	}
+	ns, name := "baetyl-cloud", "abc"
+
+	from := &specV1.Application{
+		Name:      name,
+		Namespace: ns,
+		Version:   "1",
+		Selector:  "a=b",
+		Services: []specV1.Service{
+			{Name: "s1", Image: "image:v1"},
+			{Name: "s2", Image: "image:v1"},
+		},
+		Volumes: []specV1.Volume{
+			{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c1"}}},
+			{Name: "v2", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c2", Version: "1"}}},
+			{Name: "v3", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c3"}}},
+		},
+	}
+	to := &specV1.Application{
+		Name:      name,
+		Namespace: ns,
+		Version:   "2",
+		Selector:  "a=c",
+		Services: []specV1.Service{
+			{Name: "s3", Image: "image:v1"},
+			{Name: "s1", Image: "image:v2"},
+		},
+		Volumes: []specV1.Volume{
+			{Name: "v4", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c4"}}},
+			{Name: "v1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c5"}}},
+			{Name: "v2", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "c2", Version: "2"}}},
+		},
+	}
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(nil, unknownErr).Times(1)
+	_, err := appFacade.DiffApp(context.Background(), ns, name, "1", "2")
+	assert.Equal(t, unknownErr, err)
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(from, nil).Times(1)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "2").Return(to, nil).Times(1)
+	diff, err := appFacade.DiffApp(context.Background(), ns, name, "1", "2")
+	assert.NoError(t, err)
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, &SelectorChange{From: "a=b", To: "a=c"}, diff.Selector)
+	assert.Equal(t, []ServiceChange{
+		{Name: "s1", Change: ChangeModified},
+		{Name: "s2", Change: ChangeRemoved},
+		{Name: "s3", Change: ChangeAdded},
+	}, diff.Services)
+	assert.Equal(t, []VolumeChange{
+		{Name: "v1", Change: ChangeModified, FromConfig: "c1", ToConfig: "c5", ConfigChanged: true},
+		{Name: "v2", Change: ChangeModified, FromConfig: "c2", ToConfig: "c2"},
+		{Name: "v3", Change: ChangeRemoved, FromConfig: "c3"},
+		{Name: "v4", Change: ChangeAdded, ToConfig: "c4"},
+	}, diff.Volumes)
+
+	assert.True(t, diffApp(from, from).IsEmpty())
+}
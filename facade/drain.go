@@ -0,0 +1,62 @@
+package facade
+
+import (
+	"context"
+	"sync"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// drainer tracks the writes in flight, so that the facade can be closed without truncating them.
+// The zero value accepts the writes.
+type drainer struct {
+	mu     sync.Mutex
+	closed bool
+	active sync.WaitGroup
+}
+
+// enter registers a write, the returned func must be called once the write is done.
+// ErrShuttingDown is returned after the drainer is closed.
+func (d *drainer) enter() (func(), error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil, common.Error(common.ErrShuttingDown)
+	}
+	d.active.Add(1)
+	return d.active.Done, nil
+}
+
+// close stops accepting the writes and waits for the ones in flight until ctx is done
+func (d *drainer) close(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return errors.Trace(ctx.Err())
+	}
+}
+
+// Close stops accepting the creations, updates and deletions of the apps, which fail with ErrShuttingDown,
+// and waits for the ones in flight to finish until ctx is done
+func (a *facade) Close(ctx context.Context) error {
+	err := a.drain.close(ctx)
+	if err != nil {
+		a.log.Warn("the facade is closed with the app writes in flight", log.Error(err))
+		return err
+	}
+	a.log.Info("the facade is drained")
+	return nil
+}
@@ -0,0 +1,69 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestCloseFacade(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Namespace: ns, Name: "abc"}
+
+	// the deletion in flight is waited, the closing times out until it's committed
+	deleting := make(chan struct{})
+	committing := make(chan struct{})
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "abc", "").DoAndReturn(func(_ interface{}, _, _, _ string) error {
+		close(deleting)
+		<-committing
+		return nil
+	})
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil)
+	deleted := make(chan error, 1)
+	go func() {
+		deleted <- appFacade.DeleteApp(context.Background(), ns, "abc", app)
+	}()
+	<-deleting
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := appFacade.Close(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+
+	// the writes are rejected once the facade is closed
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, &specV1.Application{Namespace: ns, Name: "def"}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrShuttingDown, err.(errors.Coder).Code())
+	_, err = appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{{App: &specV1.Application{Namespace: ns, Name: "def"}}})
+	assert.Equal(t, common.ErrShuttingDown, err.(errors.Coder).Code())
+	_, err = appFacade.UpdateApp(context.Background(), ns, app, &specV1.Application{Namespace: ns, Name: "abc"}, nil)
+	assert.Equal(t, common.ErrShuttingDown, err.(errors.Coder).Code())
+	err = appFacade.DeleteApps(context.Background(), ns, []string{"abc"})
+	assert.Equal(t, common.ErrShuttingDown, err.(errors.Coder).Code())
+
+	close(committing)
+	assert.NoError(t, <-deleted)
+	assert.NoError(t, appFacade.Close(context.Background()))
+}
@@ -0,0 +1,53 @@
+package facade
+
+import (
+	"context"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// publishAppEvent invalidates the cached app, runs the Post hooks and publishes the lifecycle event
+// of the app to the watchers and the sink, it must be called once after the change is committed.
+// The event is published to the sink by RelayAppOutbox instead if the outbox is enabled.
+// The change can not be undone, so the failure is only logged.
+func (a *facade) publishAppEvent(ctx context.Context, action models.AppAction, ns string, app *specV1.Application, nodes []string) {
+	if app == nil {
+		return
+	}
+	a.invalidateCachedApp(ns, app.Name)
+	a.postAppHooks(ctx, action, ns, app)
+	event := newAppEvent(action, ns, app, nodes)
+	a.broadcastAppEvent(event)
+	// the event written to the outbox is relayed to the sink
+	if a.event == nil || a.outboxEnabled() {
+		return
+	}
+	if err := a.event.Publish(ctx, event); err != nil {
+		a.log.Error("failed to publish app event",
+			log.Any(common.KeyContextNamespace, ns),
+			log.Any("name", app.Name),
+			log.Any("action", action),
+			log.Error(err))
+	}
+}
+
+// mergeNodes returns the sorted union of the nodes
+func mergeNodes(nodes ...[]string) []string {
+	m := map[string]bool{}
+	for _, ns := range nodes {
+		for _, n := range ns {
+			m[n] = true
+		}
+	}
+	res := make([]string, 0, len(m))
+	for n := range m {
+		res = append(res, n)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,82 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAppEvent(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		event:     mAppFacade.event,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Namespace: ns, Name: "abc", Version: "1", Selector: "a=b"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// no event on rollback
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(nil, unknownErr)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.Error(t, err)
+
+	// create
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	mAppFacade.event.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *models.AppEvent) error {
+		assert.Equal(t, ns, e.Namespace)
+		assert.Equal(t, "abc", e.Name)
+		assert.Equal(t, "1", e.Version)
+		assert.Equal(t, models.AppCreated, e.Action)
+		assert.Equal(t, []string{"n1"}, e.Nodes)
+		return nil
+	})
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.NoError(t, err)
+
+	// update with the selector changed, the nodes the app removed from are affected too
+	newApp := &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d"}
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, newApp).Return(newApp, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, newApp).Return([]string{"n2"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n2"}).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, app, gomock.Any()).Return(nil)
+	mAppFacade.event.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *models.AppEvent) error {
+		assert.Equal(t, models.AppUpdated, e.Action)
+		assert.Equal(t, []string{"n1", "n2"}, e.Nodes)
+		return nil
+	})
+	_, err = appFacade.UpdateApp(context.Background(), ns, app, newApp, nil)
+	assert.NoError(t, err)
+
+	// the failure of publishing does not fail the committed deletion
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(newApp, nil)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "abc", "").Return(nil)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, newApp).Return([]string{"n2"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{}).Return(nil)
+	mAppFacade.event.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *models.AppEvent) error {
+		assert.Equal(t, models.AppDeleted, e.Action)
+		assert.Equal(t, []string{"n2"}, e.Nodes)
+		return unknownErr
+	})
+	err = appFacade.DeleteApp(context.Background(), ns, "abc", newApp)
+	assert.NoError(t, err)
+}
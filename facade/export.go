@@ -0,0 +1,124 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// AppBundleVersion the current format version of the app bundle
+const AppBundleVersion = "v1"
+
+// AppBundle the portable form of an app with the configs it references, which is free of
+// the namespace and the versions of the source so that it can be imported into another cluster
+type AppBundle struct {
+	Version string                 `yaml:"version" json:"version"`
+	App     *specV1.Application    `yaml:"app" json:"app"`
+	Configs []specV1.Configuration `yaml:"configs" json:"configs"`
+	// Secrets are only exported on demand
+	Secrets []specV1.Secret `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	// SecretRefs are the names of the secrets referenced by the app but not exported,
+	// which should exist in the destination before the import
+	SecretRefs []string `yaml:"secretRefs,omitempty" json:"secretRefs,omitempty"`
+}
+
+// ExportApp serializes the app of the version and the configs it references into a YAML bundle,
+// the references are rewritten to the names only. The secrets are referenced by names unless
+// withSecrets is set, since the bundle is usually stored outside the cluster.
+func (a *facade) ExportApp(ctx context.Context, ns, name, version string, withSecrets bool) ([]byte, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	src, err := a.GetApp(ctx, ns, name, version)
+	if err != nil {
+		return nil, err
+	}
+	app, err := copyApp(src)
+	if err != nil {
+		return nil, err
+	}
+	app.Namespace = ""
+	app.Version = ""
+	app.CreationTimestamp = time.Time{}
+	delete(app.Labels, common.LabelCronPaused)
+	delete(app.Labels, common.LabelCronNextRuns)
+
+	bundle := &AppBundle{
+		Version: AppBundleVersion,
+		App:     app,
+		Configs: []specV1.Configuration{},
+	}
+	exported := map[string]bool{}
+	for _, v := range app.Volumes {
+		if err = ctx.Err(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch {
+		case v.Config != nil:
+			if !exported["config/"+v.Config.Name] {
+				cfg, err := a.config.Get(ns, v.Config.Name, v.Config.Version)
+				if err != nil {
+					return nil, err
+				}
+				cfg.Namespace, cfg.Version = "", ""
+				cfg.CreationTimestamp, cfg.UpdateTimestamp = time.Time{}, time.Time{}
+				bundle.Configs = append(bundle.Configs, *cfg)
+				exported["config/"+v.Config.Name] = true
+			}
+			v.Config.Version = ""
+		case v.Secret != nil:
+			if !exported["secret/"+v.Secret.Name] {
+				if withSecrets {
+					secret, err := a.secret.Get(ns, v.Secret.Name, v.Secret.Version)
+					if err != nil {
+						return nil, err
+					}
+					secret.Namespace, secret.Version = "", ""
+					secret.CreationTimestamp, secret.UpdateTimestamp = time.Time{}, time.Time{}
+					bundle.Secrets = append(bundle.Secrets, *secret)
+				} else {
+					bundle.SecretRefs = append(bundle.SecretRefs, v.Secret.Name)
+				}
+				exported["secret/"+v.Secret.Name] = true
+			}
+			v.Secret.Version = ""
+		}
+	}
+	sort.Slice(bundle.Configs, func(i, j int) bool { return bundle.Configs[i].Name < bundle.Configs[j].Name })
+	sort.Slice(bundle.Secrets, func(i, j int) bool { return bundle.Secrets[i].Name < bundle.Secrets[j].Name })
+	sort.Strings(bundle.SecretRefs)
+
+	data, err := yaml.Marshal(bundle)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// ParseAppBundle parses the bundle exported by ExportApp, the bundles of the former
+// format versions are upgraded to the current one
+func ParseAppBundle(data []byte) (*AppBundle, error) {
+	bundle := new(AppBundle)
+	if err := yaml.Unmarshal(data, bundle); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("invalid app bundle: %s", err.Error())))
+	}
+	switch bundle.Version {
+	case AppBundleVersion:
+	default:
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the version (%s) of the app bundle is not supported", bundle.Version)))
+	}
+	if bundle.App == nil || bundle.App.Name == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the app bundle has no application"))
+	}
+	return bundle, nil
+}
@@ -0,0 +1,97 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestExportApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:    mAppFacade.sApp,
+		config: mAppFacade.sConfig,
+		secret: mAppFacade.sSecret,
+		cron:   mAppFacade.sCron,
+		log:    log.L(),
+	}
+	ns := "staging"
+	newApp := func() *specV1.Application {
+		return &specV1.Application{
+			Namespace:         ns,
+			Name:              "abc",
+			Version:           "v1",
+			Selector:          "a=b",
+			CreationTimestamp: time.Now(),
+			Volumes: []specV1.Volume{
+				{Name: "user1", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "user", Version: "2"}}},
+				{Name: "user2", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "user", Version: "2"}}},
+				{Name: "cert", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "s", Version: "3"}}},
+			},
+		}
+	}
+	cfg := func() *specV1.Configuration {
+		return &specV1.Configuration{Namespace: ns, Name: "user", Version: "2", Data: map[string]string{"k": "v"}, UpdateTimestamp: time.Now()}
+	}
+
+	// the secrets are referenced only
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "v1").Return(newApp(), nil)
+	mAppFacade.sConfig.EXPECT().Get(ns, "user", "2").Return(cfg(), nil)
+	data, err := appFacade.ExportApp(context.Background(), ns, "abc", "v1", false)
+	assert.NoError(t, err)
+	bundle, err := ParseAppBundle(data)
+	assert.NoError(t, err)
+	assert.Equal(t, AppBundleVersion, bundle.Version)
+	assert.Equal(t, "abc", bundle.App.Name)
+	assert.Equal(t, "", bundle.App.Namespace)
+	assert.Equal(t, "", bundle.App.Version)
+	assert.True(t, bundle.App.CreationTimestamp.IsZero())
+	for _, v := range bundle.App.Volumes {
+		if v.Config != nil {
+			assert.Equal(t, &specV1.ObjectReference{Name: "user"}, v.Config)
+		} else {
+			assert.Equal(t, &specV1.ObjectReference{Name: "s"}, v.Secret)
+		}
+	}
+	assert.Equal(t, []specV1.Configuration{{Name: "user", Data: map[string]string{"k": "v"}}}, bundle.Configs)
+	assert.Nil(t, bundle.Secrets)
+	assert.Equal(t, []string{"s"}, bundle.SecretRefs)
+
+	// the secrets are exported on demand
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "v1").Return(newApp(), nil)
+	mAppFacade.sConfig.EXPECT().Get(ns, "user", "2").Return(cfg(), nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "s", "3").Return(&specV1.Secret{Namespace: ns, Name: "s", Version: "3", Data: map[string][]byte{"k": []byte("v")}}, nil)
+	data, err = appFacade.ExportApp(context.Background(), ns, "abc", "v1", true)
+	assert.NoError(t, err)
+	bundle, err = ParseAppBundle(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []specV1.Secret{{Name: "s", Data: map[string][]byte{"k": []byte("v")}}}, bundle.Secrets)
+	assert.Nil(t, bundle.SecretRefs)
+
+	// config not found
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "v1").Return(newApp(), nil)
+	mAppFacade.sConfig.EXPECT().Get(ns, "user", "2").Return(nil, unknownErr)
+	_, err = appFacade.ExportApp(context.Background(), ns, "abc", "v1", false)
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestParseAppBundle(t *testing.T) {
+	_, err := ParseAppBundle([]byte("version: v0\napp:\n  name: abc\n"))
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+	_, err = ParseAppBundle([]byte("version: v1\n"))
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+	_, err = ParseAppBundle([]byte("version: [v1"))
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	bundle, err := ParseAppBundle([]byte("version: v1\napp:\n  name: abc\nconfigs: []\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", bundle.App.Name)
+}
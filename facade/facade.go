@@ -1,11 +1,15 @@
 package facade
 
 import (
+	"context"
+
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	uuid "github.com/satori/go.uuid"
 
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
@@ -13,29 +17,156 @@ import (
 //go:generate mockgen -destination=../mock/facade/facade.go -package=facade github.com/baetyl/baetyl-cloud/v2/facade Facade
 
 type Facade interface {
-	GetApp(ns, name, version string) (*specV1.Application, error)
-	CreateApp(ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error)
-	UpdateApp(ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error)
-	DeleteApp(ns, name string, app *specV1.Application) error
+	GetApp(ctx context.Context, ns, name, version string) (*specV1.Application, error)
+	// GetApps gets the versions of the apps referred to by batch, the apps missing are reported by ErrResourceNotFound
+	GetApps(ctx context.Context, ns string, refs []models.AppRef) ([]*specV1.Application, error)
+	ListApps(ctx context.Context, ns string, opt *models.ListOptions) (*models.ApplicationList, error)
+	// CreateApp creates the app, the app created with the idempotency key carried by ctx is returned if exists.
+	// The nodes the app is deployed to are returned with the app created
+	CreateApp(ctx context.Context, ns string, baseApp, app *specV1.Application, configs []specV1.Configuration) (*AppResult, error)
+	CreateApps(ctx context.Context, ns string, reqs []*AppCreateRequest) ([]*specV1.Application, error)
+	// UpdateApp updates the app, the nodes whose desire is changed are returned with the app updated
+	UpdateApp(ctx context.Context, ns string, oldApp, app *specV1.Application, configs []specV1.Configuration) (*AppResult, error)
+	// PatchApp applies the JSON Patch or the merge patch to the current version of the app atomically
+	PatchApp(ctx context.Context, ns, name string, patch []byte, patchType PatchType) (*specV1.Application, error)
+	// ApplyApp creates the app if it doesn't exist or updates it otherwise, whether it's created is returned
+	ApplyApp(ctx context.Context, ns string, desired *specV1.Application, configs []specV1.Configuration) (*specV1.Application, bool, error)
+	// FanOutApp applies the app to each of the namespaces, *FanOutError is returned with the namespaces failed
+	FanOutApp(ctx context.Context, app *specV1.Application, configs []specV1.Configuration, namespaces []string) (map[string]*specV1.Application, error)
+	// DeleteApp deletes the app, which is kept in the recycle bin until purged if soft delete is enabled
+	DeleteApp(ctx context.Context, ns, name string, app *specV1.Application) error
+	// DeleteApps deletes a batch of apps in a single transaction
+	DeleteApps(ctx context.Context, ns string, names []string) error
+	// DeleteNamespaceApps deletes all the apps of the namespace in batches, *TeardownError is returned with the apps failed
+	DeleteNamespaceApps(ctx context.Context, ns string) error
+	DescribeAppDeletion(ctx context.Context, ns, name string) (*DeletionPlan, error)
+	RestoreApp(ctx context.Context, ns, name string) (*specV1.Application, error)
+	// ExportApp serializes the app with its configs into a portable YAML bundle, secrets are only exported on demand
+	ExportApp(ctx context.Context, ns, name, version string, withSecrets bool) ([]byte, error)
+	// ImportApp imports the app bundle exported by ExportApp into the namespace transactionally
+	ImportApp(ctx context.Context, ns string, data []byte, opts ImportOptions) (*specV1.Application, error)
+	CloneApp(ctx context.Context, srcNs, name, version, dstNs, newName string, policy CloneConflictPolicy) (*specV1.Application, error)
+	// InstantiateTemplate creates the app of the app template stored in the config store with the parameters
+	InstantiateTemplate(ctx context.Context, ns, templateName string, params map[string]string) (*specV1.Application, error)
+	PurgeDeletedApps(ctx context.Context) error
+	GCIdempotencyKeys(ctx context.Context) error
+	// CancelAppActivation cancels the activation of the app scheduled by WithActivateAt
+	CancelAppActivation(ctx context.Context, ns, name string) error
+	// FreezeApp freezes the app so that it can't be updated or deleted until it's unfrozen by UnfreezeApp,
+	// the caller must be granted PrivilegeFreezeApp by the authorizer
+	FreezeApp(ctx context.Context, ns, name string) error
+	UnfreezeApp(ctx context.Context, ns, name string) error
+	// ActivateDueApps deploys the versions of the apps scheduled by WithActivateAt once they're due
+	ActivateDueApps(ctx context.Context) error
+	// RelayAppOutbox publishes the app events written to the outbox to the event sink
+	RelayAppOutbox(ctx context.Context) error
+	// RelayCronOutbox applies the cron operations of the apps written to the outbox to the cron store
+	RelayCronOutbox(ctx context.Context) error
+	// HealthCheck checks the dependencies the writes of the facade rely on, *HealthError is returned if any fails
+	HealthCheck(ctx context.Context) error
+	// LeadCron runs fn as the leader of the cron scheduler while holding the lease, ErrNotLeader is returned if the lease is held by another replica
+	LeadCron(ctx context.Context, fn func(ctx context.Context) error) error
+	// FireDueCrons fires the expired crons by their misfire policies as the leader of the cron scheduler
+	FireDueCrons(ctx context.Context) error
+	// Close stops accepting the writes of the apps and waits for the ones in flight until ctx is done
+	Close(ctx context.Context) error
+	// RollbackApp rolls the app back to the target version, which defaults to the latest pinned version if the history is enabled
+	RollbackApp(ctx context.Context, ns, name, targetVersion string) (*specV1.Application, error)
+	// PinAppVersion pins the version of the app in the history, so that it's never pruned by the retention
+	PinAppVersion(ctx context.Context, ns, name, version string) error
+	UnpinAppVersion(ctx context.Context, ns, name, version string) error
+	ListPinnedAppVersions(ctx context.Context, ns, name string) ([]models.AppVersion, error)
+	PreviewApp(ctx context.Context, ns string, app *specV1.Application, configs []specV1.Configuration) (*AppPreview, error)
+	DiffApp(ctx context.Context, ns, name, fromVersion, toVersion string) (*AppDiff, error)
+	// CanaryRollout rolls the current version of the app out to the percent of the matched nodes
+	CanaryRollout(ctx context.Context, ns, name string, percent int) (*specV1.Application, error)
+	PromoteApp(ctx context.Context, ns, name string) (*specV1.Application, error)
+	GetCanaryStatus(ctx context.Context, ns, name string) (*CanaryStatus, error)
+	// AdvanceRollout updates the next nodes of the staggered app as the updating ones report the version running
+	AdvanceRollout(ctx context.Context, ns, name string) (*RolloutProgress, error)
+	// AdvanceRollouts advances the staggered rollouts of the apps of all the namespaces
+	AdvanceRollouts(ctx context.Context) error
+	// GetAppStatus counts the nodes pending, applied and failed with the version of the app by their reports
+	GetAppStatus(ctx context.Context, ns, name, version string) (*AppRolloutStatus, error)
+	// SwapApps swaps the selectors of two apps and refreshes their node indexes atomically
+	SwapApps(ctx context.Context, ns, nameA, nameB string) ([]*specV1.Application, error)
+	ResolveSelector(ctx context.Context, ns, selector string) ([]string, error)
+	ReclaimFunctionConfigs(ctx context.Context, ns string, dryRun bool) ([]string, error)
+	// VerifyFunctionConfigs reports the generated function configs of the app drifted from their checksums
+	VerifyFunctionConfigs(ctx context.Context, ns, name string) (*FunctionConfigReport, error)
+	PauseCronApp(ctx context.Context, ns, name string) error
+	ResumeCronApp(ctx context.Context, ns, name string) error
+	TriggerCronApp(ctx context.Context, ns, name string) (*specV1.Application, []string, error)
+	// ResolveCronSelector resolves the selector of the cron of the app to the nodes it would be deployed to if fired now
+	ResolveCronSelector(ctx context.Context, ns, name string) ([]string, error)
+	// SetSelectorResolver sets the resolver of the cron selectors labeled with the name, nil removes it
+	SetSelectorResolver(name string, resolver SelectorResolver) error
+	ListCronRuns(ctx context.Context, ns, name string, opt *models.ListOptions) (*models.CronRunList, error)
+	// WatchApps streams the lifecycle events of the apps of the namespace until ctx is done
+	WatchApps(ctx context.Context, ns string) (<-chan models.AppEvent, error)
+	// ListAppNodes lists the page of the nodes which the app is deployed to
+	ListAppNodes(ctx context.Context, ns, name string, opt *models.ListOptions) (*models.AppNodeList, error)
+	ListNodeApps(ctx context.Context, ns, node string, opt *models.ListOptions) (*models.NodeAppList, error)
+	// ListAppNodesByLabel lists the nodes of the apps labeled with the key and the value if IndexAppLabels is enabled
+	ListAppNodesByLabel(ctx context.Context, ns, key, value string) ([]models.AppNodeBinding, error)
+	ListAppAudit(ctx context.Context, ns, name string) ([]models.AppAudit, error)
+	VerifyAppIndex(ctx context.Context, ns string) (*IndexReport, error)
+	RepairAppIndex(ctx context.Context, ns string) (*IndexReport, error)
+	// ReconcileReport reports for each app of the namespace whether its node index matches its selector
+	// and whether the nodes report its current version running, it's read-only
+	ReconcileReport(ctx context.Context, ns string) (*NamespaceReconcileReport, error)
+	// VerifyCronApps reports the crons left without an app waiting for them, which RepairCronApps deletes
+	VerifyCronApps(ctx context.Context, ns string) (*CronReport, error)
+	RepairCronApps(ctx context.Context, ns string) (*CronReport, error)
+	// RefreshNodeIndexesForNode recomputes the apps bound to the node after its labels change
+	RefreshNodeIndexesForNode(ctx context.Context, ns, name string) ([]string, error)
+	// UpdateNode updates the node and recomputes the apps bound to it in a single transaction if its labels change
+	UpdateNode(ctx context.Context, ns string, node *specV1.Node) (*specV1.Node, error)
+	// ReconcileNodeIndexes recomputes the apps bound to the nodes after their labels change at once
+	ReconcileNodeIndexes(ctx context.Context, ns string, changedNodes []string) error
+	// RegisterAppHook registers the hook invoked around the creations, updates and deletions of the apps
+	RegisterAppHook(hook AppHook)
+	// SetAuthorizer sets the authorizer consulted before accessing the namespaces, all the accesses are allowed by default
+	SetAuthorizer(authz Authorizer)
 
-	CreateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error)
-	UpdateConfig(ns string, config *specV1.Configuration) (*specV1.Configuration, error)
-	DeleteConfig(ns, name string) error
+	CreateConfig(ctx context.Context, ns string, config *specV1.Configuration) (*specV1.Configuration, error)
+	UpdateConfig(ctx context.Context, ns string, config *specV1.Configuration) (*specV1.Configuration, error)
+	DeleteConfig(ctx context.Context, ns, name string) error
 
-	CreateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error)
-	UpdateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error)
-	DeleteSecret(ns, name string) error
+	CreateSecret(ctx context.Context, ns string, secret *specV1.Secret) (*specV1.Secret, error)
+	UpdateSecret(ctx context.Context, ns string, secret *specV1.Secret) (*specV1.Secret, error)
+	DeleteSecret(ctx context.Context, ns, name string) error
 }
 
 type facade struct {
-	node      service.NodeService
-	app       service.ApplicationService
-	config    service.ConfigService
-	secret    service.SecretService
-	index     service.IndexService
-	cron      service.CronService
-	txFactory plugin.TransactionFactory
-	log       *log.Logger
+	node        service.NodeService
+	app         service.ApplicationService
+	config      service.ConfigService
+	secret      service.SecretService
+	index       service.IndexService
+	cron        service.CronService
+	audit       service.AppAuditService
+	recycle     service.AppRecycleService
+	idempotency service.AppIdempotencyService
+	outbox      service.AppOutboxService
+	history     service.AppHistoryService
+	activation  service.AppActivationService
+	freeze      service.AppFreezeService
+	namespace   service.NamespaceService
+	blob        service.ConfigBlobService
+	locker      service.LockerService
+	rateLimiter plugin.RateLimiter
+	txFactory   plugin.TransactionFactory
+	event       plugin.EventSink
+	watchers    *appWatchers
+	cache       *appCache
+	hooks       appHooks
+	drain       drainer
+	authz       authorizer
+	resolvers   selectorResolvers
+	leaseHolder string
+	conf        config.Facade
+	log         *log.Logger
 }
 
 func NewFacade(config *config.CloudConfig) (Facade, error) {
@@ -63,19 +194,94 @@ func NewFacade(config *config.CloudConfig) (Facade, error) {
 	if err != nil {
 		return nil, err
 	}
+	audit, err := service.NewAppAuditService(config)
+	if err != nil {
+		return nil, err
+	}
+	recycle, err := service.NewAppRecycleService(config)
+	if err != nil {
+		return nil, err
+	}
+	idempotency, err := service.NewAppIdempotencyService(config)
+	if err != nil {
+		return nil, err
+	}
+	outbox, err := service.NewAppOutboxService(config)
+	if err != nil {
+		return nil, err
+	}
+	locker, err := service.NewLockerService(config)
+	if err != nil {
+		return nil, err
+	}
 	tx, err := plugin.GetPlugin(config.Plugin.Tx)
 	if err != nil {
 		return nil, err
 	}
+	event, err := plugin.GetPlugin(config.Plugin.Event)
+	if err != nil {
+		return nil, err
+	}
 
-	return &facade{
-		node:      node,
-		app:       app,
-		config:    cfg,
-		secret:    secret,
-		index:     index,
-		cron:      cron,
-		txFactory: tx.(plugin.TransactionFactory),
-		log:       log.L().With(log.Any("level", "facade")),
-	}, nil
+	f := &facade{
+		node:        node,
+		app:         app,
+		config:      cfg,
+		secret:      secret,
+		index:       index,
+		cron:        cron,
+		audit:       audit,
+		recycle:     recycle,
+		idempotency: idempotency,
+		outbox:      outbox,
+		locker:      locker,
+		txFactory:   tx.(plugin.TransactionFactory),
+		event:       event.(plugin.EventSink),
+		watchers:    newAppWatchers(),
+		leaseHolder: uuid.NewV4().String(),
+		conf:        config.Facade,
+		log:         log.L().With(log.Any("level", "facade")),
+	}
+	if config.Facade.VersionRetention.Enabled {
+		if f.history, err = service.NewAppHistoryService(config); err != nil {
+			return nil, err
+		}
+	}
+	if config.Facade.Activation.Enabled {
+		if f.activation, err = service.NewAppActivationService(config); err != nil {
+			return nil, err
+		}
+	}
+	if config.Facade.AppFreeze {
+		if f.freeze, err = service.NewAppFreezeService(config); err != nil {
+			return nil, err
+		}
+	}
+	if config.Facade.Rollout.Enabled {
+		if f.namespace, err = service.NewNamespaceService(config); err != nil {
+			return nil, err
+		}
+	}
+	if config.Plugin.ConfigBlob != "" {
+		if f.blob, err = service.NewConfigBlobService(config); err != nil {
+			return nil, err
+		}
+	}
+	if config.Facade.RateLimit.Enabled {
+		limiter, err := plugin.GetPlugin(config.Plugin.RateLimiter)
+		if err != nil {
+			return nil, err
+		}
+		f.rateLimiter = limiter.(plugin.RateLimiter)
+	}
+	if config.Facade.AppCache.Enabled && config.Facade.AppCache.Size > 0 {
+		f.cache = newAppCache(config.Facade.AppCache.Size)
+	}
+	if err = validFunctionConfigPrefixes(f.functionConfigPrefixes()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = validTxIsolations(config.Facade.TxIsolation); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f, nil
 }
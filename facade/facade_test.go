@@ -1,13 +1,19 @@
 package facade
 
 import (
+	"context"
 	"testing"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	mp "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 var (
@@ -21,7 +27,18 @@ type MockAppFacade struct {
 	sSecret   *ms.MockSecretService
 	sIndex    *ms.MockIndexService
 	sCron     *ms.MockCronService
+	sAudit    *ms.MockAppAuditService
+	sRecycle  *ms.MockAppRecycleService
+	sIdem     *ms.MockAppIdempotencyService
+	sOutbox   *ms.MockAppOutboxService
+	sHistory  *ms.MockAppHistoryService
+	sActivate *ms.MockAppActivationService
+	sFreeze   *ms.MockAppFreezeService
+	sLocker   *ms.MockLockerService
+	sBlob     *ms.MockConfigBlobService
+	sNs       *ms.MockNamespaceService
 	txFactory *mp.MockTransactionFactory
+	event     *mp.MockEventSink
 }
 
 func InitMockEnvironment(t *testing.T) (*MockAppFacade, *gomock.Controller) {
@@ -33,6 +50,137 @@ func InitMockEnvironment(t *testing.T) (*MockAppFacade, *gomock.Controller) {
 		sSecret:   ms.NewMockSecretService(mockCtl),
 		sIndex:    ms.NewMockIndexService(mockCtl),
 		sCron:     ms.NewMockCronService(mockCtl),
+		sAudit:    ms.NewMockAppAuditService(mockCtl),
+		sRecycle:  ms.NewMockAppRecycleService(mockCtl),
+		sIdem:     ms.NewMockAppIdempotencyService(mockCtl),
+		sOutbox:   ms.NewMockAppOutboxService(mockCtl),
+		sHistory:  ms.NewMockAppHistoryService(mockCtl),
+		sActivate: ms.NewMockAppActivationService(mockCtl),
+		sFreeze:   ms.NewMockAppFreezeService(mockCtl),
+		sLocker:   ms.NewMockLockerService(mockCtl),
+		sBlob:     ms.NewMockConfigBlobService(mockCtl),
+		sNs:       ms.NewMockNamespaceService(mockCtl),
 		txFactory: mp.NewMockTransactionFactory(mockCtl),
+		event:     mp.NewMockEventSink(mockCtl),
 	}, mockCtl
 }
+
+func TestErrorCodes(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		secret:    mAppFacade.sSecret,
+		index:     mAppFacade.sIndex,
+		locker:    mAppFacade.sLocker,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), gomock.Any(), gomock.Any()).Return("v", nil).AnyTimes()
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	tests := []struct {
+		name string
+		code common.Code
+		call func() error
+	}{
+		{
+			name: "not-found",
+			code: common.ErrResourceNotFound,
+			call: func() error {
+				mAppFacade.sApp.EXPECT().Get(ns, "missing", "").Return(nil, common.Error(common.ErrResourceNotFound))
+				_, err := appFacade.GetApp(context.Background(), ns, "missing", "")
+				return err
+			},
+		},
+		{
+			name: "conflict",
+			code: common.ErrResourceConflict,
+			call: func() error {
+				mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Name: "abc", Namespace: ns}, nil)
+				_, err := appFacade.ImportApp(context.Background(), ns, newTestBundle(t), ImportOptions{Conflict: ImportConflictFail})
+				return err
+			},
+		},
+		{
+			name: "quota-exceeded",
+			code: common.ErrQuotaExceeded,
+			call: func() error {
+				appFacade.conf.AppQuota.Limit = 1
+				defer func() { appFacade.conf.AppQuota.Limit = 0 }()
+				mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "a"}}}, nil)
+				_, err := appFacade.CreateApp(context.Background(), ns, nil, &specV1.Application{Name: "b"}, nil)
+				return err
+			},
+		},
+		{
+			name: "invalid-input",
+			code: common.ErrRequestParamInvalid,
+			call: func() error {
+				_, err := appFacade.CanaryRollout(context.Background(), ns, "abc", 100)
+				return err
+			},
+		},
+		{
+			name: "config-in-use",
+			code: common.ErrConfigInUsed,
+			call: func() error {
+				mAppFacade.sConfig.EXPECT().IsReferenced(ns, "cfg").Return(true, []string{"abc"}, nil)
+				return appFacade.DeleteConfig(context.Background(), ns, "cfg")
+			},
+		},
+		{
+			name: "forbidden",
+			code: common.ErrForbidden,
+			call: func() error {
+				appFacade.SetAuthorizer(AuthorizerFunc(func(_ context.Context, _ common.User, _ bool, ns string) error {
+					return common.Error(common.ErrForbidden, common.Field("namespace", ns))
+				}))
+				defer appFacade.SetAuthorizer(nil)
+				_, err := appFacade.GetApp(context.Background(), ns, "abc", "")
+				return err
+			},
+		},
+		{
+			name: "internal",
+			code: common.ErrUnknown,
+			call: func() error {
+				mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, unknownErr)
+				_, err := appFacade.GetApp(context.Background(), ns, "abc", "")
+				return err
+			},
+		},
+		{
+			name: "internal-aggregated",
+			code: common.ErrUnknown,
+			call: func() error {
+				return &FanOutError{Failures: map[string]error{
+					"ns1": common.Error(common.ErrResourceNotFound),
+					"ns2": common.Error(common.ErrForbidden),
+				}}
+			},
+		},
+		{
+			name: "shared-aggregated",
+			code: common.ErrForbidden,
+			call: func() error {
+				return &FanOutError{Failures: map[string]error{
+					"ns1": common.Error(common.ErrForbidden),
+					"ns2": errors.Trace(common.Error(common.ErrForbidden)),
+				}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			assert.Error(t, err)
+			assert.Equal(t, tt.code, common.CodeOf(err))
+		})
+	}
+}
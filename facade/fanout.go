@@ -0,0 +1,94 @@
+package facade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+// FanOutError reports the namespaces the app failed to be applied to with their failures,
+// the app is applied to the other namespaces
+type FanOutError struct {
+	Failures map[string]error
+}
+
+// Code the code shared by the failures of the namespaces
+func (e *FanOutError) Code() string {
+	return sharedCode(e.Failures)
+}
+
+func (e *FanOutError) Error() string {
+	namespaces := make([]string, 0, len(e.Failures))
+	for ns := range e.Failures {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	msgs := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", ns, e.Failures[ns].Error()))
+	}
+	return fmt.Sprintf("failed to apply the app to the namespaces: %s", strings.Join(msgs, "; "))
+}
+
+// FanOutApp applies the app with its configs to each of the namespaces by ApplyApp, so the app is created in
+// the namespaces it doesn't exist and updated over the current version otherwise. Each namespace is applied in its
+// own transaction and a failure doesn't stop the others, the apps applied are returned by namespace along with
+// *FanOutError reporting the namespaces failed. The namespaces left once ctx is done fail with its error.
+func (a *facade) FanOutApp(ctx context.Context, app *specV1.Application, configs []specV1.Configuration, namespaces []string) (map[string]*specV1.Application, error) {
+	res := map[string]*specV1.Application{}
+	failures := map[string]error{}
+	for _, ns := range namespaces {
+		if _, ok := res[ns]; ok {
+			continue
+		}
+		if _, ok := failures[ns]; ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			failures[ns] = errors.Trace(err)
+			continue
+		}
+		applied, err := a.fanOutApp(ctx, ns, app, configs)
+		if err != nil {
+			failures[ns] = err
+			continue
+		}
+		res[ns] = applied
+	}
+	if len(failures) > 0 {
+		return res, &FanOutError{Failures: failures}
+	}
+	return res, nil
+}
+
+// fanOutApp applies the copies of the app and its configs to the namespace, since they're changed by the writes.
+// The version of the app is of no namespace, so the current version of each namespace is applied over.
+func (a *facade) fanOutApp(ctx context.Context, ns string, app *specV1.Application, configs []specV1.Configuration) (*specV1.Application, error) {
+	desired, err := copyApp(app)
+	if err != nil {
+		return nil, err
+	}
+	desired.Namespace = ns
+	desired.Version = ""
+	var cfgs []specV1.Configuration
+	if configs != nil {
+		data, err := json.Marshal(configs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err = json.Unmarshal(data, &cfgs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		for i := range cfgs {
+			cfgs[i].Namespace = ns
+			cfgs[i].Version = ""
+		}
+	}
+	res, _, err := a.ApplyApp(ctx, ns, desired, cfgs)
+	return res, err
+}
@@ -0,0 +1,96 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestFanOutApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		secret:    mAppFacade.sSecret,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "app"))
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	app := &specV1.Application{Namespace: "src", Name: "abc", Version: "9", Selector: "a=b"}
+	configs := []specV1.Configuration{{Namespace: "src", Name: "c1", Version: "3", Data: map[string]string{"k": "v"}}}
+
+	// the app missing from ns1 is created
+	created := &specV1.Application{Namespace: "ns1", Name: "abc", Version: "1", Selector: "a=b"}
+	mAppFacade.sApp.EXPECT().Get("ns1", "abc", "").Return(nil, notFound)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, "ns1", gomock.Any()).DoAndReturn(
+		func(_ interface{}, ns string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+			assert.Equal(t, "ns1", cfg.Namespace)
+			assert.Equal(t, "", cfg.Version)
+			return cfg, nil
+		})
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, "ns1", gomock.Any(), nil).DoAndReturn(
+		func(_ interface{}, ns string, desired, _ *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, "ns1", desired.Namespace)
+			assert.Equal(t, "", desired.Version)
+			return created, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, "ns1", created).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, "ns1", "abc", gomock.Any()).Return(nil)
+
+	// the failure of ns2 doesn't stop ns3
+	mAppFacade.sApp.EXPECT().Get("ns2", "abc", "").Return(nil, unknownErr)
+
+	// the app of ns3 is updated over its current version
+	cur := &specV1.Application{Namespace: "ns3", Name: "abc", Version: "5", Selector: "a=b", Description: "old"}
+	updated := &specV1.Application{Namespace: "ns3", Name: "abc", Version: "6", Selector: "a=b"}
+	mAppFacade.sApp.EXPECT().Get("ns3", "abc", "").Return(cur, nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, "ns3", "abc").Return(cur, nil)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, "ns3", gomock.Any()).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, "ns3", gomock.Any()).DoAndReturn(
+		func(_ interface{}, ns string, desired *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, "5", desired.Version)
+			return updated, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, "ns3", updated).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, "ns3", "abc", gomock.Any()).Return(nil)
+
+	res, err := appFacade.FanOutApp(context.Background(), app, configs, []string{"ns1", "ns2", "ns1", "ns3"})
+	assert.Error(t, err)
+	fanOutErr, ok := err.(*FanOutError)
+	assert.True(t, ok)
+	assert.Len(t, fanOutErr.Failures, 1)
+	assert.Contains(t, fanOutErr.Failures, "ns2")
+	assert.Contains(t, err.Error(), "ns2: ")
+	assert.Equal(t, map[string]*specV1.Application{"ns1": created, "ns3": updated}, res)
+
+	// the source app and configs are kept
+	assert.Equal(t, "src", app.Namespace)
+	assert.Equal(t, "9", app.Version)
+	assert.Equal(t, "3", configs[0].Version)
+
+	// the namespaces left fail with the error of ctx
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res, err = appFacade.FanOutApp(ctx, app, nil, []string{"ns1"})
+	assert.Empty(t, res)
+	assert.Equal(t, context.Canceled, errors.Cause(err.(*FanOutError).Failures["ns1"]))
+
+	res, err = appFacade.FanOutApp(context.Background(), app, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
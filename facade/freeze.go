@@ -0,0 +1,124 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// FreezeApp freezes the app, e.g. during the incident response, so that it can't be updated or deleted by anyone,
+// including the automated pipelines, until it's unfrozen. Only the callers granted PrivilegeFreezeApp by the
+// authorizer can freeze or unfreeze the apps. Freezing the app frozen already does nothing.
+func (a *facade) FreezeApp(ctx context.Context, ns, name string) (err error) {
+	defer observeCall(ns, "FreezeApp", time.Now(), &err)
+	if err = a.authorizePrivilege(ctx, ns, PrivilegeFreezeApp); err != nil {
+		return err
+	}
+	if err = a.checkAppFreeze(); err != nil {
+		return err
+	}
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	user, _ := common.UserFromContext(ctx)
+	err = a.runTx(ctx, ns, "FreezeApp", func(tx interface{}, undo *compensations) error {
+		if _, err := a.app.Get(ns, name, ""); err != nil {
+			return errors.Trace(err)
+		}
+		undo.onCommit(func() { a.invalidateCachedApp(ns, name) })
+		return a.freeze.CreateAppFreeze(tx, &models.AppFreeze{
+			Namespace:  ns,
+			Name:       name,
+			FrozenBy:   user.Name,
+			FreezeTime: time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		return err
+	}
+	a.log.Info("app frozen", log.Any(common.KeyContextNamespace, ns), log.Any("name", name), log.Any("user", user.Name))
+	return nil
+}
+
+// UnfreezeApp unfreezes the app frozen by FreezeApp, unfreezing the app not frozen does nothing
+func (a *facade) UnfreezeApp(ctx context.Context, ns, name string) (err error) {
+	defer observeCall(ns, "UnfreezeApp", time.Now(), &err)
+	if err = a.authorizePrivilege(ctx, ns, PrivilegeFreezeApp); err != nil {
+		return err
+	}
+	if err = a.checkAppFreeze(); err != nil {
+		return err
+	}
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	user, _ := common.UserFromContext(ctx)
+	err = a.runTx(ctx, ns, "UnfreezeApp", func(tx interface{}, undo *compensations) error {
+		undo.onCommit(func() { a.invalidateCachedApp(ns, name) })
+		return a.freeze.DeleteAppFreeze(tx, ns, name)
+	})
+	if err != nil {
+		return err
+	}
+	a.log.Info("app unfrozen", log.Any(common.KeyContextNamespace, ns), log.Any("name", name), log.Any("user", user.Name))
+	return nil
+}
+
+func (a *facade) checkAppFreeze() error {
+	if a.freeze == nil {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the freeze of the apps is disabled"))
+	}
+	return nil
+}
+
+// getAppFreeze gets the freeze of the app within the transaction, nil is returned if the app isn't frozen
+func (a *facade) getAppFreeze(tx interface{}, ns, name string) (*models.AppFreeze, error) {
+	if a.freeze == nil {
+		return nil, nil
+	}
+	freeze, err := a.freeze.GetAppFreeze(tx, ns, name)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	return freeze, nil
+}
+
+// checkAppFrozen returns ErrAppFrozen if the app to be changed is frozen
+func (a *facade) checkAppFrozen(tx interface{}, ns, name string) error {
+	freeze, err := a.getAppFreeze(tx, ns, name)
+	if err != nil {
+		return err
+	}
+	if freeze != nil {
+		return common.Error(common.ErrAppFrozen, common.Field("name", name))
+	}
+	return nil
+}
+
+// labelAppFrozen labels the app read if it's frozen
+func (a *facade) labelAppFrozen(tx interface{}, ns string, app *specV1.Application) error {
+	delete(app.Labels, common.LabelAppFrozen)
+	freeze, err := a.getAppFreeze(tx, ns, app.Name)
+	if err != nil || freeze == nil {
+		return err
+	}
+	if app.Labels == nil {
+		app.Labels = map[string]string{}
+	}
+	app.Labels[common.LabelAppFrozen] = "true"
+	return nil
+}
@@ -0,0 +1,112 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// privilegedAuthorizer allows all the accesses and grants the privileges to the admin only
+type privilegedAuthorizer struct{}
+
+func (privilegedAuthorizer) Authorize(_ context.Context, _ common.User, _ bool, _ string) error {
+	return nil
+}
+
+func (privilegedAuthorizer) AuthorizePrivilege(_ context.Context, user common.User, _ bool, ns, _ string) error {
+	if user.Name != "admin" {
+		return common.Error(common.ErrForbidden, common.Field("user", user.Name), common.Field("namespace", ns))
+	}
+	return nil
+}
+
+func TestFreezeApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		freeze:    mAppFacade.sFreeze,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Version: "1"}
+	admin := common.WithUser(context.Background(), common.User{ID: "1", Name: "admin"})
+	ci := common.WithUser(context.Background(), common.User{ID: "2", Name: "ci"})
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().BeginReadOnlyTx(gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	// the privilege is required once the authorizer is set
+	appFacade.SetAuthorizer(AuthorizerFunc(func(context.Context, common.User, bool, string) error { return nil }))
+	err := appFacade.FreezeApp(admin, ns, "abc")
+	assert.Equal(t, common.ErrForbidden, err.(errors.Coder).Code())
+	appFacade.SetAuthorizer(privilegedAuthorizer{})
+	err = appFacade.FreezeApp(ci, ns, "abc")
+	assert.Equal(t, common.ErrForbidden, err.(errors.Coder).Code())
+	err = appFacade.UnfreezeApp(ci, ns, "abc")
+	assert.Equal(t, common.ErrForbidden, err.(errors.Coder).Code())
+
+	// the app missing can't be frozen
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	err = appFacade.FreezeApp(admin, ns, "abc")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(app, nil)
+	mAppFacade.sFreeze.EXPECT().CreateAppFreeze(nil, gomock.Any()).DoAndReturn(func(_ interface{}, f *models.AppFreeze) error {
+		assert.Equal(t, ns, f.Namespace)
+		assert.Equal(t, "abc", f.Name)
+		assert.Equal(t, "admin", f.FrozenBy)
+		return nil
+	})
+	assert.NoError(t, appFacade.FreezeApp(admin, ns, "abc"))
+
+	// the frozen state is surfaced by the app read
+	frozen := &models.AppFreeze{Namespace: ns, Name: "abc", FrozenBy: "admin"}
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Namespace: ns, Name: "abc", Version: "1"}, nil)
+	mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(frozen, nil)
+	res, err := appFacade.GetApp(ci, ns, "abc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "true", res.Labels[common.LabelAppFrozen])
+
+	// the frozen app can't be updated, deleted or swapped
+	mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(frozen, nil).Times(3)
+	_, err = appFacade.UpdateApp(ci, ns, app, &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d"}, nil)
+	assert.Equal(t, common.ErrAppFrozen, err.(errors.Coder).Code())
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(&specV1.Application{Namespace: ns, Name: "abc"}, nil)
+	err = appFacade.DeleteApp(ci, ns, "abc", &specV1.Application{Namespace: ns, Name: "abc"})
+	assert.Equal(t, common.ErrAppFrozen, err.(errors.Coder).Code())
+	_, err = appFacade.SwapApps(ci, ns, "abc", "def")
+	assert.Equal(t, common.ErrAppFrozen, err.(errors.Coder).Code())
+
+	mAppFacade.sFreeze.EXPECT().DeleteAppFreeze(nil, ns, "abc").Return(nil)
+	assert.NoError(t, appFacade.UnfreezeApp(admin, ns, "abc"))
+
+	// the app unfrozen can be updated again
+	newApp := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Description: "changed", Labels: map[string]string{common.LabelAppFrozen: "true"}}
+	mAppFacade.sFreeze.EXPECT().GetAppFreeze(nil, ns, "abc").Return(nil, common.Error(common.ErrResourceNotFound))
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Description: "changed", Labels: map[string]string{}, Version: "1"}).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", nil).Return(nil)
+	_, err = appFacade.UpdateApp(ci, ns, app, newApp, nil)
+	assert.NoError(t, err)
+
+	// disabled
+	appFacade.freeze = nil
+	err = appFacade.FreezeApp(admin, ns, "abc")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
@@ -0,0 +1,136 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// the dependencies of the facade checked by HealthCheck
+const (
+	HealthTransaction = "transaction"
+	HealthCron        = "cron"
+	HealthConfig      = "config"
+	HealthNode        = "node"
+	HealthIndex       = "index"
+)
+
+// healthProbe the namespace and the name looked up to probe the stores, which are not expected to exist
+const healthProbe = "baetyl-health-probe"
+
+// HealthError reports the unhealthy dependencies of the facade with their failures
+type HealthError struct {
+	Failures map[string]error
+}
+
+// Code the unhealthy dependencies are internal errors
+func (e *HealthError) Code() string {
+	return common.ErrUnknown
+}
+
+func (e *HealthError) Error() string {
+	names := make([]string, 0, len(e.Failures))
+	for name := range e.Failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", name, e.Failures[name].Error()))
+	}
+	return fmt.Sprintf("unhealthy dependencies: %s", strings.Join(msgs, "; "))
+}
+
+// HealthCheck checks the facade can serve the writes: a transaction can be begun and rolled back, and the cron,
+// config, node and index stores respond to a lookup. The checks run concurrently and the ones not finished
+// before ctx is done fail with its error. *HealthError is returned if any dependency is unhealthy.
+func (a *facade) HealthCheck(ctx context.Context) error {
+	checks := map[string]func() error{
+		HealthTransaction: func() error {
+			tx, err := a.txFactory.BeginTx(ctx, nil)
+			if err != nil {
+				return err
+			}
+			a.txFactory.Rollback(tx)
+			return nil
+		},
+		HealthCron: func() error {
+			_, err := a.cron.GetCron(nil, healthProbe, healthProbe)
+			return err
+		},
+		HealthConfig: func() error {
+			_, err := a.config.Get(healthProbe, healthProbe, "")
+			return err
+		},
+		HealthNode: func() error {
+			_, err := a.node.Get(nil, healthProbe, healthProbe)
+			return err
+		},
+		HealthIndex: func() error {
+			_, err := a.index.ListNodesByApp(healthProbe, healthProbe)
+			return err
+		},
+	}
+
+	type result struct {
+		name string
+		err  error
+	}
+	// buffered so that the checks finished after ctx is done don't block
+	results := make(chan result, len(checks))
+	for name, check := range checks {
+		go func(name string, check func() error) {
+			results <- result{name: name, err: check()}
+		}(name, check)
+	}
+	failures := map[string]error{}
+	pending := map[string]bool{}
+	for name := range checks {
+		pending[name] = true
+	}
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.name)
+			if r.err != nil && !isNotFound(r.err) {
+				failures[r.name] = r.err
+			}
+		case <-ctx.Done():
+			for name := range pending {
+				failures[name] = errors.Trace(ctx.Err())
+			}
+			pending = nil
+		}
+	}
+	if len(failures) > 0 {
+		return &HealthError{Failures: failures}
+	}
+	return nil
+}
+
+// isNotFound tells whether the store responds that the resource doesn't exist
+func isNotFound(err error) bool {
+	return err != nil && common.CodeOf(err) == common.ErrResourceNotFound
+}
+
+// sharedCode returns the code shared by all the failures, so that the aggregated failure maps to the same
+// HTTP status as each of them. ErrUnknown is returned if the failures differ in the codes.
+func sharedCode(failures map[string]error) string {
+	code := ""
+	for _, err := range failures {
+		c := string(common.CodeOf(err))
+		if code != "" && code != c {
+			return common.ErrUnknown
+		}
+		code = c
+	}
+	if code == "" {
+		return common.ErrUnknown
+	}
+	return code
+}
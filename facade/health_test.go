@@ -0,0 +1,68 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestHealthCheck(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "probe"), common.Field("name", healthProbe))
+
+	// the stores responding not found are healthy
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), nil).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sCron.EXPECT().GetCron(nil, healthProbe, healthProbe).Return(nil, notFound)
+	mAppFacade.sConfig.EXPECT().Get(healthProbe, healthProbe, "").Return(nil, notFound)
+	mAppFacade.sNode.EXPECT().Get(nil, healthProbe, healthProbe).Return(nil, notFound)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(healthProbe, healthProbe).Return(nil, nil)
+	assert.NoError(t, appFacade.HealthCheck(context.Background()))
+
+	// the unhealthy dependencies are identified
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), nil).Return(nil, unknownErr)
+	mAppFacade.sCron.EXPECT().GetCron(nil, healthProbe, healthProbe).Return(&models.Cron{}, nil)
+	mAppFacade.sConfig.EXPECT().Get(healthProbe, healthProbe, "").Return(nil, notFound)
+	mAppFacade.sNode.EXPECT().Get(nil, healthProbe, healthProbe).Return(nil, notFound)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(healthProbe, healthProbe).Return(nil, unknownErr)
+	err := appFacade.HealthCheck(context.Background())
+	healthErr, ok := err.(*HealthError)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]error{HealthTransaction: unknownErr, HealthIndex: unknownErr}, healthErr.Failures)
+	assert.Contains(t, err.Error(), "index: unknown; transaction: unknown")
+
+	// the checks not finished in time fail with the deadline
+	blocked := make(chan struct{})
+	defer close(blocked)
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), nil).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sCron.EXPECT().GetCron(nil, healthProbe, healthProbe).Return(nil, notFound)
+	mAppFacade.sConfig.EXPECT().Get(healthProbe, healthProbe, "").Return(nil, notFound)
+	mAppFacade.sNode.EXPECT().Get(nil, healthProbe, healthProbe).DoAndReturn(func(_ interface{}, _, _ string) (*specV1.Node, error) {
+		<-blocked
+		return nil, notFound
+	})
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(healthProbe, healthProbe).Return(nil, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = appFacade.HealthCheck(ctx)
+	healthErr, ok = err.(*HealthError)
+	assert.True(t, ok)
+	assert.Len(t, healthErr.Failures, 1)
+	assert.Error(t, healthErr.Failures[HealthNode])
+}
@@ -0,0 +1,110 @@
+package facade
+
+import (
+	"context"
+	"sync"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// AppHook is invoked around the mutations of the apps, e.g. to enforce the policies and send the notifications.
+// The Pre hooks run within the transaction before the app is written, the mutation is rolled back if any of them
+// returns an error. The Post hooks run after the commit with the committed app and can't affect the outcome.
+type AppHook interface {
+	PreCreate(ctx context.Context, tx interface{}, ns string, app *specV1.Application) error
+	PostCreate(ctx context.Context, ns string, app *specV1.Application)
+	PreUpdate(ctx context.Context, tx interface{}, ns string, oldApp, app *specV1.Application) error
+	PostUpdate(ctx context.Context, ns string, app *specV1.Application)
+	PreDelete(ctx context.Context, tx interface{}, ns string, app *specV1.Application) error
+	PostDelete(ctx context.Context, ns string, app *specV1.Application)
+}
+
+// NopAppHook does nothing, it's embedded by the hooks which are only interested in some of the mutations
+type NopAppHook struct{}
+
+func (NopAppHook) PreCreate(context.Context, interface{}, string, *specV1.Application) error {
+	return nil
+}
+
+func (NopAppHook) PostCreate(context.Context, string, *specV1.Application) {}
+
+func (NopAppHook) PreUpdate(context.Context, interface{}, string, *specV1.Application, *specV1.Application) error {
+	return nil
+}
+
+func (NopAppHook) PostUpdate(context.Context, string, *specV1.Application) {}
+
+func (NopAppHook) PreDelete(context.Context, interface{}, string, *specV1.Application) error {
+	return nil
+}
+
+func (NopAppHook) PostDelete(context.Context, string, *specV1.Application) {}
+
+// appHooks the hooks registered to the facade, which are invoked in the order they're registered
+type appHooks struct {
+	mu    sync.RWMutex
+	hooks []AppHook
+}
+
+func (h *appHooks) add(hook AppHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hooks = append(h.hooks, hook)
+}
+
+func (h *appHooks) list() []AppHook {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.hooks
+}
+
+// RegisterAppHook registers the hook invoked around the mutations of the apps after the hooks registered before
+func (a *facade) RegisterAppHook(hook AppHook) {
+	a.hooks.add(hook)
+}
+
+// preCreateApp runs the PreCreate hooks, the first error vetoes the creation
+func (a *facade) preCreateApp(ctx context.Context, tx interface{}, ns string, app *specV1.Application) error {
+	for _, h := range a.hooks.list() {
+		if err := h.PreCreate(ctx, tx, ns, app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preUpdateApp runs the PreUpdate hooks, the first error vetoes the update
+func (a *facade) preUpdateApp(ctx context.Context, tx interface{}, ns string, oldApp, app *specV1.Application) error {
+	for _, h := range a.hooks.list() {
+		if err := h.PreUpdate(ctx, tx, ns, oldApp, app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preDeleteApp runs the PreDelete hooks, the first error vetoes the deletion
+func (a *facade) preDeleteApp(ctx context.Context, tx interface{}, ns string, app *specV1.Application) error {
+	for _, h := range a.hooks.list() {
+		if err := h.PreDelete(ctx, tx, ns, app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postAppHooks runs the Post hooks of the committed mutation
+func (a *facade) postAppHooks(ctx context.Context, action models.AppAction, ns string, app *specV1.Application) {
+	for _, h := range a.hooks.list() {
+		switch action {
+		case models.AppCreated:
+			h.PostCreate(ctx, ns, app)
+		case models.AppUpdated:
+			h.PostUpdate(ctx, ns, app)
+		case models.AppDeleted:
+			h.PostDelete(ctx, ns, app)
+		}
+	}
+}
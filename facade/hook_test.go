@@ -0,0 +1,103 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHook struct {
+	NopAppHook
+	name   string
+	calls  *[]string
+	vetoed error
+}
+
+func (h *recordHook) PreCreate(_ context.Context, _ interface{}, _ string, app *specV1.Application) error {
+	*h.calls = append(*h.calls, h.name+".PreCreate."+app.Name)
+	return h.vetoed
+}
+
+func (h *recordHook) PostCreate(_ context.Context, _ string, app *specV1.Application) {
+	*h.calls = append(*h.calls, h.name+".PostCreate."+app.Version)
+}
+
+func (h *recordHook) PreUpdate(_ context.Context, _ interface{}, _ string, oldApp, app *specV1.Application) error {
+	*h.calls = append(*h.calls, h.name+".PreUpdate."+oldApp.Selector+"."+app.Selector)
+	return h.vetoed
+}
+
+func (h *recordHook) PreDelete(_ context.Context, _ interface{}, _ string, app *specV1.Application) error {
+	*h.calls = append(*h.calls, h.name+".PreDelete."+app.Name)
+	return h.vetoed
+}
+
+func (h *recordHook) PostDelete(_ context.Context, _ string, app *specV1.Application) {
+	*h.calls = append(*h.calls, h.name+".PostDelete."+app.Name)
+}
+
+func TestAppHooks(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	var calls []string
+	first := &recordHook{name: "first", calls: &calls}
+	second := &recordHook{name: "second", calls: &calls}
+	appFacade.RegisterAppHook(first)
+	appFacade.RegisterAppHook(second)
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+	// the hooks chain in the order they're registered
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().Times(2)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).Return(&specV1.Application{Name: "abc", Version: "1"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, &specV1.Application{Name: "abc"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first.PreCreate.abc", "second.PreCreate.abc", "first.PostCreate.1", "second.PostCreate.1"}, calls)
+
+	calls = nil
+	app := &specV1.Application{Name: "abc", Namespace: ns}
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "abc", "").Return(nil)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	err = appFacade.DeleteApp(context.Background(), ns, "abc", app)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first.PreDelete.abc", "second.PreDelete.abc", "first.PostDelete.abc", "second.PostDelete.abc"}, calls)
+
+	// the first hook vetoes, the mutation is rolled back without the following and Post hooks
+	first.vetoed = unknownErr
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(3)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(app, nil).Times(2)
+	calls = nil
+	mAppFacade.sApp.EXPECT().CreateWithBase(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, &specV1.Application{Name: "abc"}, nil)
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, []string{"first.PreCreate.abc"}, calls)
+
+	calls = nil
+	mAppFacade.sApp.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	_, err = appFacade.UpdateApp(context.Background(), ns, &specV1.Application{Name: "abc", Selector: "a=a"}, &specV1.Application{Name: "abc", Selector: "a=b"}, nil)
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, []string{"first.PreUpdate.a=a.a=b"}, calls)
+
+	calls = nil
+	mAppFacade.sApp.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	err = appFacade.DeleteApp(context.Background(), ns, "abc", app)
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, []string{"first.PreDelete.abc"}, calls)
+}
@@ -0,0 +1,63 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// getIdempotentApp gets the app already created with the idempotency key, nil if the key is unused.
+// It's a conflict if the key is used by another app.
+func (a *facade) getIdempotentApp(ctx context.Context, ns, key, name string) (*specV1.Application, error) {
+	if a.idempotency == nil || key == "" {
+		return nil, nil
+	}
+	idempotency, err := a.idempotency.GetAppIdempotency(ns, key)
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if idempotency.Name != name {
+		return nil, common.Error(common.ErrIdempotencyKeyConflict,
+			common.Field("key", key),
+			common.Field("name", idempotency.Name))
+	}
+	return a.GetApp(ctx, ns, name, "")
+}
+
+// createAppIdempotency writes the idempotency key within the transaction of the app creation,
+// the concurrent creation with the same key fails on the unique key
+func (a *facade) createAppIdempotency(tx interface{}, ns, key, name string) error {
+	if a.idempotency == nil || key == "" {
+		return nil
+	}
+	return a.idempotency.CreateAppIdempotency(tx, &models.AppIdempotency{
+		Namespace:  ns,
+		Key:        key,
+		Name:       name,
+		ExpireTime: time.Now().Add(a.conf.Idempotency.TTL).UTC(),
+	})
+}
+
+// GCIdempotencyKeys deletes the expired idempotency keys of app creation
+func (a *facade) GCIdempotencyKeys(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Trace(err)
+	}
+	n, err := a.idempotency.DeleteExpiredAppIdempotency(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		a.log.Info("deleted expired idempotency keys", log.Any("count", n))
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestCreateAppIdempotency(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:        mAppFacade.sNode,
+		app:         mAppFacade.sApp,
+		config:      mAppFacade.sConfig,
+		index:       mAppFacade.sIndex,
+		cron:        mAppFacade.sCron,
+		idempotency: mAppFacade.sIdem,
+		txFactory:   mAppFacade.txFactory,
+		conf:        config.Facade{Idempotency: config.Idempotency{TTL: time.Hour}},
+	}
+	ns := "baetyl-cloud"
+	ctx := common.WithIdempotencyKey(context.Background(), "key")
+	app := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b"}
+	created := &specV1.Application{Namespace: ns, Name: "abc", Version: "1", Selector: "a=b"}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "appIdempotency"), common.Field("name", "key"))
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// created with the key
+	mAppFacade.sIdem.EXPECT().GetAppIdempotency(ns, "key").Return(nil, notFound)
+	mAppFacade.sIdem.EXPECT().CreateAppIdempotency(nil, gomock.Any()).DoAndReturn(func(_ interface{}, idempotency *models.AppIdempotency) error {
+		assert.Equal(t, ns, idempotency.Namespace)
+		assert.Equal(t, "key", idempotency.Key)
+		assert.Equal(t, "abc", idempotency.Name)
+		assert.True(t, idempotency.ExpireTime.After(time.Now()))
+		return nil
+	})
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(created, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, created).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	res, err := appFacade.CreateApp(ctx, ns, nil, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &AppResult{App: created, Nodes: []string{"n1"}}, res)
+
+	// retried with the same key
+	mAppFacade.sIdem.EXPECT().GetAppIdempotency(ns, "key").Return(&models.AppIdempotency{Namespace: ns, Key: "key", Name: "abc"}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(created, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "abc").Return([]string{"n1"}, nil)
+	res, err = appFacade.CreateApp(ctx, ns, nil, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &AppResult{App: created, Nodes: []string{"n1"}}, res)
+
+	// the key used by another app
+	mAppFacade.sIdem.EXPECT().GetAppIdempotency(ns, "key").Return(&models.AppIdempotency{Namespace: ns, Key: "key", Name: "other"}, nil)
+	_, err = appFacade.CreateApp(ctx, ns, nil, app, nil)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrIdempotencyKeyConflict, e.Code())
+
+	// the concurrent creation with the same key wins
+	gomock.InOrder(
+		mAppFacade.sIdem.EXPECT().GetAppIdempotency(ns, "key").Return(nil, notFound),
+		mAppFacade.sIdem.EXPECT().CreateAppIdempotency(nil, gomock.Any()).Return(errors.New("Error 1062: Duplicate entry")),
+		mAppFacade.sIdem.EXPECT().GetAppIdempotency(ns, "key").Return(&models.AppIdempotency{Namespace: ns, Key: "key", Name: "abc"}, nil),
+	)
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(created, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "abc").Return([]string{"n1"}, nil)
+	res, err = appFacade.CreateApp(ctx, ns, nil, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &AppResult{App: created, Nodes: []string{"n1"}}, res)
+
+	// failed without the key being used
+	gomock.InOrder(
+		mAppFacade.sIdem.EXPECT().GetAppIdempotency(ns, "key").Return(nil, notFound),
+		mAppFacade.sIdem.EXPECT().CreateAppIdempotency(nil, gomock.Any()).Return(nil),
+		mAppFacade.sIdem.EXPECT().GetAppIdempotency(ns, "key").Return(nil, notFound),
+	)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(nil, unknownErr)
+	_, err = appFacade.CreateApp(ctx, ns, nil, app, nil)
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestGCIdempotencyKeys(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		idempotency: mAppFacade.sIdem,
+		log:         log.L(),
+	}
+
+	mAppFacade.sIdem.EXPECT().DeleteExpiredAppIdempotency(gomock.Any()).Return(int64(2), nil)
+	assert.NoError(t, appFacade.GCIdempotencyKeys(context.Background()))
+
+	mAppFacade.sIdem.EXPECT().DeleteExpiredAppIdempotency(gomock.Any()).Return(int64(0), unknownErr)
+	assert.Equal(t, unknownErr, appFacade.GCIdempotencyKeys(context.Background()))
+}
+
+func TestRunPeriodically(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		RunPeriodically(ctx, "test", time.Millisecond, func(context.Context) error {
+			select {
+			case runs <- struct{}{}:
+			default:
+			}
+			return unknownErr
+		})
+		close(done)
+	}()
+	<-runs
+	<-runs
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job not stopped after the context is done")
+	}
+
+	// the job with the interval not positive isn't run
+	RunPeriodically(context.Background(), "zero", 0, func(context.Context) error {
+		t.Fatal("job run without a positive interval")
+		return nil
+	})
+}
@@ -0,0 +1,210 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ImportConflictPolicy decides what to do if the app, a config or a secret of the bundle already exists
+type ImportConflictPolicy string
+
+const (
+	// ImportConflictFail fails the import with ErrResourceConflict, it's the default
+	ImportConflictFail ImportConflictPolicy = "fail"
+	// ImportConflictOverwrite updates the existing resources with the ones of the bundle
+	ImportConflictOverwrite ImportConflictPolicy = "overwrite"
+	// ImportConflictRename imports the conflicting resources with new names
+	ImportConflictRename ImportConflictPolicy = "rename"
+)
+
+// ImportOptions the options of importing an app bundle
+type ImportOptions struct {
+	Conflict ImportConflictPolicy `json:"conflict,omitempty"`
+}
+
+// ImportApp imports the app bundle exported by ExportApp into the namespace, the configs and secrets
+// of the bundle are written and referenced by the app in the same transaction as the app,
+// so nothing is left if the import fails. The secrets only referenced by the bundle should exist.
+func (a *facade) ImportApp(ctx context.Context, ns string, data []byte, opts ImportOptions) (res *specV1.Application, err error) {
+	defer observeCall(ns, "ImportApp", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	policy := opts.Conflict
+	if policy == "" {
+		policy = ImportConflictFail
+	}
+	if policy != ImportConflictFail && policy != ImportConflictOverwrite && policy != ImportConflictRename {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the conflict policy (%s) of import is not supported", policy)))
+	}
+	bundle, err := ParseAppBundle(data)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.validAppName(bundle.App.Name); err != nil {
+		return nil, err
+	}
+	if err = validAppCron(bundle.App, true); err != nil {
+		return nil, err
+	}
+
+	unlock, err := a.lockAppQuota(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var nodes []string
+	action := models.AppCreated
+	err = a.runTx(ctx, ns, "ImportApp", func(tx interface{}, undo *compensations) error {
+		app, err := copyApp(bundle.App)
+		if err != nil {
+			return err
+		}
+		app.Namespace = ns
+		cur, err := a.getAppIfExists(ns, app.Name)
+		if err != nil {
+			return err
+		}
+		if cur != nil {
+			switch policy {
+			case ImportConflictFail:
+				return common.Error(common.ErrResourceConflict, common.Field("type", common.APP), common.Field("name", app.Name))
+			case ImportConflictRename:
+				app.Name = strings.ToLower(fmt.Sprintf("%s-%s", app.Name, common.RandString(5)))
+				cur = nil
+			}
+		}
+
+		renamed := map[string]string{}
+		configs := make([]specV1.Configuration, 0, len(bundle.Configs))
+		for _, c := range bundle.Configs {
+			_, err := a.config.Get(ns, c.Name, "")
+			name, err := a.importName(policy, common.Config, c.Name, bundle.App.Name, app.Name, err)
+			if err != nil {
+				return err
+			}
+			cfg := c
+			cfg.Name = name
+			cfg.Namespace = ns
+			configs = append(configs, cfg)
+			renamed["config/"+c.Name] = name
+		}
+		for _, s := range bundle.Secrets {
+			name, err := a.importSecret(tx, ns, s, policy, bundle.App.Name, app.Name, undo)
+			if err != nil {
+				return err
+			}
+			renamed["secret/"+s.Name] = name
+		}
+		for _, name := range bundle.SecretRefs {
+			if _, err := a.secret.Get(ns, name, ""); err != nil {
+				return err
+			}
+		}
+		for _, v := range app.Volumes {
+			if v.Config != nil {
+				if name, ok := renamed["config/"+v.Config.Name]; ok {
+					v.Config.Name = name
+				}
+				v.Config.Version = ""
+			}
+			if v.Secret != nil {
+				if name, ok := renamed["secret/"+v.Secret.Name]; ok {
+					v.Secret.Name = name
+				}
+				v.Secret.Version = ""
+			}
+		}
+
+		if cur != nil {
+			action = models.AppUpdated
+			app.Version = cur.Version
+			app.CreationTimestamp = cur.CreationTimestamp
+			res, nodes, err = a.updateApp(ctx, tx, ns, cur, app, configs, undo)
+			return err
+		}
+		if err = a.checkAppQuota(ns, 1); err != nil {
+			return err
+		}
+		if err = a.checkAppRefs(ns, app, configs); err != nil {
+			return err
+		}
+		res, nodes, err = a.createApp(ctx, tx, ns, nil, app, configs, undo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.publishAppEvent(ctx, action, ns, res, nodes)
+	return res, nil
+}
+
+// importSecret creates the secret of the bundle, the existing one is handled by the policy,
+// and the name of the imported secret is returned
+func (a *facade) importSecret(tx interface{}, ns string, s specV1.Secret, policy ImportConflictPolicy, appName, newAppName string, undo *compensations) (string, error) {
+	old, err := a.secret.Get(ns, s.Name, "")
+	name, err := a.importName(policy, common.Secret, s.Name, appName, newAppName, err)
+	if err != nil {
+		return "", err
+	}
+	secret := s
+	secret.Name = name
+	secret.Namespace = ns
+	if old == nil || name != s.Name {
+		_, err = a.secret.Create(tx, ns, &secret)
+		return name, err
+	}
+	// the secret is not updated within the transaction
+	secret.Version = old.Version
+	if _, err = a.secret.Update(ns, &secret); err != nil {
+		return "", err
+	}
+	undo.add(func() error {
+		_, err := a.secret.Update(ns, old)
+		return err
+	})
+	return name, nil
+}
+
+// importName returns the name to import the resource with by the policy, getErr is the error of getting
+// the existing resource of the name. The generated function configs are renamed after the new app.
+func (a *facade) importName(policy ImportConflictPolicy, kind common.Resource, name, appName, newAppName string, getErr error) (string, error) {
+	if getErr != nil {
+		if e, ok := getErr.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return name, nil
+		}
+		return "", getErr
+	}
+	switch policy {
+	case ImportConflictOverwrite:
+		return name, nil
+	case ImportConflictRename:
+		if kind == common.Config && a.isFunctionConfig(name) {
+			return a.cloneConfigName(name, appName, newAppName), nil
+		}
+		return strings.ToLower(fmt.Sprintf("%s-%s", name, common.RandString(5))), nil
+	default:
+		return "", common.Error(common.ErrResourceConflict, common.Field("type", kind), common.Field("name", name))
+	}
+}
+
+func (a *facade) getAppIfExists(ns, name string) (*specV1.Application, error) {
+	app, err := a.app.Get(ns, name, "")
+	if err != nil {
+		if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return app, nil
+}
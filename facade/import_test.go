@@ -0,0 +1,155 @@
+package facade
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func newTestBundle(t *testing.T) []byte {
+	data, err := yaml.Marshal(&AppBundle{
+		Version: AppBundleVersion,
+		App: &specV1.Application{
+			Name:     "abc",
+			Selector: "a=b",
+			Volumes: []specV1.Volume{
+				{Name: "user", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "user"}}},
+				{Name: "cert", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "cert"}}},
+				{Name: "ref", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "ref"}}},
+			},
+		},
+		Configs:    []specV1.Configuration{{Name: "user", Data: map[string]string{"k": "v"}}},
+		Secrets:    []specV1.Secret{{Name: "cert", Data: map[string][]byte{"k": []byte("v")}}},
+		SecretRefs: []string{"ref"},
+	})
+	assert.NoError(t, err)
+	return data
+}
+
+func TestImportApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		secret:    mAppFacade.sSecret,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "production"
+	data := newTestBundle(t)
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "app"))
+
+	_, err := appFacade.ImportApp(context.Background(), ns, data, ImportOptions{Conflict: "merge"})
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+	_, err = appFacade.ImportApp(context.Background(), ns, []byte("version: v0"), ImportOptions{})
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	// nothing exists
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, notFound)
+	mAppFacade.sConfig.EXPECT().Get(ns, "user", "").Return(nil, notFound)
+	mAppFacade.sSecret.EXPECT().Get(ns, "cert", "").Return(nil, notFound)
+	mAppFacade.sSecret.EXPECT().Create(nil, ns, &specV1.Secret{Name: "cert", Namespace: ns, Data: map[string][]byte{"k": []byte("v")}}).Return(nil, nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "ref", "").Return(&specV1.Secret{Name: "ref"}, nil).Times(2)
+	// the secret created is found by the check of the references
+	mAppFacade.sSecret.EXPECT().Get(ns, "cert", "").Return(&specV1.Secret{Name: "cert"}, nil)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, &specV1.Configuration{Name: "user", Namespace: ns, Data: map[string]string{"k": "v"}}).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).DoAndReturn(
+		func(_ interface{}, _ string, app, _ *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, ns, app.Namespace)
+			app.Version = "1"
+			return app, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	res, err := appFacade.ImportApp(context.Background(), ns, data, ImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", res.Name)
+	assert.Equal(t, "1", res.Version)
+
+	// the app exists
+	cur := &specV1.Application{Name: "abc", Namespace: ns, Version: "7", Selector: "a=b"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(cur, nil)
+	_, err = appFacade.ImportApp(context.Background(), ns, data, ImportOptions{Conflict: ImportConflictFail})
+	assert.Equal(t, common.ErrResourceConflict, err.(errors.Coder).Code())
+
+	// a config exists
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, notFound)
+	mAppFacade.sConfig.EXPECT().Get(ns, "user", "").Return(&specV1.Configuration{Name: "user"}, nil)
+	_, err = appFacade.ImportApp(context.Background(), ns, data, ImportOptions{})
+	assert.Equal(t, common.ErrResourceConflict, err.(errors.Coder).Code())
+
+	// the existing app and resources are renamed
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(cur, nil)
+	mAppFacade.sConfig.EXPECT().Get(ns, "user", "").Return(&specV1.Configuration{Name: "user"}, nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "cert", "").Return(&specV1.Secret{Name: "cert"}, nil)
+	mAppFacade.sSecret.EXPECT().Create(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, secret *specV1.Secret) (*specV1.Secret, error) {
+			assert.True(t, strings.HasPrefix(secret.Name, "cert-"))
+			return secret, nil
+		})
+	mAppFacade.sSecret.EXPECT().Get(ns, "ref", "").Return(&specV1.Secret{Name: "ref"}, nil).Times(2)
+	mAppFacade.sSecret.EXPECT().Get(ns, gomock.Any(), "").Return(&specV1.Secret{}, nil)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+			assert.True(t, strings.HasPrefix(cfg.Name, "user-"))
+			return cfg, nil
+		})
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).DoAndReturn(
+		func(_ interface{}, _ string, app, _ *specV1.Application) (*specV1.Application, error) {
+			assert.True(t, strings.HasPrefix(app.Name, "abc-"))
+			for _, v := range app.Volumes {
+				if v.Config != nil {
+					assert.True(t, strings.HasPrefix(v.Config.Name, "user-"))
+				} else if v.Secret.Name != "ref" {
+					assert.True(t, strings.HasPrefix(v.Secret.Name, "cert-"))
+				}
+			}
+			return app, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), nil).Return(nil)
+	res, err = appFacade.ImportApp(context.Background(), ns, data, ImportOptions{Conflict: ImportConflictRename})
+	assert.NoError(t, err)
+	assert.NotEqual(t, "abc", res.Name)
+
+	// the existing app and resources are overwritten, the secret is restored on failure
+	oldSecret := &specV1.Secret{Name: "cert", Namespace: ns, Version: "3"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(cur, nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(cur, nil)
+	mAppFacade.sConfig.EXPECT().Get(ns, "user", "").Return(&specV1.Configuration{Name: "user"}, nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "cert", "").Return(oldSecret, nil).Times(2)
+	mAppFacade.sSecret.EXPECT().Update(ns, &specV1.Secret{Name: "cert", Namespace: ns, Version: "3", Data: map[string][]byte{"k": []byte("v")}}).Return(nil, nil)
+	mAppFacade.sSecret.EXPECT().Get(ns, "ref", "").Return(&specV1.Secret{Name: "ref"}, nil).Times(2)
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, "7", app.Version)
+			return app, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr)
+	mAppFacade.sSecret.EXPECT().Update(ns, oldSecret).Return(nil, nil)
+	_, err = appFacade.ImportApp(context.Background(), ns, data, ImportOptions{Conflict: ImportConflictOverwrite})
+	assert.Equal(t, unknownErr, err)
+}
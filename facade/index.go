@@ -0,0 +1,846 @@
+package facade
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/baetyl/baetyl-go/v2/utils"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// the reasons of the node-app index issues
+const (
+	// IndexMissingApp the index references an app which doesn't exist
+	IndexMissingApp = "missingApp"
+	// IndexMissingVersion the desire of the indexed node lacks the current version of the app
+	IndexMissingVersion = "missingVersion"
+	// IndexStaleNode the index references a node which isn't matched by the app selector
+	IndexStaleNode = "staleNode"
+	// IndexMissingNode the node matched by the app selector isn't indexed
+	IndexMissingNode = "missingNode"
+)
+
+// IndexIssue an inconsistency between the node-app indexes, the apps and the node desires
+type IndexIssue struct {
+	App    string `json:"app"`
+	Node   string `json:"node"`
+	Reason string `json:"reason"`
+}
+
+// IndexReport the result of checking the node-app indexes of a namespace
+type IndexReport struct {
+	Namespace string `json:"namespace"`
+	Apps      int    `json:"apps"`
+	Nodes     int    `json:"nodes"`
+	// Entries the node-app index entries of the nodes
+	Entries int          `json:"entries"`
+	Issues  []IndexIssue `json:"issues"`
+}
+
+// VerifyAppIndex walks the apps and the nodes of the namespace page by page,
+// and reports the node-app indexes inconsistent with the apps and the node desires
+func (a *facade) VerifyAppIndex(ctx context.Context, ns string) (*IndexReport, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	return a.checkAppIndex(ctx, ns, false)
+}
+
+// RepairAppIndex reports the inconsistent node-app indexes as VerifyAppIndex, and fixes them
+// by recomputing from the current app selectors. Each app is repaired in its own transaction
+// with the state reloaded, so it is safe to run on a live system.
+func (a *facade) RepairAppIndex(ctx context.Context, ns string) (*IndexReport, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	return a.checkAppIndex(ctx, ns, true)
+}
+
+func (a *facade) checkAppIndex(ctx context.Context, ns string, repair bool) (*IndexReport, error) {
+	report := &IndexReport{Namespace: ns, Issues: []IndexIssue{}}
+	// the apps of the same page are repaired only once
+	fix := func(repaired map[string]bool, issues []IndexIssue) error {
+		for _, issue := range issues {
+			if !repair || repaired[issue.App] {
+				continue
+			}
+			if err := a.repairAppIndex(ctx, ns, issue.App); err != nil {
+				return err
+			}
+			repaired[issue.App] = true
+		}
+		return nil
+	}
+
+	opt := &models.ListOptions{Limit: int64(a.conf.IndexPageSize)}
+	for {
+		apps, err := a.app.List(ns, opt)
+		if err != nil {
+			return report, errors.Trace(err)
+		}
+		repaired := map[string]bool{}
+		for _, item := range apps.Items {
+			if err = ctx.Err(); err != nil {
+				return report, errors.Trace(err)
+			}
+			issues, err := a.verifyNodesOfApp(ns, item)
+			if err != nil {
+				return report, err
+			}
+			report.Apps++
+			report.Issues = append(report.Issues, issues...)
+			if err = fix(repaired, issues); err != nil {
+				return report, err
+			}
+		}
+		if apps.ListOptions == nil || apps.Continue == "" {
+			break
+		}
+		opt.Continue = apps.Continue
+	}
+
+	opt = &models.ListOptions{Limit: int64(a.conf.IndexPageSize)}
+	for {
+		nodes, err := a.node.List(ns, opt)
+		if err != nil {
+			return report, errors.Trace(err)
+		}
+		repaired := map[string]bool{}
+		cache := map[string]*specV1.Application{}
+		for i := range nodes.Items {
+			if err = ctx.Err(); err != nil {
+				return report, errors.Trace(err)
+			}
+			issues, entries, err := a.verifyAppsOfNode(ns, &nodes.Items[i], cache)
+			if err != nil {
+				return report, err
+			}
+			report.Nodes++
+			report.Entries += entries
+			report.Issues = append(report.Issues, issues...)
+			if err = fix(repaired, issues); err != nil {
+				return report, err
+			}
+		}
+		if nodes.ListOptions == nil || nodes.Continue == "" {
+			break
+		}
+		opt.Continue = nodes.Continue
+	}
+
+	if len(report.Issues) > 0 {
+		a.log.Warn("inconsistent node-app indexes", log.Any("namespace", ns),
+			log.Any("issues", len(report.Issues)), log.Any("repair", repair))
+	}
+	observeIndexReport(report, repair)
+	return report, nil
+}
+
+// verifyNodesOfApp compares the nodes indexed by the app with the nodes matched by its selector,
+// the apps whose node bindings are managed externally are skipped
+func (a *facade) verifyNodesOfApp(ns string, item models.AppItem) ([]IndexIssue, error) {
+	if item.Labels[common.LabelSkipNodeIndex] == "true" {
+		return nil, nil
+	}
+	indexed, err := a.index.ListNodesByApp(ns, item.Name)
+	if err != nil {
+		return nil, err
+	}
+	matched, err := a.node.MatchNodes(nil, ns, item.Selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []IndexIssue
+	for _, n := range subtract(indexed, matched) {
+		issues = append(issues, IndexIssue{App: item.Name, Node: n, Reason: IndexStaleNode})
+	}
+	for _, n := range subtract(matched, indexed) {
+		issues = append(issues, IndexIssue{App: item.Name, Node: n, Reason: IndexMissingNode})
+	}
+	return issues, nil
+}
+
+// verifyAppsOfNode checks the apps indexed by the node exist and are desired by the node in the current version,
+// the apps are cached to avoid fetching them for every node. The number of the apps indexed is returned as well.
+func (a *facade) verifyAppsOfNode(ns string, node *specV1.Node, cache map[string]*specV1.Application) ([]IndexIssue, int, error) {
+	names, err := a.index.ListAppsByNode(ns, node.Name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var issues []IndexIssue
+	for _, name := range names {
+		app, ok := cache[name]
+		if !ok {
+			app, err = a.app.Get(ns, name, "")
+			if err != nil {
+				if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+					return nil, 0, err
+				}
+				app = nil
+			}
+			cache[name] = app
+		}
+		if app == nil {
+			issues = append(issues, IndexIssue{App: name, Node: node.Name, Reason: IndexMissingApp})
+			continue
+		}
+		if skipNodeIndex(app) {
+			continue
+		}
+		// the nodes out of canary or not yet advanced to keep the previous version of the app
+		if !desiresAppVersion(node.Desire, app) && !inCanary(app) && !inStagger(app) {
+			issues = append(issues, IndexIssue{App: name, Node: node.Name, Reason: IndexMissingVersion})
+		}
+	}
+	return issues, len(names), nil
+}
+
+// repairAppIndex recomputes the node-app indexes and the node desires of the app from its current selector,
+// the app is removed from the desires of the nodes which aren't matched any more. If the app doesn't exist,
+// its indexes and desires are removed.
+func (a *facade) repairAppIndex(ctx context.Context, ns, name string) error {
+	return a.runTx(ctx, ns, "RepairAppIndex", func(tx interface{}, undo *compensations) error {
+		indexed, err := a.index.ListNodesByApp(ns, name)
+		if err != nil {
+			return err
+		}
+		app, err := a.app.Get(ns, name, "")
+		if err != nil {
+			if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+				return err
+			}
+			// the system flag of the missing app is unknown, so both kinds of app infos are cleaned
+			for _, system := range []bool{false, true} {
+				if len(indexed) == 0 {
+					break
+				}
+				stub := &specV1.Application{Name: name, System: system}
+				if err = a.node.UpdateDesire(tx, ns, indexed, stub, service.DeleteNodeDesireByApp); err != nil {
+					return err
+				}
+			}
+			return a.refreshNodesIndexByApp(tx, ns, &specV1.Application{Name: name}, make([]string, 0), undo)
+		}
+
+		_, err = a.reindexApp(tx, ns, app, indexed, undo)
+		return err
+	})
+}
+
+// reindexApp deploys the app to the nodes matched by its current selector and removes it from the desires of
+// the nodes indexed which aren't matched any more, the nodes the app is deployed to are returned
+func (a *facade) reindexApp(tx interface{}, ns string, app *specV1.Application, indexed []string, undo *compensations) ([]string, error) {
+	if skipNodeIndex(app) {
+		return nil, nil
+	}
+	matched, err := a.node.MatchNodes(tx, ns, app.Selector)
+	if err != nil {
+		return nil, err
+	}
+	if stale := subtract(indexed, matched); len(stale) > 0 {
+		if err = a.node.UpdateDesire(tx, ns, stale, app, service.DeleteNodeDesireByApp); err != nil {
+			return nil, err
+		}
+	}
+	return a.updateNodeAndAppIndex(tx, ns, app, undo)
+}
+
+// RefreshNodeIndexesForNode recomputes the apps bound to the node from its current labels in a single transaction,
+// which is called when the labels of the node change. The apps newly matched are added to the node desire and
+// the apps no longer matched are removed, then the desire and the app indexes of the node are written once each.
+// The app in canary is only added if the node is chosen for canary. The names of the matched apps are returned.
+func (a *facade) RefreshNodeIndexesForNode(ctx context.Context, ns, name string) (apps []string, err error) {
+	defer observeCall(ns, "RefreshNodeIndexesForNode", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	err = a.runTx(ctx, ns, "RefreshNodeIndexesForNode", func(tx interface{}, _ *compensations) error {
+		node, err := a.node.Get(tx, ns, name)
+		if err != nil {
+			return err
+		}
+		apps, err = a.refreshNodeIndexes(tx, ns, node)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// UpdateNode updates the node and recomputes the apps bound to it as RefreshNodeIndexesForNode does if its labels
+// change, both in a single transaction so that the node is never left with the labels its indexes don't match
+func (a *facade) UpdateNode(ctx context.Context, ns string, node *specV1.Node) (res *specV1.Node, err error) {
+	defer observeCall(ns, "UpdateNode", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	err = a.runTx(ctx, ns, "UpdateNode", func(tx interface{}, _ *compensations) error {
+		old, err := a.node.Get(tx, ns, node.Name)
+		if err != nil {
+			return err
+		}
+		if res, err = a.node.Update(tx, ns, node); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(old.Labels, res.Labels) {
+			return nil
+		}
+		// the apps are matched against the new labels and the desire stored
+		relabeled := *res
+		relabeled.Desire = old.Desire
+		_, err = a.refreshNodeIndexes(tx, ns, &relabeled)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// refreshNodeIndexes recomputes the apps bound to the node within the transaction, the names of the matched apps
+// are returned
+func (a *facade) refreshNodeIndexes(tx interface{}, ns string, node *specV1.Node) ([]string, error) {
+	name := node.Name
+	indexed, err := a.index.ListAppsByNode(ns, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*specV1.Application
+	skipped := map[string]bool{}
+	opt := &models.ListOptions{Limit: int64(a.conf.IndexPageSize)}
+	for {
+		list, err := a.app.List(ns, opt)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, item := range list.Items {
+			if item.Labels[common.LabelSkipNodeIndex] == "true" {
+				skipped[item.Name] = true
+				continue
+			}
+			if item.Selector == "" {
+				continue
+			}
+			if ok, err := utils.IsLabelMatch(item.Selector, node.Labels); err != nil || !ok {
+				continue
+			}
+			matched = append(matched, appOfItem(item))
+		}
+		if list.ListOptions == nil || list.Continue == "" {
+			break
+		}
+		opt.Continue = list.Continue
+	}
+
+	apps, adds, removes, err := nodeIndexChanges(node, indexed, matched, skipped, func(app *specV1.Application) (bool, error) {
+		return a.isNodeDeployed(tx, ns, app, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(adds) > 0 || len(removes) > 0 {
+		err = a.node.UpdateDesire(tx, ns, []string{name}, nil, func(shadow *models.Shadow, _ *specV1.Application) {
+			updateNodeDesire(shadow, adds, removes)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err = a.index.RefreshAppsIndexByNode(tx, ns, name, apps); err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// ReconcileNodeIndexes recomputes the apps bound to the nodes changed, e.g. relabeled site-wide at once, as
+// RefreshNodeIndexesForNode does for each of them. The apps are listed and their selectors are parsed once, and
+// each node is only matched against the apps whose selectors require no label key it lacks. The nodes are
+// reconciled batch by batch (IndexPageSize nodes per batch), each batch in its own transaction, and only the
+// desires and the indexes changed are written. The nodes not found, e.g. deleted meanwhile, are skipped.
+func (a *facade) ReconcileNodeIndexes(ctx context.Context, ns string, changedNodes []string) (err error) {
+	defer observeCall(ns, "ReconcileNodeIndexes", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	if err = ctx.Err(); err != nil {
+		return errors.Trace(err)
+	}
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range changedNodes {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	selectors, err := a.listAppSelectors(ns)
+	if err != nil {
+		return err
+	}
+	size := a.conf.IndexPageSize
+	if size <= 0 {
+		size = len(names)
+	}
+	for start := 0; start < len(names); start += size {
+		end := start + size
+		if end > len(names) {
+			end = len(names)
+		}
+		batch := names[start:end]
+		err = a.runTx(ctx, ns, "ReconcileNodeIndexes", func(tx interface{}, _ *compensations) error {
+			return a.reconcileNodeIndexes(tx, ns, batch, selectors)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileNodeIndexes recomputes the apps bound to the nodes within the transaction, the desires of the nodes
+// changed are updated at once, and the canary nodes of each app are chosen once for all the nodes, as are the
+// nodes the staggered apps can still be deployed to
+func (a *facade) reconcileNodeIndexes(tx interface{}, ns string, names []string, selectors *appSelectors) error {
+	chosen := map[string]map[string]bool{}
+	slots := map[string]int{}
+	canaryChosen := func(app *specV1.Application, node string) (bool, error) {
+		percent, err := canaryPercent(app)
+		if err != nil {
+			// the app with an invalid canary percent can't be deployed, as updateNodeAndAppIndex fails
+			return false, nil
+		}
+		if percent == 0 {
+			maxNodes, err := rolloutMaxNodes(app)
+			if err != nil {
+				return false, nil
+			}
+			if maxNodes == 0 {
+				return true, nil
+			}
+			n, ok := slots[app.Name]
+			if !ok {
+				if n, err = a.staggerSlots(tx, ns, app, maxNodes); err != nil {
+					return false, err
+				}
+			}
+			if n <= 0 {
+				slots[app.Name] = n
+				return false, nil
+			}
+			slots[app.Name] = n - 1
+			return true, nil
+		}
+		nodes, ok := chosen[app.Name]
+		if !ok {
+			matched, err := a.node.MatchNodes(tx, ns, app.Selector)
+			if err != nil {
+				return false, err
+			}
+			nodes = map[string]bool{}
+			for _, n := range selectCanaryNodes(app.Name, matched, percent) {
+				nodes[n] = true
+			}
+			chosen[app.Name] = nodes
+		}
+		return nodes[node], nil
+	}
+
+	var changed []string
+	adds := map[string][]*specV1.Application{}
+	removes := map[string][]*specV1.Application{}
+	for _, name := range names {
+		node, err := a.node.Get(tx, ns, name)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return err
+		}
+		indexed, err := a.index.ListAppsByNode(ns, name)
+		if err != nil {
+			return err
+		}
+		apps, add, remove, err := nodeIndexChanges(node, indexed, selectors.match(node.Labels), selectors.skipped, func(app *specV1.Application) (bool, error) {
+			return canaryChosen(app, name)
+		})
+		if err != nil {
+			return err
+		}
+		if len(add) > 0 || len(remove) > 0 {
+			changed = append(changed, name)
+			adds[name], removes[name] = add, remove
+		}
+		if len(subtract(indexed, apps)) > 0 || len(subtract(apps, indexed)) > 0 {
+			if err = a.index.RefreshAppsIndexByNode(tx, ns, name, apps); err != nil {
+				return err
+			}
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return a.node.UpdateDesire(tx, ns, changed, nil, func(shadow *models.Shadow, _ *specV1.Application) {
+		updateNodeDesire(shadow, adds[shadow.Name], removes[shadow.Name])
+	})
+}
+
+// selectorApp the app with its selector parsed once to be matched against the labels of many nodes
+type selectorApp struct {
+	app      *specV1.Application
+	selector labels.Selector
+	// order the position of the app listed, which the apps matched are sorted by
+	order int
+}
+
+// appSelectors indexes the apps by a label key their selectors require, so that the apps whose selectors
+// can't match the labels of a node, which lack a key they require, are never evaluated against the node
+type appSelectors struct {
+	byKey map[string][]*selectorApp
+	// others the apps whose selectors require no key, e.g. "!deprecated", which are evaluated against every node
+	others []*selectorApp
+	// skipped the names of the apps whose node bindings are managed externally, which are never matched
+	skipped map[string]bool
+}
+
+// listAppSelectors lists the apps of the namespace with selectors page by page and indexes their selectors,
+// the apps with invalid selectors are skipped as they match no node, and so are the apps managed externally
+func (a *facade) listAppSelectors(ns string) (*appSelectors, error) {
+	res := &appSelectors{byKey: map[string][]*selectorApp{}, skipped: map[string]bool{}}
+	order := 0
+	opt := &models.ListOptions{Limit: int64(a.conf.IndexPageSize)}
+	for {
+		list, err := a.app.List(ns, opt)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, item := range list.Items {
+			if item.Labels[common.LabelSkipNodeIndex] == "true" {
+				res.skipped[item.Name] = true
+				continue
+			}
+			if item.Selector == "" {
+				continue
+			}
+			selector, err := labels.Parse(item.Selector)
+			if err != nil {
+				continue
+			}
+			app := &selectorApp{app: appOfItem(item), selector: selector, order: order}
+			order++
+			if key, ok := requiredLabelKey(selector); ok {
+				res.byKey[key] = append(res.byKey[key], app)
+			} else {
+				res.others = append(res.others, app)
+			}
+		}
+		if list.ListOptions == nil || list.Continue == "" {
+			break
+		}
+		opt.Continue = list.Continue
+	}
+	return res, nil
+}
+
+// requiredLabelKey returns a label key which the labels must have to match the selector
+func requiredLabelKey(selector labels.Selector) (string, bool) {
+	reqs, _ := selector.Requirements()
+	for _, r := range reqs {
+		switch r.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In, selection.Exists, selection.GreaterThan, selection.LessThan:
+			return r.Key(), true
+		}
+	}
+	return "", false
+}
+
+// match returns the apps whose selectors match the labels in the order listed
+func (s *appSelectors) match(nodeLabels map[string]string) []*specV1.Application {
+	candidates := append([]*selectorApp{}, s.others...)
+	for key := range nodeLabels {
+		candidates = append(candidates, s.byKey[key]...)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].order < candidates[j].order })
+	var res []*specV1.Application
+	for _, c := range candidates {
+		if c.selector.Matches(labels.Set(nodeLabels)) {
+			res = append(res, c.app)
+		}
+	}
+	return res
+}
+
+// appOfItem returns the app listed with the fields needed to bind it to the nodes
+func appOfItem(item models.AppItem) *specV1.Application {
+	return &specV1.Application{Name: item.Name, Version: item.Version, System: item.System, Labels: item.Labels, Selector: item.Selector}
+}
+
+// nodeIndexChanges computes the changes binding the node to the apps matched by its labels, which are the apps
+// newly matched to be added to its desire and the apps indexed or desired but no longer matched to be removed.
+// The app in canary is only added if deployed reports the node is chosen. The names of the matched apps are returned.
+// The apps skipped, whose node bindings are managed externally, are neither added nor removed and stay indexed.
+func nodeIndexChanges(node *specV1.Node, indexed []string, matched []*specV1.Application, skipped map[string]bool, deployed func(*specV1.Application) (bool, error)) (apps []string, adds, removes []*specV1.Application, err error) {
+	apps = []string{}
+	names := map[string]bool{}
+	for _, app := range indexed {
+		if skipped[app] {
+			apps = append(apps, app)
+			names[app] = true
+		}
+	}
+	for _, app := range matched {
+		if skipped[app.Name] {
+			continue
+		}
+		apps = append(apps, app.Name)
+		names[app.Name] = true
+		if desiresAppVersion(node.Desire, app) {
+			continue
+		}
+		deploy, err := deployed(app)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if deploy {
+			adds = append(adds, app)
+		}
+	}
+
+	// the apps indexed or desired by the node but no longer matched
+	for _, system := range []bool{false, true} {
+		var infos []specV1.AppInfo
+		if node.Desire != nil {
+			infos = node.Desire.AppInfos(system)
+		}
+		for _, info := range infos {
+			if !names[info.Name] && !skipped[info.Name] {
+				removes = append(removes, &specV1.Application{Name: info.Name, System: system})
+			}
+		}
+	}
+	for _, app := range subtract(indexed, apps) {
+		if !desired(node.Desire, app) {
+			// the system flag of the app only indexed is unknown
+			removes = append(removes, &specV1.Application{Name: app}, &specV1.Application{Name: app, System: true})
+		}
+	}
+	return apps, adds, removes, nil
+}
+
+func updateNodeDesire(shadow *models.Shadow, adds, removes []*specV1.Application) {
+	for _, app := range removes {
+		service.DeleteNodeDesireByApp(shadow, app)
+	}
+	for _, app := range adds {
+		service.RefreshNodeDesireByApp(shadow, app)
+	}
+}
+
+// isNodeDeployed checks whether the current version of the app matching the node should be deployed to it,
+// the app in canary is only deployed to the chosen nodes, and the staggered app is only deployed if fewer nodes
+// than its max nodes are updating, otherwise the node is left to AdvanceRollout
+func (a *facade) isNodeDeployed(tx interface{}, ns string, app *specV1.Application, node string) (bool, error) {
+	percent, err := canaryPercent(app)
+	if err != nil {
+		// the app with an invalid canary percent can't be deployed, as updateNodeAndAppIndex fails
+		return false, nil
+	}
+	if percent == 0 {
+		maxNodes, err := rolloutMaxNodes(app)
+		if err != nil {
+			return false, nil
+		}
+		if maxNodes == 0 {
+			return true, nil
+		}
+		slots, err := a.staggerSlots(tx, ns, app, maxNodes)
+		return slots > 0, err
+	}
+	nodes, err := a.node.MatchNodes(tx, ns, app.Selector)
+	if err != nil {
+		return false, err
+	}
+	for _, n := range selectCanaryNodes(app.Name, nodes, percent) {
+		if n == node {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func desired(desire specV1.Desire, name string) bool {
+	if desire == nil {
+		return false
+	}
+	for _, system := range []bool{false, true} {
+		for _, info := range desire.AppInfos(system) {
+			if info.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func desiresAppVersion(desire specV1.Desire, app *specV1.Application) bool {
+	if desire == nil {
+		return false
+	}
+	for _, info := range desire.AppInfos(app.System) {
+		if info.Name == app.Name {
+			return info.Version == app.Version
+		}
+	}
+	return false
+}
+
+func inCanary(app *specV1.Application) bool {
+	percent, err := canaryPercent(app)
+	return err == nil && percent > 0
+}
+
+// subtract returns the elements of a which aren't in b
+func subtract(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, v := range b {
+		set[v] = true
+	}
+	var res []string
+	for _, v := range a {
+		if !set[v] {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// ListAppNodes lists the nodes which the app is deployed to page by page (pageNo and pageSize of opt)
+// sorted by name, the nodes are filtered by the name substring of opt. The page is read from the node-app
+// indexes, so the nodes of the apps targeting large fleets are never loaded at once.
+func (a *facade) ListAppNodes(ctx context.Context, ns, name string, opt *models.ListOptions) (*models.AppNodeList, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if opt == nil {
+		opt = &models.ListOptions{}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := a.app.Get(ns, name, ""); err != nil {
+		return nil, err
+	}
+	nodes, total, err := a.index.ListNodesPageByApp(ns, name, &opt.Filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if nodes == nil {
+		nodes = []string{}
+	}
+	return &models.AppNodeList{
+		Total:       total,
+		ListOptions: opt,
+		Items:       nodes,
+	}, nil
+}
+
+// ListNodeApps lists the apps which the node is bound to page by page (pageNo and pageSize of opt) sorted by name,
+// which is the reverse of ListAppNodes. The page of the app names is read from the node-app indexes, then the
+// current versions of the apps are read by a single query and the apps waiting for cron carry the selectors of their
+// crons. The apps indexed but not found, e.g. deleted meanwhile, are omitted from the page. The reads are within
+// a read-only transaction if ctx is returned by WithReadOnlyTx.
+func (a *facade) ListNodeApps(ctx context.Context, ns, node string, opt *models.ListOptions) (*models.NodeAppList, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := a.limitAppReads(ctx, ns); err != nil {
+		return nil, err
+	}
+	if opt == nil {
+		opt = &models.ListOptions{}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	res := &models.NodeAppList{ListOptions: opt, Items: []*specV1.Application{}}
+	err := a.runReadTx(ctx, ns, "ListNodeApps", func(tx interface{}) error {
+		if _, err := a.node.Get(tx, ns, node); err != nil {
+			return err
+		}
+		names, total, err := a.index.ListAppsPageByNode(ns, node, &opt.Filter)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		res.Total = total
+		if len(names) == 0 {
+			return nil
+		}
+		apps, err := a.app.GetBatch(ns, names)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		byName := map[string]*specV1.Application{}
+		for _, app := range apps {
+			byName[app.Name] = app
+		}
+		items := make([]*specV1.Application, 0, len(names))
+		for _, name := range names {
+			if app, ok := byName[name]; ok {
+				items = append(items, app)
+			}
+		}
+		if err = a.fillCronSelectors(tx, ns, items); err != nil {
+			return err
+		}
+		res.Items = items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListAppNodesByLabel lists the nodes indexed by the apps labeled with the key and the value, sorted by the app and
+// the node, so the bindings of the apps of a label are answered by the indexes without reading the apps. The apps
+// are indexed with their labels only if IndexAppLabels is enabled, the ones indexed before are found once updated.
+func (a *facade) ListAppNodesByLabel(ctx context.Context, ns, key, value string) ([]models.AppNodeBinding, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if !a.conf.IndexAppLabels {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the labels of the apps are not indexed"))
+	}
+	if key == "" {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the label key is empty"))
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	res, err := a.index.ListAppNodesByLabel(ns, key, value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if res == nil {
+		res = []models.AppNodeBinding{}
+	}
+	return res, nil
+}
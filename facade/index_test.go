@@ -0,0 +1,587 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func mockAppIndexState(mAppFacade *MockAppFacade, ns string) (*specV1.Application, *specV1.Application) {
+	app1 := &specV1.Application{Name: "app1", Version: "2", Selector: "a=1"}
+	app2 := &specV1.Application{Name: "app2", Version: "1", Selector: "b=1"}
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "app"))
+
+	// the apps and the nodes are listed in two pages
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).DoAndReturn(func(_ string, opt *models.ListOptions) (*models.ApplicationList, error) {
+		if opt.Continue == "" {
+			return &models.ApplicationList{
+				ListOptions: &models.ListOptions{Continue: "next"},
+				Items:       []models.AppItem{{Name: "app1", Version: "2", Selector: "a=1"}},
+			}, nil
+		}
+		return &models.ApplicationList{
+			ListOptions: &models.ListOptions{},
+			Items:       []models.AppItem{{Name: "app2", Version: "1", Selector: "b=1"}},
+		}, nil
+	}).AnyTimes()
+	mAppFacade.sNode.EXPECT().List(ns, gomock.Any()).DoAndReturn(func(_ string, opt *models.ListOptions) (*models.NodeList, error) {
+		if opt.Continue == "" {
+			return &models.NodeList{
+				ListOptions: &models.ListOptions{Continue: "next"},
+				Items: []specV1.Node{
+					{Name: "n1", Desire: specV1.Desire{"apps": []specV1.AppInfo{{Name: "app1", Version: "2"}}}},
+					{Name: "n3", Desire: specV1.Desire{"apps": []specV1.AppInfo{{Name: "app1", Version: "1"}}}},
+				},
+			}, nil
+		}
+		return &models.NodeList{
+			ListOptions: &models.ListOptions{},
+			Items: []specV1.Node{
+				{Name: "n4", Desire: specV1.Desire{"apps": []specV1.AppInfo{{Name: "app2", Version: "1"}}}},
+			},
+		}, nil
+	}).AnyTimes()
+
+	// app1 matches n1 and n2, but indexes n1 and n3
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "app1").Return([]string{"n1", "n3"}, nil).AnyTimes()
+	mAppFacade.sNode.EXPECT().MatchNodes(gomock.Any(), ns, "a=1").Return([]string{"n1", "n2"}, nil).AnyTimes()
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "app2").Return([]string{"n4"}, nil).AnyTimes()
+	mAppFacade.sNode.EXPECT().MatchNodes(gomock.Any(), ns, "b=1").Return([]string{"n4"}, nil).AnyTimes()
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "ghost").Return([]string{"n1"}, nil).AnyTimes()
+
+	// n1 still indexes the deleted app ghost, n3 desires the old version of app1
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n1").Return([]string{"app1", "ghost"}, nil).AnyTimes()
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n3").Return([]string{"app1"}, nil).AnyTimes()
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n4").Return([]string{"app2"}, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().Get(ns, "app1", "").Return(app1, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().Get(ns, "app2", "").Return(app2, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().Get(ns, "ghost", "").Return(nil, notFound).AnyTimes()
+	return app1, app2
+}
+
+func TestVerifyAppIndex(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{IndexPageSize: 1},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	mockAppIndexState(mAppFacade, ns)
+
+	report, err := appFacade.VerifyAppIndex(context.Background(), ns)
+	assert.NoError(t, err)
+	assert.Equal(t, &IndexReport{
+		Namespace: ns,
+		Apps:      2,
+		Nodes:     3,
+		Entries:   4,
+		Issues: []IndexIssue{
+			{App: "app1", Node: "n3", Reason: IndexStaleNode},
+			{App: "app1", Node: "n2", Reason: IndexMissingNode},
+			{App: "ghost", Node: "n1", Reason: IndexMissingApp},
+			{App: "app1", Node: "n3", Reason: IndexMissingVersion},
+		},
+	}, report)
+	assert.Equal(t, float64(4), testutil.ToFloat64(facadeIndexEntries.WithLabelValues(ns)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(facadeIndexStaleEntries.WithLabelValues(ns, IndexStaleNode)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(facadeIndexStaleEntries.WithLabelValues(ns, IndexMissingApp)))
+
+	// list failed
+	mAppFacade.sApp.EXPECT().List("default", gomock.Any()).Return(nil, unknownErr)
+	_, err = appFacade.VerifyAppIndex(context.Background(), "default")
+	assert.Error(t, err)
+}
+
+func TestRepairAppIndex(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{IndexPageSize: 1},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	app1, _ := mockAppIndexState(mAppFacade, ns)
+
+	// app1 is repaired once by the app walk and once by the node walk, ghost once by the node walk
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(3)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().Times(3)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n3"}, app1, gomock.Any()).Return(nil).Times(2)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app1).Return([]string{"n1", "n2"}, nil).Times(2)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "app1", []string{"n1", "n2"}).Return(nil).Times(2)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, &specV1.Application{Name: "ghost"}, gomock.Any()).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, &specV1.Application{Name: "ghost", System: true}, gomock.Any()).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "ghost", []string{}).Return(nil)
+
+	report, err := appFacade.RepairAppIndex(context.Background(), ns)
+	assert.NoError(t, err)
+	assert.Len(t, report.Issues, 4)
+	// the issues repaired aren't left
+	assert.Equal(t, float64(0), testutil.ToFloat64(facadeIndexStaleEntries.WithLabelValues(ns, IndexStaleNode)))
+
+	// repair failed
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n3"}, app1, gomock.Any()).Return(unknownErr)
+	report, err = appFacade.RepairAppIndex(context.Background(), ns)
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, 1, report.Apps)
+}
+
+func TestRefreshNodeIndexesForNode(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+
+	desire := specV1.Desire{}
+	desire.SetAppInfos(false, []specV1.AppInfo{{Name: "old", Version: "1"}, {Name: "app1", Version: "1"}, {Name: "app2", Version: "1"}})
+	desire.SetAppInfos(true, []specV1.AppInfo{})
+	node := &specV1.Node{Name: "n1", Labels: map[string]string{"a": "1"}, Desire: desire}
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(node, nil).Times(2)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n1").Return([]string{"old", "app1", "ghost"}, nil).Times(2)
+	canary := map[string]string{common.LabelCanaryPercent: "50"}
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{
+		ListOptions: &models.ListOptions{},
+		Items: []models.AppItem{
+			{Name: "app1", Version: "2", Selector: "a=1"},
+			{Name: "app2", Version: "1", Selector: "a=1"},
+			{Name: "app3", Version: "1", Selector: "b=1"},
+			{Name: "app4", Version: "1", Selector: "a in (1)", Labels: canary},
+			{Name: "sys", Version: "1", Selector: "a=1", System: true},
+		},
+	}, nil).Times(2)
+	// n1 is the canary node of app4 among n1 and n2
+	others := []string{"n2"}
+	for len(selectCanaryNodes("app4", append([]string{"n1"}, others...), 50)) != 1 ||
+		selectCanaryNodes("app4", append([]string{"n1"}, others...), 50)[0] != "n1" {
+		others[0] += "x"
+	}
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a in (1)").Return(append([]string{"n1"}, others...), nil).Times(2)
+
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, nil, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, _ []string, _ *specV1.Application, f func(*models.Shadow, *specV1.Application)) error {
+			shadow := &models.Shadow{Desire: specV1.Desire{}}
+			shadow.Desire.SetAppInfos(false, desire.AppInfos(false))
+			shadow.Desire.SetAppInfos(true, []specV1.AppInfo{})
+			f(shadow, nil)
+			assert.ElementsMatch(t, []specV1.AppInfo{{Name: "app1", Version: "2"}, {Name: "app2", Version: "1"}, {Name: "app4", Version: "1"}}, shadow.Desire.AppInfos(false))
+			assert.Equal(t, []specV1.AppInfo{{Name: "sys", Version: "1"}}, shadow.Desire.AppInfos(true))
+			return nil
+		})
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n1", []string{"app1", "app2", "app4", "sys"}).Return(nil)
+	apps, err := appFacade.RefreshNodeIndexesForNode(context.Background(), ns, "n1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app1", "app2", "app4", "sys"}, apps)
+
+	// the whole refresh is rolled back
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, nil, gomock.Any()).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n1", gomock.Any()).Return(unknownErr)
+	_, err = appFacade.RefreshNodeIndexesForNode(context.Background(), ns, "n1")
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestReconcileNodeIndexes(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{IndexPageSize: 2},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	// n1 and n2 are reconciled in a transaction and n3 in another
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().Times(2)
+
+	canary := map[string]string{common.LabelCanaryPercent: "50"}
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{
+		ListOptions: &models.ListOptions{},
+		Items: []models.AppItem{
+			{Name: "app1", Version: "2", Selector: "a=1"},
+			{Name: "app2", Version: "1", Selector: "b=1"},
+			{Name: "app3", Version: "1", Selector: "!deprecated"},
+			{Name: "app4", Version: "1", Selector: "c in (1)", Labels: canary},
+			{Name: "bad", Version: "1", Selector: "a in ("},
+			{Name: "none", Version: "1"},
+		},
+	}, nil).Times(1)
+	// the canary nodes of app4 are chosen once for both nodes
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "c in (1)").Return([]string{"n1", "n2"}, nil).Times(1)
+	chosen := selectCanaryNodes("app4", []string{"n1", "n2"}, 50)[0]
+
+	desire1 := specV1.Desire{}
+	desire1.SetAppInfos(false, []specV1.AppInfo{{Name: "app1", Version: "1"}, {Name: "old", Version: "1"}})
+	desire1.SetAppInfos(true, []specV1.AppInfo{})
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Labels: map[string]string{"a": "1", "c": "1"}, Desire: desire1}, nil)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n1").Return([]string{"app1", "old"}, nil)
+	// n2 is bound to the apps already, nothing is written
+	desire2 := specV1.Desire{}
+	desire2.SetAppInfos(false, []specV1.AppInfo{{Name: "app2", Version: "1"}, {Name: "app3", Version: "1"}, {Name: "app4", Version: "1"}})
+	desire2.SetAppInfos(true, []specV1.AppInfo{})
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n2").Return(&specV1.Node{Name: "n2", Labels: map[string]string{"b": "1", "c": "1"}, Desire: desire2}, nil)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n2").Return([]string{"app4", "app3", "app2"}, nil)
+	// n3 is deleted meanwhile
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(nil, common.Error(common.ErrResourceNotFound))
+
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n1", []string{"app1", "app3", "app4"}).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, nil, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, _ []string, _ *specV1.Application, f func(*models.Shadow, *specV1.Application)) error {
+			shadow := &models.Shadow{Name: "n1", Desire: specV1.Desire{}}
+			shadow.Desire.SetAppInfos(false, desire1.AppInfos(false))
+			shadow.Desire.SetAppInfos(true, []specV1.AppInfo{})
+			f(shadow, nil)
+			expected := []specV1.AppInfo{{Name: "app1", Version: "2"}, {Name: "app3", Version: "1"}}
+			if chosen == "n1" {
+				expected = append(expected, specV1.AppInfo{Name: "app4", Version: "1"})
+			}
+			assert.ElementsMatch(t, expected, shadow.Desire.AppInfos(false))
+			return nil
+		})
+	err := appFacade.ReconcileNodeIndexes(context.Background(), ns, []string{"n1", "n2", "n1", "n3"})
+	assert.NoError(t, err)
+
+	// nothing changed
+	assert.NoError(t, appFacade.ReconcileNodeIndexes(context.Background(), ns, nil))
+
+	// the batch is rolled back
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{
+		Items: []models.AppItem{{Name: "app1", Version: "2", Selector: "a=1"}},
+	}, nil)
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Labels: map[string]string{"a": "1"}}, nil)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n1").Return(nil, unknownErr)
+	err = appFacade.ReconcileNodeIndexes(context.Background(), ns, []string{"n1"})
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestRelabelNodeSkipNodeIndex(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().Times(2)
+
+	// the node relabeled matches ext and no longer matches ext2, whose node bindings are both managed externally
+	skip := map[string]string{common.LabelSkipNodeIndex: "true"}
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{
+		ListOptions: &models.ListOptions{},
+		Items: []models.AppItem{
+			{Name: "app1", Version: "1", Selector: "a=1"},
+			{Name: "ext", Version: "1", Selector: "a=1", Labels: skip},
+			{Name: "ext2", Version: "1", Selector: "b=1", Labels: skip},
+		},
+	}, nil).Times(2)
+	desire := specV1.Desire{}
+	desire.SetAppInfos(false, []specV1.AppInfo{{Name: "ext2", Version: "1"}})
+	desire.SetAppInfos(true, []specV1.AppInfo{})
+	node := &specV1.Node{Name: "n1", Labels: map[string]string{"a": "1"}, Desire: desire}
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(node, nil).Times(2)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n1").Return([]string{"ext2"}, nil).Times(2)
+
+	// only app1 is added, ext isn't added and ext2 is kept desired and indexed
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, nil, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, _ []string, _ *specV1.Application, f func(*models.Shadow, *specV1.Application)) error {
+			shadow := &models.Shadow{Name: "n1", Desire: specV1.Desire{}}
+			shadow.Desire.SetAppInfos(false, desire.AppInfos(false))
+			shadow.Desire.SetAppInfos(true, []specV1.AppInfo{})
+			f(shadow, nil)
+			assert.ElementsMatch(t, []specV1.AppInfo{{Name: "ext2", Version: "1"}, {Name: "app1", Version: "1"}}, shadow.Desire.AppInfos(false))
+			return nil
+		}).Times(2)
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n1", []string{"ext2", "app1"}).Return(nil).Times(2)
+
+	apps, err := appFacade.RefreshNodeIndexesForNode(context.Background(), ns, "n1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ext2", "app1"}, apps)
+
+	err = appFacade.ReconcileNodeIndexes(context.Background(), ns, []string{"n1"})
+	assert.NoError(t, err)
+}
+
+func TestAppSelectors(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{app: mAppFacade.sApp}
+	ns := "baetyl-cloud"
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{
+		Items: []models.AppItem{
+			{Name: "app1", Selector: "a=1"},
+			{Name: "app2", Selector: "b!=1,c in (1,2)"},
+			{Name: "app3", Selector: "!deprecated,d notin (1)"},
+			{Name: "app4", Selector: "e"},
+		},
+	}, nil)
+	selectors, err := appFacade.listAppSelectors(ns)
+	assert.NoError(t, err)
+	assert.Len(t, selectors.byKey["a"], 1)
+	assert.Len(t, selectors.byKey["c"], 1)
+	assert.Len(t, selectors.byKey["e"], 1)
+	assert.Len(t, selectors.others, 1)
+
+	names := func(apps []*specV1.Application) []string {
+		var res []string
+		for _, app := range apps {
+			res = append(res, app.Name)
+		}
+		return res
+	}
+	assert.Equal(t, []string{"app1", "app3"}, names(selectors.match(map[string]string{"a": "1"})))
+	assert.Equal(t, []string{"app2", "app3", "app4"}, names(selectors.match(map[string]string{"c": "2", "e": ""})))
+	assert.Empty(t, selectors.match(map[string]string{"deprecated": "", "c": "3"}))
+}
+
+func TestListAppNodes(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:   mAppFacade.sApp,
+		index: mAppFacade.sIndex,
+		log:   log.L(),
+	}
+	ns := "baetyl-cloud"
+	opt := &models.ListOptions{Filter: models.Filter{Name: "node", PageNo: 2, PageSize: 2}}
+
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Name: "abc"}, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesPageByApp(ns, "abc", &opt.Filter).Return([]string{"node-c"}, 3, nil)
+	res, err := appFacade.ListAppNodes(context.Background(), ns, "abc", opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.Total)
+	assert.Equal(t, 2, res.PageNo)
+	assert.Equal(t, []string{"node-c"}, res.Items)
+
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Name: "abc"}, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesPageByApp(ns, "abc", gomock.Any()).Return(nil, 0, nil)
+	res, err = appFacade.ListAppNodes(context.Background(), ns, "abc", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{}, res.Items)
+
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, unknownErr)
+	_, err = appFacade.ListAppNodes(context.Background(), ns, "abc", opt)
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestListNodeApps(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:  mAppFacade.sNode,
+		app:   mAppFacade.sApp,
+		index: mAppFacade.sIndex,
+		cron:  mAppFacade.sCron,
+		log:   log.L(),
+	}
+	ns := "baetyl-cloud"
+	opt := &models.ListOptions{Filter: models.Filter{PageNo: 1, PageSize: 3}}
+
+	// the app deleted meanwhile is omitted and the app waiting for cron carries the selector of its cron
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1"}, nil)
+	mAppFacade.sIndex.EXPECT().ListAppsPageByNode(ns, "n1", &opt.Filter).Return([]string{"a", "b", "c"}, 5, nil)
+	mAppFacade.sApp.EXPECT().GetBatch(ns, []string{"a", "b", "c"}).Return([]*specV1.Application{
+		{Name: "c", Version: "1", CronStatus: specV1.CronWait},
+		{Name: "a", Version: "2", Selector: "x=y"},
+	}, nil)
+	mAppFacade.sCron.EXPECT().ListCrons(nil, ns, []string{"c"}).Return([]models.Cron{{Name: "c", Namespace: ns, Selector: "x=y"}}, nil)
+	res, err := appFacade.ListNodeApps(context.Background(), ns, "n1", opt)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, res.Total)
+	assert.Equal(t, 1, res.PageNo)
+	assert.Len(t, res.Items, 2)
+	assert.Equal(t, "a", res.Items[0].Name)
+	assert.Equal(t, "c", res.Items[1].Name)
+	assert.Equal(t, "x=y", res.Items[1].Selector)
+
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1"}, nil)
+	mAppFacade.sIndex.EXPECT().ListAppsPageByNode(ns, "n1", gomock.Any()).Return(nil, 0, nil)
+	res, err = appFacade.ListNodeApps(context.Background(), ns, "n1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []*specV1.Application{}, res.Items)
+
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n2").Return(nil, common.Error(common.ErrResourceNotFound))
+	_, err = appFacade.ListNodeApps(context.Background(), ns, "n2", opt)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1"}, nil)
+	mAppFacade.sIndex.EXPECT().ListAppsPageByNode(ns, "n1", gomock.Any()).Return([]string{"a"}, 1, nil)
+	mAppFacade.sApp.EXPECT().GetBatch(ns, []string{"a"}).Return(nil, unknownErr)
+	_, err = appFacade.ListNodeApps(context.Background(), ns, "n1", opt)
+	assert.Equal(t, unknownErr, errors.Cause(err))
+}
+
+func TestIndexAppLabels(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:  mAppFacade.sNode,
+		app:   mAppFacade.sApp,
+		index: mAppFacade.sIndex,
+		log:   log.L(),
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Name: "abc", Selector: "a=b", Labels: map[string]string{"team": "data"}}
+
+	// the labels are indexed only if enabled
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil).Times(2)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	_, err := appFacade.updateNodeAndAppIndex(nil, ns, app, nil)
+	assert.NoError(t, err)
+	_, err = appFacade.ListAppNodesByLabel(context.Background(), ns, "team", "data")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	appFacade.conf.IndexAppLabels = true
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByAppWithLabels(nil, ns, "abc", app.Labels, []string{"n1"}).Return(nil)
+	nodes, err := appFacade.updateNodeAndAppIndex(nil, ns, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, nodes)
+
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByAppWithLabels(nil, ns, "abc", app.Labels, []string{}).Return(nil)
+	_, err = appFacade.deleteNodeAndAppIndex(nil, ns, app, nil)
+	assert.NoError(t, err)
+
+	bindings := []models.AppNodeBinding{{App: "abc", Node: "n1"}}
+	mAppFacade.sIndex.EXPECT().ListAppNodesByLabel(ns, "team", "data").Return(bindings, nil)
+	res, err := appFacade.ListAppNodesByLabel(context.Background(), ns, "team", "data")
+	assert.NoError(t, err)
+	assert.Equal(t, bindings, res)
+
+	mAppFacade.sIndex.EXPECT().ListAppNodesByLabel(ns, "team", "ops").Return(nil, nil)
+	res, err = appFacade.ListAppNodesByLabel(context.Background(), ns, "team", "ops")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AppNodeBinding{}, res)
+
+	_, err = appFacade.ListAppNodesByLabel(context.Background(), ns, "", "data")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	mAppFacade.sIndex.EXPECT().ListAppNodesByLabel(ns, "team", "data").Return(nil, unknownErr)
+	_, err = appFacade.ListAppNodesByLabel(context.Background(), ns, "team", "data")
+	assert.Error(t, err)
+}
+
+func TestRefreshNodesIndexRecordedOnCommit(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "index-metrics"
+	app := &specV1.Application{Name: "abc"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil).Times(2)
+	refreshes := func() int {
+		n, err := testutil.GatherAndCount(prometheus.DefaultGatherer, "baetyl_cloud_index_last_refresh_timestamp_seconds")
+		assert.NoError(t, err)
+		return n
+	}
+	before := refreshes()
+
+	// the refresh rolled back isn't recorded
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	err := appFacade.runTx(context.Background(), ns, "test", func(tx interface{}, undo *compensations) error {
+		if err := appFacade.refreshNodesIndexByApp(tx, ns, app, []string{"n1"}, undo); err != nil {
+			return err
+		}
+		assert.Equal(t, before, refreshes())
+		return unknownErr
+	})
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, before, refreshes())
+
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	err = appFacade.runTx(context.Background(), ns, "test", func(tx interface{}, undo *compensations) error {
+		return appFacade.refreshNodesIndexByApp(tx, ns, app, []string{"n1"}, undo)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, before+1, refreshes())
+}
+
+func TestUpdateNode(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	old := &specV1.Node{Name: "n1", Labels: map[string]string{"a": "1"}}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(old, nil).AnyTimes()
+
+	// the node relabeled is updated with the apps bound to it in a single transaction
+	node := &specV1.Node{Name: "n1", Labels: map[string]string{"a": "2"}}
+	gomock.InOrder(
+		mAppFacade.sNode.EXPECT().Update(nil, ns, node).Return(node, nil),
+		mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n1").Return([]string{}, nil),
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{
+			Items: []models.AppItem{{Name: "app1", Version: "1", Selector: "a=2"}},
+		}, nil),
+		mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, nil, gomock.Any()).Return(nil),
+		mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n1", []string{"app1"}).Return(nil),
+		mAppFacade.txFactory.EXPECT().Commit(nil),
+	)
+	res, err := appFacade.UpdateNode(context.Background(), ns, node)
+	assert.NoError(t, err)
+	assert.Equal(t, node, res)
+
+	// the update is rolled back if the apps fail to be refreshed
+	gomock.InOrder(
+		mAppFacade.sNode.EXPECT().Update(nil, ns, node).Return(node, nil),
+		mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n1").Return(nil, unknownErr),
+		mAppFacade.txFactory.EXPECT().Rollback(nil),
+	)
+	_, err = appFacade.UpdateNode(context.Background(), ns, node)
+	assert.Error(t, err)
+
+	// the apps aren't refreshed if the labels are unchanged
+	node = &specV1.Node{Name: "n1", Labels: map[string]string{"a": "1"}, Description: "desc"}
+	gomock.InOrder(
+		mAppFacade.sNode.EXPECT().Update(nil, ns, node).Return(node, nil),
+		mAppFacade.txFactory.EXPECT().Commit(nil),
+	)
+	_, err = appFacade.UpdateNode(context.Background(), ns, node)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,86 @@
+package facade
+
+import (
+	"context"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ConfigDrift a generated function config whose data doesn't match the checksum stored when it's written
+type ConfigDrift struct {
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// FunctionConfigReport the result of verifying the generated function configs of an app
+type FunctionConfigReport struct {
+	App      string   `json:"app"`
+	Verified []string `json:"verified"`
+	// Unverified the configs without checksum, which are written before the checksums are stored
+	Unverified []string      `json:"unverified"`
+	Drifted    []ConfigDrift `json:"drifted"`
+}
+
+// VerifyFunctionConfigs recomputes the checksums of the generated function configs referenced by the app
+// and compares them with the ones stored when the configs are written, the drifted configs are reported
+func (a *facade) VerifyFunctionConfigs(ctx context.Context, ns, name string) (*FunctionConfigReport, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	app, err := a.app.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	report := &FunctionConfigReport{
+		App:        name,
+		Verified:   []string{},
+		Unverified: []string{},
+		Drifted:    []ConfigDrift{},
+	}
+	for _, v := range app.Volumes {
+		if v.Config == nil || !a.isFunctionConfig(v.Config.Name) {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		cfg, err := a.config.Get(ns, v.Config.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		expected, ok := cfg.Labels[common.LabelConfigChecksum]
+		if !ok {
+			report.Unverified = append(report.Unverified, cfg.Name)
+			continue
+		}
+		if actual := models.ConfigChecksum(cfg); actual != expected {
+			report.Drifted = append(report.Drifted, ConfigDrift{Name: cfg.Name, Expected: expected, Actual: actual})
+			continue
+		}
+		report.Verified = append(report.Verified, cfg.Name)
+	}
+	return report, nil
+}
+
+// withConfigChecksums returns the copies of the generated function configs labeled with their checksums,
+// the other configs are returned as they are
+func (a *facade) withConfigChecksums(configs []specV1.Configuration) []specV1.Configuration {
+	res := make([]specV1.Configuration, len(configs))
+	for i, cfg := range configs {
+		if a.isFunctionConfig(cfg.Name) {
+			labels := map[string]string{}
+			for k, v := range cfg.Labels {
+				labels[k] = v
+			}
+			labels[common.LabelConfigChecksum] = models.ConfigChecksum(&cfg)
+			cfg.Labels = labels
+		}
+		res[i] = cfg
+	}
+	return res
+}
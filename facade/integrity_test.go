@@ -0,0 +1,77 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestConfigChecksum(t *testing.T) {
+	a := &specV1.Configuration{Data: map[string]string{"a": "bc", "ab": "c"}}
+	b := &specV1.Configuration{Data: map[string]string{"ab": "c", "a": "bc"}}
+	assert.Equal(t, models.ConfigChecksum(a), models.ConfigChecksum(b))
+	assert.Len(t, models.ConfigChecksum(a), 32)
+	// the entries are length-prefixed
+	c := &specV1.Configuration{Data: map[string]string{"a": "bcab", "": "c"}}
+	assert.NotEqual(t, models.ConfigChecksum(a), models.ConfigChecksum(c))
+}
+
+func TestVerifyFunctionConfigs(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:    mAppFacade.sApp,
+		config: mAppFacade.sConfig,
+	}
+	ns := "baetyl-cloud"
+
+	// the checksums are stored when the generated configs are written
+	written := map[string]*specV1.Configuration{}
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+			res := *cfg
+			written[cfg.Name] = &res
+			return &res, nil
+		}).Times(3)
+	origin := map[string]string{"k": "v"}
+	err := appFacade.updateGenConfigsOfFunctionApp(nil, ns, []specV1.Configuration{
+		{Name: "baetyl-function-config-ok", Labels: origin, Data: map[string]string{"conf": "a"}},
+		{Name: "baetyl-function-program-config-bad", Data: map[string]string{"conf": "b"}},
+		{Name: "user", Data: map[string]string{"conf": "c"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"k": "v"}, origin)
+	assert.NotContains(t, written["user"].Labels, common.LabelConfigChecksum)
+
+	written["baetyl-function-program-config-bad"].Data["conf"] = "corrupted"
+	written["baetyl-function-config-old"] = &specV1.Configuration{Name: "baetyl-function-config-old"}
+	app := &specV1.Application{Name: "abc", Volumes: []specV1.Volume{
+		{Name: "ok", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-config-ok"}}},
+		{Name: "bad", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-program-config-bad"}}},
+		{Name: "old", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-config-old"}}},
+		{Name: "user", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "user"}}},
+		{Name: "secret", VolumeSource: specV1.VolumeSource{Secret: &specV1.ObjectReference{Name: "secret"}}},
+	}}
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(app, nil)
+	mAppFacade.sConfig.EXPECT().Get(ns, gomock.Any(), "").DoAndReturn(
+		func(_, name, _ string) (*specV1.Configuration, error) {
+			return written[name], nil
+		}).Times(3)
+	report, err := appFacade.VerifyFunctionConfigs(context.Background(), ns, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"baetyl-function-config-ok"}, report.Verified)
+	assert.Equal(t, []string{"baetyl-function-config-old"}, report.Unverified)
+	assert.Len(t, report.Drifted, 1)
+	assert.Equal(t, "baetyl-function-program-config-bad", report.Drifted[0].Name)
+	assert.Equal(t, models.ConfigChecksum(&specV1.Configuration{Data: map[string]string{"conf": "b"}}), report.Drifted[0].Expected)
+
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, unknownErr)
+	_, err = appFacade.VerifyFunctionConfigs(context.Background(), ns, "abc")
+	assert.Equal(t, unknownErr, err)
+}
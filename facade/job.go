@@ -0,0 +1,29 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+)
+
+// RunPeriodically runs the background job every interval until ctx is done, the failures are logged.
+// The job isn't run if the interval isn't positive, e.g. set to 0 by the config.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, job func(context.Context) error) {
+	if interval <= 0 {
+		log.L().Warn("background job is disabled since its interval isn't positive", log.Any("job", name), log.Any("interval", interval))
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				log.L().Error("background job failed", log.Any("job", name), log.Error(err))
+			}
+		}
+	}
+}
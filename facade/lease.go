@@ -0,0 +1,98 @@
+package facade
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// CronLeaderLease the lease held by the replica which leads the cron scheduler
+const CronLeaderLease = "baetyl-cron-leader"
+
+// LeadCron runs fn as the leader of the cron scheduler, e.g. to fire the crons due by TriggerCronApp, so that
+// only one of the replicas fires the crons at once. ErrNotLeader is returned if the lease is held by another replica.
+// The lease is renewed while fn runs, and the context passed to fn is canceled once the lease is lost, in which case
+// the runs of fn not committed yet are rolled back and ErrNotLeader is returned. The lease is released after fn returns.
+// The lease is held by the locker plugin, which defaults to the database one, as defaultlocker grants every replica.
+func (a *facade) LeadCron(ctx context.Context, fn func(ctx context.Context) error) error {
+	return a.runWithLease(ctx, CronLeaderLease, a.conf.CronLease.TTL, a.conf.CronLease.RenewInterval, fn)
+}
+
+// runWithLease runs fn holding the lease of the name, which is renewed every interval until fn returns
+func (a *facade) runWithLease(ctx context.Context, name string, ttl, interval time.Duration, fn func(ctx context.Context) error) error {
+	seconds := int64(ttl / time.Second)
+	if seconds <= 0 {
+		seconds = 1
+	}
+	start := time.Now()
+	ok, err := a.locker.Acquire(ctx, name, a.leaseHolder, seconds)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return common.Error(common.ErrNotLeader, common.Field("name", name))
+	}
+	defer a.locker.Unlock(context.Background(), name, a.leaseHolder)
+
+	leaseCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lease := &lease{name: name, expire: start.Add(time.Duration(seconds) * time.Second)}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.renewLease(leaseCtx, cancel, lease, seconds, interval)
+	}()
+	err = fn(leaseCtx)
+	cancel()
+	wg.Wait()
+	if lease.lost {
+		return common.Error(common.ErrNotLeader, common.Field("name", name), common.Field("error", "the lease is lost"))
+	}
+	return err
+}
+
+type lease struct {
+	name   string
+	expire time.Time
+	lost   bool
+}
+
+// renewLease renews the lease every interval until ctx is done, the lease is lost and ctx is canceled if it's
+// taken over by others or not renewed before it expires
+func (a *facade) renewLease(ctx context.Context, cancel context.CancelFunc, l *lease, ttl int64, interval time.Duration) {
+	if interval <= 0 || interval >= time.Duration(ttl)*time.Second {
+		interval = time.Duration(ttl) * time.Second / 3
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		start := time.Now()
+		ok, err := a.locker.Renew(ctx, l.name, a.leaseHolder, ttl)
+		if err == nil && ok {
+			l.expire = start.Add(time.Duration(ttl) * time.Second)
+			continue
+		}
+		if err != nil && time.Now().Before(l.expire) {
+			a.log.Warn("failed to renew the lease, which will be retried", log.Any("name", l.name), log.Error(err))
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		a.log.Warn("the lease is lost, the runs holding it are stopped", log.Any("name", l.name), log.Error(err))
+		l.lost = true
+		cancel()
+		return
+	}
+}
@@ -0,0 +1,91 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestLeadCron(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		locker:      mAppFacade.sLocker,
+		leaseHolder: "r1",
+		log:         log.L(),
+	}
+	appFacade.conf.CronLease.TTL = 30 * time.Second
+	appFacade.conf.CronLease.RenewInterval = 10 * time.Second
+
+	// the lease held by another replica
+	mAppFacade.sLocker.EXPECT().Acquire(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(false, nil)
+	err := appFacade.LeadCron(context.Background(), func(ctx context.Context) error {
+		t.Fatal("fn shouldn't run without the lease")
+		return nil
+	})
+	assert.Equal(t, common.ErrNotLeader, err.(errors.Coder).Code())
+
+	mAppFacade.sLocker.EXPECT().Acquire(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(false, unknownErr)
+	err = appFacade.LeadCron(context.Background(), func(ctx context.Context) error { return nil })
+	assert.Equal(t, unknownErr, errors.Cause(err))
+
+	// the lease is released after fn returns
+	mAppFacade.sLocker.EXPECT().Acquire(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(true, nil)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), CronLeaderLease, "r1")
+	runs := 0
+	err = appFacade.LeadCron(context.Background(), func(ctx context.Context) error {
+		runs++
+		return unknownErr
+	})
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, 1, runs)
+
+	// the lease is renewed while fn runs, and the failure of renewal is retried until the lease expires
+	appFacade.conf.CronLease.RenewInterval = 10 * time.Millisecond
+	mAppFacade.sLocker.EXPECT().Acquire(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(true, nil)
+	renewed := make(chan struct{})
+	gomock.InOrder(
+		mAppFacade.sLocker.EXPECT().Renew(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(false, unknownErr),
+		mAppFacade.sLocker.EXPECT().Renew(gomock.Any(), CronLeaderLease, "r1", int64(30)).DoAndReturn(
+			func(context.Context, string, string, int64) (bool, error) {
+				close(renewed)
+				return true, nil
+			}),
+		mAppFacade.sLocker.EXPECT().Renew(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(true, nil).AnyTimes(),
+	)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), CronLeaderLease, "r1")
+	err = appFacade.LeadCron(context.Background(), func(ctx context.Context) error {
+		<-renewed
+		return ctx.Err()
+	})
+	assert.NoError(t, err)
+}
+
+func TestLeadCronLeaseLost(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		locker:      mAppFacade.sLocker,
+		leaseHolder: "r1",
+		log:         log.L(),
+	}
+	appFacade.conf.CronLease.TTL = 30 * time.Second
+	appFacade.conf.CronLease.RenewInterval = 10 * time.Millisecond
+
+	// fn is stopped once the lease is lost
+	mAppFacade.sLocker.EXPECT().Acquire(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(true, nil)
+	mAppFacade.sLocker.EXPECT().Renew(gomock.Any(), CronLeaderLease, "r1", int64(30)).Return(false, nil)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), CronLeaderLease, "r1")
+	err := appFacade.LeadCron(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.Trace(ctx.Err())
+	})
+	assert.Equal(t, common.ErrNotLeader, err.(errors.Coder).Code())
+}
@@ -0,0 +1,51 @@
+package facade
+
+import (
+	"context"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+const appLockPrefix = "baetyl-app-"
+
+// lockApp locks the app across the replicas, so that the mutations of the app are serialized. The lock is waited
+// until the deadline of ctx, ErrLocked is returned if it's not acquired in time. The app isn't locked without locker.
+func (a *facade) lockApp(ctx context.Context, ns, name string) (func(), error) {
+	if a.locker == nil {
+		return func() {}, nil
+	}
+	key := appLockPrefix + ns + "/" + name
+	version, err := a.locker.Lock(ctx, key, 0)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, common.Error(common.ErrLocked, common.Field("name", name))
+		}
+		return nil, errors.Trace(err)
+	}
+	return func() { a.locker.Unlock(context.Background(), key, version) }, nil
+}
+
+// lockApps locks the apps in the order of their names to avoid deadlocks between the batches,
+// the locks acquired are released if any of them fails
+func (a *facade) lockApps(ctx context.Context, ns string, names []string) (func(), error) {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	unlocks := make([]func(), 0, len(sorted))
+	unlockAll := func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+	for _, name := range sorted {
+		unlock, err := a.lockApp(ctx, ns, name)
+		if err != nil {
+			unlockAll()
+			return nil, err
+		}
+		unlocks = append(unlocks, unlock)
+	}
+	return unlockAll, nil
+}
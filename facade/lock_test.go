@@ -0,0 +1,108 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestLockApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		locker:    mAppFacade.sLocker,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	lock := appLockPrefix + ns + "/abc"
+	app := &specV1.Application{Namespace: ns, Name: "abc"}
+
+	// the mutation is applied under the lock
+	gomock.InOrder(
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v1", nil),
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(app, nil),
+		mAppFacade.sApp.EXPECT().Delete(nil, ns, "abc", "").Return(nil),
+		mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, nil),
+		mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil),
+		mAppFacade.txFactory.EXPECT().Commit(nil),
+		mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v1"),
+	)
+	err := appFacade.DeleteApp(context.Background(), ns, "abc", app)
+	assert.NoError(t, err)
+
+	// the lock held by another replica is waited until the deadline, nothing is written
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).DoAndReturn(
+		func(ctx context.Context, _ string, _ int64) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = appFacade.UpdateApp(ctx, ns, app, &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b"}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrLocked, err.(errors.Coder).Code())
+
+	// the failure of the locker
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("", unknownErr)
+	err = appFacade.DeleteApp(context.Background(), ns, "abc", app)
+	assert.Error(t, err)
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+}
+
+func TestLockAppsCreated(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		locker:    mAppFacade.sLocker,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	lockA, lockB := appLockPrefix+ns+"/a", appLockPrefix+ns+"/b"
+
+	// the apps of the batch are locked in the order of their names before the transaction
+	gomock.InOrder(
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lockA, int64(0)).Return("v1", nil),
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lockB, int64(0)).Return("v2", nil),
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.txFactory.EXPECT().Commit(nil),
+		mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lockB, "v2"),
+		mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lockA, "v1"),
+	)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).DoAndReturn(
+		func(_ interface{}, _ string, app, _ *specV1.Application) (*specV1.Application, error) {
+			return app, nil
+		}).Times(2)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	_, err := appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{
+		{App: &specV1.Application{Namespace: ns, Name: "b"}},
+		{App: &specV1.Application{Namespace: ns, Name: "a"}},
+	})
+	assert.NoError(t, err)
+
+	// the locks acquired are released if any of them fails, nothing is written
+	gomock.InOrder(
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lockA, int64(0)).Return("v3", nil),
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lockB, int64(0)).Return("", unknownErr),
+		mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lockA, "v3"),
+	)
+	_, err = appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{
+		{App: &specV1.Application{Namespace: ns, Name: "b"}},
+		{App: &specV1.Application{Namespace: ns, Name: "a"}},
+	})
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+}
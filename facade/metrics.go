@@ -0,0 +1,93 @@
+package facade
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+
+	txOutcomeCommit   = "commit"
+	txOutcomeRollback = "rollback"
+
+	cacheHit  = "hit"
+	cacheMiss = "miss"
+)
+
+var (
+	// configCleanFailures counts the generated function configs failed to be deleted,
+	// which are left as orphans until reclaimed
+	configCleanFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "baetyl_cloud_facade_config_clean_failures_total",
+		Help: "The number of generated function configs failed to be deleted.",
+	})
+
+	facadeCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "baetyl_cloud_facade_calls_total",
+		Help: "The number of facade calls partitioned by namespace, method and result.",
+	}, []string{"namespace", "method", "result"})
+
+	facadeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "baetyl_cloud_facade_call_duration_seconds",
+		Help:    "The latency of facade calls partitioned by namespace and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"namespace", "method"})
+
+	facadeTransactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "baetyl_cloud_facade_transactions_total",
+		Help: "The number of facade transactions partitioned by namespace, method and outcome (commit or rollback).",
+	}, []string{"namespace", "method", "outcome"})
+
+	facadeAppCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "baetyl_cloud_facade_app_cache_requests_total",
+		Help: "The number of app cache lookups partitioned by result (hit or miss).",
+	}, []string{"result"})
+
+	facadeTxRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "baetyl_cloud_facade_transaction_retries_total",
+		Help: "The number of facade transactions retried because of deadlock or serialization failure.",
+	}, []string{"namespace", "method"})
+
+	// facadeIndexEntries and facadeIndexStaleEntries are set by the node-app index checks, which walk the whole
+	// namespace, so they're as fresh as the latest check
+	facadeIndexEntries = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "baetyl_cloud_facade_index_entries",
+		Help: "The number of node-app index entries found by the latest index check partitioned by namespace.",
+	}, []string{"namespace"})
+
+	facadeIndexStaleEntries = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "baetyl_cloud_facade_index_stale_entries",
+		Help: "The number of node-app index issues left by the latest index check partitioned by namespace and reason.",
+	}, []string{"namespace", "reason"})
+)
+
+// indexIssueReasons the reasons of the index issues, all of which are reported so that the ones fixed drop to 0
+var indexIssueReasons = []string{IndexMissingApp, IndexMissingVersion, IndexStaleNode, IndexMissingNode}
+
+// observeIndexReport records the index entries and the issues of the index check, the issues repaired aren't left
+func observeIndexReport(report *IndexReport, repaired bool) {
+	facadeIndexEntries.WithLabelValues(report.Namespace).Set(float64(report.Entries))
+	counts := map[string]int{}
+	if !repaired {
+		for _, issue := range report.Issues {
+			counts[issue.Reason]++
+		}
+	}
+	for _, reason := range indexIssueReasons {
+		facadeIndexStaleEntries.WithLabelValues(report.Namespace, reason).Set(float64(counts[reason]))
+	}
+}
+
+// observeCall records the call count and latency of a facade method, it is supposed to be deferred
+func observeCall(ns, method string, start time.Time, err *error) {
+	result := resultSuccess
+	if err != nil && *err != nil {
+		result = resultError
+	}
+	facadeCalls.WithLabelValues(ns, method, result).Inc()
+	facadeDuration.WithLabelValues(ns, method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,61 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestObserveCall(t *testing.T) {
+	ns, method := "metrics-ns", "ObserveCall"
+	success := testutil.ToFloat64(facadeCalls.WithLabelValues(ns, method, resultSuccess))
+	failure := testutil.ToFloat64(facadeCalls.WithLabelValues(ns, method, resultError))
+
+	var err error
+	observeCall(ns, method, time.Now(), &err)
+	err = unknownErr
+	observeCall(ns, method, time.Now(), &err)
+	observeCall(ns, method, time.Now(), nil)
+
+	assert.Equal(t, success+2, testutil.ToFloat64(facadeCalls.WithLabelValues(ns, method, resultSuccess)))
+	assert.Equal(t, failure+1, testutil.ToFloat64(facadeCalls.WithLabelValues(ns, method, resultError)))
+}
+
+func TestTxMetrics(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{TxRetry: config.TxRetry{Max: 1, BaseDelay: time.Millisecond}},
+		log:       log.L(),
+	}
+	ns, method := "metrics-ns", "TxMetrics"
+	commits := testutil.ToFloat64(facadeTransactions.WithLabelValues(ns, method, txOutcomeCommit))
+	rollbacks := testutil.ToFloat64(facadeTransactions.WithLabelValues(ns, method, txOutcomeRollback))
+	retries := testutil.ToFloat64(facadeTxRetries.WithLabelValues(ns, method))
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	attempts := 0
+	err := appFacade.runTx(context.Background(), ns, method, func(tx interface{}, undo *compensations) error {
+		attempts++
+		if attempts == 1 {
+			return &mysql.MySQLError{Number: 1213}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, commits+1, testutil.ToFloat64(facadeTransactions.WithLabelValues(ns, method, txOutcomeCommit)))
+	assert.Equal(t, rollbacks+1, testutil.ToFloat64(facadeTransactions.WithLabelValues(ns, method, txOutcomeRollback)))
+	assert.Equal(t, retries+1, testutil.ToFloat64(facadeTxRetries.WithLabelValues(ns, method)))
+}
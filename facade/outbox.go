@@ -0,0 +1,198 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// newAppEvent builds the lifecycle event of the app changed
+func newAppEvent(action models.AppAction, ns string, app *specV1.Application, nodes []string) *models.AppEvent {
+	if nodes == nil {
+		nodes = []string{}
+	}
+	return &models.AppEvent{
+		Namespace: ns,
+		Name:      app.Name,
+		Version:   app.Version,
+		Action:    action,
+		Nodes:     nodes,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+func (a *facade) outboxEnabled() bool {
+	return a.conf.Outbox.Enabled && a.outbox != nil
+}
+
+// writeAppOutbox writes the event of the change to the outbox within its transaction if the outbox is enabled,
+// the event is relayed to the sink by RelayAppOutbox instead of being published after the commit
+func (a *facade) writeAppOutbox(tx interface{}, action models.AppAction, ns string, app *specV1.Application, nodes []string) error {
+	if !a.outboxEnabled() {
+		return nil
+	}
+	return a.outbox.CreateAppOutbox(tx, newAppEvent(action, ns, app, nodes))
+}
+
+// RelayAppOutbox publishes the events in the outbox to the sink in the order they're written, each event is
+// deleted after it's published. The relay stops at the first failure to keep the order and resumes from it next
+// time, so the events are delivered at least once and the sink may receive an event again.
+func (a *facade) RelayAppOutbox(ctx context.Context) error {
+	if !a.outboxEnabled() || a.event == nil {
+		return nil
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		events, err := a.outbox.ListAppOutbox(a.conf.Outbox.BatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range events {
+			e := &events[i]
+			if err = a.event.Publish(ctx, &e.Event); err != nil {
+				a.log.Warn("failed to relay app event",
+					log.Any(common.KeyContextNamespace, e.Event.Namespace),
+					log.Any("name", e.Event.Name),
+					log.Any("id", e.Id),
+					log.Error(err))
+				return err
+			}
+			if err = a.outbox.DeleteAppOutbox(e.Id); err != nil {
+				return err
+			}
+		}
+		if len(events) < a.conf.Outbox.BatchSize {
+			return nil
+		}
+	}
+}
+
+func (a *facade) cronOutboxEnabled() bool {
+	return a.outboxEnabled() && a.conf.Outbox.Cron
+}
+
+// putAppCron creates the cron of the app, or updates it if exists. The operation is written to the outbox within
+// the transaction and relayed by RelayCronOutbox if the cron outbox is enabled, otherwise the cron is written
+// directly and the cron created is registered to undo so that it's deleted on rollback
+func (a *facade) putAppCron(ctx context.Context, tx interface{}, cronApp *models.Cron, exists bool, undo *compensations) error {
+	if a.cronOutboxEnabled() {
+		return traceStep(ctx, "WriteCronOutbox", func() error {
+			return a.outbox.CreateCronOutbox(tx, &models.CronOutboxOp{
+				Namespace: cronApp.Namespace,
+				Name:      cronApp.Name,
+				Op:        models.CronOutboxPut,
+				Cron:      cronApp,
+			})
+		})
+	}
+	if exists {
+		return traceStep(ctx, "UpdateCron", func() error {
+			return errors.Trace(a.cron.UpdateCron(cronApp))
+		})
+	}
+	err := traceStep(ctx, "CreateCron", func() error {
+		return errors.Trace(a.cron.CreateCron(cronApp))
+	})
+	if err != nil {
+		return err
+	}
+	if undo != nil {
+		name, namespace := cronApp.Name, cronApp.Namespace
+		// the cron is not stored within the transaction
+		undo.add(func() error {
+			return a.cron.DeleteCron(name, namespace)
+		})
+	}
+	return nil
+}
+
+// dropAppCron deletes the cron of the app, through the outbox if the cron outbox is enabled
+func (a *facade) dropAppCron(ctx context.Context, tx interface{}, ns, name string) error {
+	if a.cronOutboxEnabled() {
+		return traceStep(ctx, "WriteCronOutbox", func() error {
+			return a.outbox.CreateCronOutbox(tx, &models.CronOutboxOp{
+				Namespace: ns,
+				Name:      name,
+				Op:        models.CronOutboxDelete,
+			})
+		})
+	}
+	return traceStep(ctx, "DeleteCron", func() error {
+		return errors.Trace(a.cron.DeleteCron(name, ns))
+	})
+}
+
+// RelayCronOutbox applies the cron operations in the outbox to the cron store in the order they're written, each
+// operation is deleted after it's applied. The relay stops at the first failure and retries it next time, so the
+// operations are applied at least once. The put creates the cron missing and updates the cron existing, and the
+// delete ignores the cron missing, so applying an operation again converges to the same cron, and the crons
+// converge to the last operations of the apps committed.
+func (a *facade) RelayCronOutbox(ctx context.Context) error {
+	if !a.cronOutboxEnabled() {
+		return nil
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		ops, err := a.outbox.ListCronOutbox(a.conf.Outbox.BatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range ops {
+			op := &ops[i]
+			if err = a.applyCronOutbox(ctx, op); err != nil {
+				a.log.Warn("failed to relay app cron",
+					log.Any(common.KeyContextNamespace, op.Namespace),
+					log.Any("name", op.Name),
+					log.Any("op", op.Op),
+					log.Any("id", op.Id),
+					log.Error(err))
+				return err
+			}
+			if err = a.outbox.DeleteCronOutbox(op.Id); err != nil {
+				return err
+			}
+		}
+		if len(ops) < a.conf.Outbox.BatchSize {
+			return nil
+		}
+	}
+}
+
+// applyCronOutbox applies the cron operation idempotently while holding the lock of the app,
+// so that it doesn't interleave with the changes of the app
+func (a *facade) applyCronOutbox(ctx context.Context, op *models.CronOutboxOp) error {
+	unlock, err := a.lockApp(ctx, op.Namespace, op.Name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	switch op.Op {
+	case models.CronOutboxPut:
+		if op.Cron == nil {
+			break
+		}
+		_, err = a.cron.GetCron(nil, op.Name, op.Namespace)
+		if err == nil {
+			return errors.Trace(a.cron.UpdateCron(op.Cron))
+		}
+		if !isNotFound(err) {
+			return errors.Trace(err)
+		}
+		return errors.Trace(a.cron.CreateCron(op.Cron))
+	case models.CronOutboxDelete:
+		return errors.Trace(a.cron.DeleteCron(op.Name, op.Namespace))
+	}
+	a.log.Warn("the unknown cron operation is dropped",
+		log.Any(common.KeyContextNamespace, op.Namespace), log.Any("name", op.Name), log.Any("op", op.Op), log.Any("id", op.Id))
+	return nil
+}
@@ -0,0 +1,225 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestWriteAppOutbox(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		outbox:    mAppFacade.sOutbox,
+		txFactory: mAppFacade.txFactory,
+		event:     mAppFacade.event,
+		conf:      config.Facade{Outbox: config.Outbox{Enabled: true, BatchSize: 10}},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Namespace: ns, Name: "abc", Version: "1", Selector: "a=b"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// the event is written within the transaction instead of being published
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	mAppFacade.sOutbox.EXPECT().CreateAppOutbox(nil, gomock.Any()).DoAndReturn(func(_ interface{}, e *models.AppEvent) error {
+		assert.Equal(t, ns, e.Namespace)
+		assert.Equal(t, "abc", e.Name)
+		assert.Equal(t, models.AppCreated, e.Action)
+		assert.Equal(t, []string{"n1"}, e.Nodes)
+		return nil
+	})
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.NoError(t, err)
+
+	// the change is rolled back if the event fails to be written
+	newApp := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", Description: "new"}
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, newApp).Return(newApp, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, newApp).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	mAppFacade.sOutbox.EXPECT().CreateAppOutbox(nil, gomock.Any()).Return(unknownErr)
+	// the desire of the nodes is restored by the compensation
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil).AnyTimes()
+	_, err = appFacade.UpdateApp(context.Background(), ns, app, newApp, nil)
+	assert.Error(t, err)
+}
+
+func TestRelayAppOutbox(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		outbox: mAppFacade.sOutbox,
+		event:  mAppFacade.event,
+		conf:   config.Facade{Outbox: config.Outbox{Enabled: true, BatchSize: 2}},
+		log:    log.L(),
+	}
+	ctx := context.Background()
+	e1 := models.AppOutboxEvent{Id: 1, Event: models.AppEvent{Namespace: "cloud", Name: "a", Version: "1"}}
+	e2 := models.AppOutboxEvent{Id: 2, Event: models.AppEvent{Namespace: "cloud", Name: "a", Version: "2"}}
+	e3 := models.AppOutboxEvent{Id: 3, Event: models.AppEvent{Namespace: "cloud", Name: "b", Version: "1"}}
+
+	// relayed in order batch by batch
+	gomock.InOrder(
+		mAppFacade.sOutbox.EXPECT().ListAppOutbox(2).Return([]models.AppOutboxEvent{e1, e2}, nil),
+		mAppFacade.event.EXPECT().Publish(ctx, &e1.Event).Return(nil),
+		mAppFacade.sOutbox.EXPECT().DeleteAppOutbox(uint64(1)).Return(nil),
+		mAppFacade.event.EXPECT().Publish(ctx, &e2.Event).Return(nil),
+		mAppFacade.sOutbox.EXPECT().DeleteAppOutbox(uint64(2)).Return(nil),
+		mAppFacade.sOutbox.EXPECT().ListAppOutbox(2).Return([]models.AppOutboxEvent{e3}, nil),
+		mAppFacade.event.EXPECT().Publish(ctx, &e3.Event).Return(nil),
+		mAppFacade.sOutbox.EXPECT().DeleteAppOutbox(uint64(3)).Return(nil),
+	)
+	err := appFacade.RelayAppOutbox(ctx)
+	assert.NoError(t, err)
+
+	// stopped at the failure and the event is kept to be relayed again
+	gomock.InOrder(
+		mAppFacade.sOutbox.EXPECT().ListAppOutbox(2).Return([]models.AppOutboxEvent{e1, e2}, nil),
+		mAppFacade.event.EXPECT().Publish(ctx, &e1.Event).Return(unknownErr),
+	)
+	err = appFacade.RelayAppOutbox(ctx)
+	assert.Equal(t, unknownErr, err)
+
+	mAppFacade.sOutbox.EXPECT().ListAppOutbox(2).Return(nil, unknownErr)
+	err = appFacade.RelayAppOutbox(ctx)
+	assert.Error(t, err)
+
+	// nothing to do if the outbox is disabled
+	appFacade.conf.Outbox.Enabled = false
+	err = appFacade.RelayAppOutbox(ctx)
+	assert.NoError(t, err)
+}
+
+func TestWriteCronOutbox(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		outbox:    mAppFacade.sOutbox,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{Outbox: config.Outbox{Enabled: true, Cron: true, BatchSize: 10}},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	cronTime := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	app := &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", CronStatus: specV1.CronWait, CronTime: cronTime}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.sOutbox.EXPECT().CreateAppOutbox(nil, gomock.Any()).Return(nil).AnyTimes()
+
+	// the cron is written to the outbox within the transaction instead of the cron store
+	created := &specV1.Application{Namespace: ns, Name: "abc", Version: "1", CronStatus: specV1.CronWait, CronTime: cronTime}
+	mAppFacade.sOutbox.EXPECT().CreateCronOutbox(nil, gomock.Any()).DoAndReturn(func(_ interface{}, op *models.CronOutboxOp) error {
+		assert.Equal(t, models.CronOutboxPut, op.Op)
+		assert.Equal(t, "abc", op.Name)
+		assert.Equal(t, "a=b", op.Cron.Selector)
+		assert.Equal(t, cronTime, op.Cron.CronTime)
+		return nil
+	})
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).Return(created, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, created).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", nil).Return(nil)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.NoError(t, err)
+
+	// the app deployed immediately deletes its cron through the outbox
+	newApp := &specV1.Application{Namespace: ns, Name: "abc", Selector: "c=d"}
+	mAppFacade.sOutbox.EXPECT().CreateCronOutbox(nil, &models.CronOutboxOp{Namespace: ns, Name: "abc", Op: models.CronOutboxDelete}).Return(nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(created, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, newApp).Return(newApp, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "").Return(nil, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, newApp).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	_, err = appFacade.UpdateApp(context.Background(), ns, created, newApp, nil)
+	assert.NoError(t, err)
+
+	// the operation is rolled back with the app, nothing is left to compensate
+	mAppFacade.sOutbox.EXPECT().CreateCronOutbox(nil, gomock.Any()).Return(nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).Return(nil, unknownErr)
+	app = &specV1.Application{Namespace: ns, Name: "abc", Selector: "a=b", CronStatus: specV1.CronWait, CronTime: cronTime}
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.Error(t, err)
+}
+
+func TestRelayCronOutbox(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		cron:   mAppFacade.sCron,
+		outbox: mAppFacade.sOutbox,
+		conf:   config.Facade{Outbox: config.Outbox{Enabled: true, Cron: true, BatchSize: 2}},
+		log:    log.L(),
+	}
+	ctx := context.Background()
+	c1 := &models.Cron{Namespace: "cloud", Name: "a", Selector: "a=b", CronTime: time.Now()}
+	c2 := &models.Cron{Namespace: "cloud", Name: "b", Selector: "c=d", CronTime: time.Now()}
+	op1 := models.CronOutboxOp{Id: 1, Namespace: "cloud", Name: "a", Op: models.CronOutboxPut, Cron: c1}
+	op2 := models.CronOutboxOp{Id: 2, Namespace: "cloud", Name: "b", Op: models.CronOutboxPut, Cron: c2}
+	op3 := models.CronOutboxOp{Id: 3, Namespace: "cloud", Name: "a", Op: models.CronOutboxDelete}
+
+	// applied in order batch by batch, the put creates the cron missing and updates the cron existing
+	gomock.InOrder(
+		mAppFacade.sOutbox.EXPECT().ListCronOutbox(2).Return([]models.CronOutboxOp{op1, op2}, nil),
+		mAppFacade.sCron.EXPECT().GetCron(nil, "a", "cloud").Return(nil, common.Error(common.ErrResourceNotFound)),
+		mAppFacade.sCron.EXPECT().CreateCron(c1).Return(nil),
+		mAppFacade.sOutbox.EXPECT().DeleteCronOutbox(uint64(1)).Return(nil),
+		mAppFacade.sCron.EXPECT().GetCron(nil, "b", "cloud").Return(c2, nil),
+		mAppFacade.sCron.EXPECT().UpdateCron(c2).Return(nil),
+		mAppFacade.sOutbox.EXPECT().DeleteCronOutbox(uint64(2)).Return(nil),
+		mAppFacade.sOutbox.EXPECT().ListCronOutbox(2).Return([]models.CronOutboxOp{op3}, nil),
+		mAppFacade.sCron.EXPECT().DeleteCron("a", "cloud").Return(nil),
+		mAppFacade.sOutbox.EXPECT().DeleteCronOutbox(uint64(3)).Return(nil),
+	)
+	assert.NoError(t, appFacade.RelayCronOutbox(ctx))
+
+	// the failure stops the relay and the operation is kept to be applied again
+	gomock.InOrder(
+		mAppFacade.sOutbox.EXPECT().ListCronOutbox(2).Return([]models.CronOutboxOp{op1, op3}, nil),
+		mAppFacade.sCron.EXPECT().GetCron(nil, "a", "cloud").Return(c1, nil),
+		mAppFacade.sCron.EXPECT().UpdateCron(c1).Return(unknownErr),
+	)
+	assert.Error(t, appFacade.RelayCronOutbox(ctx))
+
+	// applied again after it's applied but not deleted, the cron is the same
+	gomock.InOrder(
+		mAppFacade.sOutbox.EXPECT().ListCronOutbox(2).Return([]models.CronOutboxOp{op1}, nil),
+		mAppFacade.sCron.EXPECT().GetCron(nil, "a", "cloud").Return(c1, nil),
+		mAppFacade.sCron.EXPECT().UpdateCron(c1).Return(nil),
+		mAppFacade.sOutbox.EXPECT().DeleteCronOutbox(uint64(1)).Return(unknownErr),
+		mAppFacade.sOutbox.EXPECT().ListCronOutbox(2).Return([]models.CronOutboxOp{op1}, nil),
+		mAppFacade.sCron.EXPECT().GetCron(nil, "a", "cloud").Return(c1, nil),
+		mAppFacade.sCron.EXPECT().UpdateCron(c1).Return(nil),
+		mAppFacade.sOutbox.EXPECT().DeleteCronOutbox(uint64(1)).Return(nil),
+	)
+	assert.Error(t, appFacade.RelayCronOutbox(ctx))
+	assert.NoError(t, appFacade.RelayCronOutbox(ctx))
+
+	// nothing is relayed if the cron outbox is disabled
+	appFacade.conf.Outbox.Cron = false
+	assert.NoError(t, appFacade.RelayCronOutbox(ctx))
+}
@@ -0,0 +1,121 @@
+package facade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	jsonpatch "github.com/evanphx/json-patch"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// PatchType the format of the patch applied to the app by PatchApp
+type PatchType string
+
+const (
+	// JSONPatchType the JSON Patch of RFC 6902, a list of the operations applied to the app in order
+	JSONPatchType PatchType = "application/json-patch+json"
+	// MergePatchType the JSON Merge Patch of RFC 7386, the fields set are replaced and the fields null are removed
+	MergePatchType PatchType = "application/merge-patch+json"
+)
+
+// PatchApp applies the patch to the current version of the app and updates the app with the result as UpdateApp
+// does. The app is read and updated within the same transaction while holding the lock of the app, so the small
+// edits of the concurrent callers are never lost, unlike the read-modify-write of UpdateApp. The version of the
+// app can be tested by the patch, e.g. the "test" operation of JSON Patch, ErrResourceVersionConflict is returned
+// if the version patched isn't the current one. The name and the namespace of the app can't be patched.
+func (a *facade) PatchApp(ctx context.Context, ns, name string, patch []byte, patchType PatchType) (res *specV1.Application, err error) {
+	defer observeCall(ns, "PatchApp", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	done, err := a.drain.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if err = a.limitAppWrites(ctx, ns, name); err != nil {
+		return nil, err
+	}
+	ctx, span := startAppSpan(ctx, "PatchApp", ns, name)
+	defer func() { endSpan(span, res, err) }()
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	var nodes []string
+	err = a.runTx(ctx, ns, "PatchApp", func(tx interface{}, undo *compensations) error {
+		cur, err := a.app.Get(ns, name, "")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if cur.CronStatus == specV1.CronWait {
+			// the selector of the app waiting for cron is kept by its cron
+			cronApp, err := a.cron.GetCron(tx, name, ns)
+			if err == nil {
+				cur.Selector = cronApp.Selector
+			} else if !isNotFound(err) {
+				return errors.Trace(err)
+			}
+		}
+		app, err := patchApp(cur, patch, patchType)
+		if err != nil {
+			return err
+		}
+		if err = validAppCron(app, cur.CronStatus != specV1.CronWait); err != nil {
+			return err
+		}
+		res, nodes, err = a.updateApp(ctx, tx, ns, cur, app, nil, undo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.publishAppEvent(ctx, models.AppUpdated, ns, res, nodes)
+	if a.history != nil {
+		a.pruneAppVersionsInBackground(ns, res.Name)
+	}
+	return res, nil
+}
+
+// patchApp applies the patch to the copy of the app, ErrRequestParamInvalid is returned if the patch is invalid
+func patchApp(cur *specV1.Application, patch []byte, patchType PatchType) (*specV1.Application, error) {
+	doc, err := json.Marshal(cur)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var patched []byte
+	switch patchType {
+	case JSONPatchType:
+		var ops jsonpatch.Patch
+		if ops, err = jsonpatch.DecodePatch(patch); err == nil {
+			patched, err = ops.Apply(doc)
+		}
+	case MergePatchType:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	default:
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the patch type (%s) is not supported", patchType)))
+	}
+	if err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("failed to apply the patch to the app (%s): %s", cur.Name, err.Error())))
+	}
+	app := &specV1.Application{}
+	if err = json.Unmarshal(patched, app); err != nil {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the app (%s) patched is invalid: %s", cur.Name, err.Error())))
+	}
+	if app.Name != cur.Name || app.Namespace != cur.Namespace {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the name and the namespace of the app (%s) can't be patched", cur.Name)))
+	}
+	app.CreationTimestamp = cur.CreationTimestamp
+	return app, nil
+}
@@ -0,0 +1,123 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestPatchApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	created := time.Now().UTC().Truncate(time.Second)
+	cur := func() *specV1.Application {
+		return &specV1.Application{
+			Namespace:         ns,
+			Name:              "abc",
+			Version:           "1",
+			Selector:          "a=b",
+			Description:       "old",
+			Labels:            map[string]string{"team": "edge"},
+			CreationTimestamp: created,
+		}
+	}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	// the JSON Patch is applied to the current version read within the transaction
+	patched := cur()
+	patched.Description = "new"
+	patched.Labels = map[string]string{"team": "edge", "env": "prod"}
+	updated := *patched
+	updated.Version = "2"
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(cur(), nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(cur(), nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, patched).Return(&updated, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, &updated).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	patch := `[
+		{"op": "test", "path": "/version", "value": "1"},
+		{"op": "replace", "path": "/description", "value": "new"},
+		{"op": "add", "path": "/labels/env", "value": "prod"}
+	]`
+	res, err := appFacade.PatchApp(context.Background(), ns, "abc", []byte(patch), JSONPatchType)
+	assert.NoError(t, err)
+	assert.Equal(t, &updated, res)
+
+	// the merge patch replaces the fields set and removes the fields null
+	patched = cur()
+	patched.Selector = "c=d"
+	patched.Labels = map[string]string{}
+	updated = *patched
+	updated.Version = "2"
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(cur(), nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(cur(), nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, patched).Return(&updated, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, &updated).Return([]string{"n2"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n2"}).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, gomock.Any(), gomock.Any()).Return(nil)
+	res, err = appFacade.PatchApp(context.Background(), ns, "abc", []byte(`{"selector": "c=d", "labels": {"team": null}}`), MergePatchType)
+	assert.NoError(t, err)
+	assert.Equal(t, &updated, res)
+
+	// the patch of the stale version conflicts
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(cur(), nil)
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(cur(), nil)
+	_, err = appFacade.PatchApp(context.Background(), ns, "abc", []byte(`{"version": "0", "description": "stale"}`), MergePatchType)
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(errors.Coder).Code())
+
+	// the selector of the app waiting for cron is patched over the one kept by its cron
+	cronApp := cur()
+	cronApp.Selector = ""
+	cronApp.CronStatus = specV1.CronWait
+	cronApp.CronTime = time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(cronApp, nil)
+	mAppFacade.sCron.EXPECT().GetCron(nil, "abc", ns).Return(&models.Cron{Namespace: ns, Name: "abc", Selector: "a=b"}, nil)
+	_, err = appFacade.PatchApp(context.Background(), ns, "abc", []byte(`[{"op": "test", "path": "/selector", "value": "c=d"}]`), JSONPatchType)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	// the invalid patches
+	for _, c := range []struct {
+		patch     string
+		patchType PatchType
+		msg       string
+	}{
+		{`{"name": "def"}`, MergePatchType, "can't be patched"},
+		{`[{"op": "remove", "path": "/namespace"}]`, JSONPatchType, "can't be patched"},
+		{`[{"op": "replace", "path": "/missing/field", "value": 1}]`, JSONPatchType, "failed to apply"},
+		{`{"selector":`, MergePatchType, "failed to apply"},
+		{`{"system": "yes"}`, MergePatchType, "patched is invalid"},
+		{`{}`, PatchType("application/strategic-merge-patch+json"), "not supported"},
+	} {
+		mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(cur(), nil)
+		_, err = appFacade.PatchApp(context.Background(), ns, "abc", []byte(c.patch), c.patchType)
+		assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code(), c.patch)
+		assert.Contains(t, err.Error(), c.msg, c.patch)
+	}
+
+	// the app missing
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	_, err = appFacade.PatchApp(context.Background(), ns, "abc", []byte(`{}`), MergePatchType)
+	assert.Equal(t, common.ErrResourceNotFound, errors.Cause(err).(errors.Coder).Code())
+}
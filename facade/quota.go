@@ -0,0 +1,54 @@
+package facade
+
+import (
+	"context"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+const appQuotaLockPrefix = "baetyl-app-quota-"
+
+// appQuota returns the max number of apps of the namespace, 0 if unlimited
+func (a *facade) appQuota(ns string) int {
+	if limit, ok := a.conf.AppQuota.Namespaces[ns]; ok {
+		return limit
+	}
+	return a.conf.AppQuota.Limit
+}
+
+// lockAppQuota locks the app quota of the namespace if it's limited, the lock should be held
+// until the creation is committed so that the concurrent creations can't overshoot the quota
+func (a *facade) lockAppQuota(ctx context.Context, ns string) (func(), error) {
+	if a.appQuota(ns) <= 0 {
+		return func() {}, nil
+	}
+	name := appQuotaLockPrefix + ns
+	version, err := a.locker.Lock(ctx, name, 0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return func() { a.locker.Unlock(ctx, name, version) }, nil
+}
+
+// checkAppQuota counts the apps of the namespace, ErrQuotaExceeded is returned if the quota can't hold n more apps.
+// It should be called within the transaction of the creation and under the lock of lockAppQuota.
+func (a *facade) checkAppQuota(ns string, n int) error {
+	limit := a.appQuota(ns)
+	if limit <= 0 {
+		return nil
+	}
+	list, err := a.app.List(ns, &models.ListOptions{})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(list.Items)+n > limit {
+		return common.Error(common.ErrQuotaExceeded,
+			common.Field("type", common.APP),
+			common.Field("namespace", ns),
+			common.Field("limit", limit))
+	}
+	return nil
+}
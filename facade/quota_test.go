@@ -0,0 +1,93 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestCreateAppQuota(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		locker:    mAppFacade.sLocker,
+		txFactory: mAppFacade.txFactory,
+		conf: config.Facade{AppQuota: config.AppQuota{
+			Limit:      2,
+			Namespaces: map[string]int{"unlimited": 0},
+		}},
+	}
+	ns := "baetyl-cloud"
+	lock := appQuotaLockPrefix + ns
+	apps := &models.ApplicationList{Items: []models.AppItem{{Name: "a"}, {Name: "b"}}}
+	// the locks of the apps created
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), gomock.Not(lock), int64(0)).Return("app", nil).AnyTimes()
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), gomock.Not(lock), "app").AnyTimes()
+
+	// the quota is reached
+	gomock.InOrder(
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v1", nil),
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(apps, nil),
+		mAppFacade.txFactory.EXPECT().Rollback(nil),
+		mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v1"),
+	)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, &specV1.Application{Name: "c"}, nil)
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrQuotaExceeded, e.Code())
+
+	// the batch exceeds the quota
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v2", nil)
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "a"}}}, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v2")
+	_, err = appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{
+		{App: &specV1.Application{Name: "c"}},
+		{App: &specV1.Application{Name: "d"}},
+	})
+	e, ok = err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrQuotaExceeded, e.Code())
+
+	// the quota is not reached
+	app := &specV1.Application{Name: "c"}
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v3", nil)
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "a"}}}, nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, app, nil).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, app).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "c", nil).Return(nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v3")
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.NoError(t, err)
+
+	// the namespace is unlimited, neither locked nor counted
+	app = &specV1.Application{Name: "c"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, "unlimited", app, nil).Return(app, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, "unlimited", app).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, "unlimited", "c", nil).Return(nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil)
+	_, err = appFacade.CreateApp(context.Background(), "unlimited", nil, app, nil)
+	assert.NoError(t, err)
+
+	// lock failed
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("", unknownErr)
+	_, err = appFacade.CreateApp(context.Background(), ns, nil, &specV1.Application{Name: "c"}, nil)
+	assert.Equal(t, unknownErr, errors.Cause(err))
+}
@@ -0,0 +1,60 @@
+package facade
+
+import (
+	"context"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+const (
+	appWriteRatePrefix = "baetyl-app-write-"
+	appReadRatePrefix  = "baetyl-app-read-"
+)
+
+// appRateLimit returns the rate and the burst of the token buckets of the namespace
+func (a *facade) appRateLimit(ns string) (float64, int) {
+	if rule, ok := a.conf.RateLimit.Namespaces[ns]; ok {
+		return rule.Rate, rule.Burst
+	}
+	return a.conf.RateLimit.Rate, a.conf.RateLimit.Burst
+}
+
+// limitAppWrites takes a token for the write of the apps from the bucket of the namespace, or from the bucket
+// of each app if limited per app. ErrRateLimited is returned if any bucket is empty. Nothing is limited without
+// the rate limiter.
+func (a *facade) limitAppWrites(ctx context.Context, ns string, names ...string) error {
+	if a.rateLimiter == nil {
+		return nil
+	}
+	if !a.conf.RateLimit.PerApp {
+		return a.takeRateToken(ctx, ns, appWriteRatePrefix+ns)
+	}
+	for _, name := range names {
+		if err := a.takeRateToken(ctx, ns, appWriteRatePrefix+ns+"/"+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// limitAppReads takes a token for the read of the apps from the bucket of the namespace if the reads are limited
+func (a *facade) limitAppReads(ctx context.Context, ns string) error {
+	if a.rateLimiter == nil || !a.conf.RateLimit.Reads {
+		return nil
+	}
+	return a.takeRateToken(ctx, ns, appReadRatePrefix+ns)
+}
+
+func (a *facade) takeRateToken(ctx context.Context, ns, key string) error {
+	rate, burst := a.appRateLimit(ns)
+	ok, err := a.rateLimiter.Allow(ctx, key, rate, burst)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return common.Error(common.ErrRateLimited, common.Field("namespace", ns))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+)
+
+func TestLimitAppRate(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	limiter := mockPlugin.NewMockRateLimiter(mCtl)
+	appFacade := &facade{
+		app:         mAppFacade.sApp,
+		rateLimiter: limiter,
+		conf: config.Facade{RateLimit: config.RateLimit{
+			Enabled:    true,
+			Rate:       10,
+			Burst:      20,
+			Namespaces: map[string]config.RateLimitRule{"slow": {Rate: 1, Burst: 2}},
+		}},
+	}
+	ctx := context.Background()
+	ns := "baetyl-cloud"
+
+	// the writes are rejected once the bucket of the namespace is empty, nothing is written
+	limiter.EXPECT().Allow(gomock.Any(), appWriteRatePrefix+ns, float64(10), 20).Return(false, nil)
+	_, err := appFacade.UpdateApp(ctx, ns, nil, &specV1.Application{Namespace: ns, Name: "abc"}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrRateLimited, err.(errors.Coder).Code())
+
+	// the limit of the namespace overrides the default one
+	limiter.EXPECT().Allow(gomock.Any(), appWriteRatePrefix+"slow", float64(1), 2).Return(false, nil)
+	err = appFacade.DeleteApp(ctx, "slow", "abc", &specV1.Application{Namespace: "slow", Name: "abc"})
+	assert.Equal(t, common.ErrRateLimited, err.(errors.Coder).Code())
+
+	// the failure of the rate limiter
+	limiter.EXPECT().Allow(gomock.Any(), appWriteRatePrefix+ns, float64(10), 20).Return(false, unknownErr)
+	_, err = appFacade.CreateApp(ctx, ns, nil, &specV1.Application{Namespace: ns, Name: "abc"}, nil)
+	assert.Equal(t, unknownErr.Error(), errors.Cause(err).Error())
+
+	// the bucket of each app is taken if limited per app
+	appFacade.conf.RateLimit.PerApp = true
+	limiter.EXPECT().Allow(gomock.Any(), appWriteRatePrefix+ns+"/a", float64(10), 20).Return(true, nil)
+	limiter.EXPECT().Allow(gomock.Any(), appWriteRatePrefix+ns+"/b", float64(10), 20).Return(false, nil)
+	err = appFacade.DeleteApps(ctx, ns, []string{"a", "b"})
+	assert.Equal(t, common.ErrRateLimited, err.(errors.Coder).Code())
+
+	// the reads are unaffected by default
+	mAppFacade.sApp.EXPECT().Get(ns, "abc", "").Return(&specV1.Application{Name: "abc"}, nil)
+	_, err = appFacade.GetApp(ctx, ns, "abc", "")
+	assert.NoError(t, err)
+
+	// the reads are limited on demand
+	appFacade.conf.RateLimit.Reads = true
+	limiter.EXPECT().Allow(gomock.Any(), appReadRatePrefix+ns, float64(10), 20).Return(false, nil)
+	_, err = appFacade.GetApp(ctx, ns, "abc", "")
+	assert.Equal(t, common.ErrRateLimited, err.(errors.Coder).Code())
+	limiter.EXPECT().Allow(gomock.Any(), appReadRatePrefix+ns, float64(10), 20).Return(false, nil)
+	_, err = appFacade.ListApps(ctx, ns, nil)
+	assert.Equal(t, common.ErrRateLimited, err.(errors.Coder).Code())
+
+	// nothing is limited without the rate limiter
+	appFacade.rateLimiter = nil
+	assert.NoError(t, appFacade.limitAppWrites(ctx, ns, "abc"))
+	assert.NoError(t, appFacade.limitAppReads(ctx, ns))
+}
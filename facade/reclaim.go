@@ -0,0 +1,243 @@
+package facade
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// ReclaimFunctionConfigs deletes the generated function configs of the namespace which are referenced
+// by no application, neither by the versions of the apps still live nor by the apps in the recycle bin, see
+// referencedFunctionConfigs. The configs created within the grace period are skipped to avoid racing with
+// the in-flight creations. If dryRun is set, the candidates are returned without being deleted.
+func (a *facade) ReclaimFunctionConfigs(ctx context.Context, ns string, dryRun bool) ([]string, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	candidates, err := a.listOrphanedFunctionConfigs(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	batch := a.conf.ConfigReclaim.BatchSize
+	if batch <= 0 {
+		batch = len(candidates)
+	}
+	deleted := make([]string, 0, len(candidates))
+	for start := 0; start < len(candidates); start += batch {
+		end := start + batch
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		names := candidates[start:end]
+		err = a.runTx(ctx, ns, "ReclaimFunctionConfigs", func(tx interface{}, _ *compensations) error {
+			for _, name := range names {
+				if err := ctx.Err(); err != nil {
+					return errors.Trace(err)
+				}
+				if err := a.deleteGenConfig(tx, ns, name); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, names...)
+		a.log.Info("reclaimed orphaned function configs", log.Any("namespace", ns), log.Any("names", names))
+	}
+	return deleted, nil
+}
+
+func (a *facade) listOrphanedFunctionConfigs(ctx context.Context, ns string) ([]string, error) {
+	cfgs, err := a.config.List(ns, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(-a.conf.ConfigReclaim.GracePeriod)
+	var candidates []string
+	for _, cfg := range cfgs.Items {
+		if !a.isFunctionConfig(cfg.Name) || cfg.CreationTimestamp.After(deadline) {
+			continue
+		}
+		candidates = append(candidates, cfg.Name)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	referenced, err := a.referencedFunctionConfigs(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := make([]string, 0, len(candidates))
+	for _, name := range candidates {
+		if !referenced[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans, nil
+}
+
+// referencedFunctionConfigs collects the configs referenced by the volumes of every version of the apps of the
+// namespace which is deployed or may be deployed again: the current versions of the apps, their older versions
+// still live (see liveAppVersions), and the apps kept in the recycle bin, which can be restored
+func (a *facade) referencedFunctionConfigs(ctx context.Context, ns string) (map[string]bool, error) {
+	referenced := map[string]bool{}
+	refer := func(app *specV1.Application) {
+		for _, v := range app.Volumes {
+			if v.VolumeSource.Config != nil {
+				referenced[v.VolumeSource.Config.Name] = true
+			}
+		}
+	}
+	// the volumes are only carried by the full app, so every app is fetched
+	apps, err := a.app.List(ns, &models.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range apps.Items {
+		if err = ctx.Err(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		app, err := a.app.Get(ns, item.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		refer(app)
+		versions, err := a.liveAppVersions(ns, app)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			old, err := a.app.Get(ns, app.Name, v)
+			if err != nil {
+				// the version pruned meanwhile references nothing any more
+				if isNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			refer(old)
+		}
+	}
+	if a.recycle == nil {
+		return referenced, nil
+	}
+	recycles, err := a.recycle.ListAppRecycle(ns)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, r := range recycles {
+		if r.App != nil {
+			refer(r.App)
+		}
+	}
+	return referenced, nil
+}
+
+// liveAppVersions returns the older versions of the app which are still deployed or may be deployed again:
+// the versions kept in the history by the retention or pinned, which the app can be rolled back to, and
+// while the activation of the app is pending, the versions desired by the nodes it's deployed to
+func (a *facade) liveAppVersions(ns string, app *specV1.Application) ([]string, error) {
+	versions := map[string]bool{}
+	if a.history != nil {
+		history, err := a.history.ListAppHistory(ns, app.Name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		_, kept := appVersionsToPrune(history, app.Version, a.conf.VersionRetention, time.Now())
+		for _, v := range kept {
+			versions[v] = true
+		}
+	}
+	if a.activation != nil {
+		activation, err := a.activation.GetAppActivation(ns, app.Name)
+		if err != nil && !isNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		if err == nil {
+			versions[activation.Version] = true
+			desired, err := a.desiredAppVersions(ns, app)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range desired {
+				versions[v] = true
+			}
+		}
+	}
+	delete(versions, app.Version)
+	res := make([]string, 0, len(versions))
+	for v := range versions {
+		res = append(res, v)
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// desiredAppVersions returns the versions of the app desired by the nodes it's deployed to by the node index
+func (a *facade) desiredAppVersions(ns string, app *specV1.Application) ([]string, error) {
+	nodes, err := a.index.ListNodesByApp(ns, app.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var res []string
+	for _, n := range nodes {
+		node, err := a.node.Get(nil, ns, n)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, info := range node.Desire.AppInfos(app.System) {
+			if info.Name == app.Name {
+				res = append(res, info.Version)
+			}
+		}
+	}
+	return res, nil
+}
+
+// functionConfigPrefixes returns the prefixes of the generated function configs and function program configs
+// of the instance, the default ones are used if not configured
+func (a *facade) functionConfigPrefixes() (string, string) {
+	prefix, programPrefix := a.conf.FunctionConfigPrefix, a.conf.FunctionProgramConfigPrefix
+	if prefix == "" {
+		prefix = FunctionConfigPrefix
+	}
+	if programPrefix == "" {
+		programPrefix = FunctionProgramConfigPrefix
+	}
+	return prefix, programPrefix
+}
+
+func (a *facade) isFunctionConfig(name string) bool {
+	prefix, programPrefix := a.functionConfigPrefixes()
+	return strings.HasPrefix(name, prefix) || strings.HasPrefix(name, programPrefix)
+}
+
+// validFunctionConfigPrefixes checks the prefixes of the generated function configs are non-empty and distinct
+func validFunctionConfigPrefixes(prefix, programPrefix string) error {
+	if strings.TrimSpace(prefix) == "" || strings.TrimSpace(programPrefix) == "" {
+		return errors.New("the prefixes of the function configs can't be empty")
+	}
+	if prefix == programPrefix {
+		return errors.New("the prefixes of the function configs and the function program configs must be distinct")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestReclaimFunctionConfigs(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		recycle:   mAppFacade.sRecycle,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{ConfigReclaim: config.ConfigReclaim{GracePeriod: time.Minute, BatchSize: 2}},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	old := time.Now().Add(-time.Hour)
+	cfgs := &models.ConfigurationList{
+		Items: []specV1.Configuration{
+			{Name: "baetyl-function-config-a", CreationTimestamp: old},
+			{Name: "baetyl-function-config-b", CreationTimestamp: old},
+			{Name: "baetyl-function-program-config-c", CreationTimestamp: old},
+			{Name: "baetyl-function-config-d", CreationTimestamp: old},
+			{Name: "baetyl-function-config-new", CreationTimestamp: time.Now()},
+			{Name: "cfg", CreationTimestamp: old},
+		},
+	}
+	apps := &models.ApplicationList{
+		Items: []models.AppItem{{Name: "app"}},
+	}
+	app := &specV1.Application{
+		Name: "app",
+		Volumes: []specV1.Volume{
+			{Name: "v", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-config-b"}}},
+		},
+	}
+	mAppFacade.sConfig.EXPECT().List(ns, gomock.Any()).Return(cfgs, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(apps, nil).AnyTimes()
+	mAppFacade.sApp.EXPECT().Get(ns, "app", "").Return(app, nil).AnyTimes()
+	mAppFacade.sRecycle.EXPECT().ListAppRecycle(ns).Return(nil, nil).AnyTimes()
+
+	// dry run
+	res, err := appFacade.ReclaimFunctionConfigs(context.Background(), ns, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"baetyl-function-config-a", "baetyl-function-config-d", "baetyl-function-program-config-c"}, res)
+
+	// the second batch fails
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, "baetyl-function-config-a").Return(nil)
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, "baetyl-function-config-d").Return(nil)
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, "baetyl-function-program-config-c").Return(unknownErr)
+	res, err = appFacade.ReclaimFunctionConfigs(context.Background(), ns, false)
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, []string{"baetyl-function-config-a", "baetyl-function-config-d"}, res)
+
+	// list failed
+	mAppFacade.sConfig.EXPECT().List("default", gomock.Any()).Return(nil, unknownErr)
+	_, err = appFacade.ReclaimFunctionConfigs(context.Background(), "default", false)
+	assert.Equal(t, unknownErr, err)
+}
+
+func TestReclaimFunctionConfigsLiveVersions(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:       mAppFacade.sNode,
+		app:        mAppFacade.sApp,
+		config:     mAppFacade.sConfig,
+		index:      mAppFacade.sIndex,
+		recycle:    mAppFacade.sRecycle,
+		history:    mAppFacade.sHistory,
+		activation: mAppFacade.sActivate,
+		conf: config.Facade{
+			ConfigReclaim:    config.ConfigReclaim{GracePeriod: time.Minute},
+			VersionRetention: config.VersionRetention{KeepLast: 2},
+		},
+		log: log.L(),
+	}
+	ns := "baetyl-cloud"
+	old := time.Now().Add(-time.Hour)
+	volume := func(cfg string) []specV1.Volume {
+		return []specV1.Volume{{Name: "v", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: cfg}}}}
+	}
+	cfgs := &models.ConfigurationList{}
+	for _, n := range []string{"cur", "retained", "pinned", "pruned", "activation", "desired", "recycle", "orphan"} {
+		cfgs.Items = append(cfgs.Items, specV1.Configuration{Name: "baetyl-function-config-" + n, CreationTimestamp: old})
+	}
+	mAppFacade.sConfig.EXPECT().List(ns, gomock.Any()).Return(cfgs, nil)
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "app"}}}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "app", "").Return(&specV1.Application{Name: "app", Version: "6", Volumes: volume("baetyl-function-config-cur")}, nil)
+
+	// the current version and the version before are retained, the oldest is pinned, the others are pruned
+	mAppFacade.sHistory.EXPECT().ListAppHistory(ns, "app").Return([]models.AppVersion{
+		{Version: "6", CreateTime: old}, {Version: "5", CreateTime: old}, {Version: "4", CreateTime: old},
+		{Version: "3", CreateTime: old}, {Version: "1", Pinned: true, CreateTime: old},
+	}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "app", "5").Return(&specV1.Application{Name: "app", Version: "5", Volumes: volume("baetyl-function-config-retained")}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "app", "1").Return(&specV1.Application{Name: "app", Version: "1", Volumes: volume("baetyl-function-config-pinned")}, nil)
+
+	// the activation of version 7 is pending, the nodes still desire version 2 which is pruned meanwhile, and version 4
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "app").Return(&models.AppActivation{Namespace: ns, Name: "app", Version: "7", ActivateAt: time.Now().Add(time.Hour)}, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "app").Return([]string{"n1", "n2", "n3"}, nil)
+	desire := func(version string) specV1.Desire {
+		d := specV1.Desire{}
+		d.SetAppInfos(false, []specV1.AppInfo{{Name: "app", Version: version}, {Name: "other", Version: "9"}})
+		return d
+	}
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Desire: desire("2")}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n2").Return(&specV1.Node{Name: "n2", Desire: desire("4")}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(nil, common.Error(common.ErrResourceNotFound))
+	mAppFacade.sApp.EXPECT().Get(ns, "app", "7").Return(&specV1.Application{Name: "app", Version: "7", Volumes: volume("baetyl-function-config-activation")}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "app", "2").Return(nil, common.Error(common.ErrResourceNotFound))
+	mAppFacade.sApp.EXPECT().Get(ns, "app", "4").Return(&specV1.Application{Name: "app", Version: "4", Volumes: volume("baetyl-function-config-desired")}, nil)
+
+	// the app deleted is kept in the recycle bin
+	mAppFacade.sRecycle.EXPECT().ListAppRecycle(ns).Return([]models.AppRecycle{
+		{Namespace: ns, Name: "deleted", Version: "3", App: &specV1.Application{Name: "deleted", Volumes: volume("baetyl-function-config-recycle")}},
+	}, nil)
+
+	res, err := appFacade.ReclaimFunctionConfigs(context.Background(), ns, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"baetyl-function-config-orphan", "baetyl-function-config-pruned"}, res)
+
+	// the recycle bin fails to be listed
+	mAppFacade.sConfig.EXPECT().List(ns, gomock.Any()).Return(cfgs, nil)
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{}, nil)
+	mAppFacade.sRecycle.EXPECT().ListAppRecycle(ns).Return(nil, unknownErr)
+	_, err = appFacade.ReclaimFunctionConfigs(context.Background(), ns, true)
+	assert.Error(t, err)
+
+	// the activation fails to be got
+	mAppFacade.sConfig.EXPECT().List(ns, gomock.Any()).Return(cfgs, nil)
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{Items: []models.AppItem{{Name: "app"}}}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "app", "").Return(&specV1.Application{Name: "app", Version: "6"}, nil)
+	mAppFacade.sHistory.EXPECT().ListAppHistory(ns, "app").Return(nil, nil)
+	mAppFacade.sActivate.EXPECT().GetAppActivation(ns, "app").Return(nil, unknownErr)
+	_, err = appFacade.ReclaimFunctionConfigs(context.Background(), ns, true)
+	assert.Error(t, err)
+}
+
+func TestFunctionConfigPrefixes(t *testing.T) {
+	a := &facade{}
+	assert.True(t, a.isFunctionConfig("baetyl-function-config-app-svc-xxx"))
+	assert.True(t, a.isFunctionConfig("baetyl-function-program-config-app-svc-xxx"))
+	assert.False(t, a.isFunctionConfig("fn-app-svc-xxx"))
+
+	a.conf = config.Facade{FunctionConfigPrefix: "fn", FunctionProgramConfigPrefix: "fnp"}
+	assert.True(t, a.isFunctionConfig("fn-app-svc-xxx"))
+	assert.True(t, a.isFunctionConfig("fnp-app-svc-xxx"))
+	assert.False(t, a.isFunctionConfig("baetyl-function-config-app-svc-xxx"))
+
+	assert.NoError(t, validFunctionConfigPrefixes(a.functionConfigPrefixes()))
+	assert.Error(t, validFunctionConfigPrefixes("fn", " "))
+	assert.Error(t, validFunctionConfigPrefixes("fn", "fn"))
+}
@@ -0,0 +1,154 @@
+package facade
+
+import (
+	"context"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// the reasons why the node index of the app isn't compared with its selector
+const (
+	// ReconcileSkipExternal the node bindings of the app are managed externally
+	ReconcileSkipExternal = "external"
+	// ReconcileSkipCronWait the app isn't deployed until its cron fires
+	ReconcileSkipCronWait = "cronWait"
+)
+
+// AppReconcileStatus the desired state of the app in the node index and the actual state reported by the nodes
+type AppReconcileStatus struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Skipped the reason the node index isn't compared with the selector, empty if it's compared
+	Skipped string `json:"skipped,omitempty"`
+	// InSync the nodes indexed are the nodes resolved from the selector, i.e. no stale or missing node
+	InSync       bool     `json:"inSync"`
+	StaleNodes   []string `json:"staleNodes"`
+	MissingNodes []string `json:"missingNodes"`
+	// Converged all the nodes indexed report the current version running
+	Converged bool              `json:"converged"`
+	Rollout   *AppRolloutStatus `json:"rollout"`
+}
+
+// NamespaceReconcileReport the desired and the actual states of all the apps of a namespace
+type NamespaceReconcileReport struct {
+	Namespace string `json:"namespace"`
+	Apps      int    `json:"apps"`
+	// OutOfSync the apps whose node indexes don't match their selectors
+	OutOfSync int `json:"outOfSync"`
+	// Unconverged the apps with the nodes indexed which don't report the current version running
+	Unconverged int                  `json:"unconverged"`
+	Items       []AppReconcileStatus `json:"items"`
+}
+
+// ReconcileReport walks the apps of the namespace page by page, and reports for each app whether its node index
+// matches the nodes resolved from its selector (desired) and whether the nodes indexed report its current version
+// running (actual), so that the drift of the index and the edges not converged are audited at once. It's read-only.
+func (a *facade) ReconcileReport(ctx context.Context, ns string) (*NamespaceReconcileReport, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	report := &NamespaceReconcileReport{Namespace: ns, Items: []AppReconcileStatus{}}
+	opt := &models.ListOptions{Limit: int64(a.conf.IndexPageSize)}
+	for {
+		apps, err := a.app.List(ns, opt)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// the nodes are shared by the apps of the same page
+		nodes := map[string]*specV1.Node{}
+		getNode := func(n string) (*specV1.Node, error) {
+			if node, ok := nodes[n]; ok {
+				return node, nil
+			}
+			node, err := a.node.Get(nil, ns, n)
+			if err != nil && !isNotFound(err) {
+				return nil, err
+			}
+			nodes[n] = node
+			return node, nil
+		}
+		for _, item := range apps.Items {
+			if err = ctx.Err(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			status, err := a.reconcileStatusOfApp(ctx, ns, item, getNode)
+			if err != nil {
+				return nil, err
+			}
+			report.Apps++
+			if !status.InSync {
+				report.OutOfSync++
+			}
+			if !status.Converged {
+				report.Unconverged++
+			}
+			report.Items = append(report.Items, *status)
+		}
+		if apps.ListOptions == nil || apps.Continue == "" {
+			break
+		}
+		opt.Continue = apps.Continue
+	}
+	if report.OutOfSync > 0 || report.Unconverged > 0 {
+		a.log.Info("the apps drift from the desired state", log.Any("namespace", ns),
+			log.Any("outOfSync", report.OutOfSync), log.Any("unconverged", report.Unconverged))
+	}
+	return report, nil
+}
+
+// reconcileStatusOfApp compares the nodes indexed by the app with the nodes resolved from its selector,
+// and aggregates the status of its current version reported by the nodes indexed
+func (a *facade) reconcileStatusOfApp(ctx context.Context, ns string, item models.AppItem, getNode func(string) (*specV1.Node, error)) (*AppReconcileStatus, error) {
+	status := &AppReconcileStatus{
+		Name:         item.Name,
+		Version:      item.Version,
+		InSync:       true,
+		StaleNodes:   []string{},
+		MissingNodes: []string{},
+	}
+	indexed, err := a.index.ListNodesByApp(ns, item.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(indexed)
+	switch {
+	case item.Labels[common.LabelSkipNodeIndex] == "true":
+		status.Skipped = ReconcileSkipExternal
+	case item.CronStatus == specV1.CronWait:
+		status.Skipped = ReconcileSkipCronWait
+	default:
+		desired, err := a.desiredNodesOfApp(ctx, ns, item)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(desired)
+		status.StaleNodes = append(status.StaleNodes, subtract(indexed, desired)...)
+		status.MissingNodes = append(status.MissingNodes, subtract(desired, indexed)...)
+		status.InSync = len(status.StaleNodes) == 0 && len(status.MissingNodes) == 0
+	}
+	status.Rollout, err = a.appRolloutStatus(ctx, ns, appOfItem(item), indexed, getNode)
+	if err != nil {
+		return nil, err
+	}
+	status.Converged = status.Rollout.Applied == status.Rollout.Total
+	return status, nil
+}
+
+// desiredNodesOfApp resolves the selector of the app to the nodes, by the resolver of its cron if labeled
+func (a *facade) desiredNodesOfApp(ctx context.Context, ns string, item models.AppItem) ([]string, error) {
+	if resolver := item.Labels[common.LabelCronSelectorResolver]; resolver != "" && resolver != SelectorResolverLabel {
+		return a.resolveCronNodes(ctx, &models.Cron{
+			Name:             item.Name,
+			Namespace:        ns,
+			Selector:         item.Selector,
+			SelectorResolver: resolver,
+		})
+	}
+	return a.node.MatchNodes(nil, ns, item.Selector)
+}
@@ -0,0 +1,99 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestReconcileReport(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:  mAppFacade.sNode,
+		app:   mAppFacade.sApp,
+		index: mAppFacade.sIndex,
+		cron:  mAppFacade.sCron,
+		log:   log.L(),
+	}
+	appFacade.conf.IndexPageSize = 2
+	ns := "baetyl-cloud"
+	report := func(name, version string, status specV1.Status) specV1.Report {
+		r := specV1.Report{}
+		r.SetAppStats(false, []specV1.AppStats{{AppInfo: specV1.AppInfo{Name: name, Version: version}, Status: status, Cause: "oops"}})
+		return r
+	}
+	app1 := models.AppItem{Name: "app1", Namespace: ns, Version: "2", Selector: "a=b"}
+	app2 := models.AppItem{Name: "app2", Namespace: ns, Version: "3", Selector: "a=c"}
+	app3 := models.AppItem{Name: "app3", Namespace: ns, Version: "1", Selector: "a=d",
+		Labels: map[string]string{common.LabelSkipNodeIndex: "true"}}
+	app4 := models.AppItem{Name: "app4", Namespace: ns, Version: "1", Selector: "a=e", CronStatus: specV1.CronWait}
+
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{Limit: 2}).Return(&models.ApplicationList{
+		Items: []models.AppItem{app1, app2}, ListOptions: &models.ListOptions{Continue: "c1"}}, nil)
+	mAppFacade.sApp.EXPECT().List(ns, &models.ListOptions{Limit: 2, Continue: "c1"}).Return(&models.ApplicationList{
+		Items: []models.AppItem{app3, app4}, ListOptions: &models.ListOptions{}}, nil)
+
+	// app1 is in sync and converged
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "app1").Return([]string{"n2", "n1"}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1", "n2"}, nil)
+	// the nodes are got once by the apps of the same page
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Report: report("app1", "2", specV1.Running)}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n2").Return(&specV1.Node{Name: "n2", Report: report("app1", "2", specV1.Running)}, nil)
+	// app2 has a stale node and a missing node, the node indexed reports an old version
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "app2").Return([]string{"n1", "n3"}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=c").Return([]string{"n1", "n4"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(nil, common.Error(common.ErrResourceNotFound))
+	// app3 is managed externally and reports failed
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "app3").Return([]string{"n5"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n5").Return(&specV1.Node{Name: "n5", Report: report("app3", "1", specV1.Failed)}, nil)
+	// app4 waits for its cron
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "app4").Return([]string{}, nil)
+
+	res, err := appFacade.ReconcileReport(context.Background(), ns)
+	assert.NoError(t, err)
+	assert.Equal(t, ns, res.Namespace)
+	assert.Equal(t, 4, res.Apps)
+	assert.Equal(t, 1, res.OutOfSync)
+	assert.Equal(t, 2, res.Unconverged)
+	assert.Len(t, res.Items, 4)
+
+	assert.Equal(t, "app1", res.Items[0].Name)
+	assert.True(t, res.Items[0].InSync)
+	assert.True(t, res.Items[0].Converged)
+	assert.Equal(t, 2, res.Items[0].Rollout.Applied)
+
+	assert.False(t, res.Items[1].InSync)
+	assert.Equal(t, []string{"n3"}, res.Items[1].StaleNodes)
+	assert.Equal(t, []string{"n4"}, res.Items[1].MissingNodes)
+	assert.False(t, res.Items[1].Converged)
+	assert.Equal(t, 2, res.Items[1].Rollout.Pending)
+
+	assert.Equal(t, ReconcileSkipExternal, res.Items[2].Skipped)
+	assert.True(t, res.Items[2].InSync)
+	assert.False(t, res.Items[2].Converged)
+	assert.Equal(t, map[string]string{"n5": "oops"}, res.Items[2].Rollout.Failures)
+
+	assert.Equal(t, ReconcileSkipCronWait, res.Items[3].Skipped)
+	assert.True(t, res.Items[3].InSync)
+	assert.True(t, res.Items[3].Converged)
+
+	// the listing fails
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(nil, unknownErr)
+	_, err = appFacade.ReconcileReport(context.Background(), ns)
+	assert.Error(t, err)
+
+	// the selector fails to be resolved
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(&models.ApplicationList{Items: []models.AppItem{app1}}, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "app1").Return([]string{"n1"}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nil, unknownErr)
+	_, err = appFacade.ReconcileReport(context.Background(), ns)
+	assert.Error(t, err)
+}
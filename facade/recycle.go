@@ -0,0 +1,155 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// softDeleteApp deletes the app and removes it from the nodes as deleteApp does, but the spec is kept
+// in the recycle bin and the generated function configs are kept until the app is purged.
+// The cron is deleted last as deleteApp does.
+func (a *facade) softDeleteApp(ctx context.Context, tx interface{}, ns, name string, app *specV1.Application, undo *compensations) ([]string, error) {
+	if err := a.checkAppFrozen(tx, ns, name); err != nil {
+		return nil, err
+	}
+	preserved := *app
+	if app.CronStatus == specV1.CronWait {
+		// the selector of the app waiting for cron is kept by the cron
+		cronApp, err := a.cron.GetCron(tx, name, ns)
+		if err == nil {
+			preserved.Selector = cronApp.Selector
+		} else if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	err := a.app.Delete(tx, ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if err = a.createAppAudit(ctx, tx, models.AppDeleted, ns, name, app.Version, ""); err != nil {
+		return nil, err
+	}
+
+	// the app deleted before with the same name is replaced, its configs kept while it was in the recycle bin
+	// are deleted by ReclaimFunctionConfigs once referenced by no app any more
+	if err = a.recycle.DeleteAppRecycle(tx, ns, name); err != nil {
+		return nil, err
+	}
+	err = a.recycle.CreateAppRecycle(tx, &models.AppRecycle{
+		Namespace:  ns,
+		Name:       name,
+		Version:    app.Version,
+		App:        &preserved,
+		DeleteTime: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var nodes []string
+	err = traceStep(ctx, "RefreshIndex", func() (err error) {
+		nodes, err = a.deleteNodeAndAppIndex(tx, ns, app, undo)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = a.writeAppOutbox(tx, models.AppDeleted, ns, app, nodes); err != nil {
+		return nil, err
+	}
+
+	if err = a.deleteCronOfApp(ctx, tx, ns, app); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// RestoreApp re-creates the soft deleted app from the spec kept in the recycle bin
+// and deploys it to the nodes matched by its selector
+func (a *facade) RestoreApp(ctx context.Context, ns, name string) (res *specV1.Application, err error) {
+	defer observeCall(ns, "RestoreApp", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	recycle, err := a.recycle.GetAppRecycle(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	app := recycle.App
+	// restored as a new resource
+	app.Version = ""
+
+	unlock, err := a.lockAppQuota(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var nodes []string
+	origin := *app
+	err = a.runTx(ctx, ns, "RestoreApp", func(tx interface{}, undo *compensations) error {
+		*app = origin
+		if err := a.checkAppQuota(ns, 1); err != nil {
+			return err
+		}
+		if err := a.checkAppRefs(ns, app, nil); err != nil {
+			return err
+		}
+		var err error
+		if res, nodes, err = a.createApp(ctx, tx, ns, nil, app, nil, undo); err != nil {
+			return err
+		}
+		return a.recycle.DeleteAppRecycle(tx, ns, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.publishAppEvent(ctx, models.AppCreated, ns, res, nodes)
+	return res, nil
+}
+
+// PurgeDeletedApps hard deletes the apps kept in the recycle bin longer than the retention
+// with their generated function configs and secrets. All the apps are tried and the first failure is returned.
+func (a *facade) PurgeDeletedApps(ctx context.Context) error {
+	recycles, err := a.recycle.ListExpiredAppRecycle(time.Now().Add(-a.conf.SoftDelete.Retention))
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for i := range recycles {
+		if err = ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		r := &recycles[i]
+		err = a.runTx(ctx, r.Namespace, "PurgeDeletedApps", func(tx interface{}, _ *compensations) error {
+			if err := a.cleanGenConfigsOfFunctionApp(tx, nil, r.App, nil); err != nil && a.conf.StrictConfigClean {
+				return err
+			}
+			return a.recycle.DeleteAppRecycle(tx, r.Namespace, r.Name)
+		})
+		if err != nil {
+			a.log.Error("failed to purge deleted app",
+				log.Any(common.KeyContextNamespace, r.Namespace),
+				log.Any("name", r.Name),
+				log.Error(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		a.cleanGenSecretsOfApp(r.Namespace, r.App)
+		a.log.Info("purged deleted app", log.Any(common.KeyContextNamespace, r.Namespace), log.Any("name", r.Name))
+	}
+	return firstErr
+}
@@ -0,0 +1,166 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestSoftDeleteApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		recycle:   mAppFacade.sRecycle,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{SoftDelete: config.SoftDelete{Enabled: true}},
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{
+		Namespace:  ns,
+		Name:       "abc",
+		Version:    "1",
+		CronStatus: specV1.CronWait,
+		CronTime:   time.Now().Add(time.Hour),
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-config-abc"}}},
+		},
+	}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(&specV1.Application{Name: "abc", Version: "1"}, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), "abc", ns).Return(&models.Cron{Name: "abc", Namespace: ns, Selector: "a=b"}, nil)
+	mAppFacade.sCron.EXPECT().DeleteCron("abc", ns).Return(nil)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "abc", "").Return(nil)
+	mAppFacade.sRecycle.EXPECT().DeleteAppRecycle(nil, ns, "abc").Return(nil)
+	mAppFacade.sRecycle.EXPECT().CreateAppRecycle(nil, gomock.Any()).DoAndReturn(func(_ interface{}, r *models.AppRecycle) error {
+		assert.Equal(t, ns, r.Namespace)
+		assert.Equal(t, "abc", r.Name)
+		assert.Equal(t, "1", r.Version)
+		assert.Equal(t, "a=b", r.App.Selector)
+		assert.False(t, r.DeleteTime.IsZero())
+		return nil
+	})
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	// the configs are kept until purged
+	mAppFacade.sConfig.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	err := appFacade.DeleteApp(context.Background(), ns, "abc", app)
+	assert.NoError(t, err)
+}
+
+func TestRestoreApp(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		recycle:   mAppFacade.sRecycle,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	// not in the recycle bin
+	notFound := common.Error(common.ErrResourceNotFound, common.Field("type", "appRecycle"), common.Field("name", "abc"))
+	mAppFacade.sRecycle.EXPECT().GetAppRecycle(ns, "abc").Return(nil, notFound)
+	_, err := appFacade.RestoreApp(context.Background(), ns, "abc")
+	assert.Equal(t, notFound, err)
+
+	// restored
+	preserved := &specV1.Application{Namespace: ns, Name: "abc", Version: "1", Selector: "a=b"}
+	restored := &specV1.Application{Namespace: ns, Name: "abc", Version: "2", Selector: "a=b"}
+	mAppFacade.sRecycle.EXPECT().GetAppRecycle(ns, "abc").Return(&models.AppRecycle{Namespace: ns, Name: "abc", Version: "1", App: preserved}, nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).DoAndReturn(
+		func(_ interface{}, _ string, app, _ *specV1.Application) (*specV1.Application, error) {
+			assert.Equal(t, "", app.Version)
+			return restored, nil
+		})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, restored).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	mAppFacade.sRecycle.EXPECT().DeleteAppRecycle(nil, ns, "abc").Return(nil)
+	res, err := appFacade.RestoreApp(context.Background(), ns, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, restored, res)
+
+	// rolled back if the recycle is failed to be deleted
+	preserved = &specV1.Application{Namespace: ns, Name: "abc", Version: "1", Selector: "a=b"}
+	mAppFacade.sRecycle.EXPECT().GetAppRecycle(ns, "abc").Return(&models.AppRecycle{Namespace: ns, Name: "abc", App: preserved}, nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).Return(restored, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, restored).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	mAppFacade.sRecycle.EXPECT().DeleteAppRecycle(nil, ns, "abc").Return(unknownErr)
+	_, err = appFacade.RestoreApp(context.Background(), ns, "abc")
+	assert.Equal(t, unknownErr, err)
+
+	// the config referenced is missing, nothing is created and the app is kept in the recycle bin
+	preserved = &specV1.Application{Namespace: ns, Name: "abc", Version: "1", Selector: "a=b",
+		Volumes: []specV1.Volume{{Name: "v", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "cfg"}}}}}
+	mAppFacade.sRecycle.EXPECT().GetAppRecycle(ns, "abc").Return(&models.AppRecycle{Namespace: ns, Name: "abc", App: preserved}, nil)
+	mAppFacade.sConfig.EXPECT().Get(ns, "cfg", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	_, err = appFacade.RestoreApp(context.Background(), ns, "abc")
+	assert.Equal(t, common.ErrMissingConfigRef, err.(errors.Coder).Code())
+}
+
+func TestPurgeDeletedApps(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		config:    mAppFacade.sConfig,
+		recycle:   mAppFacade.sRecycle,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{SoftDelete: config.SoftDelete{Enabled: true, Retention: time.Hour}},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	app1 := &specV1.Application{
+		Namespace: ns,
+		Name:      "app1",
+		Volumes: []specV1.Volume{
+			{Name: "cfg", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "baetyl-function-config-app1"}}},
+			{Name: "user", VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: "user-config"}}},
+		},
+	}
+	app2 := &specV1.Application{Namespace: ns, Name: "app2"}
+	mAppFacade.sRecycle.EXPECT().ListExpiredAppRecycle(gomock.Any()).DoAndReturn(func(before time.Time) ([]models.AppRecycle, error) {
+		assert.True(t, before.Before(time.Now().Add(-time.Minute*59)))
+		return []models.AppRecycle{
+			{Namespace: ns, Name: "app1", App: app1},
+			{Namespace: ns, Name: "app2", App: app2},
+		}, nil
+	})
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "baetyl-function-config-app1").Return(false, nil, nil)
+	mAppFacade.sConfig.EXPECT().Delete(nil, ns, "baetyl-function-config-app1").Return(nil)
+	mAppFacade.sRecycle.EXPECT().DeleteAppRecycle(nil, ns, "app1").Return(unknownErr)
+	mAppFacade.sRecycle.EXPECT().DeleteAppRecycle(nil, ns, "app2").Return(nil)
+	err := appFacade.PurgeDeletedApps(context.Background())
+	assert.Equal(t, unknownErr, err)
+
+	mAppFacade.sRecycle.EXPECT().ListExpiredAppRecycle(gomock.Any()).Return(nil, unknownErr)
+	err = appFacade.PurgeDeletedApps(context.Background())
+	assert.Equal(t, unknownErr, err)
+}
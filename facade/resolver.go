@@ -0,0 +1,176 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// the built-in resolvers of the cron selectors
+const (
+	// SelectorResolverLabel resolves the selector as the label selector of the nodes, it's the default
+	SelectorResolverLabel = "label"
+	// SelectorResolverApp resolves the selector as the name of an app to the nodes already running the app
+	SelectorResolverApp = "app"
+)
+
+// SelectorResolver resolves the query of the selector of a cron app to the nodes of the namespace,
+// it's evaluated each time the cron fires so that the cron follows the evolving nodes
+type SelectorResolver interface {
+	Resolve(ctx context.Context, ns, query string) ([]string, error)
+}
+
+// SelectorResolverFunc adapts the func to the SelectorResolver
+type SelectorResolverFunc func(ctx context.Context, ns, query string) ([]string, error)
+
+func (f SelectorResolverFunc) Resolve(ctx context.Context, ns, query string) ([]string, error) {
+	return f(ctx, ns, query)
+}
+
+// selectorResolvers holds the resolvers set to the facade besides the built-in ones
+type selectorResolvers struct {
+	mu        sync.RWMutex
+	resolvers map[string]SelectorResolver
+}
+
+func (r *selectorResolvers) set(name string, resolver SelectorResolver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if resolver == nil {
+		delete(r.resolvers, name)
+		return
+	}
+	if r.resolvers == nil {
+		r.resolvers = map[string]SelectorResolver{}
+	}
+	r.resolvers[name] = resolver
+}
+
+func (r *selectorResolvers) get(name string) SelectorResolver {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.resolvers[name]
+}
+
+// SetSelectorResolver sets the resolver of the cron selectors labeled with the name, nil removes it.
+// The names of the built-in resolvers are reserved.
+func (a *facade) SetSelectorResolver(name string, resolver SelectorResolver) error {
+	switch name {
+	case "", SelectorResolverLabel, SelectorResolverApp:
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the name (%s) of the selector resolver is reserved", name)))
+	}
+	a.resolvers.set(name, resolver)
+	return nil
+}
+
+// ResolveCronSelector returns the nodes which the cron of the app would deploy it to if fired now,
+// the selector of the cron is resolved by its resolver without any write
+func (a *facade) ResolveCronSelector(ctx context.Context, ns, name string) ([]string, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	cronApp, err := a.cron.GetCron(nil, name, ns)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return a.resolveCronNodes(ctx, cronApp)
+}
+
+// selectorResolver returns the resolver of the cron selector, ErrInvalidCron is returned if it's unknown
+func (a *facade) selectorResolver(cronApp *models.Cron) (SelectorResolver, error) {
+	switch cronApp.SelectorResolver {
+	case "", SelectorResolverLabel:
+		return SelectorResolverFunc(a.ResolveSelector), nil
+	case SelectorResolverApp:
+		return SelectorResolverFunc(a.resolveAppNodes), nil
+	}
+	if resolver := a.resolvers.get(cronApp.SelectorResolver); resolver != nil {
+		return resolver, nil
+	}
+	return nil, common.Error(common.ErrInvalidCron,
+		common.Field("name", cronApp.Name),
+		common.Field("error", fmt.Sprintf("the selector resolver (%s) is unknown", cronApp.SelectorResolver)))
+}
+
+// resolveCronNodes resolves the selector of the cron to the sorted distinct nodes
+func (a *facade) resolveCronNodes(ctx context.Context, cronApp *models.Cron) ([]string, error) {
+	resolver, err := a.selectorResolver(cronApp)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := resolver.Resolve(ctx, cronApp.Namespace, cronApp.Selector)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	res := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if !seen[n] {
+			seen[n] = true
+			res = append(res, n)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// resolveAppNodes returns the nodes the app of the query is deployed to which report it running
+func (a *facade) resolveAppNodes(ctx context.Context, ns, query string) ([]string, error) {
+	app, err := a.app.Get(ns, query, "")
+	if err != nil {
+		return nil, err
+	}
+	indexed, err := a.index.ListNodesByApp(ns, app.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var nodes []string
+	for _, n := range indexed {
+		if err = ctx.Err(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		node, err := a.node.Get(nil, ns, n)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if stats := reportedAppStats(node, app); stats != nil && stats.Status == specV1.Running {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
+
+// updateResolvedNodeAndAppIndex deploys the app to the nodes resolved from the selector of its cron, and removes
+// it from the nodes indexed before but not resolved any more, so that the app follows the evolving nodes
+func (a *facade) updateResolvedNodeAndAppIndex(tx interface{}, ns string, app *specV1.Application, nodes []string, undo *compensations) error {
+	indexed, err := a.index.ListNodesByApp(ns, app.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if stale := subtract(indexed, nodes); len(stale) > 0 {
+		if err = a.node.UpdateDesire(tx, ns, stale, app, service.DeleteNodeDesireByApp); err != nil {
+			return err
+		}
+	}
+	if len(nodes) > 0 {
+		if err = a.node.UpdateDesire(tx, ns, nodes, app, service.RefreshNodeDesireByApp); err != nil {
+			return err
+		}
+	}
+	return a.refreshNodesIndexByApp(tx, ns, app, nodes, undo)
+}
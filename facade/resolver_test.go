@@ -0,0 +1,118 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestResolveCronSelector(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:  mAppFacade.sNode,
+		app:   mAppFacade.sApp,
+		index: mAppFacade.sIndex,
+		cron:  mAppFacade.sCron,
+		log:   log.L(),
+	}
+	ns, name := "baetyl-cloud", "abc"
+	cronApp := func(resolver, selector string) *models.Cron {
+		return &models.Cron{Name: name, Namespace: ns, Selector: selector, SelectorResolver: resolver}
+	}
+	report := func(status specV1.Status) specV1.Report {
+		r := specV1.Report{}
+		r.SetAppStats(false, []specV1.AppStats{{AppInfo: specV1.AppInfo{Name: "x", Version: "1"}, Status: status}})
+		return r
+	}
+
+	// the label selector by default
+	mAppFacade.sCron.EXPECT().GetCron(nil, name, ns).Return(cronApp("", "a=b"), nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n2", "n1"}, nil)
+	nodes, err := appFacade.ResolveCronSelector(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2"}, nodes)
+
+	// the nodes running the app
+	mAppFacade.sCron.EXPECT().GetCron(nil, name, ns).Return(cronApp(SelectorResolverApp, "x"), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "x", "").Return(&specV1.Application{Namespace: ns, Name: "x", Version: "1"}, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, "x").Return([]string{"n1", "n2", "n3", "n4"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Report: report(specV1.Running)}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n2").Return(&specV1.Node{Name: "n2", Report: report(specV1.Failed)}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(&specV1.Node{Name: "n3"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n4").Return(nil, common.Error(common.ErrResourceNotFound))
+	nodes, err = appFacade.ResolveCronSelector(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1"}, nodes)
+
+	// the resolver set to the facade
+	err = appFacade.SetSelectorResolver(SelectorResolverApp, nil)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+	err = appFacade.SetSelectorResolver("group", SelectorResolverFunc(func(_ context.Context, ns, query string) ([]string, error) {
+		assert.Equal(t, "g1", query)
+		return []string{"n3", "n1", "n3"}, nil
+	}))
+	assert.NoError(t, err)
+	mAppFacade.sCron.EXPECT().GetCron(nil, name, ns).Return(cronApp("group", "g1"), nil)
+	nodes, err = appFacade.ResolveCronSelector(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n3"}, nodes)
+
+	// the resolver removed is unknown
+	assert.NoError(t, appFacade.SetSelectorResolver("group", nil))
+	mAppFacade.sCron.EXPECT().GetCron(nil, name, ns).Return(cronApp("group", "g1"), nil)
+	_, err = appFacade.ResolveCronSelector(context.Background(), ns, name)
+	assert.Equal(t, common.ErrInvalidCron, err.(errors.Coder).Code())
+}
+
+func TestTriggerCronAppResolved(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns, name := "baetyl-cloud", "abc"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	assert.NoError(t, appFacade.SetSelectorResolver("group", SelectorResolverFunc(func(_ context.Context, _, _ string) ([]string, error) {
+		return []string{"n2", "n3"}, nil
+	})))
+
+	// the app follows the nodes resolved, and is removed from the nodes not resolved any more
+	app := &specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "g1", SelectorResolver: "group"}, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(app, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, name).Return([]string{"n1", "n2"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1"}, app, gomock.Any()).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n2", "n3"}, app, gomock.Any()).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, []string{"n2", "n3"}).Return(nil)
+	res, nodes, err := appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n2", "n3"}, nodes)
+	assert.Equal(t, "g1", res.Selector)
+
+	// the unknown resolver fails the run
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name, CronStatus: specV1.CronWait}, nil)
+	mAppFacade.sCron.EXPECT().GetCron(gomock.Any(), name, ns).Return(&models.Cron{Name: name, Namespace: ns, Selector: "g1", SelectorResolver: "unknown"}, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			return app, nil
+		})
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	_, _, err = appFacade.TriggerCronApp(context.Background(), ns, name)
+	assert.Equal(t, common.ErrInvalidCron, err.(errors.Coder).Code())
+}
@@ -0,0 +1,225 @@
+package facade
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// pruneTimeout bounds the pruning in background, including the wait for the lock of the app
+const pruneTimeout = time.Minute
+
+// PinAppVersion pins the version of the app kept in the history, so that it's never pruned
+func (a *facade) PinAppVersion(ctx context.Context, ns, name, version string) error {
+	if err := a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	return a.setAppVersionPinned(ctx, ns, name, version, true)
+}
+
+// UnpinAppVersion unpins the version of the app, which is pruned once out of the retention
+func (a *facade) UnpinAppVersion(ctx context.Context, ns, name, version string) error {
+	if err := a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	return a.setAppVersionPinned(ctx, ns, name, version, false)
+}
+
+// ListPinnedAppVersions lists the pinned versions of the app from the latest
+func (a *facade) ListPinnedAppVersions(ctx context.Context, ns, name string) ([]models.AppVersion, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := a.checkAppHistory(); err != nil {
+		return nil, err
+	}
+	versions, err := a.history.ListAppHistory(ns, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res := []models.AppVersion{}
+	for _, v := range versions {
+		if v.Pinned {
+			res = append(res, v)
+		}
+	}
+	return res, nil
+}
+
+func (a *facade) setAppVersionPinned(ctx context.Context, ns, name, version string, pinned bool) error {
+	if err := a.checkAppHistory(); err != nil {
+		return err
+	}
+	// the pruning is serialized with the pin by the lock of the app, so the version pinned is never pruned
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if _, err = a.findAppVersion(ns, name, version); err != nil {
+		return err
+	}
+	return errors.Trace(a.history.UpdateAppHistoryPinned(ns, name, version, pinned))
+}
+
+// findAppVersion finds the version of the app in the history, ErrResourceNotFound is returned if it's pruned
+func (a *facade) findAppVersion(ns, name, version string) (*models.AppVersion, error) {
+	versions, err := a.history.ListAppHistory(ns, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for i := range versions {
+		if versions[i].Version == version {
+			return &versions[i], nil
+		}
+	}
+	return nil, common.Error(common.ErrResourceNotFound,
+		common.Field("type", "app version"),
+		common.Field("name", name+"@"+version),
+		common.Field("namespace", ns))
+}
+
+// latestPinnedAppVersion returns the latest pinned version of the app, ErrResourceNotFound is returned if none
+func (a *facade) latestPinnedAppVersion(ctx context.Context, ns, name string) (string, error) {
+	pinned, err := a.ListPinnedAppVersions(ctx, ns, name)
+	if err != nil {
+		return "", err
+	}
+	if len(pinned) == 0 {
+		return "", common.Error(common.ErrResourceNotFound,
+			common.Field("type", "pinned app version"),
+			common.Field("name", name),
+			common.Field("namespace", ns))
+	}
+	return pinned[0].Version, nil
+}
+
+func (a *facade) checkAppHistory() error {
+	if a.history == nil {
+		return common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the history of the app versions is disabled"))
+	}
+	return nil
+}
+
+// pruneAppVersionsInBackground prunes the versions of the app after its update is committed,
+// the failure is logged only since the versions are pruned again by the next update
+func (a *facade) pruneAppVersionsInBackground(ns, name string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), pruneTimeout)
+		defer cancel()
+		pruned, err := a.pruneAppVersions(ctx, ns, name)
+		if err != nil {
+			a.log.Warn("failed to prune the versions of the app",
+				log.Any(common.KeyContextNamespace, ns), log.Any("name", name), log.Error(err))
+			return
+		}
+		if len(pruned) > 0 {
+			a.log.Info("the versions of the app are pruned",
+				log.Any(common.KeyContextNamespace, ns), log.Any("name", name), log.Any("versions", pruned))
+		}
+	}()
+}
+
+// pruneAppVersions deletes the versions of the app out of the retention from the history, with the generated
+// function configs referenced by them only. The current version, which is deployed, and the pinned versions are
+// never pruned, neither is any version while the app is in canary since the other nodes still desire the older one.
+func (a *facade) pruneAppVersions(ctx context.Context, ns, name string) ([]string, error) {
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	cur, err := a.app.Get(ns, name, "")
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := cur.Labels[common.LabelCanaryPercent]; ok {
+		return nil, nil
+	}
+	versions, err := a.history.ListAppHistory(ns, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pruned, kept := appVersionsToPrune(versions, cur.Version, a.conf.VersionRetention, time.Now())
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+
+	// the configs still referenced by the versions kept aren't exclusive to the versions pruned
+	refs := map[string]bool{}
+	for _, v := range append(kept, cur.Version) {
+		app := cur
+		if v != cur.Version {
+			if app, err = a.app.Get(ns, name, v); err != nil {
+				return nil, err
+			}
+		}
+		for _, vol := range app.Volumes {
+			if vol.VolumeSource.Config != nil {
+				refs[vol.VolumeSource.Config.Name] = true
+			}
+		}
+	}
+	var configs []string
+	for _, v := range pruned {
+		app, err := a.app.Get(ns, name, v)
+		if err != nil {
+			return nil, err
+		}
+		for _, cfg := range a.dryRunCleanGenConfigsOfFunctionApp(nil, app, nil) {
+			if refs[cfg] {
+				continue
+			}
+			refs[cfg] = true
+			others, err := a.listOtherAppsReferencingConfig(ns, cfg, name)
+			if err != nil {
+				return nil, err
+			}
+			if len(others) == 0 {
+				configs = append(configs, cfg)
+			}
+		}
+	}
+
+	err = a.runTx(ctx, ns, "PruneAppVersions", func(tx interface{}, _ *compensations) error {
+		for _, v := range pruned {
+			if err := a.history.DeleteAppHistory(tx, ns, name, v); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		for _, cfg := range configs {
+			// the configs are mostly cleaned by the update which orphaned them already
+			if err := a.deleteGenConfig(tx, ns, cfg); err != nil && !isNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// appVersionsToPrune splits the versions listed from the latest into the ones out of the retention and the others
+func appVersionsToPrune(versions []models.AppVersion, current string, policy config.VersionRetention, now time.Time) (pruned, kept []string) {
+	for i, v := range versions {
+		keep := v.Version == current || v.Pinned ||
+			(policy.KeepLast <= 0 && policy.MaxAge <= 0) ||
+			(policy.KeepLast > 0 && i < policy.KeepLast) ||
+			(policy.MaxAge > 0 && now.Sub(v.CreateTime) < policy.MaxAge)
+		if keep {
+			kept = append(kept, v.Version)
+		} else {
+			pruned = append(pruned, v.Version)
+		}
+	}
+	return
+}
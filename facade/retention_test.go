@@ -0,0 +1,211 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestAppVersionsToPrune(t *testing.T) {
+	now := time.Now()
+	versions := []models.AppVersion{
+		{Version: "5", CreateTime: now.Add(-time.Minute)},
+		{Version: "4", CreateTime: now.Add(-time.Hour)},
+		{Version: "3", CreateTime: now.Add(-2 * time.Hour), Pinned: true},
+		{Version: "2", CreateTime: now.Add(-3 * time.Hour)},
+		{Version: "1", CreateTime: now.Add(-4 * time.Hour)},
+	}
+	tests := []struct {
+		name    string
+		current string
+		policy  config.VersionRetention
+		pruned  []string
+		kept    []string
+	}{
+		{
+			name:    "keep last",
+			current: "5",
+			policy:  config.VersionRetention{KeepLast: 2},
+			pruned:  []string{"2", "1"},
+			kept:    []string{"5", "4", "3"},
+		},
+		{
+			name:    "max age",
+			current: "5",
+			policy:  config.VersionRetention{MaxAge: 30 * time.Minute},
+			pruned:  []string{"4", "2", "1"},
+			kept:    []string{"5", "3"},
+		},
+		{
+			name:    "kept by either policy",
+			current: "5",
+			policy:  config.VersionRetention{KeepLast: 1, MaxAge: 150 * time.Minute},
+			pruned:  []string{"2", "1"},
+			kept:    []string{"5", "4", "3"},
+		},
+		{
+			name:    "current version kept",
+			current: "1",
+			policy:  config.VersionRetention{KeepLast: 1},
+			pruned:  []string{"4", "2"},
+			kept:    []string{"5", "3", "1"},
+		},
+		{
+			name:    "no policy",
+			current: "5",
+			policy:  config.VersionRetention{},
+			kept:    []string{"5", "4", "3", "2", "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pruned, kept := appVersionsToPrune(versions, tt.current, tt.policy, now)
+			assert.Equal(t, tt.pruned, pruned)
+			assert.Equal(t, tt.kept, kept)
+		})
+	}
+}
+
+func TestPruneAppVersions(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		history:   mAppFacade.sHistory,
+		txFactory: mAppFacade.txFactory,
+		conf: config.Facade{
+			FunctionConfigPrefix:        "fn",
+			FunctionProgramConfigPrefix: "fnp",
+			VersionRetention:            config.VersionRetention{Enabled: true, KeepLast: 2},
+		},
+	}
+	ns, name := "baetyl-cloud", "abc"
+	appOf := func(version string, configs ...string) *specV1.Application {
+		app := &specV1.Application{Namespace: ns, Name: name, Version: version}
+		for _, cfg := range configs {
+			app.Volumes = append(app.Volumes, specV1.Volume{Name: cfg, VolumeSource: specV1.VolumeSource{Config: &specV1.ObjectReference{Name: cfg}}})
+		}
+		return app
+	}
+	versions := []models.AppVersion{{Version: "5"}, {Version: "4"}, {Version: "3", Pinned: true}, {Version: "2"}, {Version: "1"}}
+
+	// the versions out of the retention are pruned with the function configs referenced by them only
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(appOf("5", "fn-abc-5"), nil)
+	mAppFacade.sHistory.EXPECT().ListAppHistory(ns, name).Return(versions, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "4").Return(appOf("4", "fn-abc-4"), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "3").Return(appOf("3", "fn-abc-3"), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "2").Return(appOf("2", "fn-abc-2", "fn-abc-4", "fn-other"), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(appOf("1", "fn-abc-1", "fn-abc-2", "user-config"), nil)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "fn-abc-2").Return(false, nil, nil)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "fn-other").Return(true, []string{name, "other"}, nil)
+	mAppFacade.sConfig.EXPECT().IsReferenced(ns, "fn-abc-1").Return(true, []string{name}, nil)
+	gomock.InOrder(
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.sHistory.EXPECT().DeleteAppHistory(nil, ns, name, "2").Return(nil),
+		mAppFacade.sHistory.EXPECT().DeleteAppHistory(nil, ns, name, "1").Return(nil),
+		mAppFacade.sConfig.EXPECT().Delete(nil, ns, "fn-abc-2").Return(common.Error(common.ErrResourceNotFound)),
+		mAppFacade.sConfig.EXPECT().Delete(nil, ns, "fn-abc-1").Return(nil),
+		mAppFacade.txFactory.EXPECT().Commit(nil),
+	)
+	pruned, err := appFacade.pruneAppVersions(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "1"}, pruned)
+
+	// the failure rolls back the pruning
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(appOf("5"), nil)
+	mAppFacade.sHistory.EXPECT().ListAppHistory(ns, name).Return(versions, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "4").Return(appOf("4"), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "3").Return(appOf("3"), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "2").Return(appOf("2"), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(appOf("1"), nil)
+	gomock.InOrder(
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.sHistory.EXPECT().DeleteAppHistory(nil, ns, name, "2").Return(unknownErr),
+		mAppFacade.txFactory.EXPECT().Rollback(nil),
+	)
+	_, err = appFacade.pruneAppVersions(context.Background(), ns, name)
+	assert.Error(t, err)
+
+	// nothing is pruned while the app is in canary
+	canary := appOf("5")
+	canary.Labels = map[string]string{common.LabelCanaryPercent: "10"}
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(canary, nil)
+	pruned, err = appFacade.pruneAppVersions(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Empty(t, pruned)
+
+	// nothing is out of the retention
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(appOf("5"), nil)
+	mAppFacade.sHistory.EXPECT().ListAppHistory(ns, name).Return(versions[:2], nil)
+	pruned, err = appFacade.pruneAppVersions(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Empty(t, pruned)
+}
+
+func TestPinAppVersion(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		app:       mAppFacade.sApp,
+		history:   mAppFacade.sHistory,
+		locker:    mAppFacade.sLocker,
+		txFactory: mAppFacade.txFactory,
+	}
+	ctx := context.Background()
+	ns, name := "baetyl-cloud", "abc"
+	lock := appLockPrefix + ns + "/" + name
+	versions := []models.AppVersion{{Version: "3"}, {Version: "2", Pinned: true}, {Version: "1", Pinned: true}}
+	mAppFacade.sHistory.EXPECT().ListAppHistory(ns, name).Return(versions, nil).AnyTimes()
+
+	// the version is pinned under the lock of the app
+	gomock.InOrder(
+		mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v1", nil),
+		mAppFacade.sHistory.EXPECT().UpdateAppHistoryPinned(ns, name, "3", true).Return(nil),
+		mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v1"),
+	)
+	assert.NoError(t, appFacade.PinAppVersion(ctx, ns, name, "3"))
+
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v2", nil)
+	mAppFacade.sHistory.EXPECT().UpdateAppHistoryPinned(ns, name, "2", false).Return(unknownErr)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v2")
+	err := appFacade.UnpinAppVersion(ctx, ns, name, "2")
+	assert.Error(t, err)
+
+	// the version pruned can't be pinned
+	mAppFacade.sLocker.EXPECT().Lock(gomock.Any(), lock, int64(0)).Return("v3", nil)
+	mAppFacade.sLocker.EXPECT().Unlock(gomock.Any(), lock, "v3")
+	err = appFacade.PinAppVersion(ctx, ns, name, "0")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	pinned, err := appFacade.ListPinnedAppVersions(ctx, ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, versions[1:], pinned)
+
+	// the rollback defaults to the latest pinned version
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name, Version: "3"}, nil)
+	mAppFacade.sApp.EXPECT().Get(ns, name, "2").Return(nil, unknownErr)
+	_, err = appFacade.RollbackApp(ctx, ns, name, "")
+	assert.Equal(t, unknownErr, err)
+
+	// the version pruned can't be rolled back to
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Namespace: ns, Name: name, Version: "3"}, nil)
+	_, err = appFacade.RollbackApp(ctx, ns, name, "0")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	// the history is disabled
+	appFacade.history = nil
+	err = appFacade.PinAppVersion(ctx, ns, name, "3")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+	_, err = appFacade.ListPinnedAppVersions(ctx, ns, name)
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
@@ -0,0 +1,102 @@
+package facade
+
+import (
+	"context"
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// AppRolloutStatus the progress of the version of an application on the nodes it's deployed to
+type AppRolloutStatus struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   string `json:"version"`
+	// Total the nodes the app is deployed to by the node index
+	Total int `json:"total"`
+	// Pending the nodes which haven't reported the version running yet
+	Pending int `json:"pending"`
+	// Applied the nodes which report the version running
+	Applied int `json:"applied"`
+	// Failed the nodes which report the version failed
+	Failed int `json:"failed"`
+	// Failures the causes reported by the failed nodes
+	Failures map[string]string `json:"failures"`
+}
+
+// GetAppStatus aggregates the status of the version of the app reported by the nodes it's deployed to,
+// the current version is used if version is empty. The node which reports another version or hasn't
+// reported yet is pending.
+func (a *facade) GetAppStatus(ctx context.Context, ns, name, version string) (*AppRolloutStatus, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	app, err := a.app.Get(ns, name, version)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := a.index.ListNodesByApp(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	return a.appRolloutStatus(ctx, ns, app, nodes, func(n string) (*specV1.Node, error) {
+		return a.node.Get(nil, ns, n)
+	})
+}
+
+// appRolloutStatus aggregates the status of the version of the app reported by the nodes got by getNode
+func (a *facade) appRolloutStatus(ctx context.Context, ns string, app *specV1.Application, nodes []string, getNode func(string) (*specV1.Node, error)) (*AppRolloutStatus, error) {
+	sort.Strings(nodes)
+	status := &AppRolloutStatus{
+		Name:      app.Name,
+		Namespace: ns,
+		Version:   app.Version,
+		Total:     len(nodes),
+		Failures:  map[string]string{},
+	}
+	for _, n := range nodes {
+		if err := ctx.Err(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		node, err := getNode(n)
+		if err != nil {
+			// the node deleted is left in the index until it's refreshed
+			if e, ok := err.(errors.Coder); !ok || e.Code() != common.ErrResourceNotFound {
+				return nil, err
+			}
+			node = nil
+		}
+		stats := reportedAppStats(node, app)
+		switch {
+		case stats == nil || stats.Version != app.Version:
+			status.Pending++
+		case stats.Status == specV1.Running:
+			status.Applied++
+		case stats.Status == specV1.Failed:
+			status.Failed++
+			status.Failures[n] = stats.Cause
+		default:
+			status.Pending++
+		}
+	}
+	return status, nil
+}
+
+// reportedAppStats returns the stats of the app reported by the node, nil if not reported
+func reportedAppStats(node *specV1.Node, app *specV1.Application) *specV1.AppStats {
+	if node == nil || node.Report == nil {
+		return nil
+	}
+	for _, s := range node.Report.AppStats(app.System) {
+		if s.Name == app.Name {
+			return &s
+		}
+	}
+	return nil
+}
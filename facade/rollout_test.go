@@ -0,0 +1,66 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestGetAppStatus(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:  mAppFacade.sNode,
+		app:   mAppFacade.sApp,
+		index: mAppFacade.sIndex,
+	}
+	ns, name := "baetyl-cloud", "abc"
+	report := func(stats ...specV1.AppStats) specV1.Report {
+		r := specV1.Report{}
+		r.SetAppStats(false, stats)
+		return r
+	}
+	stats := func(version string, status specV1.Status, cause string) specV1.AppStats {
+		return specV1.AppStats{AppInfo: specV1.AppInfo{Name: name, Version: version}, Status: status, Cause: cause}
+	}
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, Version: "2"}, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, name).Return([]string{"n6", "n5", "n4", "n3", "n2", "n1"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Report: report(stats("2", specV1.Running, ""))}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n2").Return(&specV1.Node{Name: "n2", Report: report(stats("2", specV1.Failed, "image pull failed"))}, nil)
+	// the previous version is still running
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(&specV1.Node{Name: "n3", Report: report(stats("1", specV1.Running, ""))}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n4").Return(&specV1.Node{Name: "n4", Report: report(stats("2", specV1.Pending, ""))}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n5").Return(&specV1.Node{Name: "n5"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n6").Return(nil, common.Error(common.ErrResourceNotFound))
+	status, err := appFacade.GetAppStatus(context.Background(), ns, name, "")
+	assert.NoError(t, err)
+	assert.Equal(t, &AppRolloutStatus{
+		Name:      name,
+		Namespace: ns,
+		Version:   "2",
+		Total:     6,
+		Pending:   4,
+		Applied:   1,
+		Failed:    1,
+		Failures:  map[string]string{"n2": "image pull failed"},
+	}, status)
+
+	// the version asked
+	mAppFacade.sApp.EXPECT().Get(ns, name, "1").Return(&specV1.Application{Name: name, Version: "1"}, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, name).Return([]string{"n3"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(&specV1.Node{Name: "n3", Report: report(stats("1", specV1.Running, ""))}, nil)
+	status, err = appFacade.GetAppStatus(context.Background(), ns, name, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, status.Applied)
+
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, Version: "2"}, nil)
+	mAppFacade.sIndex.EXPECT().ListNodesByApp(ns, name).Return([]string{"n1"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(nil, unknownErr)
+	_, err = appFacade.GetAppStatus(context.Background(), ns, name, "")
+	assert.Equal(t, unknownErr, err)
+}
@@ -1,6 +1,7 @@
 package facade
 
 import (
+	"context"
 	"strings"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
@@ -9,48 +10,53 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/common"
 )
 
-func (a *facade) CreateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error) {
-	tx, errTx := a.txFactory.BeginTx()
-	if errTx != nil {
-		return nil, errTx
+func (a *facade) CreateSecret(ctx context.Context, ns string, secret *specV1.Secret) (*specV1.Secret, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
 	}
-	var err error
-	defer func() {
-		if p := recover(); p != nil {
-			a.txFactory.Rollback(tx)
-			panic(p)
-		} else if err != nil {
-			a.txFactory.Rollback(tx)
-		} else {
-			a.txFactory.Commit(tx)
-		}
-	}()
-	secret, err = a.secret.Create(tx, ns, secret)
-	return secret, err
+	var res *specV1.Secret
+	err := a.runTx(ctx, ns, "CreateSecret", func(tx interface{}, _ *compensations) error {
+		var err error
+		res, err = a.secret.Create(tx, ns, secret)
+		return err
+	})
+	return res, err
 }
 
-func (a *facade) UpdateSecret(ns string, secret *specV1.Secret) (*specV1.Secret, error) {
+func (a *facade) UpdateSecret(ctx context.Context, ns string, secret *specV1.Secret) (*specV1.Secret, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
 	secret, err := a.secret.Update(ns, secret)
 	if err != nil {
 		return nil, err
 	}
-	err = a.updateAppSecret(ns, secret)
+	err = a.updateAppSecret(ctx, ns, secret)
 	if err != nil {
 		return nil, err
 	}
 	return secret, err
 }
 
-func (a *facade) DeleteSecret(ns, name string) error {
+func (a *facade) DeleteSecret(ctx context.Context, ns, name string) error {
+	if err := a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.Trace(err)
+	}
 	return a.secret.Delete(ns, name)
 }
 
-func (a *facade) updateAppSecret(namespace string, secret *specV1.Secret) error {
+func (a *facade) updateAppSecret(ctx context.Context, namespace string, secret *specV1.Secret) error {
 	appNames, err := a.index.ListAppIndexBySecret(namespace, secret.Name)
 	if err != nil {
 		return err
 	}
 	for _, appName := range appNames {
+		if err = ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
 		app, err := a.app.Get(namespace, appName, "")
 		if err != nil {
 			if e, ok := err.(errors.Coder); ok && e.Code() == common.ErrResourceNotFound {
@@ -65,6 +71,9 @@ func (a *facade) updateAppSecret(namespace string, secret *specV1.Secret) error
 		if err != nil {
 			return err
 		}
+		if skipNodeIndex(app) {
+			continue
+		}
 		_, err = a.node.UpdateNodeAppVersion(nil, namespace, app)
 		if err != nil {
 			return err
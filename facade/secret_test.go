@@ -1,6 +1,7 @@
 package facade
 
 import (
+	"context"
 	"testing"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
@@ -20,15 +21,15 @@ func TestCreateSecret(t *testing.T) {
 		txFactory: mFacade.txFactory,
 	}
 	ns := "test"
-	mFacade.txFactory.EXPECT().BeginTx().Return(nil, nil).AnyTimes()
+	mFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	mFacade.txFactory.EXPECT().Rollback(nil).Return().Times(1)
 	mFacade.sSecret.EXPECT().Create(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err := sFacade.CreateSecret(ns, nil)
+	_, err := sFacade.CreateSecret(context.Background(), ns, nil)
 	assert.Error(t, err, unknownErr)
 
 	mFacade.txFactory.EXPECT().Commit(nil).Return().Times(1)
 	mFacade.sSecret.EXPECT().Create(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
-	_, err = sFacade.CreateSecret(ns, nil)
+	_, err = sFacade.CreateSecret(context.Background(), ns, nil)
 	assert.NoError(t, err)
 }
 
@@ -96,7 +97,7 @@ func TestUpdateSecret(t *testing.T) {
 	}
 
 	mFacade.sSecret.EXPECT().Update(ns, gomock.Any()).Return(nil, unknownErr)
-	_, err := sFacade.UpdateSecret(ns, mConf)
+	_, err := sFacade.UpdateSecret(context.Background(), ns, mConf)
 	assert.Error(t, err, unknownErr)
 
 	mFacade.sSecret.EXPECT().Update(ns, gomock.Any()).Return(mConfSecret3, nil).AnyTimes()
@@ -105,7 +106,7 @@ func TestUpdateSecret(t *testing.T) {
 	mFacade.sApp.EXPECT().Get(ns, appNames[1], "").Return(apps[1], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(apps[0], nil).Times(1)
 	mFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
-	_, err = sFacade.UpdateSecret(ns, mConf)
+	_, err = sFacade.UpdateSecret(context.Background(), ns, mConf)
 	assert.NoError(t, err)
 
 	appNames = []string{"app01"}
@@ -124,14 +125,14 @@ func TestUpdateSecret(t *testing.T) {
 	mFacade.sIndex.EXPECT().ListAppIndexBySecret(ns, name).Return(appNames, nil).AnyTimes()
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err = sFacade.UpdateSecret(ns, mConf)
+	_, err = sFacade.UpdateSecret(context.Background(), ns, mConf)
 	assert.Error(t, err, unknownErr)
 
 	apps[0].Volumes[0].Secret.Version = "1"
 	mFacade.sApp.EXPECT().Get(ns, appNames[0], "").Return(apps[0], nil).Times(1)
 	mFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).Return(nil, nil).Times(1)
 	mFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr).Times(1)
-	_, err = sFacade.UpdateSecret(ns, mConf)
+	_, err = sFacade.UpdateSecret(context.Background(), ns, mConf)
 	assert.Error(t, err, unknownErr)
 }
 
@@ -149,6 +150,6 @@ func TestDeleteSecret(t *testing.T) {
 	ns, n := "test", "test"
 
 	mFacade.sSecret.EXPECT().Delete(ns, n).Return(nil).Times(1)
-	err := sFacade.DeleteSecret(ns, n)
+	err := sFacade.DeleteSecret(context.Background(), ns, n)
 	assert.NoError(t, err)
 }
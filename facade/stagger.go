@@ -0,0 +1,244 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/service"
+)
+
+// RolloutProgress the staggered rollout of the current version of an application,
+// the matched nodes are grouped by the version of the app they desire and report
+type RolloutProgress struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   string `json:"version"`
+	// MaxNodes the nodes updating to the version at once
+	MaxNodes int `json:"maxNodes"`
+	// Updated the nodes which report the version running
+	Updated []string `json:"updated"`
+	// Updating the nodes which desire the version but haven't reported it running yet
+	Updating []string `json:"updating"`
+	// Outdated the nodes which still desire the previous version of the app or don't desire it yet
+	Outdated []string `json:"outdated"`
+}
+
+// AdvanceRollout deploys the current version of the staggered app to the outdated nodes in place of the ones
+// which have reported the version running, so that at most MaxNodes nodes are updating at once.
+// The progress of the rollout is returned, the rollout is done once no node is outdated or updating.
+func (a *facade) AdvanceRollout(ctx context.Context, ns, name string) (*RolloutProgress, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	return a.advanceRollout(ctx, ns, name)
+}
+
+// AdvanceRollouts advances the rollouts of the staggered apps of all the namespaces listed page by page.
+// All the apps are tried and the first failure is returned.
+func (a *facade) AdvanceRollouts(ctx context.Context) error {
+	if a.namespace == nil {
+		return nil
+	}
+	var firstErr error
+	opt := &models.ListOptions{Limit: int64(a.conf.IndexPageSize)}
+	for {
+		list, err := a.namespace.List(opt)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, ns := range list.Items {
+			if err = a.advanceNamespaceRollouts(ctx, ns.Name); err != nil && firstErr == nil {
+				firstErr = err
+			}
+			if err = ctx.Err(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		if list.ListOptions == nil || list.Continue == "" {
+			return firstErr
+		}
+		opt.Continue = list.Continue
+	}
+}
+
+// advanceNamespaceRollouts advances the rollouts of the staggered apps of the namespace, the failures are logged
+func (a *facade) advanceNamespaceRollouts(ctx context.Context, ns string) error {
+	var firstErr error
+	opt := &models.ListOptions{Limit: int64(a.conf.IndexPageSize)}
+	for {
+		list, err := a.app.List(ns, opt)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, item := range list.Items {
+			if app := appOfItem(item); !inStagger(app) || skipNodeIndex(app) || item.CronStatus == specV1.CronWait {
+				continue
+			}
+			if err = ctx.Err(); err != nil {
+				return errors.Trace(err)
+			}
+			if _, err = a.advanceRollout(ctx, ns, item.Name); err != nil {
+				a.log.Warn("failed to advance rollout",
+					log.Any(common.KeyContextNamespace, ns),
+					log.Any("name", item.Name),
+					log.Error(err))
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		if list.ListOptions == nil || list.Continue == "" {
+			return firstErr
+		}
+		opt.Continue = list.Continue
+	}
+}
+
+func (a *facade) advanceRollout(ctx context.Context, ns, name string) (*RolloutProgress, error) {
+	unlock, err := a.lockApp(ctx, ns, name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var progress *RolloutProgress
+	err = a.runTx(ctx, ns, "AdvanceRollout", func(tx interface{}, undo *compensations) error {
+		app, err := a.app.Get(ns, name, "")
+		if err != nil {
+			return err
+		}
+		maxNodes, err := rolloutMaxNodes(app)
+		if err != nil {
+			return err
+		}
+		if maxNodes == 0 || skipNodeIndex(app) || app.CronStatus == specV1.CronWait {
+			return common.Error(common.ErrRequestParamInvalid,
+				common.Field("error", fmt.Sprintf("the app (%s) is not rolled out in stagger", name)))
+		}
+		selector, err := normalizeSelector(app.Selector)
+		if err != nil {
+			return err
+		}
+		if selector != app.Selector {
+			cp := *app
+			cp.Selector = selector
+			app = &cp
+		}
+		_, progress, err = a.staggerNodeAndAppIndex(tx, ns, app, maxNodes, undo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// staggerSlots returns the nodes the current version of the staggered app can still be deployed to at once,
+// which are the max nodes less the ones updating to the version
+func (a *facade) staggerSlots(tx interface{}, namespace string, app *specV1.Application, maxNodes int) (int, error) {
+	nodes, err := a.node.MatchNodes(tx, namespace, app.Selector)
+	if err != nil {
+		return 0, err
+	}
+	progress, err := a.rolloutProgress(tx, namespace, app, nodes, maxNodes)
+	if err != nil {
+		return 0, err
+	}
+	return maxNodes - len(progress.Updating), nil
+}
+
+// updateStaggeredNodeAndAppIndex deploys the app to at most maxNodes of the matched nodes at once,
+// but indexes all the matched nodes since the others keep running the previous version until advanced
+func (a *facade) updateStaggeredNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application, maxNodes int, undo *compensations) ([]string, error) {
+	nodes, _, err := a.staggerNodeAndAppIndex(tx, namespace, app, maxNodes, undo)
+	return nodes, err
+}
+
+// staggerNodeAndAppIndex deploys the app to the outdated nodes up to maxNodes updating at once
+func (a *facade) staggerNodeAndAppIndex(tx interface{}, namespace string, app *specV1.Application, maxNodes int, undo *compensations) ([]string, *RolloutProgress, error) {
+	nodes, err := a.node.MatchNodes(tx, namespace, app.Selector)
+	if err != nil {
+		return nil, nil, err
+	}
+	progress, err := a.rolloutProgress(tx, namespace, app, nodes, maxNodes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n := maxNodes - len(progress.Updating); n > 0 && len(progress.Outdated) > 0 {
+		if n > len(progress.Outdated) {
+			n = len(progress.Outdated)
+		}
+		batch := progress.Outdated[:n]
+		if err = a.node.UpdateDesire(tx, namespace, batch, app, service.RefreshNodeDesireByApp); err != nil {
+			return nil, nil, err
+		}
+		progress.Updating = append(progress.Updating, batch...)
+		progress.Outdated = progress.Outdated[n:]
+		sort.Strings(progress.Updating)
+	}
+	return nodes, progress, a.refreshNodesIndexByApp(tx, namespace, app, nodes, undo)
+}
+
+// rolloutProgress groups the matched nodes by the version of the app they desire and report,
+// the nodes deleted since matched are left out
+func (a *facade) rolloutProgress(tx interface{}, namespace string, app *specV1.Application, nodes []string, maxNodes int) (*RolloutProgress, error) {
+	sorted := make([]string, len(nodes))
+	copy(sorted, nodes)
+	sort.Strings(sorted)
+
+	progress := &RolloutProgress{
+		Name:      app.Name,
+		Namespace: namespace,
+		Version:   app.Version,
+		MaxNodes:  maxNodes,
+		Updated:   []string{},
+		Updating:  []string{},
+		Outdated:  []string{},
+	}
+	for _, n := range sorted {
+		node, err := a.node.Get(tx, namespace, n)
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !desiresAppVersion(node.Desire, app) {
+			progress.Outdated = append(progress.Outdated, n)
+			continue
+		}
+		if stats := reportedAppStats(node, app); stats != nil && stats.Version == app.Version && stats.Status == specV1.Running {
+			progress.Updated = append(progress.Updated, n)
+			continue
+		}
+		progress.Updating = append(progress.Updating, n)
+	}
+	return progress, nil
+}
+
+// rolloutMaxNodes returns the nodes the app is updated on at once, 0 is returned if the app is rolled out to all nodes
+func rolloutMaxNodes(app *specV1.Application) (int, error) {
+	v, ok := app.Labels[common.LabelRolloutMaxNodes]
+	if !ok {
+		return 0, nil
+	}
+	maxNodes, err := strconv.Atoi(v)
+	if err != nil || maxNodes <= 0 {
+		return 0, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the max concurrent nodes (%s) of the app (%s) should be a positive integer", v, app.Name)))
+	}
+	return maxNodes, nil
+}
+
+func inStagger(app *specV1.Application) bool {
+	maxNodes, err := rolloutMaxNodes(app)
+	return err == nil && maxNodes > 0
+}
@@ -0,0 +1,220 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestRolloutMaxNodes(t *testing.T) {
+	app := &specV1.Application{Name: "abc"}
+	maxNodes, err := rolloutMaxNodes(app)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, maxNodes)
+	assert.False(t, inStagger(app))
+
+	app.Labels = map[string]string{common.LabelRolloutMaxNodes: "3"}
+	maxNodes, err = rolloutMaxNodes(app)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, maxNodes)
+	assert.True(t, inStagger(app))
+
+	for _, v := range []string{"0", "-1", "x"} {
+		app.Labels[common.LabelRolloutMaxNodes] = v
+		_, err = rolloutMaxNodes(app)
+		assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code(), v)
+	}
+}
+
+func TestStaggeredRollout(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns, name := "baetyl-cloud", "abc"
+	nodes := []string{"n4", "n3", "n2", "n1", "n5"}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+
+	desire := func(version string) specV1.Desire {
+		return specV1.Desire{"apps": []specV1.AppInfo{{Name: name, Version: version}}}
+	}
+	report := func(version string, status specV1.Status) specV1.Report {
+		r := specV1.Report{}
+		r.SetAppStats(false, []specV1.AppStats{{AppInfo: specV1.AppInfo{Name: name, Version: version}, Status: status}})
+		return r
+	}
+	app := &specV1.Application{Name: name, Namespace: ns, Version: "2", Selector: "a=b",
+		Labels: map[string]string{common.LabelRolloutMaxNodes: "2"}}
+
+	// the first nodes are updated and all the matched nodes are indexed
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nodes, nil)
+	for _, n := range []string{"n1", "n2", "n3", "n4"} {
+		mAppFacade.sNode.EXPECT().Get(nil, ns, n).Return(&specV1.Node{Name: n, Desire: desire("1"), Report: report("1", specV1.Running)}, nil)
+	}
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n5").Return(nil, common.Error(common.ErrResourceNotFound, common.Field("type", "node")))
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n1", "n2"}, app, gomock.Any()).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, nodes).Return(nil)
+	res, err := appFacade.updateNodeAndAppIndex(nil, ns, app, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, nodes, res)
+
+	// the rollout advances in place of the nodes reporting the version running
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nodes[:4], nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Desire: desire("2"), Report: report("2", specV1.Running)}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n2").Return(&specV1.Node{Name: "n2", Desire: desire("2"), Report: report("1", specV1.Running)}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(&specV1.Node{Name: "n3", Desire: desire("1")}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n4").Return(&specV1.Node{Name: "n4"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n3"}, app, gomock.Any()).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, name, nodes[:4]).Return(nil)
+	progress, err := appFacade.AdvanceRollout(context.Background(), ns, name)
+	assert.NoError(t, err)
+	assert.Equal(t, &RolloutProgress{
+		Name:      name,
+		Namespace: ns,
+		Version:   "2",
+		MaxNodes:  2,
+		Updated:   []string{"n1"},
+		Updating:  []string{"n2", "n3"},
+		Outdated:  []string{"n4"},
+	}, progress)
+
+	// the failure rolls back
+	mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(app, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return(nil, unknownErr)
+	_, err = appFacade.AdvanceRollout(context.Background(), ns, name)
+	assert.Error(t, err)
+
+	// the app out of stagger can't be advanced
+	mAppFacade.sApp.EXPECT().Get(ns, "other", "").Return(&specV1.Application{Name: "other", Selector: "a=b"}, nil)
+	_, err = appFacade.AdvanceRollout(context.Background(), ns, "other")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
+
+func TestStaggeredNodeRelabel(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	ns, name := "baetyl-cloud", "abc"
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+
+	desire := func(version string) specV1.Desire {
+		return specV1.Desire{"apps": []specV1.AppInfo{{Name: name, Version: version}}}
+	}
+	report := func(version string) specV1.Report {
+		r := specV1.Report{}
+		r.SetAppStats(false, []specV1.AppStats{{AppInfo: specV1.AppInfo{Name: name, Version: version}, Status: specV1.Running}})
+		return r
+	}
+	apps := &models.ApplicationList{
+		ListOptions: &models.ListOptions{},
+		Items: []models.AppItem{{Name: name, Version: "2", Selector: "a=b",
+			Labels: map[string]string{common.LabelRolloutMaxNodes: "1"}}},
+	}
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(apps, nil).AnyTimes()
+	relabeled := func(n string) *specV1.Node {
+		return &specV1.Node{Name: n, Labels: map[string]string{"a": "b"}}
+	}
+
+	// n1 is still updating, n3 relabeled is left to AdvanceRollout but indexed
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(relabeled("n3"), nil).Times(2)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n3").Return([]string{}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1", "n3"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Desire: desire("2"), Report: report("1")}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n3", []string{name}).Return(nil)
+	res, err := appFacade.RefreshNodeIndexesForNode(context.Background(), ns, "n3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{name}, res)
+
+	// n1 reports the version running, n3 relabeled is updated
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(relabeled("n3"), nil).Times(2)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n3").Return([]string{}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1", "n3"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Desire: desire("2"), Report: report("2")}, nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n3"}, nil, gomock.Any()).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n3", []string{name}).Return(nil)
+	_, err = appFacade.RefreshNodeIndexesForNode(context.Background(), ns, "n3")
+	assert.NoError(t, err)
+
+	// n3 and n4 relabeled at once, only n3 is updated as the rollout updates one node at once
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n3").Return(relabeled("n3"), nil).Times(2)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n4").Return(relabeled("n4"), nil).Times(2)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n3").Return([]string{}, nil)
+	mAppFacade.sIndex.EXPECT().ListAppsByNode(ns, "n4").Return([]string{}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, ns, "a=b").Return([]string{"n1", "n3", "n4"}, nil)
+	mAppFacade.sNode.EXPECT().Get(nil, ns, "n1").Return(&specV1.Node{Name: "n1", Desire: desire("2"), Report: report("2")}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n3", []string{name}).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshAppsIndexByNode(nil, ns, "n4", []string{name}).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateDesire(nil, ns, []string{"n3"}, nil, gomock.Any()).Return(nil)
+	err = appFacade.ReconcileNodeIndexes(context.Background(), ns, []string{"n3", "n4"})
+	assert.NoError(t, err)
+}
+
+func TestAdvanceRollouts(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	// disabled
+	assert.NoError(t, appFacade.AdvanceRollouts(context.Background()))
+
+	appFacade.namespace = mAppFacade.sNs
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	stagger := map[string]string{common.LabelRolloutMaxNodes: "1"}
+	mAppFacade.sNs.EXPECT().List(gomock.Any()).Return(&models.NamespaceList{
+		ListOptions: &models.ListOptions{Continue: "c1"},
+		Items:       []models.Namespace{{Name: "ns1"}},
+	}, nil)
+	mAppFacade.sNs.EXPECT().List(&models.ListOptions{Continue: "c1"}).Return(&models.NamespaceList{
+		ListOptions: &models.ListOptions{},
+		Items:       []models.Namespace{{Name: "ns2"}},
+	}, nil)
+	// only the staggered apps are advanced, the failure of an app doesn't stop the others
+	mAppFacade.sApp.EXPECT().List("ns1", gomock.Any()).Return(&models.ApplicationList{
+		Items: []models.AppItem{
+			{Name: "plain", Version: "1", Selector: "a=b"},
+			{Name: "ext", Version: "1", Selector: "a=b", Labels: map[string]string{common.LabelRolloutMaxNodes: "1", common.LabelSkipNodeIndex: "true"}},
+			{Name: "cron", Version: "1", Selector: "a=b", Labels: stagger, CronStatus: specV1.CronWait},
+			{Name: "bad", Version: "1", Selector: "a=b", Labels: map[string]string{common.LabelRolloutMaxNodes: "x"}},
+			{Name: "s1", Version: "1", Selector: "a=b", Labels: stagger},
+		},
+	}, nil)
+	mAppFacade.sApp.EXPECT().Get("ns1", "s1", "").Return(&specV1.Application{Name: "s1", Version: "1", Selector: "a=b", Labels: stagger}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, "ns1", "a=b").Return(nil, unknownErr)
+	mAppFacade.sApp.EXPECT().List("ns2", gomock.Any()).Return(&models.ApplicationList{
+		Items: []models.AppItem{{Name: "s2", Version: "1", Selector: "c=d", Labels: stagger}},
+	}, nil)
+	mAppFacade.sApp.EXPECT().Get("ns2", "s2", "").Return(&specV1.Application{Name: "s2", Version: "1", Selector: "c=d", Labels: stagger}, nil)
+	mAppFacade.sNode.EXPECT().MatchNodes(nil, "ns2", "c=d").Return([]string{}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, "ns2", "s2", []string{}).Return(nil)
+	err := appFacade.AdvanceRollouts(context.Background())
+	assert.Equal(t, unknownErr, errors.Cause(err))
+}
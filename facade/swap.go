@@ -0,0 +1,99 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// SwapApps swaps the selectors of two apps for the blue-green cutover, the apps are removed from
+// the nodes they're deployed to and deployed to the nodes matched by the swapped selectors in a single
+// transaction, so the nodes move from one app to the other atomically. The apps are updated out of
+// the transaction, so they're restored if the swap fails. The swapped apps are returned in order.
+func (a *facade) SwapApps(ctx context.Context, ns, nameA, nameB string) (res []*specV1.Application, err error) {
+	defer observeCall(ns, "SwapApps", time.Now(), &err)
+	if err = a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if nameA == nameB {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the app (%s) can't be swapped with itself", nameA)))
+	}
+
+	err = a.runTx(ctx, ns, "SwapApps", func(tx interface{}, undo *compensations) error {
+		olds := make([]*specV1.Application, 2)
+		for i, name := range []string{nameA, nameB} {
+			if err := a.checkAppFrozen(tx, ns, name); err != nil {
+				return err
+			}
+			app, err := a.app.Get(ns, name, "")
+			if err != nil {
+				return err
+			}
+			// the selector of the app waiting for cron is kept by its cron
+			if app.CronStatus == specV1.CronWait {
+				return common.Error(common.ErrRequestParamInvalid,
+					common.Field("error", fmt.Sprintf("the app (%s) waiting for cron can't be swapped", name)))
+			}
+			olds[i] = app
+		}
+
+		var removed [][]string
+		for _, old := range olds {
+			rm, err := a.deleteNodeAndAppIndex(tx, ns, old, undo)
+			if err != nil {
+				return err
+			}
+			removed = append(removed, rm)
+		}
+
+		res = make([]*specV1.Application, 0, 2)
+		for i, old := range olds {
+			if err := ctx.Err(); err != nil {
+				return errors.Trace(err)
+			}
+			app := *old
+			app.Selector = olds[1-i].Selector
+			if err := a.preUpdateApp(ctx, tx, ns, old, &app); err != nil {
+				return err
+			}
+			updated, err := a.app.Update(tx, ns, &app)
+			if err != nil {
+				return err
+			}
+			selector := old.Selector
+			undo.add(func() error {
+				restore := *updated
+				restore.Selector = selector
+				_, err := a.app.Update(nil, ns, &restore)
+				return err
+			})
+			if err = a.createAppAudit(ctx, tx, models.AppUpdated, ns, updated.Name, old.Version, updated.Version); err != nil {
+				return err
+			}
+			added, err := a.updateNodeAndAppIndex(tx, ns, updated, undo)
+			if err != nil {
+				return wrapAppError(updated.Name, err)
+			}
+			changed := mergeNodes(added, removed[i])
+			if err = a.writeAppOutbox(tx, models.AppUpdated, ns, updated, changed); err != nil {
+				return err
+			}
+			res = append(res, updated)
+			undo.onCommit(func() {
+				a.publishAppEvent(ctx, models.AppUpdated, ns, updated, changed)
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
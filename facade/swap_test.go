@@ -0,0 +1,85 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+func TestSwapApps(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	blue := func() *specV1.Application {
+		return &specV1.Application{Name: "blue", Version: "1", Selector: "env=prod"}
+	}
+	green := func() *specV1.Application {
+		return &specV1.Application{Name: "green", Version: "5", Selector: "env=staging"}
+	}
+
+	_, err := appFacade.SwapApps(context.Background(), ns, "blue", "blue")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+
+	// both apps are moved within one transaction
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sApp.EXPECT().Get(ns, "blue", "").Return(blue(), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "green", "").Return(green(), nil)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, blue()).Return([]string{"n1"}, nil)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, green()).Return([]string{"n2"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "blue", []string{}).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "green", []string{}).Return(nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			app.Version = app.Version + "0"
+			return app, nil
+		}).Times(2)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, &specV1.Application{Name: "blue", Version: "10", Selector: "env=staging"}).Return([]string{"n2"}, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, &specV1.Application{Name: "green", Version: "50", Selector: "env=prod"}).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "blue", []string{"n2"}).Return(nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "green", []string{"n1"}).Return(nil)
+	res, err := appFacade.SwapApps(context.Background(), ns, "blue", "green")
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "env=staging", res[0].Selector)
+	assert.Equal(t, "env=prod", res[1].Selector)
+
+	// the index refresh of green fails, the whole swap is rolled back and blue is restored
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sApp.EXPECT().Get(ns, "blue", "").Return(blue(), nil)
+	mAppFacade.sApp.EXPECT().Get(ns, "green", "").Return(green(), nil)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), []string{}).Return(nil).Times(2)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+			app.Version = app.Version + "0"
+			return app, nil
+		}).Times(2)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "blue", gomock.Any()).Return(nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, unknownErr)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, &specV1.Application{Name: "green", Version: "50", Selector: "env=staging"}).Return(nil, nil)
+	mAppFacade.sApp.EXPECT().Update(nil, ns, &specV1.Application{Name: "blue", Version: "10", Selector: "env=prod"}).Return(nil, nil)
+	_, err = appFacade.SwapApps(context.Background(), ns, "blue", "green")
+	assert.Error(t, err)
+
+	// cron app
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sApp.EXPECT().Get(ns, "blue", "").Return(&specV1.Application{Name: "blue", CronStatus: specV1.CronWait}, nil)
+	_, err = appFacade.SwapApps(context.Background(), ns, "blue", "green")
+	assert.Equal(t, common.ErrRequestParamInvalid, err.(errors.Coder).Code())
+}
@@ -0,0 +1,122 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// TeardownError reports the apps of the namespace failed to be deleted with their failures,
+// the other apps are deleted and the teardown is completed by retrying once the failures are resolved
+type TeardownError struct {
+	Namespace string
+	Failures  map[string]error
+}
+
+// Code the code shared by the failures of the apps
+func (e *TeardownError) Code() string {
+	return sharedCode(e.Failures)
+}
+
+func (e *TeardownError) Error() string {
+	names := make([]string, 0, len(e.Failures))
+	for name := range e.Failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, 0, len(names))
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", name, e.Failures[name].Error()))
+	}
+	return fmt.Sprintf("failed to delete the apps of namespace (%s): %s", e.Namespace, strings.Join(msgs, "; "))
+}
+
+// DeleteNamespaceApps deletes all the apps of the namespace by DeleteApps, so each app is deleted with its cron,
+// generated configs and secrets, and node indexes. The apps are deleted page by page, each page in its own
+// transaction, and the apps of a failed page are retried one by one so that the failure of an app doesn't keep
+// the others. *TeardownError is returned reporting the apps failed. The apps deleted meanwhile are skipped,
+// so the teardown is idempotent and a retry after a partial failure deletes the apps left. The app listed again
+// after it's deleted or taken as deleted is reported as failed instead of being retried, so that the stale
+// listing doesn't restart the teardown forever.
+func (a *facade) DeleteNamespaceApps(ctx context.Context, ns string) error {
+	if err := a.authorize(ctx, ns); err != nil {
+		return err
+	}
+	failures := map[string]error{}
+	attempted := map[string]bool{}
+	deleted := 0
+	opt := &models.ListOptions{Limit: int64(a.conf.IndexPageSize)}
+	for {
+		if err := ctx.Err(); err != nil {
+			return errors.Trace(err)
+		}
+		list, err := a.app.List(ns, opt)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		var names []string
+		for _, item := range list.Items {
+			if _, ok := failures[item.Name]; ok {
+				continue
+			}
+			if attempted[item.Name] {
+				failures[item.Name] = errors.Errorf("the app is still listed after it's deleted")
+				continue
+			}
+			attempted[item.Name] = true
+			names = append(names, item.Name)
+		}
+		if len(names) > 0 {
+			deleted += a.deleteNamespaceApps(ctx, ns, names, failures)
+			// the apps deleted are gone from the pages, so the listing restarts
+			opt.Continue = ""
+			continue
+		}
+		// only the apps failed are left in the page
+		if list.ListOptions == nil || list.Continue == "" {
+			break
+		}
+		opt.Continue = list.Continue
+	}
+	a.log.Info("the apps of the namespace are torn down",
+		log.Any("audit", "deleteNamespaceApps"),
+		log.Any(common.KeyContextNamespace, ns),
+		log.Any("deleted", deleted),
+		log.Any("failed", len(failures)))
+	if len(failures) > 0 {
+		return &TeardownError{Namespace: ns, Failures: failures}
+	}
+	return nil
+}
+
+// deleteNamespaceApps deletes the page of apps in a single transaction, or one by one if the page fails,
+// the apps not found are taken as deleted. The number of the apps deleted is returned.
+func (a *facade) deleteNamespaceApps(ctx context.Context, ns string, names []string, failures map[string]error) int {
+	err := a.DeleteApps(ctx, ns, names)
+	if err == nil {
+		return len(names)
+	}
+	if len(names) == 1 {
+		if isNotFound(err) {
+			return 0
+		}
+		failures[names[0]] = err
+		return 0
+	}
+	deleted := 0
+	for _, name := range names {
+		if err = ctx.Err(); err != nil {
+			failures[name] = errors.Trace(err)
+			continue
+		}
+		deleted += a.deleteNamespaceApps(ctx, ns, []string{name}, failures)
+	}
+	return deleted
+}
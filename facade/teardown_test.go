@@ -0,0 +1,100 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestDeleteNamespaceApps(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		log:       log.L(),
+	}
+	appFacade.conf.IndexPageSize = 2
+	ns := "baetyl-cloud"
+	page := func(cont string, names ...string) *models.ApplicationList {
+		list := &models.ApplicationList{ListOptions: &models.ListOptions{Continue: cont}}
+		for _, name := range names {
+			list.Items = append(list.Items, models.AppItem{Name: name, Namespace: ns})
+		}
+		return list
+	}
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().AnyTimes()
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().AnyTimes()
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil).AnyTimes()
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	for _, name := range []string{"app1", "app2", "app3"} {
+		mAppFacade.sApp.EXPECT().Get(ns, name, "").Return(&specV1.Application{Name: name, Namespace: ns}, nil).AnyTimes()
+	}
+
+	// the failed page is retried app by app, the app failed is reported and skipped by the listing
+	gomock.InOrder(
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(page("c1", "app1", "app2"), nil),
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(page("", "app2", "app3"), nil),
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(page("", "app2"), nil),
+	)
+	gomock.InOrder(
+		mAppFacade.sApp.EXPECT().Delete(nil, ns, "app1", "").Return(nil),
+		mAppFacade.sApp.EXPECT().Delete(nil, ns, "app2", "").Return(unknownErr),
+		mAppFacade.sApp.EXPECT().Delete(nil, ns, "app1", "").Return(nil),
+		mAppFacade.sApp.EXPECT().Delete(nil, ns, "app2", "").Return(unknownErr),
+		mAppFacade.sApp.EXPECT().Delete(nil, ns, "app3", "").Return(nil),
+	)
+	err := appFacade.DeleteNamespaceApps(context.Background(), ns)
+	assert.Error(t, err)
+	terr, ok := err.(*TeardownError)
+	assert.True(t, ok)
+	assert.Len(t, terr.Failures, 1)
+	assert.Contains(t, terr.Failures, "app2")
+	assert.Contains(t, err.Error(), "app2")
+
+	// the retry deletes the apps left
+	gomock.InOrder(
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(page("", "app2"), nil),
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(page(""), nil),
+	)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "app2", "").Return(nil)
+	assert.NoError(t, appFacade.DeleteNamespaceApps(context.Background(), ns))
+
+	// the app deleted meanwhile is taken as deleted
+	gomock.InOrder(
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(page("", "app4"), nil),
+		mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(page(""), nil),
+	)
+	mAppFacade.sApp.EXPECT().Get(ns, "app4", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	assert.NoError(t, appFacade.DeleteNamespaceApps(context.Background(), ns))
+
+	// the app listed again after it's taken as deleted is reported instead of restarting the listing forever
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(page("", "app5"), nil).Times(2)
+	mAppFacade.sApp.EXPECT().Get(ns, "app5", "").Return(nil, common.Error(common.ErrResourceNotFound))
+	err = appFacade.DeleteNamespaceApps(context.Background(), ns)
+	terr, ok = err.(*TeardownError)
+	assert.True(t, ok)
+	assert.Len(t, terr.Failures, 1)
+	assert.Contains(t, err.Error(), "app5: the app is still listed after it's deleted")
+
+	// the listing fails
+	mAppFacade.sApp.EXPECT().List(ns, gomock.Any()).Return(nil, unknownErr)
+	assert.Error(t, appFacade.DeleteNamespaceApps(context.Background(), ns))
+
+	// the teardown is stopped once the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, appFacade.DeleteNamespaceApps(ctx, ns))
+}
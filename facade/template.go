@@ -0,0 +1,156 @@
+package facade
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// AppTemplateKey the key of the config data which holds the app template
+const AppTemplateKey = "template"
+
+// templateParam matches the parameters of the template, ${name} is required and ${name:-default} is optional
+var templateParam = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.-]*)(?::-([^}]*))?\}`)
+
+// AppTemplate the app template stored in a config labeled with common.LabelAppTemplate, it has the form of
+// the app bundle without the secrets. The parameters in the string values of the app and the configs are
+// substituted, a value consisting of a single parameter takes the type of the substitution, e.g. the replica.
+type AppTemplate struct {
+	App     *specV1.Application    `yaml:"app" json:"app"`
+	Configs []specV1.Configuration `yaml:"configs" json:"configs"`
+}
+
+// InstantiateTemplate creates the app of the template stored in the namespace with the parameters substituted,
+// the configs of the template are created with the app. ErrRequestParamInvalid listing the missing parameters
+// is returned if any required parameter isn't given.
+func (a *facade) InstantiateTemplate(ctx context.Context, ns, templateName string, params map[string]string) (*specV1.Application, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	cfg, err := a.config.Get(ns, templateName, "")
+	if err != nil {
+		return nil, err
+	}
+	text, ok := cfg.Data[AppTemplateKey]
+	if _, labeled := cfg.Labels[common.LabelAppTemplate]; !labeled || !ok {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the config (%s) is not an app template", templateName)))
+	}
+	tpl, err := renderAppTemplate(templateName, text, params)
+	if err != nil {
+		return nil, err
+	}
+
+	app := tpl.App
+	app.Namespace = ns
+	app.Version = ""
+	if err = common.ValidateStruct(app); err != nil {
+		return nil, err
+	}
+	for i := range tpl.Configs {
+		tpl.Configs[i].Namespace = ns
+		tpl.Configs[i].Version = ""
+		if err = common.ValidateStruct(&tpl.Configs[i]); err != nil {
+			return nil, err
+		}
+	}
+	res, err := a.CreateApp(ctx, ns, nil, app, tpl.Configs)
+	if err != nil {
+		return nil, err
+	}
+	return res.App, nil
+}
+
+// renderAppTemplate substitutes the parameters of the template text and parses the result
+func renderAppTemplate(name, text string, params map[string]string) (*AppTemplate, error) {
+	var tree interface{}
+	if err := yaml.Unmarshal([]byte(text), &tree); err != nil {
+		return nil, common.Error(common.ErrTemplate,
+			common.Field("error", fmt.Sprintf("invalid app template (%s): %s", name, err.Error())))
+	}
+	missing := map[string]bool{}
+	tree = substituteParams(tree, params, missing)
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for n := range missing {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", fmt.Sprintf("the parameters (%s) of the app template (%s) are missing",
+				strings.Join(names, ", "), name)))
+	}
+	data, err := yaml.Marshal(tree)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tpl := new(AppTemplate)
+	if err = yaml.Unmarshal(data, tpl); err != nil {
+		return nil, common.Error(common.ErrTemplate,
+			common.Field("error", fmt.Sprintf("invalid app template (%s): %s", name, err.Error())))
+	}
+	if tpl.App == nil {
+		return nil, common.Error(common.ErrTemplate,
+			common.Field("error", fmt.Sprintf("the app template (%s) has no application", name)))
+	}
+	return tpl, nil
+}
+
+// substituteParams substitutes the parameters in the string values of the tree, the required parameters
+// not given are collected into missing
+func substituteParams(v interface{}, params map[string]string, missing map[string]bool) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		for k, e := range t {
+			t[k] = substituteParams(e, params, missing)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = substituteParams(e, params, missing)
+		}
+		return t
+	case string:
+		resolve := func(m []string) string {
+			if value, ok := params[m[1]]; ok {
+				return value
+			}
+			if !strings.Contains(m[0], ":-") {
+				missing[m[1]] = true
+			}
+			return m[2]
+		}
+		// the value of a single parameter is typed as it's written in place
+		if m := templateParam.FindStringSubmatch(t); m != nil && m[0] == t {
+			return typedScalar(resolve(m))
+		}
+		return templateParam.ReplaceAllStringFunc(t, func(s string) string {
+			return resolve(templateParam.FindStringSubmatch(s))
+		})
+	default:
+		return v
+	}
+}
+
+// typedScalar returns the number or boolean the value represents, otherwise the value itself
+func typedScalar(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(value), &v); err == nil {
+		switch v.(type) {
+		case int, int64, uint64, float64:
+			return v
+		}
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+package facade
+
+import (
+	"context"
+	"testing"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+const testAppTemplate = `
+app:
+  name: ${name}
+  selector: ${selector:-}
+  services:
+  - name: agent
+    image: "agent:${tag:-latest}"
+    replica: ${replicas}
+  volumes:
+  - name: conf
+    config:
+      name: ${name}-conf
+configs:
+- name: ${name}-conf
+  data:
+    conf.yml: "port: ${port}"
+`
+
+func TestInstantiateTemplate(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	ctx := context.Background()
+	tpl := &specV1.Configuration{
+		Name:   "agent-template",
+		Labels: map[string]string{common.LabelAppTemplate: "true"},
+		Data:   map[string]string{AppTemplateKey: testAppTemplate},
+	}
+	mAppFacade.sConfig.EXPECT().Get(ns, "agent-template", "").Return(tpl, nil).AnyTimes()
+
+	// the required parameters are listed
+	_, err := appFacade.InstantiateTemplate(ctx, ns, "agent-template", map[string]string{"name": "agent"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "port, replicas")
+
+	// not a template
+	mAppFacade.sConfig.EXPECT().Get(ns, "plain", "").Return(&specV1.Configuration{Name: "plain"}, nil)
+	_, err = appFacade.InstantiateTemplate(ctx, ns, "plain", nil)
+	assert.Error(t, err)
+
+	// the result is validated
+	_, err = appFacade.InstantiateTemplate(ctx, ns, "agent-template", map[string]string{"name": "Agent_1", "port": "80", "replicas": "2"})
+	assert.Error(t, err)
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sConfig.EXPECT().Upsert(nil, ns, gomock.Any()).DoAndReturn(func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+		assert.Equal(t, "agent-conf", cfg.Name)
+		assert.Equal(t, "port: 8080", cfg.Data["conf.yml"])
+		return cfg, nil
+	})
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).DoAndReturn(func(_ interface{}, _ string, app, _ *specV1.Application) (*specV1.Application, error) {
+		assert.Equal(t, "agent", app.Name)
+		assert.Equal(t, ns, app.Namespace)
+		assert.Equal(t, "", app.Selector)
+		assert.Equal(t, "agent:latest", app.Services[0].Image)
+		assert.Equal(t, 3, app.Services[0].Replica)
+		assert.Equal(t, "agent-conf", app.Volumes[0].Config.Name)
+		return app, nil
+	})
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "agent", gomock.Any()).Return(nil)
+	app, err := appFacade.InstantiateTemplate(ctx, ns, "agent-template", map[string]string{"name": "agent", "port": "8080", "replicas": "3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "agent", app.Name)
+}
+
+func TestSubstituteParams(t *testing.T) {
+	missing := map[string]bool{}
+	res := substituteParams([]interface{}{"${a}", "x-${b:-y}-${c}", "${d}", "${e}"}, map[string]string{"a": "1", "d": "true", "e": "a: b"}, missing)
+	assert.Equal(t, []interface{}{1, "x-y-", true, "a: b"}, res)
+	assert.Equal(t, map[string]bool{"c": true}, missing)
+}
@@ -0,0 +1,52 @@
+package facade
+
+import (
+	"context"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/baetyl/baetyl-cloud/v2/facade"
+
+var (
+	attrNamespace  = attribute.Key("baetyl.namespace")
+	attrAppName    = attribute.Key("baetyl.app.name")
+	attrAppVersion = attribute.Key("baetyl.app.version")
+)
+
+// startSpan starts a span of the facade as a child of the span carried by ctx
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "facade."+name, trace.WithAttributes(attrs...))
+}
+
+// startAppSpan starts a span of the facade method operating the app
+func startAppSpan(ctx context.Context, method, ns, name string) (context.Context, trace.Span) {
+	return startSpan(ctx, method, attrNamespace.String(ns), attrAppName.String(name))
+}
+
+// endSpan ends the span with the version of app (if not nil), the span is marked error if err is not nil,
+// otherwise ok even if it's marked error by a retried transaction
+func endSpan(span trace.Span, app *specV1.Application, err error) {
+	if app != nil {
+		span.SetAttributes(attrAppVersion.String(app.Version))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
+
+// traceStep runs a step of the facade method within a child span
+func traceStep(ctx context.Context, name string, step func() error) error {
+	_, span := startSpan(ctx, name)
+	err := step()
+	endSpan(span, nil, err)
+	return err
+}
@@ -0,0 +1,115 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func initSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return sr
+}
+
+func endedSpans(sr *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	res := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range sr.Ended() {
+		res[s.Name()] = s
+	}
+	return res
+}
+
+func TestTraceCreateApp(t *testing.T) {
+	sr := initSpanRecorder(t)
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{
+		Namespace:  ns,
+		Name:       "abc",
+		Selector:   "a=b",
+		CronStatus: specV1.CronWait,
+		CronTime:   time.Now().Add(time.Hour),
+	}
+	created := &specV1.Application{Namespace: ns, Name: "abc", Version: "v1"}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sCron.EXPECT().CreateCron(gomock.Any()).Return(nil)
+	mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).Return(created, nil)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, created).Return([]string{"n1"}, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, "abc", []string{"n1"}).Return(nil)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.NoError(t, err)
+
+	spans := endedSpans(sr)
+	root, ok := spans["facade.CreateApp"]
+	assert.True(t, ok)
+	assert.Equal(t, codes.Ok, root.Status().Code)
+	assert.Contains(t, root.Attributes(), attrNamespace.String(ns))
+	assert.Contains(t, root.Attributes(), attrAppName.String("abc"))
+	assert.Contains(t, root.Attributes(), attrAppVersion.String("v1"))
+	for _, name := range []string{"facade.UpsertConfigs", "facade.CreateCron", "facade.RefreshIndex"} {
+		child, ok := spans[name]
+		assert.True(t, ok, name)
+		assert.Equal(t, root.SpanContext().SpanID(), child.Parent().SpanID(), name)
+	}
+}
+
+func TestTraceDeleteAppRollback(t *testing.T) {
+	sr := initSpanRecorder(t)
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{TxRetry: config.TxRetry{Max: 0}},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{Namespace: ns, Name: "abc", Version: "v1"}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.sApp.EXPECT().GetForUpdate(nil, ns, "abc").Return(app, nil)
+	mAppFacade.sApp.EXPECT().Delete(nil, ns, "abc", "").Return(nil)
+	mAppFacade.sNode.EXPECT().DeleteNodeAppVersion(nil, ns, app).Return(nil, unknownErr)
+	err := appFacade.DeleteApp(context.Background(), ns, "abc", app)
+	assert.Error(t, err)
+
+	spans := endedSpans(sr)
+	root, ok := spans["facade.DeleteApp"]
+	assert.True(t, ok)
+	assert.Equal(t, codes.Error, root.Status().Code)
+	var events []string
+	for _, e := range root.Events() {
+		events = append(events, e.Name)
+	}
+	assert.Contains(t, events, "rollback")
+	assert.Equal(t, codes.Error, spans["facade.RefreshIndex"].Status().Code)
+}
@@ -0,0 +1,258 @@
+package facade
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+	"github.com/go-sql-driver/mysql"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+const (
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrLockDeadlock    = 1213
+)
+
+// txIsolations the isolation levels of the transactions of the facade methods, the methods absent begin their
+// transactions in the default level of the storage, which is repeatable read of MySQL.
+//
+// The methods matching the selectors of the apps against the nodes to refresh the node indexes run in serializable,
+// so that a node labeled or created concurrently is either matched within the transaction or blocked until it's
+// committed, instead of being missed by the indexes. The locks taken make deadlocks more likely, which are retried
+// by runTx. PreviewApp only reads and runs in read committed to see the latest committed nodes without locking.
+// The composite reads of runReadTx keep repeatable read since they need a consistent snapshot.
+var txIsolations = map[string]sql.IsolationLevel{
+	"CreateApp":                 sql.LevelSerializable,
+	"CreateApps":                sql.LevelSerializable,
+	"UpdateApp":                 sql.LevelSerializable,
+	"PatchApp":                  sql.LevelSerializable,
+	"ImportApp":                 sql.LevelSerializable,
+	"RestoreApp":                sql.LevelSerializable,
+	"SwapApps":                  sql.LevelSerializable,
+	"TriggerCronApp":            sql.LevelSerializable,
+	"RepairAppIndex":            sql.LevelSerializable,
+	"RefreshNodeIndexesForNode": sql.LevelSerializable,
+	"ReconcileNodeIndexes":      sql.LevelSerializable,
+	"PreviewApp":                sql.LevelReadCommitted,
+}
+
+var isolationNames = map[string]sql.IsolationLevel{
+	"default":         sql.LevelDefault,
+	"read-committed":  sql.LevelReadCommitted,
+	"repeatable-read": sql.LevelRepeatableRead,
+	"serializable":    sql.LevelSerializable,
+}
+
+// txOptions returns the options of the transaction of the method, the level configured overrides the built-in one
+func (a *facade) txOptions(method string) *plugin.TxOptions {
+	if name, ok := a.conf.TxIsolation[method]; ok {
+		return &plugin.TxOptions{Isolation: isolationNames[name]}
+	}
+	return &plugin.TxOptions{Isolation: txIsolations[method]}
+}
+
+// validTxIsolations checks the isolation levels configured are known
+func validTxIsolations(levels map[string]string) error {
+	for method, name := range levels {
+		if _, ok := isolationNames[name]; !ok {
+			return errors.Errorf("the isolation level (%s) of the transactions of %s is unknown", name, method)
+		}
+	}
+	return nil
+}
+
+// runTx runs the handler within a transaction which is committed if the handler succeeds,
+// otherwise rolled back with the compensations. The hooks registered to undo by the handler run after the
+// transaction is committed or rolled back, the hooks of the attempts rolled back never run on commit. The whole transaction is retried with
+// exponential backoff if it fails because of a deadlock or serialization failure.
+// The transaction is begun in the isolation level of the method (see txIsolations),
+// the namespace and method are also used to label the transaction metrics.
+func (a *facade) runTx(ctx context.Context, ns, method string, handler func(tx interface{}, undo *compensations) error) error {
+	delay := a.conf.TxRetry.BaseDelay
+	for attempt := 0; ; attempt++ {
+		err := a.runTxOnce(ctx, ns, method, handler)
+		if err == nil || attempt >= a.conf.TxRetry.Max || !isRetryableTxError(err) {
+			return err
+		}
+		facadeTxRetries.WithLabelValues(ns, method).Inc()
+		a.log.Warn("transaction failed and will be retried", log.Any("method", method), log.Any("attempt", attempt+1), log.Error(err))
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (a *facade) runTxOnce(ctx context.Context, ns, method string, handler func(tx interface{}, undo *compensations) error) (err error) {
+	tx, err := a.txFactory.BeginTx(ctx, a.txOptions(method))
+	if err != nil {
+		return err
+	}
+	var undo compensations
+	defer func() {
+		if p := recover(); p != nil {
+			traceRollback(ctx, fmt.Errorf("panic: %v", p))
+			facadeTransactions.WithLabelValues(ns, method, txOutcomeRollback).Inc()
+			a.txFactory.Rollback(tx)
+			undo.run()
+			panic(p)
+		} else if err != nil {
+			traceRollback(ctx, err)
+			facadeTransactions.WithLabelValues(ns, method, txOutcomeRollback).Inc()
+			a.txFactory.Rollback(tx)
+			undo.run()
+		} else {
+			trace.SpanFromContext(ctx).AddEvent("commit")
+			facadeTransactions.WithLabelValues(ns, method, txOutcomeCommit).Inc()
+			a.txFactory.Commit(tx)
+			undo.committed()
+		}
+	}()
+	err = handler(tx, &undo)
+	return
+}
+
+// runOptional runs the optional step within a savepoint of the transaction, the writes of the step are rolled back
+// to the savepoint if it fails so that the transaction proceeds without them. The failure of the step is returned
+// as skipped, while err is returned only if the savepoint fails, which leaves the transaction to be aborted.
+func (a *facade) runOptional(tx interface{}, savepoint string, step func() error) (skipped, err error) {
+	if err = a.txFactory.Savepoint(tx, savepoint); err != nil {
+		return nil, err
+	}
+	if skipped = step(); skipped == nil {
+		return nil, nil
+	}
+	if err = a.txFactory.RollbackToSavepoint(tx, savepoint); err != nil {
+		return skipped, err
+	}
+	return skipped, nil
+}
+
+type readOnlyTxKey struct{}
+
+// WithReadOnlyTx returns the context which makes the composite reads of the facade (e.g. the app
+// with its cron) run within a read-only transaction, so that they see a consistent snapshot
+func WithReadOnlyTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyTxKey{}, true)
+}
+
+func readOnlyTxFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(readOnlyTxKey{}).(bool)
+	return v
+}
+
+type forceKey struct{}
+
+// WithForce returns the context which confirms the update of the app with a large impact,
+// e.g. the selector change removing the app from more nodes than the impact guard allows
+func WithForce(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceKey{}, true)
+}
+
+func forceFromContext(ctx context.Context) bool {
+	v, _ := ctx.Value(forceKey{}).(bool)
+	return v
+}
+
+// runReadTx runs the reads of the handler within a read-only transaction if ctx is returned by WithReadOnlyTx,
+// otherwise the handler is called with nil tx. The read-only transaction is never retried since it takes no lock.
+func (a *facade) runReadTx(ctx context.Context, ns, method string, handler func(tx interface{}) error) (err error) {
+	if !readOnlyTxFromContext(ctx) {
+		return handler(nil)
+	}
+	tx, err := a.txFactory.BeginReadOnlyTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			facadeTransactions.WithLabelValues(ns, method, txOutcomeRollback).Inc()
+			a.txFactory.Rollback(tx)
+			panic(p)
+		} else if err != nil {
+			facadeTransactions.WithLabelValues(ns, method, txOutcomeRollback).Inc()
+			a.txFactory.Rollback(tx)
+		} else {
+			facadeTransactions.WithLabelValues(ns, method, txOutcomeCommit).Inc()
+			a.txFactory.Commit(tx)
+		}
+	}()
+	err = handler(tx)
+	return
+}
+
+// traceRollback marks the span carried by ctx error since the transaction is rolled back
+func traceRollback(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.AddEvent("rollback")
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func isRetryableTxError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if e, ok := errors.Cause(err).(*mysql.MySQLError); ok {
+		return e.Number == mysqlErrLockDeadlock || e.Number == mysqlErrLockWaitTimeout
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "deadlock") ||
+		strings.Contains(msg, "could not serialize access") ||
+		strings.Contains(msg, "try restarting transaction")
+}
+
+// compensations undo the writes which can not be rolled back by the transaction, and hold the hooks run
+// once the transaction is committed or rolled back, e.g. the side effects visible out of the transaction,
+// so that they're registered where they're created instead of after the transaction returns
+type compensations struct {
+	undos     []func() error
+	commits   []func()
+	rollbacks []func()
+}
+
+func (c *compensations) add(f func() error) {
+	c.undos = append(c.undos, f)
+}
+
+// onCommit registers the hook run after the transaction is committed, the hooks run in the order they're registered
+func (c *compensations) onCommit(f func()) {
+	c.commits = append(c.commits, f)
+}
+
+// onRollback registers the hook run after the transaction is rolled back and compensated,
+// the hooks run in the order they're registered
+func (c *compensations) onRollback(f func()) {
+	c.rollbacks = append(c.rollbacks, f)
+}
+
+// run executes the compensations in reverse order, failures are logged as dirty data,
+// then the rollback hooks are run
+func (c *compensations) run() {
+	for i := len(c.undos) - 1; i >= 0; i-- {
+		if err := c.undos[i](); err != nil {
+			common.LogDirtyData(err, log.Any("type", "compensation"))
+		}
+	}
+	for _, f := range c.rollbacks {
+		f()
+	}
+}
+
+// committed runs the commit hooks
+func (c *compensations) committed() {
+	for _, f := range c.commits {
+		f()
+	}
+}
@@ -0,0 +1,220 @@
+package facade
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestRunTxRetry(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{TxRetry: config.TxRetry{Max: 2, BaseDelay: time.Millisecond}},
+		log:       log.L(),
+	}
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}
+
+	// retried and succeed
+	gomock.InOrder(
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.txFactory.EXPECT().Rollback(nil).Return(),
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.txFactory.EXPECT().Commit(nil).Return(),
+	)
+	attempts := 0
+	err := appFacade.runTx(context.Background(), "default", "test", func(tx interface{}, undo *compensations) error {
+		attempts++
+		if attempts == 1 {
+			return deadlock
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	// give up after max retries
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(3)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return().Times(3)
+	attempts = 0
+	err = appFacade.runTx(context.Background(), "default", "test", func(tx interface{}, undo *compensations) error {
+		attempts++
+		return deadlock
+	})
+	assert.Equal(t, deadlock, err)
+	assert.Equal(t, 3, attempts)
+
+	// not retryable
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	attempts = 0
+	err = appFacade.runTx(context.Background(), "default", "test", func(tx interface{}, undo *compensations) error {
+		attempts++
+		return unknownErr
+	})
+	assert.Equal(t, unknownErr, err)
+	assert.Equal(t, 1, attempts)
+
+	// panic is rolled back with compensations
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	compensated := false
+	assert.Panics(t, func() {
+		appFacade.runTx(context.Background(), "default", "test", func(tx interface{}, undo *compensations) error {
+			undo.add(func() error {
+				compensated = true
+				return nil
+			})
+			panic("error")
+		})
+	})
+	assert.True(t, compensated)
+}
+
+func TestRunTxHooks(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{TxRetry: config.TxRetry{Max: 1, BaseDelay: time.Millisecond}},
+		log:       log.L(),
+	}
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}
+	var calls []string
+	handler := func(fail bool) func(tx interface{}, undo *compensations) error {
+		return func(tx interface{}, undo *compensations) error {
+			undo.onCommit(func() { calls = append(calls, "commit1") })
+			undo.onRollback(func() { calls = append(calls, "rollback1") })
+			undo.add(func() error {
+				calls = append(calls, "undo1")
+				return nil
+			})
+			undo.add(func() error {
+				calls = append(calls, "undo2")
+				return nil
+			})
+			undo.onCommit(func() { calls = append(calls, "commit2") })
+			undo.onRollback(func() { calls = append(calls, "rollback2") })
+			if fail {
+				return deadlock
+			}
+			return nil
+		}
+	}
+
+	// the commit hooks run in the order they're registered after the commit
+	gomock.InOrder(
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.txFactory.EXPECT().Commit(nil).Do(func(interface{}) { calls = append(calls, "committed") }),
+	)
+	err := appFacade.runTx(context.Background(), "default", "test", handler(false))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"committed", "commit1", "commit2"}, calls)
+
+	// the rollback hooks run in the order they're registered after the compensations of each attempt
+	calls = nil
+	gomock.InOrder(
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.txFactory.EXPECT().Rollback(nil).Do(func(interface{}) { calls = append(calls, "rolledback") }),
+		mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil),
+		mAppFacade.txFactory.EXPECT().Rollback(nil).Do(func(interface{}) { calls = append(calls, "rolledback") }),
+	)
+	err = appFacade.runTx(context.Background(), "default", "test", handler(true))
+	assert.Equal(t, deadlock, err)
+	attempt := []string{"rolledback", "undo2", "undo1", "rollback1", "rollback2"}
+	assert.Equal(t, append(append([]string{}, attempt...), attempt...), calls)
+}
+
+func TestCreateApplicationRetry(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		node:      mAppFacade.sNode,
+		app:       mAppFacade.sApp,
+		config:    mAppFacade.sConfig,
+		index:     mAppFacade.sIndex,
+		cron:      mAppFacade.sCron,
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{TxRetry: config.TxRetry{Max: 1, BaseDelay: time.Millisecond}},
+		log:       log.L(),
+	}
+	ns := "baetyl-cloud"
+	app := &specV1.Application{
+		Namespace:  ns,
+		Name:       "abc",
+		Selector:   "a=b",
+		CronStatus: specV1.CronWait,
+		CronTime:   time.Now().Add(time.Hour),
+	}
+
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mAppFacade.txFactory.EXPECT().Rollback(nil).Return()
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return()
+	mAppFacade.sCron.EXPECT().CreateCron(gomock.Any()).Return(nil).Times(2)
+	mAppFacade.sCron.EXPECT().DeleteCron("abc", ns).Return(nil)
+	gomock.InOrder(
+		mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).
+			Return(nil, fmt.Errorf("Error 1213: Deadlock found when trying to get lock; try restarting transaction")),
+		mAppFacade.sApp.EXPECT().CreateWithBase(nil, ns, gomock.Any(), nil).Return(app, nil),
+	)
+	mAppFacade.sNode.EXPECT().UpdateNodeAppVersion(nil, ns, gomock.Any()).Return(nil, nil)
+	mAppFacade.sIndex.EXPECT().RefreshNodesIndexByApp(nil, ns, gomock.Any(), gomock.Any()).Return(nil)
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, nil)
+	assert.NoError(t, err)
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	assert.False(t, isRetryableTxError(nil))
+	assert.False(t, isRetryableTxError(unknownErr))
+	assert.True(t, isRetryableTxError(&mysql.MySQLError{Number: 1213}))
+	assert.True(t, isRetryableTxError(&mysql.MySQLError{Number: 1205}))
+	assert.False(t, isRetryableTxError(&mysql.MySQLError{Number: 1062}))
+	assert.True(t, isRetryableTxError(fmt.Errorf("pq: could not serialize access due to concurrent update")))
+}
+
+func TestRunTxIsolation(t *testing.T) {
+	mAppFacade, mCtl := InitMockEnvironment(t)
+	defer mCtl.Finish()
+	appFacade := &facade{
+		txFactory: mAppFacade.txFactory,
+		conf:      config.Facade{TxIsolation: map[string]string{"CreateApp": "read-committed"}},
+		log:       log.L(),
+	}
+	handler := func(tx interface{}, undo *compensations) error { return nil }
+	mAppFacade.txFactory.EXPECT().Commit(nil).Return().Times(3)
+
+	// the index work runs in serializable
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), &plugin.TxOptions{Isolation: sql.LevelSerializable}).Return(nil, nil)
+	err := appFacade.runTx(context.Background(), "default", "UpdateApp", handler)
+	assert.NoError(t, err)
+
+	// overridden by the config
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), &plugin.TxOptions{Isolation: sql.LevelReadCommitted}).Return(nil, nil)
+	err = appFacade.runTx(context.Background(), "default", "CreateApp", handler)
+	assert.NoError(t, err)
+
+	// the default level of the storage
+	mAppFacade.txFactory.EXPECT().BeginTx(gomock.Any(), &plugin.TxOptions{Isolation: sql.LevelDefault}).Return(nil, nil)
+	err = appFacade.runTx(context.Background(), "default", "DeleteApp", handler)
+	assert.NoError(t, err)
+
+	assert.NoError(t, validTxIsolations(map[string]string{"UpdateApp": "repeatable-read", "DeleteApp": "default"}))
+	assert.Error(t, validTxIsolations(map[string]string{"UpdateApp": "snapshot"}))
+}
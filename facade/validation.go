@@ -0,0 +1,112 @@
+package facade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// FieldError is a problem of a field of the app found by the validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError aggregates all the problems of the app found by the validation, so that they can be
+// fixed at once. It's coded ErrAppValidation, and the message lists all the problems.
+type ValidationError struct {
+	Name   string
+	Errors []FieldError
+}
+
+func (e *ValidationError) Code() string {
+	return common.ErrAppValidation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", fe.Field, fe.Message))
+	}
+	return common.Error(common.ErrAppValidation,
+		common.Field("name", e.Name),
+		common.Field("errors", strings.Join(msgs, "; "))).Error()
+}
+
+// add records the problem of the field found by the check, the errors which aren't coded, e.g. the failures
+// of the stores, are not problems of the app and are returned to fail fast
+func (e *ValidationError) add(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+	coder, ok := err.(errors.Coder)
+	if !ok {
+		return err
+	}
+	e.Errors = append(e.Errors, FieldError{Field: field, Code: coder.Code(), Message: err.Error()})
+	return nil
+}
+
+// fieldCheck checks a field of the app
+type fieldCheck struct {
+	field string
+	check func() error
+}
+
+// validateApp validates the app to be created with its generated configs, *ValidationError listing all the
+// problems of the name, the cron, the referenced configs and secrets and the sizes of the configs is returned
+func (a *facade) validateApp(ns string, app *specV1.Application, configs []specV1.Configuration) error {
+	verr := &ValidationError{Name: app.Name}
+	checks := []fieldCheck{
+		{"name", func() error { return a.validAppName(app.Name) }},
+		{"cron", func() error { return validAppCron(app, true) }},
+		{"volumes.config", func() error { return a.checkConfigRefs(ns, app, configs) }},
+		{"volumes.secret", func() error { return a.checkSecretRefs(ns, app) }},
+	}
+	for i := range configs {
+		cfg := &configs[i]
+		checks = append(checks, fieldCheck{fmt.Sprintf("configs[%s]", cfg.Name), func() error { return a.checkConfigSize(cfg) }})
+	}
+	for _, c := range checks {
+		if err := verr.add(c.field, c.check()); err != nil {
+			return err
+		}
+	}
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// validateApps validates the batch of apps to be created, the problems of all the apps are merged into
+// a single *ValidationError of the batch, whose fields are prefixed with the names of the apps
+func (a *facade) validateApps(ns string, reqs []*AppCreateRequest) error {
+	names := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		names = append(names, req.App.Name)
+	}
+	verr := &ValidationError{Name: strings.Join(names, ",")}
+	for _, req := range reqs {
+		err := a.validateApp(ns, req.App, req.Configs)
+		if err == nil {
+			continue
+		}
+		appErr, ok := err.(*ValidationError)
+		if !ok {
+			return err
+		}
+		for _, fe := range appErr.Errors {
+			fe.Field = fmt.Sprintf("apps[%s].%s", req.App.Name, fe.Field)
+			verr.Errors = append(verr.Errors, fe)
+		}
+	}
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
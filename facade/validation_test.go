@@ -0,0 +1,64 @@
+package facade
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+)
+
+func TestValidateApp(t *testing.T) {
+	appFacade := &facade{conf: config.Facade{ConfigSizeLimit: 4}}
+	ns := "baetyl-cloud"
+
+	// all the problems are reported at once
+	app := &specV1.Application{
+		Namespace:  ns,
+		Name:       "App",
+		CronStatus: specV1.CronWait,
+		CronTime:   time.Now().Add(-time.Hour),
+	}
+	configs := []specV1.Configuration{
+		{Name: "small", Data: map[string]string{"k": "v"}},
+		{Name: "large", Data: map[string]string{"k": strings.Repeat("v", 8)}},
+	}
+	_, err := appFacade.CreateApp(context.Background(), ns, nil, app, configs)
+	assert.Error(t, err)
+	assert.Equal(t, common.ErrAppValidation, err.(errors.Coder).Code())
+	verr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, "App", verr.Name)
+	assert.Len(t, verr.Errors, 3)
+	assert.Equal(t, FieldError{Field: "name", Code: common.ErrInvalidAppName, Message: verr.Errors[0].Message}, verr.Errors[0])
+	assert.Equal(t, "cron", verr.Errors[1].Field)
+	assert.Equal(t, common.ErrInvalidCron, verr.Errors[1].Code)
+	assert.Equal(t, "configs[large]", verr.Errors[2].Field)
+	assert.Equal(t, common.ErrConfigTooLarge, verr.Errors[2].Code)
+	assert.Contains(t, err.Error(), "The app (App) is invalid.")
+	assert.Contains(t, err.Error(), "configs[large]: ")
+
+	// the valid app
+	assert.NoError(t, appFacade.validateApp(ns, &specV1.Application{Namespace: ns, Name: "abc"}, configs[:1]))
+
+	// the problems of all the apps of the batch are reported at once
+	_, err = appFacade.CreateApps(context.Background(), ns, []*AppCreateRequest{
+		{App: &specV1.Application{Namespace: ns, Name: "App"}},
+		{App: &specV1.Application{Namespace: ns, Name: "abc"}, Configs: configs[:1]},
+		{App: &specV1.Application{Namespace: ns, Name: "def"}, Configs: configs},
+	})
+	assert.Equal(t, common.ErrAppValidation, err.(errors.Coder).Code())
+	verr = err.(*ValidationError)
+	assert.Equal(t, "App,abc,def", verr.Name)
+	assert.Len(t, verr.Errors, 2)
+	assert.Equal(t, "apps[App].name", verr.Errors[0].Field)
+	assert.Equal(t, common.ErrInvalidAppName, verr.Errors[0].Code)
+	assert.Equal(t, "apps[def].configs[large]", verr.Errors[1].Field)
+	assert.Equal(t, common.ErrConfigTooLarge, verr.Errors[1].Code)
+}
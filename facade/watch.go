@@ -0,0 +1,105 @@
+package facade
+
+import (
+	"context"
+	"sync"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/baetyl/baetyl-go/v2/log"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+// appWatchBuffer the number of events buffered for a watcher, the events are dropped
+// instead of blocking the facade if the watcher falls behind
+const appWatchBuffer = 64
+
+// appWatchers fans the committed app events out to the watchers of the namespaces,
+// the events of each namespace are numbered in the order they're committed
+type appWatchers struct {
+	mu   sync.Mutex
+	seq  map[string]uint64
+	subs map[string]map[chan models.AppEvent]struct{}
+}
+
+func newAppWatchers() *appWatchers {
+	return &appWatchers{
+		seq:  map[string]uint64{},
+		subs: map[string]map[chan models.AppEvent]struct{}{},
+	}
+}
+
+func (w *appWatchers) subscribe(ns string) chan models.AppEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ch := make(chan models.AppEvent, appWatchBuffer)
+	if w.subs[ns] == nil {
+		w.subs[ns] = map[chan models.AppEvent]struct{}{}
+	}
+	w.subs[ns][ch] = struct{}{}
+	return ch
+}
+
+func (w *appWatchers) unsubscribe(ns string, ch chan models.AppEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.subs[ns], ch)
+	if len(w.subs[ns]) == 0 {
+		delete(w.subs, ns)
+	}
+	close(ch)
+}
+
+// broadcast numbers the event and sends it to the watchers of its namespace,
+// the number of the watchers which the event is dropped for is returned
+func (w *appWatchers) broadcast(event *models.AppEvent) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.seq[event.Namespace]++
+	event.Sequence = w.seq[event.Namespace]
+	dropped := 0
+	for ch := range w.subs[event.Namespace] {
+		select {
+		case ch <- *event:
+		default:
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// WatchApps streams the lifecycle events of the apps of the namespace committed by this facade,
+// the channel is closed when ctx is done. The events are numbered by Sequence per namespace,
+// a gap means the events are dropped since the watcher falls behind, then the apps should be relisted.
+func (a *facade) WatchApps(ctx context.Context, ns string) (<-chan models.AppEvent, error) {
+	if err := a.authorize(ctx, ns); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if a.watchers == nil {
+		return nil, common.Error(common.ErrRequestParamInvalid,
+			common.Field("error", "the watch of apps is not enabled"))
+	}
+	ch := a.watchers.subscribe(ns)
+	go func() {
+		<-ctx.Done()
+		a.watchers.unsubscribe(ns, ch)
+	}()
+	return ch, nil
+}
+
+func (a *facade) broadcastAppEvent(event *models.AppEvent) {
+	if a.watchers == nil {
+		return
+	}
+	if dropped := a.watchers.broadcast(event); dropped > 0 {
+		a.log.Warn("app event dropped for the slow watchers",
+			log.Any(common.KeyContextNamespace, event.Namespace),
+			log.Any("name", event.Name),
+			log.Any("sequence", event.Sequence),
+			log.Any("watchers", dropped))
+	}
+}
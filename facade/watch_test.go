@@ -0,0 +1,63 @@
+package facade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+func TestWatchApps(t *testing.T) {
+	appFacade := &facade{
+		watchers: newAppWatchers(),
+		log:      log.L(),
+	}
+	ns := "baetyl-cloud"
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := appFacade.WatchApps(ctx, ns)
+	assert.NoError(t, err)
+
+	appFacade.publishAppEvent(ctx, models.AppCreated, ns, &specV1.Application{Name: "a", Version: "1"}, nil)
+	appFacade.publishAppEvent(ctx, models.AppCreated, "default", &specV1.Application{Name: "b", Version: "1"}, nil)
+	appFacade.publishAppEvent(ctx, models.AppUpdated, ns, &specV1.Application{Name: "a", Version: "2"}, []string{"n1"})
+
+	e := <-ch
+	assert.Equal(t, "a", e.Name)
+	assert.Equal(t, models.AppCreated, e.Action)
+	assert.Equal(t, uint64(1), e.Sequence)
+	e = <-ch
+	assert.Equal(t, "2", e.Version)
+	assert.Equal(t, []string{"n1"}, e.Nodes)
+	assert.Equal(t, uint64(2), e.Sequence)
+
+	// the events overflowing the buffer are dropped, which is seen as a gap of sequence
+	for i := 0; i < appWatchBuffer+1; i++ {
+		appFacade.publishAppEvent(ctx, models.AppUpdated, ns, &specV1.Application{Name: "a"}, nil)
+	}
+	appFacade.publishAppEvent(ctx, models.AppDeleted, ns, &specV1.Application{Name: "a"}, nil)
+	for i := 0; i < appWatchBuffer; i++ {
+		e = <-ch
+		assert.Equal(t, uint64(i+3), e.Sequence)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("the channel is not closed")
+	}
+	appFacade.watchers.mu.Lock()
+	assert.Empty(t, appFacade.watchers.subs)
+	appFacade.watchers.mu.Unlock()
+
+	_, err = appFacade.WatchApps(ctx, ns)
+	assert.Error(t, err)
+	_, err = (&facade{}).WatchApps(context.Background(), ns)
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	gocontext "context"
 	"runtime"
 
 	"github.com/baetyl/baetyl-go/v2/context"
@@ -10,15 +11,19 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/api"
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/awss3"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/database"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/decryption"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/auth"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/csrf"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/event"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/keyprovider"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/license"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/lock"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/pki"
+	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/ratelimit"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/sign"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/task"
 	_ "github.com/baetyl/baetyl-cloud/v2/plugin/default/transaction"
@@ -62,9 +67,39 @@ func main() {
 		s.SetAPI(a)
 		s.InitRoute()
 		go s.Run()
+		// the facade is drained after the admin server stops accepting the requests
+		defer func() {
+			drainCtx, cancel := gocontext.WithTimeout(gocontext.Background(), cfg.AdminServer.ShutdownTime)
+			defer cancel()
+			if err := a.Facade.Close(drainCtx); err != nil {
+				ctx.Log().Warn("failed to drain the facade", log.Error(err))
+			}
+		}()
 		defer s.Close()
 		ctx.Log().Info("admin server starting")
 
+		jobCtx, cancel := gocontext.WithCancel(gocontext.Background())
+		defer cancel()
+		go facade.RunPeriodically(jobCtx, "gc idempotency keys", cfg.Facade.Idempotency.GCInterval, a.Facade.GCIdempotencyKeys)
+		if cfg.Facade.SoftDelete.Enabled {
+			go facade.RunPeriodically(jobCtx, "purge deleted apps", cfg.Facade.SoftDelete.PurgeInterval, a.Facade.PurgeDeletedApps)
+		}
+		if cfg.Facade.Outbox.Enabled {
+			go facade.RunPeriodically(jobCtx, "relay app outbox", cfg.Facade.Outbox.RelayInterval, a.Facade.RelayAppOutbox)
+			if cfg.Facade.Outbox.Cron {
+				go facade.RunPeriodically(jobCtx, "relay cron outbox", cfg.Facade.Outbox.RelayInterval, a.Facade.RelayCronOutbox)
+			}
+		}
+		if cfg.Facade.Activation.Enabled {
+			go facade.RunPeriodically(jobCtx, "activate due apps", cfg.Facade.Activation.Interval, a.Facade.ActivateDueApps)
+		}
+		if cfg.Facade.CronScheduler.Enabled {
+			go facade.RunPeriodically(jobCtx, "fire due crons", cfg.Facade.CronScheduler.Interval, a.Facade.FireDueCrons)
+		}
+		if cfg.Facade.Rollout.Enabled {
+			go facade.RunPeriodically(jobCtx, "advance rollouts", cfg.Facade.Rollout.Interval, a.Facade.AdvanceRollouts)
+		}
+
 		ss, err := server.NewSyncServer(&cfg)
 		if err != nil {
 			return err
@@ -5,6 +5,9 @@
 package facade
 
 import (
+	context "context"
+	facade "github.com/baetyl/baetyl-cloud/v2/facade"
+	models "github.com/baetyl/baetyl-cloud/v2/models"
 	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
@@ -33,149 +36,1069 @@ func (m *MockFacade) EXPECT() *MockFacadeMockRecorder {
 	return m.recorder
 }
 
-// CreateApp mocks base method
-func (m *MockFacade) CreateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v1.Configuration) (*v1.Application, error) {
+// ActivateDueApps mocks base method
+func (m *MockFacade) ActivateDueApps(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateDueApps", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ActivateDueApps indicates an expected call of ActivateDueApps
+func (mr *MockFacadeMockRecorder) ActivateDueApps(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateDueApps", reflect.TypeOf((*MockFacade)(nil).ActivateDueApps), arg0)
+}
+
+// AdvanceRollout mocks base method
+func (m *MockFacade) AdvanceRollout(arg0 context.Context, arg1, arg2 string) (*facade.RolloutProgress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceRollout", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*facade.RolloutProgress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceRollout indicates an expected call of AdvanceRollout
+func (mr *MockFacadeMockRecorder) AdvanceRollout(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceRollout", reflect.TypeOf((*MockFacade)(nil).AdvanceRollout), arg0, arg1, arg2)
+}
+
+// AdvanceRollouts mocks base method
+func (m *MockFacade) AdvanceRollouts(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceRollouts", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdvanceRollouts indicates an expected call of AdvanceRollouts
+func (mr *MockFacadeMockRecorder) AdvanceRollouts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceRollouts", reflect.TypeOf((*MockFacade)(nil).AdvanceRollouts), arg0)
+}
+
+// ApplyApp mocks base method
+func (m *MockFacade) ApplyApp(arg0 context.Context, arg1 string, arg2 *v1.Application, arg3 []v1.Configuration) (*v1.Application, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyApp", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ApplyApp indicates an expected call of ApplyApp
+func (mr *MockFacadeMockRecorder) ApplyApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyApp", reflect.TypeOf((*MockFacade)(nil).ApplyApp), arg0, arg1, arg2, arg3)
+}
+
+// CanaryRollout mocks base method
+func (m *MockFacade) CanaryRollout(arg0 context.Context, arg1, arg2 string, arg3 int) (*v1.Application, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateApp", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "CanaryRollout", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*v1.Application)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// CanaryRollout indicates an expected call of CanaryRollout
+func (mr *MockFacadeMockRecorder) CanaryRollout(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanaryRollout", reflect.TypeOf((*MockFacade)(nil).CanaryRollout), arg0, arg1, arg2, arg3)
+}
+
+// CancelAppActivation mocks base method
+func (m *MockFacade) CancelAppActivation(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelAppActivation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelAppActivation indicates an expected call of CancelAppActivation
+func (mr *MockFacadeMockRecorder) CancelAppActivation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelAppActivation", reflect.TypeOf((*MockFacade)(nil).CancelAppActivation), arg0, arg1, arg2)
+}
+
+// CloneApp mocks base method
+func (m *MockFacade) CloneApp(arg0 context.Context, arg1, arg2, arg3, arg4, arg5 string, arg6 facade.CloneConflictPolicy) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloneApp", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloneApp indicates an expected call of CloneApp
+func (mr *MockFacadeMockRecorder) CloneApp(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloneApp", reflect.TypeOf((*MockFacade)(nil).CloneApp), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+}
+
+// Close mocks base method
+func (m *MockFacade) Close(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockFacadeMockRecorder) Close(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockFacade)(nil).Close), arg0)
+}
+
+// CreateApp mocks base method
+func (m *MockFacade) CreateApp(arg0 context.Context, arg1 string, arg2, arg3 *v1.Application, arg4 []v1.Configuration) (*facade.AppResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApp", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*facade.AppResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // CreateApp indicates an expected call of CreateApp
-func (mr *MockFacadeMockRecorder) CreateApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) CreateApp(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApp", reflect.TypeOf((*MockFacade)(nil).CreateApp), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CreateApps mocks base method
+func (m *MockFacade) CreateApps(arg0 context.Context, arg1 string, arg2 []*facade.AppCreateRequest) ([]*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApps", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApps indicates an expected call of CreateApps
+func (mr *MockFacadeMockRecorder) CreateApps(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApp", reflect.TypeOf((*MockFacade)(nil).CreateApp), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApps", reflect.TypeOf((*MockFacade)(nil).CreateApps), arg0, arg1, arg2)
 }
 
 // CreateConfig mocks base method
-func (m *MockFacade) CreateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Configuration, error) {
+func (m *MockFacade) CreateConfig(arg0 context.Context, arg1 string, arg2 *v1.Configuration) (*v1.Configuration, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateConfig", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateConfig", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*v1.Configuration)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateConfig indicates an expected call of CreateConfig
-func (mr *MockFacadeMockRecorder) CreateConfig(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) CreateConfig(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfig", reflect.TypeOf((*MockFacade)(nil).CreateConfig), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfig", reflect.TypeOf((*MockFacade)(nil).CreateConfig), arg0, arg1, arg2)
 }
 
 // CreateSecret mocks base method
-func (m *MockFacade) CreateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
+func (m *MockFacade) CreateSecret(arg0 context.Context, arg1 string, arg2 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSecret", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateSecret", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*v1.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSecret indicates an expected call of CreateSecret
-func (mr *MockFacadeMockRecorder) CreateSecret(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) CreateSecret(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecret", reflect.TypeOf((*MockFacade)(nil).CreateSecret), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecret", reflect.TypeOf((*MockFacade)(nil).CreateSecret), arg0, arg1, arg2)
 }
 
 // DeleteApp mocks base method
-func (m *MockFacade) DeleteApp(arg0, arg1 string, arg2 *v1.Application) error {
+func (m *MockFacade) DeleteApp(arg0 context.Context, arg1, arg2 string, arg3 *v1.Application) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteApp", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "DeleteApp", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteApp indicates an expected call of DeleteApp
-func (mr *MockFacadeMockRecorder) DeleteApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) DeleteApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApp", reflect.TypeOf((*MockFacade)(nil).DeleteApp), arg0, arg1, arg2, arg3)
+}
+
+// DeleteApps mocks base method
+func (m *MockFacade) DeleteApps(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteApps", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteApps indicates an expected call of DeleteApps
+func (mr *MockFacadeMockRecorder) DeleteApps(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApp", reflect.TypeOf((*MockFacade)(nil).DeleteApp), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApps", reflect.TypeOf((*MockFacade)(nil).DeleteApps), arg0, arg1, arg2)
 }
 
 // DeleteConfig mocks base method
-func (m *MockFacade) DeleteConfig(arg0, arg1 string) error {
+func (m *MockFacade) DeleteConfig(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteConfig", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteConfig", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteConfig indicates an expected call of DeleteConfig
-func (mr *MockFacadeMockRecorder) DeleteConfig(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) DeleteConfig(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfig", reflect.TypeOf((*MockFacade)(nil).DeleteConfig), arg0, arg1, arg2)
+}
+
+// DeleteNamespaceApps mocks base method
+func (m *MockFacade) DeleteNamespaceApps(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNamespaceApps", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNamespaceApps indicates an expected call of DeleteNamespaceApps
+func (mr *MockFacadeMockRecorder) DeleteNamespaceApps(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfig", reflect.TypeOf((*MockFacade)(nil).DeleteConfig), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNamespaceApps", reflect.TypeOf((*MockFacade)(nil).DeleteNamespaceApps), arg0, arg1)
 }
 
 // DeleteSecret mocks base method
-func (m *MockFacade) DeleteSecret(arg0, arg1 string) error {
+func (m *MockFacade) DeleteSecret(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSecret", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteSecret", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSecret indicates an expected call of DeleteSecret
-func (mr *MockFacadeMockRecorder) DeleteSecret(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) DeleteSecret(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockFacade)(nil).DeleteSecret), arg0, arg1, arg2)
+}
+
+// DescribeAppDeletion mocks base method
+func (m *MockFacade) DescribeAppDeletion(arg0 context.Context, arg1, arg2 string) (*facade.DeletionPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAppDeletion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*facade.DeletionPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAppDeletion indicates an expected call of DescribeAppDeletion
+func (mr *MockFacadeMockRecorder) DescribeAppDeletion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAppDeletion", reflect.TypeOf((*MockFacade)(nil).DescribeAppDeletion), arg0, arg1, arg2)
+}
+
+// DiffApp mocks base method
+func (m *MockFacade) DiffApp(arg0 context.Context, arg1, arg2, arg3, arg4 string) (*facade.AppDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffApp", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*facade.AppDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffApp indicates an expected call of DiffApp
+func (mr *MockFacadeMockRecorder) DiffApp(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffApp", reflect.TypeOf((*MockFacade)(nil).DiffApp), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ExportApp mocks base method
+func (m *MockFacade) ExportApp(arg0 context.Context, arg1, arg2, arg3 string, arg4 bool) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportApp", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportApp indicates an expected call of ExportApp
+func (mr *MockFacadeMockRecorder) ExportApp(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportApp", reflect.TypeOf((*MockFacade)(nil).ExportApp), arg0, arg1, arg2, arg3, arg4)
+}
+
+// FanOutApp mocks base method
+func (m *MockFacade) FanOutApp(arg0 context.Context, arg1 *v1.Application, arg2 []v1.Configuration, arg3 []string) (map[string]*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FanOutApp", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(map[string]*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FanOutApp indicates an expected call of FanOutApp
+func (mr *MockFacadeMockRecorder) FanOutApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FanOutApp", reflect.TypeOf((*MockFacade)(nil).FanOutApp), arg0, arg1, arg2, arg3)
+}
+
+// FireDueCrons mocks base method
+func (m *MockFacade) FireDueCrons(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FireDueCrons", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FireDueCrons indicates an expected call of FireDueCrons
+func (mr *MockFacadeMockRecorder) FireDueCrons(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FireDueCrons", reflect.TypeOf((*MockFacade)(nil).FireDueCrons), arg0)
+}
+
+// FreezeApp mocks base method
+func (m *MockFacade) FreezeApp(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FreezeApp indicates an expected call of FreezeApp
+func (mr *MockFacadeMockRecorder) FreezeApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeApp", reflect.TypeOf((*MockFacade)(nil).FreezeApp), arg0, arg1, arg2)
+}
+
+// GCIdempotencyKeys mocks base method
+func (m *MockFacade) GCIdempotencyKeys(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GCIdempotencyKeys", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GCIdempotencyKeys indicates an expected call of GCIdempotencyKeys
+func (mr *MockFacadeMockRecorder) GCIdempotencyKeys(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockFacade)(nil).DeleteSecret), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GCIdempotencyKeys", reflect.TypeOf((*MockFacade)(nil).GCIdempotencyKeys), arg0)
 }
 
 // GetApp mocks base method
-func (m *MockFacade) GetApp(arg0, arg1, arg2 string) (*v1.Application, error) {
+func (m *MockFacade) GetApp(arg0 context.Context, arg1, arg2, arg3 string) (*v1.Application, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetApp", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetApp", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*v1.Application)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetApp indicates an expected call of GetApp
-func (mr *MockFacadeMockRecorder) GetApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) GetApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApp", reflect.TypeOf((*MockFacade)(nil).GetApp), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApp", reflect.TypeOf((*MockFacade)(nil).GetApp), arg0, arg1, arg2, arg3)
 }
 
-// UpdateApp mocks base method
-func (m *MockFacade) UpdateApp(arg0 string, arg1, arg2 *v1.Application, arg3 []v1.Configuration) (*v1.Application, error) {
+// GetAppStatus mocks base method
+func (m *MockFacade) GetAppStatus(arg0 context.Context, arg1, arg2, arg3 string) (*facade.AppRolloutStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*facade.AppRolloutStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppStatus indicates an expected call of GetAppStatus
+func (mr *MockFacadeMockRecorder) GetAppStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppStatus", reflect.TypeOf((*MockFacade)(nil).GetAppStatus), arg0, arg1, arg2, arg3)
+}
+
+// GetApps mocks base method
+func (m *MockFacade) GetApps(arg0 context.Context, arg1 string, arg2 []models.AppRef) ([]*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApps", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApps indicates an expected call of GetApps
+func (mr *MockFacadeMockRecorder) GetApps(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApps", reflect.TypeOf((*MockFacade)(nil).GetApps), arg0, arg1, arg2)
+}
+
+// GetCanaryStatus mocks base method
+func (m *MockFacade) GetCanaryStatus(arg0 context.Context, arg1, arg2 string) (*facade.CanaryStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCanaryStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*facade.CanaryStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCanaryStatus indicates an expected call of GetCanaryStatus
+func (mr *MockFacadeMockRecorder) GetCanaryStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCanaryStatus", reflect.TypeOf((*MockFacade)(nil).GetCanaryStatus), arg0, arg1, arg2)
+}
+
+// HealthCheck mocks base method
+func (m *MockFacade) HealthCheck(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthCheck", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HealthCheck indicates an expected call of HealthCheck
+func (mr *MockFacadeMockRecorder) HealthCheck(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockFacade)(nil).HealthCheck), arg0)
+}
+
+// ImportApp mocks base method
+func (m *MockFacade) ImportApp(arg0 context.Context, arg1 string, arg2 []byte, arg3 facade.ImportOptions) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportApp", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportApp indicates an expected call of ImportApp
+func (mr *MockFacadeMockRecorder) ImportApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportApp", reflect.TypeOf((*MockFacade)(nil).ImportApp), arg0, arg1, arg2, arg3)
+}
+
+// InstantiateTemplate mocks base method
+func (m *MockFacade) InstantiateTemplate(arg0 context.Context, arg1, arg2 string, arg3 map[string]string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InstantiateTemplate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InstantiateTemplate indicates an expected call of InstantiateTemplate
+func (mr *MockFacadeMockRecorder) InstantiateTemplate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstantiateTemplate", reflect.TypeOf((*MockFacade)(nil).InstantiateTemplate), arg0, arg1, arg2, arg3)
+}
+
+// LeadCron mocks base method
+func (m *MockFacade) LeadCron(arg0 context.Context, arg1 func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeadCron", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeadCron indicates an expected call of LeadCron
+func (mr *MockFacadeMockRecorder) LeadCron(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeadCron", reflect.TypeOf((*MockFacade)(nil).LeadCron), arg0, arg1)
+}
+
+// ListAppAudit mocks base method
+func (m *MockFacade) ListAppAudit(arg0 context.Context, arg1, arg2 string) ([]models.AppAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppAudit", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.AppAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppAudit indicates an expected call of ListAppAudit
+func (mr *MockFacadeMockRecorder) ListAppAudit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppAudit", reflect.TypeOf((*MockFacade)(nil).ListAppAudit), arg0, arg1, arg2)
+}
+
+// ListAppNodes mocks base method
+func (m *MockFacade) ListAppNodes(arg0 context.Context, arg1, arg2 string, arg3 *models.ListOptions) (*models.AppNodeList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppNodes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.AppNodeList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppNodes indicates an expected call of ListAppNodes
+func (mr *MockFacadeMockRecorder) ListAppNodes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppNodes", reflect.TypeOf((*MockFacade)(nil).ListAppNodes), arg0, arg1, arg2, arg3)
+}
+
+// ListAppNodesByLabel mocks base method
+func (m *MockFacade) ListAppNodesByLabel(arg0 context.Context, arg1, arg2, arg3 string) ([]models.AppNodeBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppNodesByLabel", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.AppNodeBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppNodesByLabel indicates an expected call of ListAppNodesByLabel
+func (mr *MockFacadeMockRecorder) ListAppNodesByLabel(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppNodesByLabel", reflect.TypeOf((*MockFacade)(nil).ListAppNodesByLabel), arg0, arg1, arg2, arg3)
+}
+
+// ListApps mocks base method
+func (m *MockFacade) ListApps(arg0 context.Context, arg1 string, arg2 *models.ListOptions) (*models.ApplicationList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApps", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ApplicationList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApps indicates an expected call of ListApps
+func (mr *MockFacadeMockRecorder) ListApps(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApps", reflect.TypeOf((*MockFacade)(nil).ListApps), arg0, arg1, arg2)
+}
+
+// ListCronRuns mocks base method
+func (m *MockFacade) ListCronRuns(arg0 context.Context, arg1, arg2 string, arg3 *models.ListOptions) (*models.CronRunList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCronRuns", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.CronRunList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCronRuns indicates an expected call of ListCronRuns
+func (mr *MockFacadeMockRecorder) ListCronRuns(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCronRuns", reflect.TypeOf((*MockFacade)(nil).ListCronRuns), arg0, arg1, arg2, arg3)
+}
+
+// ListNodeApps mocks base method
+func (m *MockFacade) ListNodeApps(arg0 context.Context, arg1, arg2 string, arg3 *models.ListOptions) (*models.NodeAppList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNodeApps", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.NodeAppList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNodeApps indicates an expected call of ListNodeApps
+func (mr *MockFacadeMockRecorder) ListNodeApps(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodeApps", reflect.TypeOf((*MockFacade)(nil).ListNodeApps), arg0, arg1, arg2, arg3)
+}
+
+// ListPinnedAppVersions mocks base method
+func (m *MockFacade) ListPinnedAppVersions(arg0 context.Context, arg1, arg2 string) ([]models.AppVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPinnedAppVersions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.AppVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPinnedAppVersions indicates an expected call of ListPinnedAppVersions
+func (mr *MockFacadeMockRecorder) ListPinnedAppVersions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPinnedAppVersions", reflect.TypeOf((*MockFacade)(nil).ListPinnedAppVersions), arg0, arg1, arg2)
+}
+
+// PatchApp mocks base method
+func (m *MockFacade) PatchApp(arg0 context.Context, arg1, arg2 string, arg3 []byte, arg4 facade.PatchType) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchApp", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchApp indicates an expected call of PatchApp
+func (mr *MockFacadeMockRecorder) PatchApp(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchApp", reflect.TypeOf((*MockFacade)(nil).PatchApp), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PauseCronApp mocks base method
+func (m *MockFacade) PauseCronApp(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseCronApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseCronApp indicates an expected call of PauseCronApp
+func (mr *MockFacadeMockRecorder) PauseCronApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseCronApp", reflect.TypeOf((*MockFacade)(nil).PauseCronApp), arg0, arg1, arg2)
+}
+
+// PinAppVersion mocks base method
+func (m *MockFacade) PinAppVersion(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinAppVersion", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinAppVersion indicates an expected call of PinAppVersion
+func (mr *MockFacadeMockRecorder) PinAppVersion(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinAppVersion", reflect.TypeOf((*MockFacade)(nil).PinAppVersion), arg0, arg1, arg2, arg3)
+}
+
+// PreviewApp mocks base method
+func (m *MockFacade) PreviewApp(arg0 context.Context, arg1 string, arg2 *v1.Application, arg3 []v1.Configuration) (*facade.AppPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewApp", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*facade.AppPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewApp indicates an expected call of PreviewApp
+func (mr *MockFacadeMockRecorder) PreviewApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewApp", reflect.TypeOf((*MockFacade)(nil).PreviewApp), arg0, arg1, arg2, arg3)
+}
+
+// PromoteApp mocks base method
+func (m *MockFacade) PromoteApp(arg0 context.Context, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromoteApp indicates an expected call of PromoteApp
+func (mr *MockFacadeMockRecorder) PromoteApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteApp", reflect.TypeOf((*MockFacade)(nil).PromoteApp), arg0, arg1, arg2)
+}
+
+// PurgeDeletedApps mocks base method
+func (m *MockFacade) PurgeDeletedApps(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedApps", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeDeletedApps indicates an expected call of PurgeDeletedApps
+func (mr *MockFacadeMockRecorder) PurgeDeletedApps(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedApps", reflect.TypeOf((*MockFacade)(nil).PurgeDeletedApps), arg0)
+}
+
+// ReclaimFunctionConfigs mocks base method
+func (m *MockFacade) ReclaimFunctionConfigs(arg0 context.Context, arg1 string, arg2 bool) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReclaimFunctionConfigs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReclaimFunctionConfigs indicates an expected call of ReclaimFunctionConfigs
+func (mr *MockFacadeMockRecorder) ReclaimFunctionConfigs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReclaimFunctionConfigs", reflect.TypeOf((*MockFacade)(nil).ReclaimFunctionConfigs), arg0, arg1, arg2)
+}
+
+// ReconcileNodeIndexes mocks base method
+func (m *MockFacade) ReconcileNodeIndexes(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileNodeIndexes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReconcileNodeIndexes indicates an expected call of ReconcileNodeIndexes
+func (mr *MockFacadeMockRecorder) ReconcileNodeIndexes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileNodeIndexes", reflect.TypeOf((*MockFacade)(nil).ReconcileNodeIndexes), arg0, arg1, arg2)
+}
+
+// ReconcileReport mocks base method
+func (m *MockFacade) ReconcileReport(arg0 context.Context, arg1 string) (*facade.NamespaceReconcileReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileReport", arg0, arg1)
+	ret0, _ := ret[0].(*facade.NamespaceReconcileReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileReport indicates an expected call of ReconcileReport
+func (mr *MockFacadeMockRecorder) ReconcileReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileReport", reflect.TypeOf((*MockFacade)(nil).ReconcileReport), arg0, arg1)
+}
+
+// RefreshNodeIndexesForNode mocks base method
+func (m *MockFacade) RefreshNodeIndexesForNode(arg0 context.Context, arg1, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshNodeIndexesForNode", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshNodeIndexesForNode indicates an expected call of RefreshNodeIndexesForNode
+func (mr *MockFacadeMockRecorder) RefreshNodeIndexesForNode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshNodeIndexesForNode", reflect.TypeOf((*MockFacade)(nil).RefreshNodeIndexesForNode), arg0, arg1, arg2)
+}
+
+// RegisterAppHook mocks base method
+func (m *MockFacade) RegisterAppHook(arg0 facade.AppHook) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterAppHook", arg0)
+}
+
+// RegisterAppHook indicates an expected call of RegisterAppHook
+func (mr *MockFacadeMockRecorder) RegisterAppHook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterAppHook", reflect.TypeOf((*MockFacade)(nil).RegisterAppHook), arg0)
+}
+
+// RelayAppOutbox mocks base method
+func (m *MockFacade) RelayAppOutbox(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayAppOutbox", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RelayAppOutbox indicates an expected call of RelayAppOutbox
+func (mr *MockFacadeMockRecorder) RelayAppOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayAppOutbox", reflect.TypeOf((*MockFacade)(nil).RelayAppOutbox), arg0)
+}
+
+// RelayCronOutbox mocks base method
+func (m *MockFacade) RelayCronOutbox(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayCronOutbox", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RelayCronOutbox indicates an expected call of RelayCronOutbox
+func (mr *MockFacadeMockRecorder) RelayCronOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayCronOutbox", reflect.TypeOf((*MockFacade)(nil).RelayCronOutbox), arg0)
+}
+
+// RepairAppIndex mocks base method
+func (m *MockFacade) RepairAppIndex(arg0 context.Context, arg1 string) (*facade.IndexReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairAppIndex", arg0, arg1)
+	ret0, _ := ret[0].(*facade.IndexReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairAppIndex indicates an expected call of RepairAppIndex
+func (mr *MockFacadeMockRecorder) RepairAppIndex(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairAppIndex", reflect.TypeOf((*MockFacade)(nil).RepairAppIndex), arg0, arg1)
+}
+
+// RepairCronApps mocks base method
+func (m *MockFacade) RepairCronApps(arg0 context.Context, arg1 string) (*facade.CronReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RepairCronApps", arg0, arg1)
+	ret0, _ := ret[0].(*facade.CronReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RepairCronApps indicates an expected call of RepairCronApps
+func (mr *MockFacadeMockRecorder) RepairCronApps(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RepairCronApps", reflect.TypeOf((*MockFacade)(nil).RepairCronApps), arg0, arg1)
+}
+
+// ResolveCronSelector mocks base method
+func (m *MockFacade) ResolveCronSelector(arg0 context.Context, arg1, arg2 string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateApp", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "ResolveCronSelector", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveCronSelector indicates an expected call of ResolveCronSelector
+func (mr *MockFacadeMockRecorder) ResolveCronSelector(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveCronSelector", reflect.TypeOf((*MockFacade)(nil).ResolveCronSelector), arg0, arg1, arg2)
+}
+
+// ResolveSelector mocks base method
+func (m *MockFacade) ResolveSelector(arg0 context.Context, arg1, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveSelector", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveSelector indicates an expected call of ResolveSelector
+func (mr *MockFacadeMockRecorder) ResolveSelector(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveSelector", reflect.TypeOf((*MockFacade)(nil).ResolveSelector), arg0, arg1, arg2)
+}
+
+// RestoreApp mocks base method
+func (m *MockFacade) RestoreApp(arg0 context.Context, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreApp indicates an expected call of RestoreApp
+func (mr *MockFacadeMockRecorder) RestoreApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreApp", reflect.TypeOf((*MockFacade)(nil).RestoreApp), arg0, arg1, arg2)
+}
+
+// ResumeCronApp mocks base method
+func (m *MockFacade) ResumeCronApp(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeCronApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeCronApp indicates an expected call of ResumeCronApp
+func (mr *MockFacadeMockRecorder) ResumeCronApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeCronApp", reflect.TypeOf((*MockFacade)(nil).ResumeCronApp), arg0, arg1, arg2)
+}
+
+// RollbackApp mocks base method
+func (m *MockFacade) RollbackApp(arg0 context.Context, arg1, arg2, arg3 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackApp", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(*v1.Application)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// RollbackApp indicates an expected call of RollbackApp
+func (mr *MockFacadeMockRecorder) RollbackApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackApp", reflect.TypeOf((*MockFacade)(nil).RollbackApp), arg0, arg1, arg2, arg3)
+}
+
+// SetAuthorizer mocks base method
+func (m *MockFacade) SetAuthorizer(arg0 facade.Authorizer) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetAuthorizer", arg0)
+}
+
+// SetAuthorizer indicates an expected call of SetAuthorizer
+func (mr *MockFacadeMockRecorder) SetAuthorizer(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAuthorizer", reflect.TypeOf((*MockFacade)(nil).SetAuthorizer), arg0)
+}
+
+// SetSelectorResolver mocks base method
+func (m *MockFacade) SetSelectorResolver(arg0 string, arg1 facade.SelectorResolver) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSelectorResolver", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSelectorResolver indicates an expected call of SetSelectorResolver
+func (mr *MockFacadeMockRecorder) SetSelectorResolver(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSelectorResolver", reflect.TypeOf((*MockFacade)(nil).SetSelectorResolver), arg0, arg1)
+}
+
+// SwapApps mocks base method
+func (m *MockFacade) SwapApps(arg0 context.Context, arg1, arg2, arg3 string) ([]*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwapApps", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwapApps indicates an expected call of SwapApps
+func (mr *MockFacadeMockRecorder) SwapApps(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwapApps", reflect.TypeOf((*MockFacade)(nil).SwapApps), arg0, arg1, arg2, arg3)
+}
+
+// TriggerCronApp mocks base method
+func (m *MockFacade) TriggerCronApp(arg0 context.Context, arg1, arg2 string) (*v1.Application, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TriggerCronApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TriggerCronApp indicates an expected call of TriggerCronApp
+func (mr *MockFacadeMockRecorder) TriggerCronApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerCronApp", reflect.TypeOf((*MockFacade)(nil).TriggerCronApp), arg0, arg1, arg2)
+}
+
+// UnfreezeApp mocks base method
+func (m *MockFacade) UnfreezeApp(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfreezeApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfreezeApp indicates an expected call of UnfreezeApp
+func (mr *MockFacadeMockRecorder) UnfreezeApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfreezeApp", reflect.TypeOf((*MockFacade)(nil).UnfreezeApp), arg0, arg1, arg2)
+}
+
+// UnpinAppVersion mocks base method
+func (m *MockFacade) UnpinAppVersion(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinAppVersion", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinAppVersion indicates an expected call of UnpinAppVersion
+func (mr *MockFacadeMockRecorder) UnpinAppVersion(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinAppVersion", reflect.TypeOf((*MockFacade)(nil).UnpinAppVersion), arg0, arg1, arg2, arg3)
+}
+
+// UpdateApp mocks base method
+func (m *MockFacade) UpdateApp(arg0 context.Context, arg1 string, arg2, arg3 *v1.Application, arg4 []v1.Configuration) (*facade.AppResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApp", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*facade.AppResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateApp indicates an expected call of UpdateApp
-func (mr *MockFacadeMockRecorder) UpdateApp(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) UpdateApp(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApp", reflect.TypeOf((*MockFacade)(nil).UpdateApp), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApp", reflect.TypeOf((*MockFacade)(nil).UpdateApp), arg0, arg1, arg2, arg3, arg4)
 }
 
 // UpdateConfig mocks base method
-func (m *MockFacade) UpdateConfig(arg0 string, arg1 *v1.Configuration) (*v1.Configuration, error) {
+func (m *MockFacade) UpdateConfig(arg0 context.Context, arg1 string, arg2 *v1.Configuration) (*v1.Configuration, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateConfig", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateConfig", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*v1.Configuration)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateConfig indicates an expected call of UpdateConfig
-func (mr *MockFacadeMockRecorder) UpdateConfig(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) UpdateConfig(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockFacade)(nil).UpdateConfig), arg0, arg1, arg2)
+}
+
+// UpdateNode mocks base method
+func (m *MockFacade) UpdateNode(arg0 context.Context, arg1 string, arg2 *v1.Node) (*v1.Node, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Node)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNode indicates an expected call of UpdateNode
+func (mr *MockFacadeMockRecorder) UpdateNode(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfig", reflect.TypeOf((*MockFacade)(nil).UpdateConfig), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNode", reflect.TypeOf((*MockFacade)(nil).UpdateNode), arg0, arg1, arg2)
 }
 
 // UpdateSecret mocks base method
-func (m *MockFacade) UpdateSecret(arg0 string, arg1 *v1.Secret) (*v1.Secret, error) {
+func (m *MockFacade) UpdateSecret(arg0 context.Context, arg1 string, arg2 *v1.Secret) (*v1.Secret, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSecret", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateSecret", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*v1.Secret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateSecret indicates an expected call of UpdateSecret
-func (mr *MockFacadeMockRecorder) UpdateSecret(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockFacadeMockRecorder) UpdateSecret(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockFacade)(nil).UpdateSecret), arg0, arg1, arg2)
+}
+
+// VerifyAppIndex mocks base method
+func (m *MockFacade) VerifyAppIndex(arg0 context.Context, arg1 string) (*facade.IndexReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAppIndex", arg0, arg1)
+	ret0, _ := ret[0].(*facade.IndexReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAppIndex indicates an expected call of VerifyAppIndex
+func (mr *MockFacadeMockRecorder) VerifyAppIndex(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAppIndex", reflect.TypeOf((*MockFacade)(nil).VerifyAppIndex), arg0, arg1)
+}
+
+// VerifyCronApps mocks base method
+func (m *MockFacade) VerifyCronApps(arg0 context.Context, arg1 string) (*facade.CronReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyCronApps", arg0, arg1)
+	ret0, _ := ret[0].(*facade.CronReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyCronApps indicates an expected call of VerifyCronApps
+func (mr *MockFacadeMockRecorder) VerifyCronApps(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyCronApps", reflect.TypeOf((*MockFacade)(nil).VerifyCronApps), arg0, arg1)
+}
+
+// VerifyFunctionConfigs mocks base method
+func (m *MockFacade) VerifyFunctionConfigs(arg0 context.Context, arg1, arg2 string) (*facade.FunctionConfigReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyFunctionConfigs", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*facade.FunctionConfigReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyFunctionConfigs indicates an expected call of VerifyFunctionConfigs
+func (mr *MockFacadeMockRecorder) VerifyFunctionConfigs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyFunctionConfigs", reflect.TypeOf((*MockFacade)(nil).VerifyFunctionConfigs), arg0, arg1, arg2)
+}
+
+// WatchApps mocks base method
+func (m *MockFacade) WatchApps(arg0 context.Context, arg1 string) (<-chan models.AppEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchApps", arg0, arg1)
+	ret0, _ := ret[0].(<-chan models.AppEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WatchApps indicates an expected call of WatchApps
+func (mr *MockFacadeMockRecorder) WatchApps(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSecret", reflect.TypeOf((*MockFacade)(nil).UpdateSecret), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchApps", reflect.TypeOf((*MockFacade)(nil).WatchApps), arg0, arg1)
 }
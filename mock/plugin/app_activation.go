@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: AppActivation)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockAppActivation is a mock of AppActivation interface
+type MockAppActivation struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppActivationMockRecorder
+}
+
+// MockAppActivationMockRecorder is the mock recorder for MockAppActivation
+type MockAppActivationMockRecorder struct {
+	mock *MockAppActivation
+}
+
+// NewMockAppActivation creates a new mock instance
+func NewMockAppActivation(ctrl *gomock.Controller) *MockAppActivation {
+	mock := &MockAppActivation{ctrl: ctrl}
+	mock.recorder = &MockAppActivationMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppActivation) EXPECT() *MockAppActivationMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockAppActivation) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockAppActivationMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAppActivation)(nil).Close))
+}
+
+// CreateAppActivation mocks base method
+func (m *MockAppActivation) CreateAppActivation(arg0 interface{}, arg1 *models.AppActivation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppActivation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppActivation indicates an expected call of CreateAppActivation
+func (mr *MockAppActivationMockRecorder) CreateAppActivation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppActivation", reflect.TypeOf((*MockAppActivation)(nil).CreateAppActivation), arg0, arg1)
+}
+
+// DeleteAppActivation mocks base method
+func (m *MockAppActivation) DeleteAppActivation(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppActivation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppActivation indicates an expected call of DeleteAppActivation
+func (mr *MockAppActivationMockRecorder) DeleteAppActivation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppActivation", reflect.TypeOf((*MockAppActivation)(nil).DeleteAppActivation), arg0, arg1, arg2)
+}
+
+// GetAppActivation mocks base method
+func (m *MockAppActivation) GetAppActivation(arg0, arg1 string) (*models.AppActivation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppActivation", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppActivation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppActivation indicates an expected call of GetAppActivation
+func (mr *MockAppActivationMockRecorder) GetAppActivation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppActivation", reflect.TypeOf((*MockAppActivation)(nil).GetAppActivation), arg0, arg1)
+}
+
+// ListDueAppActivations mocks base method
+func (m *MockAppActivation) ListDueAppActivations(arg0 time.Time, arg1 int) ([]models.AppActivation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueAppActivations", arg0, arg1)
+	ret0, _ := ret[0].([]models.AppActivation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueAppActivations indicates an expected call of ListDueAppActivations
+func (mr *MockAppActivationMockRecorder) ListDueAppActivations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueAppActivations", reflect.TypeOf((*MockAppActivation)(nil).ListDueAppActivations), arg0, arg1)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: AppFreeze)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAppFreeze is a mock of AppFreeze interface
+type MockAppFreeze struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppFreezeMockRecorder
+}
+
+// MockAppFreezeMockRecorder is the mock recorder for MockAppFreeze
+type MockAppFreezeMockRecorder struct {
+	mock *MockAppFreeze
+}
+
+// NewMockAppFreeze creates a new mock instance
+func NewMockAppFreeze(ctrl *gomock.Controller) *MockAppFreeze {
+	mock := &MockAppFreeze{ctrl: ctrl}
+	mock.recorder = &MockAppFreezeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppFreeze) EXPECT() *MockAppFreezeMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockAppFreeze) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockAppFreezeMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAppFreeze)(nil).Close))
+}
+
+// CreateAppFreeze mocks base method
+func (m *MockAppFreeze) CreateAppFreeze(arg0 interface{}, arg1 *models.AppFreeze) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppFreeze", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppFreeze indicates an expected call of CreateAppFreeze
+func (mr *MockAppFreezeMockRecorder) CreateAppFreeze(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppFreeze", reflect.TypeOf((*MockAppFreeze)(nil).CreateAppFreeze), arg0, arg1)
+}
+
+// DeleteAppFreeze mocks base method
+func (m *MockAppFreeze) DeleteAppFreeze(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppFreeze", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppFreeze indicates an expected call of DeleteAppFreeze
+func (mr *MockAppFreezeMockRecorder) DeleteAppFreeze(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppFreeze", reflect.TypeOf((*MockAppFreeze)(nil).DeleteAppFreeze), arg0, arg1, arg2)
+}
+
+// GetAppFreeze mocks base method
+func (m *MockAppFreeze) GetAppFreeze(arg0 interface{}, arg1, arg2 string) (*models.AppFreeze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppFreeze", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.AppFreeze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppFreeze indicates an expected call of GetAppFreeze
+func (mr *MockAppFreezeMockRecorder) GetAppFreeze(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppFreeze", reflect.TypeOf((*MockAppFreeze)(nil).GetAppFreeze), arg0, arg1, arg2)
+}
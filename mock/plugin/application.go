@@ -78,6 +78,36 @@ func (mr *MockApplicationMockRecorder) GetApplication(arg0, arg1, arg2 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplication", reflect.TypeOf((*MockApplication)(nil).GetApplication), arg0, arg1, arg2)
 }
 
+// GetApplicationForUpdate mocks base method
+func (m *MockApplication) GetApplicationForUpdate(arg0 interface{}, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicationForUpdate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplicationForUpdate indicates an expected call of GetApplicationForUpdate
+func (mr *MockApplicationMockRecorder) GetApplicationForUpdate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationForUpdate", reflect.TypeOf((*MockApplication)(nil).GetApplicationForUpdate), arg0, arg1, arg2)
+}
+
+// GetApplications mocks base method
+func (m *MockApplication) GetApplications(arg0 string, arg1 []string) ([]*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplications", arg0, arg1)
+	ret0, _ := ret[0].([]*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplications indicates an expected call of GetApplications
+func (mr *MockApplicationMockRecorder) GetApplications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplications", reflect.TypeOf((*MockApplication)(nil).GetApplications), arg0, arg1)
+}
+
 // ListApplication mocks base method
 func (m *MockApplication) ListApplication(arg0 interface{}, arg1 string, arg2 *models.ListOptions) (*models.ApplicationList, error) {
 	m.ctrl.T.Helper()
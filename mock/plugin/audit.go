@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: AppAudit)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAppAudit is a mock of AppAudit interface
+type MockAppAudit struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppAuditMockRecorder
+}
+
+// MockAppAuditMockRecorder is the mock recorder for MockAppAudit
+type MockAppAuditMockRecorder struct {
+	mock *MockAppAudit
+}
+
+// NewMockAppAudit creates a new mock instance
+func NewMockAppAudit(ctrl *gomock.Controller) *MockAppAudit {
+	mock := &MockAppAudit{ctrl: ctrl}
+	mock.recorder = &MockAppAuditMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppAudit) EXPECT() *MockAppAuditMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockAppAudit) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockAppAuditMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAppAudit)(nil).Close))
+}
+
+// CreateAppAudit mocks base method
+func (m *MockAppAudit) CreateAppAudit(arg0 interface{}, arg1 *models.AppAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppAudit", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppAudit indicates an expected call of CreateAppAudit
+func (mr *MockAppAuditMockRecorder) CreateAppAudit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppAudit", reflect.TypeOf((*MockAppAudit)(nil).CreateAppAudit), arg0, arg1)
+}
+
+// ListAppAudit mocks base method
+func (m *MockAppAudit) ListAppAudit(arg0, arg1 string) ([]models.AppAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppAudit", arg0, arg1)
+	ret0, _ := ret[0].([]models.AppAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppAudit indicates an expected call of ListAppAudit
+func (mr *MockAppAuditMockRecorder) ListAppAudit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppAudit", reflect.TypeOf((*MockAppAudit)(nil).ListAppAudit), arg0, arg1)
+}
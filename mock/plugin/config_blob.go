@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: ConfigBlob)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockConfigBlob is a mock of ConfigBlob interface
+type MockConfigBlob struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigBlobMockRecorder
+}
+
+// MockConfigBlobMockRecorder is the mock recorder for MockConfigBlob
+type MockConfigBlobMockRecorder struct {
+	mock *MockConfigBlob
+}
+
+// NewMockConfigBlob creates a new mock instance
+func NewMockConfigBlob(ctrl *gomock.Controller) *MockConfigBlob {
+	mock := &MockConfigBlob{ctrl: ctrl}
+	mock.recorder = &MockConfigBlobMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockConfigBlob) EXPECT() *MockConfigBlobMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockConfigBlob) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockConfigBlobMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockConfigBlob)(nil).Close))
+}
+
+// CreateConfigBlob mocks base method
+func (m *MockConfigBlob) CreateConfigBlob(arg0 interface{}, arg1 *models.ConfigBlob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigBlob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateConfigBlob indicates an expected call of CreateConfigBlob
+func (mr *MockConfigBlobMockRecorder) CreateConfigBlob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigBlob", reflect.TypeOf((*MockConfigBlob)(nil).CreateConfigBlob), arg0, arg1)
+}
+
+// GetConfigBlob mocks base method
+func (m *MockConfigBlob) GetConfigBlob(arg0 interface{}, arg1, arg2 string) (*models.ConfigBlob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigBlob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.ConfigBlob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigBlob indicates an expected call of GetConfigBlob
+func (mr *MockConfigBlobMockRecorder) GetConfigBlob(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigBlob", reflect.TypeOf((*MockConfigBlob)(nil).GetConfigBlob), arg0, arg1, arg2)
+}
+
+// ReplaceConfigBlobRefs mocks base method
+func (m *MockConfigBlob) ReplaceConfigBlobRefs(arg0 interface{}, arg1, arg2 string, arg3 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceConfigBlobRefs", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceConfigBlobRefs indicates an expected call of ReplaceConfigBlobRefs
+func (mr *MockConfigBlobMockRecorder) ReplaceConfigBlobRefs(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceConfigBlobRefs", reflect.TypeOf((*MockConfigBlob)(nil).ReplaceConfigBlobRefs), arg0, arg1, arg2, arg3)
+}
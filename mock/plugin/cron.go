@@ -61,6 +61,20 @@ func (mr *MockCronMockRecorder) CreateCron(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCron", reflect.TypeOf((*MockCron)(nil).CreateCron), arg0)
 }
 
+// CreateCronRun mocks base method
+func (m *MockCron) CreateCronRun(arg0 interface{}, arg1 *models.CronRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCronRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCronRun indicates an expected call of CreateCronRun
+func (mr *MockCronMockRecorder) CreateCronRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCronRun", reflect.TypeOf((*MockCron)(nil).CreateCronRun), arg0, arg1)
+}
+
 // DeleteCron mocks base method
 func (m *MockCron) DeleteCron(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -90,18 +104,65 @@ func (mr *MockCronMockRecorder) DeleteExpiredApps(arg0 interface{}) *gomock.Call
 }
 
 // GetCron mocks base method
-func (m *MockCron) GetCron(arg0, arg1 string) (*models.Cron, error) {
+func (m *MockCron) GetCron(arg0 interface{}, arg1, arg2 string) (*models.Cron, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCron", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetCron", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.Cron)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCron indicates an expected call of GetCron
-func (mr *MockCronMockRecorder) GetCron(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockCronMockRecorder) GetCron(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCron", reflect.TypeOf((*MockCron)(nil).GetCron), arg0, arg1, arg2)
+}
+
+// ListCronRuns mocks base method
+func (m *MockCron) ListCronRuns(arg0, arg1 string, arg2 *models.Filter) ([]models.CronRun, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCronRuns", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.CronRun)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCronRuns indicates an expected call of ListCronRuns
+func (mr *MockCronMockRecorder) ListCronRuns(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCronRuns", reflect.TypeOf((*MockCron)(nil).ListCronRuns), arg0, arg1, arg2)
+}
+
+// ListCrons mocks base method
+func (m *MockCron) ListCrons(arg0 interface{}, arg1 string, arg2 []string) ([]models.Cron, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCrons", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Cron)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCrons indicates an expected call of ListCrons
+func (mr *MockCronMockRecorder) ListCrons(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCron", reflect.TypeOf((*MockCron)(nil).GetCron), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCrons", reflect.TypeOf((*MockCron)(nil).ListCrons), arg0, arg1, arg2)
+}
+
+// ListCronsPage mocks base method
+func (m *MockCron) ListCronsPage(arg0 string, arg1 *models.Filter) ([]models.Cron, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCronsPage", arg0, arg1)
+	ret0, _ := ret[0].([]models.Cron)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCronsPage indicates an expected call of ListCronsPage
+func (mr *MockCronMockRecorder) ListCronsPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCronsPage", reflect.TypeOf((*MockCron)(nil).ListCronsPage), arg0, arg1)
 }
 
 // ListExpiredApps mocks base method
@@ -119,6 +180,20 @@ func (mr *MockCronMockRecorder) ListExpiredApps() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredApps", reflect.TypeOf((*MockCron)(nil).ListExpiredApps))
 }
 
+// SetCronPaused mocks base method
+func (m *MockCron) SetCronPaused(arg0, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCronPaused", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCronPaused indicates an expected call of SetCronPaused
+func (mr *MockCronMockRecorder) SetCronPaused(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCronPaused", reflect.TypeOf((*MockCron)(nil).SetCronPaused), arg0, arg1, arg2)
+}
+
 // UpdateCron mocks base method
 func (m *MockCron) UpdateCron(arg0 *models.Cron) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: EventSink)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	context "context"
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockEventSink is a mock of EventSink interface
+type MockEventSink struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventSinkMockRecorder
+}
+
+// MockEventSinkMockRecorder is the mock recorder for MockEventSink
+type MockEventSinkMockRecorder struct {
+	mock *MockEventSink
+}
+
+// NewMockEventSink creates a new mock instance
+func NewMockEventSink(ctrl *gomock.Controller) *MockEventSink {
+	mock := &MockEventSink{ctrl: ctrl}
+	mock.recorder = &MockEventSinkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockEventSink) EXPECT() *MockEventSinkMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockEventSink) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockEventSinkMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockEventSink)(nil).Close))
+}
+
+// Publish mocks base method
+func (m *MockEventSink) Publish(arg0 context.Context, arg1 *models.AppEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish
+func (mr *MockEventSinkMockRecorder) Publish(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventSink)(nil).Publish), arg0, arg1)
+}
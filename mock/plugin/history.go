@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: AppHistory)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAppHistory is a mock of AppHistory interface
+type MockAppHistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppHistoryMockRecorder
+}
+
+// MockAppHistoryMockRecorder is the mock recorder for MockAppHistory
+type MockAppHistoryMockRecorder struct {
+	mock *MockAppHistory
+}
+
+// NewMockAppHistory creates a new mock instance
+func NewMockAppHistory(ctrl *gomock.Controller) *MockAppHistory {
+	mock := &MockAppHistory{ctrl: ctrl}
+	mock.recorder = &MockAppHistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppHistory) EXPECT() *MockAppHistoryMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockAppHistory) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockAppHistoryMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAppHistory)(nil).Close))
+}
+
+// DeleteAppHistory mocks base method
+func (m *MockAppHistory) DeleteAppHistory(arg0 interface{}, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppHistory indicates an expected call of DeleteAppHistory
+func (mr *MockAppHistoryMockRecorder) DeleteAppHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppHistory", reflect.TypeOf((*MockAppHistory)(nil).DeleteAppHistory), arg0, arg1, arg2, arg3)
+}
+
+// ListAppHistory mocks base method
+func (m *MockAppHistory) ListAppHistory(arg0, arg1 string) ([]models.AppVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppHistory", arg0, arg1)
+	ret0, _ := ret[0].([]models.AppVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppHistory indicates an expected call of ListAppHistory
+func (mr *MockAppHistoryMockRecorder) ListAppHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppHistory", reflect.TypeOf((*MockAppHistory)(nil).ListAppHistory), arg0, arg1)
+}
+
+// UpdateAppHistoryPinned mocks base method
+func (m *MockAppHistory) UpdateAppHistoryPinned(arg0, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppHistoryPinned", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAppHistoryPinned indicates an expected call of UpdateAppHistoryPinned
+func (mr *MockAppHistoryMockRecorder) UpdateAppHistoryPinned(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppHistoryPinned", reflect.TypeOf((*MockAppHistory)(nil).UpdateAppHistoryPinned), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: AppIdempotency)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockAppIdempotency is a mock of AppIdempotency interface
+type MockAppIdempotency struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppIdempotencyMockRecorder
+}
+
+// MockAppIdempotencyMockRecorder is the mock recorder for MockAppIdempotency
+type MockAppIdempotencyMockRecorder struct {
+	mock *MockAppIdempotency
+}
+
+// NewMockAppIdempotency creates a new mock instance
+func NewMockAppIdempotency(ctrl *gomock.Controller) *MockAppIdempotency {
+	mock := &MockAppIdempotency{ctrl: ctrl}
+	mock.recorder = &MockAppIdempotencyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppIdempotency) EXPECT() *MockAppIdempotencyMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockAppIdempotency) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockAppIdempotencyMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAppIdempotency)(nil).Close))
+}
+
+// CreateAppIdempotency mocks base method
+func (m *MockAppIdempotency) CreateAppIdempotency(arg0 interface{}, arg1 *models.AppIdempotency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppIdempotency", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppIdempotency indicates an expected call of CreateAppIdempotency
+func (mr *MockAppIdempotencyMockRecorder) CreateAppIdempotency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppIdempotency", reflect.TypeOf((*MockAppIdempotency)(nil).CreateAppIdempotency), arg0, arg1)
+}
+
+// DeleteExpiredAppIdempotency mocks base method
+func (m *MockAppIdempotency) DeleteExpiredAppIdempotency(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredAppIdempotency", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredAppIdempotency indicates an expected call of DeleteExpiredAppIdempotency
+func (mr *MockAppIdempotencyMockRecorder) DeleteExpiredAppIdempotency(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredAppIdempotency", reflect.TypeOf((*MockAppIdempotency)(nil).DeleteExpiredAppIdempotency), arg0)
+}
+
+// GetAppIdempotency mocks base method
+func (m *MockAppIdempotency) GetAppIdempotency(arg0, arg1 string) (*models.AppIdempotency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppIdempotency", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppIdempotency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppIdempotency indicates an expected call of GetAppIdempotency
+func (mr *MockAppIdempotencyMockRecorder) GetAppIdempotency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppIdempotency", reflect.TypeOf((*MockAppIdempotency)(nil).GetAppIdempotency), arg0, arg1)
+}
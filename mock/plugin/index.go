@@ -7,6 +7,7 @@ package plugin
 import (
 	sql "database/sql"
 	common "github.com/baetyl/baetyl-cloud/v2/common"
+	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
 	sqlx "github.com/jmoiron/sqlx"
 	reflect "reflect"
@@ -49,6 +50,21 @@ func (mr *MockIndexMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIndex)(nil).Close))
 }
 
+// CountIndex mocks base method
+func (m *MockIndex) CountIndex(arg0 string, arg1, arg2 common.Resource, arg3, arg4 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountIndex", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountIndex indicates an expected call of CountIndex
+func (mr *MockIndexMockRecorder) CountIndex(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountIndex", reflect.TypeOf((*MockIndex)(nil).CountIndex), arg0, arg1, arg2, arg3, arg4)
+}
+
 // CreateIndex mocks base method
 func (m *MockIndex) CreateIndex(arg0 string, arg1, arg2 common.Resource, arg3, arg4 string) (sql.Result, error) {
 	m.ctrl.T.Helper()
@@ -109,6 +125,21 @@ func (mr *MockIndexMockRecorder) DeleteIndexTx(arg0, arg1, arg2, arg3, arg4 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIndexTx", reflect.TypeOf((*MockIndex)(nil).DeleteIndexTx), arg0, arg1, arg2, arg3, arg4)
 }
 
+// ListAppNodesByLabel mocks base method
+func (m *MockIndex) ListAppNodesByLabel(arg0, arg1, arg2 string) ([]models.AppNodeBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppNodesByLabel", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.AppNodeBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppNodesByLabel indicates an expected call of ListAppNodesByLabel
+func (mr *MockIndexMockRecorder) ListAppNodesByLabel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppNodesByLabel", reflect.TypeOf((*MockIndex)(nil).ListAppNodesByLabel), arg0, arg1, arg2)
+}
+
 // ListIndex mocks base method
 func (m *MockIndex) ListIndex(arg0 string, arg1, arg2 common.Resource, arg3 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +155,21 @@ func (mr *MockIndexMockRecorder) ListIndex(arg0, arg1, arg2, arg3 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIndex", reflect.TypeOf((*MockIndex)(nil).ListIndex), arg0, arg1, arg2, arg3)
 }
 
+// ListIndexPage mocks base method
+func (m *MockIndex) ListIndexPage(arg0 string, arg1, arg2 common.Resource, arg3 string, arg4 *models.Filter) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIndexPage", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIndexPage indicates an expected call of ListIndexPage
+func (mr *MockIndexMockRecorder) ListIndexPage(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIndexPage", reflect.TypeOf((*MockIndex)(nil).ListIndexPage), arg0, arg1, arg2, arg3, arg4)
+}
+
 // ListIndexTx mocks base method
 func (m *MockIndex) ListIndexTx(arg0 *sqlx.Tx, arg1 string, arg2, arg3 common.Resource, arg4 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +185,20 @@ func (mr *MockIndexMockRecorder) ListIndexTx(arg0, arg1, arg2, arg3, arg4 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIndexTx", reflect.TypeOf((*MockIndex)(nil).ListIndexTx), arg0, arg1, arg2, arg3, arg4)
 }
 
+// RefreshAppLabelIndex mocks base method
+func (m *MockIndex) RefreshAppLabelIndex(arg0 interface{}, arg1, arg2 string, arg3 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshAppLabelIndex", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshAppLabelIndex indicates an expected call of RefreshAppLabelIndex
+func (mr *MockIndexMockRecorder) RefreshAppLabelIndex(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAppLabelIndex", reflect.TypeOf((*MockIndex)(nil).RefreshAppLabelIndex), arg0, arg1, arg2, arg3)
+}
+
 // RefreshIndex mocks base method
 func (m *MockIndex) RefreshIndex(arg0 interface{}, arg1 string, arg2, arg3 common.Resource, arg4 string, arg5 []string) error {
 	m.ctrl.T.Helper()
@@ -33,6 +33,21 @@ func (m *MockLocker) EXPECT() *MockLockerMockRecorder {
 	return m.recorder
 }
 
+// Acquire mocks base method
+func (m *MockLocker) Acquire(arg0 context.Context, arg1, arg2 string, arg3 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire
+func (mr *MockLockerMockRecorder) Acquire(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockLocker)(nil).Acquire), arg0, arg1, arg2, arg3)
+}
+
 // Close mocks base method
 func (m *MockLocker) Close() error {
 	m.ctrl.T.Helper()
@@ -62,6 +77,21 @@ func (mr *MockLockerMockRecorder) Lock(arg0, arg1, arg2 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLocker)(nil).Lock), arg0, arg1, arg2)
 }
 
+// Renew mocks base method
+func (m *MockLocker) Renew(arg0 context.Context, arg1, arg2 string, arg3 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew
+func (mr *MockLockerMockRecorder) Renew(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockLocker)(nil).Renew), arg0, arg1, arg2, arg3)
+}
+
 // Unlock mocks base method
 func (m *MockLocker) Unlock(arg0 context.Context, arg1, arg2 string) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,134 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: AppOutbox)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAppOutbox is a mock of AppOutbox interface
+type MockAppOutbox struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppOutboxMockRecorder
+}
+
+// MockAppOutboxMockRecorder is the mock recorder for MockAppOutbox
+type MockAppOutboxMockRecorder struct {
+	mock *MockAppOutbox
+}
+
+// NewMockAppOutbox creates a new mock instance
+func NewMockAppOutbox(ctrl *gomock.Controller) *MockAppOutbox {
+	mock := &MockAppOutbox{ctrl: ctrl}
+	mock.recorder = &MockAppOutboxMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppOutbox) EXPECT() *MockAppOutboxMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockAppOutbox) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockAppOutboxMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAppOutbox)(nil).Close))
+}
+
+// CreateAppOutbox mocks base method
+func (m *MockAppOutbox) CreateAppOutbox(arg0 interface{}, arg1 *models.AppEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppOutbox", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppOutbox indicates an expected call of CreateAppOutbox
+func (mr *MockAppOutboxMockRecorder) CreateAppOutbox(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppOutbox", reflect.TypeOf((*MockAppOutbox)(nil).CreateAppOutbox), arg0, arg1)
+}
+
+// CreateCronOutbox mocks base method
+func (m *MockAppOutbox) CreateCronOutbox(arg0 interface{}, arg1 *models.CronOutboxOp) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCronOutbox", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCronOutbox indicates an expected call of CreateCronOutbox
+func (mr *MockAppOutboxMockRecorder) CreateCronOutbox(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCronOutbox", reflect.TypeOf((*MockAppOutbox)(nil).CreateCronOutbox), arg0, arg1)
+}
+
+// DeleteAppOutbox mocks base method
+func (m *MockAppOutbox) DeleteAppOutbox(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppOutbox", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppOutbox indicates an expected call of DeleteAppOutbox
+func (mr *MockAppOutboxMockRecorder) DeleteAppOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppOutbox", reflect.TypeOf((*MockAppOutbox)(nil).DeleteAppOutbox), arg0)
+}
+
+// DeleteCronOutbox mocks base method
+func (m *MockAppOutbox) DeleteCronOutbox(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCronOutbox", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCronOutbox indicates an expected call of DeleteCronOutbox
+func (mr *MockAppOutboxMockRecorder) DeleteCronOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCronOutbox", reflect.TypeOf((*MockAppOutbox)(nil).DeleteCronOutbox), arg0)
+}
+
+// ListAppOutbox mocks base method
+func (m *MockAppOutbox) ListAppOutbox(arg0 int) ([]models.AppOutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppOutbox", arg0)
+	ret0, _ := ret[0].([]models.AppOutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppOutbox indicates an expected call of ListAppOutbox
+func (mr *MockAppOutboxMockRecorder) ListAppOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppOutbox", reflect.TypeOf((*MockAppOutbox)(nil).ListAppOutbox), arg0)
+}
+
+// ListCronOutbox mocks base method
+func (m *MockAppOutbox) ListCronOutbox(arg0 int) ([]models.CronOutboxOp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCronOutbox", arg0)
+	ret0, _ := ret[0].([]models.CronOutboxOp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCronOutbox indicates an expected call of ListCronOutbox
+func (mr *MockAppOutboxMockRecorder) ListCronOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCronOutbox", reflect.TypeOf((*MockAppOutbox)(nil).ListCronOutbox), arg0)
+}
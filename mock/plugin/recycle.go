@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/plugin (interfaces: AppRecycle)
+
+// Package plugin is a generated GoMock package.
+package plugin
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockAppRecycle is a mock of AppRecycle interface
+type MockAppRecycle struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppRecycleMockRecorder
+}
+
+// MockAppRecycleMockRecorder is the mock recorder for MockAppRecycle
+type MockAppRecycleMockRecorder struct {
+	mock *MockAppRecycle
+}
+
+// NewMockAppRecycle creates a new mock instance
+func NewMockAppRecycle(ctrl *gomock.Controller) *MockAppRecycle {
+	mock := &MockAppRecycle{ctrl: ctrl}
+	mock.recorder = &MockAppRecycleMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppRecycle) EXPECT() *MockAppRecycleMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method
+func (m *MockAppRecycle) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close
+func (mr *MockAppRecycleMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAppRecycle)(nil).Close))
+}
+
+// CreateAppRecycle mocks base method
+func (m *MockAppRecycle) CreateAppRecycle(arg0 interface{}, arg1 *models.AppRecycle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppRecycle", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppRecycle indicates an expected call of CreateAppRecycle
+func (mr *MockAppRecycleMockRecorder) CreateAppRecycle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppRecycle", reflect.TypeOf((*MockAppRecycle)(nil).CreateAppRecycle), arg0, arg1)
+}
+
+// DeleteAppRecycle mocks base method
+func (m *MockAppRecycle) DeleteAppRecycle(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppRecycle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppRecycle indicates an expected call of DeleteAppRecycle
+func (mr *MockAppRecycleMockRecorder) DeleteAppRecycle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppRecycle", reflect.TypeOf((*MockAppRecycle)(nil).DeleteAppRecycle), arg0, arg1, arg2)
+}
+
+// GetAppRecycle mocks base method
+func (m *MockAppRecycle) GetAppRecycle(arg0, arg1 string) (*models.AppRecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppRecycle", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppRecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppRecycle indicates an expected call of GetAppRecycle
+func (mr *MockAppRecycleMockRecorder) GetAppRecycle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppRecycle", reflect.TypeOf((*MockAppRecycle)(nil).GetAppRecycle), arg0, arg1)
+}
+
+// ListAppRecycle mocks base method
+func (m *MockAppRecycle) ListAppRecycle(arg0 string) ([]models.AppRecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppRecycle", arg0)
+	ret0, _ := ret[0].([]models.AppRecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppRecycle indicates an expected call of ListAppRecycle
+func (mr *MockAppRecycleMockRecorder) ListAppRecycle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppRecycle", reflect.TypeOf((*MockAppRecycle)(nil).ListAppRecycle), arg0)
+}
+
+// ListExpiredAppRecycle mocks base method
+func (m *MockAppRecycle) ListExpiredAppRecycle(arg0 time.Time) ([]models.AppRecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredAppRecycle", arg0)
+	ret0, _ := ret[0].([]models.AppRecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredAppRecycle indicates an expected call of ListExpiredAppRecycle
+func (mr *MockAppRecycleMockRecorder) ListExpiredAppRecycle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredAppRecycle", reflect.TypeOf((*MockAppRecycle)(nil).ListExpiredAppRecycle), arg0)
+}
@@ -223,6 +223,36 @@ func (mr *MockResourceMockRecorder) GetApplication(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplication", reflect.TypeOf((*MockResource)(nil).GetApplication), arg0, arg1, arg2)
 }
 
+// GetApplicationForUpdate mocks base method
+func (m *MockResource) GetApplicationForUpdate(arg0 interface{}, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicationForUpdate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplicationForUpdate indicates an expected call of GetApplicationForUpdate
+func (mr *MockResourceMockRecorder) GetApplicationForUpdate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicationForUpdate", reflect.TypeOf((*MockResource)(nil).GetApplicationForUpdate), arg0, arg1, arg2)
+}
+
+// GetApplications mocks base method
+func (m *MockResource) GetApplications(arg0 string, arg1 []string) ([]*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplications", arg0, arg1)
+	ret0, _ := ret[0].([]*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplications indicates an expected call of GetApplications
+func (mr *MockResourceMockRecorder) GetApplications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplications", reflect.TypeOf((*MockResource)(nil).GetApplications), arg0, arg1)
+}
+
 // GetConfig mocks base method
 func (m *MockResource) GetConfig(arg0 interface{}, arg1, arg2, arg3 string) (*v1.Configuration, error) {
 	m.ctrl.T.Helper()
@@ -5,6 +5,8 @@
 package plugin
 
 import (
+	context "context"
+	plugin "github.com/baetyl/baetyl-cloud/v2/plugin"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -32,19 +34,34 @@ func (m *MockTransactionFactory) EXPECT() *MockTransactionFactoryMockRecorder {
 	return m.recorder
 }
 
+// BeginReadOnlyTx mocks base method
+func (m *MockTransactionFactory) BeginReadOnlyTx(arg0 context.Context) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginReadOnlyTx", arg0)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginReadOnlyTx indicates an expected call of BeginReadOnlyTx
+func (mr *MockTransactionFactoryMockRecorder) BeginReadOnlyTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginReadOnlyTx", reflect.TypeOf((*MockTransactionFactory)(nil).BeginReadOnlyTx), arg0)
+}
+
 // BeginTx mocks base method
-func (m *MockTransactionFactory) BeginTx() (interface{}, error) {
+func (m *MockTransactionFactory) BeginTx(arg0 context.Context, arg1 *plugin.TxOptions) (interface{}, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BeginTx")
+	ret := m.ctrl.Call(m, "BeginTx", arg0, arg1)
 	ret0, _ := ret[0].(interface{})
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // BeginTx indicates an expected call of BeginTx
-func (mr *MockTransactionFactoryMockRecorder) BeginTx() *gomock.Call {
+func (mr *MockTransactionFactoryMockRecorder) BeginTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockTransactionFactory)(nil).BeginTx))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTx", reflect.TypeOf((*MockTransactionFactory)(nil).BeginTx), arg0, arg1)
 }
 
 // Close mocks base method
@@ -84,3 +101,31 @@ func (mr *MockTransactionFactoryMockRecorder) Rollback(arg0 interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockTransactionFactory)(nil).Rollback), arg0)
 }
+
+// RollbackToSavepoint mocks base method
+func (m *MockTransactionFactory) RollbackToSavepoint(arg0 interface{}, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackToSavepoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RollbackToSavepoint indicates an expected call of RollbackToSavepoint
+func (mr *MockTransactionFactoryMockRecorder) RollbackToSavepoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackToSavepoint", reflect.TypeOf((*MockTransactionFactory)(nil).RollbackToSavepoint), arg0, arg1)
+}
+
+// Savepoint mocks base method
+func (m *MockTransactionFactory) Savepoint(arg0 interface{}, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Savepoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Savepoint indicates an expected call of Savepoint
+func (mr *MockTransactionFactoryMockRecorder) Savepoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Savepoint", reflect.TypeOf((*MockTransactionFactory)(nil).Savepoint), arg0, arg1)
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: AppActivationService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockAppActivationService is a mock of AppActivationService interface
+type MockAppActivationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppActivationServiceMockRecorder
+}
+
+// MockAppActivationServiceMockRecorder is the mock recorder for MockAppActivationService
+type MockAppActivationServiceMockRecorder struct {
+	mock *MockAppActivationService
+}
+
+// NewMockAppActivationService creates a new mock instance
+func NewMockAppActivationService(ctrl *gomock.Controller) *MockAppActivationService {
+	mock := &MockAppActivationService{ctrl: ctrl}
+	mock.recorder = &MockAppActivationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppActivationService) EXPECT() *MockAppActivationServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAppActivation mocks base method
+func (m *MockAppActivationService) CreateAppActivation(arg0 interface{}, arg1 *models.AppActivation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppActivation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppActivation indicates an expected call of CreateAppActivation
+func (mr *MockAppActivationServiceMockRecorder) CreateAppActivation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppActivation", reflect.TypeOf((*MockAppActivationService)(nil).CreateAppActivation), arg0, arg1)
+}
+
+// DeleteAppActivation mocks base method
+func (m *MockAppActivationService) DeleteAppActivation(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppActivation", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppActivation indicates an expected call of DeleteAppActivation
+func (mr *MockAppActivationServiceMockRecorder) DeleteAppActivation(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppActivation", reflect.TypeOf((*MockAppActivationService)(nil).DeleteAppActivation), arg0, arg1, arg2)
+}
+
+// GetAppActivation mocks base method
+func (m *MockAppActivationService) GetAppActivation(arg0, arg1 string) (*models.AppActivation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppActivation", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppActivation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppActivation indicates an expected call of GetAppActivation
+func (mr *MockAppActivationServiceMockRecorder) GetAppActivation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppActivation", reflect.TypeOf((*MockAppActivationService)(nil).GetAppActivation), arg0, arg1)
+}
+
+// ListDueAppActivations mocks base method
+func (m *MockAppActivationService) ListDueAppActivations(arg0 time.Time, arg1 int) ([]models.AppActivation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueAppActivations", arg0, arg1)
+	ret0, _ := ret[0].([]models.AppActivation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueAppActivations indicates an expected call of ListDueAppActivations
+func (mr *MockAppActivationServiceMockRecorder) ListDueAppActivations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueAppActivations", reflect.TypeOf((*MockAppActivationService)(nil).ListDueAppActivations), arg0, arg1)
+}
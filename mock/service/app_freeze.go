@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: AppFreezeService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAppFreezeService is a mock of AppFreezeService interface
+type MockAppFreezeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppFreezeServiceMockRecorder
+}
+
+// MockAppFreezeServiceMockRecorder is the mock recorder for MockAppFreezeService
+type MockAppFreezeServiceMockRecorder struct {
+	mock *MockAppFreezeService
+}
+
+// NewMockAppFreezeService creates a new mock instance
+func NewMockAppFreezeService(ctrl *gomock.Controller) *MockAppFreezeService {
+	mock := &MockAppFreezeService{ctrl: ctrl}
+	mock.recorder = &MockAppFreezeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppFreezeService) EXPECT() *MockAppFreezeServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAppFreeze mocks base method
+func (m *MockAppFreezeService) CreateAppFreeze(arg0 interface{}, arg1 *models.AppFreeze) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppFreeze", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppFreeze indicates an expected call of CreateAppFreeze
+func (mr *MockAppFreezeServiceMockRecorder) CreateAppFreeze(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppFreeze", reflect.TypeOf((*MockAppFreezeService)(nil).CreateAppFreeze), arg0, arg1)
+}
+
+// DeleteAppFreeze mocks base method
+func (m *MockAppFreezeService) DeleteAppFreeze(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppFreeze", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppFreeze indicates an expected call of DeleteAppFreeze
+func (mr *MockAppFreezeServiceMockRecorder) DeleteAppFreeze(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppFreeze", reflect.TypeOf((*MockAppFreezeService)(nil).DeleteAppFreeze), arg0, arg1, arg2)
+}
+
+// GetAppFreeze mocks base method
+func (m *MockAppFreezeService) GetAppFreeze(arg0 interface{}, arg1, arg2 string) (*models.AppFreeze, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppFreeze", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.AppFreeze)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppFreeze indicates an expected call of GetAppFreeze
+func (mr *MockAppFreezeServiceMockRecorder) GetAppFreeze(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppFreeze", reflect.TypeOf((*MockAppFreezeService)(nil).GetAppFreeze), arg0, arg1, arg2)
+}
@@ -93,6 +93,36 @@ func (mr *MockApplicationServiceMockRecorder) Get(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockApplicationService)(nil).Get), arg0, arg1, arg2)
 }
 
+// GetBatch mocks base method
+func (m *MockApplicationService) GetBatch(arg0 string, arg1 []string) ([]*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatch", arg0, arg1)
+	ret0, _ := ret[0].([]*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatch indicates an expected call of GetBatch
+func (mr *MockApplicationServiceMockRecorder) GetBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatch", reflect.TypeOf((*MockApplicationService)(nil).GetBatch), arg0, arg1)
+}
+
+// GetForUpdate mocks base method
+func (m *MockApplicationService) GetForUpdate(arg0 interface{}, arg1, arg2 string) (*v1.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate
+func (mr *MockApplicationServiceMockRecorder) GetForUpdate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockApplicationService)(nil).GetForUpdate), arg0, arg1, arg2)
+}
+
 // List mocks base method
 func (m *MockApplicationService) List(arg0 string, arg1 *models.ListOptions) (*models.ApplicationList, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: AppAuditService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAppAuditService is a mock of AppAuditService interface
+type MockAppAuditService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppAuditServiceMockRecorder
+}
+
+// MockAppAuditServiceMockRecorder is the mock recorder for MockAppAuditService
+type MockAppAuditServiceMockRecorder struct {
+	mock *MockAppAuditService
+}
+
+// NewMockAppAuditService creates a new mock instance
+func NewMockAppAuditService(ctrl *gomock.Controller) *MockAppAuditService {
+	mock := &MockAppAuditService{ctrl: ctrl}
+	mock.recorder = &MockAppAuditServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppAuditService) EXPECT() *MockAppAuditServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAppAudit mocks base method
+func (m *MockAppAuditService) CreateAppAudit(arg0 interface{}, arg1 *models.AppAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppAudit", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppAudit indicates an expected call of CreateAppAudit
+func (mr *MockAppAuditServiceMockRecorder) CreateAppAudit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppAudit", reflect.TypeOf((*MockAppAuditService)(nil).CreateAppAudit), arg0, arg1)
+}
+
+// ListAppAudit mocks base method
+func (m *MockAppAuditService) ListAppAudit(arg0, arg1 string) ([]models.AppAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppAudit", arg0, arg1)
+	ret0, _ := ret[0].([]models.AppAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppAudit indicates an expected call of ListAppAudit
+func (mr *MockAppAuditServiceMockRecorder) ListAppAudit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppAudit", reflect.TypeOf((*MockAppAuditService)(nil).ListAppAudit), arg0, arg1)
+}
@@ -78,6 +78,22 @@ func (mr *MockConfigServiceMockRecorder) Get(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockConfigService)(nil).Get), arg0, arg1, arg2)
 }
 
+// IsReferenced mocks base method
+func (m *MockConfigService) IsReferenced(arg0, arg1 string) (bool, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsReferenced", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// IsReferenced indicates an expected call of IsReferenced
+func (mr *MockConfigServiceMockRecorder) IsReferenced(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReferenced", reflect.TypeOf((*MockConfigService)(nil).IsReferenced), arg0, arg1)
+}
+
 // List mocks base method
 func (m *MockConfigService) List(arg0 string, arg1 *models.ListOptions) (*models.ConfigurationList, error) {
 	m.ctrl.T.Helper()
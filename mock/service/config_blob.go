@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: ConfigBlobService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	v1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockConfigBlobService is a mock of ConfigBlobService interface
+type MockConfigBlobService struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigBlobServiceMockRecorder
+}
+
+// MockConfigBlobServiceMockRecorder is the mock recorder for MockConfigBlobService
+type MockConfigBlobServiceMockRecorder struct {
+	mock *MockConfigBlobService
+}
+
+// NewMockConfigBlobService creates a new mock instance
+func NewMockConfigBlobService(ctrl *gomock.Controller) *MockConfigBlobService {
+	mock := &MockConfigBlobService{ctrl: ctrl}
+	mock.recorder = &MockConfigBlobServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockConfigBlobService) EXPECT() *MockConfigBlobServiceMockRecorder {
+	return m.recorder
+}
+
+// Externalize mocks base method
+func (m *MockConfigBlobService) Externalize(arg0 interface{}, arg1 string, arg2 *v1.Configuration, arg3 int) (*v1.Configuration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Externalize", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*v1.Configuration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Externalize indicates an expected call of Externalize
+func (mr *MockConfigBlobServiceMockRecorder) Externalize(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Externalize", reflect.TypeOf((*MockConfigBlobService)(nil).Externalize), arg0, arg1, arg2, arg3)
+}
+
+// Release mocks base method
+func (m *MockConfigBlobService) Release(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release
+func (mr *MockConfigBlobServiceMockRecorder) Release(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockConfigBlobService)(nil).Release), arg0, arg1, arg2)
+}
+
+// Resolve mocks base method
+func (m *MockConfigBlobService) Resolve(arg0 interface{}, arg1 string, arg2 *v1.Configuration) (*v1.Configuration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*v1.Configuration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve
+func (mr *MockConfigBlobServiceMockRecorder) Resolve(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockConfigBlobService)(nil).Resolve), arg0, arg1, arg2)
+}
@@ -47,6 +47,20 @@ func (mr *MockCronServiceMockRecorder) CreateCron(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCron", reflect.TypeOf((*MockCronService)(nil).CreateCron), arg0)
 }
 
+// CreateCronRun mocks base method
+func (m *MockCronService) CreateCronRun(arg0 interface{}, arg1 *models.CronRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCronRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCronRun indicates an expected call of CreateCronRun
+func (mr *MockCronServiceMockRecorder) CreateCronRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCronRun", reflect.TypeOf((*MockCronService)(nil).CreateCronRun), arg0, arg1)
+}
+
 // DeleteCron mocks base method
 func (m *MockCronService) DeleteCron(arg0, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -76,18 +90,65 @@ func (mr *MockCronServiceMockRecorder) DeleteExpiredApps(arg0 interface{}) *gomo
 }
 
 // GetCron mocks base method
-func (m *MockCronService) GetCron(arg0, arg1 string) (*models.Cron, error) {
+func (m *MockCronService) GetCron(arg0 interface{}, arg1, arg2 string) (*models.Cron, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCron", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetCron", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*models.Cron)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCron indicates an expected call of GetCron
-func (mr *MockCronServiceMockRecorder) GetCron(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockCronServiceMockRecorder) GetCron(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCron", reflect.TypeOf((*MockCronService)(nil).GetCron), arg0, arg1, arg2)
+}
+
+// ListCronRuns mocks base method
+func (m *MockCronService) ListCronRuns(arg0, arg1 string, arg2 *models.Filter) ([]models.CronRun, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCronRuns", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.CronRun)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCronRuns indicates an expected call of ListCronRuns
+func (mr *MockCronServiceMockRecorder) ListCronRuns(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCronRuns", reflect.TypeOf((*MockCronService)(nil).ListCronRuns), arg0, arg1, arg2)
+}
+
+// ListCrons mocks base method
+func (m *MockCronService) ListCrons(arg0 interface{}, arg1 string, arg2 []string) ([]models.Cron, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCrons", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Cron)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCrons indicates an expected call of ListCrons
+func (mr *MockCronServiceMockRecorder) ListCrons(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCron", reflect.TypeOf((*MockCronService)(nil).GetCron), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCrons", reflect.TypeOf((*MockCronService)(nil).ListCrons), arg0, arg1, arg2)
+}
+
+// ListCronsPage mocks base method
+func (m *MockCronService) ListCronsPage(arg0 string, arg1 *models.Filter) ([]models.Cron, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCronsPage", arg0, arg1)
+	ret0, _ := ret[0].([]models.Cron)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCronsPage indicates an expected call of ListCronsPage
+func (mr *MockCronServiceMockRecorder) ListCronsPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCronsPage", reflect.TypeOf((*MockCronService)(nil).ListCronsPage), arg0, arg1)
 }
 
 // ListExpiredApps mocks base method
@@ -105,6 +166,20 @@ func (mr *MockCronServiceMockRecorder) ListExpiredApps() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredApps", reflect.TypeOf((*MockCronService)(nil).ListExpiredApps))
 }
 
+// SetCronPaused mocks base method
+func (m *MockCronService) SetCronPaused(arg0, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCronPaused", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCronPaused indicates an expected call of SetCronPaused
+func (mr *MockCronServiceMockRecorder) SetCronPaused(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCronPaused", reflect.TypeOf((*MockCronService)(nil).SetCronPaused), arg0, arg1, arg2)
+}
+
 // UpdateCron mocks base method
 func (m *MockCronService) UpdateCron(arg0 *models.Cron) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: AppHistoryService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAppHistoryService is a mock of AppHistoryService interface
+type MockAppHistoryService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppHistoryServiceMockRecorder
+}
+
+// MockAppHistoryServiceMockRecorder is the mock recorder for MockAppHistoryService
+type MockAppHistoryServiceMockRecorder struct {
+	mock *MockAppHistoryService
+}
+
+// NewMockAppHistoryService creates a new mock instance
+func NewMockAppHistoryService(ctrl *gomock.Controller) *MockAppHistoryService {
+	mock := &MockAppHistoryService{ctrl: ctrl}
+	mock.recorder = &MockAppHistoryServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppHistoryService) EXPECT() *MockAppHistoryServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteAppHistory mocks base method
+func (m *MockAppHistoryService) DeleteAppHistory(arg0 interface{}, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppHistory indicates an expected call of DeleteAppHistory
+func (mr *MockAppHistoryServiceMockRecorder) DeleteAppHistory(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppHistory", reflect.TypeOf((*MockAppHistoryService)(nil).DeleteAppHistory), arg0, arg1, arg2, arg3)
+}
+
+// ListAppHistory mocks base method
+func (m *MockAppHistoryService) ListAppHistory(arg0, arg1 string) ([]models.AppVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppHistory", arg0, arg1)
+	ret0, _ := ret[0].([]models.AppVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppHistory indicates an expected call of ListAppHistory
+func (mr *MockAppHistoryServiceMockRecorder) ListAppHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppHistory", reflect.TypeOf((*MockAppHistoryService)(nil).ListAppHistory), arg0, arg1)
+}
+
+// UpdateAppHistoryPinned mocks base method
+func (m *MockAppHistoryService) UpdateAppHistoryPinned(arg0, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppHistoryPinned", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAppHistoryPinned indicates an expected call of UpdateAppHistoryPinned
+func (mr *MockAppHistoryServiceMockRecorder) UpdateAppHistoryPinned(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppHistoryPinned", reflect.TypeOf((*MockAppHistoryService)(nil).UpdateAppHistoryPinned), arg0, arg1, arg2, arg3)
+}
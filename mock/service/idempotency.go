@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: AppIdempotencyService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockAppIdempotencyService is a mock of AppIdempotencyService interface
+type MockAppIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppIdempotencyServiceMockRecorder
+}
+
+// MockAppIdempotencyServiceMockRecorder is the mock recorder for MockAppIdempotencyService
+type MockAppIdempotencyServiceMockRecorder struct {
+	mock *MockAppIdempotencyService
+}
+
+// NewMockAppIdempotencyService creates a new mock instance
+func NewMockAppIdempotencyService(ctrl *gomock.Controller) *MockAppIdempotencyService {
+	mock := &MockAppIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockAppIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppIdempotencyService) EXPECT() *MockAppIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAppIdempotency mocks base method
+func (m *MockAppIdempotencyService) CreateAppIdempotency(arg0 interface{}, arg1 *models.AppIdempotency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppIdempotency", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppIdempotency indicates an expected call of CreateAppIdempotency
+func (mr *MockAppIdempotencyServiceMockRecorder) CreateAppIdempotency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppIdempotency", reflect.TypeOf((*MockAppIdempotencyService)(nil).CreateAppIdempotency), arg0, arg1)
+}
+
+// DeleteExpiredAppIdempotency mocks base method
+func (m *MockAppIdempotencyService) DeleteExpiredAppIdempotency(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredAppIdempotency", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredAppIdempotency indicates an expected call of DeleteExpiredAppIdempotency
+func (mr *MockAppIdempotencyServiceMockRecorder) DeleteExpiredAppIdempotency(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredAppIdempotency", reflect.TypeOf((*MockAppIdempotencyService)(nil).DeleteExpiredAppIdempotency), arg0)
+}
+
+// GetAppIdempotency mocks base method
+func (m *MockAppIdempotencyService) GetAppIdempotency(arg0, arg1 string) (*models.AppIdempotency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppIdempotency", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppIdempotency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppIdempotency indicates an expected call of GetAppIdempotency
+func (mr *MockAppIdempotencyServiceMockRecorder) GetAppIdempotency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppIdempotency", reflect.TypeOf((*MockAppIdempotencyService)(nil).GetAppIdempotency), arg0, arg1)
+}
@@ -6,6 +6,7 @@ package service
 
 import (
 	common "github.com/baetyl/baetyl-cloud/v2/common"
+	models "github.com/baetyl/baetyl-cloud/v2/models"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
 )
@@ -63,6 +64,21 @@ func (mr *MockIndexServiceMockRecorder) ListAppIndexBySecret(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppIndexBySecret", reflect.TypeOf((*MockIndexService)(nil).ListAppIndexBySecret), arg0, arg1)
 }
 
+// ListAppNodesByLabel mocks base method
+func (m *MockIndexService) ListAppNodesByLabel(arg0, arg1, arg2 string) ([]models.AppNodeBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppNodesByLabel", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.AppNodeBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppNodesByLabel indicates an expected call of ListAppNodesByLabel
+func (mr *MockIndexServiceMockRecorder) ListAppNodesByLabel(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppNodesByLabel", reflect.TypeOf((*MockIndexService)(nil).ListAppNodesByLabel), arg0, arg1, arg2)
+}
+
 // ListAppsByNode mocks base method
 func (m *MockIndexService) ListAppsByNode(arg0, arg1 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +94,22 @@ func (mr *MockIndexServiceMockRecorder) ListAppsByNode(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppsByNode", reflect.TypeOf((*MockIndexService)(nil).ListAppsByNode), arg0, arg1)
 }
 
+// ListAppsPageByNode mocks base method
+func (m *MockIndexService) ListAppsPageByNode(arg0, arg1 string, arg2 *models.Filter) ([]string, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppsPageByNode", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAppsPageByNode indicates an expected call of ListAppsPageByNode
+func (mr *MockIndexServiceMockRecorder) ListAppsPageByNode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppsPageByNode", reflect.TypeOf((*MockIndexService)(nil).ListAppsPageByNode), arg0, arg1, arg2)
+}
+
 // ListConfigIndexByApp mocks base method
 func (m *MockIndexService) ListConfigIndexByApp(arg0, arg1 string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +155,22 @@ func (mr *MockIndexServiceMockRecorder) ListNodesByApp(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodesByApp", reflect.TypeOf((*MockIndexService)(nil).ListNodesByApp), arg0, arg1)
 }
 
+// ListNodesPageByApp mocks base method
+func (m *MockIndexService) ListNodesPageByApp(arg0, arg1 string, arg2 *models.Filter) ([]string, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNodesPageByApp", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListNodesPageByApp indicates an expected call of ListNodesPageByApp
+func (mr *MockIndexServiceMockRecorder) ListNodesPageByApp(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodesPageByApp", reflect.TypeOf((*MockIndexService)(nil).ListNodesPageByApp), arg0, arg1, arg2)
+}
+
 // RefreshAppIndexByConfig mocks base method
 func (m *MockIndexService) RefreshAppIndexByConfig(arg0 interface{}, arg1, arg2 string, arg3 []string) error {
 	m.ctrl.T.Helper()
@@ -193,6 +241,20 @@ func (mr *MockIndexServiceMockRecorder) RefreshNodesIndexByApp(arg0, arg1, arg2,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshNodesIndexByApp", reflect.TypeOf((*MockIndexService)(nil).RefreshNodesIndexByApp), arg0, arg1, arg2, arg3)
 }
 
+// RefreshNodesIndexByAppWithLabels mocks base method
+func (m *MockIndexService) RefreshNodesIndexByAppWithLabels(arg0 interface{}, arg1, arg2 string, arg3 map[string]string, arg4 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshNodesIndexByAppWithLabels", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshNodesIndexByAppWithLabels indicates an expected call of RefreshNodesIndexByAppWithLabels
+func (mr *MockIndexServiceMockRecorder) RefreshNodesIndexByAppWithLabels(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshNodesIndexByAppWithLabels", reflect.TypeOf((*MockIndexService)(nil).RefreshNodesIndexByAppWithLabels), arg0, arg1, arg2, arg3, arg4)
+}
+
 // RefreshSecretIndexByApp mocks base method
 func (m *MockIndexService) RefreshSecretIndexByApp(arg0 interface{}, arg1, arg2 string, arg3 []string) error {
 	m.ctrl.T.Helper()
@@ -33,6 +33,21 @@ func (m *MockLockerService) EXPECT() *MockLockerServiceMockRecorder {
 	return m.recorder
 }
 
+// Acquire mocks base method
+func (m *MockLockerService) Acquire(arg0 context.Context, arg1, arg2 string, arg3 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire
+func (mr *MockLockerServiceMockRecorder) Acquire(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockLockerService)(nil).Acquire), arg0, arg1, arg2, arg3)
+}
+
 // Lock mocks base method
 func (m *MockLockerService) Lock(arg0 context.Context, arg1 string, arg2 int64) (string, error) {
 	m.ctrl.T.Helper()
@@ -48,6 +63,21 @@ func (mr *MockLockerServiceMockRecorder) Lock(arg0, arg1, arg2 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLockerService)(nil).Lock), arg0, arg1, arg2)
 }
 
+// Renew mocks base method
+func (m *MockLockerService) Renew(arg0 context.Context, arg1, arg2 string, arg3 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew
+func (mr *MockLockerServiceMockRecorder) Renew(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockLockerService)(nil).Renew), arg0, arg1, arg2, arg3)
+}
+
 // Unlock mocks base method
 func (m *MockLockerService) Unlock(arg0 context.Context, arg1, arg2 string) {
 	m.ctrl.T.Helper()
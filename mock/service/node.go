@@ -168,19 +168,34 @@ func (mr *MockNodeServiceMockRecorder) List(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockNodeService)(nil).List), arg0, arg1)
 }
 
+// MatchNodes mocks base method
+func (m *MockNodeService) MatchNodes(arg0 interface{}, arg1, arg2 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchNodes", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchNodes indicates an expected call of MatchNodes
+func (mr *MockNodeServiceMockRecorder) MatchNodes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchNodes", reflect.TypeOf((*MockNodeService)(nil).MatchNodes), arg0, arg1, arg2)
+}
+
 // Update mocks base method
-func (m *MockNodeService) Update(arg0 string, arg1 *v1.Node) (*v1.Node, error) {
+func (m *MockNodeService) Update(arg0 interface{}, arg1 string, arg2 *v1.Node) (*v1.Node, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*v1.Node)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockNodeServiceMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockNodeServiceMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNodeService)(nil).Update), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNodeService)(nil).Update), arg0, arg1, arg2)
 }
 
 // UpdateDesire mocks base method
@@ -0,0 +1,120 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: AppOutboxService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAppOutboxService is a mock of AppOutboxService interface
+type MockAppOutboxService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppOutboxServiceMockRecorder
+}
+
+// MockAppOutboxServiceMockRecorder is the mock recorder for MockAppOutboxService
+type MockAppOutboxServiceMockRecorder struct {
+	mock *MockAppOutboxService
+}
+
+// NewMockAppOutboxService creates a new mock instance
+func NewMockAppOutboxService(ctrl *gomock.Controller) *MockAppOutboxService {
+	mock := &MockAppOutboxService{ctrl: ctrl}
+	mock.recorder = &MockAppOutboxServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppOutboxService) EXPECT() *MockAppOutboxServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAppOutbox mocks base method
+func (m *MockAppOutboxService) CreateAppOutbox(arg0 interface{}, arg1 *models.AppEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppOutbox", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppOutbox indicates an expected call of CreateAppOutbox
+func (mr *MockAppOutboxServiceMockRecorder) CreateAppOutbox(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppOutbox", reflect.TypeOf((*MockAppOutboxService)(nil).CreateAppOutbox), arg0, arg1)
+}
+
+// CreateCronOutbox mocks base method
+func (m *MockAppOutboxService) CreateCronOutbox(arg0 interface{}, arg1 *models.CronOutboxOp) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCronOutbox", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCronOutbox indicates an expected call of CreateCronOutbox
+func (mr *MockAppOutboxServiceMockRecorder) CreateCronOutbox(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCronOutbox", reflect.TypeOf((*MockAppOutboxService)(nil).CreateCronOutbox), arg0, arg1)
+}
+
+// DeleteAppOutbox mocks base method
+func (m *MockAppOutboxService) DeleteAppOutbox(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppOutbox", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppOutbox indicates an expected call of DeleteAppOutbox
+func (mr *MockAppOutboxServiceMockRecorder) DeleteAppOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppOutbox", reflect.TypeOf((*MockAppOutboxService)(nil).DeleteAppOutbox), arg0)
+}
+
+// DeleteCronOutbox mocks base method
+func (m *MockAppOutboxService) DeleteCronOutbox(arg0 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCronOutbox", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCronOutbox indicates an expected call of DeleteCronOutbox
+func (mr *MockAppOutboxServiceMockRecorder) DeleteCronOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCronOutbox", reflect.TypeOf((*MockAppOutboxService)(nil).DeleteCronOutbox), arg0)
+}
+
+// ListAppOutbox mocks base method
+func (m *MockAppOutboxService) ListAppOutbox(arg0 int) ([]models.AppOutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppOutbox", arg0)
+	ret0, _ := ret[0].([]models.AppOutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppOutbox indicates an expected call of ListAppOutbox
+func (mr *MockAppOutboxServiceMockRecorder) ListAppOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppOutbox", reflect.TypeOf((*MockAppOutboxService)(nil).ListAppOutbox), arg0)
+}
+
+// ListCronOutbox mocks base method
+func (m *MockAppOutboxService) ListCronOutbox(arg0 int) ([]models.CronOutboxOp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCronOutbox", arg0)
+	ret0, _ := ret[0].([]models.CronOutboxOp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCronOutbox indicates an expected call of ListCronOutbox
+func (mr *MockAppOutboxServiceMockRecorder) ListCronOutbox(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCronOutbox", reflect.TypeOf((*MockAppOutboxService)(nil).ListCronOutbox), arg0)
+}
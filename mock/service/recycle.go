@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/baetyl/baetyl-cloud/v2/service (interfaces: AppRecycleService)
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	models "github.com/baetyl/baetyl-cloud/v2/models"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockAppRecycleService is a mock of AppRecycleService interface
+type MockAppRecycleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAppRecycleServiceMockRecorder
+}
+
+// MockAppRecycleServiceMockRecorder is the mock recorder for MockAppRecycleService
+type MockAppRecycleServiceMockRecorder struct {
+	mock *MockAppRecycleService
+}
+
+// NewMockAppRecycleService creates a new mock instance
+func NewMockAppRecycleService(ctrl *gomock.Controller) *MockAppRecycleService {
+	mock := &MockAppRecycleService{ctrl: ctrl}
+	mock.recorder = &MockAppRecycleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAppRecycleService) EXPECT() *MockAppRecycleServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAppRecycle mocks base method
+func (m *MockAppRecycleService) CreateAppRecycle(arg0 interface{}, arg1 *models.AppRecycle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAppRecycle", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAppRecycle indicates an expected call of CreateAppRecycle
+func (mr *MockAppRecycleServiceMockRecorder) CreateAppRecycle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAppRecycle", reflect.TypeOf((*MockAppRecycleService)(nil).CreateAppRecycle), arg0, arg1)
+}
+
+// DeleteAppRecycle mocks base method
+func (m *MockAppRecycleService) DeleteAppRecycle(arg0 interface{}, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppRecycle", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppRecycle indicates an expected call of DeleteAppRecycle
+func (mr *MockAppRecycleServiceMockRecorder) DeleteAppRecycle(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppRecycle", reflect.TypeOf((*MockAppRecycleService)(nil).DeleteAppRecycle), arg0, arg1, arg2)
+}
+
+// GetAppRecycle mocks base method
+func (m *MockAppRecycleService) GetAppRecycle(arg0, arg1 string) (*models.AppRecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAppRecycle", arg0, arg1)
+	ret0, _ := ret[0].(*models.AppRecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppRecycle indicates an expected call of GetAppRecycle
+func (mr *MockAppRecycleServiceMockRecorder) GetAppRecycle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppRecycle", reflect.TypeOf((*MockAppRecycleService)(nil).GetAppRecycle), arg0, arg1)
+}
+
+// ListAppRecycle mocks base method
+func (m *MockAppRecycleService) ListAppRecycle(arg0 string) ([]models.AppRecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAppRecycle", arg0)
+	ret0, _ := ret[0].([]models.AppRecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAppRecycle indicates an expected call of ListAppRecycle
+func (mr *MockAppRecycleServiceMockRecorder) ListAppRecycle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAppRecycle", reflect.TypeOf((*MockAppRecycleService)(nil).ListAppRecycle), arg0)
+}
+
+// ListExpiredAppRecycle mocks base method
+func (m *MockAppRecycleService) ListExpiredAppRecycle(arg0 time.Time) ([]models.AppRecycle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredAppRecycle", arg0)
+	ret0, _ := ret[0].([]models.AppRecycle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredAppRecycle indicates an expected call of ListExpiredAppRecycle
+func (mr *MockAppRecycleServiceMockRecorder) ListExpiredAppRecycle(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredAppRecycle", reflect.TypeOf((*MockAppRecycleService)(nil).ListExpiredAppRecycle), arg0)
+}
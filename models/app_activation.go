@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// AppActivation the activation of a version of the app scheduled at ActivateAt, the version is stored
+// but not deployed to the nodes until it's activated
+type AppActivation struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Version    string    `json:"version"`
+	ActivateAt time.Time `json:"activateAt"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// AppFreeze the freeze of the app, the frozen app can't be updated or deleted until it's unfrozen
+type AppFreeze struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	FrozenBy   string    `json:"frozenBy"`
+	FreezeTime time.Time `json:"freezeTime"`
+}
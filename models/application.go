@@ -6,6 +6,12 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 )
 
+// AppRef refers to the version of the app, the current version if the version is empty
+type AppRef struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
 type ApplicationView struct {
 	Name              string                `json:"name,omitempty" validate:"resourceName"`
 	Mode              string                `json:"mode,omitempty" default:"kube"`
@@ -40,6 +46,7 @@ type AppItem struct {
 	Mode              string                `json:"mode,omitempty" default:"kube"`
 	Type              string                `json:"type,omitempty" default:"container"`
 	Labels            map[string]string     `json:"labels,omitempty"`
+	Annotations       map[string]string     `json:"annotations,omitempty"`
 	Selector          string                `json:"selector"`
 	NodeSelector      string                `json:"nodeSelector"`
 	Version           string                `json:"version,omitempty"`
@@ -49,6 +56,7 @@ type AppItem struct {
 	System            bool                  `json:"system,omitempty"`
 	CronStatus        specV1.CronStatusCode `json:"cronStatus,omitempty" default:"0"`
 	CronTime          time.Time             `json:"cronTime,omitempty"`
+	CronTimes         []time.Time           `json:"cronTimes,omitempty"`
 }
 
 // ApplicationList app List
@@ -58,6 +66,20 @@ type ApplicationList struct {
 	Items        []AppItem `json:"items"`
 }
 
+// AppNodeList the page of the nodes which the app is deployed to
+type AppNodeList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []string `json:"items"`
+}
+
+// NodeAppList the page of the apps which the node is bound to
+type NodeAppList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []*specV1.Application `json:"items"`
+}
+
 type ServiceFunction struct {
 	Functions []specV1.ServiceFunction `json:"functions,omitempty"`
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// AppAudit the audit record of an application mutation
+type AppAudit struct {
+	Id         uint64    `json:"id,omitempty"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Action     AppAction `json:"action"`
+	Actor      string    `json:"actor"`
+	OldVersion string    `json:"oldVersion,omitempty"`
+	NewVersion string    `json:"newVersion,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
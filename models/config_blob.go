@@ -0,0 +1,45 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// ConfigBlobRefPrefix prefixes the value of the config which references a blob by its hash instead of carrying it
+const ConfigBlobRefPrefix = "baetyl-config-blob:sha256:"
+
+// ConfigBlob the large value of the configs stored once in the content-addressed store, keyed by its hash
+type ConfigBlob struct {
+	Namespace  string    `json:"namespace"`
+	Hash       string    `json:"hash"`
+	Data       []byte    `json:"data"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+// ConfigBlobHash returns the SHA-256 of the value in hex, by which the value is stored in the blob store
+func ConfigBlobHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// ConfigBlobRef returns the value referencing the blob of the hash
+func ConfigBlobRef(hash string) string {
+	return ConfigBlobRefPrefix + hash
+}
+
+// ParseConfigBlobRef returns the hash of the blob the value references, false if it doesn't reference a blob
+func ParseConfigBlobRef(value string) (string, bool) {
+	if !strings.HasPrefix(value, ConfigBlobRefPrefix) {
+		return "", false
+	}
+	hash := strings.TrimPrefix(value, ConfigBlobRefPrefix)
+	if len(hash) != sha256.Size*2 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", false
+	}
+	return hash, true
+}
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"reflect"
+	"sort"
 	"time"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
@@ -57,3 +61,18 @@ func EqualConfig(config1, config2 *specV1.Configuration) bool {
 		reflect.DeepEqual(config1.Data, config2.Data) &&
 		reflect.DeepEqual(config1.Description, config2.Description)
 }
+
+// ConfigChecksum returns the first 128 bits of the SHA-256 of the data of the config in hex, which fits
+// in a label value. The entries are sorted by key and length-prefixed so that the checksum is unambiguous.
+func ConfigChecksum(cfg *specV1.Configuration) string {
+	keys := make([]string, 0, len(cfg.Data))
+	for k := range cfg.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%d:%s%d:%s", len(k), k, len(cfg.Data[k]), cfg.Data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -2,10 +2,139 @@ package models
 
 import "time"
 
+// the policies applied to the cron times missed by the scheduler, e.g. while it's down
+const (
+	// MisfireSkip the missed cron times are not fired
+	MisfireSkip = "skip"
+	// MisfireFireOnce the missed cron times are fired once on recovery
+	MisfireFireOnce = "fireOnce"
+	// MisfireFireAll each of the missed cron times is fired on recovery
+	MisfireFireAll = "fireAll"
+)
+
+// the policies applied to the run of the cron while the previous run of the same app is still applying
+const (
+	// OverlapAllow the runs are applied concurrently
+	OverlapAllow = "allow"
+	// OverlapSkip the run is skipped, it's for the crons whose later run supersedes the previous one
+	OverlapSkip = "skip"
+	// OverlapQueue the run waits until the previous run is applied, so the runs are applied one by one in order
+	OverlapQueue = "queue"
+)
+
+// the policies applied to the run of the cron whose selector matches no nodes
+const (
+	// NoNodesProceed the run is applied even though it deploys the app to no nodes
+	NoNodesProceed = "proceed"
+	// NoNodesSkip the run is skipped and recorded as failed, since the selector matching no nodes is usually a mistake
+	NoNodesSkip = "skip"
+)
+
 type Cron struct {
-	Id        uint64    `json:"id,omitempty"`
-	Namespace string    `json:"namespace,omitempty"`
-	Name      string    `json:"name,omitempty" validate:"resourceName"`
-	Selector  string    `json:"selector,omitempty"`
-	CronTime  time.Time `json:"cronTime,omitempty"`
+	Id        uint64 `json:"id,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty" validate:"resourceName"`
+	Selector  string `json:"selector,omitempty"`
+	// CronTime the time when the cron fires next, which is the earliest of the cron times
+	CronTime time.Time `json:"cronTime,omitempty"`
+	// CronTimes all the times of the cron if it's scheduled by more than one, the cron fires on any of them
+	CronTimes []time.Time `json:"cronTimes,omitempty"`
+	// Timezone IANA name of the zone in which the cron time is interpreted, UTC if empty
+	Timezone string `json:"timezone,omitempty"`
+	// Paused the paused cron is kept but not fired
+	Paused bool `json:"paused,omitempty"`
+	// MisfirePolicy the policy applied to the missed cron times, MisfireSkip if empty
+	MisfirePolicy string `json:"misfirePolicy,omitempty"`
+	// OverlapPolicy the policy applied to the overlapping runs, OverlapAllow if empty
+	OverlapPolicy string `json:"overlapPolicy,omitempty"`
+	// NoNodesPolicy the policy applied to the run matching no nodes, NoNodesProceed if empty
+	NoNodesPolicy string `json:"noNodesPolicy,omitempty"`
+	// SelectorResolver the resolver which resolves the selector as its query to the nodes when the cron fires,
+	// the selector is the label selector if empty
+	SelectorResolver string `json:"selectorResolver,omitempty"`
+}
+
+// Schedules returns all the times of the cron, the cron scheduled by a single cron time is a one-element list
+func (c *Cron) Schedules() []time.Time {
+	if len(c.CronTimes) > 0 {
+		return c.CronTimes
+	}
+	return []time.Time{c.CronTime}
+}
+
+// ValidMisfirePolicy checks the misfire policy is known, the empty one is MisfireSkip
+func ValidMisfirePolicy(policy string) bool {
+	switch policy {
+	case "", MisfireSkip, MisfireFireOnce, MisfireFireAll:
+		return true
+	}
+	return false
+}
+
+// ValidOverlapPolicy checks the overlap policy is known, the empty one is OverlapAllow
+func ValidOverlapPolicy(policy string) bool {
+	switch policy {
+	case "", OverlapAllow, OverlapSkip, OverlapQueue:
+		return true
+	}
+	return false
+}
+
+// ValidNoNodesPolicy checks the no-nodes policy is known, the empty one is NoNodesProceed
+func ValidNoNodesPolicy(policy string) bool {
+	switch policy {
+	case "", NoNodesProceed, NoNodesSkip:
+		return true
+	}
+	return false
+}
+
+// the operations on the crons of the apps written to the outbox
+const (
+	// CronOutboxPut creates the cron or updates it if it exists
+	CronOutboxPut = "put"
+	// CronOutboxDelete deletes the cron if it exists
+	CronOutboxDelete = "delete"
+)
+
+// CronOutboxOp the operation on the cron of the app written to the outbox within the transaction of the app change,
+// which is relayed to the cron store after the change is committed. The put carries the whole cron and the delete
+// carries nothing, so applying the operation again leaves the cron the same
+type CronOutboxOp struct {
+	Id        uint64 `json:"id,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Op        string `json:"op,omitempty"`
+	Cron      *Cron  `json:"cron,omitempty"`
+}
+
+// the triggers of the runs of the crons
+const (
+	// CronRunScheduled the run fired by the scheduler on the cron time
+	CronRunScheduled = "scheduled"
+	// CronRunManual the run triggered manually by TriggerCronApp
+	CronRunManual = "manual"
+)
+
+// CronRun the record of a run of the cron which applies the app to the nodes matched by the selector of the cron
+type CronRun struct {
+	Id        uint64 `json:"id,omitempty"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Version the version of the app applied
+	Version string `json:"version,omitempty"`
+	Trigger string `json:"trigger"`
+	// Nodes the number of the nodes resolved by the selector of the cron, 0 if the run failed
+	Nodes   int  `json:"nodes"`
+	Success bool `json:"success"`
+	// Message the error of the failed run
+	Message string    `json:"message,omitempty"`
+	RunTime time.Time `json:"runTime"`
+}
+
+// CronRunList the page of the runs of the cron of an app from the latest
+type CronRunList struct {
+	Total        int `json:"total"`
+	*ListOptions `json:",inline"`
+	Items        []CronRun `json:"items"`
 }
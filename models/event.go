@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type AppAction string
+
+const (
+	AppCreated AppAction = "create"
+	AppUpdated AppAction = "update"
+	AppDeleted AppAction = "delete"
+	// AppActivated the version of the app scheduled to activate is deployed to the nodes
+	AppActivated AppAction = "activate"
+)
+
+// AppEvent the event of application lifecycle, published after the change is committed
+type AppEvent struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Version   string    `json:"version,omitempty"`
+	Action    AppAction `json:"action"`
+	Nodes     []string  `json:"nodes"`
+	Timestamp time.Time `json:"timestamp"`
+	// Sequence numbers the events of the namespace in the order they're committed within the process
+	Sequence uint64 `json:"sequence,omitempty"`
+}
+
+// AppOutboxEvent the app event written to the outbox within the transaction of the change,
+// which is relayed to the sink after the change is committed
+type AppOutboxEvent struct {
+	Id    uint64   `json:"id"`
+	Event AppEvent `json:"event"`
+}
@@ -19,9 +19,11 @@ type ListOptions struct {
 	LabelSelector string `form:"selector,omitempty" json:"selector,omitempty"`
 	NodeSelector  string `form:"nodeSelector,omitempty" json:"nodeSelector,omitempty"`
 	FieldSelector string `form:"fieldSelector,omitempty" json:"fieldSelector,omitempty"`
-	Limit         int64  `form:"limit,omitempty" json:"limit,omitempty"`
-	Continue      string `form:"continue,omitempty" json:"continue,omitempty"`
-	Filter        `json:",inline"`
+	// AnnotationSelector selects the apps by the annotations, e.g. "team=infra,cost-center in (rd)"
+	AnnotationSelector string `form:"annotationSelector,omitempty" json:"annotationSelector,omitempty"`
+	Limit              int64  `form:"limit,omitempty" json:"limit,omitempty"`
+	Continue           string `form:"continue,omitempty" json:"continue,omitempty"`
+	Filter             `json:",inline"`
 }
 
 func (f *Filter) GetLimitOffset() int {
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// AppVersion a version of the application kept in the history, the pinned version is never pruned
+type AppVersion struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	Version    string    `json:"version"`
+	Pinned     bool      `json:"pinned"`
+	CreateTime time.Time `json:"createTime"`
+}
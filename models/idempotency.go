@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// AppIdempotency the application created with the idempotency key, the key is reusable after expired
+type AppIdempotency struct {
+	Namespace  string    `json:"namespace"`
+	Key        string    `json:"key"`
+	Name       string    `json:"name"`
+	ExpireTime time.Time `json:"expireTime"`
+}
@@ -4,3 +4,9 @@ type Index struct {
 	a string `json:"a" db:"a"`
 	b string `json:"b" db:"b"`
 }
+
+// AppNodeBinding a node indexed by an app
+type AppNodeBinding struct {
+	App  string `json:"app" db:"application"`
+	Node string `json:"node" db:"node"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+)
+
+// AppRecycle the application soft deleted, whose spec is kept in the recycle bin until purged
+type AppRecycle struct {
+	Namespace  string              `json:"namespace"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version"`
+	App        *specV1.Application `json:"app"`
+	DeleteTime time.Time           `json:"deleteTime"`
+}
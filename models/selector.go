@@ -0,0 +1,102 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+)
+
+// LabelSelector the structured form of the label selector targeting the nodes, which supports
+// matchLabels and matchExpressions (In, NotIn, Exists and DoesNotExist), e.g.
+// {"matchExpressions": [{"key": "env", "operator": "In", "values": ["prod", "staging"]}]}
+type LabelSelector = metav1.LabelSelector
+
+// ParseLabelSelector parses the serialized form of the label selector, e.g. "app=a,env in (prod,staging),!deprecated",
+// "key!=value" is parsed as "key notin (value)". The numeric comparisons (gt and lt) are not supported.
+func ParseLabelSelector(selector string) (*LabelSelector, error) {
+	reqs, err := labels.ParseToRequirements(selector)
+	if err != nil {
+		return nil, err
+	}
+	res := &LabelSelector{}
+	for _, r := range reqs {
+		values := r.Values().List()
+		switch r.Operator() {
+		case selection.Equals, selection.DoubleEquals:
+			if res.MatchLabels == nil {
+				res.MatchLabels = map[string]string{}
+			}
+			res.MatchLabels[r.Key()] = values[0]
+			continue
+		case selection.In:
+			res.MatchExpressions = append(res.MatchExpressions, newRequirement(r.Key(), metav1.LabelSelectorOpIn, values))
+		case selection.NotEquals, selection.NotIn:
+			res.MatchExpressions = append(res.MatchExpressions, newRequirement(r.Key(), metav1.LabelSelectorOpNotIn, values))
+		case selection.Exists:
+			res.MatchExpressions = append(res.MatchExpressions, newRequirement(r.Key(), metav1.LabelSelectorOpExists, nil))
+		case selection.DoesNotExist:
+			res.MatchExpressions = append(res.MatchExpressions, newRequirement(r.Key(), metav1.LabelSelectorOpDoesNotExist, nil))
+		default:
+			return nil, fmt.Errorf("the operator (%s) of the label selector is not supported", r.Operator())
+		}
+	}
+	return res, nil
+}
+
+// FormatLabelSelector serializes the label selector with the requirements sorted by key,
+// an empty string is returned if the selector is nil or empty
+func FormatLabelSelector(selector *LabelSelector) (string, error) {
+	if selector == nil {
+		return "", nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+func newRequirement(key string, op metav1.LabelSelectorOperator, values []string) metav1.LabelSelectorRequirement {
+	return metav1.LabelSelectorRequirement{Key: key, Operator: op, Values: values}
+}
+
+// AnnotationSelectorToLabelSelector translates the selector of the app annotations to the selector of the labels
+// which carry the annotations, so that the apps are selected by the store
+func AnnotationSelectorToLabelSelector(selector string) (string, error) {
+	ls, err := ParseLabelSelector(selector)
+	if err != nil {
+		return "", err
+	}
+	res := &LabelSelector{}
+	for k, v := range ls.MatchLabels {
+		if res.MatchLabels == nil {
+			res.MatchLabels = map[string]string{}
+		}
+		res.MatchLabels[common.LabelAnnotationPrefix+k] = v
+	}
+	for _, r := range ls.MatchExpressions {
+		r.Key = common.LabelAnnotationPrefix + r.Key
+		res.MatchExpressions = append(res.MatchExpressions, r)
+	}
+	return FormatLabelSelector(res)
+}
+
+// AppAnnotations returns the annotations carried by the labels of the app, nil is returned if there is none
+func AppAnnotations(labels map[string]string) map[string]string {
+	var res map[string]string
+	for k, v := range labels {
+		if !strings.HasPrefix(k, common.LabelAnnotationPrefix) {
+			continue
+		}
+		if res == nil {
+			res = map[string]string{}
+		}
+		res[strings.TrimPrefix(k, common.LabelAnnotationPrefix)] = v
+	}
+	return res
+}
@@ -0,0 +1,20 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/app_activation.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppActivation
+
+type AppActivation interface {
+	// CreateAppActivation schedules the activation within the transaction, replacing the one of the app if exists
+	CreateAppActivation(tx interface{}, activation *models.AppActivation) error
+	GetAppActivation(namespace, name string) (*models.AppActivation, error)
+	DeleteAppActivation(tx interface{}, namespace, name string) error
+	// ListDueAppActivations lists the earliest activations of all namespaces due before the time
+	ListDueAppActivations(before time.Time, limit int) ([]models.AppActivation, error)
+	io.Closer
+}
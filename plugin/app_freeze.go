@@ -0,0 +1,18 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/app_freeze.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppFreeze
+
+type AppFreeze interface {
+	// CreateAppFreeze freezes the app within the transaction, the freeze of the app frozen already is kept
+	CreateAppFreeze(tx interface{}, freeze *models.AppFreeze) error
+	// GetAppFreeze gets the freeze of the app, within the transaction if tx is not nil
+	GetAppFreeze(tx interface{}, namespace, name string) (*models.AppFreeze, error)
+	DeleteAppFreeze(tx interface{}, namespace, name string) error
+	io.Closer
+}
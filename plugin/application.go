@@ -10,6 +10,11 @@ import (
 
 type Application interface {
 	GetApplication(namespace, name, version string) (*v1.Application, error)
+	// GetApplicationForUpdate gets the current version of the app in the transaction, the store with transactions
+	// holds the app against the concurrent writes until the transaction ends
+	GetApplicationForUpdate(tx interface{}, namespace, name string) (*v1.Application, error)
+	// GetApplications gets the current versions of the apps by a single query, the apps not found are omitted
+	GetApplications(namespace string, names []string) ([]*v1.Application, error)
 	CreateApplication(tx interface{}, namespace string, application *v1.Application) (*v1.Application, error)
 	UpdateApplication(tx interface{}, namespace string, application *v1.Application) (*v1.Application, error)
 	DeleteApplication(tx interface{}, namespace, name string) error
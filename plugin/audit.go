@@ -0,0 +1,17 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/audit.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppAudit
+
+type AppAudit interface {
+	// CreateAppAudit writes the audit within the transaction, so that it is committed or rolled back with the mutation
+	CreateAppAudit(tx interface{}, audit *models.AppAudit) error
+	// ListAppAudit lists the audits of the app from the latest
+	ListAppAudit(namespace, name string) ([]models.AppAudit, error)
+	io.Closer
+}
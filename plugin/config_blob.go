@@ -0,0 +1,22 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/config_blob.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin ConfigBlob
+
+// ConfigBlob the content-addressed store of the large values of the configs, each blob is stored once
+// and counted by the configs referencing it, and deleted once the last reference is dropped
+type ConfigBlob interface {
+	// CreateConfigBlob stores the blob within the transaction, the blob of the same hash stored already is kept
+	CreateConfigBlob(tx interface{}, blob *models.ConfigBlob) error
+	// GetConfigBlob gets the blob by its hash, within the transaction if tx is not nil
+	GetConfigBlob(tx interface{}, namespace, hash string) (*models.ConfigBlob, error)
+	// ReplaceConfigBlobRefs replaces the blobs referenced by the config with the ones of the hashes,
+	// the blobs dropped which aren't referenced by any other config are deleted
+	ReplaceConfigBlobRefs(tx interface{}, namespace, config string, hashes []string) error
+	io.Closer
+}
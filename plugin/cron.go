@@ -9,11 +9,26 @@ import (
 //go:generate mockgen -destination=../mock/plugin/cron.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Cron
 
 type Cron interface {
-	GetCron(name, namespace string) (*models.Cron, error)
+	// GetCron gets the cron of the app, within the transaction if tx is not nil
+	GetCron(tx interface{}, name, namespace string) (*models.Cron, error)
+	// ListCrons lists the crons of the apps of the namespace, within the transaction if tx is not nil,
+	// the crons not found are omitted
+	ListCrons(tx interface{}, namespace string, names []string) ([]models.Cron, error)
+	// ListCronsPage lists the page of all the crons of the namespace in the order they're created and the total number of the crons
+	ListCronsPage(namespace string, filter *models.Filter) ([]models.Cron, int, error)
 	CreateCron(*models.Cron) error
 	UpdateCron(*models.Cron) error
+	// SetCronPaused pauses or resumes the cron, the paused cron is skipped by ListExpiredApps
+	SetCronPaused(name, namespace string, paused bool) error
 	DeleteCron(name, namespace string) error
 	ListExpiredApps() ([]models.Cron, error)
+	// DeleteExpiredApps completes the expired crons fired, the cron scheduled by more than one time is advanced
+	// to the next of its times after now so that it fires again then, and the cron with no time left is deleted
 	DeleteExpiredApps([]uint64) error
+	// CreateCronRun records the run of the cron, within the transaction if tx is not nil,
+	// so that the run applied is recorded with the nodes it applies the app to
+	CreateCronRun(tx interface{}, run *models.CronRun) error
+	// ListCronRuns lists the page of the runs of the cron of the app from the latest and the total number of the runs
+	ListCronRuns(namespace, name string, filter *models.Filter) ([]models.CronRun, int, error)
 	io.Closer
 }
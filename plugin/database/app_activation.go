@@ -0,0 +1,67 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *DB) CreateAppActivation(tx interface{}, activation *models.AppActivation) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	// the activation scheduled before is replaced
+	deleteSQL := `DELETE FROM baetyl_app_activation WHERE namespace=? AND name=?`
+	if _, err := d.Exec(transaction, deleteSQL, activation.Namespace, activation.Name); err != nil {
+		return err
+	}
+	insertSQL := `
+INSERT INTO baetyl_app_activation (namespace, name, version, activate_at) 
+VALUES (?,?,?,?)`
+	_, err := d.Exec(transaction, insertSQL, activation.Namespace, activation.Name, activation.Version, activation.ActivateAt.UTC())
+	return err
+}
+
+func (d *DB) GetAppActivation(namespace, name string) (*models.AppActivation, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, activate_at 
+FROM baetyl_app_activation WHERE namespace=? AND name=?`
+	var activations []entities.AppActivation
+	if err := d.Query(nil, selectSQL, &activations, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(activations) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "appActivation"), common.Field("name", name))
+	}
+	return entities.ToAppActivationModel(&activations[0]), nil
+}
+
+func (d *DB) DeleteAppActivation(tx interface{}, namespace, name string) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	deleteSQL := `DELETE FROM baetyl_app_activation WHERE namespace=? AND name=?`
+	_, err := d.Exec(transaction, deleteSQL, namespace, name)
+	return err
+}
+
+func (d *DB) ListDueAppActivations(before time.Time, limit int) ([]models.AppActivation, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, activate_at 
+FROM baetyl_app_activation WHERE activate_at <= ? ORDER BY activate_at, id LIMIT ?`
+	var activations []entities.AppActivation
+	if err := d.Query(nil, selectSQL, &activations, before.UTC(), limit); err != nil {
+		return nil, err
+	}
+	res := make([]models.AppActivation, 0, len(activations))
+	for i := range activations {
+		res = append(res, *entities.ToAppActivationModel(&activations[i]))
+	}
+	return res, nil
+}
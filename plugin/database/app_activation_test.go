@@ -0,0 +1,91 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appActivationTables = []string{
+		`
+CREATE TABLE baetyl_app_activation(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    name        VARCHAR(128) NOT NULL DEFAULT '',
+    version     VARCHAR(36) NOT NULL DEFAULT '',
+    activate_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (namespace, name)
+);
+`,
+	}
+)
+
+func TestAppActivation(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(appActivationTables)
+
+	ns := "cloud"
+	now := time.Now().UTC().Truncate(time.Second)
+	a1 := &models.AppActivation{Namespace: ns, Name: "app1", Version: "1", ActivateAt: now.Add(time.Hour)}
+	a2 := &models.AppActivation{Namespace: "other", Name: "app2", Version: "5", ActivateAt: now.Add(-time.Hour)}
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppActivation(tx, a1)
+	assert.NoError(t, err)
+	db.Rollback(tx)
+	_, err = db.GetAppActivation(ns, "app1")
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrResourceNotFound, e.Code())
+
+	tx, err = db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppActivation(tx, a1)
+	assert.NoError(t, err)
+	db.Commit(tx)
+	err = db.CreateAppActivation(nil, a2)
+	assert.NoError(t, err)
+
+	res, err := db.GetAppActivation(ns, "app1")
+	assert.NoError(t, err)
+	assert.Equal(t, a1, res)
+
+	// the activation of the app is replaced
+	a1 = &models.AppActivation{Namespace: ns, Name: "app1", Version: "2", ActivateAt: now.Add(-time.Minute)}
+	err = db.CreateAppActivation(nil, a1)
+	assert.NoError(t, err)
+	res, err = db.GetAppActivation(ns, "app1")
+	assert.NoError(t, err)
+	assert.Equal(t, a1, res)
+
+	// listed from the earliest across the namespaces
+	due, err := db.ListDueAppActivations(now, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AppActivation{*a2, *a1}, due)
+	due, err = db.ListDueAppActivations(now, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AppActivation{*a2}, due)
+	due, err = db.ListDueAppActivations(now.Add(-2*time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 0)
+
+	err = db.DeleteAppActivation(nil, "other", "app2")
+	assert.NoError(t, err)
+	due, err = db.ListDueAppActivations(now, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AppActivation{*a1}, due)
+}
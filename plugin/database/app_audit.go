@@ -0,0 +1,36 @@
+package database
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *DB) CreateAppAudit(tx interface{}, audit *models.AppAudit) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	insertSQL := `
+INSERT INTO baetyl_app_audit (namespace, name, action, actor, old_version, new_version, create_time) 
+VALUES (?,?,?,?,?,?,?)`
+	_, err := d.Exec(transaction, insertSQL, audit.Namespace, audit.Name, string(audit.Action),
+		audit.Actor, audit.OldVersion, audit.NewVersion, audit.Timestamp)
+	return err
+}
+
+func (d *DB) ListAppAudit(namespace, name string) ([]models.AppAudit, error) {
+	selectSQL := `
+SELECT id, namespace, name, action, actor, old_version, new_version, create_time 
+FROM baetyl_app_audit WHERE namespace=? AND name=? ORDER BY id DESC`
+	var audits []entities.AppAudit
+	if err := d.Query(nil, selectSQL, &audits, namespace, name); err != nil {
+		return nil, err
+	}
+	res := make([]models.AppAudit, 0, len(audits))
+	for i := range audits {
+		res = append(res, *entities.ToAppAuditModel(&audits[i]))
+	}
+	return res, nil
+}
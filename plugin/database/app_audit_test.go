@@ -0,0 +1,84 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appAuditTables = []string{
+		`
+CREATE TABLE baetyl_app_audit(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    name        VARCHAR(128) NOT NULL DEFAULT '',
+    action      VARCHAR(32) NOT NULL DEFAULT '',
+    actor       VARCHAR(128) NOT NULL DEFAULT '',
+    old_version VARCHAR(36) NOT NULL DEFAULT '',
+    new_version VARCHAR(36) NOT NULL DEFAULT '',
+    create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func TestAppAudit(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(appAuditTables)
+
+	ns, name := "cloud", "baetyl"
+	now := time.Now().UTC().Truncate(time.Second)
+	err = db.CreateAppAudit(nil, &models.AppAudit{
+		Namespace:  ns,
+		Name:       name,
+		Action:     models.AppCreated,
+		Actor:      "user",
+		NewVersion: "1",
+		Timestamp:  now,
+	})
+	assert.NoError(t, err)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppAudit(tx, &models.AppAudit{Namespace: ns, Name: name, Action: models.AppDeleted, Timestamp: now})
+	assert.NoError(t, err)
+	db.Rollback(tx)
+
+	tx, err = db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppAudit(tx, &models.AppAudit{
+		Namespace:  ns,
+		Name:       name,
+		Action:     models.AppUpdated,
+		Actor:      "user",
+		OldVersion: "1",
+		NewVersion: "2",
+		Timestamp:  now,
+	})
+	assert.NoError(t, err)
+	db.Commit(tx)
+
+	res, err := db.ListAppAudit(ns, name)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, models.AppUpdated, res[0].Action)
+	assert.Equal(t, "1", res[0].OldVersion)
+	assert.Equal(t, "2", res[0].NewVersion)
+	assert.Equal(t, now, res[0].Timestamp)
+	assert.Equal(t, models.AppCreated, res[1].Action)
+	assert.Equal(t, "user", res[1].Actor)
+
+	res, err = db.ListAppAudit(ns, "none")
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+}
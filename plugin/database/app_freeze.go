@@ -0,0 +1,58 @@
+package database
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *DB) CreateAppFreeze(tx interface{}, freeze *models.AppFreeze) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	selectSQL := `SELECT id FROM baetyl_app_freeze WHERE namespace=? AND name=?`
+	var freezes []entities.AppFreeze
+	if err := d.Query(transaction, selectSQL, &freezes, freeze.Namespace, freeze.Name); err != nil {
+		return err
+	}
+	// the app is frozen already
+	if len(freezes) > 0 {
+		return nil
+	}
+	insertSQL := `
+INSERT INTO baetyl_app_freeze (namespace, name, frozen_by, freeze_time) 
+VALUES (?,?,?,?)`
+	_, err := d.Exec(transaction, insertSQL, freeze.Namespace, freeze.Name, freeze.FrozenBy, freeze.FreezeTime.UTC())
+	return err
+}
+
+func (d *DB) GetAppFreeze(tx interface{}, namespace, name string) (*models.AppFreeze, error) {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	selectSQL := `
+SELECT id, namespace, name, frozen_by, freeze_time 
+FROM baetyl_app_freeze WHERE namespace=? AND name=?`
+	var freezes []entities.AppFreeze
+	if err := d.Query(transaction, selectSQL, &freezes, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(freezes) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "appFreeze"), common.Field("name", name))
+	}
+	return entities.ToAppFreezeModel(&freezes[0]), nil
+}
+
+func (d *DB) DeleteAppFreeze(tx interface{}, namespace, name string) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	deleteSQL := `DELETE FROM baetyl_app_freeze WHERE namespace=? AND name=?`
+	_, err := d.Exec(transaction, deleteSQL, namespace, name)
+	return err
+}
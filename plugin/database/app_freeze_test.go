@@ -0,0 +1,68 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appFreezeTables = []string{
+		`
+CREATE TABLE baetyl_app_freeze(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    name        VARCHAR(128) NOT NULL DEFAULT '',
+    frozen_by   VARCHAR(128) NOT NULL DEFAULT '',
+    freeze_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (namespace, name)
+);
+`,
+	}
+)
+
+func TestAppFreeze(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(appFreezeTables)
+
+	ns := "cloud"
+	now := time.Now().UTC().Truncate(time.Second)
+	f1 := &models.AppFreeze{Namespace: ns, Name: "app1", FrozenBy: "admin", FreezeTime: now}
+
+	_, err = db.GetAppFreeze(nil, ns, "app1")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CreateAppFreeze(tx, f1))
+	res, err := db.GetAppFreeze(tx, ns, "app1")
+	assert.NoError(t, err)
+	assert.Equal(t, f1, res)
+	db.Rollback(tx)
+	_, err = db.GetAppFreeze(nil, ns, "app1")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	assert.NoError(t, db.CreateAppFreeze(nil, f1))
+	// the freeze of the app frozen already is kept
+	assert.NoError(t, db.CreateAppFreeze(nil, &models.AppFreeze{Namespace: ns, Name: "app1", FrozenBy: "other", FreezeTime: now.Add(time.Hour)}))
+	res, err = db.GetAppFreeze(nil, ns, "app1")
+	assert.NoError(t, err)
+	assert.Equal(t, f1, res)
+
+	assert.NoError(t, db.DeleteAppFreeze(nil, ns, "app1"))
+	_, err = db.GetAppFreeze(nil, ns, "app1")
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+	// deleting the freeze missing is ok
+	assert.NoError(t, db.DeleteAppFreeze(nil, ns, "app1"))
+}
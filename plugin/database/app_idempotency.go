@@ -0,0 +1,51 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *DB) GetAppIdempotency(namespace, key string) (*models.AppIdempotency, error) {
+	selectSQL := `
+SELECT id, namespace, idempotency_key, name, expire_time, create_time 
+FROM baetyl_app_idempotency WHERE namespace=? AND idempotency_key=? AND expire_time > ?`
+	var idempotencies []entities.AppIdempotency
+	if err := d.Query(nil, selectSQL, &idempotencies, namespace, key, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if len(idempotencies) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "appIdempotency"), common.Field("name", key))
+	}
+	return entities.ToAppIdempotencyModel(&idempotencies[0]), nil
+}
+
+func (d *DB) CreateAppIdempotency(tx interface{}, idempotency *models.AppIdempotency) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	// the expired key is replaced
+	deleteSQL := `DELETE FROM baetyl_app_idempotency WHERE namespace=? AND idempotency_key=? AND expire_time <= ?`
+	if _, err := d.Exec(transaction, deleteSQL, idempotency.Namespace, idempotency.Key, time.Now().UTC()); err != nil {
+		return err
+	}
+	insertSQL := `
+INSERT INTO baetyl_app_idempotency (namespace, idempotency_key, name, expire_time) 
+VALUES (?,?,?,?)`
+	_, err := d.Exec(transaction, insertSQL, idempotency.Namespace, idempotency.Key, idempotency.Name, idempotency.ExpireTime)
+	return err
+}
+
+func (d *DB) DeleteExpiredAppIdempotency(before time.Time) (int64, error) {
+	deleteSQL := `DELETE FROM baetyl_app_idempotency WHERE expire_time <= ?`
+	res, err := d.Exec(nil, deleteSQL, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
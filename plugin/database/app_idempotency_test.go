@@ -0,0 +1,81 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appIdempotencyTables = []string{
+		`
+CREATE TABLE baetyl_app_idempotency(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace       VARCHAR(64) NOT NULL DEFAULT '',
+    idempotency_key VARCHAR(128) NOT NULL DEFAULT '',
+    name            VARCHAR(128) NOT NULL DEFAULT '',
+    expire_time     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_time     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (namespace, idempotency_key)
+);
+`,
+	}
+)
+
+func TestAppIdempotency(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(appIdempotencyTables)
+
+	ns := "cloud"
+	expire := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	_, err = db.GetAppIdempotency(ns, "k1")
+	assert.Error(t, err)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppIdempotency(tx, &models.AppIdempotency{Namespace: ns, Key: "k1", Name: "app1", ExpireTime: expire})
+	assert.NoError(t, err)
+	db.Rollback(tx)
+	_, err = db.GetAppIdempotency(ns, "k1")
+	assert.Error(t, err)
+
+	err = db.CreateAppIdempotency(nil, &models.AppIdempotency{Namespace: ns, Key: "k1", Name: "app1", ExpireTime: expire})
+	assert.NoError(t, err)
+	res, err := db.GetAppIdempotency(ns, "k1")
+	assert.NoError(t, err)
+	assert.Equal(t, &models.AppIdempotency{Namespace: ns, Key: "k1", Name: "app1", ExpireTime: expire}, res)
+
+	// duplicated
+	err = db.CreateAppIdempotency(nil, &models.AppIdempotency{Namespace: ns, Key: "k1", Name: "app2", ExpireTime: expire})
+	assert.Error(t, err)
+	// the same key of another namespace
+	err = db.CreateAppIdempotency(nil, &models.AppIdempotency{Namespace: "other", Key: "k1", Name: "app2", ExpireTime: expire})
+	assert.NoError(t, err)
+
+	// the expired key is ignored and replaceable
+	err = db.CreateAppIdempotency(nil, &models.AppIdempotency{Namespace: ns, Key: "k2", Name: "app2", ExpireTime: time.Now().Add(-time.Hour).UTC()})
+	assert.NoError(t, err)
+	_, err = db.GetAppIdempotency(ns, "k2")
+	assert.Error(t, err)
+	err = db.CreateAppIdempotency(nil, &models.AppIdempotency{Namespace: ns, Key: "k2", Name: "app3", ExpireTime: expire})
+	assert.NoError(t, err)
+	res, err = db.GetAppIdempotency(ns, "k2")
+	assert.NoError(t, err)
+	assert.Equal(t, "app3", res.Name)
+
+	n, err := db.DeleteExpiredAppIdempotency(time.Now().UTC())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+	n, err = db.DeleteExpiredAppIdempotency(expire.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *DB) CreateAppOutbox(tx interface{}, event *models.AppEvent) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	outbox, err := entities.FromAppOutboxModel(event)
+	if err != nil {
+		return err
+	}
+	insertSQL := `INSERT INTO baetyl_app_outbox (namespace, name, content, create_time) VALUES (?,?,?,?)`
+	_, err = d.Exec(transaction, insertSQL, outbox.Namespace, outbox.Name, outbox.Content, outbox.CreateTime)
+	return err
+}
+
+func (d *DB) ListAppOutbox(limit int) ([]models.AppOutboxEvent, error) {
+	selectSQL := `
+SELECT id, namespace, name, content, create_time 
+FROM baetyl_app_outbox ORDER BY id LIMIT ?`
+	var outboxes []entities.AppOutbox
+	if err := d.Query(nil, selectSQL, &outboxes, limit); err != nil {
+		return nil, err
+	}
+	res := make([]models.AppOutboxEvent, 0, len(outboxes))
+	for i := range outboxes {
+		event, err := entities.ToAppOutboxModel(&outboxes[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *event)
+	}
+	return res, nil
+}
+
+func (d *DB) DeleteAppOutbox(id uint64) error {
+	deleteSQL := `DELETE FROM baetyl_app_outbox WHERE id=?`
+	_, err := d.Exec(nil, deleteSQL, id)
+	return err
+}
+
+func (d *DB) CreateCronOutbox(tx interface{}, op *models.CronOutboxOp) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	outbox, err := entities.FromCronOutboxModel(op)
+	if err != nil {
+		return err
+	}
+	insertSQL := `INSERT INTO baetyl_cron_outbox (namespace, name, op, content, create_time) VALUES (?,?,?,?,?)`
+	_, err = d.Exec(transaction, insertSQL, outbox.Namespace, outbox.Name, outbox.Op, outbox.Content, outbox.CreateTime)
+	return err
+}
+
+func (d *DB) ListCronOutbox(limit int) ([]models.CronOutboxOp, error) {
+	selectSQL := `
+SELECT id, namespace, name, op, content, create_time 
+FROM baetyl_cron_outbox ORDER BY id LIMIT ?`
+	var outboxes []entities.CronOutbox
+	if err := d.Query(nil, selectSQL, &outboxes, limit); err != nil {
+		return nil, err
+	}
+	res := make([]models.CronOutboxOp, 0, len(outboxes))
+	for i := range outboxes {
+		op, err := entities.ToCronOutboxModel(&outboxes[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *op)
+	}
+	return res, nil
+}
+
+func (d *DB) DeleteCronOutbox(id uint64) error {
+	deleteSQL := `DELETE FROM baetyl_cron_outbox WHERE id=?`
+	_, err := d.Exec(nil, deleteSQL, id)
+	return err
+}
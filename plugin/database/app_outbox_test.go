@@ -0,0 +1,134 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appOutboxTables = []string{
+		`
+CREATE TABLE baetyl_app_outbox(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace       VARCHAR(64) NOT NULL DEFAULT '',
+    name            VARCHAR(128) NOT NULL DEFAULT '',
+    content         TEXT NOT NULL,
+    create_time     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		`
+CREATE TABLE baetyl_cron_outbox(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace       VARCHAR(64) NOT NULL DEFAULT '',
+    name            VARCHAR(128) NOT NULL DEFAULT '',
+    op              VARCHAR(32) NOT NULL DEFAULT '',
+    content         TEXT NOT NULL,
+    create_time     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func TestAppOutbox(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(appOutboxTables)
+
+	ts := time.Now().UTC().Truncate(time.Second)
+	e1 := &models.AppEvent{Namespace: "cloud", Name: "app1", Version: "1", Action: models.AppCreated, Nodes: []string{"n1"}, Timestamp: ts}
+	e2 := &models.AppEvent{Namespace: "cloud", Name: "app1", Version: "2", Action: models.AppUpdated, Nodes: []string{"n1", "n2"}, Timestamp: ts}
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppOutbox(tx, e1)
+	assert.NoError(t, err)
+	db.Rollback(tx)
+	res, err := db.ListAppOutbox(10)
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	tx, err = db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppOutbox(tx, e1)
+	assert.NoError(t, err)
+	db.Commit(tx)
+	err = db.CreateAppOutbox(nil, e2)
+	assert.NoError(t, err)
+
+	// listed in the order written
+	res, err = db.ListAppOutbox(10)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.True(t, res[0].Id < res[1].Id)
+	assert.Equal(t, *e1, res[0].Event)
+	assert.Equal(t, *e2, res[1].Event)
+
+	res, err = db.ListAppOutbox(1)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, *e1, res[0].Event)
+
+	err = db.DeleteAppOutbox(res[0].Id)
+	assert.NoError(t, err)
+	res, err = db.ListAppOutbox(10)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, *e2, res[0].Event)
+}
+
+func TestCronOutbox(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(appOutboxTables)
+
+	ts := time.Now().UTC().Truncate(time.Second)
+	put := &models.CronOutboxOp{Namespace: "cloud", Name: "app1", Op: models.CronOutboxPut,
+		Cron: &models.Cron{Namespace: "cloud", Name: "app1", Selector: "a=b", CronTime: ts, Timezone: "Asia/Shanghai"}}
+	del := &models.CronOutboxOp{Namespace: "cloud", Name: "app1", Op: models.CronOutboxDelete}
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateCronOutbox(tx, put)
+	assert.NoError(t, err)
+	db.Rollback(tx)
+	res, err := db.ListCronOutbox(10)
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	tx, err = db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateCronOutbox(tx, put)
+	assert.NoError(t, err)
+	db.Commit(tx)
+	err = db.CreateCronOutbox(nil, del)
+	assert.NoError(t, err)
+
+	// listed in the order written
+	res, err = db.ListCronOutbox(10)
+	assert.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.True(t, res[0].Id < res[1].Id)
+	put.Id, del.Id = res[0].Id, res[1].Id
+	assert.Equal(t, *put, res[0])
+	assert.Equal(t, *del, res[1])
+
+	err = db.DeleteCronOutbox(res[0].Id)
+	assert.NoError(t, err)
+	res, err = db.ListCronOutbox(10)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, *del, res[0])
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *DB) CreateAppRecycle(tx interface{}, recycle *models.AppRecycle) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	entity, err := entities.FromAppRecycleModel(recycle)
+	if err != nil {
+		return err
+	}
+	insertSQL := `
+INSERT INTO baetyl_app_recycle (namespace, name, version, content, delete_time) 
+VALUES (?,?,?,?,?)`
+	_, err = d.Exec(transaction, insertSQL, entity.Namespace, entity.Name, entity.Version, entity.Content, entity.DeleteTime)
+	return err
+}
+
+func (d *DB) GetAppRecycle(namespace, name string) (*models.AppRecycle, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, content, delete_time 
+FROM baetyl_app_recycle WHERE namespace=? AND name=?`
+	var recycles []entities.AppRecycle
+	if err := d.Query(nil, selectSQL, &recycles, namespace, name); err != nil {
+		return nil, err
+	}
+	if len(recycles) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "appRecycle"), common.Field("name", name))
+	}
+	return entities.ToAppRecycleModel(&recycles[0])
+}
+
+func (d *DB) DeleteAppRecycle(tx interface{}, namespace, name string) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	deleteSQL := `DELETE FROM baetyl_app_recycle WHERE namespace=? AND name=?`
+	_, err := d.Exec(transaction, deleteSQL, namespace, name)
+	return err
+}
+
+func (d *DB) ListAppRecycle(namespace string) ([]models.AppRecycle, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, content, delete_time 
+FROM baetyl_app_recycle WHERE namespace=? ORDER BY id`
+	var recycles []entities.AppRecycle
+	if err := d.Query(nil, selectSQL, &recycles, namespace); err != nil {
+		return nil, err
+	}
+	return toAppRecycleModels(recycles)
+}
+
+func (d *DB) ListExpiredAppRecycle(before time.Time) ([]models.AppRecycle, error) {
+	selectSQL := `
+SELECT id, namespace, name, version, content, delete_time 
+FROM baetyl_app_recycle WHERE delete_time <= ? ORDER BY id`
+	var recycles []entities.AppRecycle
+	if err := d.Query(nil, selectSQL, &recycles, before); err != nil {
+		return nil, err
+	}
+	return toAppRecycleModels(recycles)
+}
+
+func toAppRecycleModels(recycles []entities.AppRecycle) ([]models.AppRecycle, error) {
+	res := make([]models.AppRecycle, 0, len(recycles))
+	for i := range recycles {
+		recycle, err := entities.ToAppRecycleModel(&recycles[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *recycle)
+	}
+	return res, nil
+}
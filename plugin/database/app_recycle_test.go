@@ -0,0 +1,100 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	appRecycleTables = []string{
+		`
+CREATE TABLE baetyl_app_recycle(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    name        VARCHAR(128) NOT NULL DEFAULT '',
+    version     VARCHAR(36) NOT NULL DEFAULT '',
+    content     TEXT NOT NULL,
+    delete_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (namespace, name)
+);
+`,
+	}
+)
+
+func TestAppRecycle(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(appRecycleTables)
+
+	ns := "cloud"
+	now := time.Now().UTC().Truncate(time.Second)
+	app := &specV1.Application{Namespace: ns, Name: "app1", Version: "1", Selector: "a=b"}
+	err = db.CreateAppRecycle(nil, &models.AppRecycle{
+		Namespace:  ns,
+		Name:       "app1",
+		Version:    "1",
+		App:        app,
+		DeleteTime: now.Add(-time.Hour),
+	})
+	assert.NoError(t, err)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppRecycle(tx, &models.AppRecycle{Namespace: ns, Name: "app2", App: app, DeleteTime: now})
+	assert.NoError(t, err)
+	db.Rollback(tx)
+
+	_, err = db.GetAppRecycle(ns, "app2")
+	assert.Error(t, err)
+	e, ok := err.(errors.Coder)
+	assert.True(t, ok)
+	assert.Equal(t, common.ErrResourceNotFound, e.Code())
+
+	tx, err = db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateAppRecycle(tx, &models.AppRecycle{Namespace: ns, Name: "app2", Version: "2", App: app, DeleteTime: now})
+	assert.NoError(t, err)
+	db.Commit(tx)
+
+	res, err := db.GetAppRecycle(ns, "app1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", res.Version)
+	assert.Equal(t, now.Add(-time.Hour), res.DeleteTime)
+	assert.Equal(t, app, res.App)
+
+	expired, err := db.ListExpiredAppRecycle(now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+	assert.Equal(t, "app1", expired[0].Name)
+
+	expired, err = db.ListExpiredAppRecycle(now)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 2)
+
+	listed, err := db.ListAppRecycle(ns)
+	assert.NoError(t, err)
+	assert.Len(t, listed, 2)
+	assert.Equal(t, app, listed[0].App)
+	listed, err = db.ListAppRecycle("other")
+	assert.NoError(t, err)
+	assert.Empty(t, listed)
+
+	err = db.DeleteAppRecycle(nil, ns, "app1")
+	assert.NoError(t, err)
+	expired, err = db.ListExpiredAppRecycle(now)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+	assert.Equal(t, "app2", expired[0].Name)
+}
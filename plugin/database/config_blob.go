@@ -0,0 +1,99 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *DB) CreateConfigBlob(tx interface{}, blob *models.ConfigBlob) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	selectSQL := `SELECT id FROM baetyl_config_blob WHERE namespace=? AND hash=?`
+	var blobs []entities.ConfigBlob
+	if err := d.Query(transaction, selectSQL, &blobs, blob.Namespace, blob.Hash); err != nil {
+		return err
+	}
+	// the blob is stored already
+	if len(blobs) > 0 {
+		return nil
+	}
+	createTime := blob.CreateTime
+	if createTime.IsZero() {
+		createTime = time.Now()
+	}
+	insertSQL := `
+INSERT INTO baetyl_config_blob (namespace, hash, data, create_time) 
+VALUES (?,?,?,?)`
+	_, err := d.Exec(transaction, insertSQL, blob.Namespace, blob.Hash, blob.Data, createTime.UTC())
+	return err
+}
+
+func (d *DB) GetConfigBlob(tx interface{}, namespace, hash string) (*models.ConfigBlob, error) {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	selectSQL := `
+SELECT id, namespace, hash, data, create_time 
+FROM baetyl_config_blob WHERE namespace=? AND hash=?`
+	var blobs []entities.ConfigBlob
+	if err := d.Query(transaction, selectSQL, &blobs, namespace, hash); err != nil {
+		return nil, err
+	}
+	if len(blobs) == 0 {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "configBlob"), common.Field("name", hash))
+	}
+	return entities.ToConfigBlobModel(&blobs[0]), nil
+}
+
+func (d *DB) ReplaceConfigBlobRefs(tx interface{}, namespace, config string, hashes []string) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	selectSQL := `SELECT id, namespace, config, hash FROM baetyl_config_blob_ref WHERE namespace=? AND config=?`
+	var refs []entities.ConfigBlobRef
+	if err := d.Query(transaction, selectSQL, &refs, namespace, config); err != nil {
+		return err
+	}
+	kept := map[string]bool{}
+	for _, hash := range hashes {
+		kept[hash] = true
+	}
+	referenced := map[string]bool{}
+	for _, ref := range refs {
+		referenced[ref.Hash] = true
+		if kept[ref.Hash] {
+			continue
+		}
+		deleteSQL := `DELETE FROM baetyl_config_blob_ref WHERE namespace=? AND config=? AND hash=?`
+		if _, err := d.Exec(transaction, deleteSQL, namespace, config, ref.Hash); err != nil {
+			return err
+		}
+		// the blob is deleted with its last reference
+		deleteSQL = `
+DELETE FROM baetyl_config_blob WHERE namespace=? AND hash=? 
+AND NOT EXISTS (SELECT 1 FROM baetyl_config_blob_ref WHERE namespace=? AND hash=?)`
+		if _, err := d.Exec(transaction, deleteSQL, namespace, ref.Hash, namespace, ref.Hash); err != nil {
+			return err
+		}
+	}
+	for _, hash := range hashes {
+		if referenced[hash] {
+			continue
+		}
+		referenced[hash] = true
+		insertSQL := `INSERT INTO baetyl_config_blob_ref (namespace, config, hash) VALUES (?,?,?)`
+		if _, err := d.Exec(transaction, insertSQL, namespace, config, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
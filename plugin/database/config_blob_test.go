@@ -0,0 +1,91 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	configBlobTables = []string{
+		`
+CREATE TABLE baetyl_config_blob(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    hash        VARCHAR(64) NOT NULL DEFAULT '',
+    data        BLOB NOT NULL,
+    create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (namespace, hash)
+);
+`,
+		`
+CREATE TABLE baetyl_config_blob_ref(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    config      VARCHAR(128) NOT NULL DEFAULT '',
+    hash        VARCHAR(64) NOT NULL DEFAULT '',
+    UNIQUE (namespace, config, hash)
+);
+`,
+	}
+)
+
+func TestConfigBlob(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(configBlobTables)
+
+	ns := "cloud"
+	now := time.Now().UTC().Truncate(time.Second)
+	b1 := &models.ConfigBlob{Namespace: ns, Hash: models.ConfigBlobHash("v1"), Data: []byte("v1"), CreateTime: now}
+	b2 := &models.ConfigBlob{Namespace: ns, Hash: models.ConfigBlobHash("v2"), Data: []byte("v2"), CreateTime: now}
+
+	_, err = db.GetConfigBlob(nil, ns, b1.Hash)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	assert.NoError(t, db.CreateConfigBlob(tx, b1))
+	res, err := db.GetConfigBlob(tx, ns, b1.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, b1, res)
+	db.Rollback(tx)
+	_, err = db.GetConfigBlob(nil, ns, b1.Hash)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	// the blob stored already is kept
+	assert.NoError(t, db.CreateConfigBlob(nil, b1))
+	assert.NoError(t, db.CreateConfigBlob(nil, &models.ConfigBlob{Namespace: ns, Hash: b1.Hash, Data: []byte("v1"), CreateTime: now.Add(time.Hour)}))
+	res, err = db.GetConfigBlob(nil, ns, b1.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, b1, res)
+	assert.NoError(t, db.CreateConfigBlob(nil, b2))
+
+	// the blob is shared by two configs
+	assert.NoError(t, db.ReplaceConfigBlobRefs(nil, ns, "c1", []string{b1.Hash, b2.Hash}))
+	assert.NoError(t, db.ReplaceConfigBlobRefs(nil, ns, "c1", []string{b1.Hash, b2.Hash}))
+	assert.NoError(t, db.ReplaceConfigBlobRefs(nil, ns, "c2", []string{b1.Hash}))
+
+	// the blob dropped by c1 is deleted since it's referenced by no other config
+	assert.NoError(t, db.ReplaceConfigBlobRefs(nil, ns, "c1", []string{b1.Hash}))
+	_, err = db.GetConfigBlob(nil, ns, b2.Hash)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+
+	// the blob is kept until the last reference is dropped
+	assert.NoError(t, db.ReplaceConfigBlobRefs(nil, ns, "c1", nil))
+	_, err = db.GetConfigBlob(nil, ns, b1.Hash)
+	assert.NoError(t, err)
+	assert.NoError(t, db.ReplaceConfigBlobRefs(nil, ns, "c2", nil))
+	_, err = db.GetConfigBlob(nil, ns, b1.Hash)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+}
@@ -1,6 +1,9 @@
 package database
 
 import (
+	"strings"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -8,36 +11,135 @@ import (
 	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
 )
 
-func (d *DB) GetCron(name, namespace string) (*models.Cron, error) {
-	selectSQL := `SELECT name, namespace, selector, cron_time FROM baetyl_cron_app WHERE name=? AND namespace=?`
+func (d *DB) GetCron(tx interface{}, name, namespace string) (*models.Cron, error) {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	selectSQL := `SELECT name, namespace, selector, cron_time, cron_times, timezone, paused, misfire_policy, overlap_policy, no_nodes_policy, selector_resolver FROM baetyl_cron_app WHERE name=? AND namespace=?`
 	var cronApps []entities.CronApp
-	err := d.Query(nil, selectSQL, &cronApps, name, namespace)
+	err := d.Query(transaction, selectSQL, &cronApps, name, namespace)
 	if err != nil {
 		return nil, err
 	}
 	if len(cronApps) > 0 {
 		return &models.Cron{
-			Name:      cronApps[0].Name,
-			Namespace: cronApps[0].Namespace,
-			Selector:  cronApps[0].Selector,
-			CronTime:  cronApps[0].CronTime.UTC(),
+			Name:             cronApps[0].Name,
+			Namespace:        cronApps[0].Namespace,
+			Selector:         cronApps[0].Selector,
+			CronTime:         cronApps[0].CronTime.UTC(),
+			CronTimes:        parseCronTimes(cronApps[0].CronTimes),
+			Timezone:         cronApps[0].Timezone,
+			Paused:           cronApps[0].Paused,
+			MisfirePolicy:    cronApps[0].Misfire,
+			OverlapPolicy:    cronApps[0].Overlap,
+			NoNodesPolicy:    cronApps[0].NoNodes,
+			SelectorResolver: cronApps[0].Resolver,
 		}, nil
 	}
 	return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "cronApp"), common.Field("name", name))
 }
 
+func (d *DB) ListCrons(tx interface{}, namespace string, names []string) ([]models.Cron, error) {
+	if len(names) == 0 {
+		return []models.Cron{}, nil
+	}
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	selectSQL := `SELECT name, namespace, selector, cron_time, cron_times, timezone, paused, misfire_policy, overlap_policy, no_nodes_policy, selector_resolver FROM baetyl_cron_app WHERE namespace=? AND name IN (?)`
+	qSQL, args, err := sqlx.In(selectSQL, namespace, names)
+	if err != nil {
+		return nil, err
+	}
+	var cronApps []entities.CronApp
+	if err = d.Query(transaction, qSQL, &cronApps, args...); err != nil {
+		return nil, err
+	}
+	res := make([]models.Cron, 0, len(cronApps))
+	for _, cronApp := range cronApps {
+		res = append(res, models.Cron{
+			Name:             cronApp.Name,
+			Namespace:        cronApp.Namespace,
+			Selector:         cronApp.Selector,
+			CronTime:         cronApp.CronTime.UTC(),
+			CronTimes:        parseCronTimes(cronApp.CronTimes),
+			Timezone:         cronApp.Timezone,
+			Paused:           cronApp.Paused,
+			MisfirePolicy:    cronApp.Misfire,
+			OverlapPolicy:    cronApp.Overlap,
+			NoNodesPolicy:    cronApp.NoNodes,
+			SelectorResolver: cronApp.Resolver,
+		})
+	}
+	return res, nil
+}
+
+func (d *DB) ListCronsPage(namespace string, filter *models.Filter) ([]models.Cron, int, error) {
+	countSQL := `SELECT count(id) AS count FROM baetyl_cron_app WHERE namespace=?`
+	var counts []struct {
+		Count int `db:"count"`
+	}
+	if err := d.Query(nil, countSQL, &counts, namespace); err != nil {
+		return nil, 0, err
+	}
+	selectSQL := `
+SELECT id, name, namespace, selector, cron_time, cron_times, timezone, paused, misfire_policy, overlap_policy, no_nodes_policy, selector_resolver 
+FROM baetyl_cron_app WHERE namespace=? ORDER BY id `
+	args := []interface{}{namespace}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ?,?"
+		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+	}
+	var cronApps []entities.CronApp
+	if err := d.Query(nil, selectSQL, &cronApps, args...); err != nil {
+		return nil, 0, err
+	}
+	res := make([]models.Cron, 0, len(cronApps))
+	for _, cronApp := range cronApps {
+		res = append(res, models.Cron{
+			Id:               cronApp.Id,
+			Name:             cronApp.Name,
+			Namespace:        cronApp.Namespace,
+			Selector:         cronApp.Selector,
+			CronTime:         cronApp.CronTime.UTC(),
+			CronTimes:        parseCronTimes(cronApp.CronTimes),
+			Timezone:         cronApp.Timezone,
+			Paused:           cronApp.Paused,
+			MisfirePolicy:    cronApp.Misfire,
+			OverlapPolicy:    cronApp.Overlap,
+			NoNodesPolicy:    cronApp.NoNodes,
+			SelectorResolver: cronApp.Resolver,
+		})
+	}
+	return res, counts[0].Count, nil
+}
+
 func (d *DB) CreateCron(cronApp *models.Cron) error {
-	insertSQL := `INSERT INTO baetyl_cron_app (name, namespace, selector, cron_time) VALUES (?,?,?,?)`
-	_, err := d.Exec(nil, insertSQL, cronApp.Name, cronApp.Namespace, cronApp.Selector, cronApp.CronTime)
+	insertSQL := `INSERT INTO baetyl_cron_app (name, namespace, selector, cron_time, cron_times, timezone, misfire_policy, overlap_policy, no_nodes_policy, selector_resolver) VALUES (?,?,?,?,?,?,?,?,?,?)`
+	_, err := d.Exec(nil, insertSQL, cronApp.Name, cronApp.Namespace, cronApp.Selector, cronApp.CronTime, formatCronTimes(cronApp.CronTimes), cronApp.Timezone, cronApp.MisfirePolicy, cronApp.OverlapPolicy, cronApp.NoNodesPolicy, cronApp.SelectorResolver)
 	return err
 }
 
 func (d *DB) UpdateCron(cronApp *models.Cron) error {
-	updateSQL := `UPDATE baetyl_cron_app SET selector=?, cron_time=? WHERE name=? AND namespace=?`
-	_, err := d.Exec(nil, updateSQL, cronApp.Selector, cronApp.CronTime, cronApp.Name, cronApp.Namespace)
+	updateSQL := `UPDATE baetyl_cron_app SET selector=?, cron_time=?, cron_times=?, timezone=?, misfire_policy=?, overlap_policy=?, no_nodes_policy=?, selector_resolver=? WHERE name=? AND namespace=?`
+	_, err := d.Exec(nil, updateSQL, cronApp.Selector, cronApp.CronTime, formatCronTimes(cronApp.CronTimes), cronApp.Timezone, cronApp.MisfirePolicy, cronApp.OverlapPolicy, cronApp.NoNodesPolicy, cronApp.SelectorResolver, cronApp.Name, cronApp.Namespace)
 	return err
 }
 
+func (d *DB) SetCronPaused(name, namespace string, paused bool) error {
+	updateSQL := `UPDATE baetyl_cron_app SET paused=? WHERE name=? AND namespace=?`
+	res, err := d.Exec(nil, updateSQL, paused, name, namespace)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return common.Error(common.ErrResourceNotFound, common.Field("type", "cronApp"), common.Field("name", name))
+	}
+	return nil
+}
+
 func (d *DB) DeleteCron(name, namespace string) error {
 	deleteSQL := `DELETE FROM baetyl_cron_app WHERE name=? AND namespace=?`
 	_, err := d.Exec(nil, deleteSQL, name, namespace)
@@ -47,8 +149,8 @@ func (d *DB) DeleteCron(name, namespace string) error {
 func (d *DB) ListExpiredApps() ([]models.Cron, error) {
 	var applications []entities.CronApp
 	selectSQL := `
-SELECT id, name, namespace, selector, cron_time 
-FROM baetyl_cron_app WHERE cron_time <= now()
+SELECT id, name, namespace, selector, cron_time, cron_times, timezone, misfire_policy, overlap_policy, no_nodes_policy, selector_resolver 
+FROM baetyl_cron_app WHERE cron_time <= now() AND paused = 0
 	`
 	if err := d.Query(nil, selectSQL, &applications); err != nil {
 		return nil, err
@@ -56,24 +158,95 @@ FROM baetyl_cron_app WHERE cron_time <= now()
 	apps := make([]models.Cron, 0)
 	for _, application := range applications {
 		apps = append(apps, models.Cron{
-			Id:        application.Id,
-			Name:      application.Name,
-			Namespace: application.Namespace,
-			Selector:  application.Selector,
-			CronTime:  application.CronTime.UTC(),
+			Id:               application.Id,
+			Name:             application.Name,
+			Namespace:        application.Namespace,
+			Selector:         application.Selector,
+			CronTime:         application.CronTime.UTC(),
+			CronTimes:        parseCronTimes(application.CronTimes),
+			Timezone:         application.Timezone,
+			MisfirePolicy:    application.Misfire,
+			OverlapPolicy:    application.Overlap,
+			NoNodesPolicy:    application.NoNodes,
+			SelectorResolver: application.Resolver,
 		})
 	}
 	return apps, nil
 }
 
 func (d *DB) DeleteExpiredApps(cronApps []uint64) error {
-	deleteSql := `DELETE FROM baetyl_cron_app WHERE id IN (?)`
-	dSql, args, err := sqlx.In(deleteSql, cronApps)
-	if err != nil {
-		return err
+	return d.advanceExpiredCrons(cronApps, time.Now())
+}
+
+// advanceExpiredCrons advances the crons to the earliest of their times after now, the times passed are dropped
+// so that they're never fired again, and deletes the crons with no time left in a transaction
+func (d *DB) advanceExpiredCrons(cronApps []uint64, now time.Time) error {
+	if len(cronApps) == 0 {
+		return nil
 	}
-	if _, err = d.Exec(nil, dSql, args...); err != nil {
+	return d.Transact(func(tx *sqlx.Tx) error {
+		selectSQL, args, err := sqlx.In(`SELECT id, cron_times FROM baetyl_cron_app WHERE id IN (?)`, cronApps)
+		if err != nil {
+			return err
+		}
+		var crons []entities.CronApp
+		if err = d.Query(tx, selectSQL, &crons, args...); err != nil {
+			return err
+		}
+		var deleted []uint64
+		for _, c := range crons {
+			var pending []time.Time
+			for _, t := range parseCronTimes(c.CronTimes) {
+				if t.After(now) {
+					pending = append(pending, t)
+				}
+			}
+			if len(pending) == 0 {
+				deleted = append(deleted, c.Id)
+				continue
+			}
+			updateSQL := `UPDATE baetyl_cron_app SET cron_time=?, cron_times=? WHERE id=?`
+			if _, err = d.Exec(tx, updateSQL, pending[0], formatCronTimes(pending), c.Id); err != nil {
+				return err
+			}
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		deleteSQL, args, err := sqlx.In(`DELETE FROM baetyl_cron_app WHERE id IN (?)`, deleted)
+		if err != nil {
+			return err
+		}
+		_, err = d.Exec(tx, deleteSQL, args...)
 		return err
+	})
+}
+
+// formatCronTimes joins the times of the cron scheduled by more than one, the cron_time column is enough
+// for the cron scheduled by a single time, and it's the earliest one which the expired crons are listed by.
+// The times are formatted in RFC3339 and so truncated to seconds, like the cron time stored in the datetime column
+func formatCronTimes(times []time.Time) string {
+	if len(times) <= 1 {
+		return ""
 	}
-	return nil
+	res := make([]string, 0, len(times))
+	for _, t := range times {
+		res = append(res, t.UTC().Format(time.RFC3339))
+	}
+	return strings.Join(res, ",")
+}
+
+func parseCronTimes(s string) []time.Time {
+	if s == "" {
+		return nil
+	}
+	var res []time.Time
+	for _, v := range strings.Split(s, ",") {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			continue
+		}
+		res = append(res, t.UTC())
+	}
+	return res
 }
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"github.com/jmoiron/sqlx"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin/database/entities"
+)
+
+func (d *DB) CreateCronRun(tx interface{}, run *models.CronRun) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	insertSQL := `
+INSERT INTO baetyl_cron_run (namespace, name, version, run_trigger, nodes, success, message, run_time) 
+VALUES (?,?,?,?,?,?,?,?)`
+	_, err := d.Exec(transaction, insertSQL, run.Namespace, run.Name, run.Version, run.Trigger,
+		run.Nodes, run.Success, run.Message, run.RunTime)
+	return err
+}
+
+func (d *DB) ListCronRuns(namespace, name string, filter *models.Filter) ([]models.CronRun, int, error) {
+	countSQL := `SELECT count(id) AS count FROM baetyl_cron_run WHERE namespace=? AND name=?`
+	var counts []struct {
+		Count int `db:"count"`
+	}
+	if err := d.Query(nil, countSQL, &counts, namespace, name); err != nil {
+		return nil, 0, err
+	}
+	selectSQL := `
+SELECT id, namespace, name, version, run_trigger, nodes, success, message, run_time 
+FROM baetyl_cron_run WHERE namespace=? AND name=? ORDER BY id DESC `
+	args := []interface{}{namespace, name}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ?,?"
+		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+	}
+	var runs []entities.CronRun
+	if err := d.Query(nil, selectSQL, &runs, args...); err != nil {
+		return nil, 0, err
+	}
+	res := make([]models.CronRun, 0, len(runs))
+	for i := range runs {
+		res = append(res, *entities.ToCronRunModel(&runs[i]))
+	}
+	return res, counts[0].Count, nil
+}
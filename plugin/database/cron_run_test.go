@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+var (
+	cronRunTables = []string{
+		`
+CREATE TABLE baetyl_cron_run(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    namespace   VARCHAR(64) NOT NULL DEFAULT '',
+    name        VARCHAR(128) NOT NULL DEFAULT '',
+    version     VARCHAR(36) NOT NULL DEFAULT '',
+    run_trigger VARCHAR(16) NOT NULL DEFAULT '',
+    nodes       INTEGER NOT NULL DEFAULT 0,
+    success     TINYINT(1) NOT NULL DEFAULT 0,
+    message     VARCHAR(1024) NOT NULL DEFAULT '',
+    run_time    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`,
+	}
+)
+
+func TestCronRun(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateTables(cronRunTables)
+
+	ns, name := "cloud", "baetyl"
+	now := time.Now().UTC().Truncate(time.Second)
+	err = db.CreateCronRun(nil, &models.CronRun{
+		Namespace: ns,
+		Name:      name,
+		Version:   "1",
+		Trigger:   models.CronRunScheduled,
+		Nodes:     3,
+		Success:   true,
+		RunTime:   now,
+	})
+	assert.NoError(t, err)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	err = db.CreateCronRun(tx, &models.CronRun{Namespace: ns, Name: name, Trigger: models.CronRunManual, RunTime: now})
+	assert.NoError(t, err)
+	db.Rollback(tx)
+
+	err = db.CreateCronRun(nil, &models.CronRun{
+		Namespace: ns,
+		Name:      name,
+		Version:   "2",
+		Trigger:   models.CronRunManual,
+		Message:   "failed",
+		RunTime:   now.Add(time.Minute),
+	})
+	assert.NoError(t, err)
+
+	res, total, err := db.ListCronRuns(ns, name, &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, res, 2)
+	assert.Equal(t, models.CronRunManual, res[0].Trigger)
+	assert.False(t, res[0].Success)
+	assert.Equal(t, "failed", res[0].Message)
+	assert.Equal(t, now.Add(time.Minute), res[0].RunTime)
+	assert.Equal(t, models.CronRunScheduled, res[1].Trigger)
+	assert.True(t, res[1].Success)
+	assert.Equal(t, 3, res[1].Nodes)
+	assert.Equal(t, "1", res[1].Version)
+
+	// paged from the latest
+	res, total, err = db.ListCronRuns(ns, name, &models.Filter{PageNo: 2, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "1", res[0].Version)
+
+	res, total, err = db.ListCronRuns(ns, "none", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Len(t, res, 0)
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -20,6 +21,13 @@ CREATE TABLE baetyl_cron_app(
     namespace   VARCHAR(64) NOT NULL DEFAULT '',
 	selector    VARCHAR(2048) NOT NULL DEFAULT '',
 	cron_time   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	cron_times  VARCHAR(2048) NOT NULL DEFAULT '',
+	timezone    VARCHAR(64) NOT NULL DEFAULT '',
+	paused      TINYINT(1) NOT NULL DEFAULT 0,
+	misfire_policy VARCHAR(16) NOT NULL DEFAULT '',
+	overlap_policy VARCHAR(16) NOT NULL DEFAULT '',
+	no_nodes_policy VARCHAR(16) NOT NULL DEFAULT '',
+	selector_resolver VARCHAR(64) NOT NULL DEFAULT '',
     create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     update_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -56,13 +64,99 @@ func TestCronApp(t *testing.T) {
 	err = db.CreateCron(cronApp)
 	assert.NoError(t, err)
 
-	_, err = db.GetCron(name, ns)
+	_, err = db.GetCron(nil, name, ns)
 	assert.NoError(t, err)
 
 	cronApp.Selector = "baetyl-node-name=node2"
+	cronApp.Timezone = "Asia/Shanghai"
 	err = db.UpdateCron(cronApp)
 	assert.NoError(t, err)
 
+	res, err := db.GetCron(nil, name, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Shanghai", res.Timezone)
+	assert.False(t, res.Paused)
+	assert.Equal(t, []time.Time{res.CronTime}, res.Schedules())
+
+	// the cron scheduled by more than one time
+	weekday, weekend := time.Date(2099, 10, 2, 8, 0, 0, 0, time.UTC), time.Date(2099, 10, 3, 10, 0, 0, 0, time.UTC)
+	cronApp.CronTime = weekday
+	cronApp.CronTimes = []time.Time{weekday, weekend}
+	err = db.UpdateCron(cronApp)
+	assert.NoError(t, err)
+	res, err = db.GetCron(nil, name, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{weekday, weekend}, res.Schedules())
+	assert.Equal(t, "", res.MisfirePolicy)
+
+	cronApp.MisfirePolicy = models.MisfireFireOnce
+	err = db.UpdateCron(cronApp)
+	assert.NoError(t, err)
+	res, err = db.GetCron(nil, name, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, models.MisfireFireOnce, res.MisfirePolicy)
+	assert.Equal(t, "", res.OverlapPolicy)
+
+	cronApp.OverlapPolicy = models.OverlapQueue
+	err = db.UpdateCron(cronApp)
+	assert.NoError(t, err)
+	res, err = db.GetCron(nil, name, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, models.OverlapQueue, res.OverlapPolicy)
+	assert.Equal(t, "", res.NoNodesPolicy)
+
+	cronApp.NoNodesPolicy = models.NoNodesSkip
+	err = db.UpdateCron(cronApp)
+	assert.NoError(t, err)
+	res, err = db.GetCron(nil, name, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, models.NoNodesSkip, res.NoNodesPolicy)
+	assert.Equal(t, "", res.SelectorResolver)
+
+	cronApp.SelectorResolver = "app"
+	err = db.UpdateCron(cronApp)
+	assert.NoError(t, err)
+	res, err = db.GetCron(nil, name, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, "app", res.SelectorResolver)
+
+	err = db.SetCronPaused(name, ns, true)
+	assert.NoError(t, err)
+	res, err = db.GetCron(nil, name, ns)
+	assert.NoError(t, err)
+	assert.True(t, res.Paused)
+
+	// the paused cron is kept after updated
+	err = db.UpdateCron(cronApp)
+	assert.NoError(t, err)
+	res, err = db.GetCron(nil, name, ns)
+	assert.NoError(t, err)
+	assert.True(t, res.Paused)
+
+	err = db.SetCronPaused(name, ns, false)
+	assert.NoError(t, err)
+
+	// the crons not found are omitted
+	crons, err := db.ListCrons(nil, ns, []string{name, "none"})
+	assert.NoError(t, err)
+	assert.Len(t, crons, 1)
+	assert.Equal(t, "baetyl-node-name=node2", crons[0].Selector)
+	assert.Equal(t, []time.Time{weekday, weekend}, crons[0].Schedules())
+	crons, err = db.ListCrons(nil, ns, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, crons)
+
+	// read within the read-only transaction
+	tx, err := db.BeginReadOnlyTx(context.Background())
+	assert.NoError(t, err)
+	res, err = db.GetCron(tx, name, ns)
+	assert.NoError(t, err)
+	assert.Equal(t, "baetyl-node-name=node2", res.Selector)
+	db.Commit(tx)
+
+	err = db.SetCronPaused("none", ns, true)
+	assert.Error(t, err)
+
 	_, err = db.ListExpiredApps()
 	assert.NotEqual(t, err, nil)
 
@@ -72,6 +166,88 @@ func TestCronApp(t *testing.T) {
 	err = db.DeleteCron(name, ns)
 	assert.NoError(t, err)
 
-	_, err = db.GetCron(name, ns)
+	_, err = db.GetCron(nil, name, ns)
 	assert.Error(t, err, common.ErrResourceNotFound)
 }
+
+func TestAdvanceExpiredCrons(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateCronAppTable()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	first, second, third := now.Add(-time.Minute), now.Add(time.Hour), now.Add(2*time.Hour)
+	assert.NoError(t, db.CreateCron(&models.Cron{Name: "multi", Namespace: "cloud", CronTime: first, CronTimes: []time.Time{first, second, third}}))
+	assert.NoError(t, db.CreateCron(&models.Cron{Name: "single", Namespace: "cloud", CronTime: first}))
+	crons, _, err := db.ListCronsPage("cloud", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, crons, 2)
+	ids := []uint64{crons[0].Id, crons[1].Id}
+
+	// the first firing advances the cron scheduled by more than one time, and deletes the single one
+	assert.NoError(t, db.advanceExpiredCrons(ids, now))
+	res, err := db.GetCron(nil, "multi", "cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, second, res.CronTime)
+	assert.Equal(t, []time.Time{second, third}, res.Schedules())
+	_, err = db.GetCron(nil, "single", "cloud")
+	assert.Error(t, err)
+
+	// the second firing advances it to the last time
+	assert.NoError(t, db.advanceExpiredCrons(ids[:1], second))
+	res, err = db.GetCron(nil, "multi", "cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, third, res.CronTime)
+	assert.Equal(t, []time.Time{third}, res.Schedules())
+
+	// the last firing deletes it
+	assert.NoError(t, db.advanceExpiredCrons(ids[:1], third))
+	_, err = db.GetCron(nil, "multi", "cloud")
+	assert.Error(t, err)
+
+	assert.NoError(t, db.DeleteExpiredApps(nil))
+}
+
+func TestListCronsPage(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateCronAppTable()
+
+	for _, name := range []string{"c1", "c2", "c3"} {
+		assert.NoError(t, db.CreateCron(&models.Cron{Name: name, Namespace: "cloud", Selector: "a=b", CronTime: time.Now()}))
+	}
+	assert.NoError(t, db.CreateCron(&models.Cron{Name: "c4", Namespace: "other", CronTime: time.Now()}))
+
+	res, total, err := db.ListCronsPage("cloud", &models.Filter{PageNo: 1, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "c1", res[0].Name)
+	assert.Equal(t, "a=b", res[0].Selector)
+	assert.NotZero(t, res[0].Id)
+	assert.Equal(t, "c2", res[1].Name)
+
+	res, total, err = db.ListCronsPage("cloud", &models.Filter{PageNo: 2, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "c3", res[0].Name)
+
+	res, total, err = db.ListCronsPage("cloud", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, res, 3)
+
+	res, total, err = db.ListCronsPage("empty", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, res)
+}
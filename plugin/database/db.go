@@ -1,8 +1,10 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"io"
+	"regexp"
 	"strings"
 	"time"
 
@@ -19,8 +21,12 @@ type DBStorage interface {
 	Exec(tx *sqlx.Tx, sql string, args ...interface{}) (sql.Result, error)
 	Query(tx *sqlx.Tx, sql string, data interface{}, args ...interface{}) error
 	BeginTx() (*sqlx.Tx, error)
+	BeginReadOnlyTx(ctx context.Context) (*sqlx.Tx, error)
+	BeginTxWithIsolation(ctx context.Context, level sql.IsolationLevel) (*sqlx.Tx, error)
 	Commit(tx *sqlx.Tx)
 	Rollback(tx *sqlx.Tx)
+	Savepoint(tx *sqlx.Tx, name string) error
+	RollbackToSavepoint(tx *sqlx.Tx, name string) error
 
 	io.Closer
 }
@@ -152,6 +158,18 @@ func (d *DB) BeginTx() (*sqlx.Tx, error) {
 	return d.db.Beginx()
 }
 
+// BeginReadOnlyTx begins a read-only transaction in repeatable read,
+// the reads within it see the snapshot established by the first read and take no write lock
+func (d *DB) BeginReadOnlyTx(ctx context.Context) (*sqlx.Tx, error) {
+	return d.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+// BeginTxWithIsolation begins a transaction in the isolation level, the default level of the database is used
+// if it's sql.LevelDefault. An error is returned if the level isn't supported by the database
+func (d *DB) BeginTxWithIsolation(ctx context.Context, level sql.IsolationLevel) (*sqlx.Tx, error) {
+	return d.db.BeginTxx(ctx, &sql.TxOptions{Isolation: level})
+}
+
 func (d *DB) Commit(tx *sqlx.Tx) {
 	if tx == nil {
 		return
@@ -171,3 +189,29 @@ func (d *DB) Rollback(tx *sqlx.Tx) {
 		log.Error(err)
 	}
 }
+
+var savepointName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Savepoint sets the savepoint within the transaction, the name must be a plain SQL identifier
+func (d *DB) Savepoint(tx *sqlx.Tx, name string) error {
+	if tx == nil {
+		return errors.New("the savepoint must be set within a transaction")
+	}
+	if !savepointName.MatchString(name) {
+		return errors.Errorf("invalid savepoint name (%s)", name)
+	}
+	_, err := d.Exec(tx, "SAVEPOINT "+name)
+	return err
+}
+
+// RollbackToSavepoint rolls back the transaction to the savepoint, which is kept to be rolled back to again
+func (d *DB) RollbackToSavepoint(tx *sqlx.Tx, name string) error {
+	if tx == nil {
+		return errors.New("the savepoint must be set within a transaction")
+	}
+	if !savepointName.MatchString(name) {
+		return errors.Errorf("invalid savepoint name (%s)", name)
+	}
+	_, err := d.Exec(tx, "ROLLBACK TO SAVEPOINT "+name)
+	return err
+}
@@ -1,8 +1,12 @@
 package database
 
 import (
+	"fmt"
+	"testing"
+
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
 )
 
 func MockNewDB() (*DB, error) {
@@ -19,3 +23,45 @@ func MockNewDB() (*DB, error) {
 	}
 	return &DB{db: db, cfg: cfg}, nil
 }
+
+// MockCreateTables creates the tables by their DDLs, it panics on failure
+func (d *DB) MockCreateTables(tables []string) {
+	for _, sql := range tables {
+		_, err := d.Exec(nil, sql)
+		if err != nil {
+			panic(fmt.Sprintf("create table exception: %s", err.Error()))
+		}
+	}
+}
+
+func TestSavepoint(t *testing.T) {
+	db, err := MockNewDB()
+	assert.NoError(t, err)
+	_, err = db.Exec(nil, "CREATE TABLE baetyl_savepoint(name VARCHAR(64) NOT NULL)")
+	assert.NoError(t, err)
+
+	tx, err := db.BeginTx()
+	assert.NoError(t, err)
+	_, err = db.Exec(tx, "INSERT INTO baetyl_savepoint (name) VALUES (?)", "kept")
+	assert.NoError(t, err)
+	assert.NoError(t, db.Savepoint(tx, "optional"))
+	_, err = db.Exec(tx, "INSERT INTO baetyl_savepoint (name) VALUES (?)", "discarded")
+	assert.NoError(t, err)
+	assert.NoError(t, db.RollbackToSavepoint(tx, "optional"))
+	// the transaction remains usable
+	_, err = db.Exec(tx, "INSERT INTO baetyl_savepoint (name) VALUES (?)", "written")
+	assert.NoError(t, err)
+	db.Commit(tx)
+
+	var names []string
+	err = db.Query(nil, "SELECT name FROM baetyl_savepoint ORDER BY name", &names)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kept", "written"}, names)
+
+	tx, err = db.BeginTx()
+	assert.NoError(t, err)
+	assert.Error(t, db.Savepoint(tx, "bad; DROP TABLE baetyl_savepoint"))
+	assert.Error(t, db.RollbackToSavepoint(tx, "unknown"))
+	db.Rollback(tx)
+	assert.Error(t, db.Savepoint(nil, "optional"))
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppActivation struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Version    string    `db:"version"`
+	ActivateAt time.Time `db:"activate_at"`
+}
+
+func ToAppActivationModel(activation *AppActivation) *models.AppActivation {
+	return &models.AppActivation{
+		Namespace:  activation.Namespace,
+		Name:       activation.Name,
+		Version:    activation.Version,
+		ActivateAt: activation.ActivateAt.UTC(),
+	}
+}
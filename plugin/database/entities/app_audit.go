@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppAudit struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Action     string    `db:"action"`
+	Actor      string    `db:"actor"`
+	OldVersion string    `db:"old_version"`
+	NewVersion string    `db:"new_version"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToAppAuditModel(audit *AppAudit) *models.AppAudit {
+	return &models.AppAudit{
+		Id:         audit.Id,
+		Namespace:  audit.Namespace,
+		Name:       audit.Name,
+		Action:     models.AppAction(audit.Action),
+		Actor:      audit.Actor,
+		OldVersion: audit.OldVersion,
+		NewVersion: audit.NewVersion,
+		Timestamp:  audit.CreateTime.UTC(),
+	}
+}
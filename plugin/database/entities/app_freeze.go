@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppFreeze struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	FrozenBy   string    `db:"frozen_by"`
+	FreezeTime time.Time `db:"freeze_time"`
+}
+
+func ToAppFreezeModel(freeze *AppFreeze) *models.AppFreeze {
+	return &models.AppFreeze{
+		Namespace:  freeze.Namespace,
+		Name:       freeze.Name,
+		FrozenBy:   freeze.FrozenBy,
+		FreezeTime: freeze.FreezeTime.UTC(),
+	}
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppIdempotency struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Key        string    `db:"idempotency_key"`
+	Name       string    `db:"name"`
+	ExpireTime time.Time `db:"expire_time"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func ToAppIdempotencyModel(idempotency *AppIdempotency) *models.AppIdempotency {
+	return &models.AppIdempotency{
+		Namespace:  idempotency.Namespace,
+		Key:        idempotency.Key,
+		Name:       idempotency.Name,
+		ExpireTime: idempotency.ExpireTime.UTC(),
+	}
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppOutbox struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Content    string    `db:"content"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func FromAppOutboxModel(event *models.AppEvent) (*AppOutbox, error) {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AppOutbox{
+		Namespace:  event.Namespace,
+		Name:       event.Name,
+		Content:    string(content),
+		CreateTime: event.Timestamp,
+	}, nil
+}
+
+func ToAppOutboxModel(outbox *AppOutbox) (*models.AppOutboxEvent, error) {
+	res := &models.AppOutboxEvent{Id: outbox.Id}
+	if err := json.Unmarshal([]byte(outbox.Content), &res.Event); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
+
+type CronOutbox struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Op         string    `db:"op"`
+	Content    string    `db:"content"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+func FromCronOutboxModel(op *models.CronOutboxOp) (*CronOutbox, error) {
+	content, err := json.Marshal(op.Cron)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &CronOutbox{
+		Namespace:  op.Namespace,
+		Name:       op.Name,
+		Op:         op.Op,
+		Content:    string(content),
+		CreateTime: time.Now().UTC(),
+	}, nil
+}
+
+func ToCronOutboxModel(outbox *CronOutbox) (*models.CronOutboxOp, error) {
+	res := &models.CronOutboxOp{
+		Id:        outbox.Id,
+		Namespace: outbox.Namespace,
+		Name:      outbox.Name,
+		Op:        outbox.Op,
+	}
+	if err := json.Unmarshal([]byte(outbox.Content), &res.Cron); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return res, nil
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type AppRecycle struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Name       string    `db:"name"`
+	Version    string    `db:"version"`
+	Content    string    `db:"content"`
+	DeleteTime time.Time `db:"delete_time"`
+}
+
+func ToAppRecycleModel(recycle *AppRecycle) (*models.AppRecycle, error) {
+	app := new(specV1.Application)
+	if err := json.Unmarshal([]byte(recycle.Content), app); err != nil {
+		return nil, err
+	}
+	return &models.AppRecycle{
+		Namespace:  recycle.Namespace,
+		Name:       recycle.Name,
+		Version:    recycle.Version,
+		App:        app,
+		DeleteTime: recycle.DeleteTime.UTC(),
+	}, nil
+}
+
+func FromAppRecycleModel(recycle *models.AppRecycle) (*AppRecycle, error) {
+	content, err := json.Marshal(recycle.App)
+	if err != nil {
+		return nil, err
+	}
+	return &AppRecycle{
+		Namespace:  recycle.Namespace,
+		Name:       recycle.Name,
+		Version:    recycle.Version,
+		Content:    string(content),
+		DeleteTime: recycle.DeleteTime,
+	}, nil
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type ConfigBlob struct {
+	Id         uint64    `db:"id"`
+	Namespace  string    `db:"namespace"`
+	Hash       string    `db:"hash"`
+	Data       []byte    `db:"data"`
+	CreateTime time.Time `db:"create_time"`
+}
+
+type ConfigBlobRef struct {
+	Id        uint64 `db:"id"`
+	Namespace string `db:"namespace"`
+	Config    string `db:"config"`
+	Hash      string `db:"hash"`
+}
+
+func ToConfigBlobModel(blob *ConfigBlob) *models.ConfigBlob {
+	return &models.ConfigBlob{
+		Namespace:  blob.Namespace,
+		Hash:       blob.Hash,
+		Data:       blob.Data,
+		CreateTime: blob.CreateTime.UTC(),
+	}
+}
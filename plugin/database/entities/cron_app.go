@@ -8,6 +8,13 @@ type CronApp struct {
 	Name       string    `db:"name"`
 	Selector   string    `db:"selector"`
 	CronTime   time.Time `db:"cron_time"`
+	CronTimes  string    `db:"cron_times"`
+	Timezone   string    `db:"timezone"`
+	Paused     bool      `db:"paused"`
+	Misfire    string    `db:"misfire_policy"`
+	Overlap    string    `db:"overlap_policy"`
+	NoNodes    string    `db:"no_nodes_policy"`
+	Resolver   string    `db:"selector_resolver"`
 	CreateTime time.Time `db:"create_time"`
 	UpdateTime time.Time `db:"update_time"`
 }
@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+type CronRun struct {
+	Id        uint64    `db:"id"`
+	Namespace string    `db:"namespace"`
+	Name      string    `db:"name"`
+	Version   string    `db:"version"`
+	Trigger   string    `db:"run_trigger"`
+	Nodes     int       `db:"nodes"`
+	Success   bool      `db:"success"`
+	Message   string    `db:"message"`
+	RunTime   time.Time `db:"run_time"`
+}
+
+func ToCronRunModel(run *CronRun) *models.CronRun {
+	return &models.CronRun{
+		Id:        run.Id,
+		Namespace: run.Namespace,
+		Name:      run.Name,
+		Version:   run.Version,
+		Trigger:   run.Trigger,
+		Nodes:     run.Nodes,
+		Success:   run.Success,
+		Message:   run.Message,
+		RunTime:   run.RunTime.UTC(),
+	}
+}
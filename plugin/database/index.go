@@ -8,6 +8,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 var cache = sync.Map{}
@@ -20,6 +21,33 @@ func (d *DB) ListIndex(namespace string, keyA, byKeyB common.Resource, valueB st
 	return d.ListIndexTx(nil, namespace, keyA, byKeyB, valueB)
 }
 
+func (d *DB) ListIndexPage(namespace string, keyA, byKeyB common.Resource, valueB string, filter *models.Filter) ([]string, error) {
+	selectSQL := fmt.Sprintf(`SELECT %s FROM %s WHERE namespace = ? and %s = ? and %s LIKE ? ORDER BY %s `,
+		keyA, getTable(keyA, byKeyB), byKeyB, keyA, keyA)
+	args := []interface{}{namespace, valueB, filter.GetFuzzyName()}
+	if filter.GetLimitNumber() > 0 {
+		selectSQL = selectSQL + "LIMIT ?,?"
+		args = append(args, filter.GetLimitOffset(), filter.GetLimitNumber())
+	}
+	var res []string
+	if err := d.Query(nil, selectSQL, &res, args...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (d *DB) CountIndex(namespace string, keyA, byKeyB common.Resource, valueB, name string) (int, error) {
+	selectSQL := fmt.Sprintf(`SELECT count(%s) AS count FROM %s WHERE namespace = ? and %s = ? and %s LIKE ?`,
+		keyA, getTable(keyA, byKeyB), byKeyB, keyA)
+	var res []struct {
+		Count int `db:"count"`
+	}
+	if err := d.Query(nil, selectSQL, &res, namespace, valueB, name); err != nil {
+		return 0, err
+	}
+	return res[0].Count, nil
+}
+
 func (d *DB) DeleteIndex(namespace string, keyA, byKeyB common.Resource, valueB string) (sql.Result, error) {
 	return d.DeleteIndexTx(nil, namespace, keyA, byKeyB, valueB)
 }
@@ -84,3 +112,33 @@ func getTable(keyA, keyB common.Resource) string {
 	cache.Store(keyBA, res)
 	return res
 }
+
+func (d *DB) RefreshAppLabelIndex(tx interface{}, namespace, app string, labels map[string]string) error {
+	var transaction *sqlx.Tx
+	if tx != nil {
+		transaction = tx.(*sqlx.Tx)
+	}
+	deleteSQL := `DELETE FROM baetyl_index_application_label WHERE namespace = ? and application = ?`
+	if _, err := d.Exec(transaction, deleteSQL, namespace, app); err != nil {
+		return err
+	}
+	insertSQL := `INSERT INTO baetyl_index_application_label (namespace, application, label_key, label_value) VALUES (?, ?, ?, ?)`
+	for k, v := range labels {
+		if _, err := d.Exec(transaction, insertSQL, namespace, app, k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DB) ListAppNodesByLabel(namespace, key, value string) ([]models.AppNodeBinding, error) {
+	selectSQL := `
+SELECT n.application, n.node FROM baetyl_index_application_node n 
+JOIN baetyl_index_application_label l ON l.namespace = n.namespace and l.application = n.application 
+WHERE l.namespace = ? and l.label_key = ? and l.label_value = ? ORDER BY n.application, n.node`
+	var res []models.AppNodeBinding
+	if err := d.Query(nil, selectSQL, &res, namespace, key, value); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 var (
@@ -32,6 +33,17 @@ CREATE TABLE baetyl_index_application_config
     create_time timestamp           NOT NULL DEFAULT CURRENT_TIMESTAMP,
     update_time timestamp           NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
+`,
+		`CREATE TABLE baetyl_index_application_label
+(
+    id          integer             PRIMARY KEY AUTOINCREMENT,
+    namespace   varchar(64)         NOT NULL DEFAULT '',
+    application varchar(128)        NOT NULL DEFAULT '',
+    label_key   varchar(317)        NOT NULL DEFAULT '',
+    label_value varchar(63)         NOT NULL DEFAULT '',
+    create_time timestamp           NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    update_time timestamp           NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
 `,
 	}
 )
@@ -151,3 +163,69 @@ func TestDBConnection_RefreshIndex(t *testing.T) {
 	db.RefreshIndex(nil, namespace, common.Node, common.Application, valueB, []string{valueA})
 	db.RefreshIndex(nil, namespace, common.Application, common.Node, valueA, []string{valueB})
 }
+
+func TestListIndexPage(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	defer db.Close()
+	db.MockCreateIndexTable()
+
+	namespace := "default"
+	err = db.RefreshIndex(nil, namespace, common.Application, common.Node, "app0", []string{"node-c", "node-a", "edge-b", "node-b"})
+	assert.NoError(t, err)
+
+	arr, err := db.ListIndexPage(namespace, common.Node, common.Application, "app0", &models.Filter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"edge-b", "node-a", "node-b", "node-c"}, arr)
+
+	filter := &models.Filter{Name: "node", PageNo: 2, PageSize: 2}
+	arr, err = db.ListIndexPage(namespace, common.Node, common.Application, "app0", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node-c"}, arr)
+	total, err := db.CountIndex(namespace, common.Node, common.Application, "app0", filter.GetFuzzyName())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	total, err = db.CountIndex(namespace, common.Node, common.Application, "app1", "%")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
+
+func TestAppLabelIndex(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+	db.MockCreateIndexTable()
+	ns := "default"
+
+	assert.NoError(t, db.RefreshIndex(nil, ns, common.Application, common.Node, "app1", []string{"n2", "n1"}))
+	assert.NoError(t, db.RefreshIndex(nil, ns, common.Application, common.Node, "app2", []string{"n3"}))
+	assert.NoError(t, db.RefreshIndex(nil, ns, common.Application, common.Node, "app3", []string{"n1"}))
+	assert.NoError(t, db.RefreshAppLabelIndex(nil, ns, "app1", map[string]string{"team": "data", "tier": "edge"}))
+	assert.NoError(t, db.RefreshAppLabelIndex(nil, ns, "app2", map[string]string{"team": "data"}))
+	assert.NoError(t, db.RefreshAppLabelIndex(nil, ns, "app3", map[string]string{"team": "ops"}))
+	assert.NoError(t, db.RefreshAppLabelIndex(nil, "other", "app1", map[string]string{"team": "data"}))
+
+	res, err := db.ListAppNodesByLabel(ns, "team", "data")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AppNodeBinding{{App: "app1", Node: "n1"}, {App: "app1", Node: "n2"}, {App: "app2", Node: "n3"}}, res)
+
+	// the labels are replaced, and kept by the refresh of the nodes
+	assert.NoError(t, db.RefreshAppLabelIndex(nil, ns, "app1", map[string]string{"team": "ops"}))
+	assert.NoError(t, db.RefreshIndex(nil, ns, common.Node, common.Application, "n1", []string{"app1", "app3"}))
+	res, err = db.ListAppNodesByLabel(ns, "team", "ops")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AppNodeBinding{{App: "app1", Node: "n1"}, {App: "app1", Node: "n2"}, {App: "app3", Node: "n1"}}, res)
+
+	assert.NoError(t, db.RefreshAppLabelIndex(nil, ns, "app1", nil))
+	res, err = db.ListAppNodesByLabel(ns, "tier", "edge")
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
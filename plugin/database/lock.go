@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// lockDefaultTTL the ttl of the lock in seconds if not specified, the lock held longer is taken as abandoned
+	lockDefaultTTL = 30
+	// lockRetryInterval the interval of retrying the lock held by others
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// Lock locks the name by inserting it into the lock table, so that the lock works across the replicas.
+// It retries until the lock is released or expired, or ctx is done, in which case the error of ctx is returned.
+// The version returned identifies the holder, which is required by Unlock.
+func (d *DB) Lock(ctx context.Context, name string, ttl int64) (string, error) {
+	if ttl <= 0 {
+		ttl = lockDefaultTTL
+	}
+	version := uuid.NewV4().String()
+	for {
+		now := time.Now().UTC()
+		// the lock abandoned by the holder is released once it expires
+		deleteSQL := `DELETE FROM baetyl_lock WHERE name=? AND expire_time <= ?`
+		if _, err := d.Exec(nil, deleteSQL, name, now); err != nil {
+			return "", err
+		}
+		insertSQL := `INSERT INTO baetyl_lock (name, version, expire_time) VALUES (?,?,?)`
+		_, err := d.Exec(nil, insertSQL, name, version, now.Add(time.Duration(ttl)*time.Second))
+		if err == nil {
+			return version, nil
+		}
+		// the insertion fails with the lock held by others, which is retried, or any other error
+		var count []int
+		if e := d.Query(nil, `SELECT count(*) FROM baetyl_lock WHERE name=?`, &count, name); e != nil || len(count) == 0 || count[0] == 0 {
+			return "", err
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
+
+// Unlock releases the lock held by the version, the lock taken over by others after it expires is kept
+func (d *DB) Unlock(ctx context.Context, name, version string) {
+	deleteSQL := `DELETE FROM baetyl_lock WHERE name=? AND version=?`
+	if _, err := d.Exec(nil, deleteSQL, name, version); err != nil {
+		d.Log.Warn("failed to unlock", log.Any("name", name), log.Error(err))
+	}
+}
+
+// Acquire acquires the lease of the name for the holder by inserting it into the lock table without waiting,
+// the lease already held by the holder is renewed instead
+func (d *DB) Acquire(ctx context.Context, name, holder string, ttl int64) (bool, error) {
+	if ttl <= 0 {
+		ttl = lockDefaultTTL
+	}
+	now := time.Now().UTC()
+	deleteSQL := `DELETE FROM baetyl_lock WHERE name=? AND expire_time <= ?`
+	if _, err := d.Exec(nil, deleteSQL, name, now); err != nil {
+		return false, err
+	}
+	insertSQL := `INSERT INTO baetyl_lock (name, version, expire_time) VALUES (?,?,?)`
+	_, err := d.Exec(nil, insertSQL, name, holder, now.Add(time.Duration(ttl)*time.Second))
+	if err == nil {
+		return true, nil
+	}
+	// the insertion fails with the lease held by the holder or others, or any other error
+	var holders []string
+	if e := d.Query(nil, `SELECT version FROM baetyl_lock WHERE name=?`, &holders, name); e != nil || len(holders) == 0 {
+		return false, err
+	}
+	if holders[0] != holder {
+		return false, nil
+	}
+	return d.Renew(ctx, name, holder, ttl)
+}
+
+// Renew extends the lease held by the holder if it isn't expired yet, the lease expired may be taken over by others
+func (d *DB) Renew(ctx context.Context, name, holder string, ttl int64) (bool, error) {
+	if ttl <= 0 {
+		ttl = lockDefaultTTL
+	}
+	now := time.Now().UTC()
+	updateSQL := `UPDATE baetyl_lock SET expire_time=? WHERE name=? AND version=? AND expire_time > ?`
+	res, err := d.Exec(nil, updateSQL, now.Add(time.Duration(ttl)*time.Second), name, holder, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
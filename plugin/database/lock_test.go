@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	lockTables = []string{
+		`
+CREATE TABLE baetyl_lock(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        VARCHAR(255) NOT NULL DEFAULT '',
+    version     VARCHAR(64) NOT NULL DEFAULT '',
+    expire_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (name)
+);
+`,
+	}
+)
+
+func TestLock(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+
+	// any failure other than the lock held is returned
+	_, err = db.Lock(context.Background(), "l1", 0)
+	assert.Error(t, err)
+
+	db.MockCreateTables(lockTables)
+	v1, err := db.Lock(context.Background(), "l1", 0)
+	assert.NoError(t, err)
+
+	// the lock held is waited until the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 3*lockRetryInterval)
+	_, err = db.Lock(ctx, "l1", 0)
+	cancel()
+	assert.Equal(t, context.DeadlineExceeded, err)
+	v2, err := db.Lock(context.Background(), "l2", 0)
+	assert.NoError(t, err)
+
+	// the lock is only released by its holder
+	db.Unlock(context.Background(), "l1", v2)
+	ctx, cancel = context.WithTimeout(context.Background(), 3*lockRetryInterval)
+	_, err = db.Lock(ctx, "l1", 0)
+	cancel()
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// the waiting holder acquires the released lock
+	go func() {
+		time.Sleep(lockRetryInterval)
+		db.Unlock(context.Background(), "l1", v1)
+	}()
+	v3, err := db.Lock(context.Background(), "l1", 0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, v1, v3)
+
+	// the abandoned lock is taken over once expired
+	_, err = db.Exec(nil, `UPDATE baetyl_lock SET expire_time=? WHERE name=?`, time.Now().Add(-time.Second).UTC(), "l1")
+	assert.NoError(t, err)
+	v4, err := db.Lock(context.Background(), "l1", 0)
+	assert.NoError(t, err)
+	db.Unlock(context.Background(), "l1", v3)
+	var versions []string
+	assert.NoError(t, db.Query(nil, `SELECT version FROM baetyl_lock WHERE name=?`, &versions, "l1"))
+	assert.Equal(t, []string{v4}, versions)
+}
+
+func TestLease(t *testing.T) {
+	db, err := MockNewDB()
+	if err != nil {
+		fmt.Printf("get mock sqlite3 error = %s", err.Error())
+		t.Fail()
+		return
+	}
+
+	_, err = db.Acquire(context.Background(), "lease", "h1", 0)
+	assert.Error(t, err)
+
+	db.MockCreateTables(lockTables)
+	ok, err := db.Acquire(context.Background(), "lease", "h1", 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the lease is held by one holder at once and renewed by its holder only
+	ok, err = db.Acquire(context.Background(), "lease", "h2", 0)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = db.Acquire(context.Background(), "lease", "h1", 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.Renew(context.Background(), "lease", "h1", 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = db.Renew(context.Background(), "lease", "h2", 0)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// the lease expired is lost and taken over by others
+	_, err = db.Exec(nil, `UPDATE baetyl_lock SET expire_time=? WHERE name=?`, time.Now().Add(-time.Second).UTC(), "lease")
+	assert.NoError(t, err)
+	ok, err = db.Renew(context.Background(), "lease", "h1", 0)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = db.Acquire(context.Background(), "lease", "h2", 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the lease released is acquired by others
+	db.Unlock(context.Background(), "lease", "h2")
+	ok, err = db.Acquire(context.Background(), "lease", "h1", 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,27 @@
+package event
+
+import (
+	"context"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func init() {
+	plugin.RegisterFactory("defaultevent", New)
+}
+
+// emptySink discards all events
+type emptySink struct{}
+
+func New() (plugin.Plugin, error) {
+	return &emptySink{}, nil
+}
+
+func (s *emptySink) Publish(ctx context.Context, event *models.AppEvent) error {
+	return nil
+}
+
+func (s *emptySink) Close() error {
+	return nil
+}
@@ -0,0 +1,10 @@
+package keyprovider
+
+type CloudConfig struct {
+	// Current the id of the key the values are encrypted with, Keys the AES keys of 16, 24 or 32 bytes
+	// in base64 by their ids, the retired keys are kept to decrypt the values not re-encrypted yet
+	DefaultKeyProvider struct {
+		Current string            `yaml:"current" json:"current"`
+		Keys    map[string]string `yaml:"keys" json:"keys"`
+	} `yaml:"defaultkeyprovider" json:"defaultkeyprovider"`
+}
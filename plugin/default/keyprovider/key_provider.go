@@ -0,0 +1,72 @@
+package keyprovider
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func init() {
+	plugin.RegisterFactory("defaultkeyprovider", New)
+}
+
+// defaultKeyProvider provides the keys loaded from the config file
+type defaultKeyProvider struct {
+	current string
+	keys    map[string][]byte
+}
+
+func New() (plugin.Plugin, error) {
+	var cfg CloudConfig
+	if err := common.LoadConfig(&cfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newKeyProvider(cfg)
+}
+
+func newKeyProvider(cfg CloudConfig) (*defaultKeyProvider, error) {
+	p := &defaultKeyProvider{
+		current: cfg.DefaultKeyProvider.Current,
+		keys:    map[string][]byte{},
+	}
+	for id, v := range cfg.DefaultKeyProvider.Keys {
+		// the id is a part of the encrypted values separated by colons
+		if id == "" || strings.Contains(id, ":") {
+			return nil, errors.Errorf("the id (%s) of the key is invalid", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, errors.Errorf("the key (%s) isn't in base64: %s", id, err.Error())
+		}
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return nil, errors.Errorf("the key (%s) is %d bytes, which should be 16, 24 or 32 bytes", id, len(key))
+		}
+		p.keys[id] = key
+	}
+	if _, ok := p.keys[p.current]; !ok {
+		return nil, errors.Errorf("the current key (%s) is missing", p.current)
+	}
+	return p, nil
+}
+
+func (p *defaultKeyProvider) CurrentKey() (string, []byte, error) {
+	return p.current, p.keys[p.current], nil
+}
+
+func (p *defaultKeyProvider) GetKey(id string) ([]byte, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, errors.Errorf("the key (%s) is missing", id)
+	}
+	return key, nil
+}
+
+func (p *defaultKeyProvider) Close() error {
+	return nil
+}
@@ -0,0 +1,52 @@
+package keyprovider
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyProvider(t *testing.T) {
+	k1 := make([]byte, 32)
+	k2 := make([]byte, 16)
+	k2[0] = 1
+	var cfg CloudConfig
+	cfg.DefaultKeyProvider.Current = "k2"
+	cfg.DefaultKeyProvider.Keys = map[string]string{
+		"k1": base64.StdEncoding.EncodeToString(k1),
+		"k2": base64.StdEncoding.EncodeToString(k2),
+	}
+	p, err := newKeyProvider(cfg)
+	assert.NoError(t, err)
+
+	id, key, err := p.CurrentKey()
+	assert.NoError(t, err)
+	assert.Equal(t, "k2", id)
+	assert.Equal(t, k2, key)
+
+	// the retired key is kept to decrypt
+	key, err = p.GetKey("k1")
+	assert.NoError(t, err)
+	assert.Equal(t, k1, key)
+	_, err = p.GetKey("k3")
+	assert.Error(t, err)
+
+	cfg.DefaultKeyProvider.Current = "k3"
+	_, err = newKeyProvider(cfg)
+	assert.Error(t, err)
+
+	cfg.DefaultKeyProvider.Current = "k1"
+	cfg.DefaultKeyProvider.Keys["k:3"] = base64.StdEncoding.EncodeToString(k1)
+	_, err = newKeyProvider(cfg)
+	assert.Error(t, err)
+
+	delete(cfg.DefaultKeyProvider.Keys, "k:3")
+	cfg.DefaultKeyProvider.Keys["k3"] = base64.StdEncoding.EncodeToString([]byte("short"))
+	_, err = newKeyProvider(cfg)
+	assert.Error(t, err)
+
+	cfg.DefaultKeyProvider.Keys["k3"] = "not base64"
+	_, err = newKeyProvider(cfg)
+	assert.Error(t, err)
+}
@@ -24,6 +24,14 @@ func (l *emptyLocker) Unlock(ctx context.Context, name, version string) {
 	return
 }
 
+func (l *emptyLocker) Acquire(ctx context.Context, name, holder string, ttl int64) (bool, error) {
+	return true, nil
+}
+
+func (l *emptyLocker) Renew(ctx context.Context, name, holder string, ttl int64) (bool, error) {
+	return true, nil
+}
+
 func (l *emptyLocker) Close() error {
 	return nil
 }
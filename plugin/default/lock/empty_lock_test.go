@@ -16,6 +16,13 @@ func TestEmptyLocker(t *testing.T) {
 
 	locker.Unlock(context.Background(), "", "")
 
+	ok, err := locker.Acquire(context.Background(), "", "", 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = locker.Renew(context.Background(), "", "", 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
 	err = locker.Close()
 	assert.NoError(t, err)
 }
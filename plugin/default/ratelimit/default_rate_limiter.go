@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func init() {
+	plugin.RegisterFactory("defaultratelimiter", New)
+}
+
+// defaultRateLimiter keeps the token buckets in process, so every replica is limited on its own
+type defaultRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*rate.Limiter
+}
+
+func New() (plugin.Plugin, error) {
+	return &defaultRateLimiter{buckets: map[string]*rate.Limiter{}}, nil
+}
+
+func (l *defaultRateLimiter) Allow(ctx context.Context, key string, r float64, burst int) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket, ok := l.buckets[key]
+	// the bucket is recreated if the limit is reconfigured
+	if !ok || bucket.Limit() != rate.Limit(r) || bucket.Burst() != burst {
+		bucket = rate.NewLimiter(rate.Limit(r), burst)
+		l.buckets[key] = bucket
+	}
+	return bucket.Allow(), nil
+}
+
+func (l *defaultRateLimiter) Close() error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultRateLimiter(t *testing.T) {
+	p, err := New()
+	assert.NoError(t, err)
+	limiter := p.(*defaultRateLimiter)
+	ctx := context.Background()
+
+	// the burst is allowed, the bucket is empty afterwards
+	for i := 0; i < 2; i++ {
+		ok, err := limiter.Allow(ctx, "a", 0.001, 2)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := limiter.Allow(ctx, "a", 0.001, 2)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// the buckets of the keys are separated
+	ok, err = limiter.Allow(ctx, "b", 0.001, 2)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// the bucket is refilled once reconfigured
+	ok, err = limiter.Allow(ctx, "a", 0.001, 3)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, limiter.Close())
+}
@@ -1,6 +1,8 @@
 package transaction
 
 import (
+	"context"
+
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
@@ -14,14 +16,26 @@ func New() (plugin.Plugin, error) {
 	return &defaultTxFactory{}, nil
 }
 
-func (t *defaultTxFactory) BeginTx() (interface{}, error) {
-	return nil, nil
+func (t *defaultTxFactory) BeginTx(ctx context.Context, _ *plugin.TxOptions) (interface{}, error) {
+	return nil, ctx.Err()
+}
+
+func (t *defaultTxFactory) BeginReadOnlyTx(ctx context.Context) (interface{}, error) {
+	return nil, ctx.Err()
 }
 
 func (t *defaultTxFactory) Commit(tx interface{}) {}
 
 func (t *defaultTxFactory) Rollback(tx interface{}) {}
 
+func (t *defaultTxFactory) Savepoint(tx interface{}, name string) error {
+	return nil
+}
+
+func (t *defaultTxFactory) RollbackToSavepoint(tx interface{}, name string) error {
+	return nil
+}
+
 func (t *defaultTxFactory) Close() error {
 	return nil
 }
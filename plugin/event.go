@@ -0,0 +1,16 @@
+package plugin
+
+import (
+	"context"
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/event.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin EventSink
+
+// EventSink the sink which the application lifecycle events are published to, such as kafka or webhook
+type EventSink interface {
+	Publish(ctx context.Context, event *models.AppEvent) error
+	io.Closer
+}
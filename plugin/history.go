@@ -0,0 +1,19 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/history.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppHistory
+
+type AppHistory interface {
+	// ListAppHistory lists the versions of the app kept in the history from the latest
+	ListAppHistory(namespace, name string) ([]models.AppVersion, error)
+	// DeleteAppHistory deletes the version of the app within the transaction
+	DeleteAppHistory(tx interface{}, namespace, name, version string) error
+	// UpdateAppHistoryPinned pins or unpins the version of the app
+	UpdateAppHistoryPinned(namespace, name, version string, pinned bool) error
+	io.Closer
+}
@@ -0,0 +1,20 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/idempotency.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppIdempotency
+
+type AppIdempotency interface {
+	// GetAppIdempotency gets the app created with the key, the expired one is ignored
+	GetAppIdempotency(namespace, key string) (*models.AppIdempotency, error)
+	// CreateAppIdempotency writes the key within the transaction, which fails if the key exists
+	CreateAppIdempotency(tx interface{}, idempotency *models.AppIdempotency) error
+	// DeleteExpiredAppIdempotency deletes the keys expired before the time and returns the number deleted
+	DeleteExpiredAppIdempotency(before time.Time) (int64, error)
+	io.Closer
+}
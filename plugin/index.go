@@ -7,6 +7,7 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 //go:generate mockgen -destination=../mock/plugin/index.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin Index
@@ -16,10 +17,18 @@ type Index interface {
 	// index
 	CreateIndex(namespace string, keyA, keyB common.Resource, valueA, valueB string) (sql.Result, error)
 	ListIndex(namespace string, keyA, byKeyB common.Resource, valueB string) ([]string, error)
+	// ListIndexPage lists the page of the values of keyA sorted and filtered by the fuzzy name of filter
+	ListIndexPage(namespace string, keyA, byKeyB common.Resource, valueB string, filter *models.Filter) ([]string, error)
+	// CountIndex counts the values of keyA matching the fuzzy name
+	CountIndex(namespace string, keyA, byKeyB common.Resource, valueB, name string) (int, error)
 	DeleteIndex(namespace string, keyA, byKeyB common.Resource, valueB string) (sql.Result, error)
 	CreateIndexTx(tx *sqlx.Tx, namespace string, keyA, keyB common.Resource, valueA, valueB string) (sql.Result, error)
 	ListIndexTx(tx *sqlx.Tx, namespace string, keyA, byKeyB common.Resource, valueB string) ([]string, error)
 	DeleteIndexTx(tx *sqlx.Tx, namespace string, keyA, byKeyB common.Resource, valueB string) (sql.Result, error)
 	RefreshIndex(tx interface{}, namespace string, keyA, keyB common.Resource, valueA string, valueBs []string) error
+	// RefreshAppLabelIndex replaces the labels indexed by the app, which are joined with the nodes indexed by the app
+	RefreshAppLabelIndex(tx interface{}, namespace, app string, labels map[string]string) error
+	// ListAppNodesByLabel lists the nodes indexed by the apps labeled with the key and the value
+	ListAppNodesByLabel(namespace, key, value string) ([]models.AppNodeBinding, error)
 	io.Closer
 }
@@ -0,0 +1,15 @@
+package plugin
+
+import "io"
+
+//go:generate mockgen -destination=../mock/plugin/key_provider.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin KeyProvider
+
+// KeyProvider provides the keys encrypting the values of the configs at rest, the keys are identified by their ids
+// so that the values encrypted by the retired keys are still decrypted after the current key is rotated
+type KeyProvider interface {
+	// CurrentKey returns the id and the key the values are encrypted with now
+	CurrentKey() (string, []byte, error)
+	// GetKey returns the key of the id, which is the current key or a retired one
+	GetKey(id string) ([]byte, error)
+	io.Closer
+}
@@ -6,6 +6,7 @@ import (
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/baetyl/baetyl-go/v2/utils"
 	"github.com/jinzhu/copier"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -107,6 +108,31 @@ func (c *client) GetApplication(namespace, name, version string) (*specV1.Applic
 	return toAppModel(app), nil
 }
 
+// GetApplicationForUpdate the kube store has no transaction, the concurrent write is rejected by the apiserver instead
+// since the app updated carries the resource version read
+func (c *client) GetApplicationForUpdate(tx interface{}, namespace, name string) (*specV1.Application, error) {
+	return c.GetApplication(namespace, name, "")
+}
+
+func (c *client) GetApplications(namespace string, names []string) ([]*specV1.Application, error) {
+	defer utils.Trace(c.log.Debug, "GetApplications")()
+	list, err := c.customClient.CloudV1alpha1().Applications(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]bool{}
+	for _, name := range names {
+		m[name] = true
+	}
+	res := []*specV1.Application{}
+	for i := range list.Items {
+		if m[list.Items[i].Name] {
+			res = append(res, toAppModel(&list.Items[i]))
+		}
+	}
+	return res, nil
+}
+
 func (c *client) CreateApplication(tx interface{}, namespace string, application *specV1.Application) (*specV1.Application, error) {
 	app := fromAppModel(namespace, application)
 	defer utils.Trace(c.log.Debug, "CreateApplication")()
@@ -123,6 +149,11 @@ func (c *client) UpdateApplication(tx interface{}, namespace string, application
 	defer utils.Trace(c.log.Debug, "UpdateApplication")()
 	app, err := c.customClient.CloudV1alpha1().Applications(namespace).Update(app)
 	if err != nil {
+		if kerrors.IsConflict(err) {
+			return nil, common.Error(common.ErrResourceVersionConflict,
+				common.Field("type", common.APP),
+				common.Field("name", application.Name))
+		}
 		return nil, err
 	}
 	return toAppModel(app), nil
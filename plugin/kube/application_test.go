@@ -3,14 +3,18 @@ package kube
 import (
 	"testing"
 
+	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin/kube/apis/cloud/v1alpha1"
 	"github.com/baetyl/baetyl-cloud/v2/plugin/kube/client/clientset/versioned/fake"
@@ -230,6 +234,21 @@ func TestGetApplication(t *testing.T) {
 	assert.NotNil(t, err)
 	cfg, err := c.GetApplication("default", "test_name", "")
 	assert.Equal(t, cfg.Name, "test_name")
+	cfg, err = c.GetApplicationForUpdate(nil, "default", "test_name")
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Name, "test_name")
+}
+
+func TestGetApplications(t *testing.T) {
+	c := initApplicationClient()
+	apps, err := c.GetApplications("default", []string{"test_name", "test"})
+	assert.NoError(t, err)
+	assert.Len(t, apps, 1)
+	assert.Equal(t, "test_name", apps[0].Name)
+
+	apps, err = c.GetApplications("default", []string{"test"})
+	assert.NoError(t, err)
+	assert.Empty(t, apps)
 }
 
 func TestCreateApplication(t *testing.T) {
@@ -263,6 +282,17 @@ func TestUpdateApplication(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestUpdateApplicationConflict(t *testing.T) {
+	fc := fake.NewSimpleClientset(genApplicationRuntime()...)
+	c := &client{customClient: fc, log: log.With(log.Any("plugin", "kube"))}
+	// the apiserver rejects the app changed since read
+	fc.PrependReactor("update", "applications", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kerrors.NewConflict(v1alpha1.Resource("applications"), "test_name", nil)
+	})
+	_, err := c.UpdateApplication(nil, "default", &specV1.Application{Name: "test_name", Namespace: "default", Version: "1"})
+	assert.Equal(t, common.ErrResourceVersionConflict, err.(errors.Coder).Code())
+}
+
 func TestDeleteApplication(t *testing.T) {
 	c := initApplicationClient()
 	err := c.DeleteApplication(nil, "default", "test_name")
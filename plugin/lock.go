@@ -24,5 +24,26 @@ type Locker interface {
 	// RETURNS:
 	//   error: if has error else nil
 	Unlock(ctx context.Context, name, version string)
+
+	// Acquire acquire the lease of the name for the holder without waiting, the lease held by the holder is renewed.
+	// The lease is released by Unlock with the holder as the version.
+	// PARAMS:
+	//   - name: the lease's name
+	//   - holder: the holder of the lease
+	//   - ttl: expire time of lease, if 0, use default time.
+	// RETURNS:
+	//   bool: whether the lease is held by the holder
+	//   error: if has error else nil
+	Acquire(ctx context.Context, name, holder string, ttl int64) (bool, error)
+
+	// Renew extend the lease held by the holder by ttl
+	// PARAMS:
+	//   - name: the lease's name
+	//   - holder: the holder of the lease
+	//   - ttl: expire time of lease, if 0, use default time.
+	// RETURNS:
+	//   bool: false if the lease is expired or held by others
+	//   error: if has error else nil
+	Renew(ctx context.Context, name, holder string, ttl int64) (bool, error)
 	io.Closer
 }
@@ -0,0 +1,23 @@
+package plugin
+
+import (
+	"io"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/outbox.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppOutbox
+
+type AppOutbox interface {
+	// CreateAppOutbox writes the event within the transaction, so that it is committed or rolled back with the change
+	CreateAppOutbox(tx interface{}, event *models.AppEvent) error
+	// ListAppOutbox lists the earliest events of all namespaces in the order they're written
+	ListAppOutbox(limit int) ([]models.AppOutboxEvent, error)
+	DeleteAppOutbox(id uint64) error
+	// CreateCronOutbox writes the cron operation within the transaction, so that it is committed or rolled back with the change
+	CreateCronOutbox(tx interface{}, op *models.CronOutboxOp) error
+	// ListCronOutbox lists the earliest cron operations of all namespaces in the order they're written
+	ListCronOutbox(limit int) ([]models.CronOutboxOp, error)
+	DeleteCronOutbox(id uint64) error
+	io.Closer
+}
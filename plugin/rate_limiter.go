@@ -0,0 +1,24 @@
+package plugin
+
+import (
+	"context"
+	"io"
+)
+
+//go:generate mockgen -destination=../mock/plugin/rate_limiter.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin RateLimiter
+
+// RateLimiter - the token buckets limiting the rate of the requests, the buckets should be kept in a shared
+// store for the replicas to be limited together
+type RateLimiter interface {
+
+	// Allow takes a token from the bucket of the key
+	// PARAMS:
+	//   - key: the bucket's name
+	//   - rate: the tokens refilled per second
+	//   - burst: the capacity of the bucket
+	// RETURNS:
+	//   bool: false if the bucket is empty
+	//   error: if has error else nil
+	Allow(ctx context.Context, key string, rate float64, burst int) (bool, error)
+	io.Closer
+}
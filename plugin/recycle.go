@@ -0,0 +1,21 @@
+package plugin
+
+import (
+	"io"
+	"time"
+
+	"github.com/baetyl/baetyl-cloud/v2/models"
+)
+
+//go:generate mockgen -destination=../mock/plugin/recycle.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin AppRecycle
+
+type AppRecycle interface {
+	CreateAppRecycle(tx interface{}, recycle *models.AppRecycle) error
+	GetAppRecycle(namespace, name string) (*models.AppRecycle, error)
+	DeleteAppRecycle(tx interface{}, namespace, name string) error
+	// ListAppRecycle lists the apps of the namespace kept in the recycle bin
+	ListAppRecycle(namespace string) ([]models.AppRecycle, error)
+	// ListExpiredAppRecycle lists the apps of all namespaces deleted before the time
+	ListExpiredAppRecycle(before time.Time) ([]models.AppRecycle, error)
+	io.Closer
+}
@@ -1,12 +1,33 @@
 package plugin
 
-import "io"
+import (
+	"context"
+	"database/sql"
+	"io"
+)
 
 //go:generate mockgen -destination=../mock/plugin/tx_factory.go -package=plugin github.com/baetyl/baetyl-cloud/v2/plugin TransactionFactory
 
+// TransactionFactory the factory of transactions used by the facade
 type TransactionFactory interface {
-	BeginTx() (interface{}, error)
+	// BeginTx begins a transaction with the options, the transaction is begun in the default isolation level
+	// of the storage if opts is nil. An error is returned if the context is done
+	BeginTx(ctx context.Context, opts *TxOptions) (interface{}, error)
+	// BeginReadOnlyTx begins a read-only transaction which reads a consistent snapshot without taking write locks,
+	// an error is returned if the context is done
+	BeginReadOnlyTx(ctx context.Context) (interface{}, error)
 	Commit(interface{})
 	Rollback(interface{})
+	// Savepoint sets the named savepoint within the transaction
+	Savepoint(tx interface{}, name string) error
+	// RollbackToSavepoint rolls back the writes of the transaction since the named savepoint was set,
+	// the transaction remains usable afterwards
+	RollbackToSavepoint(tx interface{}, name string) error
 	io.Closer
 }
+
+// TxOptions the options of the transaction to begin
+type TxOptions struct {
+	// Isolation the isolation level, the default level of the storage is used if it's sql.LevelDefault
+	Isolation sql.IsolationLevel
+}
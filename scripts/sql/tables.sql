@@ -79,6 +79,19 @@ CREATE TABLE IF NOT EXISTS `baetyl_index_application_node` (
   KEY `idx_node` (`namespace`,`node`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='应用与节点索引表';
 
+CREATE TABLE IF NOT EXISTS `baetyl_index_application_label` (
+  `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
+  `application` varchar(128) NOT NULL DEFAULT '' COMMENT 'app名称',
+  `label_key` varchar(317) NOT NULL DEFAULT '' COMMENT 'app标签键',
+  `label_value` varchar(63) NOT NULL DEFAULT '' COMMENT 'app标签值',
+  `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `update_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_application` (`namespace`,`application`),
+  KEY `idx_label` (`namespace`,`label_key`,`label_value`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='应用与标签索引表';
+
 CREATE TABLE IF NOT EXISTS `baetyl_index_application_secret` (
   `id` bigint(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT 'ID,主键',
   `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT '命名空间',
@@ -168,4 +181,145 @@ CREATE TABLE `baetyl_task` (
   UNIQUE KEY `udx_name` (`name`) USING BTREE,
   KEY `idx_update_status` (`update_time`,`status`) USING BTREE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='task for async process';
-COMMIT;
\ No newline at end of file
+
+CREATE TABLE IF NOT EXISTS `baetyl_cron_app` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `selector` varchar(2048) NOT NULL DEFAULT '' COMMENT 'the selector applied when the cron fires',
+  `cron_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'the time when the cron fires',
+  `cron_times` varchar(2048) NOT NULL DEFAULT '' COMMENT 'all the times of the cron scheduled by more than one',
+  `timezone` varchar(64) NOT NULL DEFAULT '' COMMENT 'IANA timezone of the cron time, UTC if empty',
+  `paused` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'the paused cron is not fired',
+  `misfire_policy` varchar(16) NOT NULL DEFAULT '' COMMENT 'the policy of the missed cron times, skip if empty',
+  `overlap_policy` varchar(16) NOT NULL DEFAULT '' COMMENT 'the policy of the overlapping runs, allow if empty',
+  `no_nodes_policy` varchar(16) NOT NULL DEFAULT '' COMMENT 'the policy of the runs matching no nodes, proceed if empty',
+  `selector_resolver` varchar(64) NOT NULL DEFAULT '' COMMENT 'the resolver of the selector query, the label selector if empty',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  `update_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'update time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name_namespace` (`name`,`namespace`),
+  KEY `idx_cron_time` (`cron_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='cron app table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_cron_run` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT 'the version of the app applied',
+  `run_trigger` varchar(16) NOT NULL DEFAULT '' COMMENT 'scheduled or manual',
+  `nodes` int(11) NOT NULL DEFAULT '0' COMMENT 'the number of the nodes resolved by the selector',
+  `success` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'whether the run is applied',
+  `message` varchar(1024) NOT NULL DEFAULT '' COMMENT 'the error of the failed run',
+  `run_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'the time of the run',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='cron app run history table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_audit` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `action` varchar(32) NOT NULL DEFAULT '' COMMENT 'create, update or delete',
+  `actor` varchar(128) NOT NULL DEFAULT '' COMMENT 'the user who made the change',
+  `old_version` varchar(36) NOT NULL DEFAULT '' COMMENT 'app version before the change',
+  `new_version` varchar(36) NOT NULL DEFAULT '' COMMENT 'app version after the change',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  PRIMARY KEY (`id`),
+  KEY `idx_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app audit table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_recycle` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT 'app version when deleted',
+  `content` mediumtext NOT NULL COMMENT 'app spec',
+  `delete_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'delete time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`),
+  KEY `idx_delete_time` (`delete_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app recycle bin table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_activation` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `version` varchar(36) NOT NULL DEFAULT '' COMMENT 'app version to activate',
+  `activate_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'activation time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`),
+  KEY `idx_activate_at` (`activate_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app scheduled activation table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_freeze` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `frozen_by` varchar(128) NOT NULL DEFAULT '' COMMENT 'the user froze the app',
+  `freeze_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'freeze time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_name` (`namespace`,`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app freeze table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_idempotency` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `idempotency_key` varchar(128) NOT NULL DEFAULT '' COMMENT 'idempotency key',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `expire_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'expire time',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_key` (`namespace`,`idempotency_key`),
+  KEY `idx_expire_time` (`expire_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app idempotency key table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_lock` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `name` varchar(255) NOT NULL DEFAULT '' COMMENT 'lock name',
+  `version` varchar(64) NOT NULL DEFAULT '' COMMENT 'the holder of the lock',
+  `expire_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'the lock is released once expired',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='distributed lock table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_app_outbox` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `content` text NOT NULL COMMENT 'app event',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app event outbox table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_cron_outbox` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `name` varchar(128) NOT NULL DEFAULT '' COMMENT 'app name',
+  `op` varchar(32) NOT NULL DEFAULT '' COMMENT 'cron operation',
+  `content` text NOT NULL COMMENT 'cron of the app',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='app cron outbox table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_config_blob` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `hash` varchar(64) NOT NULL DEFAULT '' COMMENT 'sha256 of the data',
+  `data` longblob NOT NULL COMMENT 'the large value of the configs',
+  `create_time` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'create time',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_hash` (`namespace`,`hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='config blob table';
+
+CREATE TABLE IF NOT EXISTS `baetyl_config_blob_ref` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'primary key',
+  `namespace` varchar(64) NOT NULL DEFAULT '' COMMENT 'namespace',
+  `config` varchar(128) NOT NULL DEFAULT '' COMMENT 'the config referencing the blob',
+  `hash` varchar(64) NOT NULL DEFAULT '' COMMENT 'sha256 of the blob',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_namespace_config_hash` (`namespace`,`config`,`hash`),
+  KEY `idx_namespace_hash` (`namespace`,`hash`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COMMENT='config blob reference table';
+COMMIT;
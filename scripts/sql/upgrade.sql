@@ -0,0 +1,10 @@
+USE `baetyl_cloud`;
+-- upgrades the tables created by a former tables.sql in place, the statement of a column already added fails with a duplicate column error and is skipped
+
+ALTER TABLE `baetyl_cron_app` ADD COLUMN `timezone` varchar(64) NOT NULL DEFAULT '' COMMENT 'IANA timezone of the cron time, UTC if empty';
+ALTER TABLE `baetyl_cron_app` ADD COLUMN `paused` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'the paused cron is not fired';
+ALTER TABLE `baetyl_cron_app` ADD COLUMN `cron_times` varchar(2048) NOT NULL DEFAULT '' COMMENT 'all the times of the cron scheduled by more than one';
+ALTER TABLE `baetyl_cron_app` ADD COLUMN `misfire_policy` varchar(16) NOT NULL DEFAULT '' COMMENT 'the policy of the missed cron times, skip if empty';
+ALTER TABLE `baetyl_cron_app` ADD COLUMN `overlap_policy` varchar(16) NOT NULL DEFAULT '' COMMENT 'the policy of the overlapping runs, allow if empty';
+ALTER TABLE `baetyl_cron_app` ADD COLUMN `no_nodes_policy` varchar(16) NOT NULL DEFAULT '' COMMENT 'the policy of the runs matching no nodes, proceed if empty';
+ALTER TABLE `baetyl_cron_app` ADD COLUMN `selector_resolver` varchar(64) NOT NULL DEFAULT '' COMMENT 'the resolver of the selector query, the label selector if empty';
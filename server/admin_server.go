@@ -3,14 +3,17 @@ package server
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
 	"github.com/baetyl/baetyl-go/v2/log"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/baetyl/baetyl-cloud/v2/api"
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/service"
 )
@@ -32,6 +35,9 @@ var (
 	NodeCollector plugin.QuotaCollector
 )
 
+// readinessTimeout bounds the health check of the readiness probe
+const readinessTimeout = 3 * time.Second
+
 // NewAdminServer create admin server
 func NewAdminServer(config *config.CloudConfig) (*AdminServer, error) {
 	auth, err := service.NewAuthService(config)
@@ -78,11 +84,37 @@ func (s *AdminServer) Close() {
 	s.server.Shutdown(ctx)
 }
 
+// Ready reports whether the facade can serve the writes, the unhealthy dependencies are responded
+// with 503 so that the load balancer stops routing to the replica
+func (s *AdminServer) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+	err := s.api.Facade.HealthCheck(ctx)
+	if err == nil {
+		c.JSON(common.PackageResponse(nil))
+		return
+	}
+	failures := map[string]string{}
+	if e, ok := err.(*facade.HealthError); ok {
+		for name, f := range e.Failures {
+			failures[name] = f.Error()
+		}
+	}
+	s.log.Warn("the facade is not ready", log.Error(err))
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"success":      false,
+		"message":      err.Error(),
+		"dependencies": failures,
+	})
+}
+
 // InitRoute init router
 func (s *AdminServer) InitRoute() {
 	s.router.NoRoute(NoRouteHandler)
 	s.router.NoMethod(NoMethodHandler)
 	s.router.GET("/health", Health)
+	s.router.GET("/ready", s.Ready)
+	s.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 	s.router.Use(RequestIDHandler)
 	s.router.Use(LoggerHandler)
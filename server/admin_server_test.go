@@ -21,6 +21,8 @@ import (
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/facade"
+	mockFacade "github.com/baetyl/baetyl-cloud/v2/mock/facade"
 	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
@@ -43,6 +45,11 @@ func initAdminServerMock(t *testing.T) (*AdminServer, *mockPlugin.MockAuth, *moc
 	c.Plugin.Tx = common.RandString(9)
 	c.Plugin.Sign = common.RandString(9)
 	c.Plugin.Cron = common.RandString(9)
+	c.Plugin.Event = common.RandString(9)
+	c.Plugin.Audit = common.RandString(9)
+	c.Plugin.Recycle = common.RandString(9)
+	c.Plugin.Idempotency = common.RandString(9)
+	c.Plugin.Outbox = common.RandString(9)
 	mockCtl := gomock.NewController(t)
 
 	mockObjectStorage := mockPlugin.NewMockObject(mockCtl)
@@ -121,6 +128,26 @@ func initAdminServerMock(t *testing.T) (*AdminServer, *mockPlugin.MockAuth, *moc
 	plugin.RegisterFactory(c.Plugin.Cron, func() (plugin.Plugin, error) {
 		return mockCronApp, nil
 	})
+	mockEvent := mockPlugin.NewMockEventSink(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Event, func() (plugin.Plugin, error) {
+		return mockEvent, nil
+	})
+	mockAudit := mockPlugin.NewMockAppAudit(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Audit, func() (plugin.Plugin, error) {
+		return mockAudit, nil
+	})
+	mockRecycle := mockPlugin.NewMockAppRecycle(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Recycle, func() (plugin.Plugin, error) {
+		return mockRecycle, nil
+	})
+	mockIdempotency := mockPlugin.NewMockAppIdempotency(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Idempotency, func() (plugin.Plugin, error) {
+		return mockIdempotency, nil
+	})
+	mockOutbox := mockPlugin.NewMockAppOutbox(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Outbox, func() (plugin.Plugin, error) {
+		return mockOutbox, nil
+	})
 
 	mockAPI, err := api.NewAPI(c)
 	assert.NoError(t, err)
@@ -280,3 +307,28 @@ func TestAdminServer_Handler(t *testing.T) {
 	go s.Run()
 	defer s.Close()
 }
+
+func TestAdminServer_Ready(t *testing.T) {
+	s, _, _, mockCtl := initAdminServerMock(t)
+	defer mockCtl.Finish()
+	mFacade := mockFacade.NewMockFacade(mockCtl)
+	s.api.Facade = mFacade
+	s.InitRoute()
+
+	mFacade.EXPECT().HealthCheck(gomock.Any()).Return(nil)
+	req, _ := http.NewRequest(http.MethodGet, "/ready", nil)
+	w := httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mFacade.EXPECT().HealthCheck(gomock.Any()).Return(&facade.HealthError{Failures: map[string]error{facade.HealthCron: fmt.Errorf("timeout")}})
+	req, _ = http.NewRequest(http.MethodGet, "/ready", nil)
+	w = httptest.NewRecorder()
+	s.GetRoute().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	var res struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, map[string]string{"cron": "timeout"}, res.Dependencies)
+}
@@ -35,6 +35,11 @@ func initMisServerMock(t *testing.T) (*MisServer, *gomock.Controller) {
 	c.Plugin.Locker = common.RandString(9)
 	c.Plugin.Tx = common.RandString(9)
 	c.Plugin.Cron = common.RandString(9)
+	c.Plugin.Event = common.RandString(9)
+	c.Plugin.Audit = common.RandString(9)
+	c.Plugin.Recycle = common.RandString(9)
+	c.Plugin.Idempotency = common.RandString(9)
+	c.Plugin.Outbox = common.RandString(9)
 	mockCtl := gomock.NewController(t)
 
 	mockObjectStorage := mockPlugin.NewMockObject(mockCtl)
@@ -124,6 +129,26 @@ func initMisServerMock(t *testing.T) (*MisServer, *gomock.Controller) {
 	plugin.RegisterFactory(c.Plugin.Cron, func() (plugin.Plugin, error) {
 		return mockCronApp, nil
 	})
+	mockEvent := mockPlugin.NewMockEventSink(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Event, func() (plugin.Plugin, error) {
+		return mockEvent, nil
+	})
+	mockAudit := mockPlugin.NewMockAppAudit(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Audit, func() (plugin.Plugin, error) {
+		return mockAudit, nil
+	})
+	mockRecycle := mockPlugin.NewMockAppRecycle(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Recycle, func() (plugin.Plugin, error) {
+		return mockRecycle, nil
+	})
+	mockIdempotency := mockPlugin.NewMockAppIdempotency(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Idempotency, func() (plugin.Plugin, error) {
+		return mockIdempotency, nil
+	})
+	mockOutbox := mockPlugin.NewMockAppOutbox(mockCtl)
+	plugin.RegisterFactory(c.Plugin.Outbox, func() (plugin.Plugin, error) {
+		return mockOutbox, nil
+	})
 	mockAPI, err := api.NewAPI(c)
 	assert.NoError(t, err)
 
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/app_activation.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppActivationService
+
+type AppActivationService interface {
+	CreateAppActivation(tx interface{}, activation *models.AppActivation) error
+	GetAppActivation(namespace, name string) (*models.AppActivation, error)
+	DeleteAppActivation(tx interface{}, namespace, name string) error
+	ListDueAppActivations(before time.Time, limit int) ([]models.AppActivation, error)
+}
+
+type appActivationService struct {
+	plugin.AppActivation
+}
+
+func NewAppActivationService(config *config.CloudConfig) (AppActivationService, error) {
+	activation, err := plugin.GetPlugin(config.Plugin.Activation)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &appActivationService{
+		activation.(plugin.AppActivation),
+	}, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAppActivationService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.Activation = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mActivation := mockPlugin.NewMockAppActivation(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Activation, func() (plugin.Plugin, error) {
+		return mActivation, nil
+	})
+
+	as, err := NewAppActivationService(conf)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	activation := &models.AppActivation{Namespace: "cloud", Name: "baetyl", Version: "1", ActivateAt: now}
+	mActivation.EXPECT().CreateAppActivation(nil, activation).Return(nil)
+	err = as.CreateAppActivation(nil, activation)
+	assert.NoError(t, err)
+
+	mActivation.EXPECT().GetAppActivation("cloud", "baetyl").Return(activation, nil)
+	res, err := as.GetAppActivation("cloud", "baetyl")
+	assert.NoError(t, err)
+	assert.Equal(t, activation, res)
+
+	mActivation.EXPECT().ListDueAppActivations(now, 10).Return([]models.AppActivation{*activation}, nil)
+	due, err := as.ListDueAppActivations(now, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.AppActivation{*activation}, due)
+
+	mActivation.EXPECT().DeleteAppActivation(nil, "cloud", "baetyl").Return(nil)
+	err = as.DeleteAppActivation(nil, "cloud", "baetyl")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/app_freeze.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppFreezeService
+
+type AppFreezeService interface {
+	CreateAppFreeze(tx interface{}, freeze *models.AppFreeze) error
+	GetAppFreeze(tx interface{}, namespace, name string) (*models.AppFreeze, error)
+	DeleteAppFreeze(tx interface{}, namespace, name string) error
+}
+
+type appFreezeService struct {
+	plugin.AppFreeze
+}
+
+func NewAppFreezeService(config *config.CloudConfig) (AppFreezeService, error) {
+	freeze, err := plugin.GetPlugin(config.Plugin.Freeze)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &appFreezeService{
+		freeze.(plugin.AppFreeze),
+	}, nil
+}
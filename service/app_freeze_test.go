@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAppFreezeService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.Freeze = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mFreeze := mockPlugin.NewMockAppFreeze(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Freeze, func() (plugin.Plugin, error) {
+		return mFreeze, nil
+	})
+
+	fs, err := NewAppFreezeService(conf)
+	assert.NoError(t, err)
+
+	freeze := &models.AppFreeze{Namespace: "cloud", Name: "baetyl", FrozenBy: "admin", FreezeTime: time.Now()}
+	mFreeze.EXPECT().CreateAppFreeze(nil, freeze).Return(nil)
+	err = fs.CreateAppFreeze(nil, freeze)
+	assert.NoError(t, err)
+
+	mFreeze.EXPECT().GetAppFreeze(nil, "cloud", "baetyl").Return(freeze, nil)
+	res, err := fs.GetAppFreeze(nil, "cloud", "baetyl")
+	assert.NoError(t, err)
+	assert.Equal(t, freeze, res)
+
+	mFreeze.EXPECT().DeleteAppFreeze(nil, "cloud", "baetyl").Return(nil)
+	err = fs.DeleteAppFreeze(nil, "cloud", "baetyl")
+	assert.NoError(t, err)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/baetyl/baetyl-go/v2/errors"
@@ -18,10 +19,15 @@ import (
 // ApplicationService ApplicationService
 type ApplicationService interface {
 	Get(namespace, name, version string) (*specV1.Application, error)
+	// GetForUpdate gets the current version of the app in the transaction, see plugin.Application
+	GetForUpdate(tx interface{}, namespace, name string) (*specV1.Application, error)
+	// GetBatch gets the current versions of the apps by a single query, the apps not found are omitted
+	GetBatch(namespace string, names []string) ([]*specV1.Application, error)
 	Create(tx interface{}, namespace string, app *specV1.Application) (*specV1.Application, error)
 	Update(tx interface{}, namespace string, app *specV1.Application) (*specV1.Application, error)
 	Delete(tx interface{}, namespace, name, version string) error
 	List(namespace string, listOptions *models.ListOptions) (*models.ApplicationList, error)
+	// CreateWithBase creates the app merged with the base app, see applicationService.CreateWithBase for the merge
 	CreateWithBase(tx interface{}, namespace string, app, base *specV1.Application) (*specV1.Application, error)
 }
 
@@ -70,6 +76,22 @@ func (a *applicationService) Get(namespace, name, version string) (*specV1.Appli
 	return app, err
 }
 
+// GetForUpdate get the current version of the application in the transaction
+func (a *applicationService) GetForUpdate(tx interface{}, namespace, name string) (*specV1.Application, error) {
+	app, err := a.app.GetApplicationForUpdate(tx, namespace, name)
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "app"),
+			common.Field("name", name))
+	}
+
+	return app, err
+}
+
+// GetBatch get the current versions of the applications
+func (a *applicationService) GetBatch(namespace string, names []string) ([]*specV1.Application, error) {
+	return a.app.GetApplications(namespace, names)
+}
+
 // Create create application
 func (a *applicationService) Create(tx interface{}, namespace string, app *specV1.Application) (*specV1.Application, error) {
 	configs, secrets, err := a.getConfigsAndSecrets(tx, namespace, app)
@@ -142,9 +164,16 @@ func (a *applicationService) List(namespace string,
 	return a.app.ListApplication(nil, namespace, listOptions)
 }
 
-// CreateWithBase create application with base
+// CreateWithBase creates the app merged with the base app if base is not nil. The services and the volumes of the base
+// are inherited and put before the ones of the app, while the other fields, e.g. the selector, the labels and the
+// description, are all the app's own and never inherited from the base. The app can't override the services or the
+// volumes of the base by name, nor be of another type or mode than the base, ErrInvalidBaseApp is returned before
+// anything is written if it does. The configs of the base in another namespace are copied to the namespace.
 func (a *applicationService) CreateWithBase(tx interface{}, namespace string, app, base *specV1.Application) (*specV1.Application, error) {
 	if base != nil {
+		if err := validBaseApp(app, base); err != nil {
+			return nil, err
+		}
 		if namespace != base.Namespace {
 			err := a.constructConfig(tx, namespace, base)
 			if err != nil {
@@ -163,6 +192,41 @@ func (a *applicationService) CreateWithBase(tx interface{}, namespace string, ap
 	return a.Create(tx, namespace, app)
 }
 
+// validBaseApp checks the app can be merged with the base app, the app must be of the same type and mode as the base
+// if they're set, and the names of its services and volumes must not conflict with the ones of the base
+func validBaseApp(app, base *specV1.Application) error {
+	invalid := func(format string, args ...interface{}) error {
+		return common.Error(common.ErrInvalidBaseApp,
+			common.Field("name", base.Name),
+			common.Field("error", fmt.Sprintf(format, args...)))
+	}
+	if app.Type != "" && base.Type != "" && app.Type != base.Type {
+		return invalid("the type (%s) of the app differs from the type (%s) of the base app", app.Type, base.Type)
+	}
+	if app.Mode != "" && base.Mode != "" && app.Mode != base.Mode {
+		return invalid("the mode (%s) of the app differs from the mode (%s) of the base app", app.Mode, base.Mode)
+	}
+	services := map[string]bool{}
+	for _, s := range base.Services {
+		services[s.Name] = true
+	}
+	for _, s := range app.Services {
+		if services[s.Name] {
+			return invalid("the service (%s) is defined by both the app and the base app", s.Name)
+		}
+	}
+	volumes := map[string]bool{}
+	for _, v := range base.Volumes {
+		volumes[v.Name] = true
+	}
+	for _, v := range app.Volumes {
+		if volumes[v.Name] {
+			return invalid("the volume (%s) is defined by both the app and the base app", v.Name)
+		}
+	}
+	return nil
+}
+
 func (a *applicationService) constructConfig(tx interface{}, namespace string, base *specV1.Application) error {
 	for _, v := range base.Volumes {
 		if v.Config != nil {
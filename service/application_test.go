@@ -6,10 +6,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/baetyl/baetyl-go/v2/errors"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/baetyl/baetyl-cloud/v2/common"
 	ms "github.com/baetyl/baetyl-cloud/v2/mock/service"
 	"github.com/baetyl/baetyl-cloud/v2/models"
 )
@@ -102,6 +104,41 @@ func TestDefaultApplicationService_Get(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDefaultApplicationService_GetForUpdate(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	namespace := "default"
+	name := "Deployment-get"
+
+	mockObject.app.EXPECT().GetApplicationForUpdate(nil, namespace, name).Return(&specV1.Application{Name: name, Version: "1"}, nil).Times(1)
+	cs, err := NewApplicationService(mockObject.conf)
+	assert.NoError(t, err)
+	app, err := cs.GetForUpdate(nil, namespace, name)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", app.Version)
+
+	mockObject.app.EXPECT().GetApplicationForUpdate(nil, namespace, name).Return(nil, fmt.Errorf("app not found")).Times(1)
+	_, err = cs.GetForUpdate(nil, namespace, name)
+	assert.Equal(t, common.ErrResourceNotFound, err.(errors.Coder).Code())
+}
+
+func TestDefaultApplicationService_GetBatch(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	namespace := "default"
+	names := []string{"app1", "app2"}
+	apps := []*specV1.Application{{Name: "app1", Namespace: namespace}}
+
+	mockObject.app.EXPECT().GetApplications(namespace, names).Return(apps, nil).Times(1)
+	cs, err := NewApplicationService(mockObject.conf)
+	assert.NoError(t, err)
+	res, err := cs.GetBatch(namespace, names)
+	assert.NoError(t, err)
+	assert.Equal(t, apps, res)
+}
+
 func TestDefaultApplicationService_List(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
@@ -172,6 +209,7 @@ func TestDefaultApplicationService_CreateWithBase(t *testing.T) {
 
 	mockObject.configuration.EXPECT().CreateConfig(gomock.Any(), gomock.Any(), gomock.Any()).Return(config, nil).AnyTimes()
 	mockObject.configuration.EXPECT().GetConfig(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(config, fmt.Errorf("error")).Times(1)
+	newApp, baseApp = genAppTestCase()
 	baseApp.Namespace = "test01"
 	_, err = as.CreateWithBase(nil, newApp.Namespace, newApp, baseApp)
 	assert.NotNil(t, err)
@@ -215,6 +253,79 @@ func TestDefaultApplicationService_CreateWithBase(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCreateWithBaseMerge(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	mockIndexService := ms.NewMockIndexService(mockObject.ctl)
+	as := applicationService{
+		indexService: mockIndexService,
+		config:       mockObject.configuration,
+		secret:       mockObject.secret,
+		app:          mockObject.app,
+	}
+	ns := "default"
+	base := &specV1.Application{
+		Namespace:   ns,
+		Name:        "base",
+		Type:        specV1.AppTypeContainer,
+		Selector:    "base=1",
+		Description: "base",
+		Labels:      map[string]string{"base": "1"},
+		Services:    []specV1.Service{{Name: "s1"}},
+		Volumes:     []specV1.Volume{{Name: "v1"}},
+	}
+	newApp := func() *specV1.Application {
+		return &specV1.Application{
+			Namespace: ns,
+			Name:      "abc",
+			Selector:  "app=1",
+			Labels:    map[string]string{"app": "1"},
+			Services:  []specV1.Service{{Name: "s2", VolumeMounts: []specV1.VolumeMount{{Name: "v1"}}}},
+			Volumes:   []specV1.Volume{{Name: "v2"}},
+		}
+	}
+
+	// the services and the volumes of the base come first, the other fields are the app's own
+	mockIndexService.EXPECT().RefreshConfigIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	mockIndexService.EXPECT().RefreshSecretIndexByApp(nil, ns, "abc", gomock.Any()).Return(nil)
+	mockObject.app.EXPECT().CreateApplication(nil, ns, gomock.Any()).DoAndReturn(func(_ interface{}, _ string, app *specV1.Application) (*specV1.Application, error) {
+		return app, nil
+	})
+	res, err := as.CreateWithBase(nil, ns, newApp(), base)
+	assert.NoError(t, err)
+	assert.Equal(t, []specV1.Service{{Name: "s1"}, {Name: "s2", VolumeMounts: []specV1.VolumeMount{{Name: "v1"}}}}, res.Services)
+	assert.Equal(t, []specV1.Volume{{Name: "v1"}, {Name: "v2"}}, res.Volumes)
+	assert.Equal(t, "app=1", res.Selector)
+	assert.Equal(t, map[string]string{"app": "1"}, res.Labels)
+	assert.Empty(t, res.Description)
+	assert.Empty(t, res.Type)
+
+	// nothing is written for the app conflicting with the base
+	app := newApp()
+	app.Services = append(app.Services, specV1.Service{Name: "s1"})
+	_, err = as.CreateWithBase(nil, ns, app, base)
+	assert.Equal(t, common.ErrInvalidBaseApp, err.(errors.Coder).Code())
+	assert.Contains(t, err.Error(), "the service (s1)")
+
+	app = newApp()
+	app.Volumes = append(app.Volumes, specV1.Volume{Name: "v1"})
+	_, err = as.CreateWithBase(nil, ns, app, base)
+	assert.Equal(t, common.ErrInvalidBaseApp, err.(errors.Coder).Code())
+	assert.Contains(t, err.Error(), "the volume (v1)")
+
+	app = newApp()
+	app.Type = specV1.AppTypeFunction
+	_, err = as.CreateWithBase(nil, ns, app, base)
+	assert.Equal(t, common.ErrInvalidBaseApp, err.(errors.Coder).Code())
+
+	app = newApp()
+	app.Mode = "native"
+	base.Mode = "kube"
+	_, err = as.CreateWithBase(nil, ns, app, base)
+	assert.Equal(t, common.ErrInvalidBaseApp, err.(errors.Coder).Code())
+}
+
 func TestDefaultApplicationService_Update(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/audit.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppAuditService
+
+type AppAuditService interface {
+	CreateAppAudit(tx interface{}, audit *models.AppAudit) error
+	ListAppAudit(namespace, name string) ([]models.AppAudit, error)
+}
+
+type appAuditService struct {
+	plugin.AppAudit
+}
+
+func NewAppAuditService(config *config.CloudConfig) (AppAuditService, error) {
+	audit, err := plugin.GetPlugin(config.Plugin.Audit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &appAuditService{
+		audit.(plugin.AppAudit),
+	}, nil
+}
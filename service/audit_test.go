@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAppAuditService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.Audit = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mAudit := mockPlugin.NewMockAppAudit(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Audit, func() (plugin.Plugin, error) {
+		return mAudit, nil
+	})
+
+	as, err := NewAppAuditService(conf)
+	assert.NoError(t, err)
+
+	audit := &models.AppAudit{Namespace: "cloud", Name: "baetyl", Action: models.AppCreated}
+	mAudit.EXPECT().CreateAppAudit(nil, audit).Return(nil)
+	err = as.CreateAppAudit(nil, audit)
+	assert.NoError(t, err)
+
+	mAudit.EXPECT().ListAppAudit("cloud", "baetyl").Return([]models.AppAudit{*audit}, nil)
+	res, err := as.ListAppAudit("cloud", "baetyl")
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+}
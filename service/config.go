@@ -1,6 +1,7 @@
 package service
 
 import (
+	"reflect"
 	"strings"
 	"time"
 
@@ -22,10 +23,17 @@ type ConfigService interface {
 	Update(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error)
 	Upsert(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error)
 	Delete(tx interface{}, namespace, name string) error
+	// IsReferenced checks whether the config is referenced by any app and returns the apps
+	IsReferenced(namespace, name string) (bool, []string, error)
 }
 
 type configService struct {
 	config plugin.Configuration
+	index  IndexService
+	// blob resolves the values of the configs stored in the blob store, nil if the store is disabled
+	blob ConfigBlobService
+	// cipher encrypts the values of the configs at rest, nil if the encryption is disabled
+	cipher *configCipher
 }
 
 // NewConfigService NewConfigService
@@ -34,53 +42,147 @@ func NewConfigService(config *config.CloudConfig) (ConfigService, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &configService{
+	is, err := NewIndexService(config)
+	if err != nil {
+		return nil, err
+	}
+	cs := &configService{
 		config: cfg.(plugin.Configuration),
-	}, nil
+		index:  is,
+	}
+	if config.Plugin.ConfigBlob != "" {
+		if cs.blob, err = NewConfigBlobService(config); err != nil {
+			return nil, err
+		}
+	}
+	if cs.cipher, err = newConfigCipher(config); err != nil {
+		return nil, err
+	}
+	return cs, nil
 }
 
-// Get get a config
+// Get get a config, the encrypted values are decrypted and the values stored in the blob store are inlined
 func (s *configService) Get(namespace, name, version string) (*specV1.Configuration, error) {
 	res, err := s.config.GetConfig(nil, namespace, name, version)
 	if err != nil && strings.Contains(err.Error(), "not found") {
 		return nil, common.Error(common.ErrResourceNotFound, common.Field("type", "config"),
 			common.Field("name", name))
 	}
-	return res, err
+	if res, err = s.decrypt(res, err); err != nil || s.blob == nil {
+		return res, err
+	}
+	return s.blob.Resolve(nil, namespace, res)
 }
 
-// List get list config
+// List get list config, the encrypted values are decrypted and the values stored in the blob store are inlined
 func (s *configService) List(namespace string, listOptions *models.ListOptions) (*models.ConfigurationList, error) {
-	return s.config.ListConfig(namespace, listOptions)
+	res, err := s.config.ListConfig(namespace, listOptions)
+	if err != nil || (s.cipher == nil && s.blob == nil) {
+		return res, err
+	}
+	for i := range res.Items {
+		cfg, err := s.decrypt(&res.Items[i], nil)
+		if err != nil {
+			return nil, err
+		}
+		if s.blob != nil {
+			if cfg, err = s.blob.Resolve(nil, namespace, cfg); err != nil {
+				return nil, err
+			}
+		}
+		res.Items[i] = *cfg
+	}
+	return res, nil
 }
 
 // Create Create a config
 func (s *configService) Create(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error) {
-	return s.config.CreateConfig(tx, namespace, config)
+	sealed, err := s.encrypt(config)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(s.config.CreateConfig(tx, namespace, sealed))
 }
 
 // Update update a config
 func (s *configService) Update(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error) {
-	return s.config.UpdateConfig(tx, namespace, config)
+	sealed, err := s.encrypt(config)
+	if err != nil {
+		return nil, err
+	}
+	return s.decrypt(s.config.UpdateConfig(tx, namespace, sealed))
 }
 
-// Upsert update a config or create a config if not exist
+// Upsert update a config or create a config if not exist, the config stored is compared after decrypted,
+// and it's rewritten if not encrypted by the current key, so that the values are re-encrypted after the rotation
 func (s *configService) Upsert(tx interface{}, namespace string, config *specV1.Configuration) (*specV1.Configuration, error) {
 	res, err := s.config.GetConfig(tx, namespace, config.Name, "")
 	if err != nil {
-		return s.config.CreateConfig(tx, namespace, config)
+		return s.Create(tx, namespace, config)
 	}
 
-	if models.EqualConfig(res, config) {
-		return res, nil
+	if stored, ok := s.upToDate(res); ok && (models.EqualConfig(stored, config) || unchangedConfig(stored, config)) {
+		return stored, nil
 	}
 
 	config.Version = res.Version
 	config.UpdateTimestamp = time.Now()
-	return s.config.UpdateConfig(tx, namespace, config)
+	return s.Update(tx, namespace, config)
+}
+
+func (s *configService) encrypt(config *specV1.Configuration) (*specV1.Configuration, error) {
+	if s.cipher == nil {
+		return config, nil
+	}
+	return s.cipher.encrypt(config)
+}
+
+func (s *configService) decrypt(config *specV1.Configuration, err error) (*specV1.Configuration, error) {
+	if err != nil || s.cipher == nil {
+		return config, err
+	}
+	return s.cipher.decrypt(config)
+}
+
+// upToDate returns the stored config decrypted if it's encrypted as it would be written now
+func (s *configService) upToDate(stored *specV1.Configuration) (*specV1.Configuration, bool) {
+	if s.cipher == nil {
+		return stored, true
+	}
+	if ok, err := s.cipher.upToDate(stored); err != nil || !ok {
+		return nil, false
+	}
+	res, err := s.cipher.decrypt(stored)
+	return res, err == nil
 }
 
 // Delete Delete a config
 func (s *configService) Delete(tx interface{}, namespace, name string) error {
 	return s.config.DeleteConfig(tx, namespace, name)
 }
+
+// IsReferenced checks whether the config is referenced by any app and returns the apps
+func (s *configService) IsReferenced(namespace, name string) (bool, []string, error) {
+	apps, err := s.index.ListAppIndexByConfig(namespace, name)
+	if err != nil {
+		return false, nil, err
+	}
+	return len(apps) > 0, apps, nil
+}
+
+// unchangedConfig checks the config carrying the checksum of its data, e.g. the generated function config,
+// by the checksum instead of the content, so the write is skipped if the stored data still matches the checksum
+// or is the same as the data, e.g. both reference the same blobs. The labels added to the stored config by the
+// store are ignored.
+func unchangedConfig(stored, config *specV1.Configuration) bool {
+	sum, ok := config.Labels[common.LabelConfigChecksum]
+	if !ok || stored.Labels[common.LabelConfigChecksum] != sum || stored.Description != config.Description {
+		return false
+	}
+	for k, v := range config.Labels {
+		if stored.Labels[k] != v {
+			return false
+		}
+	}
+	return models.ConfigChecksum(stored) == sum || reflect.DeepEqual(stored.Data, config.Data)
+}
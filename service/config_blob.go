@@ -0,0 +1,110 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/config_blob.go -package=service github.com/baetyl/baetyl-cloud/v2/service ConfigBlobService
+
+// ConfigBlobService stores the large values of the configs in the content-addressed blob store
+type ConfigBlobService interface {
+	// Externalize stores the values of the config larger than threshold as the blobs and returns the copy
+	// of the config referencing them, the blobs referenced by the config before but not any more are released
+	Externalize(tx interface{}, namespace string, cfg *specV1.Configuration, threshold int) (*specV1.Configuration, error)
+	// Resolve returns the copy of the config with the blobs it references inlined
+	Resolve(tx interface{}, namespace string, cfg *specV1.Configuration) (*specV1.Configuration, error)
+	// Release drops the references of the deleted config, the blobs not referenced by any other config are deleted
+	Release(tx interface{}, namespace, name string) error
+}
+
+type configBlobService struct {
+	blob plugin.ConfigBlob
+	// encrypted the patterns of the keys encrypted at rest, whose values are kept inline
+	// so that they're never stored in the blob store in plaintext
+	encrypted []string
+}
+
+func NewConfigBlobService(config *config.CloudConfig) (ConfigBlobService, error) {
+	blob, err := plugin.GetPlugin(config.Plugin.ConfigBlob)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	bs := &configBlobService{
+		blob: blob.(plugin.ConfigBlob),
+	}
+	if config.Plugin.KeyProvider != "" {
+		bs.encrypted = config.ConfigEncryption.Keys
+	}
+	return bs, nil
+}
+
+func (s *configBlobService) Externalize(tx interface{}, namespace string, cfg *specV1.Configuration, threshold int) (*specV1.Configuration, error) {
+	res := *cfg
+	res.Data = make(map[string]string, len(cfg.Data))
+	res.Labels = make(map[string]string, len(cfg.Labels))
+	for k, v := range cfg.Labels {
+		res.Labels[k] = v
+	}
+	delete(res.Labels, common.LabelConfigBlobs)
+
+	refs := map[string]bool{}
+	for k, v := range cfg.Data {
+		if threshold <= 0 || len(v) <= threshold || encryptedKey(s.encrypted, k) {
+			res.Data[k] = v
+			continue
+		}
+		hash := models.ConfigBlobHash(v)
+		if !refs[hash] {
+			if err := s.blob.CreateConfigBlob(tx, &models.ConfigBlob{Namespace: namespace, Hash: hash, Data: []byte(v)}); err != nil {
+				return nil, errors.Trace(err)
+			}
+			refs[hash] = true
+		}
+		res.Data[k] = models.ConfigBlobRef(hash)
+	}
+	hashes := make([]string, 0, len(refs))
+	for hash := range refs {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	if err := s.blob.ReplaceConfigBlobRefs(tx, namespace, cfg.Name, hashes); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(hashes) > 0 {
+		res.Labels[common.LabelConfigBlobs] = "true"
+	}
+	return &res, nil
+}
+
+func (s *configBlobService) Resolve(tx interface{}, namespace string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+	if cfg == nil || cfg.Labels[common.LabelConfigBlobs] != "true" {
+		return cfg, nil
+	}
+	res := *cfg
+	res.Data = make(map[string]string, len(cfg.Data))
+	for k, v := range cfg.Data {
+		hash, ok := models.ParseConfigBlobRef(v)
+		if !ok {
+			res.Data[k] = v
+			continue
+		}
+		blob, err := s.blob.GetConfigBlob(tx, namespace, hash)
+		if err != nil {
+			return nil, err
+		}
+		res.Data[k] = string(blob.Data)
+	}
+	return &res, nil
+}
+
+func (s *configBlobService) Release(tx interface{}, namespace, name string) error {
+	return errors.Trace(s.blob.ReplaceConfigBlobRefs(tx, namespace, name, nil))
+}
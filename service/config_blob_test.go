@@ -0,0 +1,129 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestConfigBlobService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.ConfigBlob = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mBlob := mockPlugin.NewMockConfigBlob(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.ConfigBlob, func() (plugin.Plugin, error) {
+		return mBlob, nil
+	})
+
+	bs, err := NewConfigBlobService(conf)
+	assert.NoError(t, err)
+
+	ns := "cloud"
+	large := strings.Repeat("x", 16)
+	hash := models.ConfigBlobHash(large)
+	cfg := &specV1.Configuration{
+		Name:   "baetyl-function-program-config-abc",
+		Labels: map[string]string{common.LabelAppName: "abc"},
+		Data:   map[string]string{"a.bin": large, "b.bin": large, "c.txt": "small"},
+	}
+
+	// the identical values are stored once
+	mBlob.EXPECT().CreateConfigBlob(nil, &models.ConfigBlob{Namespace: ns, Hash: hash, Data: []byte(large)}).Return(nil)
+	mBlob.EXPECT().ReplaceConfigBlobRefs(nil, ns, cfg.Name, []string{hash}).Return(nil)
+	res, err := bs.Externalize(nil, ns, cfg, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.bin": models.ConfigBlobRef(hash), "b.bin": models.ConfigBlobRef(hash), "c.txt": "small"}, res.Data)
+	assert.Equal(t, "true", res.Labels[common.LabelConfigBlobs])
+	assert.Equal(t, large, cfg.Data["a.bin"])
+	assert.NotContains(t, cfg.Labels, common.LabelConfigBlobs)
+
+	mBlob.EXPECT().GetConfigBlob(nil, ns, hash).Return(&models.ConfigBlob{Namespace: ns, Hash: hash, Data: []byte(large)}, nil).Times(2)
+	resolved, err := bs.Resolve(nil, ns, res)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Data, resolved.Data)
+
+	// the config without blobs is returned as is
+	resolved, err = bs.Resolve(nil, ns, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg, resolved)
+
+	// the values below the threshold are inline, and the blobs referenced before are released
+	mBlob.EXPECT().ReplaceConfigBlobRefs(nil, ns, cfg.Name, []string{}).Return(nil)
+	res, err = bs.Externalize(nil, ns, cfg, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Data, res.Data)
+	assert.NotContains(t, res.Labels, common.LabelConfigBlobs)
+
+	mBlob.EXPECT().GetConfigBlob(nil, ns, hash).Return(nil, common.Error(common.ErrResourceNotFound))
+	_, err = bs.Resolve(nil, ns, &specV1.Configuration{
+		Labels: map[string]string{common.LabelConfigBlobs: "true"},
+		Data:   map[string]string{"a.bin": models.ConfigBlobRef(hash)},
+	})
+	assert.Error(t, err)
+
+	mBlob.EXPECT().ReplaceConfigBlobRefs(nil, ns, cfg.Name, nil).Return(nil)
+	assert.NoError(t, bs.Release(nil, ns, cfg.Name))
+}
+
+func TestConfigService_GetBlobs(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	mBlob := mockPlugin.NewMockConfigBlob(mockObject.ctl)
+	cs := configService{
+		config: mockObject.configuration,
+		blob:   &configBlobService{blob: mBlob},
+	}
+	ns := "default"
+	hash := models.ConfigBlobHash("large")
+	stored := &specV1.Configuration{
+		Name:    "baetyl-function-program-config-abc",
+		Version: "5",
+		Labels:  map[string]string{common.LabelConfigBlobs: "true", common.LabelConfigChecksum: "sum"},
+		Data:    map[string]string{"a.bin": models.ConfigBlobRef(hash)},
+	}
+	mockObject.configuration.EXPECT().GetConfig(nil, ns, stored.Name, "").Return(stored, nil)
+	mBlob.EXPECT().GetConfigBlob(nil, ns, hash).Return(&models.ConfigBlob{Namespace: ns, Hash: hash, Data: []byte("large")}, nil)
+	res, err := cs.Get(ns, stored.Name, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.bin": "large"}, res.Data)
+
+	// the config referencing the same blobs is unchanged
+	mockObject.configuration.EXPECT().GetConfig(nil, ns, stored.Name, "").Return(stored, nil)
+	mockObject.configuration.EXPECT().UpdateConfig(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	res, err = cs.Upsert(nil, ns, &specV1.Configuration{
+		Name:   stored.Name,
+		Labels: map[string]string{common.LabelConfigBlobs: "true", common.LabelConfigChecksum: "sum"},
+		Data:   map[string]string{"a.bin": models.ConfigBlobRef(hash)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "5", res.Version)
+
+	// the listed configs are resolved as well
+	mockObject.configuration.EXPECT().ListConfig(ns, gomock.Any()).Return(&models.ConfigurationList{
+		Items: []specV1.Configuration{*stored, {Name: "small", Data: map[string]string{"a": "b"}}},
+	}, nil)
+	mBlob.EXPECT().GetConfigBlob(nil, ns, hash).Return(&models.ConfigBlob{Namespace: ns, Hash: hash, Data: []byte("large")}, nil)
+	list, err := cs.List(ns, &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.bin": "large"}, list.Items[0].Data)
+	assert.Equal(t, map[string]string{"a": "b"}, list.Items[1].Data)
+
+	mockObject.configuration.EXPECT().ListConfig(ns, gomock.Any()).Return(&models.ConfigurationList{
+		Items: []specV1.Configuration{*stored},
+	}, nil)
+	mBlob.EXPECT().GetConfigBlob(nil, ns, hash).Return(nil, errors.New("failed"))
+	_, err = cs.List(ns, &models.ListOptions{})
+	assert.Error(t, err)
+}
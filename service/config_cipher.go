@@ -0,0 +1,189 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+// configCipherPrefix prefixes the encrypted value, which is followed by the id of the key and the base64 of
+// the nonce and the sealed value separated by a colon
+const configCipherPrefix = "baetyl-encrypted:"
+
+// configCipher encrypts the values of the configs whose keys match the patterns at rest by AES-GCM,
+// the key of the value is authenticated along so that the encrypted values can't be swapped
+type configCipher struct {
+	keys     plugin.KeyProvider
+	patterns []string
+}
+
+// newConfigCipher returns nil if the key provider isn't set
+func newConfigCipher(config *config.CloudConfig) (*configCipher, error) {
+	if config.Plugin.KeyProvider == "" {
+		return nil, nil
+	}
+	for _, p := range config.ConfigEncryption.Keys {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, errors.Errorf("the pattern (%s) of the encrypted keys is invalid: %s", p, err.Error())
+		}
+	}
+	keys, err := plugin.GetPlugin(config.Plugin.KeyProvider)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &configCipher{
+		keys:     keys.(plugin.KeyProvider),
+		patterns: config.ConfigEncryption.Keys,
+	}, nil
+}
+
+// encryptedKey checks whether the value of the key is encrypted at rest by the patterns
+func encryptedKey(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// encrypt returns the copy of the config with the values of the matched keys encrypted by the current key,
+// the values encrypted already are decrypted first so that they're re-encrypted by the current key
+func (c *configCipher) encrypt(cfg *specV1.Configuration) (*specV1.Configuration, error) {
+	if cfg == nil {
+		return cfg, nil
+	}
+	plain, err := c.decrypt(cfg)
+	if err != nil {
+		return nil, err
+	}
+	id, key, err := c.keys.CurrentKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, c.error(cfg, "", err)
+	}
+	res := copyConfigData(plain)
+	encrypted := false
+	for k, v := range plain.Data {
+		if !encryptedKey(c.patterns, k) {
+			continue
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+			return nil, c.error(cfg, k, err)
+		}
+		sealed := aead.Seal(nonce, nonce, []byte(v), []byte(k))
+		res.Data[k] = configCipherPrefix + id + ":" + base64.StdEncoding.EncodeToString(sealed)
+		encrypted = true
+	}
+	if encrypted {
+		res.Labels[common.LabelConfigEncrypted] = "true"
+	}
+	return res, nil
+}
+
+// decrypt returns the copy of the config with the encrypted values decrypted by the keys encrypting them
+func (c *configCipher) decrypt(cfg *specV1.Configuration) (*specV1.Configuration, error) {
+	if cfg == nil || cfg.Labels[common.LabelConfigEncrypted] != "true" {
+		return cfg, nil
+	}
+	res := copyConfigData(cfg)
+	for k, v := range cfg.Data {
+		id, payload, ok := parseEncryptedValue(v)
+		if !ok {
+			continue
+		}
+		key, err := c.keys.GetKey(id)
+		if err != nil {
+			return nil, c.error(cfg, k, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, c.error(cfg, k, err)
+		}
+		sealed, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil || len(sealed) < aead.NonceSize() {
+			return nil, c.error(cfg, k, errors.New("the encrypted value is malformed"))
+		}
+		plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(k))
+		if err != nil {
+			return nil, c.error(cfg, k, err)
+		}
+		res.Data[k] = string(plain)
+	}
+	return res, nil
+}
+
+// upToDate checks whether the stored config is encrypted as it would be written now, that's the values of the
+// matched keys are encrypted by the current key and the others aren't encrypted, otherwise it's rewritten
+func (c *configCipher) upToDate(stored *specV1.Configuration) (bool, error) {
+	id, _, err := c.keys.CurrentKey()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	labeled := stored.Labels[common.LabelConfigEncrypted] == "true"
+	for k, v := range stored.Data {
+		keyID, _, ok := parseEncryptedValue(v)
+		ok = ok && labeled
+		if encryptedKey(c.patterns, k) != ok || (ok && keyID != id) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (c *configCipher) error(cfg *specV1.Configuration, key string, err error) error {
+	return common.Error(common.ErrConfigCipher,
+		common.Field("name", cfg.Name),
+		common.Field("key", key),
+		common.Field("error", err.Error()))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// parseEncryptedValue returns the id of the key and the payload of the encrypted value
+func parseEncryptedValue(v string) (string, string, bool) {
+	if !strings.HasPrefix(v, configCipherPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(v, configCipherPrefix), ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// copyConfigData copies the config with the data and the labels, the label marking the encryption is dropped
+func copyConfigData(cfg *specV1.Configuration) *specV1.Configuration {
+	res := *cfg
+	res.Data = make(map[string]string, len(cfg.Data))
+	for k, v := range cfg.Data {
+		res.Data[k] = v
+	}
+	res.Labels = make(map[string]string, len(cfg.Labels))
+	for k, v := range cfg.Labels {
+		res.Labels[k] = v
+	}
+	delete(res.Labels, common.LabelConfigEncrypted)
+	return &res
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func mockKeyProvider(ctl *gomock.Controller, current string) *mockPlugin.MockKeyProvider {
+	keys := map[string][]byte{
+		"k1": []byte(strings.Repeat("1", 32)),
+		"k2": []byte(strings.Repeat("2", 16)),
+	}
+	mKeys := mockPlugin.NewMockKeyProvider(ctl)
+	mKeys.EXPECT().CurrentKey().Return(current, keys[current], nil).AnyTimes()
+	mKeys.EXPECT().GetKey(gomock.Any()).DoAndReturn(func(id string) ([]byte, error) {
+		if key, ok := keys[id]; ok {
+			return key, nil
+		}
+		return nil, errors.New("missing")
+	}).AnyTimes()
+	return mKeys
+}
+
+func TestConfigCipher(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	conf := &config.CloudConfig{}
+	conf.Plugin.KeyProvider = common.RandString(9)
+	conf.ConfigEncryption.Keys = []string{"*.password", "token"}
+	mKeys := mockKeyProvider(mockCtl, "k1")
+	plugin.RegisterFactory(conf.Plugin.KeyProvider, func() (plugin.Plugin, error) {
+		return mKeys, nil
+	})
+	c, err := newConfigCipher(conf)
+	assert.NoError(t, err)
+
+	cfg := &specV1.Configuration{
+		Name:   "baetyl-function-config-abc",
+		Labels: map[string]string{common.LabelAppName: "abc"},
+		Data:   map[string]string{"db.password": "secret", "token": "t", "index.py": "print(1)"},
+	}
+	sealed, err := c.encrypt(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "true", sealed.Labels[common.LabelConfigEncrypted])
+	assert.Equal(t, "print(1)", sealed.Data["index.py"])
+	assert.True(t, strings.HasPrefix(sealed.Data["db.password"], configCipherPrefix+"k1:"))
+	assert.NotContains(t, sealed.Data["db.password"], "secret")
+	assert.Equal(t, "secret", cfg.Data["db.password"])
+	ok, err := c.upToDate(sealed)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	plain, err := c.decrypt(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Data, plain.Data)
+	assert.Equal(t, cfg.Labels, plain.Labels)
+
+	// the config without the encrypted values is read as is
+	plain, err = c.decrypt(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg, plain)
+	ok, err = c.upToDate(cfg)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// the encrypted values can't be swapped between the keys
+	swapped := copyConfigData(sealed)
+	swapped.Labels[common.LabelConfigEncrypted] = "true"
+	swapped.Data["token"] = sealed.Data["db.password"]
+	_, err = c.decrypt(swapped)
+	assert.Equal(t, common.ErrConfigCipher, err.(errors.Coder).Code())
+
+	// the values encrypted by the retired key are decrypted, and re-encrypted by the current key
+	rotated := &configCipher{keys: mockKeyProvider(mockCtl, "k2"), patterns: c.patterns}
+	ok, err = rotated.upToDate(sealed)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	plain, err = rotated.decrypt(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Data, plain.Data)
+	resealed, err := rotated.encrypt(sealed)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resealed.Data["db.password"], configCipherPrefix+"k2:"))
+	plain, err = rotated.decrypt(resealed)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Data, plain.Data)
+
+	// the value encrypted by the missing key fails
+	missing := copyConfigData(sealed)
+	missing.Labels[common.LabelConfigEncrypted] = "true"
+	missing.Data["token"] = configCipherPrefix + "k3:" + strings.SplitN(sealed.Data["token"], ":", 3)[2]
+	_, err = rotated.decrypt(missing)
+	assert.Equal(t, common.ErrConfigCipher, err.(errors.Coder).Code())
+
+	conf.ConfigEncryption.Keys = []string{"["}
+	_, err = newConfigCipher(conf)
+	assert.Error(t, err)
+	conf.Plugin.KeyProvider = ""
+	c, err = newConfigCipher(conf)
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+}
+
+func TestConfigService_Encrypted(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	c := &configCipher{keys: mockKeyProvider(mockObject.ctl, "k1"), patterns: []string{"*.password"}}
+	cs := configService{
+		config: mockObject.configuration,
+		cipher: c,
+	}
+	ns := "default"
+	gen := func() *specV1.Configuration {
+		cfg := &specV1.Configuration{
+			Name:   "baetyl-function-config-abc",
+			Labels: map[string]string{common.LabelAppName: "abc"},
+			Data:   map[string]string{"db.password": "secret", "index.py": "print(1)"},
+		}
+		cfg.Labels[common.LabelConfigChecksum] = models.ConfigChecksum(cfg)
+		return cfg
+	}
+	encrypted := func(cfg *specV1.Configuration) bool {
+		return cfg.Labels[common.LabelConfigEncrypted] == "true" && strings.HasPrefix(cfg.Data["db.password"], configCipherPrefix)
+	}
+
+	// the values are encrypted when written and decrypted when read
+	var stored *specV1.Configuration
+	mockObject.configuration.EXPECT().CreateConfig(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+			assert.True(t, encrypted(cfg))
+			stored = cfg
+			stored.Version = "5"
+			return stored, nil
+		})
+	res, err := cs.Create(nil, ns, gen())
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", res.Data["db.password"])
+
+	mockObject.configuration.EXPECT().GetConfig(nil, ns, stored.Name, "").Return(stored, nil)
+	res, err = cs.Get(ns, stored.Name, "")
+	assert.NoError(t, err)
+	assert.Equal(t, gen().Data, res.Data)
+
+	mockObject.configuration.EXPECT().ListConfig(ns, gomock.Any()).Return(&models.ConfigurationList{Items: []specV1.Configuration{*stored}}, nil)
+	list, err := cs.List(ns, &models.ListOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, gen().Data, list.Items[0].Data)
+
+	// the unchanged config is compared after decrypted
+	mockObject.configuration.EXPECT().GetConfig(nil, ns, stored.Name, "").Return(stored, nil)
+	mockObject.configuration.EXPECT().UpdateConfig(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	res, err = cs.Upsert(nil, ns, gen())
+	assert.NoError(t, err)
+	assert.Equal(t, "5", res.Version)
+	assert.Equal(t, "secret", res.Data["db.password"])
+
+	// the config encrypted by the retired key is rewritten by the current key
+	cs.cipher = &configCipher{keys: mockKeyProvider(mockObject.ctl, "k2"), patterns: c.patterns}
+	mockObject.configuration.EXPECT().GetConfig(nil, ns, stored.Name, "").Return(stored, nil)
+	mockObject.configuration.EXPECT().UpdateConfig(nil, ns, gomock.Any()).DoAndReturn(
+		func(_ interface{}, _ string, cfg *specV1.Configuration) (*specV1.Configuration, error) {
+			assert.True(t, strings.HasPrefix(cfg.Data["db.password"], configCipherPrefix+"k2:"))
+			assert.Equal(t, "5", cfg.Version)
+			return cfg, nil
+		})
+	res, err = cs.Upsert(nil, ns, gen())
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", res.Data["db.password"])
+}
+
+func TestConfigBlobServiceEncrypted(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+	mBlob := mockPlugin.NewMockConfigBlob(mockCtl)
+	bs := &configBlobService{blob: mBlob, encrypted: []string{"*.key"}}
+
+	// the encrypted value is kept inline however large it is
+	cfg := &specV1.Configuration{Name: "c", Data: map[string]string{"tls.key": strings.Repeat("k", 16)}}
+	mBlob.EXPECT().ReplaceConfigBlobRefs(nil, "ns", "c", []string{}).Return(nil)
+	res, err := bs.Externalize(nil, "ns", cfg, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg.Data, res.Data)
+}
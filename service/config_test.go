@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
@@ -124,6 +125,64 @@ func TestDefaultConfigService_Upsert(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestConfigService_UpsertUnchanged(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	cs := configService{
+		config: mockObject.configuration,
+	}
+	namespace := "default"
+	gen := func() *specV1.Configuration {
+		cfg := &specV1.Configuration{
+			Name:   "baetyl-function-config-abc",
+			Labels: map[string]string{common.LabelAppName: "abc"},
+			Data:   map[string]string{"index.py": "print(1)"},
+		}
+		cfg.Labels[common.LabelConfigChecksum] = models.ConfigChecksum(cfg)
+		return cfg
+	}
+	// the labels are added by the store
+	stored := gen()
+	stored.Version = "5"
+	stored.Labels[common.LabelSystem] = "true"
+
+	// the unchanged function config of the updated app isn't written
+	mockObject.configuration.EXPECT().GetConfig(nil, namespace, stored.Name, "").Return(stored, nil).Times(2)
+	mockObject.configuration.EXPECT().UpdateConfig(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	for i := 0; i < 2; i++ {
+		res, err := cs.Upsert(nil, namespace, gen())
+		assert.NoError(t, err)
+		assert.Equal(t, "5", res.Version)
+	}
+
+	// the changed data is written
+	changed := gen()
+	changed.Data["index.py"] = "print(2)"
+	changed.Labels[common.LabelConfigChecksum] = models.ConfigChecksum(changed)
+	mockObject.configuration.EXPECT().GetConfig(nil, namespace, stored.Name, "").Return(stored, nil)
+	mockObject.configuration.EXPECT().UpdateConfig(nil, namespace, changed).Return(&specV1.Configuration{Version: "6"}, nil)
+	res, err := cs.Upsert(nil, namespace, changed)
+	assert.NoError(t, err)
+	assert.Equal(t, "6", res.Version)
+
+	// the stored config drifted from its checksum is rewritten
+	drifted := gen()
+	drifted.Version = "5"
+	drifted.Data["index.py"] = "corrupted"
+	mockObject.configuration.EXPECT().GetConfig(nil, namespace, stored.Name, "").Return(drifted, nil)
+	mockObject.configuration.EXPECT().UpdateConfig(nil, namespace, gomock.Any()).Return(&specV1.Configuration{Version: "6"}, nil)
+	_, err = cs.Upsert(nil, namespace, gen())
+	assert.NoError(t, err)
+
+	// the changed label is written
+	relabeled := gen()
+	relabeled.Labels["k"] = "v"
+	mockObject.configuration.EXPECT().GetConfig(nil, namespace, stored.Name, "").Return(stored, nil)
+	mockObject.configuration.EXPECT().UpdateConfig(nil, namespace, relabeled).Return(&specV1.Configuration{Version: "6"}, nil)
+	_, err = cs.Upsert(nil, namespace, relabeled)
+	assert.NoError(t, err)
+}
+
 func TestDefaultConfigService_Delete(t *testing.T) {
 	mockObject := InitMockEnvironment(t)
 	defer mockObject.Close()
@@ -139,3 +198,30 @@ func TestDefaultConfigService_Delete(t *testing.T) {
 	err = cs.Delete(nil, namespace, name)
 	assert.NoError(t, err)
 }
+
+func TestDefaultConfigService_IsReferenced(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	namespace := "default"
+	name := "ConfigService-referenced"
+
+	cs, err := NewConfigService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.index.EXPECT().ListIndex(namespace, common.Application, common.Config, name).Return([]string{}, nil)
+	referenced, apps, err := cs.IsReferenced(namespace, name)
+	assert.NoError(t, err)
+	assert.False(t, referenced)
+	assert.Empty(t, apps)
+
+	mockObject.index.EXPECT().ListIndex(namespace, common.Application, common.Config, name).Return([]string{"app1", "app2"}, nil)
+	referenced, apps, err = cs.IsReferenced(namespace, name)
+	assert.NoError(t, err)
+	assert.True(t, referenced)
+	assert.Equal(t, []string{"app1", "app2"}, apps)
+
+	mockObject.index.EXPECT().ListIndex(namespace, common.Application, common.Config, name).Return(nil, fmt.Errorf("error"))
+	_, _, err = cs.IsReferenced(namespace, name)
+	assert.Error(t, err)
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"sort"
+	"time"
+
 	"github.com/baetyl/baetyl-go/v2/errors"
 
 	"github.com/baetyl/baetyl-cloud/v2/config"
@@ -11,12 +14,23 @@ import (
 //go:generate mockgen -destination=../mock/service/cron.go -package=service github.com/baetyl/baetyl-cloud/v2/service CronService
 
 type CronService interface {
-	GetCron(name, namespace string) (*models.Cron, error)
+	GetCron(tx interface{}, name, namespace string) (*models.Cron, error)
+	ListCrons(tx interface{}, namespace string, names []string) ([]models.Cron, error)
+	// ListCronsPage lists the page of all the crons of the namespace and the total number of the crons
+	ListCronsPage(namespace string, filter *models.Filter) ([]models.Cron, int, error)
 	CreateCron(*models.Cron) error
 	UpdateCron(*models.Cron) error
+	// SetCronPaused pauses or resumes the cron, the paused cron is skipped by ListExpiredApps
+	SetCronPaused(name, namespace string, paused bool) error
 	DeleteCron(name, namespace string) error
 	ListExpiredApps() ([]models.Cron, error)
+	// DeleteExpiredApps completes the expired crons fired, the cron scheduled by more than one time is advanced
+	// to the next of its times after now so that it fires again then, and the cron with no time left is deleted
 	DeleteExpiredApps([]uint64) error
+	// CreateCronRun records the run of the cron within the transaction if tx is not nil
+	CreateCronRun(tx interface{}, run *models.CronRun) error
+	// ListCronRuns lists the page of the runs of the cron of the app from the latest and the total number of the runs
+	ListCronRuns(namespace, name string, filter *models.Filter) ([]models.CronRun, int, error)
 }
 
 type cronService struct {
@@ -33,3 +47,55 @@ func NewCronService(config *config.CloudConfig) (CronService, error) {
 		cron.(plugin.Cron),
 	}, nil
 }
+
+// CronFirings returns the times of the expired cron which the scheduler fires now according to its misfire policy.
+// The time passed no longer than the threshold is on time and always fired, the earlier ones are missed, e.g.
+// while the scheduler is down, they're dropped by MisfireSkip, coalesced into a single firing by MisfireFireOnce
+// unless any time is on time, and all fired by MisfireFireAll. The cron isn't fired if nothing is returned.
+func CronFirings(cron *models.Cron, now time.Time, threshold time.Duration) []time.Time {
+	var res, missed []time.Time
+	for _, t := range cron.Schedules() {
+		if t.After(now) {
+			continue
+		}
+		if now.Sub(t) <= threshold {
+			res = append(res, t)
+		} else {
+			missed = append(missed, t)
+		}
+	}
+	switch cron.MisfirePolicy {
+	case models.MisfireFireOnce:
+		if len(res) == 0 && len(missed) > 0 {
+			res = missed[len(missed)-1:]
+		}
+	case models.MisfireFireAll:
+		res = append(missed, res...)
+	}
+	return res
+}
+
+// CronNextRuns returns at most n upcoming times of the cron after now in order, they're in the timezone
+// of the cron so that the schedule is shown as it's defined. The paused cron has no upcoming times.
+func CronNextRuns(cron *models.Cron, now time.Time, n int) []time.Time {
+	if cron.Paused || n <= 0 {
+		return nil
+	}
+	loc := time.UTC
+	if cron.Timezone != "" {
+		if l, err := time.LoadLocation(cron.Timezone); err == nil {
+			loc = l
+		}
+	}
+	var res []time.Time
+	for _, t := range cron.Schedules() {
+		if t.After(now) {
+			res = append(res, t.In(loc))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Before(res[j]) })
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
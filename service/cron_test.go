@@ -41,10 +41,15 @@ func TestCronService(t *testing.T) {
 		CronTime:  time.Now(),
 	}
 
-	mCron.EXPECT().GetCron(n, ns).Return(cronEntity, nil)
-	_, err = cs.GetCron(n, ns)
+	mCron.EXPECT().GetCron(nil, n, ns).Return(cronEntity, nil)
+	_, err = cs.GetCron(nil, n, ns)
 	assert.NoError(t, err)
 
+	mCron.EXPECT().ListCrons(nil, ns, []string{n}).Return([]models.Cron{*cronEntity}, nil)
+	crons, err := cs.ListCrons(nil, ns, []string{n})
+	assert.NoError(t, err)
+	assert.Len(t, crons, 1)
+
 	mCron.EXPECT().CreateCron(cronEntity).Return(nil)
 	err = cs.CreateCron(cronEntity)
 	assert.NoError(t, err)
@@ -53,6 +58,10 @@ func TestCronService(t *testing.T) {
 	err = cs.UpdateCron(cronEntity)
 	assert.NoError(t, err)
 
+	mCron.EXPECT().SetCronPaused(n, ns, true).Return(nil)
+	err = cs.SetCronPaused(n, ns, true)
+	assert.NoError(t, err)
+
 	mCron.EXPECT().DeleteCron(n, ns).Return(nil)
 	err = cs.DeleteCron(n, ns)
 	assert.NoError(t, err)
@@ -65,3 +74,50 @@ func TestCronService(t *testing.T) {
 	err = cs.DeleteExpiredApps(nil)
 	assert.NoError(t, err)
 }
+
+func TestCronFirings(t *testing.T) {
+	now := time.Date(2021, 10, 1, 8, 0, 0, 0, time.UTC)
+	missed1, missed2, onTime := now.Add(-2*time.Hour), now.Add(-time.Hour), now.Add(-10*time.Second)
+	cron := &models.Cron{CronTime: missed1, CronTimes: []time.Time{missed1, missed2, onTime, now.Add(time.Hour)}}
+
+	assert.Equal(t, []time.Time{onTime}, CronFirings(cron, now, time.Minute))
+	cron.MisfirePolicy = models.MisfireSkip
+	assert.Equal(t, []time.Time{onTime}, CronFirings(cron, now, time.Minute))
+	// the missed times are coalesced into the time on time
+	cron.MisfirePolicy = models.MisfireFireOnce
+	assert.Equal(t, []time.Time{onTime}, CronFirings(cron, now, time.Minute))
+	cron.MisfirePolicy = models.MisfireFireAll
+	assert.Equal(t, []time.Time{missed1, missed2, onTime}, CronFirings(cron, now, time.Minute))
+
+	// the single cron time missed
+	cron = &models.Cron{CronTime: missed1}
+	assert.Empty(t, CronFirings(cron, now, time.Minute))
+	cron.MisfirePolicy = models.MisfireFireOnce
+	assert.Equal(t, []time.Time{missed1}, CronFirings(cron, now, time.Minute))
+	cron.MisfirePolicy = models.MisfireFireAll
+	assert.Equal(t, []time.Time{missed1}, CronFirings(cron, now, time.Minute))
+
+	cron = &models.Cron{CronTime: missed1, CronTimes: []time.Time{missed1, missed2}, MisfirePolicy: models.MisfireFireOnce}
+	assert.Equal(t, []time.Time{missed2}, CronFirings(cron, now, time.Minute))
+}
+
+func TestCronNextRuns(t *testing.T) {
+	now := time.Date(2021, 10, 1, 8, 0, 0, 0, time.UTC)
+	past, t1, t2, t3 := now.Add(-time.Hour), now.Add(time.Hour), now.Add(2*time.Hour), now.Add(3*time.Hour)
+	cron := &models.Cron{CronTime: past, CronTimes: []time.Time{past, t3, t1, t2}}
+
+	assert.Equal(t, []time.Time{t1, t2}, CronNextRuns(cron, now, 2))
+	assert.Equal(t, []time.Time{t1, t2, t3}, CronNextRuns(cron, now, 5))
+	assert.Empty(t, CronNextRuns(cron, now, 0))
+	assert.Empty(t, CronNextRuns(cron, t3, 5))
+
+	// the times are in the timezone of the cron
+	cron = &models.Cron{CronTime: t1, Timezone: "Asia/Shanghai"}
+	res := CronNextRuns(cron, now, 5)
+	assert.Len(t, res, 1)
+	assert.True(t, res[0].Equal(t1))
+	assert.Equal(t, "2021-10-01T17:00:00+08:00", res[0].Format(time.RFC3339))
+
+	cron.Paused = true
+	assert.Empty(t, CronNextRuns(cron, now, 5))
+}
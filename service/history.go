@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/history.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppHistoryService
+
+type AppHistoryService interface {
+	ListAppHistory(namespace, name string) ([]models.AppVersion, error)
+	DeleteAppHistory(tx interface{}, namespace, name, version string) error
+	UpdateAppHistoryPinned(namespace, name, version string, pinned bool) error
+}
+
+type appHistoryService struct {
+	plugin.AppHistory
+}
+
+func NewAppHistoryService(config *config.CloudConfig) (AppHistoryService, error) {
+	history, err := plugin.GetPlugin(config.Plugin.AppHistory)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &appHistoryService{
+		history.(plugin.AppHistory),
+	}, nil
+}
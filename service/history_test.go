@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAppHistoryService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.AppHistory = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mHistory := mockPlugin.NewMockAppHistory(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.AppHistory, func() (plugin.Plugin, error) {
+		return mHistory, nil
+	})
+
+	hs, err := NewAppHistoryService(conf)
+	assert.NoError(t, err)
+
+	versions := []models.AppVersion{{Namespace: "cloud", Name: "baetyl", Version: "2"}, {Namespace: "cloud", Name: "baetyl", Version: "1"}}
+	mHistory.EXPECT().ListAppHistory("cloud", "baetyl").Return(versions, nil)
+	res, err := hs.ListAppHistory("cloud", "baetyl")
+	assert.NoError(t, err)
+	assert.Equal(t, versions, res)
+
+	mHistory.EXPECT().DeleteAppHistory(nil, "cloud", "baetyl", "1").Return(nil)
+	err = hs.DeleteAppHistory(nil, "cloud", "baetyl", "1")
+	assert.NoError(t, err)
+
+	mHistory.EXPECT().UpdateAppHistoryPinned("cloud", "baetyl", "2", true).Return(nil)
+	err = hs.UpdateAppHistoryPinned("cloud", "baetyl", "2", true)
+	assert.NoError(t, err)
+}
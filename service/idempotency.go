@@ -0,0 +1,33 @@
+package service
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/idempotency.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppIdempotencyService
+
+type AppIdempotencyService interface {
+	GetAppIdempotency(namespace, key string) (*models.AppIdempotency, error)
+	CreateAppIdempotency(tx interface{}, idempotency *models.AppIdempotency) error
+	DeleteExpiredAppIdempotency(before time.Time) (int64, error)
+}
+
+type appIdempotencyService struct {
+	plugin.AppIdempotency
+}
+
+func NewAppIdempotencyService(config *config.CloudConfig) (AppIdempotencyService, error) {
+	idempotency, err := plugin.GetPlugin(config.Plugin.Idempotency)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &appIdempotencyService{
+		idempotency.(plugin.AppIdempotency),
+	}, nil
+}
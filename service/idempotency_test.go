@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAppIdempotencyService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.Idempotency = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mIdempotency := mockPlugin.NewMockAppIdempotency(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Idempotency, func() (plugin.Plugin, error) {
+		return mIdempotency, nil
+	})
+
+	is, err := NewAppIdempotencyService(conf)
+	assert.NoError(t, err)
+
+	idempotency := &models.AppIdempotency{Namespace: "cloud", Key: "key", Name: "baetyl"}
+	mIdempotency.EXPECT().CreateAppIdempotency(nil, idempotency).Return(nil)
+	err = is.CreateAppIdempotency(nil, idempotency)
+	assert.NoError(t, err)
+
+	mIdempotency.EXPECT().GetAppIdempotency("cloud", "key").Return(idempotency, nil)
+	res, err := is.GetAppIdempotency("cloud", "key")
+	assert.NoError(t, err)
+	assert.Equal(t, idempotency, res)
+
+	before := time.Now()
+	mIdempotency.EXPECT().DeleteExpiredAppIdempotency(before).Return(int64(1), nil)
+	n, err := is.DeleteExpiredAppIdempotency(before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
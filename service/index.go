@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/baetyl/baetyl-cloud/v2/common"
 	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 )
 
@@ -19,12 +20,21 @@ type IndexService interface {
 	ListConfigIndexByApp(namespace, app string) ([]string, error)
 
 	ListNodesByApp(namespace, app string) ([]string, error)
+	// ListNodesPageByApp lists the page of the nodes of the app and the total number of the nodes matching the filter
+	ListNodesPageByApp(namespace, app string, filter *models.Filter) ([]string, int, error)
+	// ListAppsPageByNode lists the page of the apps of the node and the total number of the apps matching the filter
+	ListAppsPageByNode(namespace, node string, filter *models.Filter) ([]string, int, error)
 	ListAppsByNode(namespace, node string) ([]string, error)
 	ListAppIndexBySecret(namespace, secret string) ([]string, error)
 
 	// app and secret
 	RefreshSecretIndexByApp(tx interface{}, namespace, app string, secrets []string) error
 	RefreshNodesIndexByApp(tx interface{}, namespace, appName string, nodes []string) error
+	// RefreshNodesIndexByAppWithLabels refreshes the nodes indexed by the app along with the labels of the app,
+	// the labels are dropped if the app is left without nodes
+	RefreshNodesIndexByAppWithLabels(tx interface{}, namespace, appName string, labels map[string]string, nodes []string) error
+	// ListAppNodesByLabel lists the nodes indexed by the apps labeled with the key and the value
+	ListAppNodesByLabel(namespace, key, value string) ([]models.AppNodeBinding, error)
 	RefreshAppsIndexByNode(tx interface{}, namespace, node string, apps []string) error
 }
 
@@ -67,10 +77,37 @@ func (i *indexService) ListConfigIndexByApp(namespace, app string) ([]string, er
 	return i.ListIndex(namespace, common.Config, common.Application, app)
 }
 
+// RefreshNodesIndexByApp refreshes the nodes indexed by the app within the transaction, the refresh is recorded
+// by RecordNodesIndexRefresh once the transaction is committed
 func (i *indexService) RefreshNodesIndexByApp(tx interface{}, namespace, appName string, nodes []string) error {
 	return i.RefreshIndex(tx, namespace, common.Application, common.Node, appName, nodes)
 }
 
+// RecordNodesIndexRefresh records the entries refreshed and the refresh time of the nodes indexed by the app,
+// it's called after the refresh is committed so that the refreshes rolled back aren't recorded
+func RecordNodesIndexRefresh(namespace, appName string, nodes int) {
+	indexRefreshedEntries.WithLabelValues(namespace).Add(float64(nodes))
+	if nodes == 0 {
+		indexLastRefresh.DeleteLabelValues(namespace, appName)
+	} else {
+		indexLastRefresh.WithLabelValues(namespace, appName).SetToCurrentTime()
+	}
+}
+
+func (i *indexService) RefreshNodesIndexByAppWithLabels(tx interface{}, namespace, appName string, labels map[string]string, nodes []string) error {
+	if err := i.RefreshNodesIndexByApp(tx, namespace, appName, nodes); err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		labels = nil
+	}
+	return i.index.RefreshAppLabelIndex(tx, namespace, appName, labels)
+}
+
+func (i *indexService) ListAppNodesByLabel(namespace, key, value string) ([]models.AppNodeBinding, error) {
+	return i.index.ListAppNodesByLabel(namespace, key, value)
+}
+
 func (i *indexService) RefreshAppsIndexByNode(tx interface{}, namespace, node string, apps []string) error {
 	return i.RefreshIndex(tx, namespace, common.Node, common.Application, node, apps)
 }
@@ -79,6 +116,30 @@ func (i *indexService) ListNodesByApp(namespace, app string) ([]string, error) {
 	return i.ListIndex(namespace, common.Node, common.Application, app)
 }
 
+func (i *indexService) ListNodesPageByApp(namespace, app string, filter *models.Filter) ([]string, int, error) {
+	nodes, err := i.index.ListIndexPage(namespace, common.Node, common.Application, app, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := i.index.CountIndex(namespace, common.Node, common.Application, app, filter.GetFuzzyName())
+	if err != nil {
+		return nil, 0, err
+	}
+	return nodes, total, nil
+}
+
+func (i *indexService) ListAppsPageByNode(namespace, node string, filter *models.Filter) ([]string, int, error) {
+	apps, err := i.index.ListIndexPage(namespace, common.Application, common.Node, node, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := i.index.CountIndex(namespace, common.Application, common.Node, node, filter.GetFuzzyName())
+	if err != nil {
+		return nil, 0, err
+	}
+	return apps, total, nil
+}
+
 func (i *indexService) ListAppsByNode(namespace, node string) ([]string, error) {
 	return i.ListIndex(namespace, common.Application, common.Node, node)
 }
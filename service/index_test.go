@@ -3,11 +3,14 @@ package service
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/models"
 )
 
 func TestDefaultIndexService_RefreshIndex(t *testing.T) {
@@ -99,3 +102,102 @@ func TestResourceRefresh(t *testing.T) {
 	err = is.RefreshAppsIndexByNode(nil, namespace, data, arr)
 	assert.NoError(t, err)
 }
+
+func TestListNodesPageByApp(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	namespace := "default"
+	filter := &models.Filter{Name: "node", PageNo: 1, PageSize: 2}
+	is, err := NewIndexService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.index.EXPECT().ListIndexPage(namespace, common.Node, common.Application, "app", filter).Return([]string{"node-a", "node-b"}, nil)
+	mockObject.index.EXPECT().CountIndex(namespace, common.Node, common.Application, "app", "%node%").Return(3, nil)
+	nodes, total, err := is.ListNodesPageByApp(namespace, "app", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node-a", "node-b"}, nodes)
+	assert.Equal(t, 3, total)
+
+	mockObject.index.EXPECT().ListIndexPage(namespace, common.Node, common.Application, "app", filter).Return([]string{"node-a", "node-b"}, nil)
+	mockObject.index.EXPECT().CountIndex(namespace, common.Node, common.Application, "app", "%node%").Return(0, fmt.Errorf("error"))
+	_, _, err = is.ListNodesPageByApp(namespace, "app", filter)
+	assert.Error(t, err)
+}
+
+func TestListAppsPageByNode(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	namespace := "default"
+	filter := &models.Filter{Name: "app", PageNo: 1, PageSize: 2}
+	is, err := NewIndexService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.index.EXPECT().ListIndexPage(namespace, common.Application, common.Node, "node", filter).Return([]string{"app-a", "app-b"}, nil)
+	mockObject.index.EXPECT().CountIndex(namespace, common.Application, common.Node, "node", "%app%").Return(3, nil)
+	apps, total, err := is.ListAppsPageByNode(namespace, "node", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app-a", "app-b"}, apps)
+	assert.Equal(t, 3, total)
+
+	mockObject.index.EXPECT().ListIndexPage(namespace, common.Application, common.Node, "node", filter).Return(nil, fmt.Errorf("error"))
+	_, _, err = is.ListAppsPageByNode(namespace, "node", filter)
+	assert.Error(t, err)
+}
+
+func TestRefreshNodesIndexByAppMetrics(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns := "metrics-ns"
+	is, err := NewIndexService(mockObject.conf)
+	assert.NoError(t, err)
+	refreshed := testutil.ToFloat64(indexRefreshedEntries.WithLabelValues(ns))
+
+	// the refresh isn't recorded until RecordNodesIndexRefresh is called after the commit
+	mockObject.index.EXPECT().RefreshIndex(nil, ns, common.Application, common.Node, "app", []string{"n1", "n2"}).Return(nil)
+	err = is.RefreshNodesIndexByApp(nil, ns, "app", []string{"n1", "n2"})
+	assert.NoError(t, err)
+	assert.Equal(t, refreshed, testutil.ToFloat64(indexRefreshedEntries.WithLabelValues(ns)))
+
+	start := float64(time.Now().Unix())
+	RecordNodesIndexRefresh(ns, "app", 2)
+	assert.Equal(t, refreshed+2, testutil.ToFloat64(indexRefreshedEntries.WithLabelValues(ns)))
+	assert.GreaterOrEqual(t, testutil.ToFloat64(indexLastRefresh.WithLabelValues(ns, "app")), start)
+
+	// the app left without nodes is dropped
+	RecordNodesIndexRefresh(ns, "app", 0)
+	assert.Equal(t, refreshed+2, testutil.ToFloat64(indexRefreshedEntries.WithLabelValues(ns)))
+	assert.False(t, indexLastRefresh.DeleteLabelValues(ns, "app"))
+}
+
+func TestRefreshNodesIndexByAppWithLabels(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+
+	ns, labels := "default", map[string]string{"team": "data"}
+	is, err := NewIndexService(mockObject.conf)
+	assert.NoError(t, err)
+
+	mockObject.index.EXPECT().RefreshIndex(nil, ns, common.Application, common.Node, "app", []string{"n1"}).Return(nil)
+	mockObject.index.EXPECT().RefreshAppLabelIndex(nil, ns, "app", labels).Return(nil)
+	err = is.RefreshNodesIndexByAppWithLabels(nil, ns, "app", labels, []string{"n1"})
+	assert.NoError(t, err)
+
+	// the labels of the app without nodes are dropped
+	mockObject.index.EXPECT().RefreshIndex(nil, ns, common.Application, common.Node, "app", []string{}).Return(nil)
+	mockObject.index.EXPECT().RefreshAppLabelIndex(nil, ns, "app", nil).Return(nil)
+	err = is.RefreshNodesIndexByAppWithLabels(nil, ns, "app", labels, []string{})
+	assert.NoError(t, err)
+
+	mockObject.index.EXPECT().RefreshIndex(nil, ns, common.Application, common.Node, "app", []string{"n1"}).Return(fmt.Errorf("error"))
+	err = is.RefreshNodesIndexByAppWithLabels(nil, ns, "app", labels, []string{"n1"})
+	assert.Error(t, err)
+
+	bindings := []models.AppNodeBinding{{App: "app", Node: "n1"}}
+	mockObject.index.EXPECT().ListAppNodesByLabel(ns, "team", "data").Return(bindings, nil)
+	res, err := is.ListAppNodesByLabel(ns, "team", "data")
+	assert.NoError(t, err)
+	assert.Equal(t, bindings, res)
+}
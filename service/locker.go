@@ -12,6 +12,8 @@ import (
 type LockerService interface {
 	Lock(ctx context.Context, name string, ttl int64) (string, error)
 	Unlock(ctx context.Context, name, value string)
+	Acquire(ctx context.Context, name, holder string, ttl int64) (bool, error)
+	Renew(ctx context.Context, name, holder string, ttl int64) (bool, error)
 }
 
 // NewModuleService
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// indexRefreshedEntries counts the node-app index entries written by the refreshes, so that its rate over an
+	// interval is the number of the entries refreshed in it. The refreshes rolled back aren't counted.
+	indexRefreshedEntries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "baetyl_cloud_index_refreshed_entries_total",
+		Help: "The number of node-app index entries refreshed partitioned by namespace.",
+	}, []string{"namespace"})
+
+	// indexLastRefresh the time the node-app index of the app is last refreshed, so that the staleness of the index
+	// is the time since then. The app left without nodes is dropped since there is no entry to be stale.
+	indexLastRefresh = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "baetyl_cloud_index_last_refresh_timestamp_seconds",
+		Help: "The unix time the node-app index of an app is last refreshed partitioned by namespace and app.",
+	}, []string{"namespace", "app"})
+)
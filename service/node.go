@@ -22,6 +22,9 @@ type CheckResourceDependencyFunc func(ns, nodeName string) error
 
 const casRetryTimes = 3
 
+// desireChunkSize the number of the node shadows loaded and written at once when refreshing the desires by an app
+var desireChunkSize = 200
+
 // NodeService NodeService
 type NodeService interface {
 	Get(tx interface{}, namespace, name string) (*specV1.Node, error)
@@ -30,7 +33,7 @@ type NodeService interface {
 	CountAll() (map[string]int, error)
 
 	Create(tx interface{}, namespace string, node *specV1.Node) (*specV1.Node, error)
-	Update(namespace string, node *specV1.Node) (*specV1.Node, error)
+	Update(tx interface{}, namespace string, node *specV1.Node) (*specV1.Node, error)
 	Delete(namespace, name string) error
 
 	UpdateReport(namespace, name string, report specV1.Report) (*models.Shadow, error)
@@ -38,6 +41,7 @@ type NodeService interface {
 
 	GetDesire(namespace, name string) (*specV1.Desire, error)
 
+	MatchNodes(tx interface{}, namespace, selector string) ([]string, error)
 	UpdateNodeAppVersion(tx interface{}, namespace string, app *specV1.Application) ([]string, error)
 	DeleteNodeAppVersion(tx interface{}, namespace string, app *specV1.Application) ([]string, error)
 
@@ -134,28 +138,14 @@ func (n *NodeServiceImpl) Create(tx interface{}, namespace string, node *specV1.
 	return res, err
 }
 
-// Update update node
-func (n *NodeServiceImpl) Update(namespace string, node *specV1.Node) (*specV1.Node, error) {
-	list, err := n.Node.UpdateNode(nil, namespace, []*specV1.Node{node})
+// Update update node within the transaction, the apps bound to the node are not refreshed since it's done
+// within the same transaction by the facade (UpdateNode) if the labels change
+func (n *NodeServiceImpl) Update(tx interface{}, namespace string, node *specV1.Node) (*specV1.Node, error) {
+	list, err := n.Node.UpdateNode(tx, namespace, []*specV1.Node{node})
 	if err != nil || len(list) < 1 {
 		return nil, err
 	}
-	res := list[0]
-
-	shadow, err := n.Shadow.Get(nil, namespace, node.Name)
-	if err != nil {
-		return nil, err
-	}
-
-	// delete indexes for node and apps
-	if err := n.IndexService.RefreshAppsIndexByNode(nil, namespace, res.Name, []string{}); err != nil {
-		return nil, err
-	}
-
-	if err = n.InsertOrUpdateNodeAndAppIndex(nil, namespace, res, shadow, false); err != nil {
-		return nil, err
-	}
-	return res, nil
+	return list[0], nil
 }
 
 // List get list node
@@ -314,16 +304,28 @@ func (n *NodeServiceImpl) updateReportNodeProperties(ns, name string, report spe
 
 // UpdateDesire Update Desire
 // Parameter f can be RefreshNodeDesireByApp or DeleteNodeDesireByApp
+// UpdateDesire refreshes the desires of the nodes by the app chunk by chunk (desireChunkSize nodes per chunk),
+// so that the shadows of the apps targeting large fleets are never loaded at once. All the chunks are written
+// within tx, which are committed or rolled back together.
 func (n *NodeServiceImpl) UpdateDesire(tx interface{}, namespace string, names []string, app *specV1.Application, f func(*models.Shadow, *specV1.Application)) error {
-	shadows, err := n.Shadow.ListShadowByNames(tx, namespace, names)
-	if err != nil {
-		return err
-	}
-	for _, shadow := range shadows {
-		// Refresh desire in Shadow by app
-		f(shadow, app)
+	for start := 0; start < len(names); start += desireChunkSize {
+		end := start + desireChunkSize
+		if end > len(names) {
+			end = len(names)
+		}
+		shadows, err := n.Shadow.ListShadowByNames(tx, namespace, names[start:end])
+		if err != nil {
+			return err
+		}
+		for _, shadow := range shadows {
+			// Refresh desire in Shadow by app
+			f(shadow, app)
+		}
+		if err = n.Shadow.UpdateDesires(tx, shadows); err != nil {
+			return err
+		}
 	}
-	return n.Shadow.UpdateDesires(tx, shadows)
+	return nil
 }
 
 func (n *NodeServiceImpl) updateDesire(tx interface{}, shadow *models.Shadow, desire specV1.Desire) error {
@@ -419,23 +421,32 @@ func (n *NodeServiceImpl) rematchApplicationsForNode(apps *models.ApplicationLis
 
 }
 
+// MatchNodes returns the names of nodes matched by the label selector, which is used by app deployment
+func (n *NodeServiceImpl) MatchNodes(tx interface{}, namespace, selector string) ([]string, error) {
+	if selector == "" {
+		return nil, nil
+	}
+	nodeList, err := n.Node.ListNode(tx, namespace, &models.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	var nodes []string
+	for idx := range nodeList.Items {
+		nodes = append(nodes, nodeList.Items[idx].Name)
+	}
+	return nodes, nil
+}
+
 // UpdateNodeAppVersion update the node desire's appVersion for app changed
 func (n *NodeServiceImpl) UpdateNodeAppVersion(tx interface{}, namespace string, app *specV1.Application) ([]string, error) {
 	if app.Selector == "" {
 		return nil, nil
 	}
 
-	// list nodes
-	nodeList, err := n.Node.ListNode(tx, namespace, &models.ListOptions{LabelSelector: app.Selector})
+	nodes, err := n.MatchNodes(tx, namespace, app.Selector)
 	if err != nil {
 		return nil, err
 	}
-	// update nodes
-	var nodes []string
-	for idx := range nodeList.Items {
-		node := &nodeList.Items[idx]
-		nodes = append(nodes, node.Name)
-	}
 	err = n.UpdateDesire(tx, namespace, nodes, app, RefreshNodeDesireByApp)
 	if err != nil {
 		return nil, err
@@ -463,18 +474,10 @@ func (n *NodeServiceImpl) DeleteNodeAppVersion(tx interface{}, namespace string,
 		return nil, nil
 	}
 
-	// list nodes
-	nodeList, err := n.Node.ListNode(tx, namespace, &models.ListOptions{LabelSelector: app.Selector})
+	nodes, err := n.MatchNodes(tx, namespace, app.Selector)
 	if err != nil {
 		return nil, err
 	}
-
-	// update nodes
-	var nodes []string
-	for idx := range nodeList.Items {
-		node := &nodeList.Items[idx]
-		nodes = append(nodes, node.Name)
-	}
 	err = n.UpdateDesire(tx, namespace, nodes, app, DeleteNodeDesireByApp)
 	if err != nil {
 		return nil, err
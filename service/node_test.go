@@ -201,63 +201,44 @@ func TestDefaultNodeService_Update(t *testing.T) {
 		Node:         mockObject.node,
 		App:          mockObject.app,
 	}
-	app := &specV1.Application{
-		Name:    "appTest",
-		Version: "1234",
-	}
 
 	node := &specV1.Node{
 		Name:      "node01",
 		Namespace: "test",
 	}
 
-	shadow := genShadowTestCase()
-
-	mockObject.shadow.EXPECT().UpdateDesire(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	mockObject.shadow.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(shadow, nil).AnyTimes()
-
 	mockObject.node.EXPECT().UpdateNode(nil, node.Namespace, []*specV1.Node{node}).Return(nil, fmt.Errorf("error"))
-	_, err := ns.Update(node.Namespace, node)
+	_, err := ns.Update(nil, node.Namespace, node)
 	assert.NotNil(t, err)
 
-	mockObject.node.EXPECT().UpdateNode(nil, node.Namespace, []*specV1.Node{node}).Return([]*specV1.Node{node}, nil).AnyTimes()
-	mockIndexService.EXPECT().RefreshAppsIndexByNode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("error"))
-	_, err = ns.Update(node.Namespace, node)
-	assert.NotNil(t, err)
+	// the apps bound to the node are refreshed by the facade instead
+	mockObject.node.EXPECT().UpdateNode(nil, node.Namespace, []*specV1.Node{node}).Return([]*specV1.Node{node}, nil)
+	shad, err := ns.Update(nil, node.Namespace, node)
+	assert.NoError(t, err)
+	assert.Equal(t, node.Name, shad.Name)
+}
 
-	//mockObject.matcher.EXPECT().IsLabelMatch(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
-	mockIndexService.EXPECT().RefreshAppsIndexByNode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	mockObject.app.EXPECT().ListApplication(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
-	_, err = ns.Update(node.Namespace, node)
-	assert.NotNil(t, err)
+func TestMatchNodes(t *testing.T) {
+	mockObject := InitMockEnvironment(t)
+	defer mockObject.Close()
+	ss := NodeServiceImpl{
+		Node: mockObject.node,
+	}
+	ns := "default"
 
-	appList := &models.ApplicationList{Items: []models.AppItem{
-		{
-			Name:      app.Name,
-			Namespace: app.Namespace,
-			Version:   app.Version,
-			Selector:  "env=test",
-			Labels: map[string]string{
-				common.LabelSystem: app.Name,
-			},
-		},
-		{
-			Name:      "app01",
-			Namespace: app.Namespace,
-			Version:   app.Version,
-			Selector:  "env=test",
-		},
-		{
-			Name:      "app02",
-			Namespace: app.Namespace,
-			Version:   app.Version,
-		},
-	}}
-	mockObject.app.EXPECT().ListApplication(gomock.Any(), gomock.Any(), gomock.Any()).Return(appList, nil).AnyTimes()
-	mockObject.shadow.EXPECT().UpdateDesire(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	shad, err := ns.Update(node.Namespace, node)
+	nodes, err := ss.MatchNodes(nil, ns, "")
 	assert.NoError(t, err)
-	assert.Equal(t, node.Name, shad.Name)
+	assert.Nil(t, nodes)
+
+	mockObject.node.EXPECT().ListNode(nil, ns, &models.ListOptions{LabelSelector: "a=b"}).Return(nil, fmt.Errorf("error"))
+	_, err = ss.MatchNodes(nil, ns, "a=b")
+	assert.Error(t, err)
+
+	nodeList := &models.NodeList{Items: []specV1.Node{{Name: "n1"}, {Name: "n2"}}}
+	mockObject.node.EXPECT().ListNode(nil, ns, &models.ListOptions{LabelSelector: "a=b"}).Return(nodeList, nil)
+	nodes, err = ss.MatchNodes(nil, ns, "a=b")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n1", "n2"}, nodes)
 }
 
 func TestUpdateNodeAppVersion(t *testing.T) {
@@ -749,6 +730,19 @@ func TestUpdateDesired(t *testing.T) {
 
 	err = ns.UpdateDesire(nil, namespace, names, app, RefreshNodeDesireByApp)
 	assert.NoError(t, err)
+
+	// the desires are refreshed chunk by chunk within the transaction
+	defer func(size int) { desireChunkSize = size }(desireChunkSize)
+	desireChunkSize = 2
+	names = []string{"n1", "n2", "n3"}
+	gomock.InOrder(
+		mockObject.shadow.EXPECT().ListShadowByNames(gomock.Any(), namespace, []string{"n1", "n2"}).Return(shadows, nil),
+		mockObject.shadow.EXPECT().UpdateDesires(gomock.Any(), shadows).Return(nil),
+		mockObject.shadow.EXPECT().ListShadowByNames(gomock.Any(), namespace, []string{"n3"}).Return(shadows, nil),
+		mockObject.shadow.EXPECT().UpdateDesires(gomock.Any(), shadows).Return(listErr),
+	)
+	err = ns.UpdateDesire(nil, namespace, names, app, RefreshNodeDesireByApp)
+	assert.Equal(t, listErr, err)
 }
 
 func TestRematchApplicationForNode(t *testing.T) {
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/outbox.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppOutboxService
+
+type AppOutboxService interface {
+	CreateAppOutbox(tx interface{}, event *models.AppEvent) error
+	ListAppOutbox(limit int) ([]models.AppOutboxEvent, error)
+	DeleteAppOutbox(id uint64) error
+	CreateCronOutbox(tx interface{}, op *models.CronOutboxOp) error
+	ListCronOutbox(limit int) ([]models.CronOutboxOp, error)
+	DeleteCronOutbox(id uint64) error
+}
+
+type appOutboxService struct {
+	plugin.AppOutbox
+}
+
+func NewAppOutboxService(config *config.CloudConfig) (AppOutboxService, error) {
+	outbox, err := plugin.GetPlugin(config.Plugin.Outbox)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &appOutboxService{
+		outbox.(plugin.AppOutbox),
+	}, nil
+}
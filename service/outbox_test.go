@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAppOutboxService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.Outbox = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mOutbox := mockPlugin.NewMockAppOutbox(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Outbox, func() (plugin.Plugin, error) {
+		return mOutbox, nil
+	})
+
+	os, err := NewAppOutboxService(conf)
+	assert.NoError(t, err)
+
+	event := &models.AppEvent{Namespace: "cloud", Name: "baetyl", Action: models.AppCreated}
+	mOutbox.EXPECT().CreateAppOutbox(nil, event).Return(nil)
+	err = os.CreateAppOutbox(nil, event)
+	assert.NoError(t, err)
+
+	events := []models.AppOutboxEvent{{Id: 1, Event: *event}}
+	mOutbox.EXPECT().ListAppOutbox(10).Return(events, nil)
+	res, err := os.ListAppOutbox(10)
+	assert.NoError(t, err)
+	assert.Equal(t, events, res)
+
+	mOutbox.EXPECT().DeleteAppOutbox(uint64(1)).Return(nil)
+	err = os.DeleteAppOutbox(1)
+	assert.NoError(t, err)
+}
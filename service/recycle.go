@@ -0,0 +1,35 @@
+package service
+
+import (
+	"time"
+
+	"github.com/baetyl/baetyl-go/v2/errors"
+
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+//go:generate mockgen -destination=../mock/service/recycle.go -package=service github.com/baetyl/baetyl-cloud/v2/service AppRecycleService
+
+type AppRecycleService interface {
+	CreateAppRecycle(tx interface{}, recycle *models.AppRecycle) error
+	GetAppRecycle(namespace, name string) (*models.AppRecycle, error)
+	DeleteAppRecycle(tx interface{}, namespace, name string) error
+	ListAppRecycle(namespace string) ([]models.AppRecycle, error)
+	ListExpiredAppRecycle(before time.Time) ([]models.AppRecycle, error)
+}
+
+type appRecycleService struct {
+	plugin.AppRecycle
+}
+
+func NewAppRecycleService(config *config.CloudConfig) (AppRecycleService, error) {
+	recycle, err := plugin.GetPlugin(config.Plugin.Recycle)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &appRecycleService{
+		recycle.(plugin.AppRecycle),
+	}, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/baetyl/baetyl-cloud/v2/common"
+	"github.com/baetyl/baetyl-cloud/v2/config"
+	mockPlugin "github.com/baetyl/baetyl-cloud/v2/mock/plugin"
+	"github.com/baetyl/baetyl-cloud/v2/models"
+	"github.com/baetyl/baetyl-cloud/v2/plugin"
+)
+
+func TestAppRecycleService(t *testing.T) {
+	conf := &config.CloudConfig{}
+	conf.Plugin.Recycle = common.RandString(9)
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mRecycle := mockPlugin.NewMockAppRecycle(mockCtl)
+	plugin.RegisterFactory(conf.Plugin.Recycle, func() (plugin.Plugin, error) {
+		return mRecycle, nil
+	})
+
+	rs, err := NewAppRecycleService(conf)
+	assert.NoError(t, err)
+
+	recycle := &models.AppRecycle{Namespace: "cloud", Name: "baetyl"}
+	mRecycle.EXPECT().CreateAppRecycle(nil, recycle).Return(nil)
+	err = rs.CreateAppRecycle(nil, recycle)
+	assert.NoError(t, err)
+
+	mRecycle.EXPECT().GetAppRecycle("cloud", "baetyl").Return(recycle, nil)
+	res, err := rs.GetAppRecycle("cloud", "baetyl")
+	assert.NoError(t, err)
+	assert.Equal(t, recycle, res)
+
+	mRecycle.EXPECT().ListAppRecycle("cloud").Return([]models.AppRecycle{*recycle}, nil)
+	list, err := rs.ListAppRecycle("cloud")
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	before := time.Now()
+	mRecycle.EXPECT().ListExpiredAppRecycle(before).Return([]models.AppRecycle{*recycle}, nil)
+	list, err = rs.ListExpiredAppRecycle(before)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	mRecycle.EXPECT().DeleteAppRecycle(nil, "cloud", "baetyl").Return(nil)
+	err = rs.DeleteAppRecycle(nil, "cloud", "baetyl")
+	assert.NoError(t, err)
+}
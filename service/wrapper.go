@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+
 	"github.com/baetyl/baetyl-cloud/v2/config"
 	"github.com/baetyl/baetyl-cloud/v2/plugin"
 	specV1 "github.com/baetyl/baetyl-go/v2/spec/v1"
@@ -45,7 +47,7 @@ func (w *WrapperServiceImpl) FinishTx(err error, tx interface{}) {
 
 func (w *WrapperServiceImpl) CreateNodeTx(function CreateNodeFunc) CreateNodeFunc {
 	return func(tx interface{}, namespace string, node *specV1.Node) (*specV1.Node, error) {
-		transaction, err := w.BeginTx()
+		transaction, err := w.BeginTx(context.Background(), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -41,16 +41,16 @@ func TestCreateWrapper(t *testing.T) {
 	wrapper, err := NewWrapperService(cfg)
 	assert.NoError(t, err)
 
-	mTx.EXPECT().BeginTx().Return(nil, errors.New("error"))
+	mTx.EXPECT().BeginTx(gomock.Any(), nil).Return(nil, errors.New("error"))
 	_, err = wrapper.CreateNodeTx(mockCreateNodeY)(nil, "", nil)
 	assert.Error(t, err)
 
-	mTx.EXPECT().BeginTx().Return(nil, nil)
+	mTx.EXPECT().BeginTx(gomock.Any(), nil).Return(nil, nil)
 	mTx.EXPECT().Rollback(nil).Return()
 	_, err = wrapper.CreateNodeTx(mockCreateNodeN)(nil, "", nil)
 	assert.Error(t, err)
 
-	mTx.EXPECT().BeginTx().Return(nil, nil)
+	mTx.EXPECT().BeginTx(gomock.Any(), nil).Return(nil, nil)
 	mTx.EXPECT().Commit(nil).Return()
 	_, err = wrapper.CreateNodeTx(mockCreateNodeY)(nil, "", nil)
 	assert.NoError(t, err)